@@ -0,0 +1,123 @@
+// Package aglfn maps between glyph names and Unicode code points, following the
+// Adobe Glyph List Specification and the Adobe Glyph List For New Fonts (AGLFN).
+//
+// Glyph names are used by the 'post' table, by CFF fonts, and by PDF producers
+// that need to recover the text for a glyph. Fonts are free to use any names
+// they like, but names following the specification can be mapped back to the
+// characters they represent.
+// https://github.com/adobe-type-tools/agl-specification
+package aglfn
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	byRune = make(map[rune]string, len(glyphList))
+	byName = make(map[string]rune, len(glyphList))
+)
+
+func init() {
+	for _, entry := range glyphList {
+		byRune[entry.r] = entry.name
+		byName[entry.name] = entry.r
+	}
+}
+
+// Name returns the glyph name that should be used for a glyph representing r.
+// This is the AGLFN name if there is one, and otherwise uniXXXX (or uXXXXX outside
+// the Basic Multilingual Plane).
+func Name(r rune) string {
+	if name, found := byRune[r]; found {
+		return name
+	}
+	return UniName(r)
+}
+
+// UniName returns the uniXXXX (or uXXXXX outside the Basic Multilingual Plane)
+// name for r, ignoring the names in the AGLFN.
+func UniName(r rune) string {
+	if r <= 0xFFFF {
+		return fmt.Sprintf("uni%04X", r)
+	}
+	return fmt.Sprintf("u%05X", r)
+}
+
+// Lookup returns the code point for a glyph name in the AGLFN. Unlike Rune it does
+// not understand uniXXXX names or suffixes.
+func Lookup(name string) (rune, bool) {
+	r, found := byName[name]
+	return r, found
+}
+
+// Rune returns the code point represented by a glyph name, if the name maps to
+// exactly one character (e.g. "A", "A.sc", "uni0041" or "u1F600").
+func Rune(name string) (rune, bool) {
+	runes := Runes(name)
+	if len(runes) != 1 {
+		return 0, false
+	}
+	return runes[0], true
+}
+
+// Runes returns the sequence of code points represented by a glyph name, using
+// the algorithm in the Adobe Glyph List Specification. Anything after the first
+// period is ignored, and the remainder is split into components on underscores
+// (so "f_f_i.liga" maps to "ffi"). Components that cannot be mapped are skipped,
+// and nil is returned if no component can be mapped.
+func Runes(name string) []rune {
+	if i := strings.IndexByte(name, '.'); i >= 0 {
+		name = name[:i]
+	}
+
+	var runes []rune
+	for _, component := range strings.Split(name, "_") {
+		runes = append(runes, componentRunes(component)...)
+	}
+	return runes
+}
+
+// componentRunes maps a single component of a glyph name to code points.
+func componentRunes(component string) []rune {
+	if r, found := byName[component]; found {
+		return []rune{r}
+	}
+
+	if strings.HasPrefix(component, "uni") {
+		hex := component[3:]
+		if len(hex) == 0 || len(hex)%4 != 0 {
+			return nil
+		}
+		var runes []rune
+		for i := 0; i < len(hex); i += 4 {
+			r, ok := parseHex(hex[i : i+4])
+			if !ok {
+				return nil
+			}
+			runes = append(runes, r)
+		}
+		return runes
+	}
+
+	if strings.HasPrefix(component, "u") && len(component) >= 5 && len(component) <= 7 {
+		if r, ok := parseHex(component[1:]); ok {
+			return []rune{r}
+		}
+	}
+
+	return nil
+}
+
+// parseHex parses upper-case hexadecimal digits as a Unicode scalar value.
+func parseHex(hex string) (rune, bool) {
+	if strings.ToUpper(hex) != hex {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil || n > 0x10FFFF || (n >= 0xD800 && n <= 0xDFFF) {
+		return 0, false
+	}
+	return rune(n), true
+}
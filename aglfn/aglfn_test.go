@@ -0,0 +1,65 @@
+package aglfn
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGlyphListSorted(t *testing.T) {
+	for i := 1; i < len(glyphList); i++ {
+		if glyphList[i].r <= glyphList[i-1].r {
+			t.Errorf("glyphList[%d] = %04X, not after %04X", i, glyphList[i].r, glyphList[i-1].r)
+		}
+	}
+	if len(byName) != len(glyphList) {
+		t.Errorf("len(byName) = %d, want %d (duplicate names)", len(byName), len(glyphList))
+	}
+}
+
+func TestName(t *testing.T) {
+	tests := map[rune]string{
+		'A':     "A",
+		'é':     "eacute",
+		0x2206:  "Delta",
+		0x0394:  "uni0394",
+		0x2554:  "SF390000",
+		0x25CF:  "H18533",
+		0x4E00:  "uni4E00",
+		0x1F600: "u1F600",
+	}
+	for r, want := range tests {
+		if got := Name(r); got != want {
+			t.Errorf("Name(%04X) = %q, want %q", r, got, want)
+		}
+	}
+}
+
+func TestRunes(t *testing.T) {
+	tests := map[string][]rune{
+		"A":              {'A'},
+		"A.sc":           {'A'},
+		"f_f_i.liga":     {'f', 'f', 'i'},
+		"uni0041":        {'A'},
+		"uni00410042":    {'A', 'B'},
+		"u1F600":         {0x1F600},
+		"uni004":         nil,
+		"uni004a":        nil,
+		"uniD800":        nil,
+		"u110000":        nil,
+		".notdef":        nil,
+		"glyph123":       nil,
+		"Lcommaaccent.x": {0x013B},
+	}
+	for name, want := range tests {
+		if got := Runes(name); !reflect.DeepEqual(got, want) {
+			t.Errorf("Runes(%q) = %U, want %U", name, got, want)
+		}
+	}
+
+	if r, ok := Rune("uni20AC"); !ok || r != 0x20AC {
+		t.Errorf("Rune(uni20AC) = %U, %v; want U+20AC, true", r, ok)
+	}
+	if _, ok := Rune("f_i"); ok {
+		t.Errorf("Rune(f_i) = _, true; want false")
+	}
+}
@@ -0,0 +1,243 @@
+// Package autohint hints TrueType fonts by running them through ttfautohint,
+// which must be installed separately (https://freetype.org/ttfautohint/).
+//
+// This lets a Go build pipeline hint the fonts it produces without a shell
+// script:
+//
+//	font, err := sfnt.Parse(file)
+//	hinted, err := autohint.Hint(ctx, font, autohint.Options{HintingRangeMax: 36})
+//	_, err = hinted.WriteOTF(out)
+//
+// Use Installed to check for ttfautohint, to skip hinting when it is missing.
+package autohint
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/ConradIrwin/font/sfnt"
+)
+
+// DefaultPath is the ttfautohint command used when Options.Path is empty. It
+// is looked up in $PATH.
+const DefaultPath = "ttfautohint"
+
+// ErrNotInstalled is returned by Hint if the ttfautohint command is not found.
+var ErrNotInstalled = errors.New("ttfautohint is not installed")
+
+// StemWidthMode is how stems are snapped to the pixel grid in one of the
+// rendering modes.
+type StemWidthMode int
+
+const (
+	// StemWidthDefault uses ttfautohint's default for the rendering mode:
+	// quantized for grayscale and DirectWrite ClearType, and strong for GDI
+	// ClearType.
+	StemWidthDefault StemWidthMode = iota
+	// StemWidthNatural keeps the widths of stems, and does not snap them.
+	StemWidthNatural
+	// StemWidthQuantized snaps stems to whole pixels, but keeps their
+	// positions close to the outlines.
+	StemWidthQuantized
+	// StemWidthStrong snaps stems to whole pixels and their edges to the grid,
+	// for the sharpest results.
+	StemWidthStrong
+)
+
+// flag returns the letter of the mode in ttfautohint's --stem-width-mode, or
+// def for StemWidthDefault.
+func (m StemWidthMode) flag(def byte) (byte, error) {
+	switch m {
+	case StemWidthDefault:
+		return def, nil
+	case StemWidthNatural:
+		return 'n', nil
+	case StemWidthQuantized:
+		return 'q', nil
+	case StemWidthStrong:
+		return 's', nil
+	}
+	return 0, fmt.Errorf("invalid stem width mode %d", int(m))
+}
+
+// Options are the options passed to ttfautohint. The zero value of each option
+// uses ttfautohint's default.
+type Options struct {
+	// Path is the ttfautohint command to run. If it is empty, DefaultPath is
+	// used.
+	Path string
+
+	// HintingRangeMin and HintingRangeMax are the smallest and largest sizes
+	// in pixels per em that are optimized (8 and 50 by default).
+	HintingRangeMin int
+	HintingRangeMax int
+	// HintingLimit is the size in pixels per em above which hinting is
+	// turned off (200 by default).
+	HintingLimit int
+
+	// IncreaseXHeight is the size in pixels per em up to which the x-height
+	// is rounded up, to make small text more legible (14 by default). A
+	// negative value turns this off.
+	IncreaseXHeight int
+	// XHeightSnappingExceptions are the sizes, or ranges of sizes, at which
+	// the x-height is not snapped to the grid, for example "7-9, 11".
+	XHeightSnappingExceptions string
+
+	// DefaultScript is the script used for OpenType features that are not
+	// specific to a script, such as "latn" (the default).
+	DefaultScript string
+	// FallbackScript is the script used for glyphs that are not covered by
+	// any script that ttfautohint supports, such as "latn" (the default is
+	// "none", which only scales them).
+	FallbackScript string
+	// FallbackStemWidth is the stem width in font units used for the
+	// fallback script, or for symbol fonts.
+	FallbackStemWidth int
+
+	// GrayStemWidth, GDIStemWidth and DirectWriteStemWidth are the stem width
+	// modes for grayscale rendering, GDI ClearType and DirectWrite ClearType.
+	GrayStemWidth        StemWidthMode
+	GDIStemWidth         StemWidthMode
+	DirectWriteStemWidth StemWidthMode
+
+	// WindowsCompatibility adds space above and below the glyphs so that they
+	// are not clipped by the usWinAscent and usWinDescent metrics.
+	WindowsCompatibility bool
+	// Symbol hints fonts that have no glyphs in a script that ttfautohint
+	// supports, such as icon fonts, instead of failing.
+	Symbol bool
+	// HintComposites hints composite glyphs as a whole, rather than each of
+	// their components.
+	HintComposites bool
+	// AdjustSubglyphs hints the components of composite glyphs that were
+	// hinted with the original instructions of the font (rarely needed).
+	AdjustSubglyphs bool
+	// NoInfo does not add ttfautohint's version and options to the version
+	// string in the 'name' table.
+	NoInfo bool
+	// TTFAInfo adds a 'TTFA' table with the options used, so that the font
+	// can be hinted again with the same options.
+	TTFAInfo bool
+	// DehintOnly removes the hinting of the font, instead of hinting it.
+	DehintOnly bool
+
+	// ControlFile is the path of a ttfautohint control instructions file,
+	// which adjusts the hinting of individual glyphs.
+	ControlFile string
+}
+
+// args returns the command line arguments for the options. ttfautohint reads
+// the font from its standard input and writes the hinted font to its standard
+// output when no files are given.
+func (o Options) args() ([]string, error) {
+	var args []string
+	intFlag := func(name string, v int) {
+		if v != 0 {
+			args = append(args, name+"="+strconv.Itoa(v))
+		}
+	}
+	stringFlag := func(name, v string) {
+		if v != "" {
+			args = append(args, name+"="+v)
+		}
+	}
+	boolFlag := func(name string, v bool) {
+		if v {
+			args = append(args, name)
+		}
+	}
+
+	intFlag("--hinting-range-min", o.HintingRangeMin)
+	intFlag("--hinting-range-max", o.HintingRangeMax)
+	intFlag("--hinting-limit", o.HintingLimit)
+	if o.IncreaseXHeight < 0 {
+		args = append(args, "--increase-x-height=0")
+	} else {
+		intFlag("--increase-x-height", o.IncreaseXHeight)
+	}
+	stringFlag("--x-height-snapping-exceptions", o.XHeightSnappingExceptions)
+	stringFlag("--default-script", o.DefaultScript)
+	stringFlag("--fallback-script", o.FallbackScript)
+	intFlag("--fallback-stem-width", o.FallbackStemWidth)
+
+	if o.GrayStemWidth != StemWidthDefault || o.GDIStemWidth != StemWidthDefault || o.DirectWriteStemWidth != StemWidthDefault {
+		var mode [3]byte
+		var err error
+		if mode[0], err = o.GrayStemWidth.flag('q'); err != nil {
+			return nil, err
+		}
+		if mode[1], err = o.GDIStemWidth.flag('s'); err != nil {
+			return nil, err
+		}
+		if mode[2], err = o.DirectWriteStemWidth.flag('q'); err != nil {
+			return nil, err
+		}
+		args = append(args, "--stem-width-mode="+string(mode[:]))
+	}
+
+	boolFlag("--windows-compatibility", o.WindowsCompatibility)
+	boolFlag("--symbol", o.Symbol)
+	boolFlag("--composites", o.HintComposites)
+	boolFlag("--adjust-subglyphs", o.AdjustSubglyphs)
+	boolFlag("--no-info", o.NoInfo)
+	boolFlag("--ttfa-info", o.TTFAInfo)
+	boolFlag("--dehint", o.DehintOnly)
+	stringFlag("--control-file", o.ControlFile)
+	return args, nil
+}
+
+func (o Options) path() string {
+	if o.Path == "" {
+		return DefaultPath
+	}
+	return o.Path
+}
+
+// Installed returns true if the ttfautohint command given by the options can be
+// found.
+func Installed(options Options) bool {
+	_, err := exec.LookPath(options.path())
+	return err == nil
+}
+
+// Hint returns a copy of the font hinted by ttfautohint with the given
+// options. The font must have TrueType outlines. The font is not modified.
+func Hint(ctx context.Context, font *sfnt.Font, options Options) (*sfnt.Font, error) {
+	if !font.HasTable(sfnt.TagGlyf) {
+		return nil, fmt.Errorf("ttfautohint can only hint fonts with TrueType outlines")
+	}
+	path, err := exec.LookPath(options.path())
+	if err != nil {
+		return nil, ErrNotInstalled
+	}
+	args, err := options.args()
+	if err != nil {
+		return nil, err
+	}
+
+	var in, out, stderr bytes.Buffer
+	if _, err := font.WriteOTF(&in); err != nil {
+		return nil, err
+	}
+	cmd := exec.CommandContext(ctx, path, args...)
+	cmd.Stdin = &in
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("ttfautohint: %s: %s", err, msg)
+		}
+		return nil, fmt.Errorf("ttfautohint: %s", err)
+	}
+
+	hinted, err := sfnt.Parse(bytes.NewReader(out.Bytes()))
+	if err != nil {
+		return nil, fmt.Errorf("reading the output of ttfautohint: %s", err)
+	}
+	return hinted, nil
+}
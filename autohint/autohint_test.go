@@ -0,0 +1,89 @@
+package autohint
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ConradIrwin/font/sfnt"
+)
+
+func parseTestFont(t *testing.T, name string) *sfnt.Font {
+	t.Helper()
+	file, err := os.Open(filepath.Join("..", "sfnt", "testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { file.Close() })
+	font, err := sfnt.Parse(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return font
+}
+
+func TestOptionsArgs(t *testing.T) {
+	args, err := Options{}.args()
+	if err != nil || len(args) != 0 {
+		t.Errorf("Options{}.args() = %q, %v; want no arguments", args, err)
+	}
+
+	args, err = Options{
+		HintingRangeMax: 36,
+		IncreaseXHeight: -1,
+		FallbackScript:  "latn",
+		GDIStemWidth:    StemWidthNatural,
+		Symbol:          true,
+		NoInfo:          true,
+	}.args()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"--hinting-range-max=36", "--increase-x-height=0", "--fallback-script=latn", "--stem-width-mode=qnq", "--symbol", "--no-info"}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("args() = %q, want %q", args, want)
+	}
+
+	if _, err := (Options{GrayStemWidth: 7}).args(); err == nil {
+		t.Errorf("args() with an invalid stem width mode err = nil, want an error")
+	}
+}
+
+func TestHint(t *testing.T) {
+	ctx := context.Background()
+	font := parseTestFont(t, "Roboto-BoldItalic.ttf")
+	if _, err := Hint(ctx, font, Options{Path: "ttfautohint-does-not-exist"}); err != ErrNotInstalled {
+		t.Errorf("Hint() with a missing command err = %v, want ErrNotInstalled", err)
+	}
+	if _, err := Hint(ctx, parseTestFont(t, "Raleway-v4020-Regular.otf"), Options{}); err == nil {
+		t.Errorf("Hint() of a CFF font err = nil, want an error")
+	}
+
+	// cat stands in for ttfautohint to check that the font is piped through
+	// the command, as it copies its input to its output without arguments.
+	if _, err := exec.LookPath("cat"); err == nil {
+		piped, err := Hint(ctx, font, Options{Path: "cat"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(piped.Tags(), font.Tags()) {
+			t.Errorf("Hint() tables = %v, want %v", piped.Tags(), font.Tags())
+		}
+	}
+
+	if !Installed(Options{}) {
+		t.Skip("ttfautohint is not installed")
+	}
+	hinted, err := Hint(ctx, font, Options{NoInfo: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tag := range []string{"fpgm", "prep", "cvt "} {
+		if !hinted.HasTable(sfnt.MustNamedTag(tag)) {
+			t.Errorf("hinted font has no %q table", tag)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+// Package cache provides an in-memory cache of parsed fonts, for servers that
+// render text with a rotating set of fonts.
+//
+// Fonts are keyed by path or by a hash of their content, and the least recently
+// used fonts are evicted once the total size of the cached fonts exceeds
+// the cache's budget.
+//
+//	c := cache.New(256 << 20)
+//	font, err := c.Open("/fonts/customer.woff2")
+package cache
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ConradIrwin/font/sfnt"
+)
+
+// Cache is an LRU cache of parsed fonts. It is safe for concurrent use, and the
+// fonts it returns are shared, so they should not be modified.
+type Cache struct {
+	mu       sync.Mutex
+	maxBytes int64
+	size     int64
+	order    *list.List // order contains *entry, most recently used first.
+	entries  map[string]*list.Element
+}
+
+type entry struct {
+	key     string
+	font    *sfnt.Font
+	size    int64
+	modTime time.Time // modTime is the modification time of the file, for fonts opened by path.
+}
+
+// New returns a cache that holds fonts until their total size exceeds maxBytes.
+// The size of a font is the size of its file, which is held in memory so that
+// tables can be parsed on demand, or the total size of its uncompressed tables
+// if that is larger, as it is for WOFF and WOFF2 fonts.
+func New(maxBytes int64) *Cache {
+	return &Cache{
+		maxBytes: maxBytes,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// Open returns the font at path, parsing it if it is not in the cache or if
+// the file has been modified since it was cached.
+func (c *Cache) Open(path string) (*sfnt.Font, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key := "path:" + path
+	if e := c.get(key); e != nil && e.modTime.Equal(info.ModTime()) {
+		return e.font, nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	font, err := sfnt.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	c.add(&entry{key: key, font: font, size: fontSize(font, data), modTime: info.ModTime()})
+	return font, nil
+}
+
+// Parse returns the font with the given content, parsing it if there is no font
+// with the same content in the cache. The cache keeps a reference to data, so it
+// must not be modified afterwards.
+func (c *Cache) Parse(data []byte) (*sfnt.Font, error) {
+	key := Key(data)
+	if e := c.get(key); e != nil {
+		return e.font, nil
+	}
+
+	font, err := sfnt.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	c.add(&entry{key: key, font: font, size: fontSize(font, data)})
+	return font, nil
+}
+
+// fontSize returns the size charged for font, which was parsed from data.
+func fontSize(font *sfnt.Font, data []byte) int64 {
+	var tables int64
+	for _, record := range font.Directory() {
+		tables += int64(record.Length)
+	}
+	if size := int64(len(data)); size > tables {
+		return size
+	}
+	return tables
+}
+
+// Key returns the key used to cache the font with the given content.
+func Key(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// Remove removes the font with the given key (a path, or a key returned by Key)
+// from the cache.
+func (c *Cache) Remove(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, found := c.entries[key]; found {
+		c.remove(el)
+	} else if el, found := c.entries["path:"+key]; found {
+		c.remove(el)
+	}
+}
+
+// Len returns the number of fonts in the cache.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// Size returns the total size of the fonts in the cache.
+func (c *Cache) Size() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.size
+}
+
+// get returns the entry with the given key, marking it as recently used.
+func (c *Cache) get(key string) *entry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, found := c.entries[key]
+	if !found {
+		return nil
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*entry)
+}
+
+// add adds e to the cache, replacing any entry with the same key, and evicts the
+// least recently used entries until the cache is within its budget. Fonts larger
+// than the whole budget are not cached.
+func (c *Cache) add(e *entry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, found := c.entries[e.key]; found {
+		c.remove(el)
+	}
+	if e.size > c.maxBytes {
+		return
+	}
+
+	c.entries[e.key] = c.order.PushFront(e)
+	c.size += e.size
+	for c.size > c.maxBytes {
+		c.remove(c.order.Back())
+	}
+}
+
+// remove removes an element from the cache. The caller must hold c.mu.
+func (c *Cache) remove(el *list.Element) {
+	e := c.order.Remove(el).(*entry)
+	delete(c.entries, e.key)
+	c.size -= e.size
+}
@@ -0,0 +1,134 @@
+package cache
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ConradIrwin/font/sfnt"
+)
+
+func readTestFont(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := ioutil.ReadFile(filepath.Join("..", "sfnt", "testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// testFontSize returns the size charged for the font with the given content.
+func testFontSize(t *testing.T, data []byte) int64 {
+	t.Helper()
+	font, err := sfnt.Parse(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fontSize(font, data)
+}
+
+func TestFontSize(t *testing.T) {
+	// WOFF2 fonts are decompressed when they are parsed.
+	data := readTestFont(t, "Go-Regular.woff2")
+	if size := testFontSize(t, data); size <= int64(len(data)) {
+		t.Errorf("fontSize(Go-Regular.woff2) = %d, want more than the %d bytes of the file", size, len(data))
+	}
+	data = readTestFont(t, "Roboto-BoldItalic.ttf")
+	if size := testFontSize(t, data); size != int64(len(data)) {
+		t.Errorf("fontSize(Roboto-BoldItalic.ttf) = %d, want %d", size, len(data))
+	}
+}
+
+func TestCacheParse(t *testing.T) {
+	roboto := readTestFont(t, "Roboto-BoldItalic.ttf")
+	openSans := readTestFont(t, "open-sans-v15-latin-regular.woff")
+	goRegular := readTestFont(t, "Go-Regular.woff2")
+	robotoSize, openSansSize, goRegularSize := testFontSize(t, roboto), testFontSize(t, openSans), testFontSize(t, goRegular)
+
+	// There is room for roboto and either of the others, but not all three.
+	budget := robotoSize + goRegularSize
+	if openSansSize > goRegularSize {
+		budget = robotoSize + openSansSize
+	}
+	c := New(budget)
+	a, err := c.Parse(roboto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := c.Parse(roboto); b != a {
+		t.Errorf("Parse(roboto) returned a new font, want the cached font")
+	}
+	if _, err := c.Parse(openSans); err != nil {
+		t.Fatal(err)
+	}
+	if c.Len() != 2 || c.Size() != robotoSize+openSansSize {
+		t.Errorf("Len(), Size() = %d, %d, want 2, %d", c.Len(), c.Size(), robotoSize+openSansSize)
+	}
+
+	// Using roboto makes open sans the least recently used font.
+	c.Parse(roboto)
+	if _, err := c.Parse(goRegular); err != nil {
+		t.Fatal(err)
+	}
+	c.mu.Lock()
+	_, hasRoboto := c.entries[Key(roboto)]
+	_, hasOpenSans := c.entries[Key(openSans)]
+	c.mu.Unlock()
+	if !hasRoboto || hasOpenSans {
+		t.Errorf("after eviction, cached roboto = %v, open sans = %v, want true, false", hasRoboto, hasOpenSans)
+	}
+	if c.Size() != robotoSize+goRegularSize {
+		t.Errorf("Size() = %d, want %d", c.Size(), robotoSize+goRegularSize)
+	}
+
+	if _, err := c.Parse([]byte("not a font")); err == nil {
+		t.Errorf("Parse(invalid) err = nil, want an error")
+	}
+}
+
+func TestCacheOpen(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "font.ttf")
+	if err := ioutil.WriteFile(path, readTestFont(t, "Roboto-BoldItalic.ttf"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := New(1 << 30)
+	a, err := c.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := c.Open(path); b != a {
+		t.Errorf("Open(path) returned a new font, want the cached font")
+	}
+
+	// Modifying the file invalidates the cached font.
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := c.Open(path); b == a {
+		t.Errorf("Open(modified path) returned the cached font, want a new font")
+	}
+	if c.Len() != 1 {
+		t.Errorf("Len() = %d, want 1", c.Len())
+	}
+
+	c.Remove(path)
+	if c.Len() != 0 || c.Size() != 0 {
+		t.Errorf("after Remove, Len(), Size() = %d, %d, want 0, 0", c.Len(), c.Size())
+	}
+}
+
+func TestCacheTooLarge(t *testing.T) {
+	c := New(10)
+	if _, err := c.Parse(readTestFont(t, "Roboto-BoldItalic.ttf")); err != nil {
+		t.Fatal(err)
+	}
+	if c.Len() != 0 {
+		t.Errorf("Len() = %d, want 0 for a font larger than the cache", c.Len())
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/ConradIrwin/font/sfnt"
+)
+
+var axesFlags = flag.NewFlagSet("axes", flag.ExitOnError)
+var axesFormat = axesFlags.String("format", "text", "output format, either text or css")
+var axesURL = axesFlags.String("url", "font.woff2", "url of the font in the css @font-face rule")
+
+// cssFormats are the format() hints for each font file extension.
+var cssFormats = map[string]string{".woff2": "woff2", ".woff": "woff", ".otf": "opentype", ".ttf": "truetype"}
+
+// Axes prints the variation axes and named instances of a variable font, or a
+// CSS @font-face rule that covers the range of each axis.
+func Axes(font *sfnt.Font) error {
+	if !font.HasTable(sfnt.TagFvar) {
+		fmt.Println("No variation axes")
+		return nil
+	}
+	fvar, err := font.FvarTable()
+	if err != nil {
+		return err
+	}
+	name, err := font.NameTable()
+	if err != nil {
+		return err
+	}
+
+	switch *axesFormat {
+	case "text":
+		for _, axis := range fvar.Axes {
+			info, _ := sfnt.LookupAxis(axis.Tag)
+			axisName := name.Lookup(axis.NameID)
+			if axisName == "" {
+				axisName = info.Name
+			}
+			hidden := ""
+			if axis.Hidden() {
+				hidden = " (hidden)"
+			}
+			css := info.CSSProperty
+			if css == "" {
+				css = "font-variation-settings"
+			}
+			fmt.Printf("Axis %q%s: %v to %v, default %v, %s%s\n", axis.Tag, bracketName(axisName), axis.Min, axis.Max, axis.Default, css, hidden)
+		}
+		for _, instance := range fvar.Instances {
+			coordinates := make([]string, len(fvar.Axes))
+			location := map[sfnt.Tag]float64{}
+			for i, axis := range fvar.Axes {
+				coordinates[i] = fmt.Sprintf("%s=%v", axis.Tag, instance.Coordinates[i])
+				location[axis.Tag] = instance.Coordinates[i]
+			}
+			ps, err := font.InstancePostscriptName(location)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Instance %q (%s): %s\n", name.Lookup(instance.SubfamilyNameID), ps, strings.Join(coordinates, " "))
+		}
+		return nil
+	case "css":
+		family := name.Lookup(sfnt.NamePreferredFamily)
+		if family == "" {
+			family = name.Lookup(sfnt.NameFontFamily)
+		}
+		fmt.Printf("@font-face {\n")
+		fmt.Printf("  font-family: %q;\n", family)
+		if format, found := cssFormats[path.Ext(*axesURL)]; found {
+			fmt.Printf("  src: url(%q) format(%q);\n", *axesURL, format)
+		} else {
+			fmt.Printf("  src: url(%q);\n", *axesURL)
+		}
+		for _, axis := range fvar.Axes {
+			info, _ := sfnt.LookupAxis(axis.Tag)
+			if value := info.CSSRange(axis.Min, axis.Max); value != "" {
+				fmt.Printf("  %s: %s;\n", info.CSSProperty, value)
+			}
+		}
+		fmt.Printf("}\n")
+		return nil
+	default:
+		return fmt.Errorf("unknown format %q, use text or css", *axesFormat)
+	}
+}
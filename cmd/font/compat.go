@@ -0,0 +1,23 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ConradIrwin/font/sfnt"
+)
+
+// Compat prints the glyphs that cannot be interpolated between the master fonts.
+func Compat(fonts []*sfnt.Font) error {
+	problems, err := sfnt.CheckCompatibility(fonts)
+	if err != nil {
+		return err
+	}
+
+	for _, problem := range problems {
+		fmt.Println(problem)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("found %d incompatibilities", len(problems))
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/ConradIrwin/font/sfnt"
+)
+
+var convertFlags = flag.NewFlagSet("convert", flag.ExitOnError)
+var convertSanitizeNames = convertFlags.Bool("sanitize-names", false, "remove duplicate entries and control characters from the name table, and sort it")
+var convertDropLanguages = convertFlags.Bool("drop-languages", false, "with -sanitize-names, drop the name table entries that are not in English or a -keep-languages language")
+var convertKeepLanguages = convertFlags.String("keep-languages", "", "comma separated Windows or Macintosh language ids kept by -drop-languages (e.g. 0x0407,0x040C)")
+var convertVendor = convertFlags.String("vendor", "", "the vendor id to set in the OS/2 table, up to 4 characters (e.g. GOOG)")
+
+// Convert writes the font in the first file to the second, in the format given
+// by the second file's extension (.ttf, .otf or .woff).
+func Convert() error {
+	if len(os.Args) != 3 {
+		return fmt.Errorf("Usage: font convert [-sanitize-names [-drop-languages]] [-vendor id] <font file> <output file>")
+	}
+	input, output := os.Args[1], os.Args[2]
+
+	file, err := os.Open(input)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	font, err := parseFont(file)
+	if err != nil {
+		return fmt.Errorf("%s: %s", input, err)
+	}
+
+	options, err := convertOptions()
+	if err != nil {
+		return err
+	}
+	format := sfnt.FormatFromExtension(filepath.Ext(output))
+	write := font.WriteOTFWithOptions
+	switch format {
+	case sfnt.FormatTrueType, sfnt.FormatOpenType:
+		// The outlines are not converted, so the extension must match them.
+		if format != font.Format() {
+			return fmt.Errorf("%s has %s outlines, so it can only be written as %s", input, font.Format(), font.Format().Extension())
+		}
+	case sfnt.FormatWOFF:
+		write = font.WriteWOFFWithOptions
+	case sfnt.FormatUnknown:
+		return fmt.Errorf("%s: unknown extension, use %s or .woff", output, font.Format().Extension())
+	default:
+		return fmt.Errorf("%s: writing %s fonts is not supported", output, format)
+	}
+
+	out, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	if _, err := write(out, options); err != nil {
+		out.Close()
+		os.Remove(output)
+		return err
+	}
+	return out.Close()
+}
+
+// convertOptions returns the options for writing the font given by the flags.
+func convertOptions() (sfnt.WriteOptions, error) {
+	options := sfnt.WriteOptions{SanitizeNames: *convertSanitizeNames, DropNameLanguages: *convertDropLanguages}
+	for _, id := range strings.Split(*convertKeepLanguages, ",") {
+		if id == "" {
+			continue
+		}
+		n, err := strconv.ParseUint(id, 0, 16)
+		if err != nil {
+			return options, fmt.Errorf("invalid language id %q: %s", id, err)
+		}
+		options.KeepNameLanguages = append(options.KeepNameLanguages, sfnt.PlatformLanguageID(n))
+	}
+	if *convertVendor != "" {
+		// Vendor ids shorter than 4 characters are padded with spaces.
+		tag, err := sfnt.NamedTag(fmt.Sprintf("%-4s", *convertVendor))
+		if err != nil {
+			return options, fmt.Errorf("invalid vendor id %q: %s", *convertVendor, err)
+		}
+		options.VendorID = tag
+	}
+	return options, nil
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ConradIrwin/font/sfnt"
+)
+
+var corpusCheckFlags = flag.NewFlagSet("corpus-check", flag.ExitOnError)
+var corpusCheckBaseline = corpusCheckFlags.String("baseline", "", "the JSON file of results to compare with (the results are printed if not given)")
+var corpusCheckUpdate = corpusCheckFlags.Bool("update", false, "write the results to the -baseline file instead of comparing with it")
+
+// corpusResult is what corpus-check records about each font.
+type corpusResult struct {
+	Path     string            `json:"path"`               // Path is relative to the corpus directory.
+	Error    string            `json:"error,omitempty"`    // Error is why the font could not be parsed or validated.
+	Warnings []string          `json:"warnings,omitempty"` // Warnings are the problems ignored by Parse.
+	Glyphs   int               `json:"glyphs,omitempty"`
+	Tables   map[string]string `json:"tables,omitempty"`   // Tables maps each table to "ok", or the error parsing it.
+	Problems map[string]int    `json:"problems,omitempty"` // Problems counts the validation problems of each severity.
+}
+
+// CorpusCheck parses every font in a directory and records the results, which
+// it compares with a -baseline file written by an earlier version with -update.
+// This shows how upgrading the library changes its handling of a private corpus.
+func CorpusCheck() error {
+	// Flags may also follow the directory, as in "corpus-check dir/ -baseline b.json".
+	var dirs []string
+	args := os.Args[1:]
+	for len(args) > 0 {
+		dirs = append(dirs, args[0])
+		corpusCheckFlags.Parse(args[1:])
+		args = corpusCheckFlags.Args()
+	}
+	if len(dirs) != 1 || (*corpusCheckUpdate && *corpusCheckBaseline == "") {
+		return fmt.Errorf("Usage: font corpus-check <dir> [-baseline baseline.json [-update]]")
+	}
+
+	results, err := checkCorpus(dirs[0])
+	if err != nil {
+		return err
+	}
+
+	if *corpusCheckBaseline == "" || *corpusCheckUpdate {
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return err
+		}
+		data = append(data, '\n')
+		if *corpusCheckBaseline == "" {
+			_, err = os.Stdout.Write(data)
+			return err
+		}
+		if err := ioutil.WriteFile(*corpusCheckBaseline, data, 0644); err != nil {
+			return err
+		}
+		fmt.Printf("Wrote the results for %d fonts to %s\n", len(results), *corpusCheckBaseline)
+		return nil
+	}
+
+	data, err := ioutil.ReadFile(*corpusCheckBaseline)
+	if err != nil {
+		return err
+	}
+	var baseline []corpusResult
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return fmt.Errorf("%s: %s", *corpusCheckBaseline, err)
+	}
+	changed := diffCorpus(baseline, results)
+	if changed > 0 {
+		return fmt.Errorf("%d of %d fonts differ from %s", changed, len(results), *corpusCheckBaseline)
+	}
+	fmt.Printf("All %d fonts match %s\n", len(results), *corpusCheckBaseline)
+	return nil
+}
+
+// checkCorpus returns the results for each font in dir (and its
+// subdirectories), sorted by path.
+func checkCorpus(dir string) ([]corpusResult, error) {
+	var results []corpusResult
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !fontExtensions[strings.ToLower(filepath.Ext(path))] {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		results = append(results, checkFont(filepath.ToSlash(rel), data))
+		return nil
+	})
+	sort.Slice(results, func(i, j int) bool { return results[i].Path < results[j].Path })
+	return results, err
+}
+
+// checkFont parses the font, each of its tables, and validates it.
+func checkFont(path string, data []byte) corpusResult {
+	result := corpusResult{Path: path}
+	font, err := sfnt.Parse(bytes.NewReader(data))
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	for _, warning := range font.ParseWarnings() {
+		result.Warnings = append(result.Warnings, warning.Error())
+	}
+
+	result.Tables = map[string]string{}
+	for _, tag := range font.Tags() {
+		result.Tables[tag.String()] = "ok"
+		if _, err := font.Table(tag); err != nil {
+			result.Tables[tag.String()] = err.Error()
+		}
+	}
+	if font.HasTable(sfnt.TagMaxp) {
+		if maxp, err := font.MaxpTable(); err == nil {
+			result.Glyphs = int(maxp.NumGlyphs)
+		}
+	}
+
+	problems, err := font.Validate()
+	if err != nil {
+		result.Error = "validating: " + err.Error()
+		return result
+	}
+	for _, p := range problems {
+		if result.Problems == nil {
+			result.Problems = map[string]int{}
+		}
+		result.Problems[p.Severity.String()]++
+	}
+	return result
+}
+
+// fields returns the result as a flat map from the name of each field to its
+// value, so that results can be compared field by field.
+func (r corpusResult) fields() map[string]string {
+	fields := map[string]string{}
+	if r.Error != "" {
+		fields["error"] = r.Error
+	}
+	for i, warning := range r.Warnings {
+		fields["warnings["+strconv.Itoa(i)+"]"] = warning
+	}
+	if r.Glyphs != 0 {
+		fields["glyphs"] = strconv.Itoa(r.Glyphs)
+	}
+	for tag, status := range r.Tables {
+		fields["tables["+strconv.Quote(tag)+"]"] = status
+	}
+	for severity, n := range r.Problems {
+		fields["problems["+severity+"]"] = strconv.Itoa(n)
+	}
+	return fields
+}
+
+// diffCorpus prints the differences between the baseline and the results, and
+// returns the number of fonts that differ.
+func diffCorpus(baseline, results []corpusResult) int {
+	old := map[string]corpusResult{}
+	for _, r := range baseline {
+		old[r.Path] = r
+	}
+	current := map[string]bool{}
+	changed := 0
+	for _, r := range results {
+		current[r.Path] = true
+		b, found := old[r.Path]
+		if !found {
+			fmt.Printf("+ %s: not in the baseline\n", r.Path)
+			changed++
+			continue
+		}
+
+		before, after := b.fields(), r.fields()
+		var names []string
+		for name := range before {
+			names = append(names, name)
+		}
+		for name := range after {
+			if _, found := before[name]; !found {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		differs := false
+		for _, name := range names {
+			if before[name] != after[name] {
+				fmt.Printf("~ %s: %s: %q -> %q\n", r.Path, name, before[name], after[name])
+				differs = true
+			}
+		}
+		if differs {
+			changed++
+		}
+	}
+	for _, b := range baseline {
+		if !current[b.Path] {
+			fmt.Printf("- %s: missing from the corpus\n", b.Path)
+			changed++
+		}
+	}
+	return changed
+}
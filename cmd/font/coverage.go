@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/ConradIrwin/font/sfnt"
+)
+
+var coverageFlags = flag.NewFlagSet("coverage", flag.ExitOnError)
+var coverageBlocks = coverageFlags.Bool("blocks", false, "print the coverage of each Unicode block")
+var coverageLanguages = coverageFlags.Bool("languages", false, "print the languages whose exemplar characters are all supported")
+var coverageText = coverageFlags.String("text", "", "print the characters of the text that the font cannot render")
+
+// Coverage prints the number of code points the font supports, with -blocks
+// the number and percentage covered in each Unicode block, and with -languages
+// the languages it supports. With -text it prints the characters of the text
+// that cannot be rendered, or that can only be rendered after decomposition.
+func Coverage(font *sfnt.Font) error {
+	coverage, err := font.BlockCoverage()
+	if err != nil {
+		return err
+	}
+
+	total := 0
+	for _, c := range coverage {
+		total += c.Covered
+	}
+	fmt.Printf("%d code points in %d Unicode blocks\n", total, len(coverage))
+	if *coverageBlocks {
+		for _, c := range coverage {
+			fmt.Printf("%s (%.1f%%)\n", c, c.Percent())
+		}
+	}
+
+	if *coverageLanguages {
+		languages, err := font.SupportedLanguages()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%d languages supported\n", len(languages))
+		for _, language := range languages {
+			fmt.Printf("%s (%s)\n", language.Code, language.Name)
+		}
+	}
+
+	if *coverageText != "" {
+		support, err := font.SupportsText(*coverageText)
+		if err != nil {
+			return err
+		}
+		for _, r := range support.Missing {
+			fmt.Printf("Missing: %U %q\n", r, r)
+		}
+		for _, r := range support.Decomposed {
+			fmt.Printf("Decomposed: %U %q\n", r, r)
+		}
+		if support.Supported() {
+			fmt.Println("Text is supported")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+var dedupeFlags = flag.NewFlagSet("dedupe", flag.ExitOnError)
+var dedupePlan = dedupeFlags.Bool("plan", false, "print a shell script that deletes the duplicates instead of a report")
+
+// Dedupe reports the fonts in the given directories that are exact duplicates
+// (files with the same contents), and near duplicates (the same family, style,
+// version and format, but different files, such as different subsets).
+//
+// With -plan it prints the rm commands to delete all but one font of each
+// group instead. The font kept from a group of near duplicates is the one
+// with the most glyphs, and then the largest file (as subsetting may leave
+// empty glyphs in place).
+func Dedupe() error {
+	paths := os.Args[1:]
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	fonts, err := listFonts(paths)
+	if err != nil {
+		return err
+	}
+	sort.Slice(fonts, func(i, j int) bool { return fonts[i].Path < fonts[j].Path })
+
+	// Only one copy of each exact duplicate is compared with the other fonts.
+	var exact [][]listedFont
+	bySum := map[[32]byte]int{}
+	for _, f := range fonts {
+		if i, found := bySum[f.sum]; found {
+			exact[i] = append(exact[i], f)
+			continue
+		}
+		bySum[f.sum] = len(exact)
+		exact = append(exact, []listedFont{f})
+	}
+
+	var near [][]listedFont
+	byName := map[string]int{}
+	for _, group := range exact {
+		f := group[0]
+		if f.Family == "" {
+			continue
+		}
+		key := strings.Join([]string{f.Family, f.Style, f.Version, f.Format}, "\x00")
+		if i, found := byName[key]; found {
+			near[i] = append(near[i], f)
+			continue
+		}
+		byName[key] = len(near)
+		near = append(near, []listedFont{f})
+	}
+	for _, group := range near {
+		sort.SliceStable(group, func(i, j int) bool {
+			if group[i].Glyphs != group[j].Glyphs {
+				return group[i].Glyphs > group[j].Glyphs
+			}
+			return group[i].Size > group[j].Size
+		})
+	}
+
+	if *dedupePlan {
+		for _, groups := range [][][]listedFont{exact, near} {
+			for _, group := range groups {
+				for _, f := range group[1:] {
+					fmt.Printf("rm -- %s\n", shellQuote(f.Path))
+				}
+			}
+		}
+		return nil
+	}
+
+	found := false
+	for _, group := range exact {
+		if len(group) > 1 {
+			found = true
+			fmt.Printf("Exact duplicates (sha256 %x):\n", group[0].sum[:8])
+			for _, f := range group {
+				fmt.Printf("\t%s\n", f.Path)
+			}
+		}
+	}
+	for _, group := range near {
+		if len(group) > 1 {
+			found = true
+			fmt.Printf("Near duplicates (%s %s, version %s, %s):\n", group[0].Family, group[0].Style, group[0].Version, group[0].Format)
+			for _, f := range group {
+				fmt.Printf("\t%s (%d glyphs, %d bytes)\n", f.Path, f.Glyphs, f.Size)
+			}
+		}
+	}
+	if !found {
+		fmt.Println("No duplicates")
+	}
+	return nil
+}
+
+// shellQuote quotes s for use as a single argument in a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
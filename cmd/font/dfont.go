@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/ConradIrwin/font/sfnt"
+)
+
+var dfontFlags = flag.NewFlagSet("dfont", flag.ExitOnError)
+var dfontDir = dfontFlags.String("dir", "", "the directory to extract the fonts to (by default they are only listed)")
+
+// Dfont lists the fonts in each Macintosh font suitcase (.dfont file, or the
+// resource fork of an older suitcase as file/..namedfork/rsrc), and extracts
+// them to standalone files named Family-Style.ttf or .otf if -dir is given.
+// Existing files are never overwritten.
+func Dfont() error {
+	if len(os.Args) < 2 {
+		return fmt.Errorf("Usage: font dfont [-dir directory] <suitcase> ...")
+	}
+
+	var failed bool
+	for _, filename := range os.Args[1:] {
+		if err := extractDfont(filename); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", filename, err)
+			failed = true
+		}
+	}
+	if failed {
+		return fmt.Errorf("some suitcases could not be read")
+	}
+	return nil
+}
+
+func extractDfont(filename string) error {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	resources, err := sfnt.ParseDfont(data)
+	if err != nil {
+		return err
+	}
+
+	for _, resource := range resources {
+		font, err := sfnt.Parse(bytes.NewReader(resource.Data))
+		if err != nil {
+			return fmt.Errorf("resource %d: %s", resource.ID, err)
+		}
+		name, err := font.CanonicalFilename("")
+		if err != nil {
+			return fmt.Errorf("resource %d: %s", resource.ID, err)
+		}
+
+		if *dfontDir == "" {
+			fmt.Printf("%s\t%d\t%s\t%s\n", filename, resource.ID, resource.Name, name)
+			continue
+		}
+		target := filepath.Join(*dfontDir, name)
+		if _, err := os.Stat(target); err == nil {
+			return fmt.Errorf("not extracting resource %d to %s, the file already exists", resource.ID, target)
+		}
+		if err := ioutil.WriteFile(target, resource.Data, 0644); err != nil {
+			return err
+		}
+		fmt.Printf("%s -> %s\n", filename, target)
+	}
+	return nil
+}
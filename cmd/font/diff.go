@@ -0,0 +1,51 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+
+	"github.com/ConradIrwin/font/sfnt"
+)
+
+var diffFlags = flag.NewFlagSet("diff", flag.ExitOnError)
+var diffAsDefaultInstance = diffFlags.Bool("as-default-instance", false, "compare the default instance of the first (variable) font with the second (static) font")
+var diffTolerance = diffFlags.Float64("tolerance", 1, "the largest difference in font units that is ignored")
+
+// Diff prints the glyphs whose outlines differ between two fonts, or with
+// -as-default-instance, every difference between the default instance of a
+// variable font and the static font that it replaces.
+func Diff(fonts []*sfnt.Font) error {
+	if len(fonts) != 2 {
+		return fmt.Errorf("diff needs two fonts, got %d", len(fonts))
+	}
+
+	count := 0
+	if *diffAsDefaultInstance {
+		differences, err := sfnt.CompareDefaultInstance(fonts[0], fonts[1], *diffTolerance)
+		if err != nil {
+			return err
+		}
+		for _, difference := range differences {
+			fmt.Println(difference)
+		}
+		count = len(differences)
+	} else {
+		differences, err := sfnt.CompareOutlines(fonts[0], fonts[1], *diffTolerance)
+		if err != nil {
+			return err
+		}
+		for _, difference := range differences {
+			if math.IsInf(difference.Distance, 1) {
+				fmt.Printf("glyph %d: only in one font\n", difference.GlyphID)
+			} else {
+				fmt.Printf("glyph %d: outlines differ by %.4g units\n", difference.GlyphID, difference.Distance)
+			}
+		}
+		count = len(differences)
+	}
+	if count > 0 {
+		return fmt.Errorf("found %d differences", count)
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+package main
+
+import (
+	"os"
+
+	"github.com/ConradIrwin/font/sfnt"
+)
+
+// Fea prints the gpos/gsub tables as an Adobe feature file.
+func Fea(font *sfnt.Font) error {
+	return font.WriteFea(os.Stdout)
+}
@@ -41,6 +41,14 @@ func layoutTable(font *sfnt.Font, tag sfnt.Tag, name string) error {
 				}
 			}
 		}
+
+		if params, found := t.SizeParams(); found {
+			fmt.Printf("\tDesign size: %gpt", params.DesignSize)
+			if params.SubfamilyID != 0 {
+				fmt.Printf(" (subfamily %d, for %gpt to %gpt)", params.SubfamilyID, params.RangeStart, params.RangeEnd)
+			}
+			fmt.Println()
+		}
 	} else {
 		fmt.Printf("No %s\n", name)
 	}
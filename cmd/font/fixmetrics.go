@@ -0,0 +1,40 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ConradIrwin/font/sfnt"
+)
+
+var fixMetricsFlags = flag.NewFlagSet("fix-metrics", flag.ExitOnError)
+var fixMetricsDryRun = fixMetricsFlags.Bool("dry-run", false, "print the changes without writing the font")
+
+// FixMetrics makes the vertical metrics in the OS/2 and hhea tables consistent,
+// and writes the font to stdout. The changes are printed to stderr, or to
+// stdout with -dry-run.
+func FixMetrics(font *sfnt.Font) error {
+	changes, err := font.FixVerticalMetrics(*fixMetricsDryRun)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stderr
+	if *fixMetricsDryRun {
+		w = os.Stdout
+	}
+	if len(changes) == 0 {
+		fmt.Fprintln(w, "Vertical metrics are already consistent")
+	}
+	for _, change := range changes {
+		fmt.Fprintf(w, "%s %s: %d -> %d\n", change.Table, change.Field, change.Old, change.New)
+	}
+
+	if *fixMetricsDryRun {
+		return nil
+	}
+	_, err = font.WriteOTF(os.Stdout)
+	return err
+}
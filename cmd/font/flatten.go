@@ -0,0 +1,50 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ConradIrwin/font/sfnt"
+)
+
+var flattenFlags = flag.NewFlagSet("flatten", flag.ExitOnError)
+var flattenRemoveOverlaps = flattenFlags.Bool("remove-overlaps", false, "also remove overlapping contours (curves in affected glyphs become line segments)")
+
+// Flatten replaces composite glyphs with simple glyphs, and optionally removes overlaps.
+func Flatten(font *sfnt.Font) error {
+	glyf, err := font.GlyfTable()
+	if err == sfnt.ErrMissingTable {
+		return fmt.Errorf("flattening is only supported for fonts with TrueType outlines")
+	} else if err != nil {
+		return err
+	}
+
+	decomposed, merged := 0, 0
+	for i := range glyf.Glyphs {
+		changed, err := glyf.Decompose(uint16(i))
+		if err != nil {
+			return err
+		}
+		if changed {
+			decomposed++
+		}
+
+		if *flattenRemoveOverlaps {
+			changed, err := glyf.RemoveOverlaps(uint16(i))
+			if err != nil {
+				return err
+			}
+			if changed {
+				merged++
+			}
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Decomposed %d glyphs\n", decomposed)
+	if *flattenRemoveOverlaps {
+		fmt.Fprintf(os.Stderr, "Removed overlaps from %d glyphs\n", merged)
+	}
+
+	_, err = font.WriteOTF(os.Stdout)
+	return err
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ConradIrwin/font/aglfn"
+	"github.com/ConradIrwin/font/sfnt"
+)
+
+var iconsFlags = flag.NewFlagSet("icons", flag.ExitOnError)
+var iconsFormat = iconsFlags.String("format", "json", "output format, either json or css")
+var iconsPrefix = iconsFlags.String("prefix", "icon-", "prefix for css class names")
+
+// icon is a glyph mapped to a code point in one of the Private Use Areas.
+type icon struct {
+	name string
+	r    rune
+}
+
+// Icons prints the name of each glyph mapped to a Private Use Area code point.
+// A glyph mapped to several code points is listed with the first, and the others
+// are reported on stderr.
+func Icons(font *sfnt.Font) error {
+	cmap, err := font.CmapTable()
+	if err != nil {
+		return err
+	}
+	unicode := cmap.Unicode()
+	if unicode == nil {
+		return fmt.Errorf("font has no unicode cmap")
+	}
+
+	var names []string
+	if font.HasTable(sfnt.TagPost) {
+		post, err := font.PostTable()
+		if err != nil {
+			return err
+		}
+		names = post.GlyphNames()
+	}
+
+	var icons []icon
+	for r, gid := range unicode.Mapping {
+		if !isPrivateUse(r) {
+			continue
+		}
+		name := aglfn.UniName(r)
+		if int(gid) < len(names) && names[gid] != "" {
+			name = names[gid]
+		}
+		icons = append(icons, icon{name, r})
+	}
+	sort.Slice(icons, func(i, j int) bool {
+		return icons[i].r < icons[j].r
+	})
+
+	// Each name can only be listed once, as a JSON key or a CSS class.
+	first := map[string]rune{}
+	unique := icons[:0]
+	for _, icon := range icons {
+		if r, found := first[icon.name]; found {
+			fmt.Fprintf(os.Stderr, "%s is mapped to U+%04X and U+%04X, only U+%04X is listed\n", icon.name, r, icon.r, r)
+			continue
+		}
+		first[icon.name] = icon.r
+		unique = append(unique, icon)
+	}
+	icons = unique
+
+	switch *iconsFormat {
+	case "json":
+		mapping := make(map[string]string, len(icons))
+		for _, icon := range icons {
+			mapping[icon.name] = fmt.Sprintf("%x", icon.r)
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(mapping)
+	case "css":
+		for _, icon := range icons {
+			fmt.Printf(".%s::before { content: \"\\%x\"; }\n", cssIdentifier(*iconsPrefix+icon.name), icon.r)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown format %q, use json or css", *iconsFormat)
+	}
+}
+
+// cssIdentifier escapes the characters of name that are not allowed in a CSS
+// identifier, as CSS.escape does.
+func cssIdentifier(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		switch {
+		case r == 0:
+			b.WriteRune('\uFFFD')
+		case r < 0x20 || r == 0x7F,
+			r >= '0' && r <= '9' && (i == 0 || i == 1 && name[0] == '-'):
+			fmt.Fprintf(&b, "\\%x ", r)
+		case r == '-' && name == "-":
+			b.WriteString("\\-")
+		case r >= 0x80 || r == '-' || r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z':
+			b.WriteRune(r)
+		default:
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// isPrivateUse returns true if r is in one of the Private Use Areas.
+func isPrivateUse(r rune) bool {
+	return r >= 0xE000 && r <= 0xF8FF || r >= 0xF0000 && r <= 0xFFFFD || r >= 0x100000 && r <= 0x10FFFD
+}
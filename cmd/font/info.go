@@ -7,7 +7,8 @@ import (
 	"github.com/ConradIrwin/font/sfnt"
 )
 
-// Info prints the name table (contains metadata).
+// Info prints the name table (contains metadata), the vendor and IBM family
+// class, and the character collection of CID-keyed fonts.
 func Info(font *sfnt.Font) error {
 	if font.HasTable(sfnt.TagName) {
 		name, err := font.NameTable()
@@ -20,5 +21,31 @@ func Info(font *sfnt.Font) error {
 			fmt.Println(entry.Platform() + ids + entry.Label() + ": " + entry.String())
 		}
 	}
+
+	if font.HasTable(sfnt.TagOS2) {
+		os2, err := font.OS2Table()
+		if err != nil {
+			return err
+		}
+		if vendor, found := sfnt.LookupVendor(os2.AchVendID); found {
+			fmt.Printf("Vendor: %s (%s)\n", os2.AchVendID, vendor.Name)
+		} else {
+			fmt.Printf("Vendor: %s\n", os2.AchVendID)
+		}
+		fmt.Printf("Family class: %s\n", os2.FamilyClassName())
+	}
+
+	if font.HasTable(sfnt.TagCFF) {
+		cff, err := font.CFFTable()
+		if err != nil {
+			return err
+		}
+		if cff.IsCIDKeyed() {
+			fmt.Printf("CIDFont %s: %s, %d of %d CIDs\n", cff.FontName, cff.ROS, cff.NumGlyphs(), cff.CIDCount)
+			for i, fd := range cff.FDArray {
+				fmt.Printf("FDArray %d: %s\n", i, fd.FontName)
+			}
+		}
+	}
 	return nil
 }
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ConradIrwin/font/sfnt"
+)
+
+var inspectFlags = flag.NewFlagSet("inspect", flag.ExitOnError)
+var inspectChar = inspectFlags.String("char", "", "the character to inspect, as itself or a code point (e.g. A or U+0041)")
+
+// Inspect prints the glyph that -char is mapped to, its metrics, the GSUB
+// features that can substitute it, and the glyphs it is kerned with.
+func Inspect(font *sfnt.Font) error {
+	r, err := parseInspectChar(*inspectChar)
+	if err != nil {
+		return err
+	}
+	info, err := font.InspectChar(r)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Character: U+%04X %q\n", info.Rune, info.Rune)
+	if info.Mapped {
+		fmt.Printf("Glyph: %d (%s)\n", info.GlyphID, info.GlyphName)
+	} else {
+		fmt.Printf("Glyph: not mapped, drawn as %d (%s)\n", info.GlyphID, info.GlyphName)
+	}
+	fmt.Println("Advance:", info.Advance)
+	if info.Bounds != nil {
+		fmt.Printf("Bounds: %g,%g to %g,%g\n", info.Bounds.XMin, info.Bounds.YMin, info.Bounds.XMax, info.Bounds.YMax)
+	}
+
+	features := make([]string, len(info.Features))
+	for i, tag := range info.Features {
+		features[i] = strings.TrimSpace(tag.String())
+	}
+	if len(features) == 0 {
+		features = []string{"none"}
+	}
+	fmt.Println("GSUB features:", strings.Join(features, ", "))
+
+	for _, kerning := range []struct {
+		label string
+		pairs []sfnt.KernPair
+	}{{"before", info.KernedBefore}, {"after", info.KernedAfter}} {
+		fmt.Printf("Kerned with %d glyphs %s it:\n", len(kerning.pairs), kerning.label)
+		for _, pair := range kerning.pairs {
+			fmt.Printf("  %6d %-20s %g\n", pair.GlyphID, pair.GlyphName, pair.Value)
+		}
+	}
+	return nil
+}
+
+// parseInspectChar returns the character given as itself, or as a code point
+// written as U+0041 or 0x41.
+func parseInspectChar(s string) (rune, error) {
+	if utf8.RuneCountInString(s) == 1 {
+		r, _ := utf8.DecodeRuneInString(s)
+		return r, nil
+	}
+	hex := strings.TrimPrefix(strings.TrimPrefix(strings.ToUpper(s), "U+"), "0X")
+	if hex != strings.ToUpper(s) {
+		if v, err := strconv.ParseUint(hex, 16, 32); err == nil && utf8.ValidRune(rune(v)) {
+			return rune(v), nil
+		}
+	}
+	return 0, fmt.Errorf("-char must be a single character or a code point like U+0041, not %q", s)
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ConradIrwin/font/sfnt"
+)
+
+var instanceFlags = flag.NewFlagSet("instance", flag.ExitOnError)
+var instanceNamed = instanceFlags.String("named", "", "name of the instance to extract (e.g. \"SemiBold Italic\")")
+
+// Instance makes a static font from the named instance of a variable font given
+// by -named, renames it to match, and writes it to stdout.
+func Instance(font *sfnt.Font) error {
+	if *instanceNamed == "" {
+		return fmt.Errorf("missing -named, use font axes to list the named instances")
+	}
+	location, err := font.InstanceByName(*instanceNamed)
+	if err != nil {
+		return err
+	}
+
+	if err := font.Instantiate(location); err != nil {
+		return err
+	}
+	if err := font.UpdateInstanceStyle(location); err != nil {
+		return err
+	}
+
+	_, err = font.WriteOTF(os.Stdout)
+	return err
+}
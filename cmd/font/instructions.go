@@ -0,0 +1,42 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/ConradIrwin/font/sfnt"
+)
+
+var instructionsFlags = flag.NewFlagSet("instructions", flag.ExitOnError)
+var instructionsTop = instructionsFlags.Int("top", 20, "the number of glyphs with the most instructions to print (0 for all)")
+
+// Instructions prints the size of the TrueType hinting instructions in the
+// font-wide tables and the glyphs, and the glyphs with the most instructions.
+// The share is each glyph's percentage of the size of all glyph instructions.
+func Instructions(font *sfnt.Font) error {
+	sizes, err := font.InstructionSizes()
+	if err != nil {
+		return err
+	}
+	if sizes.Total() == 0 {
+		fmt.Println("The font has no TrueType instructions")
+		return nil
+	}
+
+	fmt.Printf("%-8s %10s\n", "table", "size")
+	fmt.Printf("%-8s %10d\n", "fpgm", sizes.FontProgram)
+	fmt.Printf("%-8s %10d\n", "cvt", sizes.ControlValues)
+	fmt.Printf("%-8s %10d\n", "prep", sizes.Prep)
+	fmt.Printf("%-8s %10d (%d glyphs)\n", "glyphs", sizes.GlyphTotal(), len(sizes.Glyphs))
+	fmt.Printf("%-8s %10d\n", "total", sizes.Total())
+
+	glyphs := sizes.Glyphs
+	if *instructionsTop > 0 && len(glyphs) > *instructionsTop {
+		glyphs = glyphs[:*instructionsTop]
+	}
+	fmt.Printf("\n%6s %-24s %10s %7s\n", "gid", "name", "size", "share")
+	for _, g := range glyphs {
+		fmt.Printf("%6d %-24s %10d %6.1f%%\n", g.GlyphID, g.Name, g.Size, percent(g.Size, sizes.GlyphTotal()))
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ConradIrwin/font/sfnt"
+)
+
+var interpolateFlags = flag.NewFlagSet("interpolate", flag.ExitOnError)
+var interpolateT = interpolateFlags.Float64("t", 0.5, "position between the masters, from 0 for the first to 1 for the second")
+var interpolateGrid = interpolateFlags.Int("grid", 0, "round coordinates and metrics to multiples of this many font units")
+
+// Interpolate writes a font between two compatible master fonts to stdout,
+// named after its interpolated weight and the style of the first master.
+func Interpolate(fonts []*sfnt.Font) error {
+	if len(fonts) != 2 {
+		return fmt.Errorf("interpolate needs two masters, got %d", len(fonts))
+	}
+	font, err := sfnt.Interpolate(fonts[0], fonts[1], *interpolateT)
+	if err != nil {
+		return err
+	}
+
+	if font.HasTable(sfnt.TagOS2) {
+		os2, err := font.OS2Table()
+		if err != nil {
+			return err
+		}
+		location := map[sfnt.Tag]float64{sfnt.MustNamedTag("wght"): float64(os2.USWeightClass)}
+		if os2.FsSelection&1 != 0 {
+			location[sfnt.MustNamedTag("ital")] = 1
+		}
+		if err := font.UpdateInstanceStyle(location); err != nil {
+			return err
+		}
+	}
+
+	_, err = font.WriteOTFWithOptions(os.Stdout, sfnt.WriteOptions{RoundToGrid: *interpolateGrid})
+	return err
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ConradIrwin/font/sfnt"
+)
+
+var limitFlags = flag.NewFlagSet("limit", flag.ExitOnError)
+var limitAxes = limitFlags.String("axes", "", "comma separated list of axis ranges (e.g. wght=400:700,wdth=100:100)")
+
+// Limit restricts the axes of a variable font to the ranges given by -axes,
+// and writes the font to stdout. If any axis is pinned to a single value, the
+// names and style of the font are updated to match.
+func Limit(font *sfnt.Font) error {
+	pinned := map[sfnt.Tag]float64{}
+	for _, axis := range strings.Split(*limitAxes, ",") {
+		if axis == "" {
+			continue
+		}
+		parts := strings.SplitN(axis, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid axis range %q, want tag=min:max", axis)
+		}
+		tag, err := sfnt.NamedTag(fmt.Sprintf("%-4s", parts[0]))
+		if err != nil {
+			return fmt.Errorf("invalid axis %q: %s", parts[0], err)
+		}
+
+		bounds := strings.SplitN(parts[1], ":", 2)
+		if len(bounds) == 1 {
+			bounds = append(bounds, bounds[0])
+		}
+		min, err := strconv.ParseFloat(bounds[0], 64)
+		if err != nil {
+			return fmt.Errorf("invalid axis range %q: %s", axis, err)
+		}
+		max, err := strconv.ParseFloat(bounds[1], 64)
+		if err != nil {
+			return fmt.Errorf("invalid axis range %q: %s", axis, err)
+		}
+
+		if err := font.LimitAxis(tag, min, max); err != nil {
+			return err
+		}
+		if min == max {
+			pinned[tag] = min
+		}
+	}
+
+	if len(pinned) > 0 {
+		if err := font.UpdateInstanceStyle(pinned); err != nil {
+			return err
+		}
+	}
+
+	_, err := font.WriteOTF(os.Stdout)
+	return err
+}
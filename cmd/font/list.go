@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ConradIrwin/font/sfnt"
+)
+
+var listFlags = flag.NewFlagSet("list", flag.ExitOnError)
+var listFormat = listFlags.String("format", "text", "output format, either text, json or csv")
+var listSort = listFlags.String("sort", "family", "column to sort by: path, family, style, version, format, glyphs, size or axes")
+
+// fontExtensions are the extensions of the files that list reads.
+var fontExtensions = map[string]bool{".ttf": true, ".otf": true, ".woff": true, ".woff2": true}
+
+// listedFont is a row of the table printed by list.
+type listedFont struct {
+	Path    string   `json:"path"`
+	Family  string   `json:"family"`
+	Style   string   `json:"style"`
+	Version string   `json:"version"`
+	Format  string   `json:"format"`
+	Glyphs  int      `json:"glyphs"`
+	Size    int64    `json:"size"`
+	Axes    []string `json:"axes,omitempty"`
+
+	sum [sha256.Size]byte // sum is the hash of the file's contents.
+}
+
+// List prints a table of the fonts in each directory (and their subdirectories)
+// or file given on the command line, sorted by -sort.
+func List() error {
+	paths := os.Args[1:]
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+
+	fonts, err := listFonts(paths)
+	if err != nil {
+		return err
+	}
+
+	less := map[string]func(a, b listedFont) bool{
+		"path":    func(a, b listedFont) bool { return a.Path < b.Path },
+		"family":  func(a, b listedFont) bool { return a.Family < b.Family },
+		"style":   func(a, b listedFont) bool { return a.Style < b.Style },
+		"version": func(a, b listedFont) bool { return a.Version < b.Version },
+		"format":  func(a, b listedFont) bool { return a.Format < b.Format },
+		"glyphs":  func(a, b listedFont) bool { return a.Glyphs < b.Glyphs },
+		"size":    func(a, b listedFont) bool { return a.Size < b.Size },
+		"axes":    func(a, b listedFont) bool { return len(a.Axes) < len(b.Axes) },
+	}[*listSort]
+	if less == nil {
+		return fmt.Errorf("unknown sort column %q", *listSort)
+	}
+	// Ties are broken by path, so the order is stable between runs.
+	sort.Slice(fonts, func(i, j int) bool {
+		if less(fonts[i], fonts[j]) || less(fonts[j], fonts[i]) {
+			return less(fonts[i], fonts[j])
+		}
+		return fonts[i].Path < fonts[j].Path
+	})
+
+	columns := []string{"path", "family", "style", "version", "format", "glyphs", "size", "axes"}
+	row := func(f listedFont) []string {
+		return []string{f.Path, f.Family, f.Style, f.Version, f.Format, strconv.Itoa(f.Glyphs), strconv.FormatInt(f.Size, 10), strings.Join(f.Axes, ",")}
+	}
+
+	switch *listFormat {
+	case "text":
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(w, strings.Join(columns, "\t"))
+		for _, f := range fonts {
+			fmt.Fprintln(w, strings.Join(row(f), "\t"))
+		}
+		return w.Flush()
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(fonts)
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		w.Write(columns)
+		for _, f := range fonts {
+			w.Write(row(f))
+		}
+		w.Flush()
+		return w.Error()
+	default:
+		return fmt.Errorf("unknown format %q, use text, json or csv", *listFormat)
+	}
+}
+
+// listFonts reads the fonts in each of the given directories (and their
+// subdirectories) or files. Files that cannot be parsed are reported to stderr.
+func listFonts(paths []string) ([]listedFont, error) {
+	var fonts []listedFont
+	for _, root := range paths {
+		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() || (path != root && !fontExtensions[strings.ToLower(filepath.Ext(path))]) {
+				return nil
+			}
+			listed, err := listFont(path, info.Size())
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s: %s\n", path, err)
+				return nil
+			}
+			fonts = append(fonts, listed)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return fonts, nil
+}
+
+// listFont reads the font at path, which is size bytes long.
+func listFont(path string, size int64) (listedFont, error) {
+	listed := listedFont{Path: path, Size: size}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return listed, err
+	}
+	listed.sum = sha256.Sum256(data)
+	font, err := sfnt.Parse(bytes.NewReader(data))
+	if err != nil {
+		return listed, err
+	}
+
+	listed.Format = fontFormat(data)
+	if font.HasTable(sfnt.TagName) {
+		name, err := font.NameTable()
+		if err != nil {
+			return listed, err
+		}
+		listed.Family = name.Lookup(sfnt.NamePreferredFamily)
+		if listed.Family == "" {
+			listed.Family = name.Lookup(sfnt.NameFontFamily)
+		}
+		listed.Style = name.Lookup(sfnt.NamePreferredSubfamily)
+		if listed.Style == "" {
+			listed.Style = name.Lookup(sfnt.NameFontSubfamily)
+		}
+		listed.Version = strings.TrimPrefix(name.Lookup(sfnt.NameVersion), "Version ")
+	}
+	if font.HasTable(sfnt.TagMaxp) {
+		maxp, err := font.MaxpTable()
+		if err != nil {
+			return listed, err
+		}
+		listed.Glyphs = int(maxp.NumGlyphs)
+	}
+	if font.HasTable(sfnt.TagFvar) {
+		fvar, err := font.FvarTable()
+		if err != nil {
+			return listed, err
+		}
+		for _, axis := range fvar.Axes {
+			listed.Axes = append(listed.Axes, strings.TrimRight(axis.Tag.String(), " "))
+		}
+	}
+	return listed, nil
+}
+
+// fontFormat returns the format of the font file: ttf, otf, woff or woff2.
+func fontFormat(data []byte) string {
+	if len(data) < 4 {
+		return ""
+	}
+	switch string(data[:4]) {
+	case "wOFF":
+		return "woff"
+	case "wOF2":
+		return "woff2"
+	case "OTTO":
+		return "otf"
+	}
+	return "ttf"
+}
@@ -2,7 +2,9 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"io/ioutil"
 	"os"
 
 	"github.com/ConradIrwin/font/sfnt"
@@ -10,13 +12,44 @@ import (
 
 func usage() {
 	fmt.Println(`
-Usage: font [features|info|metrics|scrub|stats] font.[otf,ttf,woff,woff2] ...
+Usage: font [axes|compat|convert|corpus-check|coverage|dedupe|dfont|diff|fea|features|fix-metrics|flatten|icons|info|inspect|instance|instructions|interpolate|limit|list|measure|metrics|rename-file|scripts|scrub|serve|shape|simplify|size-report|specimen|stats|strip|subset|synth|validate|waterfall] font.[otf,ttf,woff,woff2,pfb,pfa] ...
 
+axes: prints the variation axes and named instances, or an @font-face rule with -format css
+compat: checks that glyphs in each master font can be interpolated (e.g. font compat light.ttf bold.ttf)
+convert: writes the font to the output file in the format given by its extension, .ttf, .otf or .woff (e.g. font convert font.woff2 font.woff), and with -sanitize-names removes duplicate entries and control characters from the name table (and -drop-languages its translations), and with -vendor sets the OS/2 vendor id
+corpus-check: parses every font in a directory and prints the results as JSON, or compares them with -baseline (written with -update) to check a library upgrade (e.g. font corpus-check fonts/ -baseline baseline.json)
+coverage: prints the number of code points supported, the coverage of each Unicode block with -blocks, the supported languages with -languages, and the characters of -text it cannot render
+dedupe: reports exact and near duplicate fonts in the given directories, or prints the commands to delete them with -plan
+dfont: lists the fonts in Macintosh font suitcases (.dfont files or resource forks), and extracts them to standalone files in -dir
+diff: prints the glyphs whose outlines differ between two fonts, or with -as-default-instance how the default instance of a variable font differs from a static font (e.g. font diff -as-default-instance vf.ttf regular.ttf)
+fea: prints the gpos/gsub tables as an Adobe feature file (.fea)
 features: prints the gpos/gsub tables (contains font features)
-info: prints the name table (contains metadata)
+fix-metrics: sets the typographic, win and hhea vertical metrics to the same values for consistent line spacing (prints the changes with -dry-run)
+flatten: decomposes composite glyphs, and removes overlaps with -remove-overlaps
+icons: prints the names of glyphs mapped to private use code points as -format json or css
+info: prints the name table (contains metadata), the vendor (e.g. GOOG (Google)) and the IBM family class (e.g. Oldstyle Serifs / Garalde)
+inspect: prints the glyph that -char is mapped to, its advance and bounds, the GSUB features that can replace it, and the glyphs it is kerned with (e.g. -char A or -char U+00E9)
+instance: makes a static font from the named instance of a variable font given by -named (e.g. -named "SemiBold Italic")
+instructions: prints the size of the TrueType instructions in the fpgm, cvt and prep tables and the glyphs, and the -top glyphs with the most
+interpolate: writes a font between two compatible masters at -t, from 0 for the first to 1 for the second, with coordinates rounded to multiples of -grid units (e.g. font interpolate -t 0.25 light.ttf bold.ttf)
+limit: restricts the axes of a variable font to the ranges given by -axes, and renames it if an axis is pinned (e.g. -axes wght=400:700,wdth=100)
+list: prints a table of the fonts in the given directories as -format text, json or csv, sorted by -sort (e.g. -sort size)
+measure: prints the width of each line of the -text file at -size points, in points and pixels, with and without kerning (e.g. -text lines.txt -size 14)
 metrics: prints the hhea table (contains font metrics)
-scrub: remove the name table (saves significant space)
-stats: prints each table and the amount of space used`)
+rename-file: renames each font file to Family-Style.ext, or Family[axes].ext for variable fonts (prints the new names with -dry-run)
+scripts: prints the scripts and languages in the gsub/gpos tables, and the features of each
+scrub: remove the name table (saves significant space), or with -metadata only the designer, vendor, unique ID and timestamps, and private tables (for white-labeled fonts)
+serve: serves info, validate, axes, subset and convert over HTTP on -listen (takes no font files)
+shape: prints the glyphs and positions that -text is shaped to with -features (e.g. -features liga,kern)
+simplify: removes points that change the outlines by less than -tolerance font units, to make webfonts smaller (hinting of changed glyphs is removed)
+size-report: prints the size and compression ratio of each table, and the savings from removing hinting, names or layout
+specimen: renders a specimen sheet as -format svg or png
+stats: prints each table and the amount of space used
+strip: removes the tables given by -tables (e.g. -tables DSIG,hinting,private)
+subset: removes the outlines of glyphs not given by -glyphs, -gids, -text or -unicodes (e.g. -gids 1-50,70 or -text abc)
+synth: writes minimal and broken fonts for testing parsers to -dir (takes no font files)
+validate: prints problems found in the font, such as overlapping contours, with the checks chosen by -profile (minimal, web, desktop or strict) and custom -rules from a JSON file, and with -pdfa those that prevent embedding it in PDF/A documents; -format json adds the byte range and fix of each problem
+waterfall: renders -text at each of -sizes as -format svg or png (e.g. -sizes 8,10,12,16,24)`)
 }
 
 func main() {
@@ -27,23 +60,108 @@ func main() {
 	}
 
 	cmds := map[string]func(*sfnt.Font) error{
-		"scrub":    Scrub,
-		"info":     Info,
-		"stats":    Stats,
-		"metrics":  Metrics,
-		"features": Features,
+		"axes":         Axes,
+		"coverage":     Coverage,
+		"scripts":      Scripts,
+		"scrub":        Scrub,
+		"icons":        Icons,
+		"info":         Info,
+		"inspect":      Inspect,
+		"instance":     Instance,
+		"instructions": Instructions,
+		"limit":        Limit,
+		"shape":        Shape,
+		"simplify":     Simplify,
+		"size-report":  SizeReport,
+		"specimen":     Specimen,
+		"stats":        Stats,
+		"measure":      Measure,
+		"metrics":      Metrics,
+		"fea":          Fea,
+		"features":     Features,
+		"fix-metrics":  FixMetrics,
+		"flatten":      Flatten,
+		"strip":        Strip,
+		"subset":       Subset,
+		"validate":     Validate,
+		"waterfall":    Waterfall,
 	}
-	if _, found := cmds[command]; !found {
+	// multiCmds operate on all of the fonts at once.
+	multiCmds := map[string]func([]*sfnt.Font) error{
+		"compat":      Compat,
+		"diff":        Diff,
+		"interpolate": Interpolate,
+	}
+	// standaloneCmds don't have the fonts read for them (convert, corpus-check, dedupe, dfont, list and rename-file read their own).
+	standaloneCmds := map[string]func() error{
+		"convert":      Convert,
+		"corpus-check": CorpusCheck,
+		"dedupe":       Dedupe,
+		"dfont":        Dfont,
+		"list":         List,
+		"rename-file":  RenameFile,
+		"serve":        Serve,
+		"synth":        Synth,
+	}
+
+	_, found := cmds[command]
+	_, multiFound := multiCmds[command]
+	_, standaloneFound := standaloneCmds[command]
+	if !found && !multiFound && !standaloneFound {
 		usage()
 		return
 	}
 
+	flagSets := map[string]*flag.FlagSet{
+		"axes":         axesFlags,
+		"convert":      convertFlags,
+		"corpus-check": corpusCheckFlags,
+		"coverage":     coverageFlags,
+		"dedupe":       dedupeFlags,
+		"dfont":        dfontFlags,
+		"diff":         diffFlags,
+		"fix-metrics":  fixMetricsFlags,
+		"flatten":      flattenFlags,
+		"icons":        iconsFlags,
+		"inspect":      inspectFlags,
+		"instance":     instanceFlags,
+		"instructions": instructionsFlags,
+		"interpolate":  interpolateFlags,
+		"limit":        limitFlags,
+		"list":         listFlags,
+		"measure":      measureFlags,
+		"rename-file":  renameFileFlags,
+		"scrub":        scrubFlags,
+		"serve":        serveFlags,
+		"shape":        shapeFlags,
+		"simplify":     simplifyFlags,
+		"specimen":     specimenFlags,
+		"strip":        stripFlags,
+		"subset":       subsetFlags,
+		"synth":        synthFlags,
+		"validate":     validateFlags,
+		"waterfall":    waterfallFlags,
+	}
+	if flags, found := flagSets[command]; found {
+		flags.Parse(os.Args[1:])
+		os.Args = append(os.Args[:1], flags.Args()...)
+	}
+
+	if standaloneFound {
+		if err := standaloneCmds[command](); err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	if len(os.Args) < 1 {
 		fmt.Fprintf(os.Stderr, "Usage: font %s <font file> ...\n", command)
 		os.Exit(1)
 	}
 
 	exitCode := 0
+	var fonts []*sfnt.Font
 	for _, filename := range os.Args[1:] {
 		file, err := os.Open(filename)
 		if err != nil {
@@ -53,13 +171,18 @@ func main() {
 		}
 		defer file.Close()
 
-		font, err := sfnt.Parse(file)
+		font, err := parseFont(file)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Failed to parse font: %s\n", err)
 			exitCode = 1
 			continue
 		}
 
+		if multiFound {
+			fonts = append(fonts, font)
+			continue
+		}
+
 		if len(os.Args[1:]) > 1 {
 			fmt.Println("==>", filename, "<==")
 		}
@@ -69,5 +192,27 @@ func main() {
 			continue
 		}
 	}
+
+	if multiFound && exitCode == 0 {
+		if err := multiCmds[command](fonts); err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+			exitCode = 1
+		}
+	}
 	os.Exit(exitCode)
 }
+
+// parseFont parses an open font file. Type 1 fonts are converted to OpenType,
+// so that they can be inspected or written out (e.g. with convert) like other
+// fonts.
+func parseFont(file *os.File) (*sfnt.Font, error) {
+	font, err := sfnt.Parse(file)
+	if err != sfnt.ErrType1 {
+		return font, err
+	}
+	data, err := ioutil.ReadFile(file.Name())
+	if err != nil {
+		return nil, err
+	}
+	return sfnt.ConvertType1(data)
+}
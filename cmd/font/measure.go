@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/ConradIrwin/font/sfnt"
+)
+
+var measureFlags = flag.NewFlagSet("measure", flag.ExitOnError)
+var measureText = measureFlags.String("text", "", "the file containing the lines of text to measure")
+var measureSize = measureFlags.Float64("size", 16, "the font size in points")
+var measureDPI = measureFlags.Float64("dpi", 96, "the resolution used to convert points to pixels (96 for CSS pixels)")
+
+// Measure prints the width of each line in the -text file at -size, in points
+// and pixels, with and without kerning.
+func Measure(font *sfnt.Font) error {
+	if *measureText == "" {
+		return fmt.Errorf("Usage: font measure -text <file> [-size points] <font file> ...")
+	}
+	data, err := ioutil.ReadFile(*measureText)
+	if err != nil {
+		return err
+	}
+	head, err := font.HeadTable()
+	if err != nil {
+		return err
+	}
+	points := *measureSize / float64(head.UnitsPerEm)
+	pixels := points * *measureDPI / 72
+
+	fmt.Printf("%5s %10s %10s %10s %10s  %s\n", "line", "pt", "kerned pt", "px", "kerned px", "text")
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	for i, line := range lines {
+		line = strings.TrimSuffix(line, "\r")
+		width, err := font.TextWidth(line, false)
+		if err != nil {
+			return err
+		}
+		kerned, err := font.TextWidth(line, true)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%5d %10.2f %10.2f %10.2f %10.2f  %s\n", i+1, width*points, kerned*points, width*pixels, kerned*pixels, line)
+	}
+	return nil
+}
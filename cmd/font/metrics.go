@@ -33,7 +33,8 @@ func Metrics(font *sfnt.Font) error {
 
 		fmt.Printf("%#v\n", os2)
 
-		fmt.Println("Cap Height:", os2.SCapHeight)
+		fmt.Printf("Weight class: %d (%s, font-weight: %s)\n", os2.USWeightClass, os2.WeightName(), os2.CSSFontWeight())
+		fmt.Printf("Width class: %d (%s, font-stretch: %s)\n", os2.USWidthClass, os2.WidthName(), os2.CSSFontStretch())
 		fmt.Println("Typographic Ascender:", os2.STypoAscender)
 		fmt.Println("Typographic Descender:", os2.STypoDescender)
 		fmt.Println("Win Ascent:", os2.UsWinAscent)
@@ -42,5 +43,21 @@ func Metrics(font *sfnt.Font) error {
 		fmt.Println("TODO: SHOW MORE METRICS")
 	}
 
+	if height, err := font.XHeight(); err == nil {
+		fmt.Println("x-height:", height)
+	}
+	if height, err := font.CapHeight(); err == nil {
+		fmt.Println("Cap height:", height)
+	}
+
+	decoration, err := font.DecorationMetrics()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Underline position: %.4gem\n", decoration.UnderlinePosition)
+	fmt.Printf("Underline thickness: %.4gem\n", decoration.UnderlineThickness)
+	fmt.Printf("Strikeout position: %.4gem\n", decoration.StrikeoutPosition)
+	fmt.Printf("Strikeout thickness: %.4gem\n", decoration.StrikeoutThickness)
+
 	return nil
 }
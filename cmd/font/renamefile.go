@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ConradIrwin/font/sfnt"
+)
+
+var renameFileFlags = flag.NewFlagSet("rename-file", flag.ExitOnError)
+var renameFileDryRun = renameFileFlags.Bool("dry-run", false, "print the new names without renaming the files")
+
+// RenameFile renames each font file given on the command line to its canonical
+// name (e.g. Family-Style.ttf or Family[wght].ttf), in the same directory and
+// keeping its extension. Files are never overwritten.
+func RenameFile() error {
+	if len(os.Args) < 2 {
+		return fmt.Errorf("Usage: font rename-file <font file> ...")
+	}
+
+	var failed bool
+	for _, filename := range os.Args[1:] {
+		if err := renameFontFile(filename); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", filename, err)
+			failed = true
+		}
+	}
+	if failed {
+		return fmt.Errorf("some files could not be renamed")
+	}
+	return nil
+}
+
+func renameFontFile(filename string) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	font, err := sfnt.Parse(file)
+	if err != nil {
+		return err
+	}
+
+	name, err := font.CanonicalFilename(strings.ToLower(filepath.Ext(filename)))
+	if err != nil {
+		return err
+	}
+	file.Close() // some systems can't rename open files
+	target := filepath.Join(filepath.Dir(filename), name)
+	if target == filepath.Clean(filename) {
+		return nil
+	}
+	fmt.Printf("%s -> %s\n", filename, target)
+	if *renameFileDryRun {
+		return nil
+	}
+
+	if _, err := os.Stat(target); err == nil {
+		return fmt.Errorf("not renaming to %s, the file already exists", target)
+	}
+	return os.Rename(filename, target)
+}
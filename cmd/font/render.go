@@ -0,0 +1,277 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/ConradIrwin/font/sfnt"
+)
+
+// point is a point on a glyph outline in pixels, with y increasing downwards.
+type point struct {
+	x, y    float64
+	onCurve bool
+}
+
+// canvas collects glyph outlines so they can be written as SVG or PNG.
+type canvas struct {
+	width, height int
+	contours      [][]point
+}
+
+// textRenderer lays out text in a single font, without kerning or shaping.
+type textRenderer struct {
+	cmap       *sfnt.CmapSubtable
+	glyf       *sfnt.TableGlyf
+	hmtx       *sfnt.TableHmtx
+	unitsPerEm float64
+}
+
+func newTextRenderer(font *sfnt.Font) (*textRenderer, error) {
+	head, err := font.HeadTable()
+	if err != nil {
+		return nil, err
+	}
+	cmap, err := font.CmapTable()
+	if err != nil {
+		return nil, err
+	}
+	if cmap.Unicode() == nil {
+		return nil, fmt.Errorf("font has no unicode cmap")
+	}
+	glyf, err := font.GlyfTable()
+	if err == sfnt.ErrMissingTable {
+		return nil, fmt.Errorf("rendering is only supported for fonts with TrueType outlines")
+	} else if err != nil {
+		return nil, err
+	}
+	hmtx, err := font.HmtxTable()
+	if err != nil {
+		return nil, err
+	}
+
+	return &textRenderer{
+		cmap:       cmap.Unicode(),
+		glyf:       glyf,
+		hmtx:       hmtx,
+		unitsPerEm: float64(head.UnitsPerEm),
+	}, nil
+}
+
+// width returns the width of text in pixels at the given size.
+func (r *textRenderer) width(text string, size float64) float64 {
+	scale := size / r.unitsPerEm
+	width := 0.0
+	for _, c := range text {
+		width += float64(r.hmtx.Advance(r.cmap.Mapping[c])) * scale
+	}
+	return width
+}
+
+// draw adds text to the canvas with its baseline starting at (x, y).
+// Characters that are not in the font are drawn using the .notdef glyph.
+func (r *textRenderer) draw(c *canvas, text string, x, y, size float64) error {
+	scale := size / r.unitsPerEm
+	for _, ch := range text {
+		gid := r.cmap.Mapping[ch]
+		contours, err := r.glyf.Contours(gid)
+		if err != nil {
+			return err
+		}
+		for _, contour := range contours {
+			points := make([]point, len(contour))
+			for i, p := range contour {
+				points[i] = point{x + float64(p.X)*scale, y - float64(p.Y)*scale, p.OnCurve}
+			}
+			c.contours = append(c.contours, points)
+		}
+		x += float64(r.hmtx.Advance(gid)) * scale
+	}
+	return nil
+}
+
+// wrap splits text into lines that fit within width pixels at the given size.
+func (r *textRenderer) wrap(text string, size, width float64) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		candidate := word
+		if line != "" {
+			candidate = line + " " + word
+		}
+		if line != "" && r.width(candidate, size) > width {
+			lines = append(lines, line)
+			candidate = word
+		}
+		line = candidate
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// walkContour calls moveTo, then lineTo and quadTo for each segment of a TrueType
+// contour, inserting the implied on-curve points between consecutive off-curve points.
+func walkContour(contour []point, moveTo, lineTo func(p point), quadTo func(ctrl, p point)) {
+	if len(contour) == 0 {
+		return
+	}
+
+	midpoint := func(a, b point) point {
+		return point{(a.x + b.x) / 2, (a.y + b.y) / 2, true}
+	}
+
+	start := contour[0]
+	if !start.onCurve {
+		last := contour[len(contour)-1]
+		if last.onCurve {
+			start = last
+			contour = append([]point{last}, contour[:len(contour)-1]...)
+		} else {
+			start = midpoint(last, start)
+			contour = append([]point{start}, contour...)
+		}
+	}
+
+	moveTo(start)
+	var ctrl *point
+	rest := append(append([]point{}, contour[1:]...), start)
+	for _, p := range rest {
+		p := p
+		switch {
+		case p.onCurve && ctrl == nil:
+			lineTo(p)
+		case p.onCurve:
+			quadTo(*ctrl, p)
+			ctrl = nil
+		case ctrl != nil:
+			mid := midpoint(*ctrl, p)
+			quadTo(*ctrl, mid)
+			ctrl = &p
+		default:
+			ctrl = &p
+		}
+	}
+	if ctrl != nil {
+		quadTo(*ctrl, start)
+	}
+}
+
+// writeSVG writes the canvas as an SVG image with black glyphs on a white background.
+func (c *canvas) writeSVG(w io.Writer) error {
+	b := bufio.NewWriter(w)
+	fmt.Fprintf(b, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\">\n", c.width, c.height, c.width, c.height)
+	fmt.Fprintf(b, "<rect width=\"100%%\" height=\"100%%\" fill=\"white\"/>\n<path fill=\"black\" d=\"")
+	for _, contour := range c.contours {
+		walkContour(contour,
+			func(p point) { fmt.Fprintf(b, "M%.2f %.2f", p.x, p.y) },
+			func(p point) { fmt.Fprintf(b, "L%.2f %.2f", p.x, p.y) },
+			func(ctrl, p point) { fmt.Fprintf(b, "Q%.2f %.2f %.2f %.2f", ctrl.x, ctrl.y, p.x, p.y) })
+		b.WriteString("Z")
+	}
+	b.WriteString("\"/>\n</svg>\n")
+	return b.Flush()
+}
+
+// edge is a line segment of a flattened outline, with dir +1 if it goes downwards.
+type edge struct {
+	x0, y0, x1, y1 float64
+	dir            int
+}
+
+// samplesPerPixel is the number of scanlines sampled per row of pixels when rasterizing.
+const samplesPerPixel = 4
+
+// writePNG rasterizes the canvas using the non-zero winding rule, and writes it as a
+// grayscale PNG image with black glyphs on a white background.
+func (c *canvas) writePNG(w io.Writer) error {
+	var edges []edge
+	for _, contour := range c.contours {
+		var last point
+		lineTo := func(p point) {
+			if p.y != last.y {
+				e := edge{last.x, last.y, p.x, p.y, 1}
+				if e.y0 > e.y1 {
+					e = edge{p.x, p.y, last.x, last.y, -1}
+				}
+				edges = append(edges, e)
+			}
+			last = p
+		}
+		quadTo := func(ctrl, p point) {
+			start := last
+			steps := int(math.Ceil(math.Sqrt(math.Hypot(ctrl.x-start.x, ctrl.y-start.y)+math.Hypot(p.x-ctrl.x, p.y-ctrl.y)))) + 1
+			for i := 1; i <= steps; i++ {
+				t := float64(i) / float64(steps)
+				u := 1 - t
+				lineTo(point{
+					x: u*u*start.x + 2*u*t*ctrl.x + t*t*p.x,
+					y: u*u*start.y + 2*u*t*ctrl.y + t*t*p.y,
+				})
+			}
+		}
+		walkContour(contour, func(p point) { last = p }, lineTo, quadTo)
+	}
+
+	img := image.NewGray(image.Rect(0, 0, c.width, c.height))
+	coverage := make([]float64, c.width)
+	type crossing struct {
+		x   float64
+		dir int
+	}
+	var crossings []crossing
+
+	for row := 0; row < c.height; row++ {
+		for i := range coverage {
+			coverage[i] = 0
+		}
+		for sample := 0; sample < samplesPerPixel; sample++ {
+			y := float64(row) + (float64(sample)+0.5)/samplesPerPixel
+			crossings = crossings[:0]
+			for _, e := range edges {
+				if y >= e.y0 && y < e.y1 {
+					crossings = append(crossings, crossing{e.x0 + (y-e.y0)*(e.x1-e.x0)/(e.y1-e.y0), e.dir})
+				}
+			}
+			sort.Slice(crossings, func(i, j int) bool { return crossings[i].x < crossings[j].x })
+
+			winding := 0
+			for i, cr := range crossings {
+				winding += cr.dir
+				if winding == 0 || i+1 == len(crossings) {
+					continue
+				}
+				x0, x1 := math.Max(cr.x, 0), math.Min(crossings[i+1].x, float64(c.width))
+				for px := int(x0); px < c.width && float64(px) < x1; px++ {
+					overlap := math.Min(x1, float64(px+1)) - math.Max(x0, float64(px))
+					coverage[px] += overlap / samplesPerPixel
+				}
+			}
+		}
+		for px, cov := range coverage {
+			img.SetGray(px, row, color.Gray{Y: uint8(255 - 255*math.Min(cov, 1))})
+		}
+	}
+
+	return png.Encode(w, img)
+}
+
+// write writes the canvas in the given format, either svg or png.
+func (c *canvas) write(w io.Writer, format string) error {
+	switch format {
+	case "svg":
+		return c.writeSVG(w)
+	case "png":
+		return c.writePNG(w)
+	default:
+		return fmt.Errorf("unknown format %q, use svg or png", format)
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+
+	"github.com/ConradIrwin/font/sfnt"
+)
+
+// ruleConfig is a custom rule in the JSON file given to validate -rules, for
+// example:
+//
+//	[
+//	  {"name": "vendor", "vendorID": "HSPT"},
+//	  {"name": "license-url", "nameID": 14, "pattern": "^https://example\\.com/license"}
+//	]
+type ruleConfig struct {
+	Name     string `json:"name"`
+	VendorID string `json:"vendorID"` // VendorID is the achVendID that the 'OS/2' table must have.
+	NameID   *int   `json:"nameID"`   // NameID is the 'name' table entry that must match Pattern.
+	Pattern  string `json:"pattern"`
+}
+
+// registerRules registers the rules in the file with sfnt.RegisterRule, so
+// that they are checked by every profile.
+func registerRules(filename string) error {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	var configs []ruleConfig
+	if err := json.Unmarshal(data, &configs); err != nil {
+		return fmt.Errorf("%s: %s", filename, err)
+	}
+
+	for i, config := range configs {
+		if config.Name == "" {
+			config.Name = fmt.Sprintf("%s:%d", filename, i+1)
+		}
+		var check func(font *sfnt.Font) ([]sfnt.Problem, error)
+		switch {
+		case config.VendorID != "":
+			tag, err := sfnt.NamedTag(config.VendorID)
+			if err != nil {
+				return fmt.Errorf("%s: rule %s: %s", filename, config.Name, err)
+			}
+			check = vendorIDRule(tag)
+		case config.NameID != nil:
+			pattern, err := regexp.Compile(config.Pattern)
+			if err != nil {
+				return fmt.Errorf("%s: rule %s: %s", filename, config.Name, err)
+			}
+			check = nameRule(sfnt.NameID(*config.NameID), pattern)
+		default:
+			return fmt.Errorf("%s: rule %s needs a vendorID, or a nameID and pattern", filename, config.Name)
+		}
+		sfnt.RegisterRule(sfnt.NewRule(config.Name, sfnt.ProfileMinimal, check))
+	}
+	return nil
+}
+
+// vendorIDRule checks that the font was made by the vendor with the given ID.
+func vendorIDRule(vendor sfnt.Tag) func(font *sfnt.Font) ([]sfnt.Problem, error) {
+	return func(font *sfnt.Font) ([]sfnt.Problem, error) {
+		if !font.HasTable(sfnt.TagOS2) {
+			return []sfnt.Problem{{Severity: sfnt.SeverityError, Tag: sfnt.TagOS2, GlyphID: -1, Message: fmt.Sprintf("the table is needed for vendor ID %q", vendor)}}, nil
+		}
+		os2, err := font.OS2Table()
+		if err != nil {
+			return nil, err
+		}
+		if os2.AchVendID != vendor {
+			return []sfnt.Problem{{Severity: sfnt.SeverityError, Tag: sfnt.TagOS2, GlyphID: -1, Message: fmt.Sprintf("achVendID is %q, but should be %q", os2.AchVendID, vendor), Offset: 58, Length: 4}}, nil
+		}
+		return nil, nil
+	}
+}
+
+// nameRule checks that the font has entries for the name ID, and that they all
+// match pattern.
+func nameRule(id sfnt.NameID, pattern *regexp.Regexp) func(font *sfnt.Font) ([]sfnt.Problem, error) {
+	return func(font *sfnt.Font) ([]sfnt.Problem, error) {
+		if !font.HasTable(sfnt.TagName) {
+			return []sfnt.Problem{{Severity: sfnt.SeverityError, Tag: sfnt.TagName, GlyphID: -1, Message: fmt.Sprintf("the table is needed for %s", id)}}, nil
+		}
+		name, err := font.NameTable()
+		if err != nil {
+			return nil, err
+		}
+
+		var problems []sfnt.Problem
+		found := false
+		for _, entry := range name.List() {
+			if entry.NameID != id {
+				continue
+			}
+			found = true
+			if value := entry.String(); !pattern.MatchString(value) {
+				problems = append(problems, sfnt.Problem{Severity: sfnt.SeverityError, Tag: sfnt.TagName, GlyphID: -1, Message: fmt.Sprintf("%s for %s is %q, which does not match %s", id, entry.Platform(), value, pattern)})
+			}
+		}
+		if !found {
+			problems = append(problems, sfnt.Problem{Severity: sfnt.SeverityError, Tag: sfnt.TagName, GlyphID: -1, Message: fmt.Sprintf("there is no entry for %s", id)})
+		}
+		return problems, nil
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ConradIrwin/font/sfnt"
+)
+
+// Scripts prints the scripts and languages supported by the gsub/gpos tables,
+// and the features registered for each.
+func Scripts(font *sfnt.Font) error {
+	scripts, err := font.Scripts()
+	if err != nil {
+		return err
+	}
+	if len(scripts) == 0 {
+		fmt.Println("No scripts")
+		return nil
+	}
+
+	for _, script := range scripts {
+		fmt.Printf("Script %q%s:\n", script.Tag, bracketName(script.Name))
+		for _, lang := range script.Languages {
+			fmt.Printf("\tLanguage %q%s:\n", lang.Tag, bracketName(lang.Name))
+			fmt.Printf("\t\tGSUB: %s\n", joinTags(lang.GSUB))
+			fmt.Printf("\t\tGPOS: %s\n", joinTags(lang.GPOS))
+		}
+	}
+	return nil
+}
+
+func bracketName(name string) string {
+	if name != "" {
+		return fmt.Sprintf(" (%s)", name)
+	}
+	return ""
+}
+
+func joinTags(tags []sfnt.Tag) string {
+	if len(tags) == 0 {
+		return "-"
+	}
+	s := make([]string, len(tags))
+	for i, tag := range tags {
+		s[i] = tag.String()
+	}
+	return strings.Join(s, " ")
+}
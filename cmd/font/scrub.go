@@ -1,14 +1,23 @@
 package main
 
 import (
+	"flag"
 	"os"
 
 	"github.com/ConradIrwin/font/sfnt"
 )
 
-// Scrub remove the name table (saves significant space).
+var scrubFlags = flag.NewFlagSet("scrub", flag.ExitOnError)
+var scrubMetadata = scrubFlags.Bool("metadata", false, "remove only the metadata that identifies who made the font and when, instead of the whole name table")
+
+// Scrub remove the name table (saves significant space), or with -metadata
+// only the metadata that identifies the font's makers.
 func Scrub(font *sfnt.Font) error {
-	if font.HasTable(sfnt.TagName) {
+	if *scrubMetadata {
+		if err := font.ScrubMetadata(); err != nil {
+			return err
+		}
+	} else if font.HasTable(sfnt.TagName) {
 		font.AddTable(sfnt.TagName, sfnt.NewTableName())
 	}
 
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/ConradIrwin/font/httpapi"
+)
+
+var serveFlags = flag.NewFlagSet("serve", flag.ExitOnError)
+var serveListen = serveFlags.String("listen", ":8080", "the address to listen on")
+var serveMaxSize = serveFlags.Int64("max-size", httpapi.DefaultMaxSize, "the largest font accepted, in bytes")
+var serveMaxTablesSize = serveFlags.Int64("max-tables-size", httpapi.DefaultMaxTablesSize, "the largest total size of a font's tables once decompressed, in bytes")
+var serveMaxConcurrent = serveFlags.Int("max-concurrent", runtime.GOMAXPROCS(0), "the number of requests handled at once (others wait)")
+var serveTimeout = serveFlags.Duration("timeout", time.Minute, "the longest time spent waiting for, parsing and subsetting each font")
+var serveFetch = serveFlags.Bool("fetch", false, "allow fonts to be fetched with ?url= (only enable this on trusted networks)")
+
+// Serve runs an HTTP server that handles /info, /validate, /axes, /subset and /convert
+// requests, as described in the httpapi package.
+func Serve() error {
+	handler := &httpapi.Handler{MaxSize: *serveMaxSize, MaxTablesSize: *serveMaxTablesSize}
+	if *serveFetch {
+		handler.Client = &http.Client{Timeout: *serveTimeout}
+	}
+
+	// Limiting the number of concurrent requests limits the CPU and memory they
+	// use: -max-size bounds each upload, and -max-tables-size bounds the tables
+	// decompressed from it, although parsing the tables takes more again.
+	// The timeout stops waiting for a slot, parsing and subsetting, but not
+	// the reports from /info, /validate and /axes once the font is parsed.
+	slots := make(chan struct{}, *serveMaxConcurrent)
+	limited := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), *serveTimeout)
+		defer cancel()
+
+		select {
+		case slots <- struct{}{}:
+			defer func() { <-slots }()
+		case <-ctx.Done():
+			http.Error(w, "server is busy", http.StatusServiceUnavailable)
+			return
+		}
+		handler.ServeHTTP(w, r.WithContext(ctx))
+	})
+
+	server := &http.Server{
+		Addr:              *serveListen,
+		Handler:           limited,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	fmt.Fprintf(os.Stderr, "Listening on %s\n", *serveListen)
+	return server.ListenAndServe()
+}
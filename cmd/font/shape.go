@@ -0,0 +1,53 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/ConradIrwin/font/sfnt"
+)
+
+var shapeFlags = flag.NewFlagSet("shape", flag.ExitOnError)
+var shapeText = shapeFlags.String("text", "office", "text to shape")
+var shapeFeatures = shapeFlags.String("features", "ccmp,liga,kern", "comma separated list of features to apply")
+
+// Shape prints the glyphs that -text is shaped to with the features given by -features.
+func Shape(font *sfnt.Font) error {
+	var features []sfnt.Tag
+	for _, name := range strings.Split(*shapeFeatures, ",") {
+		if name == "" {
+			continue
+		}
+		tag, err := sfnt.NamedTag(fmt.Sprintf("%-4s", name))
+		if err != nil {
+			return fmt.Errorf("invalid feature %q: %s", name, err)
+		}
+		features = append(features, tag)
+	}
+
+	glyphs, err := font.Shape(*shapeText, features)
+	if err != nil {
+		return err
+	}
+
+	var names []string
+	if font.HasTable(sfnt.TagPost) {
+		post, err := font.PostTable()
+		if err != nil {
+			return err
+		}
+		names = post.GlyphNames()
+	}
+
+	fmt.Printf("%6s %-20s %7s %13s %13s\n", "gid", "name", "cluster", "advance", "offset")
+	for _, g := range glyphs {
+		name := "-"
+		if int(g.GlyphID) < len(names) {
+			name = names[g.GlyphID]
+		}
+		fmt.Printf("%6d %-20s %7d %13s %13s\n", g.GlyphID, name, g.Cluster,
+			fmt.Sprintf("%d,%d", g.XAdvance, g.YAdvance), fmt.Sprintf("%d,%d", g.XOffset, g.YOffset))
+	}
+	return nil
+}
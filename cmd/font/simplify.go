@@ -0,0 +1,25 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ConradIrwin/font/sfnt"
+)
+
+var simplifyFlags = flag.NewFlagSet("simplify", flag.ExitOnError)
+var simplifyTolerance = simplifyFlags.Float64("tolerance", 1, "the largest change to an outline in font units")
+
+// Simplify removes redundant points from the outlines of the font, and writes it
+// to stdout.
+func Simplify(font *sfnt.Font) error {
+	changed, err := font.SimplifyOutlines(*simplifyTolerance)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Simplified %d glyphs\n", changed)
+
+	_, err = font.WriteOTF(os.Stdout)
+	return err
+}
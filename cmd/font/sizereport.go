@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/zlib"
+	"fmt"
+
+	"github.com/ConradIrwin/font/sfnt"
+)
+
+// layoutTables contain OpenType and TrueType layout features, such as kerning and ligatures.
+var layoutTables = []string{"GDEF", "GPOS", "GSUB", "BASE", "JSTF", "MATH", "kern", "morx", "kerx"}
+
+// SizeReport prints the size of each table, uncompressed and compressed as it would be
+// in a WOFF file, and how much space would be saved by removing hinting, names or layout.
+// The ratio is the WOFF size as a percentage of the uncompressed size, and the share
+// is each table's percentage of the total size when compressed with DEFLATE.
+func SizeReport(font *sfnt.Font) error {
+	tables := map[sfnt.Tag][]byte{}
+	for _, tag := range font.Tags() {
+		data, err := font.TableData(tag)
+		if err != nil {
+			return err
+		}
+		tables[tag] = data
+	}
+
+	// Each table compressed on its own with the best DEFLATE compression shows which
+	// tables dominate the compressed size. WOFF2 files are compressed with Brotli,
+	// which this package has no encoder for, so their size is not reported.
+	deflated := map[sfnt.Tag]int{}
+	totalDeflated := 0
+	for tag, data := range tables {
+		deflated[tag] = deflateSize(data)
+		totalDeflated += deflated[tag]
+	}
+
+	// WOFF files have a 44 byte header, and a 20 byte directory entry for each table.
+	total, totalWOFF := 12, 44
+	fmt.Printf("%-6s %10s %10s %7s %10s %7s\n", "table", "size", "woff", "ratio", "deflate", "share")
+	for _, tag := range font.Tags() {
+		size, woff := padded(len(tables[tag])), padded(woffSize(tables[tag]))
+		total += 16 + size
+		totalWOFF += 20 + woff
+		fmt.Printf("%-6q %10d %10d %6.1f%% %10d %6.1f%%\n", tag, size, woff, percent(woff, size), deflated[tag], percent(deflated[tag], totalDeflated))
+	}
+	fmt.Printf("%-6s %10d %10d %6.1f%% %10d\n", "total", total, totalWOFF, percent(totalWOFF, total), totalDeflated)
+
+	fmt.Printf("\n%-8s %10s %10s\n", "removing", "saves", "woff")
+	hinting, hintingWOFF, err := hintingSavings(font, tables)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%-8s %10d %10d\n", "hinting", hinting, hintingWOFF)
+
+	if data, found := tables[sfnt.TagName]; found {
+		empty := sfnt.NewTableName().Bytes()
+		fmt.Printf("%-8s %10d %10d\n", "names", padded(len(data))-padded(len(empty)), padded(woffSize(data))-padded(woffSize(empty)))
+	}
+
+	layout, layoutWOFF := tableSavings(tables, layoutTables)
+	fmt.Printf("%-8s %10d %10d\n", "layout", layout, layoutWOFF)
+	return nil
+}
+
+// hintingSavings returns the space that would be saved by removing the hinting
+// tables and the instructions of each TrueType glyph. Hints in CFF charstrings
+// are not counted.
+func hintingSavings(font *sfnt.Font, tables map[sfnt.Tag][]byte) (int, int, error) {
+	saved, savedWOFF := tableSavings(tables, hintingTables)
+	if !font.HasTable(sfnt.TagGlyf) {
+		return saved, savedWOFF, nil
+	}
+
+	glyf, err := font.GlyfTable()
+	if err != nil {
+		return 0, 0, err
+	}
+	var with, without bytes.Buffer
+	for i, data := range glyf.Glyphs {
+		with.Write(data)
+		glyph, err := glyf.Glyph(uint16(i))
+		if err != nil {
+			return 0, 0, err
+		}
+		if glyph == nil || len(glyph.Instructions) == 0 {
+			without.Write(data)
+			continue
+		}
+		glyph.Instructions = nil
+		without.Write(glyph.Bytes())
+	}
+	saved += with.Len() - without.Len()
+	savedWOFF += woffSize(with.Bytes()) - woffSize(without.Bytes())
+	return saved, savedWOFF, nil
+}
+
+// tableSavings returns the space that would be saved by removing the named tables.
+func tableSavings(tables map[sfnt.Tag][]byte, names []string) (int, int) {
+	saved, savedWOFF := 0, 0
+	for _, name := range names {
+		if data, found := tables[sfnt.MustNamedTag(name)]; found {
+			saved += 16 + padded(len(data))
+			savedWOFF += 20 + padded(woffSize(data))
+		}
+	}
+	return saved, savedWOFF
+}
+
+// woffSize returns the size of the table in a WOFF file, which is compressed with
+// zlib unless that would make it larger.
+func woffSize(data []byte) int {
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	w.Write(data)
+	w.Close()
+	if buf.Len() < len(data) {
+		return buf.Len()
+	}
+	return len(data)
+}
+
+// deflateSize returns the size of the data compressed with the best DEFLATE compression.
+func deflateSize(data []byte) int {
+	var buf bytes.Buffer
+	w, _ := flate.NewWriter(&buf, flate.BestCompression)
+	w.Write(data)
+	w.Close()
+	return buf.Len()
+}
+
+// percent returns n as a percentage of total.
+func percent(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return 100 * float64(n) / float64(total)
+}
+
+// padded returns the size rounded up to a multiple of four, as tables are aligned.
+func padded(size int) int {
+	return (size + 3) &^ 3
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"flag"
+	"math"
+	"os"
+
+	"github.com/ConradIrwin/font/sfnt"
+)
+
+var specimenFlags = flag.NewFlagSet("specimen", flag.ExitOnError)
+var specimenFormat = specimenFlags.String("format", "svg", "output format, either svg or png")
+var specimenWidth = specimenFlags.Int("width", 800, "width of the image in pixels")
+var specimenText = specimenFlags.String("text", "The quick brown fox jumps over the lazy dog. Pack my box with five dozen liquor jugs. How vexingly quick daft zebras jump!", "sample paragraph")
+
+// specimenMargin is the space around the edge of the specimen in pixels.
+const specimenMargin = 20
+
+// specimenLine is a line of text in the specimen, and its size in pixels.
+type specimenLine struct {
+	text string
+	size float64
+}
+
+// Specimen renders a specimen sheet showing the alphabet, numerals and a sample paragraph.
+func Specimen(font *sfnt.Font) error {
+	r, err := newTextRenderer(font)
+	if err != nil {
+		return err
+	}
+
+	title := "Unnamed font"
+	if font.HasTable(sfnt.TagName) {
+		name, err := font.NameTable()
+		if err != nil {
+			return err
+		}
+		if family := name.Lookup(sfnt.NameFontFamily); family != "" {
+			title = family + " " + name.Lookup(sfnt.NameFontSubfamily)
+		}
+	}
+
+	lines := []specimenLine{
+		{title, 32},
+		{"ABCDEFGHIJKLMNOPQRSTUVWXYZ", 24},
+		{"abcdefghijklmnopqrstuvwxyz", 24},
+		{"0123456789 !?&@#%*()[]{}.,:;", 24},
+	}
+	width := float64(*specimenWidth - 2*specimenMargin)
+	for _, line := range r.wrap(*specimenText, 16, width) {
+		lines = append(lines, specimenLine{line, 16})
+	}
+
+	c := &canvas{width: *specimenWidth}
+	y := float64(specimenMargin)
+	for _, line := range lines {
+		y += line.size * 1.3
+		if err := r.draw(c, line.text, specimenMargin, y, line.size); err != nil {
+			return err
+		}
+	}
+	c.height = int(math.Ceil(y)) + specimenMargin
+
+	return c.write(os.Stdout, *specimenFormat)
+}
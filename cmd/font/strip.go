@@ -0,0 +1,108 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ConradIrwin/font/sfnt"
+)
+
+var stripFlags = flag.NewFlagSet("strip", flag.ExitOnError)
+var stripTables = stripFlags.String("tables", "DSIG", "comma separated list of tables to remove, 'hinting' and 'private' are also accepted")
+
+// requiredTables may never be removed by strip, as the font would no longer work.
+var requiredTables = []string{"cmap", "head", "hhea", "hmtx", "maxp", "name", "OS/2", "post", "glyf", "loca", "CFF ", "CFF2"}
+
+// hintingTables contain TrueType instructions and the data only used by them.
+var hintingTables = []string{"cvt ", "fpgm", "prep", "hdmx", "VDMX", "LTSH", "TTFA"}
+
+// Strip removes the selected tables, and reports how much space was saved.
+// Removing 'hinting' also removes the instructions of each TrueType glyph.
+func Strip(font *sfnt.Font) error {
+	var tags []sfnt.Tag
+	hinting := false
+	for _, name := range strings.Split(*stripTables, ",") {
+		switch name {
+		case "":
+		case "hinting":
+			hinting = true
+			for _, name := range hintingTables {
+				tags = append(tags, sfnt.MustNamedTag(name))
+			}
+		case "private":
+			for _, tag := range font.Tags() {
+				if !sfnt.IsKnownTable(tag) {
+					tags = append(tags, tag)
+				}
+			}
+		default:
+			tag, err := sfnt.NamedTag(fmt.Sprintf("%-4s", name))
+			if err != nil {
+				return fmt.Errorf("invalid table %q: %s", name, err)
+			}
+			tags = append(tags, tag)
+		}
+	}
+
+	for _, tag := range tags {
+		for _, name := range requiredTables {
+			if tag.String() == name {
+				return fmt.Errorf("refusing to remove required table %q", tag)
+			}
+		}
+	}
+
+	saved := 0
+	for _, tag := range tags {
+		if !font.HasTable(tag) {
+			continue
+		}
+		data, err := font.TableData(tag)
+		if err != nil {
+			return err
+		}
+		saved += (len(data)+3)&^3 + 16 // padded table, plus its directory entry.
+		font.RemoveTable(tag)
+		fmt.Fprintf(os.Stderr, "Removed %q (%d bytes)\n", tag, len(data))
+	}
+	if hinting && font.HasTable(sfnt.TagGlyf) {
+		removed, err := stripInstructions(font)
+		if err != nil {
+			return err
+		}
+		saved += removed
+		fmt.Fprintf(os.Stderr, "Removed glyph instructions (%d bytes)\n", removed)
+	}
+	fmt.Fprintf(os.Stderr, "Saved %d bytes\n", saved)
+
+	_, err := font.WriteOTF(os.Stdout)
+	return err
+}
+
+// stripInstructions removes the instructions of each glyph in the 'glyf' table,
+// and returns the number of bytes removed.
+func stripInstructions(font *sfnt.Font) (int, error) {
+	glyf, err := font.GlyfTable()
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	for i, data := range glyf.Glyphs {
+		glyph, err := glyf.Glyph(uint16(i))
+		if err != nil {
+			return 0, err
+		}
+		if len(glyph.Instructions) == 0 {
+			continue
+		}
+		glyph.Instructions = nil
+		if err := glyf.SetGlyph(uint16(i), glyph); err != nil {
+			return 0, err
+		}
+		removed += len(data) - len(glyf.Glyphs[i])
+	}
+	font.AddTable(sfnt.TagGlyf, glyf)
+	return removed, nil
+}
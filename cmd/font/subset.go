@@ -0,0 +1,123 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/ConradIrwin/font/sfnt"
+)
+
+var subsetFlags = flag.NewFlagSet("subset", flag.ExitOnError)
+var subsetGlyphs = subsetFlags.String("glyphs", "", "comma separated list of glyph names to keep (e.g. -glyphs A,B,uni20AC)")
+var subsetGIDs = subsetFlags.String("gids", "", "comma separated list of glyph ids or ranges to keep (e.g. -gids 1-50,70)")
+var subsetText = subsetFlags.String("text", "", "keep the glyphs of the characters in this text (e.g. -text \"Hello, world\")")
+var subsetUnicodes = subsetFlags.String("unicodes", "", "comma separated list of code points or ranges to keep, in hex (e.g. -unicodes U+0041-005A,20AC)")
+
+// Subset removes the outlines of all glyphs not selected by -glyphs, -gids,
+// -text or -unicodes.
+func Subset(font *sfnt.Font) error {
+	gids, err := parseGIDs(*subsetGIDs)
+	if err != nil {
+		return err
+	}
+
+	runes, err := parseUnicodes(*subsetUnicodes)
+	if err != nil {
+		return err
+	}
+	runes = append(runes, []rune(*subsetText)...)
+	if len(runes) > 0 {
+		mapped, err := font.GlyphsForRunes(runes)
+		if err != nil {
+			return err
+		}
+		gids = append(gids, mapped...)
+	}
+
+	if *subsetGlyphs != "" {
+		named, err := font.GlyphIDs(strings.Split(*subsetGlyphs, ","))
+		if err != nil {
+			return err
+		}
+		gids = append(gids, named...)
+	}
+
+	if len(gids) == 0 {
+		return fmt.Errorf("no glyphs selected, use -glyphs, -gids, -text or -unicodes")
+	}
+
+	if err := font.SubsetGlyphs(gids); err != nil {
+		return err
+	}
+
+	_, err = font.WriteOTF(os.Stdout)
+	return err
+}
+
+// parseGIDs parses a comma separated list of glyph ids and ranges, like "1-50,70".
+func parseGIDs(list string) ([]uint16, error) {
+	var gids []uint16
+	for _, item := range strings.Split(list, ",") {
+		if item == "" {
+			continue
+		}
+
+		from, to := item, item
+		if i := strings.IndexByte(item, '-'); i >= 0 {
+			from, to = item[:i], item[i+1:]
+		}
+
+		start, err := strconv.ParseUint(from, 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("invalid glyph id %q", item)
+		}
+		end, err := strconv.ParseUint(to, 10, 16)
+		if err != nil || end < start {
+			return nil, fmt.Errorf("invalid glyph id %q", item)
+		}
+
+		for gid := start; gid <= end; gid++ {
+			gids = append(gids, uint16(gid))
+		}
+	}
+	return gids, nil
+}
+
+// parseUnicodes parses a comma separated list of hexadecimal code points and
+// ranges, optionally prefixed with U+, like "U+0041-005A,20AC".
+func parseUnicodes(list string) ([]rune, error) {
+	var runes []rune
+	for _, item := range strings.Split(list, ",") {
+		if item == "" {
+			continue
+		}
+
+		from, to := item, item
+		if i := strings.IndexByte(item, '-'); i >= 0 {
+			from, to = item[:i], item[i+1:]
+		}
+
+		start, err := strconv.ParseUint(trimCodePointPrefix(from), 16, 32)
+		if err != nil || start > unicode.MaxRune {
+			return nil, fmt.Errorf("invalid code point %q", item)
+		}
+		end, err := strconv.ParseUint(trimCodePointPrefix(to), 16, 32)
+		if err != nil || end > unicode.MaxRune || end < start {
+			return nil, fmt.Errorf("invalid code point %q", item)
+		}
+
+		for r := start; r <= end; r++ {
+			runes = append(runes, rune(r))
+		}
+	}
+	return runes, nil
+}
+
+// trimCodePointPrefix removes the U+ from the start of a code point.
+func trimCodePointPrefix(s string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(s, "U+"), "u+")
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/ConradIrwin/font/sfnt"
+)
+
+var synthFlags = flag.NewFlagSet("synth", flag.ExitOnError)
+var synthDir = synthFlags.String("dir", ".", "the directory to write the fonts to")
+
+// Synth writes a set of minimal and pathological fonts to -dir, for use when
+// fuzzing or testing font parsers.
+func Synth() error {
+	basic, err := synthBasic()
+	if err != nil {
+		return err
+	}
+	basicTables, err := synthTables(basic)
+	if err != nil {
+		return err
+	}
+
+	fixtures := map[string]func() ([]byte, error){
+		"minimal.ttf": func() ([]byte, error) {
+			return synthWrite(sfnt.NewBuilder(1000))
+		},
+		"basic.ttf": func() ([]byte, error) {
+			return basic, nil
+		},
+		"no-tables.ttf": func() ([]byte, error) {
+			return synthRaw(nil), nil
+		},
+		"max-glyphs.ttf":   synthMaxGlyphs,
+		"max-contours.ttf": synthMaxContours,
+	}
+
+	for tag := range basicTables {
+		tag := tag
+		name := fmt.Sprintf("empty-%s.ttf", synthTagName(tag))
+		fixtures[name] = func() ([]byte, error) {
+			tables := make(map[sfnt.Tag][]byte, len(basicTables))
+			for t, data := range basicTables {
+				tables[t] = data
+			}
+			tables[tag] = nil
+			return synthRaw(tables), nil
+		}
+	}
+
+	// Truncate the basic font part way through the header, the directory and the tables.
+	for _, length := range []int{4, 12, 12 + 16*len(basicTables)/2, 12 + 16*len(basicTables), len(basic) / 2, len(basic) - 1} {
+		length := length
+		fixtures[fmt.Sprintf("truncated-%d.ttf", length)] = func() ([]byte, error) {
+			return basic[:length], nil
+		}
+	}
+
+	if err := os.MkdirAll(*synthDir, 0755); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(fixtures))
+	for name := range fixtures {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		data, err := fixtures[name]()
+		if err != nil {
+			return fmt.Errorf("%s: %s", name, err)
+		}
+		path := filepath.Join(*synthDir, name)
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return err
+		}
+		fmt.Println(path)
+	}
+	return nil
+}
+
+// synthSquare returns a clockwise square contour.
+func synthSquare(x, y, size int16) []sfnt.GlyphPoint {
+	return []sfnt.GlyphPoint{{X: x, Y: y, OnCurve: true}, {X: x, Y: y + size, OnCurve: true}, {X: x + size, Y: y + size, OnCurve: true}, {X: x + size, Y: y, OnCurve: true}}
+}
+
+// synthBasic returns a small font with a simple glyph, a composite glyph, an
+// empty glyph, and characters mapped both inside and outside the BMP.
+func synthBasic() ([]byte, error) {
+	b := sfnt.NewBuilder(1000)
+	b.SetName(sfnt.NameFontFamily, "Synth Basic")
+
+	space := b.AddGlyph("space", 250, nil)
+	b.Map(' ', space)
+	square := b.AddGlyph("square", 600, [][]sfnt.GlyphPoint{synthSquare(100, 0, 400)})
+	b.Map('a', square)
+	ring := b.AddGlyph("ring", 600, [][]sfnt.GlyphPoint{synthSquare(100, 0, 400), reverseContour(synthSquare(200, 100, 200))})
+	b.Map('o', ring)
+	pair := b.AddComposite("pair", 1200, []sfnt.GlyphComponent{
+		{GlyphID: square, Transform: [4]float64{1, 0, 0, 1}},
+		{GlyphID: ring, Arg1: 600, Transform: [4]float64{1, 0, 0, 1}},
+	})
+	b.Map(0x1F600, pair)
+
+	return synthWrite(b)
+}
+
+// synthMaxGlyphs returns a font with the maximum number of glyphs, all of which
+// are empty and mapped to consecutive characters outside the BMP.
+func synthMaxGlyphs() ([]byte, error) {
+	b := sfnt.NewBuilder(1000)
+	b.SetName(sfnt.NameFontFamily, "Synth Max Glyphs")
+	for i := 1; i <= 0xFFFF-1; i++ {
+		gid := b.AddGlyph(fmt.Sprintf("g%d", i), 500, nil)
+		b.Map(rune(0x10000+i), gid)
+	}
+	return synthWrite(b)
+}
+
+// synthMaxContours returns a font with a glyph that has as many points as
+// the glyph format allows.
+func synthMaxContours() ([]byte, error) {
+	b := sfnt.NewBuilder(1000)
+	b.SetName(sfnt.NameFontFamily, "Synth Max Contours")
+
+	var contours [][]sfnt.GlyphPoint
+	for i := 0; i < 0xFFFF/4; i++ {
+		contours = append(contours, synthSquare(int16(i%128)*8, int16(i/128)*8, 4))
+	}
+	b.Map('a', b.AddGlyph("grid", 1000, contours))
+	return synthWrite(b)
+}
+
+func reverseContour(contour []sfnt.GlyphPoint) []sfnt.GlyphPoint {
+	reversed := make([]sfnt.GlyphPoint, len(contour))
+	for i, p := range contour {
+		reversed[len(contour)-1-i] = p
+	}
+	return reversed
+}
+
+func synthWrite(b *sfnt.Builder) ([]byte, error) {
+	font, err := b.Font()
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if _, err := font.WriteOTFWithOptions(&buf, sfnt.WriteOptions{Reproducible: true}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// synthTables returns the content of each table in the font.
+func synthTables(data []byte) (map[sfnt.Tag][]byte, error) {
+	font, err := sfnt.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	tables := make(map[sfnt.Tag][]byte)
+	for _, tag := range font.Tags() {
+		if tables[tag], err = font.TableData(tag); err != nil {
+			return nil, err
+		}
+	}
+	return tables, nil
+}
+
+// synthRaw writes a TrueType font containing the given tables without parsing
+// or otherwise checking them, so that the font can be as broken as required.
+func synthRaw(tables map[sfnt.Tag][]byte) []byte {
+	tags := make([]sfnt.Tag, 0, len(tables))
+	for tag := range tables {
+		tags = append(tags, tag)
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Number < tags[j].Number })
+
+	entrySelector := 0
+	for 1<<(entrySelector+1) <= len(tags) {
+		entrySelector++
+	}
+	searchRange := 16 << entrySelector
+	if len(tags) == 0 {
+		searchRange, entrySelector = 0, 0
+	}
+
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.BigEndian, []uint32{0x00010000})
+	binary.Write(&buf, binary.BigEndian, []uint16{uint16(len(tags)), uint16(searchRange), uint16(entrySelector), uint16(16*len(tags) - searchRange)})
+
+	offset := 12 + 16*len(tags)
+	for _, tag := range tags {
+		data := tables[tag]
+		binary.Write(&buf, binary.BigEndian, []uint32{tag.Number, synthChecksum(data), uint32(offset), uint32(len(data))})
+		offset += (len(data) + 3) &^ 3
+	}
+	for _, tag := range tags {
+		data := tables[tag]
+		buf.Write(data)
+		buf.Write(make([]byte, ((len(data)+3)&^3)-len(data)))
+	}
+	return buf.Bytes()
+}
+
+func synthChecksum(data []byte) uint32 {
+	var sum uint32
+	for i := 0; i < len(data); i += 4 {
+		var word [4]byte
+		copy(word[:], data[i:])
+		sum += binary.BigEndian.Uint32(word[:])
+	}
+	return sum
+}
+
+// synthTagName returns the tag in a form that can be used in a file name.
+func synthTagName(tag sfnt.Tag) string {
+	name := []byte(tag.String())
+	for i, c := range name {
+		if c == '/' || c == ' ' {
+			name[i] = '_'
+		}
+	}
+	return string(bytes.TrimRight(name, "_"))
+}
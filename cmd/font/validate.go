@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ConradIrwin/font/httpapi"
+	"github.com/ConradIrwin/font/sfnt"
+)
+
+var validateFlags = flag.NewFlagSet("validate", flag.ExitOnError)
+var validatePDFA = validateFlags.Bool("pdfa", false, "also check that the font can be embedded in PDF/A documents")
+var validateProfile = validateFlags.String("profile", "desktop", "the checks to make: minimal, web (what browsers reject), desktop or strict (the OpenType specification)")
+var validateFormat = validateFlags.String("format", "text", "output format, either text, or json with the byte range of each problem in its table and an identifier for its fix")
+var validateRules = validateFlags.String("rules", "", "a JSON file of custom rules that every font must pass, such as a vendor ID or a pattern for a name entry")
+
+// validateRulesRegistered is set once the -rules have been registered, as
+// Validate is called for each font.
+var validateRulesRegistered = false
+
+// Validate prints any problems found in the font, and fails if any are errors.
+func Validate(font *sfnt.Font) error {
+	profile, err := sfnt.ParseProfile(*validateProfile)
+	if err != nil {
+		return err
+	}
+	if *validateRules != "" && !validateRulesRegistered {
+		if err := registerRules(*validateRules); err != nil {
+			return err
+		}
+		validateRulesRegistered = true
+	}
+	problems, err := font.ValidateProfile(profile)
+	if err != nil {
+		return err
+	}
+	if *validatePDFA {
+		pdfa, err := font.ValidatePDFA()
+		if err != nil {
+			return err
+		}
+		problems = append(problems, pdfa...)
+	}
+
+	switch *validateFormat {
+	case "text":
+		for _, problem := range problems {
+			fmt.Println(problem)
+		}
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(httpapi.NewValidation(problems)); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown format %q, use text or json", *validateFormat)
+	}
+
+	errors := 0
+	for _, problem := range problems {
+		if problem.Severity == sfnt.SeverityError {
+			errors++
+		}
+	}
+	if errors > 0 {
+		return fmt.Errorf("found %d errors", errors)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ConradIrwin/font/sfnt"
+)
+
+var waterfallFlags = flag.NewFlagSet("waterfall", flag.ExitOnError)
+var waterfallFormat = waterfallFlags.String("format", "svg", "output format, either svg or png")
+var waterfallText = waterfallFlags.String("text", "Hamburgefonstiv", "text to render")
+var waterfallSizes = waterfallFlags.String("sizes", "8,10,12,16,24", "comma separated list of sizes in pixels")
+
+// Waterfall renders the text given by -text at each of the sizes given by -sizes.
+func Waterfall(font *sfnt.Font) error {
+	r, err := newTextRenderer(font)
+	if err != nil {
+		return err
+	}
+
+	var sizes []float64
+	for _, s := range strings.Split(*waterfallSizes, ",") {
+		size, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil || size <= 0 {
+			return fmt.Errorf("invalid size %q", s)
+		}
+		sizes = append(sizes, size)
+	}
+
+	// Each line is labelled with its size, so leave room for the widest label.
+	labelSize := 10.0
+	labelWidth := 0.0
+	for _, size := range sizes {
+		labelWidth = math.Max(labelWidth, r.width(waterfallLabel(size), labelSize))
+	}
+
+	c := &canvas{}
+	y := float64(specimenMargin)
+	width := 0.0
+	for _, size := range sizes {
+		y += math.Max(size, labelSize) * 1.3
+		if err := r.draw(c, waterfallLabel(size), specimenMargin, y, labelSize); err != nil {
+			return err
+		}
+		x := specimenMargin + labelWidth + labelSize
+		if err := r.draw(c, *waterfallText, x, y, size); err != nil {
+			return err
+		}
+		width = math.Max(width, x+r.width(*waterfallText, size))
+	}
+	c.width = int(math.Ceil(width)) + specimenMargin
+	c.height = int(math.Ceil(y)) + specimenMargin
+
+	return c.write(os.Stdout, *waterfallFormat)
+}
+
+// waterfallLabel is the label shown next to the line rendered at size.
+func waterfallLabel(size float64) string {
+	return strconv.FormatFloat(size, 'f', -1, 64) + "px"
+}
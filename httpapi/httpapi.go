@@ -0,0 +1,439 @@
+// Package httpapi provides an http.Handler that reports information about fonts
+// as JSON, for services that inspect fonts uploaded by their users.
+//
+// The handler serves these endpoints, relative to where it is mounted:
+//
+//	/info      the tables in the font, and the entries in its 'name' table
+//	/validate  the problems found by (*sfnt.Font).Validate
+//	/axes      the variation axes and named instances of a variable font
+//	/subset    the font subset to the glyphs given by the "gids" and "glyphs" parameters
+//	/convert   the font in OpenType format (for example, to decompress a WOFF2 font)
+//
+// The first three respond with JSON, and the others with the font.
+// The font is given as the body of a POST request (either the raw font, or a
+// multipart form with a "font" file), or by a "url" query parameter if the
+// handler has a Client.
+//
+//	http.Handle("/fonts/", http.StripPrefix("/fonts", &httpapi.Handler{}))
+package httpapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/ConradIrwin/font/sfnt"
+)
+
+// DefaultMaxSize is the largest font accepted by a Handler with no MaxSize.
+const DefaultMaxSize = 32 << 20
+
+// DefaultMaxTablesSize is the largest total size of the decompressed tables of
+// a font accepted by a Handler with no MaxTablesSize.
+const DefaultMaxTablesSize = 128 << 20
+
+// Handler serves information about fonts as JSON.
+type Handler struct {
+	// MaxSize is the largest font that will be read, in bytes.
+	// If it is zero, DefaultMaxSize is used.
+	MaxSize int64
+
+	// MaxTablesSize is the largest total size of the font's tables, in bytes,
+	// once they are decompressed from a WOFF or WOFF2 font. Larger fonts are
+	// rejected before they are decompressed. If it is zero, DefaultMaxTablesSize
+	// is used.
+	MaxTablesSize int64
+
+	// Client is used to fetch fonts given by the "url" query parameter. If it is nil,
+	// fetching fonts by URL is disabled. Fetching arbitrary URLs on behalf of users
+	// can expose internal services, so the client should restrict where it connects.
+	Client *http.Client
+}
+
+// Info is the response from the /info endpoint.
+type Info struct {
+	Type   string      `json:"type"`
+	Tables []TableInfo `json:"tables"`
+	Names  []NameInfo  `json:"names"`
+}
+
+// TableInfo describes a table in the font.
+type TableInfo struct {
+	Tag    string `json:"tag"`
+	Length uint32 `json:"length"`
+}
+
+// NameInfo is an entry in the font's 'name' table.
+type NameInfo struct {
+	PlatformID uint16 `json:"platformID"`
+	EncodingID uint16 `json:"encodingID"`
+	LanguageID uint16 `json:"languageID"`
+	NameID     uint16 `json:"nameID"`
+	Label      string `json:"label"`
+	Value      string `json:"value"`
+}
+
+// Validation is the response from the /validate endpoint.
+type Validation struct {
+	Valid    bool      `json:"valid"` // Valid is false if any problems are errors.
+	Problems []Problem `json:"problems"`
+}
+
+// Problem is a problem found in the font.
+type Problem struct {
+	Severity string `json:"severity"`
+	Table    string `json:"table"`
+	GlyphID  *int   `json:"glyphID,omitempty"`
+	Message  string `json:"message"`
+	Offset   int    `json:"offset"`        // Offset of the bytes in the table that the problem is about.
+	Length   int    `json:"length"`        // Length of those bytes, or 0 for the whole table.
+	Fix      string `json:"fix,omitempty"` // Fix identifies a change that fixes the problem.
+}
+
+// Axes is the response from the /axes endpoint. Both lists are empty if the font
+// is not a variable font.
+type Axes struct {
+	Axes      []Axis     `json:"axes"`
+	Instances []Instance `json:"instances"`
+}
+
+// Axis is a variation axis of the font. The name comes from the font, or from
+// sfnt.LookupAxis if the font does not name the axis.
+type Axis struct {
+	Tag         string  `json:"tag"`
+	Name        string  `json:"name"`
+	Min         float64 `json:"min"`
+	Default     float64 `json:"default"`
+	Max         float64 `json:"max"`
+	Hidden      bool    `json:"hidden"`
+	CSSProperty string  `json:"cssProperty,omitempty"`
+}
+
+// Instance is a named instance of the font.
+type Instance struct {
+	Name           string             `json:"name"`
+	PostScriptName string             `json:"postScriptName,omitempty"`
+	Coordinates    map[string]float64 `json:"coordinates"`
+}
+
+// ServeHTTP reads the font from the request and responds with the JSON for the
+// requested endpoint. Errors are reported as {"error": "..."}.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var report func(*sfnt.Font) (interface{}, error)
+	var transform func(*sfnt.Font, *http.Request) error
+	switch path.Base(r.URL.Path) {
+	case "info":
+		report = info
+	case "validate":
+		report = validate
+	case "axes":
+		report = axes
+	case "subset":
+		transform = subset
+	case "convert":
+		transform = func(*sfnt.Font, *http.Request) error { return nil }
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("not found: %s", r.URL.Path))
+		return
+	}
+
+	data, status, err := h.readFont(w, r)
+	if err != nil {
+		writeError(w, status, err)
+		return
+	}
+	maxTablesSize := h.MaxTablesSize
+	if maxTablesSize == 0 {
+		maxTablesSize = DefaultMaxTablesSize
+	}
+	font, err := sfnt.ParseWithOptions(r.Context(), bytes.NewReader(data), sfnt.ParseOptions{MaxSize: maxTablesSize})
+	if err == sfnt.ErrTooLarge {
+		writeError(w, http.StatusRequestEntityTooLarge, fmt.Errorf("the font's tables are larger than %d bytes", maxTablesSize))
+		return
+	} else if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Errorf("failed to parse font: %s", err))
+		return
+	}
+
+	if transform != nil {
+		if err := transform(font, r); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err)
+			return
+		}
+		writeFont(w, font)
+		return
+	}
+
+	response, err := report(font)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// readFont returns the content of the font given in the request, or an error and
+// the status code to respond with.
+func (h *Handler) readFont(w http.ResponseWriter, r *http.Request) ([]byte, int, error) {
+	maxSize := h.MaxSize
+	if maxSize == 0 {
+		maxSize = DefaultMaxSize
+	}
+
+	if url := r.URL.Query().Get("url"); url != "" {
+		if h.Client == nil {
+			return nil, http.StatusBadRequest, fmt.Errorf("fetching fonts by url is disabled")
+		}
+		req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, url, nil)
+		if err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+		resp, err := h.Client.Do(req)
+		if err != nil {
+			return nil, http.StatusBadGateway, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, http.StatusBadGateway, fmt.Errorf("fetching %s: %s", url, resp.Status)
+		}
+		return readAll(resp.Body, maxSize)
+	}
+
+	if r.Method != http.MethodPost {
+		return nil, http.StatusMethodNotAllowed, fmt.Errorf("POST a font, or give its url")
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		// Allow a little extra for the rest of the form.
+		r.Body = http.MaxBytesReader(w, r.Body, maxSize+64<<10)
+		file, _, err := r.FormFile("font")
+		if err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+		defer file.Close()
+		return readAll(file, maxSize)
+	}
+	return readAll(r.Body, maxSize)
+}
+
+// readAll reads r, failing if it is longer than maxSize.
+func readAll(r io.Reader, maxSize int64) ([]byte, int, error) {
+	data, err := ioutil.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	if int64(len(data)) > maxSize {
+		return nil, http.StatusRequestEntityTooLarge, fmt.Errorf("font is larger than %d bytes", maxSize)
+	}
+	return data, http.StatusOK, nil
+}
+
+func info(font *sfnt.Font) (interface{}, error) {
+	response := Info{
+		Type:   fontType(font.Type()),
+		Tables: []TableInfo{},
+		Names:  []NameInfo{},
+	}
+	for _, record := range font.Directory() {
+		response.Tables = append(response.Tables, TableInfo{
+			Tag:    record.Tag.String(),
+			Length: record.Length,
+		})
+	}
+
+	if font.HasTable(sfnt.TagName) {
+		name, err := font.NameTable()
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range name.List() {
+			response.Names = append(response.Names, NameInfo{
+				PlatformID: uint16(entry.PlatformID),
+				EncodingID: uint16(entry.EncodingID),
+				LanguageID: uint16(entry.LanguageID),
+				NameID:     uint16(entry.NameID),
+				Label:      entry.Label(),
+				Value:      entry.String(),
+			})
+		}
+	}
+	return response, nil
+}
+
+func validate(font *sfnt.Font) (interface{}, error) {
+	problems, err := font.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return NewValidation(problems), nil
+}
+
+// NewValidation returns the response from the /validate endpoint for the
+// problems found in a font, so that other tools can report problems in the
+// same format.
+func NewValidation(problems []sfnt.Problem) Validation {
+	response := Validation{Valid: true, Problems: []Problem{}}
+	for _, p := range problems {
+		problem := Problem{
+			Severity: p.Severity.String(),
+			Table:    p.Tag.String(),
+			Message:  p.Message,
+			Offset:   p.Offset,
+			Length:   p.Length,
+			Fix:      string(p.Fix),
+		}
+		if p.GlyphID >= 0 {
+			gid := p.GlyphID
+			problem.GlyphID = &gid
+		}
+		if p.Severity == sfnt.SeverityError {
+			response.Valid = false
+		}
+		response.Problems = append(response.Problems, problem)
+	}
+	return response
+}
+
+func axes(font *sfnt.Font) (interface{}, error) {
+	response := Axes{Axes: []Axis{}, Instances: []Instance{}}
+	if !font.HasTable(sfnt.TagFvar) {
+		return response, nil
+	}
+	fvar, err := font.FvarTable()
+	if err != nil {
+		return nil, err
+	}
+
+	lookup := func(sfnt.NameID) string { return "" }
+	if font.HasTable(sfnt.TagName) {
+		name, err := font.NameTable()
+		if err != nil {
+			return nil, err
+		}
+		lookup = name.Lookup
+	}
+
+	for _, axis := range fvar.Axes {
+		info, _ := sfnt.LookupAxis(axis.Tag)
+		name := lookup(axis.NameID)
+		if name == "" {
+			name = info.Name
+		}
+		response.Axes = append(response.Axes, Axis{
+			Tag:         axis.Tag.String(),
+			Name:        name,
+			Min:         axis.Min,
+			Default:     axis.Default,
+			Max:         axis.Max,
+			Hidden:      axis.Hidden(),
+			CSSProperty: info.CSSProperty,
+		})
+	}
+	for _, instance := range fvar.Instances {
+		coordinates := make(map[string]float64, len(fvar.Axes))
+		for i, axis := range fvar.Axes {
+			coordinates[axis.Tag.String()] = instance.Coordinates[i]
+		}
+		i := Instance{
+			Name:        lookup(instance.SubfamilyNameID),
+			Coordinates: coordinates,
+		}
+		if instance.PostScriptNameID != 0xFFFF {
+			i.PostScriptName = lookup(instance.PostScriptNameID)
+		}
+		response.Instances = append(response.Instances, i)
+	}
+	return response, nil
+}
+
+// fontType returns a description of the kind of outlines in the font.
+func fontType(scalerType sfnt.Tag) string {
+	switch scalerType {
+	case sfnt.TypeTrueType, sfnt.TypeAppleTrueType:
+		return "TrueType"
+	case sfnt.TypeOpenType:
+		return "OpenType"
+	case sfnt.TypePostScript1:
+		return "PostScript Type 1"
+	default:
+		return scalerType.String()
+	}
+}
+
+// subset removes the glyphs not selected by the "gids" and "glyphs" parameters
+// (comma separated lists of glyph ids or ranges, and glyph names).
+func subset(font *sfnt.Font, r *http.Request) error {
+	gids, err := parseGIDs(r.URL.Query().Get("gids"))
+	if err != nil {
+		return err
+	}
+	if names := r.URL.Query().Get("glyphs"); names != "" {
+		named, err := font.GlyphIDs(strings.Split(names, ","))
+		if err != nil {
+			return err
+		}
+		gids = append(gids, named...)
+	}
+	if len(gids) == 0 {
+		return fmt.Errorf("no glyphs selected, use gids or glyphs")
+	}
+	return font.SubsetGlyphsContext(r.Context(), gids)
+}
+
+// parseGIDs parses a comma separated list of glyph ids and ranges, like "1-50,70".
+func parseGIDs(list string) ([]uint16, error) {
+	var gids []uint16
+	for _, item := range strings.Split(list, ",") {
+		if item == "" {
+			continue
+		}
+
+		from, to := item, item
+		if i := strings.IndexByte(item, '-'); i >= 0 {
+			from, to = item[:i], item[i+1:]
+		}
+
+		start, err := strconv.ParseUint(from, 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("invalid glyph id %q", item)
+		}
+		end, err := strconv.ParseUint(to, 10, 16)
+		if err != nil || end < start {
+			return nil, fmt.Errorf("invalid glyph id %q", item)
+		}
+
+		for gid := start; gid <= end; gid++ {
+			gids = append(gids, uint16(gid))
+		}
+	}
+	return gids, nil
+}
+
+// writeFont responds with the font in OpenType format.
+func writeFont(w http.ResponseWriter, font *sfnt.Font) {
+	var buf bytes.Buffer
+	if _, err := font.WriteOTF(&buf); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", font.Format().MIMEType())
+	w.Write(buf.Bytes())
+}
+
+func writeJSON(w http.ResponseWriter, status int, response interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
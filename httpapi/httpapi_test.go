@@ -0,0 +1,220 @@
+package httpapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ConradIrwin/font/sfnt"
+)
+
+func readTestFont(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := ioutil.ReadFile(filepath.Join("..", "sfnt", "testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// serve sends the request to a Handler, and decodes the JSON response into v.
+func serve(t *testing.T, h *Handler, r *http.Request, v interface{}) int {
+	t.Helper()
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if err := json.Unmarshal(w.Body.Bytes(), v); err != nil {
+		t.Fatalf("%s %s: invalid JSON %q: %s", r.Method, r.URL, w.Body.String(), err)
+	}
+	return w.Code
+}
+
+func TestInfo(t *testing.T) {
+	r := httptest.NewRequest("POST", "/info", bytes.NewReader(readTestFont(t, "Roboto-BoldItalic.ttf")))
+	var info Info
+	if code := serve(t, &Handler{}, r, &info); code != http.StatusOK {
+		t.Fatalf("POST /info = %d, want %d", code, http.StatusOK)
+	}
+
+	if info.Type != "TrueType" {
+		t.Errorf("Type = %q, want TrueType", info.Type)
+	}
+	found := false
+	for _, name := range info.Names {
+		if name.NameID == uint16(sfnt.NameFontFamily) && name.Value == "Roboto" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Names = %v, want a family name of Roboto", info.Names)
+	}
+	if len(info.Tables) == 0 || info.Tables[0].Length == 0 {
+		t.Errorf("Tables = %v, want the tables in the font", info.Tables)
+	}
+}
+
+func TestValidateMultipart(t *testing.T) {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("font", "open-sans.woff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write(readTestFont(t, "open-sans-v15-latin-regular.woff"))
+	form.Close()
+
+	r := httptest.NewRequest("POST", "/fonts/validate", &body)
+	r.Header.Set("Content-Type", form.FormDataContentType())
+	var validation Validation
+	if code := serve(t, &Handler{}, r, &validation); code != http.StatusOK {
+		t.Fatalf("POST /validate = %d, want %d", code, http.StatusOK)
+	}
+	if !validation.Valid || validation.Problems == nil {
+		t.Errorf("Validation = %+v, want valid with a (possibly empty) list of problems", validation)
+	}
+}
+
+func TestAxes(t *testing.T) {
+	font, err := sfnt.Parse(bytes.NewReader(readTestFont(t, "Roboto-BoldItalic.ttf")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	font.AddTable(sfnt.TagFvar, &sfnt.TableFvar{
+		Axes: []sfnt.VariationAxis{{Tag: sfnt.MustNamedTag("wght"), Min: 100, Default: 400, Max: 900, NameID: sfnt.NameFontSubfamily}},
+		Instances: []sfnt.NamedInstance{
+			{SubfamilyNameID: sfnt.NameFontSubfamily, PostScriptNameID: sfnt.NamePostscript, Coordinates: []float64{700}},
+		},
+	})
+	var buf bytes.Buffer
+	if _, err := font.WriteOTF(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	r := httptest.NewRequest("POST", "/axes", &buf)
+	var got Axes
+	if code := serve(t, &Handler{}, r, &got); code != http.StatusOK {
+		t.Fatalf("POST /axes = %d, want %d", code, http.StatusOK)
+	}
+	want := Axes{
+		Axes:      []Axis{{Tag: "wght", Name: "Bold Italic", Min: 100, Default: 400, Max: 900, CSSProperty: "font-weight"}},
+		Instances: []Instance{{Name: "Bold Italic", PostScriptName: "Roboto-BoldItalic", Coordinates: map[string]float64{"wght": 700}}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("POST /axes = %+v, want %+v", got, want)
+	}
+
+	// Fonts without an 'fvar' table have no axes.
+	r = httptest.NewRequest("POST", "/axes", bytes.NewReader(readTestFont(t, "Roboto-BoldItalic.ttf")))
+	got = Axes{}
+	serve(t, &Handler{}, r, &got)
+	if len(got.Axes) != 0 || got.Axes == nil {
+		t.Errorf("POST /axes = %+v, want an empty list of axes", got)
+	}
+}
+
+func TestURL(t *testing.T) {
+	roboto := readTestFont(t, "Roboto-BoldItalic.ttf")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/roboto.ttf" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(roboto)
+	}))
+	defer server.Close()
+
+	var info Info
+	r := httptest.NewRequest("GET", "/info?url="+server.URL+"/roboto.ttf", nil)
+	if code := serve(t, &Handler{Client: server.Client()}, r, &info); code != http.StatusOK {
+		t.Errorf("GET /info?url= = %d, want %d", code, http.StatusOK)
+	}
+
+	var e map[string]string
+	r = httptest.NewRequest("GET", "/info?url="+server.URL+"/missing.ttf", nil)
+	if code := serve(t, &Handler{Client: server.Client()}, r, &e); code != http.StatusBadGateway {
+		t.Errorf("GET /info?url=missing = %d, want %d", code, http.StatusBadGateway)
+	}
+
+	r = httptest.NewRequest("GET", "/info?url="+server.URL+"/roboto.ttf", nil)
+	if code := serve(t, &Handler{}, r, &e); code != http.StatusBadRequest {
+		t.Errorf("GET /info?url= without a Client = %d, want %d", code, http.StatusBadRequest)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	roboto := readTestFont(t, "Roboto-BoldItalic.ttf")
+	tests := []struct {
+		handler *Handler
+		method  string
+		path    string
+		body    []byte
+		code    int
+	}{
+		{&Handler{}, "POST", "/unknown", roboto, http.StatusNotFound},
+		{&Handler{}, "GET", "/info", nil, http.StatusMethodNotAllowed},
+		{&Handler{}, "POST", "/info", []byte("not a font"), http.StatusUnprocessableEntity},
+		{&Handler{MaxSize: 1024}, "POST", "/info", roboto, http.StatusRequestEntityTooLarge},
+		{&Handler{MaxTablesSize: 1024}, "POST", "/info", readTestFont(t, "Go-Regular.woff2"), http.StatusRequestEntityTooLarge},
+	}
+	for _, test := range tests {
+		var e map[string]string
+		r := httptest.NewRequest(test.method, test.path, bytes.NewReader(test.body))
+		if code := serve(t, test.handler, r, &e); code != test.code || e["error"] == "" {
+			t.Errorf("%s %s = %d %v, want %d with an error", test.method, test.path, code, e, test.code)
+		}
+	}
+}
+
+func TestSubsetAndConvert(t *testing.T) {
+	tests := []struct {
+		path   string
+		font   string
+		glyphs int // glyphs is the number of non-empty glyphs in the response, or -1 to skip the check.
+	}{
+		{"/subset?glyphs=A,B", "open-sans-v15-latin-regular.woff", 3},
+		{"/subset?gids=0-10", "open-sans-v15-latin-regular.woff", 8}, // Glyphs 1 to 3 are empty.
+		{"/convert", "Raleway-v4020-Regular.otf", -1},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest("POST", test.path, bytes.NewReader(readTestFont(t, test.font)))
+		w := httptest.NewRecorder()
+		(&Handler{}).ServeHTTP(w, r)
+		if w.Code != http.StatusOK {
+			t.Errorf("POST %s = %d %s, want %d", test.path, w.Code, w.Body, http.StatusOK)
+			continue
+		}
+
+		font, err := sfnt.StrictParse(bytes.NewReader(w.Body.Bytes()))
+		if err != nil {
+			t.Errorf("POST %s returned an invalid font: %s", test.path, err)
+			continue
+		}
+		if test.glyphs < 0 {
+			continue
+		}
+		glyf, err := font.GlyfTable()
+		if err != nil {
+			t.Fatal(err)
+		}
+		glyphs := 0
+		for _, glyph := range glyf.Glyphs {
+			if len(glyph) > 0 {
+				glyphs++
+			}
+		}
+		if glyphs != test.glyphs {
+			t.Errorf("POST %s kept %d glyphs, want %d", test.path, glyphs, test.glyphs)
+		}
+	}
+
+	var e map[string]string
+	r := httptest.NewRequest("POST", "/subset", bytes.NewReader(readTestFont(t, "open-sans-v15-latin-regular.woff")))
+	if code := serve(t, &Handler{}, r, &e); code != http.StatusUnprocessableEntity {
+		t.Errorf("POST /subset with no glyphs = %d, want %d", code, http.StatusUnprocessableEntity)
+	}
+}
@@ -0,0 +1,76 @@
+package remote
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// HTTPObject is an Object served by a web server that supports Range requests.
+type HTTPObject struct {
+	URL string
+
+	// Client is used to make requests. If it is nil, http.DefaultClient is used.
+	Client *http.Client
+}
+
+func (o *HTTPObject) client() *http.Client {
+	if o.Client == nil {
+		return http.DefaultClient
+	}
+	return o.Client
+}
+
+// Size returns the Content-Length of the object, found with a HEAD request.
+func (o *HTTPObject) Size(ctx context.Context) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, o.URL, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := o.client().Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("HEAD %s: %s", o.URL, resp.Status)
+	}
+	if resp.ContentLength < 0 {
+		return 0, fmt.Errorf("HEAD %s: unknown Content-Length", o.URL)
+	}
+	return resp.ContentLength, nil
+}
+
+// ReadRange fetches part of the object with a Range request.
+func (o *HTTPObject) ReadRange(ctx context.Context, offset, length int64) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, o.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", offset, offset+length-1))
+	resp, err := o.client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+	case http.StatusOK:
+		// The server ignored the Range header and is sending the whole object.
+		if _, err := io.CopyN(ioutil.Discard, resp.Body, offset); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("GET %s: %s", o.URL, resp.Status)
+	}
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(resp.Body, data); err != nil {
+		return nil, fmt.Errorf("GET %s: %s", o.URL, err)
+	}
+	return data, nil
+}
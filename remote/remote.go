@@ -0,0 +1,162 @@
+// Package remote reads fonts stored elsewhere, such as on a web server or in an
+// object store, by fetching only the byte ranges that are needed.
+//
+// This lets an indexer read the 'name' and 'OS/2' tables of a large font without
+// downloading the whole file:
+//
+//	file, err := remote.Open(ctx, &remote.HTTPObject{URL: url})
+//	font, err := sfnt.Parse(file)
+//	name, err := font.NameTable()
+//
+// Other stores can be supported by implementing Object; for example, an Object
+// for S3 would use HeadObject for Size and GetObject with a Range for ReadRange.
+package remote
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// DefaultBlockSize is the smallest range fetched by a File. Parsing a font makes
+// many small reads close together, so rounding them up to whole blocks makes far
+// fewer requests.
+const DefaultBlockSize = 16 << 10
+
+// Object is a remote file that supports reading byte ranges.
+type Object interface {
+	// Size returns the length of the object in bytes.
+	Size(ctx context.Context) (int64, error)
+	// ReadRange returns length bytes of the object, starting at offset.
+	ReadRange(ctx context.Context, offset, length int64) ([]byte, error)
+}
+
+// File reads from an Object in blocks, and keeps the blocks it has read in memory.
+// It satisfies sfnt.File, and is safe for concurrent use.
+type File struct {
+	*io.SectionReader
+	at *blockReader
+}
+
+// Open returns a File for the object. The context is used for all requests made
+// while reading the file, as io.ReaderAt has no way to pass one.
+func Open(ctx context.Context, object Object) (*File, error) {
+	return OpenWithBlockSize(ctx, object, DefaultBlockSize)
+}
+
+// OpenWithBlockSize is like Open, but reads the object in blocks of the given size.
+func OpenWithBlockSize(ctx context.Context, object Object, blockSize int64) (*File, error) {
+	size, err := object.Size(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if blockSize <= 0 {
+		return nil, fmt.Errorf("invalid block size %d", blockSize)
+	}
+
+	at := &blockReader{
+		ctx:       ctx,
+		object:    object,
+		size:      size,
+		blockSize: blockSize,
+		blocks:    make(map[int64][]byte),
+	}
+	return &File{SectionReader: io.NewSectionReader(at, 0, size), at: at}, nil
+}
+
+// Fetched returns the number of bytes that have been fetched from the object.
+func (f *File) Fetched() int64 {
+	f.at.mu.Lock()
+	defer f.at.mu.Unlock()
+	return f.at.fetched
+}
+
+// blockReader is an io.ReaderAt that reads an object in blocks.
+type blockReader struct {
+	ctx       context.Context
+	object    Object
+	size      int64
+	blockSize int64
+
+	mu      sync.Mutex
+	blocks  map[int64][]byte // blocks contains the blocks that have been read, by index.
+	fetched int64
+}
+
+func (r *blockReader) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, fmt.Errorf("invalid offset %d", off)
+	}
+	if off >= r.size {
+		return 0, io.EOF
+	}
+	end := off + int64(len(p))
+	if end > r.size {
+		end = r.size
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	first, last := off/r.blockSize, (end-1)/r.blockSize
+	if err := r.fetch(first, last); err != nil {
+		return 0, err
+	}
+
+	n := 0
+	for i := first; i <= last; i++ {
+		block := r.blocks[i]
+		start := int64(0)
+		if i == first {
+			start = off - i*r.blockSize
+		}
+		n += copy(p[n:], block[start:])
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// fetch reads the blocks from first to last that have not been read yet. Runs of
+// missing blocks are read with a single request. The caller must hold r.mu.
+func (r *blockReader) fetch(first, last int64) error {
+	for i := first; i <= last; i++ {
+		if _, found := r.blocks[i]; found {
+			continue
+		}
+		j := i
+		for j < last {
+			if _, found := r.blocks[j+1]; found {
+				break
+			}
+			j++
+		}
+
+		offset := i * r.blockSize
+		length := (j+1)*r.blockSize - offset
+		if offset+length > r.size {
+			length = r.size - offset
+		}
+		data, err := r.object.ReadRange(r.ctx, offset, length)
+		if err != nil {
+			return err
+		}
+		if int64(len(data)) != length {
+			return fmt.Errorf("reading %d bytes at %d: got %d bytes", length, offset, len(data))
+		}
+		r.fetched += length
+
+		for k := i; k <= j; k++ {
+			start := (k - i) * r.blockSize
+			stop := start + r.blockSize
+			if stop > length {
+				stop = length
+			}
+			r.blocks[k] = data[start:stop]
+		}
+		i = j
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ConradIrwin/font/sfnt"
+)
+
+// memoryObject is an Object stored in memory, that counts the requests made.
+type memoryObject struct {
+	data     []byte
+	requests int
+}
+
+func (o *memoryObject) Size(ctx context.Context) (int64, error) {
+	return int64(len(o.data)), nil
+}
+
+func (o *memoryObject) ReadRange(ctx context.Context, offset, length int64) ([]byte, error) {
+	o.requests++
+	return append([]byte(nil), o.data[offset:offset+length]...), nil
+}
+
+func TestFileReadAt(t *testing.T) {
+	data := make([]byte, 1000)
+	rand.New(rand.NewSource(1)).Read(data)
+	object := &memoryObject{data: data}
+
+	file, err := OpenWithBlockSize(context.Background(), object, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		off, n int
+		err    error
+	}{
+		{0, 10, nil},
+		{60, 10, nil},   // Crosses a block boundary.
+		{5, 300, nil},   // Partly read already.
+		{900, 100, nil}, // The last block is short.
+		{990, 20, io.EOF},
+		{1000, 1, io.EOF},
+	}
+	for _, test := range tests {
+		p := make([]byte, test.n)
+		n, err := file.ReadAt(p, int64(test.off))
+		if err != test.err {
+			t.Errorf("ReadAt(%d, %d) err = %v, want %v", test.off, test.n, err, test.err)
+		}
+		end := test.off + test.n
+		if end > len(data) {
+			end = len(data)
+		}
+		if !bytes.Equal(p[:n], data[test.off:end]) {
+			t.Errorf("ReadAt(%d, %d) returned the wrong data", test.off, test.n)
+		}
+	}
+
+	if object.requests != 4 {
+		t.Errorf("made %d requests, want 4", object.requests)
+	}
+	// Blocks 0 to 4, and blocks 14 and 15 (which ends at 1000).
+	if file.Fetched() != 5*64+104 {
+		t.Errorf("Fetched() = %d, want %d", file.Fetched(), 5*64+104)
+	}
+}
+
+func TestHTTPObject(t *testing.T) {
+	data, err := ioutil.ReadFile(filepath.Join("..", "sfnt", "testdata", "Roboto-BoldItalic.ttf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var requests int32
+	ignoreRanges := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if ignoreRanges {
+			r.Header.Del("Range")
+		}
+		http.ServeContent(w, r, "roboto.ttf", time.Time{}, bytes.NewReader(data))
+	}))
+	defer server.Close()
+
+	for _, ignoreRanges = range []bool{false, true} {
+		atomic.StoreInt32(&requests, 0)
+		file, err := Open(context.Background(), &HTTPObject{URL: server.URL, Client: server.Client()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		font, err := sfnt.Parse(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		name, err := font.NameTable()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if family := name.Lookup(sfnt.NameFontFamily); family != "Roboto" {
+			t.Errorf("Lookup(NameFontFamily) = %q, want Roboto", family)
+		}
+
+		if file.Fetched() >= int64(len(data))/2 {
+			t.Errorf("fetched %d of %d bytes, want less than half", file.Fetched(), len(data))
+		}
+		if n := atomic.LoadInt32(&requests); n > 4 {
+			t.Errorf("made %d requests, want at most 4", n)
+		}
+	}
+}
+
+func TestHTTPObjectErrors(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	if _, err := Open(context.Background(), &HTTPObject{URL: server.URL, Client: server.Client()}); err == nil {
+		t.Errorf("Open(missing) err = nil, want an error")
+	}
+}
@@ -0,0 +1,116 @@
+package sfnt
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// AxisInfo describes a variation axis that is registered in the OpenType
+// specification, or commonly used by fonts.
+// https://docs.microsoft.com/en-us/typography/opentype/spec/dvaraxisreg
+type AxisInfo struct {
+	Tag  Tag
+	Name string // Name is the display name of the axis, for example "Weight".
+
+	// CSSProperty is the CSS property that controls the axis, for example
+	// "font-weight", or "" if the axis can only be set with
+	// font-variation-settings.
+	CSSProperty string
+
+	// Min, Default and Max are the range of values allowed on the axis, and
+	// the usual default.
+	Min, Default, Max float64
+
+	Registered bool // Registered is true for the axes in the OpenType specification.
+}
+
+var axisRegistry = []AxisInfo{
+	{MustNamedTag("ital"), "Italic", "font-style", 0, 0, 1, true},
+	{MustNamedTag("opsz"), "Optical Size", "font-optical-sizing", 5, 14, 1200, true},
+	{MustNamedTag("slnt"), "Slant", "font-style", -90, 0, 90, true},
+	{MustNamedTag("wdth"), "Width", "font-stretch", 25, 100, 200, true},
+	{MustNamedTag("wght"), "Weight", "font-weight", 1, 400, 1000, true},
+
+	{MustNamedTag("CASL"), "Casual", "", 0, 0, 1, false},
+	{MustNamedTag("CRSV"), "Cursive", "", 0, 0.5, 1, false},
+	{MustNamedTag("FILL"), "Fill", "", 0, 0, 1, false},
+	{MustNamedTag("GRAD"), "Grade", "", -1000, 0, 1000, false},
+	{MustNamedTag("MONO"), "Monospace", "", 0, 0, 1, false},
+	{MustNamedTag("SOFT"), "Softness", "", 0, 0, 100, false},
+	{MustNamedTag("WONK"), "Wonky", "", 0, 0, 1, false},
+	{MustNamedTag("XOPQ"), "Thick Stroke", "", -1000, 88, 2000, false},
+	{MustNamedTag("XTRA"), "Counter Width", "", -1000, 400, 2000, false},
+	{MustNamedTag("YOPQ"), "Thin Stroke", "", -1000, 116, 2000, false},
+	{MustNamedTag("YTAS"), "Ascender Height", "", 0, 750, 2000, false},
+	{MustNamedTag("YTDE"), "Descender Depth", "", -1000, -250, 0, false},
+	{MustNamedTag("YTFI"), "Figure Height", "", -1000, 600, 2000, false},
+	{MustNamedTag("YTLC"), "Lowercase Height", "", 0, 500, 2000, false},
+	{MustNamedTag("YTUC"), "Uppercase Height", "", 0, 725, 2000, false},
+}
+
+// LookupAxis returns the description of the axis with the given tag. If the axis
+// is not known, it returns false and a description named after the tag.
+func LookupAxis(tag Tag) (AxisInfo, bool) {
+	for _, info := range axisRegistry {
+		if info.Tag == tag {
+			return info, true
+		}
+	}
+	return AxisInfo{Tag: tag, Name: tag.String()}, false
+}
+
+// RegisteredAxes returns the description of every known axis, with the axes
+// registered in the OpenType specification first.
+func RegisteredAxes() []AxisInfo {
+	return append([]AxisInfo{}, axisRegistry...)
+}
+
+// CSSValue returns the value of CSSProperty that selects the position v on the
+// axis, or "" if there is none (as for Optical Size, which CSS sets from the font
+// size). For example 75 on the Width axis is "75%".
+func (info AxisInfo) CSSValue(v float64) string {
+	switch info.CSSProperty {
+	case "font-weight":
+		return cssNumber(v)
+	case "font-stretch":
+		return cssNumber(v) + "%"
+	case "font-style":
+		if info.Tag == axisItalic {
+			if v >= 1 {
+				return "italic"
+			}
+			return "normal"
+		}
+		// Slant is counter-clockwise, CSS oblique angles are clockwise.
+		if v == 0 {
+			return "normal"
+		}
+		return fmt.Sprintf("oblique %sdeg", cssNumber(-v))
+	}
+	return ""
+}
+
+// CSSRange returns the value of CSSProperty in an @font-face rule for a font
+// that covers the range from min to max on the axis, or "" if there is none.
+// For example 100 to 900 on the Weight axis is "100 900".
+func (info AxisInfo) CSSRange(min, max float64) string {
+	if min == max || info.Tag == axisItalic {
+		return info.CSSValue(min)
+	}
+	switch info.CSSProperty {
+	case "font-weight":
+		return cssNumber(min) + " " + cssNumber(max)
+	case "font-stretch":
+		return cssNumber(min) + "% " + cssNumber(max) + "%"
+	case "font-style":
+		return fmt.Sprintf("oblique %sdeg %sdeg", cssNumber(-max), cssNumber(-min))
+	}
+	return ""
+}
+
+func cssNumber(v float64) string {
+	if v == 0 {
+		v = 0 // avoid printing -0
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,42 @@
+package sfnt
+
+import "testing"
+
+func TestLookupAxis(t *testing.T) {
+	info, found := LookupAxis(MustNamedTag("wdth"))
+	if !found || info.Name != "Width" || info.CSSProperty != "font-stretch" || !info.Registered {
+		t.Errorf("LookupAxis(wdth) = %+v, %v, want Width", info, found)
+	}
+	info, found = LookupAxis(MustNamedTag("GRAD"))
+	if !found || info.Name != "Grade" || info.CSSProperty != "" || info.Registered {
+		t.Errorf("LookupAxis(GRAD) = %+v, %v, want Grade", info, found)
+	}
+	info, found = LookupAxis(MustNamedTag("ZZZZ"))
+	if found || info.Name != "ZZZZ" {
+		t.Errorf("LookupAxis(ZZZZ) = %+v, %v, want ZZZZ, false", info, found)
+	}
+}
+
+func TestAxisInfoCSS(t *testing.T) {
+	for _, test := range []struct {
+		tag             string
+		v, min, max     float64
+		value, rangeCSS string
+	}{
+		{"wght", 700, 100, 900, "700", "100 900"},
+		{"wdth", 75, 75, 100, "75%", "75% 100%"},
+		{"ital", 1, 0, 1, "italic", "normal"},
+		{"slnt", -12, -12, 0, "oblique 12deg", "oblique 0deg 12deg"},
+		{"slnt", 0, 0, 0, "normal", "normal"},
+		{"opsz", 12, 8, 144, "", ""},
+		{"GRAD", 50, -50, 100, "", ""},
+	} {
+		info, _ := LookupAxis(MustNamedTag(test.tag))
+		if got := info.CSSValue(test.v); got != test.value {
+			t.Errorf("%s CSSValue(%v) = %q, want %q", test.tag, test.v, got, test.value)
+		}
+		if got := info.CSSRange(test.min, test.max); got != test.rangeCSS {
+			t.Errorf("%s CSSRange(%v, %v) = %q, want %q", test.tag, test.min, test.max, got, test.rangeCSS)
+		}
+	}
+}
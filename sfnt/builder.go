@@ -0,0 +1,302 @@
+package sfnt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Builder constructs a font with TrueType outlines from scratch. It is mostly
+// useful for generating test fixtures and simple icon fonts.
+//
+//	b := NewBuilder(1000)
+//	gid := b.AddGlyph("A", 600, contours)
+//	b.Map('A', gid)
+//	font, err := b.Font()
+type Builder struct {
+	UnitsPerEm uint16
+	Ascender   int16 // Ascender is the typographic ascender, 80% of the em by default.
+	Descender  int16 // Descender is the typographic descender (normally negative), -20% of the em by default.
+	LineGap    int16 // LineGap is the typographic line gap, 0 by default.
+
+	glyphs  []builderGlyph
+	mapping map[rune]uint16
+	names   map[NameID]string
+}
+
+type builderGlyph struct {
+	name    string
+	advance uint16
+	glyph   *Glyph
+}
+
+// NewBuilder returns a Builder for a font with the given number of units per em.
+// The font starts with a .notdef glyph, drawn as an empty rectangle.
+func NewBuilder(unitsPerEm uint16) *Builder {
+	b := &Builder{
+		UnitsPerEm: unitsPerEm,
+		Ascender:   int16(unitsPerEm * 4 / 5),
+		Descender:  -int16(unitsPerEm / 5),
+		mapping:    make(map[rune]uint16),
+		names:      make(map[NameID]string),
+	}
+
+	width, height := int16(unitsPerEm/2), int16(unitsPerEm*7/10)
+	stroke := int16(unitsPerEm / 20)
+	b.AddGlyph(".notdef", unitsPerEm/2, [][]GlyphPoint{
+		{{0, 0, true}, {0, height, true}, {width, height, true}, {width, 0, true}},
+		{{stroke, stroke, true}, {width - stroke, stroke, true}, {width - stroke, height - stroke, true}, {stroke, height - stroke, true}},
+	})
+	return b
+}
+
+// AddGlyph adds a simple glyph with the given name, advance width and contours,
+// and returns its glyph id. Contours are in font units, and outer contours should
+// be clockwise.
+func (b *Builder) AddGlyph(name string, advance uint16, contours [][]GlyphPoint) uint16 {
+	glyph := &Glyph{Contours: contours}
+	glyph.UpdateBounds()
+	b.glyphs = append(b.glyphs, builderGlyph{name, advance, glyph})
+	return uint16(len(b.glyphs) - 1)
+}
+
+// AddComposite adds a composite glyph made up of the given components,
+// and returns its glyph id. Each component is offset by (Arg1, Arg2).
+func (b *Builder) AddComposite(name string, advance uint16, components []GlyphComponent) uint16 {
+	for i := range components {
+		components[i].Flags |= glyfArgsAreXY
+	}
+	b.glyphs = append(b.glyphs, builderGlyph{name, advance, &Glyph{Components: components}})
+	return uint16(len(b.glyphs) - 1)
+}
+
+// Map maps a character to a glyph.
+func (b *Builder) Map(r rune, gid uint16) {
+	b.mapping[r] = gid
+}
+
+// SetName sets an entry in the name table. If the family name is not set, it
+// defaults to "Untitled", and the other required names are derived from it.
+func (b *Builder) SetName(id NameID, value string) {
+	b.names[id] = value
+}
+
+// Font returns a new font containing the glyphs, mappings and names that have
+// been added to the builder.
+func (b *Builder) Font() (*Font, error) {
+	glyf := &TableGlyf{baseTable: baseTable(TagGlyf)}
+	hmtx := &TableHmtx{baseTable: baseTable(TagHmtx)}
+	maxp := &TableMaxp{baseTable: baseTable(TagMaxp), tableMaxpFields: tableMaxpFields{
+		Version:   maxpVersion1,
+		NumGlyphs: uint16(len(b.glyphs)),
+		MaxZones:  2,
+	}}
+	hhea := &TableHhea{baseTable: baseTable(TagHhea), tableHheaFields: tableHheaFields{
+		Version:        fixed{Major: 1},
+		Ascent:         b.Ascender,
+		Descent:        b.Descender,
+		LineGap:        b.LineGap,
+		CaretSlopeRise: 1,
+	}}
+	head := &TableHead{baseTable: baseTable(TagHead), tableHeadFields: tableHeadFields{
+		VersionNumber: fixed{Major: 1},
+		FontRevision:  fixed{Major: 1},
+		MagicNumber:   0x5F0F3CF5,
+		Flags:         0x0003, // Baseline at y=0, left side bearing point at x=0.
+		UnitsPerEm:    b.UnitsPerEm,
+		FontDirection: 2,
+	}}
+
+	var names []string
+	var totalAdvance, advances int
+	first := true
+	// depths contains the number of levels of components below each glyph.
+	// Components are always added before the glyphs that use them.
+	depths := make([]uint16, len(b.glyphs))
+	for gid, g := range b.glyphs {
+		glyph := g.glyph
+		glyf.Glyphs = append(glyf.Glyphs, glyph.Bytes())
+		if len(glyph.Components) > 0 {
+			// Composite glyphs are stored with the bounds of their resolved outlines.
+			contours, err := glyf.Contours(uint16(gid))
+			if err != nil {
+				return nil, fmt.Errorf("glyph %q: %s", g.name, err)
+			}
+			bounds := Glyph{Contours: contours}
+			bounds.UpdateBounds()
+			glyph.XMin, glyph.YMin, glyph.XMax, glyph.YMax = bounds.XMin, bounds.YMin, bounds.XMax, bounds.YMax
+			glyf.Glyphs[gid] = glyph.Bytes()
+			points := 0
+			for _, contour := range contours {
+				points += len(contour)
+			}
+			maxp.MaxComponentPoints = max16(maxp.MaxComponentPoints, uint16(points))
+			maxp.MaxComponentContours = max16(maxp.MaxComponentContours, uint16(len(contours)))
+			maxp.MaxComponentElements = max16(maxp.MaxComponentElements, uint16(len(glyph.Components)))
+			for _, c := range glyph.Components {
+				depths[gid] = max16(depths[gid], depths[c.GlyphID]+1)
+			}
+			maxp.MaxComponentDepth = max16(maxp.MaxComponentDepth, depths[gid])
+		}
+		names = append(names, g.name)
+
+		hmtx.Metrics = append(hmtx.Metrics, HMetric{AdvanceWidth: g.advance, LeftSideBearing: glyph.XMin})
+		hhea.AdvanceWidthMax = max16(hhea.AdvanceWidthMax, g.advance)
+		if g.advance > 0 {
+			totalAdvance += int(g.advance)
+			advances++
+		}
+
+		maxp.MaxPoints = max16(maxp.MaxPoints, uint16(glyph.NumPoints()))
+		maxp.MaxContours = max16(maxp.MaxContours, uint16(len(glyph.Contours)))
+
+		if glyph.IsEmpty() {
+			continue
+		}
+		rsb := int16(int(g.advance) - int(glyph.XMax))
+		if first || glyph.XMin < hhea.MinLeftSideBearing {
+			hhea.MinLeftSideBearing = glyph.XMin
+		}
+		if first || rsb < hhea.MinRightSideBearing {
+			hhea.MinRightSideBearing = rsb
+		}
+		if first || glyph.XMax > hhea.XMaxExtent {
+			hhea.XMaxExtent = glyph.XMax
+		}
+		if first || glyph.XMin < head.XMin {
+			head.XMin = glyph.XMin
+		}
+		if first || glyph.YMin < head.YMin {
+			head.YMin = glyph.YMin
+		}
+		if first || glyph.XMax > head.XMax {
+			head.XMax = glyph.XMax
+		}
+		if first || glyph.YMax > head.YMax {
+			head.YMax = glyph.YMax
+		}
+		first = false
+	}
+
+	post := &TablePost{baseTable: baseTable(TagPost)}
+	post.UnderlinePosition = b.Descender / 2
+	post.UnderlineThickness = int16(b.UnitsPerEm / 20)
+	if err := post.SetGlyphNames(names); err != nil {
+		return nil, err
+	}
+
+	var avgCharWidth uint16
+	if advances > 0 {
+		avgCharWidth = uint16(totalAdvance / advances)
+	}
+	os2 := b.os2Table(head, avgCharWidth)
+	head.IndexToLocFormat = glyf.IndexToLocFormat()
+	glyf.locaFormat = head.IndexToLocFormat
+	hhea.NumOfLongHorMetrics = int16(hmtx.NumberOfHMetrics())
+
+	name, err := b.nameTable()
+	if err != nil {
+		return nil, err
+	}
+
+	font := New(TypeTrueType)
+	font.AddTable(TagHead, head)
+	font.AddTable(TagHhea, hhea)
+	font.AddTable(TagMaxp, maxp)
+	font.AddTable(TagOS2, os2)
+	font.AddTable(TagHmtx, hmtx)
+	font.AddTable(TagCmap, NewTableCmap(b.mapping))
+	font.AddTable(TagLoca, &TableLoca{baseTable: baseTable(TagLoca), glyf: glyf})
+	font.AddTable(TagGlyf, glyf)
+	font.AddTable(TagName, name)
+	font.AddTable(TagPost, post)
+	return font, nil
+}
+
+// os2Table returns a version 4 'OS/2' table for the font.
+func (b *Builder) os2Table(head *TableHead, avgCharWidth uint16) *TableOS2 {
+	fields := tableOS2Fields{
+		Version:            4,
+		XAvgCharWidth:      avgCharWidth,
+		USWeightClass:      400,
+		USWidthClass:       5,
+		YStrikeoutSize:     int16(b.UnitsPerEm / 20),
+		YStrikeoutPosition: int16(b.UnitsPerEm / 4),
+		AchVendID:          MustNamedTag("NONE"),
+		FsSelection:        0x00C0, // REGULAR and USE_TYPO_METRICS.
+		STypoAscender:      b.Ascender,
+		STypoDescender:     b.Descender,
+		STypoLineGap:       b.LineGap,
+		UsWinAscent:        uint16(max16(uint16(b.Ascender), uint16(head.YMax))),
+		UsWinDescent:       uint16(-min(int(b.Descender), int(head.YMin))),
+		UlCodePageRange1:   1, // Latin 1.
+		UsDefaultChar:      0,
+		UsBreakChar:        ' ',
+		UsMaxContext:       1,
+	}
+
+	first, last := rune(0xFFFF), rune(0)
+	for r := range b.mapping {
+		if r < first {
+			first = r
+		}
+		if r > last {
+			last = r
+		}
+	}
+	if last > 0xFFFF {
+		last = 0xFFFF
+	}
+	if first <= last {
+		fields.FsFirstCharIndex, fields.FsLastCharIndex = uint16(first), uint16(last)
+	}
+
+	return &TableOS2{
+		baseTable:      baseTable(TagOS2),
+		tableOS2Fields: fields,
+	}
+}
+
+// nameTable returns a 'name' table containing the names set on the builder, and
+// defaults for the names that every font should have.
+func (b *Builder) nameTable() (*TableName, error) {
+	names := make(map[NameID]string, len(b.names)+4)
+	for id, value := range b.names {
+		names[id] = value
+	}
+	if names[NameFontFamily] == "" {
+		names[NameFontFamily] = "Untitled"
+	}
+	if names[NameFontSubfamily] == "" {
+		names[NameFontSubfamily] = "Regular"
+	}
+	if names[NameFull] == "" {
+		names[NameFull] = names[NameFontFamily] + " " + names[NameFontSubfamily]
+	}
+	if names[NamePostscript] == "" {
+		names[NamePostscript] = strings.Replace(names[NameFontFamily], " ", "", -1) + "-" + strings.Replace(names[NameFontSubfamily], " ", "", -1)
+	}
+
+	table := NewTableName()
+	for id := NameID(0); id <= NameDarkBackgroundPalette; id++ {
+		if value, found := names[id]; found {
+			if err := table.AddMicrosoftEnglishEntry(id, value); err != nil {
+				return nil, fmt.Errorf("name %d: %s", id, err)
+			}
+		}
+	}
+	return table, nil
+}
+
+func max16(a, b uint16) uint16 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,136 @@
+package sfnt
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestBuilder(t *testing.T) {
+	b := NewBuilder(1000)
+	b.SetName(NameFontFamily, "Test Sans")
+
+	square := []GlyphPoint{{100, 0, true}, {100, 500, true}, {500, 500, true}, {500, 0, true}}
+	sq := b.AddGlyph("square", 600, [][]GlyphPoint{square})
+	b.Map('a', sq)
+	space := b.AddGlyph("space", 250, nil)
+	b.Map(' ', space)
+	b.Map(0x00A0, space)
+	b.Map('\r', space)
+	twice := b.AddComposite("twice", 1200, []GlyphComponent{
+		{GlyphID: sq, Transform: [4]float64{1, 0, 0, 1}},
+		{GlyphID: sq, Arg1: 600, Transform: [4]float64{1, 0, 0, 1}},
+	})
+	b.Map(0x1F600, twice)
+	b.AddComposite("four", 2400, []GlyphComponent{
+		{GlyphID: twice, Transform: [4]float64{1, 0, 0, 1}},
+		{GlyphID: twice, Arg1: 1200, Transform: [4]float64{1, 0, 0, 1}},
+	})
+
+	built, err := b.Font()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if _, err := built.WriteOTF(&buf); err != nil {
+		t.Fatal(err)
+	}
+	font, err := StrictParse(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmap, err := font.CmapTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for r, want := range map[rune]uint16{'a': sq, ' ': space, 0x1F600: twice, 'b': 0} {
+		if gid, _ := cmap.Lookup(r); gid != want {
+			t.Errorf("Lookup(%q) = %d, want %d", r, gid, want)
+		}
+	}
+
+	glyf, err := font.GlyfTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	contours, err := glyf.Contours(sq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(contours, [][]GlyphPoint{square}) {
+		t.Errorf("Contours(square) = %v, want %v", contours, [][]GlyphPoint{square})
+	}
+	composite, err := glyf.Glyph(twice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if composite.XMin != 100 || composite.XMax != 1100 {
+		t.Errorf("Glyph(twice) bounds = %d..%d, want 100..1100", composite.XMin, composite.XMax)
+	}
+
+	maxp, err := font.MaxpTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if maxp.MaxComponentDepth != 2 {
+		t.Errorf("MaxComponentDepth = %d, want 2", maxp.MaxComponentDepth)
+	}
+
+	post, err := font.PostTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names, want := post.GlyphNames(), []string{".notdef", "square", "space", "twice", "four"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("GlyphNames() = %v, want %v", names, want)
+	}
+
+	name, err := font.NameTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := name.Lookup(NamePostscript); got != "TestSans-Regular" {
+		t.Errorf("Lookup(NamePostscript) = %q, want %q", got, "TestSans-Regular")
+	}
+
+	hmtx, err := font.HmtxTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if advance := hmtx.Advance(twice); advance != 1200 {
+		t.Errorf("Advance(twice) = %d, want 1200", advance)
+	}
+
+	problems, err := font.Validate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(problems) != 0 {
+		t.Errorf("Validate() = %v, want no problems", problems)
+	}
+}
+
+func TestNewTableCmap(t *testing.T) {
+	mapping := map[rune]uint16{'A': 1, 'B': 2, 'C': 3, 'a': 7, 0xFFFD: 9, 0x10000: 10, 0x1F600: 11}
+	table, err := parseTableCmap(TagCmap, NewTableCmap(mapping).Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmap := table.(*TableCmap)
+
+	formats := map[uint16]bool{}
+	for _, subtable := range cmap.Subtables {
+		formats[subtable.Format] = true
+	}
+	if !formats[4] || !formats[12] {
+		t.Errorf("subtable formats = %v, want 4 and 12", formats)
+	}
+	for r, want := range mapping {
+		if gid, _ := cmap.Lookup(r); gid != want {
+			t.Errorf("Lookup(%U) = %d, want %d", r, gid, want)
+		}
+	}
+	if gid, _ := cmap.Lookup('D'); gid != 0 {
+		t.Errorf("Lookup('D') = %d, want 0", gid)
+	}
+}
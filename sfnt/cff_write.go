@@ -0,0 +1,227 @@
+package sfnt
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Operators in CFF DICTs that are only written.
+const (
+	cffVersion            = 0
+	cffNotice             = 1
+	cffFullName           = 2
+	cffFamilyName         = 3
+	cffWeight             = 4
+	cffFontBBox           = 5
+	cffBlueValues         = 6
+	cffOtherBlues         = 7
+	cffFamilyBlues        = 8
+	cffFamilyOtherBlues   = 9
+	cffStdHW              = 10
+	cffStdVW              = 11
+	cffIsFixedPitch       = 1201
+	cffItalicAngle        = 1202
+	cffUnderlinePosition  = 1203
+	cffUnderlineThickness = 1204
+	cffFontMatrix         = 1207
+	cffBlueScale          = 1209
+	cffBlueShift          = 1210
+	cffBlueFuzz           = 1211
+	cffStemSnapH          = 1212
+	cffStemSnapV          = 1213
+	cffForceBold          = 1214
+)
+
+// cffFont describes a CFF font with a single Private DICT and no subroutines,
+// which can be serialized with Bytes.
+type cffFont struct {
+	name        string
+	top         cffDictWriter // top contains the Top DICT, except for the offsets.
+	private     cffDictWriter
+	strings     []string
+	glyphNames  []string // glyphNames are the name of each glyph, starting with .notdef.
+	charStrings [][]byte
+}
+
+// sid returns the string id of s, adding it to the String INDEX if needed.
+// Strings are always stored in the font, even if they are one of the standard
+// strings, which is allowed but slightly larger.
+func (f *cffFont) sid(s string) int {
+	for i, existing := range f.strings {
+		if existing == s {
+			return cffStandardStrings + i
+		}
+	}
+	f.strings = append(f.strings, s)
+	return cffStandardStrings + len(f.strings) - 1
+}
+
+// Bytes returns the CFF table data.
+func (f *cffFont) Bytes() []byte {
+	charset := []byte{0} // Format 0 lists the string id of every glyph but .notdef.
+	for _, name := range f.glyphNames[1:] {
+		charset = appendUint16s(charset, uint16(f.sid(name)))
+	}
+	strs := make([][]byte, len(f.strings))
+	for i, s := range f.strings {
+		strs[i] = []byte(s)
+	}
+
+	header := []byte{1, 0, 4, 4}
+	names := cffIndex([][]byte{[]byte(f.name)})
+	stringIndex := cffIndex(strs)
+	globalSubrs := cffIndex(nil)
+	charStrings := cffIndex(f.charStrings)
+	private := f.private.buf
+
+	// The offsets are written with five bytes, so the size of the Top DICT
+	// does not depend on them.
+	top := func(charsetOffset, charStringsOffset, privateOffset int) []byte {
+		dict := cffDictWriter{append([]byte(nil), f.top.buf...)}
+		dict.fixedInt(charsetOffset)
+		dict.op(cffCharset)
+		dict.fixedInt(charStringsOffset)
+		dict.op(cffCharStrings)
+		dict.fixedInt(len(private))
+		dict.fixedInt(privateOffset)
+		dict.op(cffPrivate)
+		return cffIndex([][]byte{dict.buf})
+	}
+	offset := len(header) + len(names) + len(top(0, 0, 0)) + len(stringIndex) + len(globalSubrs)
+	charsetOffset := offset
+	charStringsOffset := charsetOffset + len(charset)
+	privateOffset := charStringsOffset + len(charStrings)
+
+	var buf []byte
+	for _, part := range [][]byte{header, names, top(charsetOffset, charStringsOffset, privateOffset), stringIndex, globalSubrs, charset, charStrings, private} {
+		buf = append(buf, part...)
+	}
+	return buf
+}
+
+// cffIndex returns an INDEX containing the objects.
+func cffIndex(objects [][]byte) []byte {
+	if len(objects) == 0 {
+		return []byte{0, 0}
+	}
+	size := 1
+	for _, object := range objects {
+		size += len(object)
+	}
+	offSize := 1
+	for size >= 1<<(8*offSize) {
+		offSize++
+	}
+
+	buf := appendUint16s(nil, uint16(len(objects)))
+	buf = append(buf, byte(offSize))
+	offset := 1
+	appendOffset := func() {
+		for i := offSize - 1; i >= 0; i-- {
+			buf = append(buf, byte(offset>>(8*i)))
+		}
+	}
+	appendOffset()
+	for _, object := range objects {
+		offset += len(object)
+		appendOffset()
+	}
+	for _, object := range objects {
+		buf = append(buf, object...)
+	}
+	return buf
+}
+
+// appendCFFInt appends the CFF encoding of v. Values that do not fit in a
+// single byte or two bytes use the operator long, which is 28 (for 16 bit
+// values) in charstrings, and 29 (for 32 bit values) in DICTs.
+func appendCFFInt(buf []byte, v int, long byte) []byte {
+	switch {
+	case v >= -107 && v <= 107:
+		return append(buf, byte(v+139))
+	case v >= 108 && v <= 1131:
+		v -= 108
+		return append(buf, byte(v>>8+247), byte(v))
+	case v >= -1131 && v <= -108:
+		v = -v - 108
+		return append(buf, byte(v>>8+251), byte(v))
+	case long == 28:
+		return appendUint16s(append(buf, 28), uint16(int16(v)))
+	}
+	return appendUint32s(append(buf, 29), uint32(int32(v)))
+}
+
+// cffDictWriter builds a CFF DICT.
+type cffDictWriter struct {
+	buf []byte
+}
+
+// number writes an operand, as an integer if it is whole and as a real
+// number otherwise.
+func (d *cffDictWriter) number(v float64) {
+	if v == math.Trunc(v) && math.Abs(v) < 1<<31 {
+		d.buf = appendCFFInt(d.buf, int(v), 29)
+		return
+	}
+
+	// Real numbers are written as a string of nibbles.
+	s := strings.Replace(strconv.FormatFloat(v, 'g', -1, 64), "e+", "e", 1)
+	var nibbles []byte
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c >= '0' && c <= '9':
+			nibbles = append(nibbles, c-'0')
+		case c == '.':
+			nibbles = append(nibbles, 0xa)
+		case c == 'e' && i+1 < len(s) && s[i+1] == '-':
+			nibbles = append(nibbles, 0xc)
+			i++
+		case c == 'e':
+			nibbles = append(nibbles, 0xb)
+		case c == '-':
+			nibbles = append(nibbles, 0xe)
+		}
+	}
+	nibbles = append(nibbles, 0xf)
+	if len(nibbles)%2 != 0 {
+		nibbles = append(nibbles, 0xf)
+	}
+	d.buf = append(d.buf, 30)
+	for i := 0; i < len(nibbles); i += 2 {
+		d.buf = append(d.buf, nibbles[i]<<4|nibbles[i+1])
+	}
+}
+
+// fixedInt writes an integer operand in five bytes.
+func (d *cffDictWriter) fixedInt(v int) {
+	d.buf = appendUint32s(append(d.buf, 29), uint32(int32(v)))
+}
+
+// op writes an operator, where two byte operators are 1200 plus their second byte.
+func (d *cffDictWriter) op(op int) {
+	if op >= 1200 {
+		d.buf = append(d.buf, 12, byte(op-1200))
+	} else {
+		d.buf = append(d.buf, byte(op))
+	}
+}
+
+// entry writes an operator and its operands.
+func (d *cffDictWriter) entry(op int, operands ...float64) {
+	for _, v := range operands {
+		d.number(v)
+	}
+	d.op(op)
+}
+
+// deltaEntry writes an operator whose operands are stored as the difference
+// from the previous operand, as for BlueValues.
+func (d *cffDictWriter) deltaEntry(op int, operands []float64) {
+	previous := 0.0
+	for _, v := range operands {
+		d.number(v - previous)
+		previous = v
+	}
+	d.op(op)
+}
@@ -0,0 +1,229 @@
+package sfnt
+
+// languageExemplars contains the main exemplar characters of each language in
+// CLDR 42, as used by ICU 72. Runs of four or more consecutive code points are
+// written as a range, such as "a-z". Sequences such as the Dutch "ij" are
+// included as their separate characters.
+// https://cldr.unicode.org/translation/core-data/exemplars
+var languageExemplars = []struct {
+	code, name, exemplars string
+}{
+	{"af", "Afrikaans", "a-záâè-ëîïôöû"},
+	{"agq", "Aghem", "a-ik-ps-wyzàâèêìîòôùûāēěīŋōūǎǐǒǔɔɛɨʉʔ̀̂̄̌"},
+	{"ak", "Akan", "abd-ik-pr-uwyɔɛ"},
+	{"am", "Amharic", "ሀ-ሆለ-ቆቈቊ-ቍበ-ኆኈኊ-ኍነ-ኮኰኲ-ኵኸ-ኾወ-ዎዐ-ዖዘ-ዮደ-ዷጀ-ጎጐጒ-ጕጠ-ፆፈ-ፗ"},
+	{"ar", "Arabic", "ء-غف-ْٰ"},
+	{"as", "Assamese", "ঁংঃঅ-ঋএঐও-নপ-যলশ-হ়া-ৃেৈোৌ্ৰৱ"},
+	{"asa", "Asu", "a-pr-wyz"},
+	{"ast", "Asturian", "a-il-vxyzáéíñóúüḥḷ"},
+	{"az", "Azerbaijani", "a-vxyzçöüğİışə"},
+	{"bas", "Basaa", "a-pr-wyzàáâèéêìíîòóôùúûāēěīńŋōūǎǐǒǔǹɓɔɛ̀́̂̄̌᷆᷇"},
+	{"be", "Belarusian", "а-зй-шы-яёіў"},
+	{"bem", "Bemba", "abce-pstuwy"},
+	{"bez", "Bena", "a-wyz"},
+	{"bg", "Bulgarian", "а-ъьюя"},
+	{"bgc", "Haryanvi", "ँःअ-ऋएऐओ-नप-रलव-हा-ृॅेैोौ्"},
+	{"bho", "Bhojpuri", "ँंःक-घच-नप-रलव-ह़ा-ृॅेैोौ्"},
+	{"bm", "Bambara", "a-pr-uwyzŋɔɛɲ"},
+	{"bn", "Bangla", "ঁংঃঅ-ঌএঐও-নপ-রলশ-হ়-ৄেৈো-ৎৗৠ-ৣ৺"},
+	{"bo", "Tibetan", "ཀཁགང-ཇཉ-ཌཎ-དན-བམ-ཛཝ-ཨཪཱིུཷཹ-྄ྀྐྑྒྔ-ྗྙ-ྜྞ-ྡྣ-ྦྨ-ྫྭ-ྸྺྻྼ"},
+	{"br", "Breton", "a-pr-zêñùʼ"},
+	{"brx", "Bodo", "ँंअ-ऊऍएऐऑओ-घच-नप-रलळव-ह़ा-ृॅेैॉोौ्"},
+	{"bs", "Bosnian", "a-pr-vzćčđšž"},
+	{"ca", "Catalan", "a-z·àçèéíïòóúü"},
+	{"ccp", "Chakma", "𑄀-𑄴"},
+	{"ce", "Chechen", "а-яёӏ"},
+	{"ceb", "Cebuano", "abdeghik-pr-uwy"},
+	{"cgg", "Chiga", "a-z"},
+	{"chr", "Cherokee", "ᏸ-ᏼꭰ-ꮿ"},
+	{"ckb", "Central Kurdish", "ئابتج-در-شعغفقلمنوپچڕژڤکگڵھۆیێە"},
+	{"cs", "Czech", "a-záéíóúýčďěňřšťůž"},
+	{"cv", "Chuvash", "а-яёҫӑӗӳ"},
+	{"cy", "Welsh", "a-jl-pr-uwyàáâäè-ïòóôöù-ýÿŵŷẁẃẅỳ"},
+	{"da", "Danish", "a-zåæø"},
+	{"dav", "Taita", "a-pr-wyz"},
+	{"de", "German", "a-zßäöü"},
+	{"dje", "Zarma", "a-uw-zãõŋšžɲẽ"},
+	{"doi", "Dogri", "ँंःअ-ऌएऐओ-नप-रलळव-ह़-ॄेैोौ्ॐ॒॑ॠ-ॣ"},
+	{"dsb", "Lower Sorbian", "a-zóćčěłńŕśšźž"},
+	{"dua", "Duala", "a-gi-pr-uwyáéíóúŋūɓɔɗɛ́"},
+	{"dyo", "Jola-Fonyi", "a-yáéíñóúŋ"},
+	{"dz", "Dzongkha", "ཀཁགང-ཇཉཏཐདན-བམ-ཛཝ-ཤསཧཨིེོུྐྑྒྔྗྙྟྠྡྣ-ྦྨ-ྫྭྱྲླྵྶྷ"},
+	{"ebu", "Embu", "a-zĩũ"},
+	{"ee", "Ewe", "abd-ik-pr-zàáãèéìíòóõùúĩŋũƒɔɖɛɣʋ̀́̃ẽ"},
+	{"el", "Greek", "ΐά-ώ"},
+	{"en", "English", "a-z"},
+	{"eo", "Esperanto", "a-pr-vzĉĝĥĵŝŭ"},
+	{"es", "Spanish", "a-záéíñóúü"},
+	{"et", "Estonian", "a-zäõöüšž"},
+	{"eu", "Basque", "a-zçñ"},
+	{"ewo", "Ewondo", "abd-ik-pr-wyzàáâèéêìíîòóôùúûěńŋǎǐǒǔǹɔəɛ̀́̂̌"},
+	{"fa", "Persian", "ء-ؤئ-غفقل-ؤًٌٍّپچژکگی"},
+	{"ff", "Fula", "a-pr-uwyñŋƴɓɗ"},
+	{"fi", "Finnish", "a-zäåöšž"},
+	{"fil", "Filipino", "a-zñ"},
+	{"fo", "Faroese", "abd-pr-vyáæíðóøúý"},
+	{"fr", "French", "a-zàâæ-ëîïôùûüÿœ"},
+	{"fur", "Friulian", "a-zàâçèêìîòôùû"},
+	{"fy", "Western Frisian", "a-pr-wyzàáâäè-ëíïóôöú-ý́"},
+	{"ga", "Irish", "a-il-pr-uáéíóú"},
+	{"gd", "Scottish Gaelic", "a-il-pr-uàèìòù"},
+	{"gl", "Galician", "a-záéíïñóúü"},
+	{"gsw", "Swiss German", "a-zäöü"},
+	{"gu", "Gujarati", "ઁંઃઅ-ઋઍએઐઑઓ-નપ-રલળવ-હ઼-ૅેૈૉોૌ્ૐૠ"},
+	{"guz", "Gusii", "a-pr-wyz"},
+	{"gv", "Manx", "a-zç"},
+	{"ha", "Hausa", "a-or-uwyzƙƴɓɗʼ"},
+	{"haw", "Hawaiian", "aehik-puwāēīōūʻ"},
+	{"he", "Hebrew", "א-ת"},
+	{"hi", "Hindi", "ँंःअ-ऍएऐऑओ-नप-रलळव-ह़-ृॅेैॉोौ्ॐ"},
+	{"hr", "Croatian", "a-pr-vzćčđšž"},
+	{"hsb", "Upper Sorbian", "a-zóćčěłńřšźž"},
+	{"hu", "Hungarian", "a-pr-vyzáéíóöúüőű"},
+	{"hy", "Armenian", "ա-ֆ"},
+	{"ia", "Interlingua", "a-z"},
+	{"id", "Indonesian", "a-z"},
+	{"ig", "Igbo", "a-pr-wyzṅẹịọụ"},
+	{"ii", "Sichuan Yi", "ꀀ-ꒌ"},
+	{"is", "Icelandic", "abd-pr-vxyáæéíðóöúýþ"},
+	{"it", "Italian", "a-zàèéìòóù"},
+	{"ja", "Japanese", "々ぁ-んゝゞァ-ヶーヽヾ一丁七万-下不与且世丘丙両並中串丸丹主丼久乏乗乙九乞乱乳乾亀了予争事二互五井亜亡交享京亭人仁今介仏仕他付仙代令以仮仰仲件任企伎-休会伝伯伴伸伺似但位-佐体何余作佳併使例侍供依価侮侯侵侶便係促俊俗保信修俳俵俸俺倉個倍倒候借倣値倫倹偉偏停健側偵偶偽傍傑傘備催傲債傷傾僅働像僕僚僧儀億儒償優元-兆先光克免児党入全八公六共兵具典兼内円冊再冒冗写冠冥冬冶冷凄准凍凝凡処凶凸凹出刀刃分切刈刊刑列初判別利到制-刻則削前剖剛剣剤剥副剰割創劇力功加劣助努励労効劾勃勅勇勉動勘務勝募勢勤勧勲勾匂包化北匠匹区医匿十千升午半卑-協南単博占印危即却卵卸厄厘厚原厳去参又及-収叔取受叙口古句叫召可-右号司各合吉同-向君吟否含吸吹呂呈呉告周呪味呼命和咲咽哀品員哲哺唄唆唇唐唯唱唾商問啓善喉喚喜喝喩喪喫営嗅嗣嘆嘱嘲器噴嚇囚四回因団困囲図固国圏園土圧在地坂均坊坑坪垂型垣埋城域執培基埼堀堂堅堆堕堤堪報場塀塁塊塑塔塗塚塞塩填塾境墓増墜墨墳墾壁壇壊壌士壮声壱売変夏夕外多夜夢大天太夫央失奇奈奉奏契奔奥奨奪奮女奴好如妃妄妊妖妙妥妨妬妹妻姉始姓委姫姻姿威娘娠娯婆婚婦婿媒媛嫁嫉嫌嫡嬢子孔字存孝季孤学孫宅宇守安完宗-宝実客宣室宮宰害-家容宿寂寄密富寒寛寝察寡寧審寮寸寺対寿封専射将尉尊尋導小少尚就尺-局居屈届屋展属層履屯山岐岡岩岬岳岸峠峡峰島崇崎崖崩嵐川州巡巣工-巨差己巻巾市布帆希帝帥師席帯帰帳常帽幅幕幣干平年幸幹幻-幾庁広床序底店府度座庫庭庶康庸廃廉廊延廷建弁弄弊式弐弓弔引弟弥弦弧弱張強弾当彙形彩彫彰影役彼往征径待律後徐徒従得御復循微徳徴徹心必忌忍志忘忙応忠快念怒怖思怠急性怨怪恋恐恒恣恥恨恩恭息恵悔悟悠患悦悩悪悲悼情惑惜惧惨惰想愁愉意愚愛感慄慈態慌慎慕慢慣慨慮慰慶憂憎憤憧憩憬憲憶憾懇懐懲懸成我戒戚戦戯戴戸戻房所扇扉手才打払扱扶批承技抄把抑投抗折抜択披抱抵抹押抽担拉拍拐拒拓拘拙招拝拠拡括拭拳拶拷拾持指挑挙挟挨挫振挿捉捕捗捜捨据捻掃授掌排掘掛採探接控推措掲描提揚換握揮援揺損搬搭携搾摂摘摩摯撃撤撮撲擁操擦擬支改攻放政故敏救敗教敢散敬数整敵敷文斉斎斑斗料斜斤斥斬断新方施旅旋族旗既日-早旬旺昆昇明易昔星映春昧昨昭是昼時晩普景晴晶暁暇暑暖暗暦暫暮暴曇曖曜曲更書曹曽替最月有服朕朗望朝期木未-札朱朴机朽杉材村束条来杯東松板析枕林枚果枝枠枢枯架柄某染柔柱柳柵査柿栃栄栓校株核根格栽桁桃案桑桜桟梅梗梨械棄棋棒棚棟森棺椅植椎検業極楷楼楽概構様槽標模権横樹橋機欄欠次欧欲欺款歌歓止正武歩歯歳歴死殉殊残殖殴段殺殻殿毀母毎毒比毛氏民気水氷永氾汁求汎汗汚江池汰決汽沃沈沖沙没沢河沸油治沼沿況泉泊泌法泡波泣泥注泰泳洋洗洞津洪活派流浄浅浜浦浪浮浴海浸消涙涯液涼淑淡淫深混添清渇済渉渋渓減渡渦温測港湖湧湯湾湿満源準溝溶溺滅滋滑滝滞滴漁漂漆漏演漠漢漫漬漸潔潜潟潤潮潰澄激濁濃濫濯瀬火灯灰災炉炊炎炭点為烈無焦然焼煎煙照煩煮熊熟熱燃燥爆爪爵父爽片版牙牛牧物牲特犠犬犯状狂狙狩独狭猛猟猫献猶猿獄獣獲玄率玉王玩珍珠班現球理琴瑠璃璧環璽瓦瓶甘甚生産用田-申男町画界畏畑畔留畜畝略番異畳畿疎疑疫疲疾病症痕痘痛痢痩痴瘍療癒癖発登白百的皆皇皮皿盆益盗盛盟監盤目盲直相盾省眉看県真眠眺眼着睡督睦瞬瞭瞳矛矢知短矯石砂研砕砲破硝硫硬碁碑確磁磨礁礎示礼社祈祉祖祝神祥票祭禁禅禍福秀私秋科秒秘租秩称移程税稚種稲稼稽稿穀穂積穏穫穴究空突窃窒窓窟窮窯立竜章童端競竹笑笛符第筆等筋筒答策箇箋算管箱箸節範築篤簡簿籍籠米粉粋粒粗粘粛粧精糖糧糸系糾紀約紅紋納純紙級紛素紡索紫累細紳紹紺終組経結絞絡給統絵絶絹継続維綱網綻綿緊総緑緒線締編緩緯練緻縁縄縛縦縫縮績繁繊織繕繭繰缶罪置罰署罵罷羅羊美羞群羨義羽翁翌習翻翼老考者耐耕耗耳聖聞聴職肉肌肖肘肝股肢肥肩肪肯育肺胃胆背胎胞胴胸能脂脅脇脈脊脚脱脳腎腐腕腫腰腸腹腺膚膜膝膨膳臆臓臣臨自臭至致臼興舌舎舗舞舟航般舶舷船艇艦良色艶芋芝芯花芳芸芽苗苛若苦英茂茎茨茶草荒荘荷菊菌菓菜華萎落葉著葛葬蒸蓄蓋蔑蔵蔽薄薦薪薫薬藍藤藩藻虎虐虚虜虞虫虹蚊蚕蛇蛍蛮蜂蜜融血衆行術街衛衝衡衣表衰衷袋袖被裁裂装裏裕補裸製裾複褐褒襟襲西要覆覇見規視覚覧親観角解触言訂訃計討訓託記訟訪設許訳訴診証詐詔評詞詠詣試詩詮詰-詳誇誉誌認誓誕誘語誠誤説読誰課調談請論諦諧諭諮諸諾謀謁謄謎謙講謝謡謹識譜警議譲護谷豆豊豚象豪貌貝貞負財貢貧-責貯貴買貸費貼貿賀賂賃賄資賊賓賛賜賞賠賢賦質賭購贈赤赦走赴起超越趣足距跡路跳践踊踏踪蹴躍身車軌軍軒軟転軸軽較載輝輩輪輸轄辛辞辣辱農辺込迅迎近返迫迭述迷追退送逃逆透逐逓途通逝速造連逮週進逸遂遅遇遊運遍過道達違遜遠遡遣適遭遮遵遷選遺避還那邦邪邸郊郎郡部郭郵郷都酌配酎酒酔酢酪酬酵酷酸醒醜醸采釈里-量金釜針釣鈍鈴鉄鉛鉢鉱銀銃銅銘銭鋭鋳鋼錠錦錬錮錯録鍋鍛鍵鎌鎖鎮鏡鐘鑑長門閉開閑間関閣閥閲闇闘阜阪防阻附降限陛院-陥陪陰陳陵陶陸険陽隅隆隊階随隔隙際障隠隣隷隻雄-雇雌雑離難雨雪雰雲零雷電需震霊霜霧露青静非面革靴韓音韻響頂頃項順須預-頓領頬頭頻頼題額顎顔顕願類顧風飛食飢飯飲飼飽飾餅養餌餓館首香馬駄駅駆駐駒騎騒験騰驚骨骸髄高髪鬱鬼魂魅魔魚鮮鯨鳥鳴鶏鶴鹿麓麗麦麺麻黄黒黙鼓鼻齢"},
+	{"jgo", "Ngomba", "a-df-nps-wyzáâíîúûńŋǎǐǔǹɔɛʉ̀́̂̄̈̌ḿẅꞌ"},
+	{"jmc", "Machame", "a-pr-wyz"},
+	{"jv", "Javanese", "a-eg-pr-uwyâåèéêìòù"},
+	{"ka", "Georgian", "ა-ჰ"},
+	{"kab", "Kabyle", "a-np-uw-zčǧɛɣḍḥṛṣṭẓ"},
+	{"kam", "Kamba", "a-wyzĩũ"},
+	{"kde", "Makonde", "a-z"},
+	{"kea", "Kabuverdianu", "abd-pr-vxyzñ"},
+	{"kgp", "Kaingang", "ae-km-pr-vyáãéóĩũẽỹ"},
+	{"khq", "Koyra Chiini", "a-uw-zãõŋšžɲẽ"},
+	{"ki", "Kikuyu", "a-eg-kmnortuwyĩũ"},
+	{"kk", "Kazakh", "а-яёіғқңүұһәө"},
+	{"kkj", "Kako", "a-pr-wyàáâèéêìíîòóôùúûŋɓɔɗɛ̧̀́̂"},
+	{"kl", "Kalaallisut", "a-zåæø"},
+	{"kln", "Kalenjin", "a-eg-pr-uwy"},
+	{"km", "Khmer", "ក-វស-អឥឦឧឩ-ឳា-់៍័្"},
+	{"kn", "Kannada", "ಂಃಅ-ಌಎಏಐಒ-ನಪ-ಳವ-ಹ಼-ೄೆೇೈೊ-್ೕೖೠೡ೦-೯"},
+	{"ko", "Korean", "가-힣"},
+	{"kok", "Konkani", "ँंःअ-ऍएऐऑओ-नप-रलळव-ह़-ृॅेैॉोौ्ॐ०-९"},
+	{"ks", "Kashmiri", "ؠ-ؤابت-غفقلمنوٲٹپچڈڑژکگںھہۄۆیۍے"},
+	{"ksb", "Shambala", "a-ps-wyz"},
+	{"ksf", "Bafia", "a-pr-wyzáéíóúŋǝɔɛ́"},
+	{"ksh", "Colognian", "a-zßäåæëöüėœů"},
+	{"ku", "Kurdish", "a-zçêîûş"},
+	{"kw", "Cornish", "a-z"},
+	{"ky", "Kyrgyz", "абг-ухчшъыэюяёңүө"},
+	{"lag", "Langi", "a-záéíóúɨʉ"},
+	{"lb", "Luxembourgish", "a-zäéë"},
+	{"lg", "Ganda", "a-gi-pr-wyzŋ"},
+	{"lkt", "Lakota", "abeghik-pstuwyzáéíóúčŋšžǧȟʼ"},
+	{"ln", "Lingala", "a-ik-pr-wyzáâéêíîóôúěǎǐǒɔɛ́̂̌"},
+	{"lo", "Lao", "ກຂຄງຈຊຍດ-ທນ-ຟມຢຣລວສຫອ-ູົຼຽເ-ໄໆ່-ໍໜໝ"},
+	{"lrc", "Northern Luri", "آأؤئابت-غؽفقلمنوٙٛپچژڤکگھۉۊیە"},
+	{"lt", "Lithuanian", "a-pr-vyząčėęįšūųž"},
+	{"lu", "Luba-Katanga", "a-qs-wyzàáèéìíòóùúɔɛ̀́"},
+	{"luo", "Luo", "a-pr-wy"},
+	{"luy", "Luyia", "a-z"},
+	{"lv", "Latvian", "a-pr-vzāčēģīķļņšūž"},
+	{"mai", "Maithili", "ंःक-घच-नप-रलव-ह़ा-ूेैोौ्"},
+	{"mas", "Masai", "a-eg-pr-uwyàáâèéêìíîòóôùúûāēīŋōūɔɛɨʉ́"},
+	{"mer", "Meru", "a-zĩũ"},
+	{"mfe", "Morisyen", "a-pr-z"},
+	{"mg", "Malagasy", "abd-prstvyzàâè-ìîïñô"},
+	{"mgh", "Makhuwa-Meetto", "a-pr-wyz"},
+	{"mgo", "Meta\u02bc", "a-km-pr-uwyzàèìòùŋɔəʼ̀"},
+	{"mi", "M\u0101ori", "aeghikm-prtuwāēīōū"},
+	{"mk", "Macedonian", "а-ик-шѓѕјљњќџ"},
+	{"ml", "Malayalam", "ംഃഅ-ഌഎഏഐഒ-നപ-ഹാ-ൃെേൈൊ-്ൗൠൡൺ-ൿ‌‍"},
+	{"mn", "Mongolian", "а-яёүө"},
+	{"mni", "Manipuri", "ঁংঃঅ-ঋএঐও-নপ-রলশ-হ়া-ৃেৈোৌ্ৱ"},
+	{"mr", "Marathi", "ँंःअ-ऍएऐऑओ-नप-ळव-ह़-ृॅेैॉोौ्ॐ"},
+	{"ms", "Malay", "a-z"},
+	{"mt", "Maltese", "abd-xzàèìòùċġħż"},
+	{"mua", "Mundang", "a-pr-wyzãëõĩŋǝɓɗṽ"},
+	{"my", "Burmese", "က-အဣ-ဧဩ-ဲံ-ဿ၏"},
+	{"mzn", "Mazanderani", "ء-ؤئ-غفقل-ؤًٌٍّپچژکگی"},
+	{"naq", "Nama", "a-ikm-uw-zâîôûǀ-ǃ"},
+	{"nb", "Norwegian Bokm\u00e5l", "a-zàåæéòóôø"},
+	{"nd", "North Ndebele", "a-qs-z"},
+	{"ne", "Nepali", "ँंःअ-ऍएऐऑओ-नप-रलळव-ह़-ृॅेैॉोौ्ॐ"},
+	{"nl", "Dutch", "a-záäéëíïóöúǘ"},
+	{"nmg", "Kwasio", "a-pr-wyáâäéêíîïóôöúûāēěīńŋōŕūǎǐǒǔǝɓɔɛ́̂̄̌"},
+	{"nn", "Norwegian Nynorsk", "a-zàåæéòóôø"},
+	{"nnh", "Ngiemboon", "a-ps-wyzàáâèéêìíòóôùúûÿěńŋǎǒǔɔɛʉʼ̀́̂̌ḿẅ"},
+	{"no", "Norwegian", "a-zàåæéòóôø"},
+	{"nus", "Nuer", "a-zäëïöŋɔɛɣ̱̈"},
+	{"nyn", "Nyankole", "a-z"},
+	{"om", "Oromo", "a-z"},
+	{"or", "Odia", "ଁଂଃଅ-ଋଏଐଓ-ନପ-ରଲଳଵ-ହ଼ା-ୃେୈୋୌ୍ୟୱ"},
+	{"os", "Ossetic", "а-яёӕ"},
+	{"pa", "Punjabi", "ਅ-ਊਏਐਓ-ਨਪ-ਰਲਵਸਹ਼ਾ-ੂੇੈੋੌ੍ੜ੦-ੴ"},
+	{"pcm", "Nigerian Pidgin", "a-pr-wyzáéíóú́ẹọ"},
+	{"pl", "Polish", "a-pr-uwyzóąćęłńśźż"},
+	{"ps", "Pashto", "ء-ؤئ-غفقل-وي-ْٰٔټپځڅچډړږژښکګگڼیۍې"},
+	{"pt", "Portuguese", "a-zà-ãçéêíò-õú"},
+	{"qu", "Quechua", "achik-npqstuwyñʼ"},
+	{"raj", "Rajasthani", "ँंःअक-नप-रलव-हा-ृॅेैोौ्"},
+	{"rm", "Romansh", "a-zàèéìòù"},
+	{"rn", "Rundi", "a-z"},
+	{"ro", "Romanian", "a-zâîășț"},
+	{"rof", "Rombo", "a-pr-wyz"},
+	{"ru", "Russian", "а-яё"},
+	{"rw", "Kinyarwanda", "a-z"},
+	{"rwk", "Rwa", "a-pr-wyz"},
+	{"sa", "Sanskrit", "ँंःअ-ऌएऐओ-नप-रलळव-ह़-ॄेैोौ्ॐ॒॑ॠ-ॣ"},
+	{"sah", "Yakut", "абгди-ухчыьэҕҥүһө"},
+	{"saq", "Samburu", "a-eg-pr-wy"},
+	{"sat", "Santali", "ᱚ-ᱽ"},
+	{"sbp", "Sangu", "a-ps-wy"},
+	{"sc", "Sardinian", "a-jl-pr-vzàèìòù"},
+	{"sd", "Sindhi", "ءآابت-غفقل-ويٺٻٽ-ڀڃڄچڇڊڌڍڏڙڦکڪگڱڳڻھ"},
+	{"se", "Northern Sami", "a-pr-vzáčđŋšŧž"},
+	{"seh", "Sena", "a-zà-ãçéêíò-õú"},
+	{"ses", "Koyraboro Senni", "a-uw-zãõŋšžɲẽ"},
+	{"sg", "Sango", "abd-pr-wyzâäêëîïôöùûü"},
+	{"shi", "Tachelhit", "ⴰⴱⴳⴷⴹⴻⴼⴽⵀⵃⵄⵅⵇⵉⵊⵍⵎⵏⵓ-ⵖⵙ-ⵜⵟⵡⵢⵣⵥⵯ"},
+	{"si", "Sinhala", "ංඃඅ-ඍඑ-ඖක-ඥට-නඳ-රලව-ෆ්ා-ුූෘ-ෟෲ"},
+	{"sk", "Slovak", "a-záäéíóôúýčďĺľňŕšťž"},
+	{"sl", "Slovenian", "a-pr-vzčšž"},
+	{"smn", "Inari Sami", "a-pr-vyzáâäčđŋšž"},
+	{"sn", "Shona", "a-pr-wyz"},
+	{"so", "Somali", "bcdfghj-nq-twxy"},
+	{"sq", "Albanian", "a-vxyzçë"},
+	{"sr", "Serbian", "а-ик-шђј-ћџ"},
+	{"su", "Sundanese", "a-zé"},
+	{"sv", "Swedish", "a-zàäåéö"},
+	{"sw", "Swahili", "a-pr-wyz"},
+	{"ta", "Tamil", "ஃஅ-ஊஎஏஐஒ-கஙசஜஞடணதநனபம-வஷஸஹா-ூெேைொ-்"},
+	{"te", "Telugu", "ఁంఃఅ-ఌఎఏఐఒ-నప-ళవ-హా-ౄెేైొ-్ౕౖౠౡ"},
+	{"teo", "Teso", "a-eg-pr-y"},
+	{"tg", "Tajik", "а-хчшъэюяёғқҳҷӣӯ"},
+	{"th", "Thai", "ก-ฺเ-๎"},
+	{"ti", "Tigrinya", "ሀ-ሆለ-ቆቈቊ-ቍቐ-ቖቘቚ-ቝበ-ኆኈኊ-ኍነ-ኮኰኲ-ኵኸ-ኾዀዂ-ዅወ-ዎዐ-ዖዘ-ዮደ-ዷጀ-ጎጐጒ-ጕጠ-ፗ፟"},
+	{"tk", "Turkmen", "abd-pr-uwyzäçöüýňşž"},
+	{"to", "Tongan", "ae-ik-ps-váéíóúāēīōūʻ"},
+	{"tr", "Turkish", "a-pr-vyzçöüğİış"},
+	{"tt", "Tatar", "а-яёҗңүһәө"},
+	{"twq", "Tasawaq", "a-uw-zãõŋšžɲẽ"},
+	{"tzm", "Central Atlas Tamazight", "a-nq-uw-zɛɣʷḍḥṛṣṭ"},
+	{"ug", "Uyghur", "ئابتجخدر-شغف-يپچژڭگھۆۇۈۋېە"},
+	{"uk", "Ukrainian", "ʼа-щьюяєіїґ"},
+	{"ur", "Urdu", "ءابت-غفقلمنوٹپچڈڑژکگھہیے"},
+	{"uz", "Uzbek", "a-vxyzʻʼ"},
+	{"vai", "Vai", "ꔀ-ꘌꘐꘑꘒꘪꘫ"},
+	{"vi", "Vietnamese", "a-eghik-vxyà-ãèéêìíò-õùúýăđĩũơưạảấầẩẫậắằẳẵặẹẻẽếềểễệỉịọỏốồổỗộớờởỡợụủứừửữựỳỵỷỹ"},
+	{"vun", "Vunjo", "a-pr-wyz"},
+	{"wae", "Walser", "a-záãäéíóõöúüčšũ"},
+	{"wo", "Wolof", "a-gi-uwxyàéëñóŋ"},
+	{"xh", "Xhosa", "a-z"},
+	{"xog", "Soga", "a-z"},
+	{"yav", "Yangben", "a-ik-ps-wyàáâèéìíîòóôùúûāīŋōūǎǒǔɔɛ̀́"},
+	{"yi", "Yiddish", "ִַָּֿׂא-תײ"},
+	{"yo", "Yoruba", "abd-pr-uwyàáèéìíòóùúńǹ̀́̄ḿṣẹọ"},
+	{"yrl", "Nheengatu", "abdegikmnpr-uwxyãĩũẽ"},
+	{"yue", "Cantonese", "一丁七丈-不丑且世丘丙丟並中串丸丹主乃久么之乎乏乖乘乙九也乾亂了予事二于云互五井些亞亡交亥亦亨享京亮人什仁仇今介仍仔他付仙代令以仰仲件任份企伊伍伐休伙伯估伴伸似伽但佈佉位低住佔何余佛作你佩佳使來例供依侯侵便係促俄俊俏俗保俠信修俱俾倉個倍們倒候倚借倫值假偉偏做停健側-偷偽傅傑傘備傢傣傲傳傷傻傾僅像僑僧價儀億儒儘優允元兄充兇先光克免兒兔入內全兩八-兮共兵-典兼冊再冒冠冬冰冷准凋凌凍凝凡凰凱出函刀分切刊列初判別刨利刪刮到制刷刺刻剃則剌前剛剩剪副割創劃劇劉劍力功加助努劫勁勇勉勒動務勝勞勢勤勵勸勾勿包匈化北匯匹區十千升午半卒卓協南博卜卡卯印危即卷卹卻厄厘厚原厭厲去參又及友反叔取受口-另只-叭可台史右司吃各合吉吊同名后吐向吒君吝-吠否吧含吳吵吸吹吾呀呂呆告呢周味呵呼命和咖咦咧咪咬咱哀品哇哈哉哎員哥哦哩哪哭哲唇唉唐唔唬售唯唱唵唷唸商啊問啟啡啤啥啦啪喀喂善喇喊喔喜喝喪喬單喲喵嗎嗚嗨嗯嘆嘉嘗嘛嘴嘻嘿噁噓器噴嚇嚏嚴囉四回因困固圈國圍園圓圖團圜土在圭地圾址均坎坐坑坡坤坦坪垂垃型埃城埔域執培基堂堅堆堡堪報場塊塔塗塞填塵境墅墓增墟墨墮墳壁壇壓壘壞壢壩士壬壯壺壽夏夕外多夜夠夢夥大天太夫央失夷夸夾奇奈奉奎奏契奔套奧奪奮女奴奶她好如妙妝妥妨妮妳妹妻姆姊始姐姑姓委姿威娃娘娛婁婆婚婦媒媽嫌嫩子孔孕字存孝孟季孤孩孫孵學它宅宇守安宋完宏宗-宜客宣室宮害家容宿寂寄寅密富寒寞察寢實-審寫寬寮寵寶寺封射將專尊尋對導小少尖尚尤就尺尼尾-屁居屆屋屍屏屑展屠層屬山岡岩岸峰島峽崇崙崴嵐嶺川州巡工-巨巫差己-巴巷市布希帕帖帚帛帝帥師席帳帶常帽幅幕幟幣幫干平年幸幹幻-幾庇床序底店庚府度座庫庭康庸廈廉廖廟廠廢廣廳延廷建弄式引弗弘弟弦弱張強彈彊彌彎彝彞形彥彩彬彭彰影役彼往征待很律後徐徑徒得從復微徵德徹心必忌忍志忘忙忠忡快念忽怎怒怕怖思怡急性怨怪恆恐恢恥恨恩恭息恰悅悉悔悟悠您悲悶情惑惜惠惡惱想惹愁愈愉意愚愛感慈態慕慘慢慣慧慮慰慶慾憂憊憐憑憲憶憾懂應懨懶懷懼戀戈戊戌成我戒或截戰戲戴戶房所扁扇手才扎打托扣扥扭扮扯批找承技抄把抓投抗折披抬抱抵抹抽拆拉拋拍拏拒拔拖招拜括拳拼拾拿持指按挑挖挪振挺捏捐捕捧捨捲捷掃授掉掌排掛掠採探接控推措掰描提插揚換握揮援揹損搏搖搜搞搬搭搶摀摘摩摸撐撒撕撞撣撥播撲撾撿擁擇擊擋操擎擔據擠擦擬擴擺擾攀攝攤支收改攻放政故效敍敏救敗敘教敝敞敢散敦敬整敵數文斐斑斗料斜斧斯新斷方於施旁旅旋族旗既日旦早旭旺昂昆昇昌明昏易星映春昨昭是時晉晒晚晨普景晴晶智暑暖暗暫暮暴曆曇曉曬曰曲曳更書曼曾替最會月有朋服朔朗望朝期木未-札朱朵杉李材村杖杜束杯杰東松板析林果枝枯架柏某染柔查柬柯柳柴栓校核根格栽桃案桌桑梁梅條梨梯械梵棄棉棋棍棒棕棚森棺椅植椒椰楊楓楚業極概榜榮構槌槍樂樓標樞模樣樹橄橇橋橘橙機橫檀檔檢檬檸櫚櫻欄權欖欠次欣欲欺欽款歉歌歐歡-武歲歷歸死殊残殘殭段殺殼毀毅母每毒比毛毫氏民氣水永汁求汗汝江池污汪汶決汽沃沈沉沒沖沙沫沮河油治沿況泉泊法泡波泣泥注泰泳洋洗洛洞洩洪洲活洽派流浣浦浩浪浮浴海涇消涉涎涮涯液涵涼淇淋淑淚淡淨深混淺清減渡測港游湖湘湯源準溜溝溪溫滄滅滋滑滴滾滿漂漏演漠漢漫漲漸漿潔潘潛潮澡澤澳激濃濕濟濤濫濱瀏灌灣火灰災炎炮炸為烈烏烘烤烹焊焙無焦焰然煙煞照煩煮熊熟熱燃燈燒燙營爆爍爐爛爪爬爭爵父爸爺爽爾牆片版牌牙牛牠牧物牲特牽犀犧犬犯狀狂狐狗狠狡狸狼猛猜猩猴猶猾猿獄獅獎獨獲獸獺獻獾玄率玉王玩玫玲玻珊珍珠珥班現球理琉琪琴瑙瑜瑞瑟瑤瑪瑰環瓜瓢瓦瓶甕甘甚甜生產用田-申男甸界留畢略番畫異當疆疏疑疲疼疾病痕痛痴瘋瘦瘧療癡癸登-百皂的皆皇皮皿盃盆盈益盔盛盜盟盡監盤盥盧目盲直相盼盾省眉看真眠眼眾睏睛睡督瞇瞌瞧瞪瞭矛矣知短石砂砍研砲破硬碎碗碟碧碩碰確碼磁磚磨磯礎礙礫示社祈祕祖祚祛祝神祥票祿禁禍禎福禪禮禱禿秀私秋科秒秘租秤秦移稅程稍種稱稻稿穀穆穌積穩究穹空穿突窄窗窩窮窶立站竟章童端競竹竿笑笛符笨第筆等筋答策筷箏箔算管箭箱節範篇築篷簡簫簽簿籃籌籍籠籤米粉粗粵精糊糕糖糟糥系糾紀約紅紉納紐純紙級紛素索紫紮累細紳紹終組結絕絡給統絲經綜綠維綱網綽綿緊緒線緣編緩緬緯練縛縣縫縮縱總績繁繃繆織繞繡繩繪繳繼續纖缸缺罈罐罕罩罪置罰署罵罷羅羊美羞群義羽翁習翔翰翹翻翼耀老考者而耍耐耗耳耶聊聖聚聞聯聰聲職聽聾肉肌肚股肥肩肯育肺背胎胖胞胡胸能脆脈脖脫腐腓腔腦腰腳腹腿膚膠膽臂臉臘臟臣臥臨自臭至致臺與-舊舌舍舒舞舟航般船艦良色艾芙芝芬芭花芳芽苣若苦英茄茅茫茲茵茶茸草荒荷荼莉莊莎莓莖莫菇菌菜菩華菲萄萊萎萬萵落葉著葛葡葵蒂蒙蒜蒲蒸蒼蓄蓉蓋蓮蔔蔕蔡蔣蔥蔬蕉蕭蕾薄薑薦薩薪薯藉藍藏藝藤藥蘆蘇蘋蘑蘭蘿虎處虛號虧蚊蚓蚯蛇蛋蛙蜂蜜蜥蜴蝙蝟蝠蝦蝶螂螃融螞螢螺蟀蟄蟋蟑蟲蟳蟹蟻蠅蠍蠕蠣蠻血行術街衛衝衡衣表衫袋袍被裁裂裏裕補裝裡裱裹製複褐褲襪襯西要覆見規視親覺覽觀角解觸言訂計訊討訓託記訝訥訪設許訴診註証評詞詢試詩話該詳誇誌認誓誕語誠誤說誰課誼調談請諒論諸諺諾謀謂謎講謝證識譜警譯議護譽讀變讓讚谷豆豈豎豐豔象豪豬豹貌貓貝貞負財貢貨貪貫責貴買費貼賀資賈賓賜賞賢賣賤賦質賭賴賺購賽贈贊贏贛赤赫走起超越趕趙趣趨足跆跌跎跑距跟跡跪路跳踏踢踩蹟蹤躍身躲車軌軍軒軟軸較載輔輕輛輝輩輪輯輸轉轎轟辛辜辣辦辨辭辯-農迅迎近返迦迪迫述迴迷追退送逃逆透逐途這通逛逝速造逢連週進逸逼遇遊運遍過道達違遙遜遠適遭遮遲遷選遺避邀邁還邊邏那邦邪邱郎部郭郵都鄂鄉鄙鄭鄰酉配酒酪酷酸醉醒醜醫醬采釋-量金針釣鈴鉅鉢鉤銀銅銖銘銳銷鋁鋒鋼錄錢錦錨錫錯錶鍊鍋鍵鍾鎊鎖鎮鏈鏡鏢鐘鐡鐵鑑鑿長門閃閉開閏閒間閣閩閱闆闊闍闐關闡阱防阻阿陀附降限院陣除陪陰陳陵-陸陽隆隊階隔際障隨險隱隻雄雅集雉雌雖雙雜雞離難雨雪雲零雷電需震霍霜霧露霸霹靂靈青靖静靜非靠面革靴靼鞋鞭韃韋韓音韻響頁頂項順須頌預頑頓頗領頞頭頸頻顆題額顏願顛類顧顯風颱飄飆飛食飪飯飲飽飾餃餅養餌餐餘餚館餾首香馬駐駕駛駝駱騎騙騷驅驕驗驚骨體高髮鬆鬍鬥鬧鬱鬼魁魂魅魔魚魯魷鮑鮮鯊鯨鱷鳥鳩鳳鳴鴨鴻鵝鵡鶴鷹鸚鹽鹿麗麥麵麻麼黃黎黑默黛點黨鼓鼠鼬鼻齊齋齒齡龍龐龜"},
+	{"zgh", "Standard Moroccan Tamazight", "ⴰⴱⴳⴷⴹⴻⴼⴽⵀⵃⵄⵅⵇⵉⵊⵍⵎⵏⵓ-ⵖⵙ-ⵜⵟⵡⵢⵣⵥⵯ"},
+	{"zh", "Chinese", "一丁七万-与丑专且世丘丙业东丝丢两严丧个中丰串临丸-主丽举乃久么义之-乐乔乖乘乙九也习乡书买乱乾了予争事二于亏云互五井亚些亡交-亨享京亮亲人亿什仁仅仇今介仍从仔他付仙代令以仪们仰仲件价任份仿企伊伍伏伐休众-会伟传伤伦伯估伴伸似伽但位-佑体何余佛作你佤佩佳使例供依侠侦侧侨侬侯侵便促俄俊俗保信俩修俱俾倍倒候倚借倦值倾假偌偏做停健偶偷储催傲傻像僧儒儿允元-兆先光克免兑兔党入全八-兮兰共关-兹养兼兽内冈册再冒写军农冠冬冰冲决况冷准凌减凝几凡凤凭凯凰出击函刀分切刊刑划列-创初判利别到制刷券刺刻剂前剑剧剩剪副割力劝-务劣动-劫励劲劳势勇勉勋勒勤勾勿包匆匈化北匙匹区医十千升午半华协卒卓单卖南博占卡卢卫卯印危即却卷厂厄厅历厉压厌厍厚原去县参又-反发叔取-叙口-另只-叭可台史右叶-叹吃各合吉吊同名后吐向吓吗君吝吟否吧含听启吵吸吹吻吾呀呆呈告呐员呜呢呦周味呵呼命和咖咦咧咨咪咬咯咱哀品哇哈哉响哎哟哥哦哩哪哭哲唉唐唤唬售唯唱唷商啊啡啥啦啪喀喂善喇喊喏喔喜喝喵喷喻嗒嗨嗯嘉嘛嘴嘻嘿器四回因团园困围固国图圆圈土圣在圭地圳场圾址均坎坐坑块坚坛坜坡坤坦坪垂垃型垒埃埋城埔域培基堂堆堕堡堪塑塔塞填境增墨壁壤士壬壮声处备复夏夕外多夜够夥大天太夫央失头夷-夺奇奈奉奋奏契奔奖套奥女奴奶她好如妇妈妖妙妥妨妮妹妻姆姊始姐姑姓委姿威娃娄娘娜娟娱婆婚媒嫁嫌嫩子孔孕字存孙孜孝孟季孤学孩宁它宇守安宋完宏宗-实审-室宪害宴家容宽宾宿寂寄-寇富寒寝寞察寡寨寸对寻导寿封射将尊小少尔尖尘尚尝尤就尺尼尽尾局屁层居屋屏展属屠山岁岂岗岘岚岛岳岸峡峰崇崩崴川州巡工-巨巫差己-巴巷币市布帅师希帐帕帖帝带席帮常帽幅幕干平年并幸幻幼幽广庆床序库应底店庙庚府庞废度座庭康庸廉廖延廷建开异弃弄弊式引弗弘弟张弥弦弯弱弹强归当录彝形彩彬彭彰影彷役彻彼往征径待很律後徐徒得循微徵德心必忆忌忍志忘忙忠忧快念忽怀态怎怒怕怖思怡急性怨怪总恋恐恢恨恩恭息恰恶恼悄悉悔悟悠患您悲情惑惜惠惧惨惯想惹愁愈愉意愚感愧慈慎慕慢慧慰憾懂懒戈戊戌戏-戒或战截戴户房所扁扇手才扎扑打托扣执扩扫-扯批找承技抄把抑抓投抗折抢护报披抬抱抵抹抽担拆拉拍拒拔拖拘招拜拟拥拦拨择括拳拷拼拾拿持指按挑挖挝挡挤挥挪振挺捉捐捕损捡换据捷授掉掌排探接控-措掸描提插握援搜搞搬搭摄摆摊摔摘摩摸撒撞播操擎擦支收改攻放政故效敌敏救教敝敢散敦敬数敲整文斋斐斗料斜斥断斯新方於施旁旅旋族旗无既日-早旭时旺昂昆昌明昏易星映春昨昭是显晃晋晒晓晚晨普景晴晶智暂暑暖暗暮暴曰曲更曹曼曾替最月有朋服朗望朝期木未-札术朱朵机杀杂权杉李材村杜束条来杨杯杰松板极构析林果枝枢枪枫架柏某染柔查柬柯柳柴标栋栏树校样核根格桃框案桌桑档桥梁梅梦梯械梵检棉棋棒棚森椅植椰楚楼概榜模樱檀欠-欣欧欲欺款歉歌止-武歪死殊残段毅母每毒比毕毛毫氏民气氛水永求汇汉汗汝江池污汤汪汶汽沃沈沉沙沟没沧河油治沿泉泊法泛泡波泣泥注泰泳泽洋洗洛洞津洪洲活洽派流浅测济浏浑浓浙浦浩浪浮浴海涅消涉涛涨涯液涵淋淑淘淡深混添清渐渡渣温港渴游湖湾源溜溪滋滑满滥滨滴漂漏演漠漫潘潜潮澎澳激灌火灭灯灰灵灿炉炎炮炸点烂烈烤烦烧热焦然煌煞照煮熊熟燃燕爆爪爬爱爵-爸爽片版牌牙牛牡牢牧物牲牵特牺犯状犹狂狐狗狠独狮狱狼猛猜猪献猴玄率玉王玛玩玫环现玲玻珀珊珍珠班球理琊琪琳琴琼瑙瑜瑞瑟瑰瑶璃瓜瓦瓶甘甚甜生用田-申电男甸画畅界留略番疆疏疑疗疯疲疼疾病痕痛痴癸登白百的皆皇皮盈益监盒盖盘盛盟目直相盼盾省眉看真眠眼着睛睡督瞧矛矣知短石矶码砂砍研破础硕硬确碍碎碗碟碧碰磁磅磨示礼社祖祚祝神祥票祯祸禁禅福离秀私秋种科秒秘租秤秦秩积称移稀程稍税稣稳稿穆究穷穹空穿突窗窝立站竞竟章童端竹笑笔笛符笨第等筋筑答策筹签简算管箭箱篇篮簿籍米类粉粒粗粤粹精糊糕糖糟系素索紧紫累繁红约级纪纯纲纳纵纷纸纽线练组细织终绍经结绕绘给络绝统继绩绪续维绵综绿缅缓编缘缠缩缴缶缸缺罐网罕罗罚罢罪置署羊美羞群羯羽翁翅翔翘翠翰翻翼耀老考者而耍耐耗耳耶聊职联聘聚聪肉肖肚股肤肥肩肯育胁胆背胎胖胜胞胡胶胸能脆脑脱脸腊腐腓腰腹腾腿臂臣自臭至致舌舍舒舞舟航般舰船良色艺艾节芒芝芦芬芭花芳苍苏苗若苦英茂范茨茫茶草荐荒荣药荷莉莎莪莫莱莲获菜菩菲萄萍萤营萧萨落著葛葡蒂蒋蒙蓉蓝蓬蔑蔡薄薪藉藏藤虎虑虫虹虽虾蚁蛇蛋蛙蛮蜂蜜蝶融蟹蠢血行街衡衣补表袋被袭裁裂装裕裤西要覆见观规视览觉角解言誉誓警计订认讨让训-记讲讷许论设访证评识诉词译试诗诚话诞询该详语误说请诸诺读课谁调谅谈谊谋谓谜谢谨谱谷豆象豪貌贝贞负贡-败货-贪购贯贱贴贵贸费贺贼贾资赋赌赏赐赔赖赚赛赞赠赢赤赫走赵起趁超越趋趣足跃跌跑距跟路跳踏踢踩身躲车轨轩转轮软轰轻载较辅辆辈辉辑输辛辞辨辩辰辱边达迁迅过迈迎运近返还这进-迟迦迪迫述迷追退-逃逆选逊透逐递途通逛逝速造逢逸逻逼遇遍道遗遭遮遵避邀邓那邦邪邮邱邻郎郑部郭都鄂酉酋配酒酷酸醉醒采释里-量金针钓钟钢钦钱钻铁铃铜铢铭银铺链销锁锅锋错锡锦键锺镇镜镭长门闪闭问闰闲间闷闹闻阁阅阐阔队阮防-阶阻阿陀附际陆陈降限院除险陪陵陶陷隆随隐隔障难雄雅集雉雨雪雯雳零雷雾需震霍霖露霸霹青靖静非靠面革靼鞋鞑韦韩音页顶项顺须顽顾顿预领颇频颗题额风飘飙飞食餐饭饮饰饱饼馆首香馨马驱驶驻驾验骑骗骚骤骨高鬼魂魅魔鱼鲁鲜鸟鸡鸣鸭鸿鹅鹤鹰鹿麦麻黄黎黑默鼓鼠鼻齐齿龄龙龟"},
+	{"zu", "Zulu", "a-z"},
+}
@@ -0,0 +1,52 @@
+package sfnt
+
+import (
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+)
+
+// legacyEncodings are the character encodings of the CJK subtables on the
+// Microsoft platform that were used before Unicode.
+var legacyEncodings = map[PlatformEncodingID]encoding.Encoding{
+	PlatformEncodingMicrosoftShiftJIS: japanese.ShiftJIS,
+	PlatformEncodingMicrosoftPRC:      simplifiedchinese.GBK,
+	PlatformEncodingMicrosoftBig5:     traditionalchinese.Big5,
+	PlatformEncodingMicrosoftWansung:  korean.EUCKR,
+}
+
+// decodeLegacy returns a copy of the first CJK subtable on the Microsoft
+// platform with its character codes converted to Unicode, or nil if there is
+// no such subtable. Codes of one or two bytes that do not decode to a single
+// character are left out.
+func (t *TableCmap) decodeLegacy() *CmapSubtable {
+	for _, s := range t.Subtables {
+		enc, found := legacyEncodings[s.EncodingID]
+		if s.PlatformID != PlatformMicrosoft || !found || s.Mapping == nil {
+			continue
+		}
+
+		decoder := enc.NewDecoder()
+		decoded := *s
+		decoded.Mapping = make(map[rune]uint16, len(s.Mapping))
+		for c, gid := range s.Mapping {
+			code := []byte{byte(c)}
+			if c > 0xFF {
+				code = []byte{byte(c >> 8), byte(c)}
+			}
+			text, err := decoder.Bytes(code)
+			if err != nil {
+				continue
+			}
+			if r, size := utf8.DecodeRune(text); r != utf8.RuneError && size == len(text) {
+				decoded.Mapping[r] = gid
+			}
+		}
+		return &decoded
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+package sfnt
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Incompatibility describes a glyph that cannot be interpolated between masters.
+type Incompatibility struct {
+	Glyph   string // Glyph is the name of the glyph, or its id if the fonts have no glyph names.
+	Master  int    // Master is the index of the font that differs from the first font.
+	Message string // Message describes the difference.
+}
+
+// String returns a human readable description of the incompatibility.
+func (i Incompatibility) String() string {
+	return fmt.Sprintf("glyph %s in master %d: %s", i.Glyph, i.Master, i.Message)
+}
+
+// CheckCompatibility checks that the glyphs in each of the masters of a variable
+// font can be interpolated, by comparing each master with the first. Glyphs are
+// matched by name when all the masters have glyph names, and by id otherwise.
+//
+// Corresponding simple glyphs must have the same number of contours, with the same
+// number of points and the same on- and off-curve points in each. Corresponding
+// composite glyphs must have the same components, in the same order.
+func CheckCompatibility(masters []*Font) ([]Incompatibility, error) {
+	if len(masters) < 2 {
+		return nil, fmt.Errorf("at least two masters are needed to check compatibility")
+	}
+
+	glyfs := make([]*TableGlyf, len(masters))
+	names := make([][]string, len(masters))
+	byName := true
+	for i, font := range masters {
+		glyf, err := font.GlyfTable()
+		if err != nil {
+			return nil, fmt.Errorf("master %d: %s", i, err)
+		}
+		glyfs[i] = glyf
+
+		if font.HasTable(TagPost) {
+			post, err := font.PostTable()
+			if err != nil {
+				return nil, fmt.Errorf("master %d: %s", i, err)
+			}
+			names[i] = post.GlyphNames()
+		}
+		if len(names[i]) != glyf.NumGlyphs() {
+			byName = false
+		}
+	}
+
+	// glyphKeys returns the identifier of each glyph in a master, mapped to its id.
+	glyphKeys := func(master int) map[string]uint16 {
+		keys := make(map[string]uint16, glyfs[master].NumGlyphs())
+		for gid := 0; gid < glyfs[master].NumGlyphs(); gid++ {
+			key := fmt.Sprint(gid)
+			if byName {
+				key = names[master][gid]
+			}
+			if _, found := keys[key]; !found {
+				keys[key] = uint16(gid)
+			}
+		}
+		return keys
+	}
+	// glyphKey returns the identifier for a glyph id in a master.
+	glyphKey := func(master int, gid uint16) string {
+		if byName && int(gid) < len(names[master]) {
+			return names[master][gid]
+		}
+		return fmt.Sprint(gid)
+	}
+
+	base := glyphKeys(0)
+	keys := make([]string, 0, len(base))
+	for key := range base {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return base[keys[i]] < base[keys[j]] })
+
+	var problems []Incompatibility
+	for m := 1; m < len(masters); m++ {
+		other := glyphKeys(m)
+		for _, key := range keys {
+			gid, found := other[key]
+			if !found {
+				problems = append(problems, Incompatibility{key, m, "glyph is missing"})
+				continue
+			}
+
+			a, err := glyfs[0].Glyph(base[key])
+			if err != nil {
+				return nil, fmt.Errorf("master 0: %s", err)
+			}
+			b, err := glyfs[m].Glyph(gid)
+			if err != nil {
+				return nil, fmt.Errorf("master %d: %s", m, err)
+			}
+
+			keyA := func(gid uint16) string { return glyphKey(0, gid) }
+			keyB := func(gid uint16) string { return glyphKey(m, gid) }
+			for _, message := range compareGlyphStructure(a, b, keyA, keyB) {
+				problems = append(problems, Incompatibility{key, m, message})
+			}
+		}
+		for key := range other {
+			if _, found := base[key]; !found {
+				problems = append(problems, Incompatibility{key, m, "glyph is not in master 0"})
+			}
+		}
+	}
+	return problems, nil
+}
+
+// compareGlyphStructure returns a message for each way in which glyph b cannot be
+// interpolated with glyph a. keyA and keyB return the identifier of the glyphs
+// referred to by the components of a and b respectively.
+func compareGlyphStructure(a, b *Glyph, keyA, keyB func(gid uint16) string) []string {
+	switch {
+	case a.IsEmpty() != b.IsEmpty():
+		return []string{"glyph is empty in only one master"}
+	case len(a.Components) > 0 != (len(b.Components) > 0):
+		return []string{"glyph is composite in only one master"}
+	}
+
+	if len(a.Components) > 0 {
+		if len(a.Components) != len(b.Components) {
+			return []string{fmt.Sprintf("has %d components, want %d", len(b.Components), len(a.Components))}
+		}
+		var messages []string
+		for i := range a.Components {
+			if want, got := keyA(a.Components[i].GlyphID), keyB(b.Components[i].GlyphID); want != got {
+				messages = append(messages, fmt.Sprintf("component %d is %s, want %s", i, got, want))
+			}
+		}
+		return messages
+	}
+
+	if len(a.Contours) != len(b.Contours) {
+		return []string{fmt.Sprintf("has %d contours, want %d", len(b.Contours), len(a.Contours))}
+	}
+
+	var messages []string
+	for i := range a.Contours {
+		if len(a.Contours[i]) != len(b.Contours[i]) {
+			messages = append(messages, fmt.Sprintf("contour %d has %d points, want %d", i, len(b.Contours[i]), len(a.Contours[i])))
+			continue
+		}
+		for j := range a.Contours[i] {
+			if a.Contours[i][j].OnCurve != b.Contours[i][j].OnCurve {
+				messages = append(messages, fmt.Sprintf("point %d of contour %d is on-curve in only one master", j, i))
+				break
+			}
+		}
+	}
+
+	// When the contours all have the same points in a different order, or each
+	// contour is closest to a different contour in the other master, it's
+	// likely that the contours have been reordered.
+	if len(messages) > 0 && sameContourSizes(a.Contours, b.Contours) || len(messages) == 0 && !closestContoursMatch(a.Contours, b.Contours) {
+		messages = append(messages, "contours appear to be in a different order")
+	}
+	return messages
+}
+
+// closestContoursMatch returns true if the bounding box of each contour in b is
+// closest to the bounding box of the contour with the same index in a.
+func closestContoursMatch(a, b [][]GlyphPoint) bool {
+	bounds := func(contours [][]GlyphPoint) []Glyph {
+		boxes := make([]Glyph, len(contours))
+		for i, contour := range contours {
+			boxes[i].Contours = [][]GlyphPoint{contour}
+			boxes[i].UpdateBounds()
+		}
+		return boxes
+	}
+	distance := func(g, h Glyph) int {
+		return abs(int(g.XMin)-int(h.XMin)) + abs(int(g.YMin)-int(h.YMin)) + abs(int(g.XMax)-int(h.XMax)) + abs(int(g.YMax)-int(h.YMax))
+	}
+
+	boundsA, boundsB := bounds(a), bounds(b)
+	for i, box := range boundsB {
+		for j, other := range boundsA {
+			if j != i && distance(box, other) < distance(box, boundsA[i]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
+
+// sameContourSizes returns true if a and b have contours of the same sizes, in any order.
+func sameContourSizes(a, b [][]GlyphPoint) bool {
+	counts := make(map[int]int)
+	for _, contour := range a {
+		counts[len(contour)]++
+	}
+	for _, contour := range b {
+		counts[len(contour)]--
+	}
+	for _, count := range counts {
+		if count != 0 {
+			return false
+		}
+	}
+	return true
+}
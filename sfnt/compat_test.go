@@ -0,0 +1,65 @@
+package sfnt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCheckCompatibility(t *testing.T) {
+	a := parseTestFont(t, "open-sans-v15-latin-regular.woff")
+	b := parseTestFont(t, "open-sans-v15-latin-regular.woff")
+
+	problems, err := CheckCompatibility([]*Font{a, b})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(problems) != 0 {
+		t.Fatalf("CheckCompatibility(a, a) = %v, want no problems", problems)
+	}
+
+	glyf, err := b.GlyfTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	gids, err := b.GlyphIDs([]string{"O", "Aacute", "B"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	o, err := glyf.Glyph(gids[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	o.Contours[0], o.Contours[1] = o.Contours[1], o.Contours[0]
+	glyf.SetGlyph(gids[0], o)
+
+	b2, err := glyf.Glyph(gids[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	b2.Contours[0], b2.Contours[1] = b2.Contours[1], b2.Contours[0]
+	b2.Contours[2] = b2.Contours[2][1:]
+	glyf.SetGlyph(gids[2], b2)
+
+	aacute, err := glyf.Glyph(gids[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	aacute.Components = aacute.Components[:1]
+	glyf.SetGlyph(gids[1], aacute)
+
+	problems, err = CheckCompatibility([]*Font{a, b})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Incompatibility{
+		{"B", 1, "contour 0 has 9 points, want 15"},
+		{"B", 1, "contour 1 has 15 points, want 9"},
+		{"B", 1, "contour 2 has 8 points, want 9"},
+		{"O", 1, "contours appear to be in a different order"},
+		{"Aacute", 1, "has 1 components, want 2"},
+	}
+	if !reflect.DeepEqual(problems, want) {
+		t.Errorf("CheckCompatibility() = %v, want %v", problems, want)
+	}
+}
@@ -0,0 +1,79 @@
+package sfnt
+
+import (
+	"fmt"
+	"sort"
+)
+
+// UnicodeBlock is a named range of Unicode code points, such as "Cyrillic".
+type UnicodeBlock struct {
+	Name        string
+	First, Last rune
+}
+
+// Size returns the number of code points in the block.
+func (block UnicodeBlock) Size() int {
+	return int(block.Last-block.First) + 1
+}
+
+// UnicodeBlocks returns every Unicode block, ordered by code point.
+func UnicodeBlocks() []UnicodeBlock {
+	return append([]UnicodeBlock{}, unicodeBlocks...)
+}
+
+// LookupUnicodeBlock returns the block containing r, or false if r is not in a block.
+func LookupUnicodeBlock(r rune) (UnicodeBlock, bool) {
+	i := sort.Search(len(unicodeBlocks), func(i int) bool { return unicodeBlocks[i].Last >= r })
+	if i < len(unicodeBlocks) && unicodeBlocks[i].First <= r {
+		return unicodeBlocks[i], true
+	}
+	return UnicodeBlock{}, false
+}
+
+// BlockCoverage is the number of code points in a Unicode block that a font maps
+// to glyphs.
+type BlockCoverage struct {
+	Block   UnicodeBlock
+	Covered int
+}
+
+// Percent returns the percentage of the code points in the block that are covered.
+func (c BlockCoverage) Percent() float64 {
+	return 100 * float64(c.Covered) / float64(c.Block.Size())
+}
+
+// String returns the coverage in the form "Cyrillic: 243/256".
+func (c BlockCoverage) String() string {
+	return fmt.Sprintf("%s: %d/%d", c.Block.Name, c.Covered, c.Block.Size())
+}
+
+// BlockCoverage returns the coverage of each Unicode block that the font's
+// Unicode 'cmap' subtable maps at least one code point of, ordered by code point.
+// The total for each block includes unassigned code points.
+func (font *Font) BlockCoverage() ([]BlockCoverage, error) {
+	cmap, err := font.CmapTable()
+	if err != nil {
+		return nil, err
+	}
+	unicode := cmap.Unicode()
+	if unicode == nil {
+		return nil, fmt.Errorf("font has no unicode cmap")
+	}
+
+	counts := map[string]int{}
+	for r, gid := range unicode.Mapping {
+		if gid == 0 {
+			continue // mapped to .notdef, so not supported
+		}
+		if block, found := LookupUnicodeBlock(r); found {
+			counts[block.Name]++
+		}
+	}
+	var coverage []BlockCoverage
+	for _, block := range unicodeBlocks {
+		if n := counts[block.Name]; n > 0 {
+			coverage = append(coverage, BlockCoverage{block, n})
+		}
+	}
+	return coverage, nil
+}
@@ -0,0 +1,59 @@
+package sfnt
+
+import "testing"
+
+func TestLookupUnicodeBlock(t *testing.T) {
+	tests := []struct {
+		r     rune
+		want  string
+		found bool
+	}{
+		{'A', "Basic Latin", true},
+		{0x7F, "Basic Latin", true},
+		{0x80, "Latin-1 Supplement", true},
+		{'Ж', "Cyrillic", true},
+		{0x1F600, "Emoticons", true},
+		{0x2FE0, "", false},
+	}
+	for _, test := range tests {
+		got, found := LookupUnicodeBlock(test.r)
+		if found != test.found || got.Name != test.want {
+			t.Errorf("LookupUnicodeBlock(%U) = %q, %v, want %q, %v", test.r, got.Name, found, test.want, test.found)
+		}
+	}
+}
+
+func TestBlockCoverage(t *testing.T) {
+	b := NewBuilder(1000)
+	gid := b.AddGlyph("box", 500, nil)
+	for _, r := range "abcЖЗ" {
+		b.Map(r, gid)
+	}
+	b.Map(0x1F600, gid)
+	b.Map('d', 0)
+	font, err := b.Font()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	coverage, err := font.BlockCoverage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, c := range coverage {
+		got = append(got, c.String())
+	}
+	want := []string{"Basic Latin: 3/128", "Cyrillic: 2/256", "Emoticons: 1/80"}
+	if len(got) != len(want) {
+		t.Fatalf("BlockCoverage() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("BlockCoverage()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+	if p := coverage[1].Percent(); p != 100*2.0/256 {
+		t.Errorf("Percent() = %v, want %v", p, 100*2.0/256)
+	}
+}
@@ -0,0 +1,138 @@
+package sfnt
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FormatVariationSettings returns the value of the CSS font-variation-settings
+// property that selects the given coordinates, for example `"wdth" 75, "wght" 700`.
+// Axes are sorted by tag, and an empty map is "normal".
+func FormatVariationSettings(coords map[Tag]float64) string {
+	if len(coords) == 0 {
+		return "normal"
+	}
+	tags := make([]Tag, 0, len(coords))
+	for tag := range coords {
+		tags = append(tags, tag)
+	}
+	sortTags(tags)
+
+	settings := make([]string, 0, len(coords))
+	for _, tag := range tags {
+		settings = append(settings, fmt.Sprintf("%q %s", tag, cssNumber(coords[tag])))
+	}
+	return strings.Join(settings, ", ")
+}
+
+// ParseVariationSettings parses the value of the CSS font-variation-settings
+// property. If an axis is given more than once, the last value is used.
+func ParseVariationSettings(s string) (map[Tag]float64, error) {
+	coords := map[Tag]float64{}
+	err := parseCSSSettings(s, func(tag Tag, value string) error {
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid value %q for %q", value, tag)
+		}
+		coords[tag] = v
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return coords, nil
+}
+
+// FormatFeatureSettings returns the value of the CSS font-feature-settings
+// property that sets each feature to the given value, for example
+// `"liga" 0, "smcp"`. A value of 1 (on) is left out, features are sorted by tag,
+// and an empty map is "normal".
+func FormatFeatureSettings(features map[Tag]int) string {
+	if len(features) == 0 {
+		return "normal"
+	}
+	tags := make([]Tag, 0, len(features))
+	for tag := range features {
+		tags = append(tags, tag)
+	}
+	sortTags(tags)
+
+	settings := make([]string, 0, len(features))
+	for _, tag := range tags {
+		if v := features[tag]; v != 1 {
+			settings = append(settings, fmt.Sprintf("%q %d", tag, v))
+		} else {
+			settings = append(settings, fmt.Sprintf("%q", tag))
+		}
+	}
+	return strings.Join(settings, ", ")
+}
+
+// ParseFeatureSettings parses the value of the CSS font-feature-settings
+// property. Features without a value, or with the value "on", are set to 1 and
+// features with the value "off" to 0. If a feature is given more than once, the
+// last value is used.
+func ParseFeatureSettings(s string) (map[Tag]int, error) {
+	features := map[Tag]int{}
+	err := parseCSSSettings(s, func(tag Tag, value string) error {
+		switch value {
+		case "", "on":
+			features[tag] = 1
+		case "off":
+			features[tag] = 0
+		default:
+			v, err := strconv.Atoi(value)
+			if err != nil || v < 0 {
+				return fmt.Errorf("invalid value %q for %q", value, tag)
+			}
+			features[tag] = v
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return features, nil
+}
+
+// parseCSSSettings calls add with the tag and value of each comma separated
+// setting in s, where each tag is a quoted string of 4 printable ASCII
+// characters. The value is "" if a setting does not have one.
+func parseCSSSettings(s string, add func(tag Tag, value string) error) error {
+	s = strings.TrimSpace(s)
+	if s == "normal" {
+		return nil
+	}
+
+	for _, setting := range strings.Split(s, ",") {
+		setting = strings.TrimSpace(setting)
+		if len(setting) < 6 || (setting[0] != '"' && setting[0] != '\'') || setting[5] != setting[0] {
+			return fmt.Errorf("invalid setting %q, want a quoted 4 character tag", setting)
+		}
+		tag, err := NamedTag(setting[1:5])
+		if err != nil {
+			return fmt.Errorf("invalid setting %q: %s", setting, err)
+		}
+		for _, c := range setting[1:5] {
+			if c < 0x20 || c > 0x7E {
+				return fmt.Errorf("invalid setting %q, tags must be printable ASCII", setting)
+			}
+		}
+
+		value := setting[6:]
+		if value != "" && value[0] != ' ' && value[0] != '\t' {
+			return fmt.Errorf("invalid setting %q, want a space after the tag", setting)
+		}
+		if err := add(tag, strings.TrimSpace(value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sortTags sorts tags by their numeric value.
+func sortTags(tags []Tag) {
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Number < tags[j].Number })
+}
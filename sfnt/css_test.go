@@ -0,0 +1,53 @@
+package sfnt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestVariationSettings(t *testing.T) {
+	coords := map[Tag]float64{MustNamedTag("wght"): 700, MustNamedTag("wdth"): 87.5, MustNamedTag("GRAD"): -25}
+	s := FormatVariationSettings(coords)
+	if want := `"GRAD" -25, "wdth" 87.5, "wght" 700`; s != want {
+		t.Errorf("FormatVariationSettings() = %q, want %q", s, want)
+	}
+	if got, err := ParseVariationSettings(s); err != nil || !reflect.DeepEqual(got, coords) {
+		t.Errorf("ParseVariationSettings(%q) = %v, %v, want %v", s, got, err, coords)
+	}
+
+	if s := FormatVariationSettings(nil); s != "normal" {
+		t.Errorf("FormatVariationSettings(nil) = %q, want normal", s)
+	}
+	if got, err := ParseVariationSettings(" 'wght'  400 , \"wght\" 500"); err != nil || got[MustNamedTag("wght")] != 500 {
+		t.Errorf("ParseVariationSettings() = %v, %v, want wght 500", got, err)
+	}
+	for _, s := range []string{`"wght"`, `wght 400`, `"wgh" 400`, `"wght"400`, `"wght" bold`, `"wght' 400`} {
+		if _, err := ParseVariationSettings(s); err == nil {
+			t.Errorf("ParseVariationSettings(%q) err = nil, want an error", s)
+		}
+	}
+}
+
+func TestFeatureSettings(t *testing.T) {
+	features := map[Tag]int{MustNamedTag("liga"): 0, MustNamedTag("smcp"): 1, MustNamedTag("salt"): 3}
+	s := FormatFeatureSettings(features)
+	if want := `"liga" 0, "salt" 3, "smcp"`; s != want {
+		t.Errorf("FormatFeatureSettings() = %q, want %q", s, want)
+	}
+	if got, err := ParseFeatureSettings(s); err != nil || !reflect.DeepEqual(got, features) {
+		t.Errorf("ParseFeatureSettings(%q) = %v, %v, want %v", s, got, err, features)
+	}
+
+	got, err := ParseFeatureSettings(`"kern" off, "dlig" on`)
+	if want := map[Tag]int{MustNamedTag("kern"): 0, MustNamedTag("dlig"): 1}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseFeatureSettings() = %v, %v, want %v", got, err, want)
+	}
+	if got, err := ParseFeatureSettings("normal"); err != nil || len(got) != 0 {
+		t.Errorf("ParseFeatureSettings(normal) = %v, %v, want an empty map", got, err)
+	}
+	for _, s := range []string{`"liga" -1`, `"liga" yes`, `liga`, `"liga",`} {
+		if _, err := ParseFeatureSettings(s); err == nil {
+			t.Errorf("ParseFeatureSettings(%q) err = nil, want an error", s)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package sfnt
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// InstanceDifference describes a way in which the default instance of a variable
+// font differs from a static font.
+type InstanceDifference struct {
+	Glyph   string // Glyph is the name or id of the glyph, or "" for font-wide metrics.
+	Message string // Message describes the difference.
+}
+
+// String returns a human readable description of the difference.
+func (d InstanceDifference) String() string {
+	if d.Glyph == "" {
+		return d.Message
+	}
+	return fmt.Sprintf("glyph %s: %s", d.Glyph, d.Message)
+}
+
+// CompareDefaultInstance checks that the default instance of a variable font
+// matches a static font, as when a family of static fonts is replaced by a
+// variable font. Glyphs are matched by name when both fonts have glyph names,
+// and by id otherwise.
+//
+// The fonts must have the same units per em, and the outlines (compared as by
+// CompareOutlines), advance widths and the vertical metrics in 'hhea' and 'OS/2'
+// must be within tolerance font units.
+func CompareDefaultInstance(variable, static *Font, tolerance float64) ([]InstanceDifference, error) {
+	if !variable.HasTable(TagFvar) {
+		return nil, fmt.Errorf("the first font has no variation axes")
+	}
+	differences, err := compareFontMetrics(variable, static, tolerance)
+	if err != nil {
+		return nil, err
+	}
+
+	glyfA, err := variable.GlyfTable()
+	if err != nil {
+		return nil, err
+	}
+	glyfB, err := static.GlyfTable()
+	if err != nil {
+		return nil, err
+	}
+	hmtxA, err := variable.HmtxTable()
+	if err != nil {
+		return nil, err
+	}
+	hmtxB, err := static.HmtxTable()
+	if err != nil {
+		return nil, err
+	}
+	namesA, err := allGlyphNames(variable, glyfA.NumGlyphs())
+	if err != nil {
+		return nil, err
+	}
+	namesB, err := allGlyphNames(static, glyfB.NumGlyphs())
+	if err != nil {
+		return nil, err
+	}
+	byName := namesA != nil && namesB != nil
+	key := func(names []string, gid uint16) string {
+		if byName {
+			return names[gid]
+		}
+		return fmt.Sprint(gid)
+	}
+
+	idsB := map[string]uint16{}
+	for gid := glyfB.NumGlyphs() - 1; gid >= 0; gid-- {
+		idsB[key(namesB, uint16(gid))] = uint16(gid)
+	}
+	matched := map[string]bool{}
+	for gid := 0; gid < glyfA.NumGlyphs(); gid++ {
+		a := uint16(gid)
+		name := key(namesA, a)
+		b, found := idsB[name]
+		if !found {
+			differences = append(differences, InstanceDifference{name, "glyph is not in the static font"})
+			continue
+		}
+		matched[name] = true
+
+		contoursA, err := glyfA.Contours(a)
+		if err != nil {
+			return nil, err
+		}
+		contoursB, err := glyfB.Contours(b)
+		if err != nil {
+			return nil, err
+		}
+		if distance := outlineDistance(contoursA, contoursB); distance > tolerance {
+			differences = append(differences, InstanceDifference{name, fmt.Sprintf("outlines differ by %.4g units", distance)})
+		}
+		if advanceA, advanceB := hmtxA.Advance(a), hmtxB.Advance(b); math.Abs(float64(advanceA)-float64(advanceB)) > tolerance {
+			differences = append(differences, InstanceDifference{name, fmt.Sprintf("advance width is %d, but %d in the static font", advanceA, advanceB)})
+		}
+	}
+
+	var missing []string
+	for name := range idsB {
+		if !matched[name] {
+			missing = append(missing, name)
+		}
+	}
+	sort.Slice(missing, func(i, j int) bool { return idsB[missing[i]] < idsB[missing[j]] })
+	for _, name := range missing {
+		differences = append(differences, InstanceDifference{name, "glyph is not in the variable font"})
+	}
+	return differences, nil
+}
+
+// compareFontMetrics returns the differences between the units per em and the
+// vertical metrics of two fonts.
+func compareFontMetrics(a, b *Font, tolerance float64) ([]InstanceDifference, error) {
+	headA, err := a.HeadTable()
+	if err != nil {
+		return nil, err
+	}
+	headB, err := b.HeadTable()
+	if err != nil {
+		return nil, err
+	}
+	if headA.UnitsPerEm != headB.UnitsPerEm {
+		return []InstanceDifference{{"", fmt.Sprintf("units per em is %d, but %d in the static font", headA.UnitsPerEm, headB.UnitsPerEm)}}, nil
+	}
+
+	type metric struct {
+		name string
+		a, b int
+	}
+	var metrics []metric
+	if a.HasTable(TagHhea) && b.HasTable(TagHhea) {
+		hheaA, err := a.HheaTable()
+		if err != nil {
+			return nil, err
+		}
+		hheaB, err := b.HheaTable()
+		if err != nil {
+			return nil, err
+		}
+		metrics = append(metrics,
+			metric{"hhea ascender", int(hheaA.Ascent), int(hheaB.Ascent)},
+			metric{"hhea descender", int(hheaA.Descent), int(hheaB.Descent)},
+			metric{"hhea line gap", int(hheaA.LineGap), int(hheaB.LineGap)},
+		)
+	}
+	if a.HasTable(TagOS2) && b.HasTable(TagOS2) {
+		os2A, err := a.OS2Table()
+		if err != nil {
+			return nil, err
+		}
+		os2B, err := b.OS2Table()
+		if err != nil {
+			return nil, err
+		}
+		metrics = append(metrics,
+			metric{"OS/2 typo ascender", int(os2A.STypoAscender), int(os2B.STypoAscender)},
+			metric{"OS/2 typo descender", int(os2A.STypoDescender), int(os2B.STypoDescender)},
+			metric{"OS/2 typo line gap", int(os2A.STypoLineGap), int(os2B.STypoLineGap)},
+			metric{"OS/2 win ascent", int(os2A.UsWinAscent), int(os2B.UsWinAscent)},
+			metric{"OS/2 win descent", int(os2A.UsWinDescent), int(os2B.UsWinDescent)},
+		)
+		if os2A.Version >= 2 && os2B.Version >= 2 {
+			metrics = append(metrics,
+				metric{"OS/2 x height", int(os2A.SxHeigh), int(os2B.SxHeigh)},
+				metric{"OS/2 cap height", int(os2A.SCapHeight), int(os2B.SCapHeight)},
+			)
+		}
+	}
+
+	var differences []InstanceDifference
+	for _, m := range metrics {
+		if math.Abs(float64(m.a-m.b)) > tolerance {
+			differences = append(differences, InstanceDifference{"", fmt.Sprintf("%s is %d, but %d in the static font", m.name, m.a, m.b)})
+		}
+	}
+	return differences, nil
+}
+
+// allGlyphNames returns the name of each glyph from the 'post' table, or nil if
+// the font does not name all of its glyphs.
+func allGlyphNames(font *Font, numGlyphs int) ([]string, error) {
+	if !font.HasTable(TagPost) {
+		return nil, nil
+	}
+	post, err := font.PostTable()
+	if err != nil {
+		return nil, err
+	}
+	if names := post.GlyphNames(); len(names) == numGlyphs {
+		return names, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,39 @@
+package sfnt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompareDefaultInstance(t *testing.T) {
+	variable, _, _ := variedTestFont(t)
+
+	b := NewBuilder(1000)
+	sq := b.AddGlyph("square", 610, [][]GlyphPoint{square(100, 0, 400)})
+	b.AddComposite("moved", 1200, []GlyphComponent{{GlyphID: sq, Arg1: 1010, Transform: [4]float64{1, 0, 0, 1}}})
+	b.AddGlyph("extra", 600, nil)
+	static, err := b.Font()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	differences, err := CompareDefaultInstance(variable, static, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []InstanceDifference{
+		{"square", "advance width is 600, but 610 in the static font"},
+		{"moved", "outlines differ by 10 units"},
+		{"extra", "glyph is not in the variable font"},
+	}
+	if !reflect.DeepEqual(differences, want) {
+		t.Errorf("CompareDefaultInstance() = %v, want %v", differences, want)
+	}
+
+	if differences, err := CompareDefaultInstance(variable, static, 10); err != nil || len(differences) != 1 {
+		t.Errorf("CompareDefaultInstance() with a tolerance of 10 = %v, %v, want only the extra glyph", differences, err)
+	}
+	if _, err := CompareDefaultInstance(static, variable, 10); err == nil {
+		t.Errorf("CompareDefaultInstance() of a static font err = nil, want error")
+	}
+}
@@ -0,0 +1,117 @@
+package sfnt
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// ErrDfont is returned from Parse if the file is a Macintosh font suitcase,
+// whose fonts can be read with ParseDfont.
+var ErrDfont = errors.New("Macintosh font suitcase (use ParseDfont to read the fonts it contains)")
+
+// dfontHeaderLength is the length of the resource fork header, which is also
+// copied to the start of the resource map.
+const dfontHeaderLength = 16
+
+// DfontResource is an 'sfnt' resource in a Macintosh font suitcase. Data is a
+// complete TrueType or OpenType font, which can be read with Parse or saved to
+// a standalone file.
+type DfontResource struct {
+	ID   int16
+	Name string // Name is the resource name, which is usually the font's full name.
+	Data []byte
+}
+
+// IsDfont returns true if data starts like a Macintosh resource fork, as used
+// by .dfont files and the resource forks of older font suitcases.
+func IsDfont(data []byte) bool {
+	if len(data) < dfontHeaderLength {
+		return false
+	}
+	dataOffset := binary.BigEndian.Uint32(data)
+	mapOffset := binary.BigEndian.Uint32(data[4:])
+	dataLength := binary.BigEndian.Uint32(data[8:])
+	mapLength := binary.BigEndian.Uint32(data[12:])
+	// The data is always written at offset 256, after a header that is
+	// reserved for the system.
+	return dataOffset == 256 && mapLength >= 28 && uint64(mapOffset) >= uint64(dataOffset)+uint64(dataLength)
+}
+
+// ParseDfont returns the 'sfnt' resources in a Macintosh resource fork, in the
+// order they are listed in the resource map. Other resources, such as the
+// 'FOND' resources that group fonts into families, are ignored.
+// https://developer.apple.com/library/archive/documentation/mac/pdf/MoreMacintoshToolbox.pdf
+func ParseDfont(data []byte) ([]DfontResource, error) {
+	if !IsDfont(data) {
+		return nil, fmt.Errorf("not a Macintosh resource fork")
+	}
+	dataOffset := int(binary.BigEndian.Uint32(data))
+	mapOffset := int(binary.BigEndian.Uint32(data[4:]))
+	mapLength := int(binary.BigEndian.Uint32(data[12:]))
+	if mapOffset > len(data) || mapLength > len(data)-mapOffset {
+		return nil, fmt.Errorf("reading resource map: %s", io.ErrUnexpectedEOF)
+	}
+	resourceMap := data[mapOffset : mapOffset+mapLength]
+
+	typeListOffset := int(binary.BigEndian.Uint16(resourceMap[24:]))
+	nameListOffset := int(binary.BigEndian.Uint16(resourceMap[26:]))
+	if typeListOffset+2 > len(resourceMap) {
+		return nil, fmt.Errorf("reading resource types: %s", io.ErrUnexpectedEOF)
+	}
+	typeList := resourceMap[typeListOffset:]
+	numTypes := int(binary.BigEndian.Uint16(typeList)) + 1
+	if numTypes == 0x10000 {
+		// The count is stored minus one, so 0xFFFF means no types.
+		numTypes = 0
+	}
+	if 2+8*numTypes > len(typeList) {
+		return nil, fmt.Errorf("reading resource types: %s", io.ErrUnexpectedEOF)
+	}
+
+	var resources []DfontResource
+	for i := 0; i < numTypes; i++ {
+		entry := typeList[2+8*i:]
+		if NewTag(entry) != MustNamedTag("sfnt") {
+			continue
+		}
+		count := int(binary.BigEndian.Uint16(entry[4:])) + 1
+		refs := int(binary.BigEndian.Uint16(entry[6:]))
+		if refs+12*count > len(typeList) {
+			return nil, fmt.Errorf("reading 'sfnt' resources: %s", io.ErrUnexpectedEOF)
+		}
+
+		for j := 0; j < count; j++ {
+			ref := typeList[refs+12*j:]
+			resource := DfontResource{ID: int16(binary.BigEndian.Uint16(ref))}
+			if nameOffset := int(binary.BigEndian.Uint16(ref[2:])); nameOffset != 0xFFFF {
+				name := nameListOffset + nameOffset
+				if name >= len(resourceMap) || name+1+int(resourceMap[name]) > len(resourceMap) {
+					return nil, fmt.Errorf("reading name of resource %d: %s", resource.ID, io.ErrUnexpectedEOF)
+				}
+				value := resourceMap[name+1 : name+1+int(resourceMap[name])]
+				if decoded, err := charmap.Macintosh.NewDecoder().Bytes(value); err == nil {
+					resource.Name = string(decoded)
+				} else {
+					resource.Name = string(value)
+				}
+			}
+
+			// The top byte holds the resource's attributes.
+			offset := dataOffset + int(binary.BigEndian.Uint32(ref[4:])&0xFFFFFF)
+			if offset+4 > len(data) {
+				return nil, fmt.Errorf("reading resource %d: %s", resource.ID, io.ErrUnexpectedEOF)
+			}
+			length := int(binary.BigEndian.Uint32(data[offset:]))
+			if length > len(data)-offset-4 {
+				return nil, fmt.Errorf("reading resource %d: %s", resource.ID, io.ErrUnexpectedEOF)
+			}
+			resource.Data = data[offset+4 : offset+4+length]
+			resources = append(resources, resource)
+		}
+	}
+	return resources, nil
+}
@@ -0,0 +1,100 @@
+package sfnt
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+// testDfont returns a resource fork containing a 'FOND' resource and an 'sfnt'
+// resource for each of the fonts.
+func testDfont(t *testing.T, names map[string]string, filenames ...string) []byte {
+	type resource struct {
+		id     int16
+		name   string
+		offset int
+	}
+
+	data := []byte{}
+	addData := func(d []byte) int {
+		offset := len(data)
+		data = appendUint32s(data, uint32(len(d)))
+		data = append(data, d...)
+		return offset
+	}
+	fond := []resource{{id: 256, offset: addData([]byte("family"))}}
+	var sfnts []resource
+	for i, filename := range filenames {
+		font, err := ioutil.ReadFile(filepath.Join("testdata", filename))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sfnts = append(sfnts, resource{id: int16(256 + i), name: names[filename], offset: addData(font)})
+	}
+
+	const typeListOffset = 28
+	typeList := appendUint16s(nil, 1) // Two types.
+	typeList = append(typeList, "FOND"...)
+	typeList = appendUint16s(typeList, uint16(len(fond)-1), 2+8*2)
+	typeList = append(typeList, "sfnt"...)
+	typeList = appendUint16s(typeList, uint16(len(sfnts)-1), uint16(2+8*2+12*len(fond)))
+	var nameList []byte
+	for _, r := range append(fond, sfnts...) {
+		nameOffset := uint16(0xFFFF)
+		if r.name != "" {
+			nameOffset = uint16(len(nameList))
+			nameList = append(append(nameList, byte(len(r.name))), r.name...)
+		}
+		typeList = appendUint16s(typeList, uint16(r.id), nameOffset)
+		typeList = appendUint32s(typeList, uint32(r.offset), 0)
+	}
+
+	header := appendUint32s(nil, 256, uint32(256+len(data)), uint32(len(data)), uint32(typeListOffset+len(typeList)+len(nameList)))
+	resourceMap := append(append([]byte(nil), header...), make([]byte, 8)...)
+	resourceMap = appendUint16s(resourceMap, typeListOffset, uint16(typeListOffset+len(typeList)))
+	resourceMap = append(append(resourceMap, typeList...), nameList...)
+
+	fork := append(header, make([]byte, 256-len(header))...)
+	return append(append(fork, data...), resourceMap...)
+}
+
+func TestParseDfont(t *testing.T) {
+	// Resource names are stored in Mac Roman, where 0x8E is é.
+	data := testDfont(t, map[string]string{"Roboto-BoldItalic.ttf": "Roboto Bold Italic", "Raleway-v4020-Regular.otf": "Ral\x8eway"},
+		"Roboto-BoldItalic.ttf", "Raleway-v4020-Regular.otf")
+	names := map[string]string{"Roboto-BoldItalic.ttf": "Roboto Bold Italic", "Raleway-v4020-Regular.otf": "Raléway"}
+
+	if _, err := Parse(bytes.NewReader(data)); err != ErrDfont {
+		t.Errorf("Parse() err = %v, want ErrDfont", err)
+	}
+
+	resources, err := ParseDfont(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resources) != 2 {
+		t.Fatalf("ParseDfont() returned %d resources, want 2", len(resources))
+	}
+	for i, filename := range []string{"Roboto-BoldItalic.ttf", "Raleway-v4020-Regular.otf"} {
+		want, err := ioutil.ReadFile(filepath.Join("testdata", filename))
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := resources[i]
+		if r.ID != int16(256+i) || r.Name != names[filename] || !bytes.Equal(r.Data, want) {
+			t.Errorf("resource %d = {%d %q, %d bytes}, want {%d %q, %d bytes}", i, r.ID, r.Name, len(r.Data), 256+i, names[filename], len(want))
+		}
+		if _, err := StrictParse(bytes.NewReader(r.Data)); err != nil {
+			t.Errorf("StrictParse(resource %d) err = %v", i, err)
+		}
+	}
+
+	// The map comes after the data, so any truncation breaks it.
+	for _, n := range []int{len(data) - 1, len(data) / 2, 300} {
+		truncated := data[:n]
+		if _, err := ParseDfont(truncated); err == nil {
+			t.Errorf("ParseDfont(%d of %d bytes) err = nil, want an error", n, len(data))
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package sfnt
+
+import "fmt"
+
+// familyClass is a class of the IBM font family classification, and the names
+// of its subclasses.
+type familyClass struct {
+	name       string
+	subclasses map[uint8]string
+}
+
+// familyClasses are the classes of the IBM font family classification, used
+// by the sFamilyClass field of the 'OS/2' table. Subclass 0 is always "No
+// Classification" and 15 "Miscellaneous", so they are not listed.
+// https://docs.microsoft.com/en-us/typography/opentype/spec/ibmfc
+var familyClasses = map[uint8]familyClass{
+	0: {"No Classification", nil},
+	1: {"Oldstyle Serifs", map[uint8]string{
+		1: "IBM Rounded Legibility",
+		2: "Garalde",
+		3: "Venetian",
+		4: "Modified Venetian",
+		5: "Dutch Modern",
+		6: "Dutch Traditional",
+		7: "Contemporary",
+		8: "Calligraphic",
+	}},
+	2: {"Transitional Serifs", map[uint8]string{
+		1: "Direct Line",
+		2: "Script",
+	}},
+	3: {"Modern Serifs", map[uint8]string{
+		1: "Italian",
+		2: "Script",
+	}},
+	4: {"Clarendon Serifs", map[uint8]string{
+		1: "Clarendon",
+		2: "Modern",
+		3: "Traditional",
+		4: "Newspaper",
+		5: "Stub Serif",
+		6: "Monotone",
+		7: "Typewriter",
+	}},
+	5: {"Slab Serifs", map[uint8]string{
+		1: "Monotone",
+		2: "Humanist",
+		3: "Geometric",
+		4: "Swiss",
+		5: "Typewriter",
+	}},
+	7: {"Freeform Serifs", map[uint8]string{
+		1: "Modern",
+	}},
+	8: {"Sans Serif", map[uint8]string{
+		1:  "IBM Neo-grotesque Gothic",
+		2:  "Humanist",
+		3:  "Low-x Round Geometric",
+		4:  "High-x Round Geometric",
+		5:  "Neo-grotesque Gothic",
+		6:  "Modified Neo-grotesque Gothic",
+		9:  "Typewriter Gothic",
+		10: "Matrix",
+	}},
+	9: {"Ornamentals", map[uint8]string{
+		1: "Engraver",
+		2: "Black Letter",
+		3: "Decorative",
+		4: "Three Dimensional",
+	}},
+	10: {"Scripts", map[uint8]string{
+		1: "Uncial",
+		2: "Brush Joined",
+		3: "Formal Joined",
+		4: "Monotone Joined",
+		5: "Calligraphic",
+		6: "Brush Unjoined",
+		7: "Formal Unjoined",
+		8: "Monotone Unjoined",
+	}},
+	12: {"Symbolic", map[uint8]string{
+		3: "Mixed Serif",
+		6: "Oldstyle Serif",
+		7: "Neo-grotesque Sans Serif",
+	}},
+}
+
+// FamilyClass returns the names of the class and subclass of the IBM font
+// family classification given by sFamilyClass, for example "Oldstyle Serifs"
+// and "Garalde". Values that are reserved by the specification are named
+// "Reserved" followed by the number.
+func (t *TableOS2) FamilyClass() (class, subclass string) {
+	id, subID := uint8(uint16(t.SFamilyClass)>>8), uint8(t.SFamilyClass)
+	c, found := familyClasses[id]
+	if !found {
+		return fmt.Sprintf("Reserved %d", id), fmt.Sprintf("Reserved %d", subID)
+	}
+	switch subID {
+	case 0:
+		return c.name, "No Classification"
+	case 15:
+		return c.name, "Miscellaneous"
+	}
+	if name, found := c.subclasses[subID]; found {
+		return c.name, name
+	}
+	return c.name, fmt.Sprintf("Reserved %d", subID)
+}
+
+// FamilyClassName returns the class and subclass given by sFamilyClass as one
+// name, for example "Oldstyle Serifs / Garalde". Fonts without a class are
+// "No Classification".
+func (t *TableOS2) FamilyClassName() string {
+	class, subclass := t.FamilyClass()
+	if t.SFamilyClass == 0 {
+		return class
+	}
+	return class + " / " + subclass
+}
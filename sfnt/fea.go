@@ -0,0 +1,382 @@
+package sfnt
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// WriteFea writes the GSUB and GPOS tables as an Adobe feature file, so that the
+// layout rules of a compiled font can be read, or edited and compiled again.
+//
+// Each lookup is written as a named lookup block, followed by a feature block for
+// each feature that lists the lookups it uses in each script and language.
+// Lookups that can't be represented (contextual substitutions and positioning,
+// and attachments) are written as comments.
+//
+// See https://adobe-type-tools.github.io/afdko/OpenTypeFeatureFileSpecification.html
+func (font *Font) WriteFea(w io.Writer) error {
+	names, err := font.glyphNames()
+	if err != nil {
+		return err
+	}
+	fea := &feaWriter{names: names}
+
+	scripts, err := font.Scripts()
+	if err != nil {
+		return err
+	}
+	for _, script := range scripts {
+		for _, lang := range script.Languages {
+			fea.printf("languagesystem %s %s;\n", feaTag(script.Tag), feaTag(lang.Tag))
+		}
+	}
+
+	for _, tag := range []Tag{TagGsub, TagGpos} {
+		if !font.HasTable(tag) {
+			continue
+		}
+		layout, err := font.TableLayout(tag)
+		if err != nil {
+			return err
+		}
+		prefix := strings.ToLower(tag.String())
+		for i, lookup := range layout.Lookups {
+			fea.printf("\nlookup %s_%d {\n", prefix, i)
+			if tag == TagGsub {
+				fea.substitutions(lookup)
+			} else {
+				fea.positions(lookup)
+			}
+			fea.printf("} %s_%d;\n", prefix, i)
+		}
+		fea.features(layout, prefix)
+	}
+
+	_, err = io.WriteString(w, fea.String())
+	return err
+}
+
+// glyphNames returns the name of each glyph from the 'post' table, or names
+// generated from the 'cmap' table if the font has no glyph names.
+func (font *Font) glyphNames() ([]string, error) {
+	maxp, err := font.MaxpTable()
+	if err != nil {
+		return nil, err
+	}
+	numGlyphs := int(maxp.NumGlyphs)
+
+	if font.HasTable(TagPost) {
+		post, err := font.PostTable()
+		if err != nil {
+			return nil, err
+		}
+		if names := post.GlyphNames(); len(names) >= numGlyphs {
+			return names, nil
+		}
+	}
+
+	runes := map[uint16]rune{}
+	if font.HasTable(TagCmap) {
+		cmap, err := font.CmapTable()
+		if err != nil {
+			return nil, err
+		}
+		if unicode := cmap.Unicode(); unicode != nil {
+			for r, gid := range unicode.Mapping {
+				if prev, found := runes[gid]; !found || r < prev {
+					runes[gid] = r
+				}
+			}
+		}
+	}
+	return GenerateGlyphNames(numGlyphs, runes), nil
+}
+
+// feaTag returns the tag as it is written in a feature file, without trailing spaces.
+func feaTag(tag Tag) string {
+	return strings.TrimRight(tag.String(), " ")
+}
+
+type feaWriter struct {
+	strings.Builder
+	names []string
+}
+
+func (fea *feaWriter) printf(format string, args ...interface{}) {
+	fmt.Fprintf(fea, format, args...)
+}
+
+// glyph returns the name of a glyph.
+func (fea *feaWriter) glyph(gid uint16) string {
+	if int(gid) < len(fea.names) {
+		return fea.names[gid]
+	}
+	return fmt.Sprintf("glyph%d", gid)
+}
+
+// glyphs returns the names of the glyphs separated by spaces.
+func (fea *feaWriter) glyphs(gids []uint16) string {
+	names := make([]string, len(gids))
+	for i, gid := range gids {
+		names[i] = fea.glyph(gid)
+	}
+	return strings.Join(names, " ")
+}
+
+// class returns a glyph class containing the glyphs.
+func (fea *feaWriter) class(gids []uint16) string {
+	if len(gids) == 1 {
+		return fea.glyph(gids[0])
+	}
+	return "[" + fea.glyphs(gids) + "]"
+}
+
+// lookupFlag writes the lookupflag statement for a lookup, if it has flags.
+func (fea *feaWriter) lookupFlag(l *Lookup) {
+	var flags []string
+	for i, name := range []string{"RightToLeft", "IgnoreBaseGlyphs", "IgnoreLigatures", "IgnoreMarks"} {
+		if l.Flag&(1<<uint(i)) != 0 {
+			flags = append(flags, name)
+		}
+	}
+	if len(flags) > 0 {
+		fea.printf("\tlookupflag %s;\n", strings.Join(flags, " "))
+	}
+	if l.Flag&0xFF10 != 0 {
+		fea.printf("\t# mark filtering flags 0x%04x are not supported\n", l.Flag&0xFF10)
+	}
+}
+
+// substitutions writes the rules of a GSUB lookup.
+func (fea *feaWriter) substitutions(l *Lookup) {
+	fea.lookupFlag(l)
+	subtables, lookupType := l.extensionSubtables(gsubExtension)
+
+	for i, sub := range subtables {
+		if i > 0 {
+			fea.printf("\tsubtable;\n")
+		}
+		if len(sub) < 6 {
+			continue
+		}
+		format := binary.BigEndian.Uint16(sub)
+		covered := coverageGlyphs(at(sub, int(binary.BigEndian.Uint16(sub[2:]))))
+
+		switch {
+		case lookupType == gsubSingle && format == 1:
+			delta := binary.BigEndian.Uint16(sub[4:])
+			for _, gid := range covered {
+				fea.printf("\tsub %s by %s;\n", fea.glyph(gid), fea.glyph(gid+delta))
+			}
+
+		case lookupType == gsubSingle && format == 2:
+			substitutes := uint16Array(sub, 4)
+			for j, gid := range covered {
+				if j < len(substitutes) {
+					fea.printf("\tsub %s by %s;\n", fea.glyph(gid), fea.glyph(substitutes[j]))
+				}
+			}
+
+		case lookupType == gsubMultiple && format == 1:
+			offsets := uint16Array(sub, 4)
+			for j, gid := range covered {
+				if j >= len(offsets) {
+					break
+				}
+				sequence := fea.glyphs(uint16Array(sub, int(offsets[j])))
+				if sequence == "" {
+					sequence = "NULL"
+				}
+				fea.printf("\tsub %s by %s;\n", fea.glyph(gid), sequence)
+			}
+
+		case lookupType == gsubAlternate && format == 1:
+			offsets := uint16Array(sub, 4)
+			for j, gid := range covered {
+				if j < len(offsets) {
+					fea.printf("\tsub %s from [%s];\n", fea.glyph(gid), fea.glyphs(uint16Array(sub, int(offsets[j]))))
+				}
+			}
+
+		case lookupType == gsubLigature && format == 1:
+			offsets := uint16Array(sub, 4)
+			for j, gid := range covered {
+				if j >= len(offsets) {
+					break
+				}
+				set := at(sub, int(offsets[j]))
+				for _, offset := range uint16Array(set, 0) {
+					ligature := at(set, int(offset))
+					if len(ligature) < 4 {
+						continue
+					}
+					count := int(binary.BigEndian.Uint16(ligature[2:]))
+					if count == 0 || len(ligature) < 4+2*(count-1) {
+						continue
+					}
+					components := []uint16{gid}
+					for k := 1; k < count; k++ {
+						components = append(components, binary.BigEndian.Uint16(ligature[4+2*(k-1):]))
+					}
+					fea.printf("\tsub %s by %s;\n", fea.glyphs(components), fea.glyph(binary.BigEndian.Uint16(ligature)))
+				}
+			}
+
+		default:
+			fea.printf("\t# GSUB lookup type %d format %d is not supported\n", lookupType, format)
+		}
+	}
+}
+
+// positions writes the rules of a GPOS lookup.
+func (fea *feaWriter) positions(l *Lookup) {
+	fea.lookupFlag(l)
+	subtables, lookupType := l.extensionSubtables(gposExtension)
+
+	for i, sub := range subtables {
+		if i > 0 {
+			fea.printf("\tsubtable;\n")
+		}
+		if len(sub) < 8 {
+			continue
+		}
+		format := binary.BigEndian.Uint16(sub)
+		covered := coverageGlyphs(at(sub, int(binary.BigEndian.Uint16(sub[2:]))))
+
+		switch {
+		case lookupType == gposSingleAdjustment && (format == 1 || format == 2):
+			valueFormat := binary.BigEndian.Uint16(sub[4:])
+			for _, gid := range covered {
+				if v, ok := singlePos(sub, gid); ok {
+					fea.printf("\tpos %s %s;\n", fea.glyph(gid), feaValue(v, valueFormat))
+				}
+			}
+
+		case lookupType == gposPairAdjustment && format == 1:
+			fea.pairs(sub, covered)
+
+		case lookupType == gposPairAdjustment && format == 2:
+			fea.classPairs(sub, covered)
+
+		default:
+			fea.printf("\t# GPOS lookup type %d format %d is not supported\n", lookupType, format)
+		}
+	}
+}
+
+// pairs writes the rules of a pair adjustment subtable in format 1.
+func (fea *feaWriter) pairs(sub []byte, covered []uint16) {
+	format1, format2 := binary.BigEndian.Uint16(sub[4:]), binary.BigEndian.Uint16(sub[6:])
+	offsets := uint16Array(sub, 8)
+	size := 2 + valueRecordSize(format1) + valueRecordSize(format2)
+
+	for i, left := range covered {
+		if i >= len(offsets) {
+			break
+		}
+		set := at(sub, int(offsets[i]))
+		if len(set) < 2 {
+			continue
+		}
+		count := int(binary.BigEndian.Uint16(set))
+		for j := 0; j < count && len(set) >= 2+(j+1)*size; j++ {
+			right := binary.BigEndian.Uint16(set[2+j*size:])
+			if v1, v2, _, ok := pairPos(sub, left, right); ok {
+				fea.pair(fea.glyph(left), fea.glyph(right), v1, v2, format1, format2)
+			}
+		}
+	}
+}
+
+// classPairs writes the rules of a pair adjustment subtable in format 2.
+// Pairs with a second glyph in class 0 are not written, as the class contains
+// every glyph that is not in another class.
+func (fea *feaWriter) classPairs(sub []byte, covered []uint16) {
+	if len(sub) < 16 {
+		return
+	}
+	format1, format2 := binary.BigEndian.Uint16(sub[4:]), binary.BigEndian.Uint16(sub[6:])
+	classDef1 := at(sub, int(binary.BigEndian.Uint16(sub[8:])))
+	classes1 := map[uint16][]uint16{}
+	for _, gid := range covered {
+		class := classOf(classDef1, gid)
+		classes1[class] = append(classes1[class], gid)
+	}
+	classes2 := classGlyphs(at(sub, int(binary.BigEndian.Uint16(sub[10:]))))
+
+	for _, class1 := range sortedClasses(classes1) {
+		left := classes1[class1]
+		for _, class2 := range sortedClasses(classes2) {
+			right := classes2[class2]
+			v1, v2, _, ok := pairPos(sub, left[0], right[0])
+			if !ok || (v1 == valueRecord{} && v2 == valueRecord{}) {
+				continue
+			}
+			fea.pair("["+fea.glyphs(left)+"]", "["+fea.glyphs(right)+"]", v1, v2, format1, format2)
+		}
+	}
+}
+
+// pair writes a pair positioning rule.
+func (fea *feaWriter) pair(left, right string, v1, v2 valueRecord, format1, format2 uint16) {
+	if format2 == 0 {
+		fea.printf("\tpos %s %s %s;\n", left, right, feaValue(v1, format1))
+	} else {
+		fea.printf("\tpos %s %s %s %s;\n", left, feaValue(v1, format1), right, feaValue(v2, format2))
+	}
+}
+
+// sortedClasses returns the classes in increasing order.
+func sortedClasses(classes map[uint16][]uint16) []uint16 {
+	var keys []uint16
+	for class := range classes {
+		keys = append(keys, class)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+// feaValue returns a value record as it is written in a feature file.
+func feaValue(v valueRecord, format uint16) string {
+	if format&^valueXAdvance == 0 {
+		return fmt.Sprint(v.XAdvance)
+	}
+	return fmt.Sprintf("<%d %d %d %d>", v.XPlacement, v.YPlacement, v.XAdvance, v.YAdvance)
+}
+
+// features writes a feature block for each feature in the layout table.
+func (fea *feaWriter) features(layout *TableLayout, prefix string) {
+	var tags []Tag
+	for _, f := range layout.Features {
+		tags = appendTag(tags, f.Tag)
+	}
+
+	for _, tag := range tags {
+		fea.printf("\nfeature %s {\n", feaTag(tag))
+		for _, script := range layout.Scripts {
+			langs := script.Languages
+			if script.DefaultLanguage != nil {
+				langs = append([]*LangSys{{Tag: DefaultLanguage, Features: script.DefaultLanguage.Features}}, langs...)
+			}
+			for _, lang := range langs {
+				var lookups []uint16
+				for _, f := range lang.Features {
+					if f.Tag == tag {
+						lookups = append(lookups, f.LookupIndices...)
+					}
+				}
+				if len(lookups) == 0 {
+					continue
+				}
+				fea.printf("\tscript %s;\n\tlanguage %s;\n", feaTag(script.Tag), feaTag(lang.Tag))
+				for _, i := range lookups {
+					fea.printf("\t\tlookup %s_%d;\n", prefix, i)
+				}
+			}
+		}
+		fea.printf("} %s;\n", feaTag(tag))
+	}
+}
@@ -0,0 +1,841 @@
+package sfnt
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// CompileFea compiles rules written in Adobe feature file syntax into GSUB and
+// GPOS lookups, and adds them to the font's tables. The font's existing features
+// and lookups are kept, and the compiled lookups are applied after them. Glyphs
+// are referred to by the names in the 'post' table (or the names WriteFea uses if
+// the font has none).
+//
+// Only a subset of the syntax is supported:
+//
+//	languagesystem latn dflt;
+//	@round = [o c e];
+//	markClass [acutecomb gravecomb] <anchor 0 500> @TOP;
+//	feature liga { sub f i by fi; } liga;
+//	feature kern { pos T @round -80; } kern;
+//	feature mark { pos base [a e] <anchor 250 500> mark @TOP; } mark;
+//
+// Every feature is registered for every language system, and if there is no
+// languagesystem statement, DFLT dflt is used. When mark classes are used and the
+// font has no GDEF table, one is added that classifies the bases, ligatures and marks.
+//
+// See https://adobe-type-tools.github.io/afdko/OpenTypeFeatureFileSpecification.html
+func (font *Font) CompileFea(src string) error {
+	names, err := font.glyphNames()
+	if err != nil {
+		return err
+	}
+	c := &feaCompiler{
+		glyphIDs:    map[string]uint16{},
+		classes:     map[string][]uint16{},
+		markClasses: map[string]map[uint16]feaAnchor{},
+		glyphClass:  map[uint16]uint16{},
+	}
+	for i, name := range names {
+		c.glyphIDs[name] = uint16(i)
+	}
+	if c.tokens, err = feaTokens(src); err != nil {
+		return err
+	}
+	if err := c.compile(); err != nil {
+		return err
+	}
+
+	gsub, gpos, err := c.tables()
+	if err != nil {
+		return err
+	}
+	for tag, compiled := range map[Tag][]byte{TagGsub: gsub, TagGpos: gpos} {
+		if compiled == nil {
+			continue
+		}
+		if font.HasTable(tag) {
+			if compiled, err = font.mergeLayout(tag, compiled); err != nil {
+				return fmt.Errorf("merging %s: %s", tag, err)
+			}
+		}
+		font.SetTable(tag, compiled)
+	}
+	if len(c.markClasses) > 0 && !font.HasTable(TagGdef) {
+		font.SetTable(TagGdef, c.gdef())
+	}
+	return nil
+}
+
+// mergeLayout returns the font's GSUB or GPOS table with the compiled table's
+// scripts, features and lookups added.
+func (font *Font) mergeLayout(tag Tag, compiled []byte) ([]byte, error) {
+	existing, err := font.TableLayout(tag)
+	if err != nil {
+		return nil, err
+	}
+	added, err := parseTableLayout(tag, compiled)
+	if err != nil {
+		return nil, err
+	}
+	return mergeLayouts(tag, existing, added.(*TableLayout))
+}
+
+type feaToken struct {
+	text string
+	line int
+}
+
+// feaTokens splits a feature file into tokens, removing comments.
+func feaTokens(src string) ([]feaToken, error) {
+	var tokens []feaToken
+	line := 1
+	for i := 0; i < len(src); {
+		ch := src[i]
+		switch {
+		case ch == '\n':
+			line++
+			i++
+		case ch == ' ' || ch == '\t' || ch == '\r':
+			i++
+		case ch == '#':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case strings.IndexByte("{}[]<>;=", ch) >= 0:
+			tokens = append(tokens, feaToken{src[i : i+1], line})
+			i++
+		case ch == '@' || ch == '\\' || ch == '.' || ch == '_' || ch == '-' || ch < 0x80 && (unicode.IsLetter(rune(ch)) || unicode.IsDigit(rune(ch))):
+			start := i
+			for i < len(src) && strings.IndexByte(" \t\r\n#{}[]<>;=", src[i]) < 0 {
+				i++
+			}
+			tokens = append(tokens, feaToken{src[start:i], line})
+		default:
+			return nil, fmt.Errorf("line %d: unexpected character %q", line, ch)
+		}
+	}
+	return tokens, nil
+}
+
+type feaAnchor struct {
+	x, y int16
+}
+
+type feaLigature struct {
+	components []uint16
+	glyph      uint16
+}
+
+type feaLookup struct {
+	gpos      bool
+	kind      uint16 // kind is the GSUB or GPOS lookup type.
+	ligatures []feaLigature
+	pairs     map[[2]uint16]int16
+	bases     map[uint16]map[string]feaAnchor // bases contains the anchor of each mark class on each base.
+}
+
+type feaFeature struct {
+	tag     Tag
+	gpos    bool
+	lookups []*feaLookup
+}
+
+type feaCompiler struct {
+	tokens []feaToken
+	pos    int
+
+	glyphIDs    map[string]uint16
+	classes     map[string][]uint16
+	markClasses map[string]map[uint16]feaAnchor
+	glyphClass  map[uint16]uint16 // glyphClass is the GDEF class of each glyph used in a rule.
+
+	languageSystems [][2]Tag
+	gsub, gpos      []*feaFeature
+}
+
+// next returns the next token, or "" at the end of the file.
+func (c *feaCompiler) next() string {
+	if c.pos >= len(c.tokens) {
+		return ""
+	}
+	c.pos++
+	return c.tokens[c.pos-1].text
+}
+
+// peek returns the next token without consuming it.
+func (c *feaCompiler) peek() string {
+	if c.pos >= len(c.tokens) {
+		return ""
+	}
+	return c.tokens[c.pos].text
+}
+
+func (c *feaCompiler) errorf(format string, args ...interface{}) error {
+	line := 0
+	if len(c.tokens) > 0 {
+		line = c.tokens[len(c.tokens)-1].line
+		if c.pos > 0 && c.pos <= len(c.tokens) {
+			line = c.tokens[c.pos-1].line
+		}
+	}
+	return fmt.Errorf("line %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+func (c *feaCompiler) expect(token string) error {
+	if got := c.next(); got != token {
+		return c.errorf("expected %q, got %q", token, got)
+	}
+	return nil
+}
+
+func (c *feaCompiler) tag() (Tag, error) {
+	s := c.next()
+	if len(s) == 0 || len(s) > 4 {
+		return Tag{}, c.errorf("invalid tag %q", s)
+	}
+	return NamedTag(fmt.Sprintf("%-4s", s))
+}
+
+func (c *feaCompiler) number() (int16, error) {
+	s := c.next()
+	n, err := strconv.ParseInt(s, 10, 16)
+	if err != nil {
+		return 0, c.errorf("invalid number %q", s)
+	}
+	return int16(n), nil
+}
+
+// glyph reads a single glyph name.
+func (c *feaCompiler) glyph() (uint16, error) {
+	name := c.next()
+	if gid, found := c.glyphIDs[strings.TrimPrefix(name, "\\")]; found {
+		return gid, nil
+	}
+	return 0, c.errorf("unknown glyph %q", name)
+}
+
+// glyphSet reads a glyph name, a named class, or a list of them in brackets.
+func (c *feaCompiler) glyphSet() ([]uint16, error) {
+	switch token := c.peek(); {
+	case token == "[":
+		c.next()
+		var gids []uint16
+		for c.peek() != "]" {
+			if c.peek() == "" || c.peek() == "[" {
+				return nil, c.errorf("unterminated glyph class")
+			}
+			set, err := c.glyphSet()
+			if err != nil {
+				return nil, err
+			}
+			gids = append(gids, set...)
+		}
+		c.next()
+		return gids, nil
+	case strings.HasPrefix(token, "@"):
+		c.next()
+		class, found := c.classes[token]
+		if !found {
+			return nil, c.errorf("unknown glyph class %q", token)
+		}
+		return class, nil
+	default:
+		gid, err := c.glyph()
+		if err != nil {
+			return nil, err
+		}
+		return []uint16{gid}, nil
+	}
+}
+
+// anchor reads an anchor in the format <anchor x y>.
+func (c *feaCompiler) anchor() (feaAnchor, error) {
+	var a feaAnchor
+	if err := c.expect("<"); err != nil {
+		return a, err
+	}
+	if err := c.expect("anchor"); err != nil {
+		return a, err
+	}
+	var err error
+	if a.x, err = c.number(); err != nil {
+		return a, err
+	}
+	if a.y, err = c.number(); err != nil {
+		return a, err
+	}
+	return a, c.expect(">")
+}
+
+// compile reads the top level statements of the file.
+func (c *feaCompiler) compile() error {
+	for {
+		switch token := c.next(); {
+		case token == "":
+			return nil
+
+		case token == "languagesystem":
+			script, err := c.tag()
+			if err != nil {
+				return err
+			}
+			lang, err := c.tag()
+			if err != nil {
+				return err
+			}
+			c.languageSystems = append(c.languageSystems, [2]Tag{script, lang})
+			if err := c.expect(";"); err != nil {
+				return err
+			}
+
+		case strings.HasPrefix(token, "@"):
+			if err := c.expect("="); err != nil {
+				return err
+			}
+			set, err := c.glyphSet()
+			if err != nil {
+				return err
+			}
+			c.classes[token] = set
+			if err := c.expect(";"); err != nil {
+				return err
+			}
+
+		case token == "markClass":
+			set, err := c.glyphSet()
+			if err != nil {
+				return err
+			}
+			anchor, err := c.anchor()
+			if err != nil {
+				return err
+			}
+			name := c.next()
+			if !strings.HasPrefix(name, "@") {
+				return c.errorf("invalid mark class name %q", name)
+			}
+			if c.markClasses[name] == nil {
+				c.markClasses[name] = map[uint16]feaAnchor{}
+			}
+			for _, gid := range set {
+				c.markClasses[name][gid] = anchor
+				c.glyphClass[gid] = 3
+			}
+			if err := c.expect(";"); err != nil {
+				return err
+			}
+
+		case token == "feature":
+			if err := c.feature(); err != nil {
+				return err
+			}
+
+		default:
+			return c.errorf("unsupported statement %q", token)
+		}
+	}
+}
+
+// feature reads a feature block.
+func (c *feaCompiler) feature() error {
+	tag, err := c.tag()
+	if err != nil {
+		return err
+	}
+	if err := c.expect("{"); err != nil {
+		return err
+	}
+	gsub, gpos := &feaFeature{tag: tag}, &feaFeature{tag: tag, gpos: true}
+
+	for c.peek() != "}" {
+		var err error
+		switch token := c.next(); token {
+		case "sub", "substitute":
+			err = c.substitution(gsub)
+		case "pos", "position":
+			err = c.position(gpos)
+		case "":
+			return c.errorf("unterminated feature %q", feaTag(tag))
+		default:
+			return c.errorf("unsupported rule %q", token)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	c.next()
+	if end, err := c.tag(); err != nil || end != tag {
+		return c.errorf("feature %q is closed by the wrong tag", feaTag(tag))
+	}
+	if err := c.expect(";"); err != nil {
+		return err
+	}
+
+	if len(gsub.lookups) > 0 {
+		c.gsub = append(c.gsub, gsub)
+	}
+	if len(gpos.lookups) > 0 {
+		c.gpos = append(c.gpos, gpos)
+	}
+	return nil
+}
+
+// lookup returns the lookup of the given kind in the feature, adding it if needed.
+func (f *feaFeature) lookup(kind uint16) *feaLookup {
+	for _, l := range f.lookups {
+		if l.kind == kind {
+			return l
+		}
+	}
+	l := &feaLookup{gpos: f.gpos, kind: kind, pairs: map[[2]uint16]int16{}, bases: map[uint16]map[string]feaAnchor{}}
+	f.lookups = append(f.lookups, l)
+	return l
+}
+
+// substitution reads a ligature substitution rule.
+func (c *feaCompiler) substitution(f *feaFeature) error {
+	var components []uint16
+	for c.peek() != "by" {
+		if c.peek() == "" || c.peek() == ";" || c.peek() == "[" || strings.HasPrefix(c.peek(), "@") {
+			return c.errorf("only ligature substitutions of single glyphs are supported")
+		}
+		gid, err := c.glyph()
+		if err != nil {
+			return err
+		}
+		components = append(components, gid)
+	}
+	c.next()
+	glyph, err := c.glyph()
+	if err != nil {
+		return err
+	}
+	if len(components) < 2 {
+		return c.errorf("only ligature substitutions are supported")
+	}
+	c.glyphClass[glyph] = 2
+
+	l := f.lookup(gsubLigature)
+	l.ligatures = append(l.ligatures, feaLigature{components: components, glyph: glyph})
+	return c.expect(";")
+}
+
+// position reads a pair or mark to base positioning rule.
+func (c *feaCompiler) position(f *feaFeature) error {
+	if c.peek() == "base" {
+		c.next()
+		bases, err := c.glyphSet()
+		if err != nil {
+			return err
+		}
+		l := f.lookup(gposMarkToBase)
+		for c.peek() == "<" {
+			anchor, err := c.anchor()
+			if err != nil {
+				return err
+			}
+			if err := c.expect("mark"); err != nil {
+				return err
+			}
+			class := c.next()
+			if _, found := c.markClasses[class]; !found {
+				return c.errorf("unknown mark class %q", class)
+			}
+			for _, gid := range bases {
+				if l.bases[gid] == nil {
+					l.bases[gid] = map[string]feaAnchor{}
+				}
+				l.bases[gid][class] = anchor
+				if c.glyphClass[gid] == 0 {
+					c.glyphClass[gid] = 1
+				}
+			}
+		}
+		return c.expect(";")
+	}
+
+	left, err := c.glyphSet()
+	if err != nil {
+		return err
+	}
+	right, err := c.glyphSet()
+	if err != nil {
+		return err
+	}
+	value, err := c.number()
+	if err != nil {
+		return err
+	}
+
+	l := f.lookup(gposPairAdjustment)
+	for _, a := range left {
+		for _, b := range right {
+			// As in other compilers, the first rule for a pair wins.
+			if _, found := l.pairs[[2]uint16{a, b}]; !found {
+				l.pairs[[2]uint16{a, b}] = value
+			}
+		}
+	}
+	return c.expect(";")
+}
+
+const gposMarkToBase = 4
+
+// tables returns the compiled GSUB and GPOS tables, or nil if they have no features.
+func (c *feaCompiler) tables() (gsub, gpos []byte, err error) {
+	if len(c.languageSystems) == 0 {
+		c.languageSystems = [][2]Tag{{MustNamedTag("DFLT"), DefaultLanguage}}
+	}
+	if len(c.gsub) > 0 {
+		if gsub, err = c.layout(c.gsub); err != nil {
+			return nil, nil, fmt.Errorf("compiling GSUB: %s", err)
+		}
+	}
+	if len(c.gpos) > 0 {
+		if gpos, err = c.layout(c.gpos); err != nil {
+			return nil, nil, fmt.Errorf("compiling GPOS: %s", err)
+		}
+	}
+	return gsub, gpos, nil
+}
+
+// layout returns a GSUB or GPOS table containing the features.
+func (c *feaCompiler) layout(features []*feaFeature) ([]byte, error) {
+	sort.SliceStable(features, func(i, j int) bool {
+		return features[i].tag.Number < features[j].tag.Number
+	})
+
+	// The lookups are applied in the order that they appear in the file.
+	var lookups []*feaLookup
+	var featureTags []uint32
+	var featureTables [][]byte
+	for _, f := range features {
+		table := appendUint16s(nil, 0, uint16(len(f.lookups)))
+		for _, l := range f.lookups {
+			table = appendUint16s(table, uint16(len(lookups)))
+			lookups = append(lookups, l)
+		}
+		featureTags = append(featureTags, f.tag.Number)
+		featureTables = append(featureTables, table)
+	}
+	featureList, err := packRecords(appendUint16s(nil, uint16(len(features))), featureTags, featureTables)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every feature applies to every language system.
+	langSys := appendUint16s(nil, 0, 0xFFFF, uint16(len(features)))
+	for i := range features {
+		langSys = appendUint16s(langSys, uint16(i))
+	}
+	langs := map[Tag][]Tag{}
+	var scriptTags []Tag
+	for _, system := range c.languageSystems {
+		if _, found := langs[system[0]]; !found {
+			scriptTags = append(scriptTags, system[0])
+		}
+		langs[system[0]] = appendTag(langs[system[0]], system[1])
+	}
+	sort.Slice(scriptTags, func(i, j int) bool { return scriptTags[i].Number < scriptTags[j].Number })
+
+	var scriptNumbers []uint32
+	var scripts [][]byte
+	for _, script := range scriptTags {
+		var tags []uint32
+		var tables [][]byte
+		hasDefault := false
+		for _, lang := range langs[script] {
+			if lang == DefaultLanguage {
+				hasDefault = true
+			} else {
+				tags = append(tags, lang.Number)
+				tables = append(tables, langSys)
+			}
+		}
+		sort.Slice(tags, func(i, j int) bool { return tags[i] < tags[j] })
+
+		table, err := packRecords(appendUint16s(nil, 0, uint16(len(tags))), tags, tables)
+		if err != nil {
+			return nil, err
+		}
+		if hasDefault {
+			binary.BigEndian.PutUint16(table, uint16(len(table)))
+			table = append(table, langSys...)
+		}
+		scriptNumbers = append(scriptNumbers, script.Number)
+		scripts = append(scripts, table)
+	}
+	scriptList, err := packRecords(appendUint16s(nil, uint16(len(scripts))), scriptNumbers, scripts)
+	if err != nil {
+		return nil, err
+	}
+
+	var subtables [][]byte
+	for _, l := range lookups {
+		sub, err := c.subtable(l)
+		if err != nil {
+			return nil, err
+		}
+		subtables = append(subtables, sub)
+	}
+	lookupList, err := packLookupList(lookups, subtables, false)
+	if err != nil {
+		// Large lookups are moved after the lookup list, using extension subtables.
+		lookupList, err = packLookupList(lookups, subtables, true)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	buf := appendUint32s(nil, 0x00010000)
+	offset := 10
+	for _, table := range [][]byte{scriptList, featureList, lookupList} {
+		if offset > 0xFFFF {
+			return nil, fmt.Errorf("table is too large")
+		}
+		buf = appendUint16s(buf, uint16(offset))
+		offset += len(table)
+	}
+	buf = append(buf, scriptList...)
+	buf = append(buf, featureList...)
+	return append(buf, lookupList...), nil
+}
+
+// packLookupList returns a lookup list containing one subtable for each lookup. If
+// extension is true, the subtables are wrapped in extension subtables and placed
+// after all the lookups, so that they can be more than 64KB from the lookup list.
+func packLookupList(lookups []*feaLookup, subtables [][]byte, extension bool) ([]byte, error) {
+	var tables [][]byte
+	for i, l := range lookups {
+		if !extension {
+			table, err := packRecords(appendUint16s(nil, l.kind, 0, 1), nil, [][]byte{subtables[i]})
+			if err != nil {
+				return nil, err
+			}
+			tables = append(tables, table)
+			continue
+		}
+		extensionType := uint16(gsubExtension)
+		if l.gpos {
+			extensionType = gposExtension
+		}
+		// The offset of the real subtable is filled in below.
+		tables = append(tables, appendUint16s(nil, extensionType, 0, 1, 8, 1, l.kind, 0, 0))
+	}
+	list, err := packRecords(appendUint16s(nil, uint16(len(lookups))), nil, tables)
+	if err != nil || !extension {
+		return list, err
+	}
+
+	start := 2 + 2*len(lookups)
+	for i := range lookups {
+		ext := start + 16*i + 8
+		binary.BigEndian.PutUint32(list[ext+4:], uint32(len(list)-ext))
+		list = append(list, subtables[i]...)
+	}
+	return list, nil
+}
+
+// packRecords returns header followed by a record for each child table, and the
+// tables themselves. Each record contains the tag (if tags is not nil) and the
+// 16-bit offset of the child from the start of the header.
+func packRecords(header []byte, tags []uint32, children [][]byte) ([]byte, error) {
+	recordSize := 2
+	if tags != nil {
+		recordSize = 6
+	}
+	buf := append([]byte{}, header...)
+	offset := len(header) + recordSize*len(children)
+	for i, child := range children {
+		if offset > 0xFFFF {
+			return nil, fmt.Errorf("offset %d does not fit in 16 bits", offset)
+		}
+		if tags != nil {
+			buf = appendUint32s(buf, tags[i])
+		}
+		buf = appendUint16s(buf, uint16(offset))
+		offset += len(child)
+	}
+	for _, child := range children {
+		buf = append(buf, child...)
+	}
+	return buf, nil
+}
+
+// coverageTable returns a format 1 coverage table for the sorted glyphs.
+func coverageTable(gids []uint16) []byte {
+	return appendUint16s(appendUint16s(nil, 1, uint16(len(gids))), gids...)
+}
+
+// subtable returns the single subtable of a lookup.
+func (c *feaCompiler) subtable(l *feaLookup) ([]byte, error) {
+	switch {
+	case !l.gpos && l.kind == gsubLigature:
+		sets := map[uint16][]feaLigature{}
+		var first []uint16
+		for _, lig := range l.ligatures {
+			if _, found := sets[lig.components[0]]; !found {
+				first = append(first, lig.components[0])
+			}
+			sets[lig.components[0]] = append(sets[lig.components[0]], lig)
+		}
+		sort.Slice(first, func(i, j int) bool { return first[i] < first[j] })
+
+		var tables [][]byte
+		for _, gid := range first {
+			// Longer ligatures are tried first, so that "f f i" is not hidden by "f f".
+			set := sets[gid]
+			sort.SliceStable(set, func(i, j int) bool { return len(set[i].components) > len(set[j].components) })
+			var ligatures [][]byte
+			for _, lig := range set {
+				ligatures = append(ligatures, appendUint16s(appendUint16s(nil, lig.glyph, uint16(len(lig.components))), lig.components[1:]...))
+			}
+			table, err := packRecords(appendUint16s(nil, uint16(len(set))), nil, ligatures)
+			if err != nil {
+				return nil, err
+			}
+			tables = append(tables, table)
+		}
+		return withCoverage(appendUint16s(nil, 1, 0, uint16(len(first))), 2, tables, first)
+
+	case l.gpos && l.kind == gposPairAdjustment:
+		sets := map[uint16][][2]uint16{}
+		var first []uint16
+		for pair, value := range l.pairs {
+			if _, found := sets[pair[0]]; !found {
+				first = append(first, pair[0])
+			}
+			sets[pair[0]] = append(sets[pair[0]], [2]uint16{pair[1], uint16(value)})
+		}
+		sort.Slice(first, func(i, j int) bool { return first[i] < first[j] })
+
+		var tables [][]byte
+		for _, gid := range first {
+			set := sets[gid]
+			sort.Slice(set, func(i, j int) bool { return set[i][0] < set[j][0] })
+			table := appendUint16s(nil, uint16(len(set)))
+			for _, record := range set {
+				table = appendUint16s(table, record[0], record[1])
+			}
+			tables = append(tables, table)
+		}
+		return withCoverage(appendUint16s(nil, 1, 0, valueXAdvance, 0, uint16(len(first))), 2, tables, first)
+
+	case l.gpos && l.kind == gposMarkToBase:
+		return c.markToBase(l)
+	}
+	return nil, fmt.Errorf("unsupported lookup type %d", l.kind)
+}
+
+// withCoverage packs a subtable whose header contains the offset of a coverage
+// table at coverageOffset, followed by the child tables and then the coverage.
+func withCoverage(header []byte, coverageOffset int, children [][]byte, covered []uint16) ([]byte, error) {
+	buf, err := packRecords(header, nil, children)
+	if err != nil {
+		return nil, err
+	}
+	if len(buf) > 0xFFFF {
+		return nil, fmt.Errorf("subtable is too large")
+	}
+	binary.BigEndian.PutUint16(buf[coverageOffset:], uint16(len(buf)))
+	return append(buf, coverageTable(covered)...), nil
+}
+
+// markToBase returns a mark to base attachment subtable.
+func (c *feaCompiler) markToBase(l *feaLookup) ([]byte, error) {
+	// Only the mark classes used by this lookup are included.
+	var classNames []string
+	for _, classes := range l.bases {
+		for name := range classes {
+			found := false
+			for _, n := range classNames {
+				found = found || n == name
+			}
+			if !found {
+				classNames = append(classNames, name)
+			}
+		}
+	}
+	sort.Strings(classNames)
+
+	type mark struct {
+		gid    uint16
+		class  uint16
+		anchor feaAnchor
+	}
+	var marks []mark
+	for i, name := range classNames {
+		for gid, anchor := range c.markClasses[name] {
+			marks = append(marks, mark{gid, uint16(i), anchor})
+		}
+	}
+	sort.Slice(marks, func(i, j int) bool { return marks[i].gid < marks[j].gid })
+	var markGlyphs []uint16
+	markArray := appendUint16s(nil, uint16(len(marks)))
+	for i, m := range marks {
+		if i > 0 && marks[i-1].gid == m.gid {
+			return nil, fmt.Errorf("glyph %d is in more than one mark class", m.gid)
+		}
+		markGlyphs = append(markGlyphs, m.gid)
+		markArray = appendUint16s(markArray, m.class, uint16(2+4*len(marks)+6*i))
+	}
+	for _, m := range marks {
+		markArray = appendUint16s(markArray, 1, uint16(m.anchor.x), uint16(m.anchor.y))
+	}
+
+	var baseGlyphs []uint16
+	for gid := range l.bases {
+		baseGlyphs = append(baseGlyphs, gid)
+	}
+	sort.Slice(baseGlyphs, func(i, j int) bool { return baseGlyphs[i] < baseGlyphs[j] })
+	baseArray := appendUint16s(nil, uint16(len(baseGlyphs)))
+	var anchors []byte
+	anchorsStart := 2 + 2*len(baseGlyphs)*len(classNames)
+	for _, gid := range baseGlyphs {
+		for _, name := range classNames {
+			anchor, found := l.bases[gid][name]
+			if !found {
+				baseArray = appendUint16s(baseArray, 0)
+				continue
+			}
+			baseArray = appendUint16s(baseArray, uint16(anchorsStart+len(anchors)))
+			anchors = appendUint16s(anchors, 1, uint16(anchor.x), uint16(anchor.y))
+		}
+	}
+	baseArray = append(baseArray, anchors...)
+
+	markCoverage, baseCoverage := coverageTable(markGlyphs), coverageTable(baseGlyphs)
+	offset := 12
+	buf := appendUint16s(nil, 1, uint16(offset), uint16(offset+len(markCoverage)), uint16(len(classNames)),
+		uint16(offset+len(markCoverage)+len(baseCoverage)), uint16(offset+len(markCoverage)+len(baseCoverage)+len(markArray)))
+	if len(buf)+len(markCoverage)+len(baseCoverage)+len(markArray) > 0xFFFF {
+		return nil, fmt.Errorf("subtable is too large")
+	}
+	buf = append(buf, markCoverage...)
+	buf = append(buf, baseCoverage...)
+	buf = append(buf, markArray...)
+	return append(buf, baseArray...), nil
+}
+
+// gdef returns a GDEF table containing the class of each glyph used as a base,
+// ligature or mark.
+func (c *feaCompiler) gdef() []byte {
+	var gids []uint16
+	for gid := range c.glyphClass {
+		gids = append(gids, gid)
+	}
+	sort.Slice(gids, func(i, j int) bool { return gids[i] < gids[j] })
+
+	buf := appendUint32s(nil, 0x00010000)
+	buf = appendUint16s(buf, 12, 0, 0, 0)
+	buf = appendUint16s(buf, 2, uint16(len(gids)))
+	for _, gid := range gids {
+		buf = appendUint16s(buf, gid, gid, c.glyphClass[gid])
+	}
+	return buf
+}
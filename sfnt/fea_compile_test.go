@@ -0,0 +1,207 @@
+package sfnt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+const testFea = `
+languagesystem DFLT dflt;
+languagesystem latn dflt;
+languagesystem latn TRK;
+
+@round = [o a];
+markClass acute <anchor 0 500> @TOP; # above the base
+
+feature liga {
+	sub f i by fi;
+} liga;
+
+feature kern {
+	pos T @round -80;
+	pos T a -60; # the first rule for a pair wins
+} kern;
+
+feature mark {
+	pos base [a o] <anchor 250 500> mark @TOP;
+} mark;
+`
+
+func TestCompileFea(t *testing.T) {
+	b := NewBuilder(1000)
+	gids := map[string]uint16{}
+	for _, name := range []string{"f", "i", "fi", "T", "o", "a", "acute"} {
+		gids[name] = b.AddGlyph(name, 500, nil)
+		if len(name) == 1 {
+			b.Map(rune(name[0]), gids[name])
+		}
+	}
+	built, err := b.Font()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := built.CompileFea(testFea); err != nil {
+		t.Fatalf("CompileFea() err = %v", err)
+	}
+
+	var buf bytes.Buffer
+	if _, err := built.WriteOTF(&buf); err != nil {
+		t.Fatal(err)
+	}
+	font, err := StrictParse(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	glyphs, err := font.Shape("fi", []Tag{MustNamedTag("liga")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(glyphs) != 1 || glyphs[0].GlyphID != gids["fi"] {
+		t.Errorf("Shape(fi) = %v, want [%d]", glyphs, gids["fi"])
+	}
+	for _, pair := range []string{"To", "Ta"} {
+		kern, err := font.KerningValue(gids[pair[:1]], gids[pair[1:]], nil)
+		if err != nil || kern != -80 {
+			t.Errorf("KerningValue(%s) = %v, %v, want -80, nil", pair, kern, err)
+		}
+	}
+
+	scripts, err := font.Scripts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scripts) != 2 || len(scripts[1].Languages) != 2 || len(scripts[1].Languages[1].GPOS) != 2 {
+		t.Errorf("Scripts() = %+v, want DFLT and latn with dflt and TRK", scripts)
+	}
+
+	var fea strings.Builder
+	if err := font.WriteFea(&fea); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(fea.String(), "\tsub f i by fi;\n") || !strings.Contains(fea.String(), "\tpos T o -80;\n") {
+		t.Errorf("WriteFea() = %s, want the ligature and pair", fea.String())
+	}
+
+	if !font.HasTable(TagGdef) {
+		t.Errorf("HasTable(GDEF) = false, want true")
+	}
+	gpos, err := font.GposTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mark := gpos.featureLookups(MustNamedTag("mark"))
+	if len(mark) != 1 || mark[0].Type != gposMarkToBase {
+		t.Fatalf("mark lookups = %v, want one mark to base lookup", mark)
+	}
+	sub := mark[0].subtables[0]
+	if got := coverageGlyphs(sub[binary.BigEndian.Uint16(sub[2:]):]); len(got) != 1 || got[0] != gids["acute"] {
+		t.Errorf("mark coverage = %v, want [%d]", got, gids["acute"])
+	}
+	index, _ := coverageIndex(sub[binary.BigEndian.Uint16(sub[4:]):], gids["o"])
+	baseArray := sub[binary.BigEndian.Uint16(sub[10:]):]
+	anchor := baseArray[binary.BigEndian.Uint16(baseArray[2+2*index:]):]
+	if x, y := int16(binary.BigEndian.Uint16(anchor[2:])), int16(binary.BigEndian.Uint16(anchor[4:])); x != 250 || y != 500 {
+		t.Errorf("base anchor of o = %d, %d, want 250, 500", x, y)
+	}
+}
+
+func TestCompileFeaErrors(t *testing.T) {
+	b := NewBuilder(1000)
+	b.AddGlyph("a", 500, nil)
+	font, err := b.Font()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for src, want := range map[string]string{
+		"feature liga {\n sub a b by c;\n} liga;": "line 2: unknown glyph \"b\"",
+		"lookup foo { } foo;":                     "line 1: unsupported statement \"lookup\"",
+		"feature kern { pos a a -10; } liga;":     "line 1: feature \"kern\" is closed by the wrong tag",
+		"feature kern { pos a a -10; ":            "line 1: unterminated feature \"kern\"",
+	} {
+		if err := font.CompileFea(src); err == nil || err.Error() != want {
+			t.Errorf("CompileFea(%q) err = %v, want %q", src, err, want)
+		}
+	}
+}
+
+func TestCompileFeaKeepsExistingFeatures(t *testing.T) {
+	font := parseTestFont(t, "Roboto-BoldItalic.ttf")
+	names, err := font.glyphNames()
+	if err != nil {
+		t.Fatal(err)
+	}
+	gids := map[string]uint16{}
+	for i, name := range names {
+		gids[name] = uint16(i)
+	}
+	kern, err := font.KerningValue(gids["T"], gids["o"], nil)
+	if err != nil || kern == 0 {
+		t.Fatalf("KerningValue(To) = %v, %v, want a kerning pair", kern, err)
+	}
+	counts := map[Tag][2]int{}
+	for _, tag := range []Tag{TagGsub, TagGpos} {
+		layout, err := font.TableLayout(tag)
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts[tag] = [2]int{len(layout.Features), len(layout.Lookups)}
+	}
+
+	src := "languagesystem latn dflt;\nfeature ss20 { sub f i by fl; } ss20;\nfeature kern { pos four five -33; } kern;"
+	if err := font.CompileFea(src); err != nil {
+		t.Fatalf("CompileFea() err = %v", err)
+	}
+	var buf bytes.Buffer
+	if _, err := font.WriteOTF(&buf); err != nil {
+		t.Fatal(err)
+	}
+	font, err = StrictParse(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for tag, count := range counts {
+		layout, err := font.TableLayout(tag)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(layout.Features) != count[0]+1 || len(layout.Lookups) != count[1]+1 {
+			t.Errorf("%s has %d features and %d lookups, want %d and %d", tag, len(layout.Features), len(layout.Lookups), count[0]+1, count[1]+1)
+		}
+	}
+
+	for feature, want := range map[string]uint16{"liga": gids["fi"], "ss20": gids["fl"]} {
+		glyphs, err := font.Shape("fi", []Tag{MustNamedTag(feature)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(glyphs) != 1 || glyphs[0].GlyphID != want {
+			t.Errorf("Shape(fi, %s) = %v, want [%d]", feature, glyphs, want)
+		}
+	}
+	for pair, want := range map[[2]string]float64{{"T", "o"}: kern, {"four", "five"}: -33} {
+		if got, err := font.KerningValue(gids[pair[0]], gids[pair[1]], nil); err != nil || got != want {
+			t.Errorf("KerningValue(%s%s) = %v, %v, want %v, nil", pair[0], pair[1], got, err, want)
+		}
+	}
+
+	// Languages that the feature file does not mention use its default language.
+	scripts, err := font.Scripts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, script := range scripts {
+		if script.Tag != MustNamedTag("latn") {
+			continue
+		}
+		for _, lang := range script.Languages {
+			if !hasTag(lang.GSUB, MustNamedTag("liga")) || !hasTag(lang.GSUB, MustNamedTag("ss20")) {
+				t.Errorf("latn %s GSUB = %v, want liga and ss20", lang.Tag, lang.GSUB)
+			}
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package sfnt
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteFea(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		want []string
+	}{
+		{"open-sans-v15-latin-regular.woff", []string{
+			"languagesystem latn dflt;",
+			"\tsub f f i by uniFB03;\n",
+			"feature liga {\n\tscript latn;\n\tlanguage dflt;\n\t\tlookup gsub_0;\n",
+		}},
+		{"Roboto-BoldItalic.ttf", []string{
+			"languagesystem latn TRK;",
+			"\tsub f f i by ",
+			"feature kern {\n",
+			"] -29;\n",
+		}},
+	} {
+		font := parseTestFont(t, test.name)
+		var fea strings.Builder
+		if err := font.WriteFea(&fea); err != nil {
+			t.Fatalf("%s: WriteFea() err = %v", test.name, err)
+		}
+		for _, want := range test.want {
+			if !strings.Contains(fea.String(), want) {
+				t.Errorf("%s: WriteFea() does not contain %q", test.name, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package sfnt
+
+import "strings"
+
+// CanonicalFilename returns the conventional file name for the font, made from
+// its family and style names with spaces and non-ASCII characters removed, for
+// example "OpenSans-BoldItalic.ttf". Variable fonts list their axes in brackets
+// instead of the style, for example "Roboto[wdth,wght].ttf" or
+// "Roboto-Italic[wdth,wght].ttf".
+//
+// The extension is ext if it is not empty, and otherwise ".otf" for fonts with
+// CFF outlines and ".ttf" for the rest.
+func (font *Font) CanonicalFilename(ext string) (string, error) {
+	name, err := font.NameTable()
+	if err != nil {
+		return "", err
+	}
+	if ext == "" {
+		ext = font.Format().Extension()
+	}
+
+	family := name.Lookup(NamePreferredFamily)
+	if family == "" {
+		family = name.Lookup(NameFontFamily)
+	}
+	family = filenamePart(family)
+	if family == "" {
+		family = "Untitled"
+	}
+
+	if font.HasTable(TagFvar) {
+		fvar, err := font.FvarTable()
+		if err != nil {
+			return "", err
+		}
+		tags := make([]Tag, 0, len(fvar.Axes))
+		axes := make([]string, 0, len(fvar.Axes))
+		for _, axis := range fvar.Axes {
+			tags = append(tags, axis.Tag)
+		}
+		sortTags(tags)
+		for _, tag := range tags {
+			axes = append(axes, strings.TrimRight(tag.String(), " "))
+		}
+
+		italic := false
+		if font.HasTable(TagOS2) {
+			os2, err := font.OS2Table()
+			if err != nil {
+				return "", err
+			}
+			italic = os2.FsSelection&fsSelectionItalic != 0
+		}
+		if italic {
+			family += "-Italic"
+		}
+		return family + "[" + strings.Join(axes, ",") + "]" + ext, nil
+	}
+
+	style := name.Lookup(NamePreferredSubfamily)
+	if style == "" {
+		style = name.Lookup(NameFontSubfamily)
+	}
+	style = filenamePart(style)
+	if style == "" {
+		style = "Regular"
+	}
+	return family + "-" + style + ext, nil
+}
+
+// filenamePart removes the characters that are not allowed in PostScript names
+// or in file names on common operating systems, and hyphens, which separate the
+// family from the style.
+func filenamePart(s string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`-\:*?"|`, r) {
+			return -1
+		}
+		return r
+	}, postscriptName(s))
+}
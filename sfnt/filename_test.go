@@ -0,0 +1,43 @@
+package sfnt
+
+import "testing"
+
+func TestCanonicalFilename(t *testing.T) {
+	tests := []struct {
+		file, ext, want string
+	}{
+		{"Roboto-BoldItalic.ttf", "", "Roboto-BoldItalic.ttf"},
+		{"open-sans-v15-latin-regular.woff", ".woff", "OpenSans-Regular.woff"},
+	}
+	for _, test := range tests {
+		font := parseTestFont(t, test.file)
+		if got, err := font.CanonicalFilename(test.ext); err != nil || got != test.want {
+			t.Errorf("%s: CanonicalFilename(%q) = %q, %v, want %q", test.file, test.ext, got, err, test.want)
+		}
+	}
+
+	font := limitTestFont(t)
+	fvar, err := font.FvarTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fvar.Axes = append(fvar.Axes, VariationAxis{Tag: MustNamedTag("GRAD")})
+	name, err := font.NameTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := name.AddMicrosoftEnglishEntry(NamePreferredFamily, "Test Sans: VF"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := font.CanonicalFilename(""); err != nil || got != "TestSansVF[GRAD,wdth,wght].ttf" {
+		t.Errorf("CanonicalFilename() = %q, %v, want TestSansVF[GRAD,wdth,wght].ttf", got, err)
+	}
+	os2, err := font.OS2Table()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os2.FsSelection = fsSelectionItalic
+	if got, err := font.CanonicalFilename(".woff2"); err != nil || got != "TestSansVF-Italic[GRAD,wdth,wght].woff2" {
+		t.Errorf("CanonicalFilename() = %q, %v, want TestSansVF-Italic[GRAD,wdth,wght].woff2", got, err)
+	}
+}
@@ -1,9 +1,13 @@
 package sfnt
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"runtime"
 	"sort"
+	"sync"
+	"time"
 )
 
 type fixed struct {
@@ -19,10 +23,11 @@ func (u *unparsedTable) Bytes() []byte {
 	return u.bytes
 }
 
-// ErrMissingHead is returned by ParseOTF when the font has no head section.
+// ErrMissingHead is returned by ParseOTF when the font has no head (or bhed) section.
 var ErrMissingHead = errors.New("missing head table in font")
 
-// ErrInvalidChecksum is returned by ParseOTF if the font's checksum is wrong
+// ErrInvalidChecksum is wrapped by the *ChecksumError that ParseWithOptions returns
+// if a table's checksum is wrong.
 var ErrInvalidChecksum = errors.New("invalid checksum")
 
 // ErrUnsupportedFormat is returned from Parse if parsing failed
@@ -31,31 +36,122 @@ var ErrUnsupportedFormat = errors.New("unsupported font format")
 // ErrMissingTable is returned from *Table if the table does not exist in the font.
 var ErrMissingTable = errors.New("missing table")
 
+// ErrTooLarge is returned from ParseWithOptions if the font's tables are larger
+// than its MaxSize option.
+var ErrTooLarge = errors.New("font is too large")
+
 // Font represents a SFNT font, which is the underlying representation found
 // in .otf and .ttf files (and .woff, .woff2, .eot files)
 // SFNT is a container format, which contains a number of tables identified by
 // Tags. Depending on the type of glyphs embedded in the file which tables will
 // exist. In particular, there's a big different between TrueType glyphs (usually .ttf)
 // and CFF/PostScript Type 2 glyphs (usually .otf)
+//
+// A Font is safe for concurrent use by multiple goroutines as long as none of them
+// modify it. Tables are parsed lazily, and each table is parsed at most once even
+// if it is requested concurrently. The methods that modify the font are AddTable,
+// SetTable, RemoveTable, and any method of a table that changes its contents
+// (for example TableGlyf.SetGlyph, TableName.Add, or SubsetGlyphs); these must not
+// be called concurrently with any other use of the font.
 type Font struct {
 	file File
 
 	scalerType Tag
-	tables     map[Tag]*tableSection
+
+	mu     sync.RWMutex // mu guards tables, but not the tableSections in it.
+	tables map[Tag]*tableSection
+
+	warnings []error // warnings are the problems found by ParseWithOptions.
 }
 
 // tableSection represents a table within the font file.
 type tableSection struct {
-	tag   Tag
+	tag Tag
+
+	mu    sync.Mutex // mu guards table, which is set when the table is first parsed.
 	table Table
 
-	offset  uint32 // Offset into the file this table starts.
-	length  uint32 // Length of this table within the file.
-	zLength uint32 // Uncompressed length of this table.
+	offset   uint32 // Offset into the file this table starts.
+	length   uint32 // Length of this table within the file.
+	zLength  uint32 // Uncompressed length of this table.
+	checkSum uint32 // Checksum of the table recorded in the file.
+
+	bytes []byte // Uncompressed content of this table, if it was set with SetTable.
+}
+
+// TableRecord describes where a table is stored in the parsed font file.
+// Tables added with AddTable or SetTable are not stored in the file and have no Offset.
+type TableRecord struct {
+	Tag              Tag
+	CheckSum         uint32 // CheckSum recorded in the table directory (0 for WOFF2).
+	Offset           uint32 // Offset into the file this table starts.
+	Length           uint32 // Length of this table when uncompressed.
+	CompressedLength uint32 // CompressedLength of this table within a WOFF file, or 0 if it is not compressed.
+}
+
+// Directory returns a record for each table in the font (including tables that are
+// not parsed by this package), sorted by numeric value of the tag.
+func (font *Font) Directory() []TableRecord {
+	tags := font.Tags()
+	records := make([]TableRecord, 0, len(tags))
+
+	for _, tag := range tags {
+		s, found := font.section(tag)
+		if !found {
+			continue
+		}
+		record := TableRecord{
+			Tag:      tag,
+			CheckSum: s.checkSum,
+			Offset:   s.offset,
+			Length:   s.length,
+		}
+		if s.length != 0 && s.length < s.zLength {
+			record.Length = s.zLength
+			record.CompressedLength = s.length
+		}
+		records = append(records, record)
+	}
+
+	return records
+}
+
+// TableData returns the uncompressed content of the table, without parsing it.
+// For tables that have been modified or added since the font was parsed, this
+// is the serialized form of the table.
+func (font *Font) TableData(tag Tag) ([]byte, error) {
+	s, found := font.section(tag)
+	if !found {
+		return nil, ErrMissingTable
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return font.sectionData(s)
+}
+
+// sectionData returns the content of the table in s. The caller must hold s.mu.
+func (font *Font) sectionData(s *tableSection) ([]byte, error) {
+	if s.table != nil {
+		return s.table.Bytes(), nil
+	}
+
+	return font.readTable(s)
+}
+
+// section returns the section of the font containing the table with the given tag.
+func (font *Font) section(tag Tag) (*tableSection, bool) {
+	font.mu.RLock()
+	defer font.mu.RUnlock()
+	s, found := font.tables[tag]
+	return s, found
 }
 
 // Tags is the list of tags that are defined in this font, sorted by numeric value.
 func (font *Font) Tags() []Tag {
+	font.mu.RLock()
+	defer font.mu.RUnlock()
+
 	tags := make([]Tag, 0, len(font.tables))
 
 	for t := range font.tables {
@@ -71,27 +167,45 @@ func (font *Font) Tags() []Tag {
 
 // HasTable returns true if this font has an entry for the given table.
 func (font *Font) HasTable(tag Tag) bool {
-	_, ok := font.tables[tag]
+	_, ok := font.section(tag)
 	return ok
 }
 
 // AddTable adds a table to the font. If a table with the
 // given tag is already present, it will be overwritten.
 func (font *Font) AddTable(tag Tag, table Table) {
+	font.mu.Lock()
+	defer font.mu.Unlock()
 	font.tables[tag] = &tableSection{
 		tag:   tag,
 		table: table,
 	}
 }
 
+// SetTable adds a table to the font from its raw content. If a table with the
+// given tag is already present, it will be overwritten. The content will be
+// parsed when the table is first accessed, and written out unchanged unless the
+// table is modified.
+func (font *Font) SetTable(tag Tag, data []byte) {
+	font.mu.Lock()
+	defer font.mu.Unlock()
+	font.tables[tag] = &tableSection{
+		tag:    tag,
+		length: uint32(len(data)),
+		bytes:  data,
+	}
+}
+
 // RemoveTable removes a table from the font. If the table
 // doesn't exist, this method will do nothing.
 func (font *Font) RemoveTable(tag Tag) {
+	font.mu.Lock()
+	defer font.mu.Unlock()
 	delete(font.tables, tag)
 }
 
 // Type represents the kind of glyphs in this font.
-// It is one of TypeTrueType, TypeTrueTypeApple, TypePostScript1, TypeOpenType
+// It is one of TypeTrueType, TypeAppleTrueType, TypePostScript1, TypeOpenType
 func (font *Font) Type() Tag {
 	return font.scalerType
 }
@@ -111,13 +225,28 @@ func (font *Font) String() string {
 	return str
 }
 
-// HeadTable returns the table corresponding to the 'head' tag.
+// HeadTable returns the table corresponding to the 'head' tag, or to the 'bhed'
+// tag in Apple fonts that only have bitmaps (which have the same format).
 func (font *Font) HeadTable() (*TableHead, error) {
-	t, err := font.Table(TagHead)
+	tag := font.headTag()
+	t, err := font.Table(tag)
 	if err != nil {
 		return nil, err
 	}
-	return t.(*TableHead), nil
+	head, ok := t.(*TableHead)
+	if !ok {
+		return nil, unexpectedTable(tag, t)
+	}
+	return head, nil
+}
+
+// headTag returns the tag of the font header, which is 'bhed' in Apple fonts
+// that only have bitmaps and 'head' otherwise.
+func (font *Font) headTag() Tag {
+	if !font.HasTable(TagHead) && font.HasTable(TagBhed) {
+		return TagBhed
+	}
+	return TagHead
 }
 
 // NameTable returns the table corresponding to the 'name' tag.
@@ -126,7 +255,11 @@ func (font *Font) NameTable() (*TableName, error) {
 	if err != nil {
 		return nil, err
 	}
-	return t.(*TableName), nil
+	table, ok := t.(*TableName)
+	if !ok {
+		return nil, unexpectedTable(TagName, t)
+	}
+	return table, nil
 }
 
 func (font *Font) HheaTable() (*TableHhea, error) {
@@ -134,7 +267,24 @@ func (font *Font) HheaTable() (*TableHhea, error) {
 	if err != nil {
 		return nil, err
 	}
-	return t.(*TableHhea), nil
+	table, ok := t.(*TableHhea)
+	if !ok {
+		return nil, unexpectedTable(TagHhea, t)
+	}
+	return table, nil
+}
+
+// MaxpTable returns the table corresponding to the 'maxp' tag.
+func (font *Font) MaxpTable() (*TableMaxp, error) {
+	t, err := font.Table(TagMaxp)
+	if err != nil {
+		return nil, err
+	}
+	table, ok := t.(*TableMaxp)
+	if !ok {
+		return nil, unexpectedTable(TagMaxp, t)
+	}
+	return table, nil
 }
 
 func (font *Font) OS2Table() (*TableOS2, error) {
@@ -142,7 +292,154 @@ func (font *Font) OS2Table() (*TableOS2, error) {
 	if err != nil {
 		return nil, err
 	}
-	return t.(*TableOS2), nil
+	table, ok := t.(*TableOS2)
+	if !ok {
+		return nil, unexpectedTable(TagOS2, t)
+	}
+	return table, nil
+}
+
+// CmapTable returns the table corresponding to the 'cmap' tag.
+func (font *Font) CmapTable() (*TableCmap, error) {
+	t, err := font.Table(TagCmap)
+	if err != nil {
+		return nil, err
+	}
+	table, ok := t.(*TableCmap)
+	if !ok {
+		return nil, unexpectedTable(TagCmap, t)
+	}
+	return table, nil
+}
+
+// FvarTable returns the table corresponding to the 'fvar' tag.
+func (font *Font) FvarTable() (*TableFvar, error) {
+	t, err := font.Table(TagFvar)
+	if err != nil {
+		return nil, err
+	}
+	table, ok := t.(*TableFvar)
+	if !ok {
+		return nil, unexpectedTable(TagFvar, t)
+	}
+	return table, nil
+}
+
+// AvarTable returns the table corresponding to the 'avar' tag.
+func (font *Font) AvarTable() (*TableAvar, error) {
+	t, err := font.Table(TagAvar)
+	if err != nil {
+		return nil, err
+	}
+	table, ok := t.(*TableAvar)
+	if !ok {
+		return nil, unexpectedTable(TagAvar, t)
+	}
+	return table, nil
+}
+
+// GvarTable returns the table corresponding to the 'gvar' tag.
+func (font *Font) GvarTable() (*TableGvar, error) {
+	t, err := font.Table(TagGvar)
+	if err != nil {
+		return nil, err
+	}
+	table, ok := t.(*TableGvar)
+	if !ok {
+		return nil, unexpectedTable(TagGvar, t)
+	}
+	return table, nil
+}
+
+// StatTable returns the table corresponding to the 'STAT' tag.
+func (font *Font) StatTable() (*TableStat, error) {
+	t, err := font.Table(TagStat)
+	if err != nil {
+		return nil, err
+	}
+	table, ok := t.(*TableStat)
+	if !ok {
+		return nil, unexpectedTable(TagStat, t)
+	}
+	return table, nil
+}
+
+// KernTable returns the table corresponding to the 'kern' tag.
+func (font *Font) KernTable() (*TableKern, error) {
+	t, err := font.Table(TagKern)
+	if err != nil {
+		return nil, err
+	}
+	table, ok := t.(*TableKern)
+	if !ok {
+		return nil, unexpectedTable(TagKern, t)
+	}
+	return table, nil
+}
+
+// KerxTable returns the table corresponding to the 'kerx' tag.
+func (font *Font) KerxTable() (*TableKerx, error) {
+	t, err := font.Table(TagKerx)
+	if err != nil {
+		return nil, err
+	}
+	table, ok := t.(*TableKerx)
+	if !ok {
+		return nil, unexpectedTable(TagKerx, t)
+	}
+	return table, nil
+}
+
+// TrakTable returns the table corresponding to the 'trak' tag.
+func (font *Font) TrakTable() (*TableTrak, error) {
+	t, err := font.Table(TagTrak)
+	if err != nil {
+		return nil, err
+	}
+	table, ok := t.(*TableTrak)
+	if !ok {
+		return nil, unexpectedTable(TagTrak, t)
+	}
+	return table, nil
+}
+
+// GaspTable returns the table corresponding to the 'gasp' tag.
+func (font *Font) GaspTable() (*TableGasp, error) {
+	t, err := font.Table(TagGasp)
+	if err != nil {
+		return nil, err
+	}
+	table, ok := t.(*TableGasp)
+	if !ok {
+		return nil, unexpectedTable(TagGasp, t)
+	}
+	return table, nil
+}
+
+// CFFTable returns the table corresponding to the 'CFF ' tag.
+func (font *Font) CFFTable() (*TableCFF, error) {
+	t, err := font.Table(TagCFF)
+	if err != nil {
+		return nil, err
+	}
+	table, ok := t.(*TableCFF)
+	if !ok {
+		return nil, unexpectedTable(TagCFF, t)
+	}
+	return table, nil
+}
+
+// PostTable returns the table corresponding to the 'post' tag.
+func (font *Font) PostTable() (*TablePost, error) {
+	t, err := font.Table(TagPost)
+	if err != nil {
+		return nil, err
+	}
+	table, ok := t.(*TablePost)
+	if !ok {
+		return nil, unexpectedTable(TagPost, t)
+	}
+	return table, nil
 }
 
 func (font *Font) TableLayout(tag Tag) (*TableLayout, error) {
@@ -152,11 +449,18 @@ func (font *Font) TableLayout(tag Tag) (*TableLayout, error) {
 	}
 	l, ok := t.(*TableLayout)
 	if !ok {
-		return nil, fmt.Errorf("table %q is not a layout table", tag)
+		return nil, unexpectedTable(tag, t)
 	}
 	return l, nil
 }
 
+// unexpectedTable returns the error for a table that was parsed into a different
+// type than its accessor returns, as it may be if RegisterTableParser replaced
+// the parser for its tag.
+func unexpectedTable(tag Tag, t Table) error {
+	return fmt.Errorf("table %q is a %T, not the type this package parses it as", tag, t)
+}
+
 // GposTable returns the Glyph Positioning table identified with the 'GPOS' tag.
 func (font *Font) GposTable() (*TableLayout, error) {
 	return font.TableLayout(TagGpos)
@@ -167,12 +471,98 @@ func (font *Font) GsubTable() (*TableLayout, error) {
 	return font.TableLayout(TagGsub)
 }
 
+// BaseTable returns the Baseline table identified with the 'BASE' tag.
+func (font *Font) BaseTable() (*TableBase, error) {
+	t, err := font.Table(TagBase)
+	if err != nil {
+		return nil, err
+	}
+	table, ok := t.(*TableBase)
+	if !ok {
+		return nil, unexpectedTable(TagBase, t)
+	}
+	return table, nil
+}
+
+// JstfTable returns the Justification table identified with the 'JSTF' tag.
+func (font *Font) JstfTable() (*TableJstf, error) {
+	t, err := font.Table(TagJstf)
+	if err != nil {
+		return nil, err
+	}
+	table, ok := t.(*TableJstf)
+	if !ok {
+		return nil, unexpectedTable(TagJstf, t)
+	}
+	return table, nil
+}
+
+// FeatTable returns the Graphite Feature table identified with the 'Feat' tag.
+func (font *Font) FeatTable() (*TableFeat, error) {
+	t, err := font.Table(TagFeat)
+	if err != nil {
+		return nil, err
+	}
+	table, ok := t.(*TableFeat)
+	if !ok {
+		return nil, unexpectedTable(TagFeat, t)
+	}
+	return table, nil
+}
+
+// GlocTable returns the Graphite Glyph Attribute Location table identified with the 'Gloc' tag.
+func (font *Font) GlocTable() (*TableGloc, error) {
+	t, err := font.Table(TagGloc)
+	if err != nil {
+		return nil, err
+	}
+	table, ok := t.(*TableGloc)
+	if !ok {
+		return nil, unexpectedTable(TagGloc, t)
+	}
+	return table, nil
+}
+
+// GlatTable returns the Graphite Glyph Attribute table identified with the 'Glat' tag.
+func (font *Font) GlatTable() (*TableGlat, error) {
+	t, err := font.Table(TagGlat)
+	if err != nil {
+		return nil, err
+	}
+	table, ok := t.(*TableGlat)
+	if !ok {
+		return nil, unexpectedTable(TagGlat, t)
+	}
+	return table, nil
+}
+
+// SilfTable returns the Graphite Rules table identified with the 'Silf' tag.
+func (font *Font) SilfTable() (*TableSilf, error) {
+	t, err := font.Table(TagSilf)
+	if err != nil {
+		return nil, err
+	}
+	table, ok := t.(*TableSilf)
+	if !ok {
+		return nil, unexpectedTable(TagSilf, t)
+	}
+	return table, nil
+}
+
 func (font *Font) Table(tag Tag) (Table, error) {
-	s, found := font.tables[tag]
+	s, found := font.section(tag)
 	if !found {
 		return nil, ErrMissingTable
 	}
 
+	return font.sectionTable(s)
+}
+
+// sectionTable returns the table in s, parsing it if it has not yet been parsed.
+func (font *Font) sectionTable(s *tableSection) (Table, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	if s.table == nil {
 		t, err := font.parseTable(s)
 		if err != nil {
@@ -183,6 +573,18 @@ func (font *Font) Table(tag Tag) (Table, error) {
 	return s.table, nil
 }
 
+// parsedTable returns the table with the given tag if it has been parsed or
+// added to the font, and so may have been changed, or nil.
+func (font *Font) parsedTable(tag Tag) Table {
+	s, found := font.section(tag)
+	if !found {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.table
+}
+
 // New returns an empty Font. It has only an empty 'head' table.
 func New(scalerType Tag) *Font {
 	font := &Font{
@@ -203,9 +605,35 @@ type File interface {
 }
 
 // Parse parses an OpenType, TrueType, WOFF, or WOFF2 file and returns a Font.
-// If parsing fails, an error is returned and *Font will be nil.
+// If parsing fails, an error is returned and *Font will be nil. Malformed fonts
+// never cause a panic: if parsing panics, a *PanicError is returned instead, as
+// it is by the methods that parse each table.
 func Parse(file File) (*Font, error) {
-	magic, err := ReadTag(file)
+	return ParseContext(context.Background(), file)
+}
+
+// ParseContext is like Parse, but returns ctx.Err() if ctx is cancelled
+// before parsing completes.
+func ParseContext(ctx context.Context, file File) (font *Font, err error) {
+	var magic Tag
+	if i := currentInstrumentation(); i != nil {
+		i.ParseStarted()
+		start, size := time.Now(), fileSize(file)
+		defer func() {
+			event := ParseEvent{Format: formatForMagic(magic), Size: size, Duration: time.Since(start), Err: err}
+			if font != nil {
+				event.Tables = len(font.tables)
+			}
+			i.ParseFinished(event)
+		}()
+	}
+	defer (&panicContext{offset: -1}).recover(&err)
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	magic, err = ReadTag(file)
 	if err != nil {
 		return nil, err
 	}
@@ -214,29 +642,91 @@ func Parse(file File) (*Font, error) {
 
 	switch magic {
 	case SignatureWOFF:
-		return parseWOFF(file)
+		font, err = parseWOFF(file)
 	case SignatureWOFF2:
-		return parseWOFF2(file)
+		font, err = parseWOFF2(file)
 	case TypeTrueType, TypeOpenType, TypePostScript1, TypeAppleTrueType:
-		return parseOTF(file)
+		font, err = parseOTF(file)
 	default:
+		switch format, _ := Sniff(file); format {
+		case FormatType1:
+			return nil, ErrType1
+		case FormatDfont:
+			return nil, ErrDfont
+		}
 		return nil, ErrUnsupportedFormat
 	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Decompressing a WOFF2 file can take a while, so check again.
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return font, nil
 }
 
 // StrictParse parses an OpenType, TrueType, WOFF or WOFF2 file and returns a Font.
 // Each table will be fully parsed and an error is returned if any fail.
+//
+// Tables are parsed concurrently, using up to GOMAXPROCS goroutines, so file must
+// support parallel calls to ReadAt (as required by io.ReaderAt).
 func StrictParse(file File) (*Font, error) {
-	font, err := Parse(file)
+	return StrictParseContext(context.Background(), file)
+}
+
+// StrictParseContext is like StrictParse, but stops parsing tables and returns
+// ctx.Err() if ctx is cancelled.
+func StrictParseContext(ctx context.Context, file File) (*Font, error) {
+	font, err := ParseContext(ctx, file)
 	if err != nil {
 		return nil, err
 	}
 
-	for _, tag := range font.Tags() {
-		if _, err := font.Table(tag); err != nil {
-			return nil, fmt.Errorf("failed to parse %q: %s", tag, err)
-		}
+	if err := font.parseTables(ctx, runtime.GOMAXPROCS(0)); err != nil {
+		return nil, err
 	}
 
 	return font, nil
 }
+
+// parseTables parses every table in the font that has not yet been parsed, using
+// up to workers goroutines. If any tables fail to parse, the error for the first
+// of them (by tag) is returned. If ctx is cancelled, the remaining tables are
+// skipped and ctx.Err() is returned.
+func (font *Font) parseTables(ctx context.Context, workers int) error {
+	tags := font.Tags()
+	errs := make([]error, len(tags))
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < workers && i < len(tags); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				s, found := font.section(tags[i])
+				if !found || ctx.Err() != nil {
+					continue
+				}
+				_, errs[i] = font.sectionTable(s)
+			}
+		}()
+	}
+	for i := range tags {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	for i, err := range errs {
+		if err != nil {
+			return fmt.Errorf("failed to parse %q: %s", tags[i], err)
+		}
+	}
+	return nil
+}
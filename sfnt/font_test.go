@@ -2,9 +2,12 @@ package sfnt
 
 import (
 	"bytes"
+	"context"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 )
 
@@ -43,6 +46,79 @@ func TestSmokeTest(t *testing.T) {
 	}
 }
 
+func TestParseTablesReportsFirstError(t *testing.T) {
+	for _, tag := range []Tag{TagName, TagPost} {
+		original := tableParserFor(tag)
+		defer RegisterTableParser(tag, original)
+		tag := tag
+		RegisterTableParser(tag, func(Tag, []byte) (Table, error) {
+			return nil, fmt.Errorf("bad %s", tag)
+		})
+	}
+
+	for _, workers := range []int{1, 2, 8} {
+		font := parseTestFont(t, "Roboto-BoldItalic.ttf")
+		err := font.parseTables(context.Background(), workers)
+		if want := `failed to parse "name": bad name`; err == nil || err.Error() != want {
+			t.Errorf("parseTables(%d) = %v, want %s", workers, err, want)
+		}
+		if head, err := font.HeadTable(); err != nil || head.UnitsPerEm == 0 {
+			t.Errorf("parseTables(%d) did not parse the head table: %v", workers, err)
+		}
+	}
+}
+
+func TestStrictParseContextCancelled(t *testing.T) {
+	file, err := os.Open(filepath.Join("testdata", "Roboto-BoldItalic.ttf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := StrictParseContext(ctx, file); err != context.Canceled {
+		t.Errorf("StrictParseContext(cancelled) err = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestConcurrentReads(t *testing.T) {
+	font := parseTestFont(t, "open-sans-v15-latin-regular.woff")
+
+	const n = 8
+	glyfs := make([]*TableGlyf, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for _, tag := range font.Tags() {
+				if _, err := font.Table(tag); err != nil {
+					t.Errorf("Table(%q) err = %q, want nil", tag, err)
+				}
+			}
+			glyf, err := font.GlyfTable()
+			if err != nil {
+				t.Error(err)
+			}
+			glyfs[i] = glyf
+			if _, err := font.HmtxTable(); err != nil {
+				t.Error(err)
+			}
+			if _, err := font.WriteOTF(ioutil.Discard); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 1; i < n; i++ {
+		if glyfs[i] != glyfs[0] {
+			t.Fatalf("GlyfTable() returned different tables when called concurrently")
+		}
+	}
+}
+
 // benchmarkParse tests the performance of a simple Parse.
 // Example run:
 //   go test -cpuprofile cpu.prof -benchmem -memprofile mem.prof -bench . -run=^$ -benchtime=30s github.com/ConradIrwin/font/sfnt
@@ -108,3 +184,74 @@ func BenchmarkParseWOFF2(b *testing.B) {
 func BenchmarkStrictParseWOFF2(b *testing.B) {
 	benchmarkStrictParse(b, "Go-Regular.woff2")
 }
+
+func TestDirectory(t *testing.T) {
+	file, err := os.Open(filepath.Join("testdata", "open-sans-v15-latin-regular.woff"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	font, err := Parse(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	records := font.Directory()
+	if len(records) != len(font.Tags()) {
+		t.Fatalf("len(Directory()) = %d, want %d", len(records), len(font.Tags()))
+	}
+
+	for _, record := range records {
+		data, err := font.TableData(record.Tag)
+		if err != nil {
+			t.Errorf("TableData(%q) err = %q, want nil", record.Tag, err)
+			continue
+		}
+		if len(data) != int(record.Length) {
+			t.Errorf("len(TableData(%q)) = %d, want %d", record.Tag, len(data), record.Length)
+		}
+		if record.Tag != TagHead && checkSum(data) != record.CheckSum {
+			t.Errorf("checkSum(TableData(%q)) = %x, want %x", record.Tag, checkSum(data), record.CheckSum)
+		}
+	}
+
+	if _, err := font.TableData(MustNamedTag("zzzz")); err != ErrMissingTable {
+		t.Errorf("TableData(zzzz) err = %v, want ErrMissingTable", err)
+	}
+}
+
+func TestSetTable(t *testing.T) {
+	file, err := os.Open(filepath.Join("testdata", "Roboto-BoldItalic.ttf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	font, err := Parse(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tag := MustNamedTag("TSIV")
+	data := []byte("vendor specific data")
+	font.SetTable(tag, data)
+
+	var buf bytes.Buffer
+	if _, err := font.WriteOTF(&buf); err != nil {
+		t.Fatalf("WriteOTF() err = %q, want nil", err)
+	}
+
+	font, err = StrictParse(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("StrictParse() err = %q, want nil", err)
+	}
+
+	got, err := font.TableData(tag)
+	if err != nil {
+		t.Fatalf("TableData(%q) err = %q, want nil", tag, err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("TableData(%q) = %q, want %q", tag, got, data)
+	}
+}
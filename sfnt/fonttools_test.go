@@ -0,0 +1,361 @@
+//go:build fonttools
+// +build fonttools
+
+package sfnt
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// The tests in this file compare the tables parsed by this package with those
+// dumped by fontTools' ttx, which must be installed (or given by $TTX), for
+// each font in $FONTTOOLS_CORPUS (testdata by default). Each font is also
+// written with WriteOTF and dumped again, to check that nothing changed. Run
+// them with:
+//
+//	FONTTOOLS_CORPUS=~/fonts go test -tags fonttools -run TestFontTools ./sfnt
+
+// ttxTables are the tables dumped by ttx and compared.
+var ttxTables = []string{"head", "hhea", "maxp", "OS/2", "name", "post", "cmap", "hmtx"}
+
+// ttxNode is an element of the XML written by ttx.
+type ttxNode struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr `xml:",any,attr"`
+	Text     string     `xml:",chardata"`
+	Children []ttxNode  `xml:",any"`
+}
+
+// attr returns the value of the named attribute, or "" if there is none.
+func (n ttxNode) attr(name string) string {
+	for _, a := range n.Attrs {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// child returns the first child element with the given name.
+func (n ttxNode) child(name string) (ttxNode, bool) {
+	for _, c := range n.Children {
+		if c.XMLName.Local == name {
+			return c, true
+		}
+	}
+	return ttxNode{}, false
+}
+
+// ttxDump runs ttx on the font file, and returns the parsed XML.
+func ttxDump(t *testing.T, path string) ttxNode {
+	t.Helper()
+	ttx := os.Getenv("TTX")
+	if ttx == "" {
+		ttx = "ttx"
+	}
+	if _, err := exec.LookPath(ttx); err != nil {
+		t.Skipf("ttx not found: %s", err)
+	}
+
+	out := filepath.Join(t.TempDir(), "font.ttx")
+	args := []string{"-q", "-o", out}
+	for _, tag := range ttxTables {
+		args = append(args, "-t", tag)
+	}
+	var stderr bytes.Buffer
+	cmd := exec.Command(ttx, append(args, path)...)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if strings.Contains(stderr.String(), "brotli") {
+			t.Skipf("ttx %s needs the brotli module: %s", path, err)
+		}
+		t.Fatalf("ttx %s: %s: %s", path, err, stderr.String())
+	}
+
+	data, err := ioutil.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var root ttxNode
+	if err := xml.Unmarshal(data, &root); err != nil {
+		t.Fatalf("reading the output of ttx %s: %s", path, err)
+	}
+	return root
+}
+
+// ttxBinary formats flags as ttx does, for example "00000000 00000011".
+func ttxBinary(v uint16) string {
+	return fmt.Sprintf("%08b %08b", v>>8, v&0xFF)
+}
+
+// ttxText normalizes the whitespace of text, as ttx indents multi-line text.
+func ttxText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// compareTTX checks that the tables dumped by ttx have the same values as those
+// parsed from font.
+func compareTTX(t *testing.T, font *Font, root ttxNode) {
+	t.Helper()
+	glyphIDs := map[string]uint16{}
+	if order, found := root.child("GlyphOrder"); found {
+		for _, g := range order.Children {
+			id, err := strconv.Atoi(g.attr("id"))
+			if err != nil {
+				t.Fatalf("GlyphOrder: invalid id %q", g.attr("id"))
+			}
+			glyphIDs[g.attr("name")] = uint16(id)
+		}
+	}
+
+	// compareValues checks the value attribute of each child of a table.
+	compareValues := func(table string, want map[string]string) {
+		t.Helper()
+		node, found := root.child(table)
+		if !found {
+			t.Errorf("ttx did not dump %s", table)
+			return
+		}
+		for field, value := range want {
+			child, found := node.child(field)
+			if !found {
+				continue // The field is not in this version of the table.
+			}
+			if got := strings.TrimRight(child.attr("value"), " "); got != strings.TrimRight(value, " ") {
+				t.Errorf("%s.%s: ttx has %q, sfnt has %q", table, field, got, value)
+			}
+		}
+	}
+
+	head, err := font.HeadTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	compareValues("head", map[string]string{
+		"unitsPerEm":    strconv.Itoa(int(head.UnitsPerEm)),
+		"xMin":          strconv.Itoa(int(head.XMin)),
+		"yMin":          strconv.Itoa(int(head.YMin)),
+		"xMax":          strconv.Itoa(int(head.XMax)),
+		"yMax":          strconv.Itoa(int(head.YMax)),
+		"macStyle":      ttxBinary(head.MacStyle),
+		"lowestRecPPEM": strconv.Itoa(int(head.LowestRecPPEM)),
+	})
+
+	hhea, err := font.HheaTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	compareValues("hhea", map[string]string{
+		"ascent":          strconv.Itoa(int(hhea.Ascent)),
+		"descent":         strconv.Itoa(int(hhea.Descent)),
+		"lineGap":         strconv.Itoa(int(hhea.LineGap)),
+		"advanceWidthMax": strconv.Itoa(int(hhea.AdvanceWidthMax)),
+	})
+
+	maxp, err := font.MaxpTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	compareValues("maxp", map[string]string{"numGlyphs": strconv.Itoa(int(maxp.NumGlyphs))})
+	if len(glyphIDs) != int(maxp.NumGlyphs) {
+		t.Errorf("GlyphOrder: ttx has %d glyphs, sfnt has %d", len(glyphIDs), maxp.NumGlyphs)
+	}
+
+	if font.HasTable(TagOS2) {
+		os2, err := font.OS2Table()
+		if err != nil {
+			t.Fatal(err)
+		}
+		compareValues("OS_2", map[string]string{
+			"usWeightClass":  strconv.Itoa(int(os2.USWeightClass)),
+			"usWidthClass":   strconv.Itoa(int(os2.USWidthClass)),
+			"fsType":         ttxBinary(os2.FSType),
+			"achVendID":      os2.AchVendID.String(),
+			"fsSelection":    ttxBinary(os2.FsSelection),
+			"sTypoAscender":  strconv.Itoa(int(os2.STypoAscender)),
+			"sTypoDescender": strconv.Itoa(int(os2.STypoDescender)),
+			"sTypoLineGap":   strconv.Itoa(int(os2.STypoLineGap)),
+			"usWinAscent":    strconv.Itoa(int(os2.UsWinAscent)),
+			"usWinDescent":   strconv.Itoa(int(os2.UsWinDescent)),
+		})
+	}
+
+	if font.HasTable(TagPost) {
+		post, err := font.PostTable()
+		if err != nil {
+			t.Fatal(err)
+		}
+		compareValues("post", map[string]string{
+			"underlinePosition":  strconv.Itoa(int(post.UnderlinePosition)),
+			"underlineThickness": strconv.Itoa(int(post.UnderlineThickness)),
+			"isFixedPitch":       strconv.Itoa(int(post.IsFixedPitch)),
+		})
+	}
+
+	if font.HasTable(TagName) {
+		compareTTXNames(t, font, root)
+	}
+	compareTTXCmap(t, font, root, glyphIDs)
+
+	hmtx, err := font.HmtxTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if node, found := root.child("hmtx"); found {
+		for _, mtx := range node.Children {
+			gid, found := glyphIDs[mtx.attr("name")]
+			if !found || int(gid) >= len(hmtx.Metrics) {
+				t.Errorf("hmtx: ttx has a metric for %q, which sfnt does not have", mtx.attr("name"))
+				continue
+			}
+			m := hmtx.Metrics[gid]
+			if want := fmt.Sprintf("%d %d", m.AdvanceWidth, m.LeftSideBearing); mtx.attr("width")+" "+mtx.attr("lsb") != want {
+				t.Errorf("hmtx %q: ttx has width and lsb %s %s, sfnt has %s", mtx.attr("name"), mtx.attr("width"), mtx.attr("lsb"), want)
+			}
+		}
+	}
+}
+
+// compareTTXNames checks the Unicode entries of the 'name' table, which ttx
+// and this package both decode.
+func compareTTXNames(t *testing.T, font *Font, root ttxNode) {
+	t.Helper()
+	name, err := font.NameTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ours := map[nameKey]string{}
+	for _, entry := range name.List() {
+		if entry.isUTF16() {
+			ours[nameKey{entry.PlatformID, entry.EncodingID, entry.LanguageID, entry.NameID}] = ttxText(entry.String())
+		}
+	}
+
+	node, _ := root.child("name")
+	theirs := 0
+	for _, record := range node.Children {
+		var ids [4]uint64
+		for i, attr := range []string{"platformID", "platEncID", "langID", "nameID"} {
+			if ids[i], err = strconv.ParseUint(record.attr(attr), 0, 16); err != nil {
+				t.Fatalf("name: invalid %s %q", attr, record.attr(attr))
+			}
+		}
+		key := nameKey{PlatformID(ids[0]), PlatformEncodingID(ids[1]), PlatformLanguageID(ids[2]), NameID(ids[3])}
+		value, found := ours[key]
+		if !found {
+			continue // A legacy encoding, decoded only by ttx.
+		}
+		theirs++
+		if got := ttxText(record.Text); got != value {
+			t.Errorf("name %+v: ttx has %q, sfnt has %q", key, got, value)
+		}
+	}
+	if theirs != len(ours) {
+		t.Errorf("name: ttx has %d Unicode entries, sfnt has %d", theirs, len(ours))
+	}
+}
+
+// compareTTXCmap checks the mapping of each 'cmap' subtable that this package
+// can parse.
+func compareTTXCmap(t *testing.T, font *Font, root ttxNode, glyphIDs map[string]uint16) {
+	t.Helper()
+	cmap, err := font.CmapTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	node, _ := root.child("cmap")
+	for _, subtable := range node.Children {
+		format := strings.TrimPrefix(subtable.XMLName.Local, "cmap_format_")
+		if format == subtable.XMLName.Local || format == "14" {
+			continue // The table version, or variation sequences.
+		}
+		var ours *CmapSubtable
+		for _, s := range cmap.Subtables {
+			if strconv.Itoa(int(s.Format)) == format && strconv.Itoa(int(s.PlatformID)) == subtable.attr("platformID") && strconv.Itoa(int(s.EncodingID)) == subtable.attr("platEncID") {
+				ours = s
+			}
+		}
+		if ours == nil {
+			t.Errorf("cmap: ttx has a format %s subtable for platform %s encoding %s, which sfnt does not have", format, subtable.attr("platformID"), subtable.attr("platEncID"))
+			continue
+		}
+		if ours.Mapping == nil {
+			continue
+		}
+
+		theirs := 0
+		for _, m := range subtable.Children {
+			if m.XMLName.Local != "map" {
+				continue
+			}
+			code, err := strconv.ParseUint(m.attr("code"), 0, 32)
+			if err != nil {
+				t.Fatalf("cmap: invalid code %q", m.attr("code"))
+			}
+			theirs++
+			if gid, found := ours.Mapping[rune(code)]; !found || gid != glyphIDs[m.attr("name")] {
+				t.Errorf("cmap %d/%d: ttx maps U+%04X to %q (glyph %d), sfnt to glyph %d", ours.PlatformID, ours.EncodingID, code, m.attr("name"), glyphIDs[m.attr("name")], gid)
+			}
+		}
+		if theirs != len(ours.Mapping) {
+			t.Errorf("cmap %d/%d: ttx maps %d codes, sfnt %d", ours.PlatformID, ours.EncodingID, theirs, len(ours.Mapping))
+		}
+	}
+}
+
+func TestFontTools(t *testing.T) {
+	corpus := os.Getenv("FONTTOOLS_CORPUS")
+	if corpus == "" {
+		corpus = "testdata"
+	}
+	var paths []string
+	err := filepath.Walk(corpus, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".ttf", ".otf", ".woff", ".woff2":
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range paths {
+		path := path
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			file, err := os.Open(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer file.Close()
+			font, err := Parse(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			compareTTX(t, font, ttxDump(t, path))
+
+			// The written font must have the same values as the original.
+			written := filepath.Join(t.TempDir(), "written"+font.Format().Extension())
+			var buf bytes.Buffer
+			if _, err := font.WriteOTF(&buf); err != nil {
+				t.Fatal(err)
+			}
+			if err := ioutil.WriteFile(written, buf.Bytes(), 0644); err != nil {
+				t.Fatal(err)
+			}
+			compareTTX(t, font, ttxDump(t, written))
+		})
+	}
+}
@@ -0,0 +1,147 @@
+//go:build go1.18
+// +build go1.18
+
+package sfnt
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+// The fuzz targets in this file are seeded with the fonts in testdata and the
+// malformed fonts in fuzz/corpus. Without -fuzz they only run the seeds; to
+// look for new crashes, run for example:
+//
+//	go test -run '^$' -fuzz FuzzParse -fuzztime 10m ./sfnt
+//
+// Inputs that fail are saved in testdata/fuzz, and should be committed with
+// the fix so that they are checked from then on.
+
+// fuzzSeeds returns the contents of the named files in testdata, and of every
+// file in fuzz/corpus.
+func fuzzSeeds(f *testing.F, names ...string) [][]byte {
+	f.Helper()
+	paths, err := filepath.Glob(filepath.Join("fuzz", "corpus", "*"))
+	if err != nil {
+		f.Fatal(err)
+	}
+	for _, name := range names {
+		paths = append(paths, filepath.Join("testdata", name))
+	}
+	var seeds [][]byte
+	for _, path := range paths {
+		seeds = append(seeds, readFuzzSeed(f, path))
+	}
+	return seeds
+}
+
+func readFuzzSeed(f *testing.F, path string) []byte {
+	f.Helper()
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		f.Fatal(err)
+	}
+	return data
+}
+
+// failOnPanic fails the test if err is a *PanicError. Malformed inputs are
+// expected to return errors, but a panic, even one that is recovered, is a bug.
+func failOnPanic(t *testing.T, err error) {
+	t.Helper()
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		t.Fatalf("%s\n%s", panicErr, panicErr.Stack)
+	}
+}
+
+// FuzzParse parses fonts in every format, and then each of their tables.
+func FuzzParse(f *testing.F) {
+	for _, seed := range fuzzSeeds(f, "Roboto-BoldItalic.ttf", "Raleway-v4020-Regular.otf", "open-sans-v15-latin-regular.woff") {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		font, err := Parse(bytes.NewReader(data))
+		if err != nil {
+			failOnPanic(t, err)
+			return
+		}
+		for _, tag := range font.Tags() {
+			_, err := font.Table(tag)
+			failOnPanic(t, err)
+		}
+	})
+}
+
+// FuzzWOFF2 parses WOFF2 fonts, which are decompressed and have their 'glyf'
+// and 'loca' tables reconstructed.
+func FuzzWOFF2(f *testing.F) {
+	for _, seed := range fuzzSeeds(f, "Go-Regular.woff2") {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		font, err := parseWOFF2(bytes.NewReader(data))
+		if err != nil {
+			failOnPanic(t, err)
+			return
+		}
+		for _, tag := range font.Tags() {
+			_, err := font.Table(tag)
+			failOnPanic(t, err)
+		}
+	})
+}
+
+// FuzzCmap parses 'cmap' tables, and looks up characters in them.
+func FuzzCmap(f *testing.F) {
+	for _, name := range []string{"Roboto-BoldItalic.ttf", "Raleway-v4020-Regular.otf"} {
+		font, err := Parse(bytes.NewReader(readFuzzSeed(f, filepath.Join("testdata", name))))
+		if err != nil {
+			f.Fatal(err)
+		}
+		data, err := font.TableData(TagCmap)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		table, err := parseTableCmap(TagCmap, data)
+		if err != nil {
+			failOnPanic(t, err)
+			return
+		}
+		cmap := table.(*TableCmap)
+		for _, r := range []rune{0, ' ', 'A', 'é', 0xF020, 0x1F600} {
+			cmap.Lookup(r)
+		}
+		cmap.Bytes()
+	})
+}
+
+// FuzzSubset subsets fonts to the glyphs for some text, and writes them.
+func FuzzSubset(f *testing.F) {
+	for _, seed := range fuzzSeeds(f, "Roboto-BoldItalic.ttf") {
+		f.Add(seed, "Hello, World!")
+	}
+	f.Fuzz(func(t *testing.T, data []byte, text string) {
+		font, err := Parse(bytes.NewReader(data))
+		if err != nil {
+			failOnPanic(t, err)
+			return
+		}
+		gids, err := font.GlyphsForRunes([]rune(text))
+		if err != nil {
+			failOnPanic(t, err)
+			return
+		}
+		if err := font.SubsetGlyphs(gids); err != nil {
+			failOnPanic(t, err)
+			return
+		}
+		_, err = font.WriteOTF(ioutil.Discard)
+		failOnPanic(t, err)
+	})
+}
@@ -0,0 +1,517 @@
+package sfnt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+// Flags used by the points of a simple glyph.
+const (
+	glyfOnCurve       = 0x01
+	glyfXShort        = 0x02
+	glyfYShort        = 0x04
+	glyfRepeat        = 0x08
+	glyfXSameOrPlus   = 0x10
+	glyfYSameOrPlus   = 0x20
+	glyfArgsAreXY     = 0x0002
+	glyfInstructions  = 0x0100
+	maxComponentDepth = 16
+)
+
+// Glyph is a decoded TrueType glyph. A glyph either has Contours (a simple glyph)
+// or Components (a composite glyph), or neither (an empty glyph, such as space).
+type Glyph struct {
+	XMin, YMin, XMax, YMax int16
+
+	// Contours contains the points of each closed contour in a simple glyph.
+	Contours [][]GlyphPoint
+	// Components contains the glyphs that make up a composite glyph.
+	Components []GlyphComponent
+	// Instructions contains the TrueType hinting instructions for the glyph.
+	Instructions []byte
+}
+
+// GlyphPoint is a point on a TrueType contour. Consecutive off-curve points
+// have an implied on-curve point midway between them.
+type GlyphPoint struct {
+	X, Y    int16
+	OnCurve bool
+}
+
+// GlyphComponent is a reference from a composite glyph to another glyph.
+type GlyphComponent struct {
+	GlyphID uint16
+	// Flags are the component flags, as stored in the font.
+	Flags uint16
+	// Arg1 and Arg2 are the x and y offset of the component when the
+	// ARGS_ARE_XY_VALUES flag is set, and point numbers to align otherwise.
+	Arg1, Arg2 int16
+	// Transform is the 2x2 matrix (xx, xy, yx, yy) applied to the component.
+	Transform [4]float64
+}
+
+// IsEmpty returns true if the glyph has no outline.
+func (g *Glyph) IsEmpty() bool {
+	return len(g.Contours) == 0 && len(g.Components) == 0
+}
+
+// NumPoints returns the number of points in a simple glyph.
+func (g *Glyph) NumPoints() int {
+	n := 0
+	for _, contour := range g.Contours {
+		n += len(contour)
+	}
+	return n
+}
+
+// Glyph decodes the glyph with the given id.
+func (table *TableGlyf) Glyph(gid uint16) (*Glyph, error) {
+	if int(gid) >= len(table.Glyphs) {
+		return nil, fmt.Errorf("glyph %d is out of range (font has %d glyphs)", gid, len(table.Glyphs))
+	}
+	glyph, err := parseGlyph(table.Glyphs[gid])
+	if err != nil {
+		return nil, fmt.Errorf("glyph %d: %s", gid, err)
+	}
+	return glyph, nil
+}
+
+// SetGlyph replaces the glyph with the given id.
+func (table *TableGlyf) SetGlyph(gid uint16, glyph *Glyph) error {
+	if int(gid) >= len(table.Glyphs) {
+		return fmt.Errorf("glyph %d is out of range (font has %d glyphs)", gid, len(table.Glyphs))
+	}
+	table.Glyphs[gid] = glyph.Bytes()
+	return nil
+}
+
+// Contours returns the contours of the glyph with the given id, with the
+// components of composite glyphs resolved and transformed into place.
+// Components positioned by aligning points are moved so that their point lies
+// on the point of the components before them.
+func (table *TableGlyf) Contours(gid uint16) ([][]GlyphPoint, error) {
+	return table.contours(gid, 0)
+}
+
+func (table *TableGlyf) contours(gid uint16, depth int) ([][]GlyphPoint, error) {
+	if depth > maxComponentDepth {
+		return nil, fmt.Errorf("glyph %d: components are nested too deeply", gid)
+	}
+	glyph, err := table.Glyph(gid)
+	if err != nil {
+		return nil, err
+	}
+	if len(glyph.Components) == 0 {
+		return glyph.Contours, nil
+	}
+
+	var contours [][]GlyphPoint
+	for _, c := range glyph.Components {
+		parts, err := table.contours(c.GlyphID, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		dx, dy := int(c.Arg1), int(c.Arg2)
+		if c.Flags&glyfArgsAreXY == 0 {
+			dx, dy = 0, 0
+		}
+		var added [][]GlyphPoint
+		for _, part := range parts {
+			contour := make([]GlyphPoint, len(part))
+			for i, p := range part {
+				x, y := float64(p.X), float64(p.Y)
+				contour[i] = GlyphPoint{
+					X:       int16(math.Round(x*c.Transform[0] + y*c.Transform[2] + float64(dx))),
+					Y:       int16(math.Round(x*c.Transform[1] + y*c.Transform[3] + float64(dy))),
+					OnCurve: p.OnCurve,
+				}
+			}
+			added = append(added, contour)
+		}
+
+		if c.Flags&glyfArgsAreXY == 0 {
+			// Arg1 is the number of a point in the contours so far, and Arg2 is
+			// the number of a point in the component, counting across contours.
+			parent, found := contourPoint(contours, int(uint16(c.Arg1)))
+			child, childFound := contourPoint(added, int(uint16(c.Arg2)))
+			if !found || !childFound {
+				return nil, fmt.Errorf("glyph %d: component %d is aligned by a point that does not exist", gid, c.GlyphID)
+			}
+			dx, dy := parent.X-child.X, parent.Y-child.Y
+			for _, contour := range added {
+				for i := range contour {
+					contour[i].X += dx
+					contour[i].Y += dy
+				}
+			}
+		}
+		contours = append(contours, added...)
+	}
+	return contours, nil
+}
+
+// contourPoint returns the point with the given number in contours, counting
+// across contours, or false if there is no such point.
+func contourPoint(contours [][]GlyphPoint, n int) (GlyphPoint, bool) {
+	for _, contour := range contours {
+		if n < len(contour) {
+			return contour[n], true
+		}
+		n -= len(contour)
+	}
+	return GlyphPoint{}, false
+}
+
+type glyphHeader struct {
+	NumberOfContours       int16
+	XMin, YMin, XMax, YMax int16
+}
+
+func parseGlyph(data []byte) (*Glyph, error) {
+	if len(data) == 0 {
+		return &Glyph{}, nil
+	}
+
+	r := bytes.NewReader(data)
+	var header glyphHeader
+	if err := binary.Read(r, binary.BigEndian, &header); err != nil {
+		return nil, errInvalidGlyph
+	}
+	glyph := &Glyph{XMin: header.XMin, YMin: header.YMin, XMax: header.XMax, YMax: header.YMax}
+
+	var err error
+	if header.NumberOfContours >= 0 {
+		err = glyph.parseSimple(r, int(header.NumberOfContours))
+	} else {
+		err = glyph.parseComposite(r)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return glyph, nil
+}
+
+func (glyph *Glyph) parseSimple(r *bytes.Reader, numberOfContours int) error {
+	endPts := make([]uint16, numberOfContours)
+	if err := binary.Read(r, binary.BigEndian, endPts); err != nil {
+		return errInvalidGlyph
+	}
+	var instructionLength uint16
+	if err := binary.Read(r, binary.BigEndian, &instructionLength); err != nil {
+		return errInvalidGlyph
+	}
+	glyph.Instructions = make([]byte, instructionLength)
+	if _, err := io.ReadFull(r, glyph.Instructions); err != nil {
+		return errInvalidGlyph
+	}
+
+	numPoints := 0
+	for i, end := range endPts {
+		if int(end) < numPoints-1 || i > 0 && end <= endPts[i-1] {
+			return fmt.Errorf("contour %d ends at point %d before it starts", i, end)
+		}
+		numPoints = int(end) + 1
+	}
+
+	flags := make([]byte, 0, numPoints)
+	for len(flags) < numPoints {
+		flag, err := r.ReadByte()
+		if err != nil {
+			return errInvalidGlyph
+		}
+		flags = append(flags, flag)
+		if flag&glyfRepeat != 0 {
+			count, err := r.ReadByte()
+			if err != nil {
+				return errInvalidGlyph
+			}
+			for i := 0; i < int(count) && len(flags) < numPoints; i++ {
+				flags = append(flags, flag)
+			}
+		}
+	}
+
+	readCoordinates := func(short, sameOrPlus byte) ([]int16, error) {
+		coordinates := make([]int16, numPoints)
+		var v int16
+		for i, flag := range flags {
+			switch {
+			case flag&short != 0:
+				b, err := r.ReadByte()
+				if err != nil {
+					return nil, errInvalidGlyph
+				}
+				if flag&sameOrPlus != 0 {
+					v += int16(b)
+				} else {
+					v -= int16(b)
+				}
+			case flag&sameOrPlus == 0:
+				var delta int16
+				if err := binary.Read(r, binary.BigEndian, &delta); err != nil {
+					return nil, errInvalidGlyph
+				}
+				v += delta
+			}
+			coordinates[i] = v
+		}
+		return coordinates, nil
+	}
+
+	xs, err := readCoordinates(glyfXShort, glyfXSameOrPlus)
+	if err != nil {
+		return err
+	}
+	ys, err := readCoordinates(glyfYShort, glyfYSameOrPlus)
+	if err != nil {
+		return err
+	}
+
+	start := 0
+	for _, end := range endPts {
+		contour := make([]GlyphPoint, 0, int(end)+1-start)
+		for i := start; i <= int(end); i++ {
+			contour = append(contour, GlyphPoint{X: xs[i], Y: ys[i], OnCurve: flags[i]&glyfOnCurve != 0})
+		}
+		glyph.Contours = append(glyph.Contours, contour)
+		start = int(end) + 1
+	}
+	return nil
+}
+
+func (glyph *Glyph) parseComposite(r *bytes.Reader) error {
+	for {
+		var header struct {
+			Flags   uint16
+			GlyphID uint16
+		}
+		if err := binary.Read(r, binary.BigEndian, &header); err != nil {
+			return errInvalidGlyph
+		}
+		c := GlyphComponent{GlyphID: header.GlyphID, Flags: header.Flags, Transform: [4]float64{1, 0, 0, 1}}
+
+		if c.Flags&glyfArg1And2AreWords != 0 {
+			var args [2]int16
+			if err := binary.Read(r, binary.BigEndian, &args); err != nil {
+				return errInvalidGlyph
+			}
+			c.Arg1, c.Arg2 = args[0], args[1]
+			if c.Flags&glyfArgsAreXY == 0 {
+				c.Arg1, c.Arg2 = int16(uint16(args[0])), int16(uint16(args[1]))
+			}
+		} else {
+			var args [2]byte
+			if _, err := io.ReadFull(r, args[:]); err != nil {
+				return errInvalidGlyph
+			}
+			if c.Flags&glyfArgsAreXY != 0 {
+				c.Arg1, c.Arg2 = int16(int8(args[0])), int16(int8(args[1]))
+			} else {
+				c.Arg1, c.Arg2 = int16(args[0]), int16(args[1])
+			}
+		}
+
+		var scale []int16
+		switch {
+		case c.Flags&glyfWeHaveAScale != 0:
+			scale = make([]int16, 1)
+		case c.Flags&glyfWeHaveAnXAndYScale != 0:
+			scale = make([]int16, 2)
+		case c.Flags&glyfWeHaveATwoByTwo != 0:
+			scale = make([]int16, 4)
+		}
+		if err := binary.Read(r, binary.BigEndian, scale); err != nil {
+			return errInvalidGlyph
+		}
+		switch len(scale) {
+		case 1:
+			c.Transform[0], c.Transform[3] = f2dot14(scale[0]), f2dot14(scale[0])
+		case 2:
+			c.Transform[0], c.Transform[3] = f2dot14(scale[0]), f2dot14(scale[1])
+		case 4:
+			for i, s := range scale {
+				c.Transform[i] = f2dot14(s)
+			}
+		}
+
+		glyph.Components = append(glyph.Components, c)
+		if c.Flags&glyfMoreComponents == 0 {
+			break
+		}
+	}
+
+	if glyph.Components[len(glyph.Components)-1].Flags&glyfInstructions != 0 {
+		var instructionLength uint16
+		if err := binary.Read(r, binary.BigEndian, &instructionLength); err != nil {
+			return errInvalidGlyph
+		}
+		glyph.Instructions = make([]byte, instructionLength)
+		if _, err := io.ReadFull(r, glyph.Instructions); err != nil {
+			return errInvalidGlyph
+		}
+	}
+	return nil
+}
+
+// f2dot14 converts a 2.14 fixed point number to a float.
+func f2dot14(v int16) float64 {
+	return float64(v) / (1 << 14)
+}
+
+// toF2dot14 converts a float to a 2.14 fixed point number.
+func toF2dot14(f float64) int16 {
+	return int16(math.Round(f * (1 << 14)))
+}
+
+// Bytes returns the encoded form of the glyph, as stored in the 'glyf' table.
+// The bounding box is written as is, use UpdateBounds to recalculate it.
+func (g *Glyph) Bytes() []byte {
+	if g.IsEmpty() {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	if len(g.Components) > 0 {
+		binary.Write(&buf, binary.BigEndian, glyphHeader{-1, g.XMin, g.YMin, g.XMax, g.YMax})
+		g.writeComposite(&buf)
+	} else {
+		binary.Write(&buf, binary.BigEndian, glyphHeader{int16(len(g.Contours)), g.XMin, g.YMin, g.XMax, g.YMax})
+		g.writeSimple(&buf)
+	}
+	return buf.Bytes()
+}
+
+func (g *Glyph) writeSimple(buf *bytes.Buffer) {
+	end := -1
+	for _, contour := range g.Contours {
+		end += len(contour)
+		binary.Write(buf, binary.BigEndian, uint16(end))
+	}
+	binary.Write(buf, binary.BigEndian, uint16(len(g.Instructions)))
+	buf.Write(g.Instructions)
+
+	var flags []byte
+	var xs, ys bytes.Buffer
+	var lastX, lastY int16
+
+	encode := func(delta int16, short, sameOrPlus byte, out *bytes.Buffer) byte {
+		switch {
+		case delta == 0:
+			return sameOrPlus
+		case delta > -256 && delta < 256:
+			if delta > 0 {
+				out.WriteByte(byte(delta))
+				return short | sameOrPlus
+			}
+			out.WriteByte(byte(-delta))
+			return short
+		default:
+			binary.Write(out, binary.BigEndian, delta)
+			return 0
+		}
+	}
+
+	for _, contour := range g.Contours {
+		for _, p := range contour {
+			var flag byte
+			if p.OnCurve {
+				flag |= glyfOnCurve
+			}
+			flag |= encode(p.X-lastX, glyfXShort, glyfXSameOrPlus, &xs)
+			flag |= encode(p.Y-lastY, glyfYShort, glyfYSameOrPlus, &ys)
+			lastX, lastY = p.X, p.Y
+			flags = append(flags, flag)
+		}
+	}
+
+	for i := 0; i < len(flags); {
+		repeat := 0
+		for i+repeat+1 < len(flags) && flags[i+repeat+1] == flags[i] && repeat < 255 {
+			repeat++
+		}
+		if repeat > 1 {
+			buf.WriteByte(flags[i] | glyfRepeat)
+			buf.WriteByte(byte(repeat))
+		} else {
+			buf.WriteByte(flags[i])
+			repeat = 0
+		}
+		i += repeat + 1
+	}
+
+	buf.Write(xs.Bytes())
+	buf.Write(ys.Bytes())
+}
+
+func (g *Glyph) writeComposite(buf *bytes.Buffer) {
+	for i, c := range g.Components {
+		flags := c.Flags &^ (glyfArg1And2AreWords | glyfWeHaveAScale | glyfWeHaveAnXAndYScale | glyfWeHaveATwoByTwo | glyfMoreComponents | glyfInstructions)
+		if i < len(g.Components)-1 {
+			flags |= glyfMoreComponents
+		} else if len(g.Instructions) > 0 {
+			flags |= glyfInstructions
+		}
+
+		words := c.Arg1 < -128 || c.Arg1 > 127 || c.Arg2 < -128 || c.Arg2 > 127
+		if flags&glyfArgsAreXY == 0 {
+			words = c.Arg1 < 0 || c.Arg1 > 255 || c.Arg2 < 0 || c.Arg2 > 255
+		}
+		if words {
+			flags |= glyfArg1And2AreWords
+		}
+
+		t := c.Transform
+		var scale []int16
+		switch {
+		case t[1] != 0 || t[2] != 0:
+			flags |= glyfWeHaveATwoByTwo
+			scale = []int16{toF2dot14(t[0]), toF2dot14(t[1]), toF2dot14(t[2]), toF2dot14(t[3])}
+		case t[0] != t[3]:
+			flags |= glyfWeHaveAnXAndYScale
+			scale = []int16{toF2dot14(t[0]), toF2dot14(t[3])}
+		case t[0] != 1:
+			flags |= glyfWeHaveAScale
+			scale = []int16{toF2dot14(t[0])}
+		}
+
+		binary.Write(buf, binary.BigEndian, flags)
+		binary.Write(buf, binary.BigEndian, c.GlyphID)
+		if words {
+			binary.Write(buf, binary.BigEndian, [2]int16{c.Arg1, c.Arg2})
+		} else {
+			buf.Write([]byte{byte(c.Arg1), byte(c.Arg2)})
+		}
+		binary.Write(buf, binary.BigEndian, scale)
+	}
+
+	if len(g.Instructions) > 0 {
+		binary.Write(buf, binary.BigEndian, uint16(len(g.Instructions)))
+		buf.Write(g.Instructions)
+	}
+}
+
+// UpdateBounds recalculates the bounding box of a simple glyph from its points.
+func (g *Glyph) UpdateBounds() {
+	g.XMin, g.YMin, g.XMax, g.YMax = 0, 0, 0, 0
+	first := true
+	for _, contour := range g.Contours {
+		for _, p := range contour {
+			if first || p.X < g.XMin {
+				g.XMin = p.X
+			}
+			if first || p.Y < g.YMin {
+				g.YMin = p.Y
+			}
+			if first || p.X > g.XMax {
+				g.XMax = p.X
+			}
+			if first || p.Y > g.YMax {
+				g.YMax = p.Y
+			}
+			first = false
+		}
+	}
+}
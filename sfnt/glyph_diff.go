@@ -0,0 +1,220 @@
+package sfnt
+
+import (
+	"math"
+)
+
+// curveSamples is the number of line segments each curve is split into when
+// outlines are compared by sampling.
+const curveSamples = 8
+
+// OutlineDifference describes a glyph whose outline differs between two fonts.
+type OutlineDifference struct {
+	GlyphID uint16
+	// Distance is the largest distance between the two outlines in font units.
+	// It is +Inf if the glyph only exists in one font, or if one outline is empty.
+	Distance float64
+}
+
+// CompareOutlines compares the outline of each glyph in a with the glyph with the
+// same id in b, and returns the glyphs that differ by more than tolerance font units.
+//
+// Outlines with the same number of points in each contour are compared point by
+// point. Other outlines (for example where a conversion has added or removed
+// implied on-curve points) are compared by sampling both outlines, and measuring
+// the largest distance from a sample on either outline to the other.
+func CompareOutlines(a, b *Font, tolerance float64) ([]OutlineDifference, error) {
+	glyfA, err := a.GlyfTable()
+	if err != nil {
+		return nil, err
+	}
+	glyfB, err := b.GlyfTable()
+	if err != nil {
+		return nil, err
+	}
+
+	var differences []OutlineDifference
+	for i := 0; i < glyfA.NumGlyphs() || i < glyfB.NumGlyphs(); i++ {
+		gid := uint16(i)
+		if i >= glyfA.NumGlyphs() || i >= glyfB.NumGlyphs() {
+			differences = append(differences, OutlineDifference{gid, math.Inf(1)})
+			continue
+		}
+
+		contoursA, err := glyfA.Contours(gid)
+		if err != nil {
+			return nil, err
+		}
+		contoursB, err := glyfB.Contours(gid)
+		if err != nil {
+			return nil, err
+		}
+
+		if distance := outlineDistance(contoursA, contoursB); distance > tolerance {
+			differences = append(differences, OutlineDifference{gid, distance})
+		}
+	}
+	return differences, nil
+}
+
+// outlineDistance returns the largest distance between two outlines.
+func outlineDistance(a, b [][]GlyphPoint) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 0
+	}
+	if len(a) == 0 || len(b) == 0 {
+		return math.Inf(1)
+	}
+
+	if sameStructure(a, b) {
+		distance := 0.0
+		for i := range a {
+			for j := range a[i] {
+				if a[i][j].OnCurve != b[i][j].OnCurve {
+					return sampledDistance(a, b)
+				}
+				dx, dy := float64(a[i][j].X)-float64(b[i][j].X), float64(a[i][j].Y)-float64(b[i][j].Y)
+				distance = math.Max(distance, math.Hypot(dx, dy))
+			}
+		}
+		return distance
+	}
+	return sampledDistance(a, b)
+}
+
+// sameStructure returns true if both outlines have the same number of points in each contour.
+func sameStructure(a, b [][]GlyphPoint) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if len(a[i]) != len(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// sampledDistance returns the symmetric Hausdorff distance between the flattened outlines.
+func sampledDistance(a, b [][]GlyphPoint) float64 {
+	var polyA, polyB [][]vector
+	for _, contour := range a {
+		polyA = append(polyA, flattenContour(contour, curveSamples))
+	}
+	for _, contour := range b {
+		polyB = append(polyB, flattenContour(contour, curveSamples))
+	}
+	return math.Max(directedDistance(polyA, polyB), directedDistance(polyB, polyA))
+}
+
+// directedDistance returns the largest distance from a point in a to the nearest segment of b.
+func directedDistance(a, b [][]vector) float64 {
+	distance := 0.0
+	for _, contour := range a {
+		for _, p := range contour {
+			nearest := math.Inf(1)
+			for _, other := range b {
+				for i := range other {
+					nearest = math.Min(nearest, p.segmentDistance(other[i], other[(i+1)%len(other)]))
+				}
+			}
+			distance = math.Max(distance, nearest)
+		}
+	}
+	return distance
+}
+
+// vector is a point or direction in font units.
+type vector struct {
+	X, Y float64
+}
+
+func (v vector) sub(w vector) vector      { return vector{v.X - w.X, v.Y - w.Y} }
+func (v vector) add(w vector) vector      { return vector{v.X + w.X, v.Y + w.Y} }
+func (v vector) scale(f float64) vector   { return vector{v.X * f, v.Y * f} }
+func (v vector) dot(w vector) float64     { return v.X*w.X + v.Y*w.Y }
+func (v vector) cross(w vector) float64   { return v.X*w.Y - v.Y*w.X }
+func (v vector) length() float64          { return math.Hypot(v.X, v.Y) }
+func (v vector) midpoint(w vector) vector { return vector{(v.X + w.X) / 2, (v.Y + w.Y) / 2} }
+
+// segmentDistance returns the distance from v to the line segment from a to b.
+func (v vector) segmentDistance(a, b vector) float64 {
+	ab := b.sub(a)
+	t := 0.0
+	if l := ab.dot(ab); l > 0 {
+		t = math.Max(0, math.Min(1, v.sub(a).dot(ab)/l))
+	}
+	return v.sub(a.add(ab.scale(t))).length()
+}
+
+// flattenContour converts a TrueType contour into a closed polygon, splitting each
+// quadratic curve into the given number of line segments. The polygon starts at
+// an on-curve point, and the closing segment back to it is implied.
+func flattenContour(contour []GlyphPoint, steps int) []vector {
+	if len(contour) == 0 {
+		return nil
+	}
+
+	points := make([]vector, len(contour))
+	start := -1
+	for i, p := range contour {
+		points[i] = vector{float64(p.X), float64(p.Y)}
+		if start < 0 && p.OnCurve {
+			start = i
+		}
+	}
+
+	// A contour with no on-curve points starts at an implied point.
+	var first vector
+	if start < 0 {
+		first = points[len(points)-1].midpoint(points[0])
+		start = 0
+	} else {
+		first = points[start]
+		start++
+	}
+
+	polygon := []vector{first}
+	current := first
+	var ctrl *vector
+	quadTo := func(c, p vector) {
+		for i := 1; i <= steps; i++ {
+			t := float64(i) / float64(steps)
+			u := 1 - t
+			polygon = append(polygon, current.scale(u*u).add(c.scale(2*u*t)).add(p.scale(t*t)))
+		}
+		current = p
+	}
+
+	for n := 0; n < len(contour); n++ {
+		i := (start + n) % len(contour)
+		p := points[i]
+		if n == len(contour)-1 && contour[i].OnCurve && p == first {
+			break
+		}
+		switch {
+		case contour[i].OnCurve && ctrl == nil:
+			polygon = append(polygon, p)
+			current = p
+		case contour[i].OnCurve:
+			quadTo(*ctrl, p)
+			ctrl = nil
+		case ctrl != nil:
+			quadTo(*ctrl, ctrl.midpoint(p))
+			c := p
+			ctrl = &c
+		default:
+			c := p
+			ctrl = &c
+		}
+	}
+	if ctrl != nil {
+		quadTo(*ctrl, first)
+	}
+
+	// The last point is the first point again, which is implied.
+	if len(polygon) > 1 && polygon[len(polygon)-1] == first {
+		polygon = polygon[:len(polygon)-1]
+	}
+	return polygon
+}
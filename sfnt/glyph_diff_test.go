@@ -0,0 +1,78 @@
+package sfnt
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCompareOutlines(t *testing.T) {
+	a := parseTestFont(t, "open-sans-v15-latin-regular.woff")
+	b := parseTestFont(t, "open-sans-v15-latin-regular.woff")
+
+	differences, err := CompareOutlines(a, b, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(differences) != 0 {
+		t.Fatalf("CompareOutlines(a, a) = %v, want no differences", differences)
+	}
+
+	gids, err := b.GlyphIDs([]string{"A", "O"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	glyf, err := b.GlyfTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Move a point of A by 5 units.
+	moved, err := glyf.Glyph(gids[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	moved.Contours[0][0].X += 3
+	moved.Contours[0][0].Y += 4
+	glyf.SetGlyph(gids[0], moved)
+
+	// Make the implied on-curve points of O explicit, which doesn't change its shape.
+	explicit, err := glyf.Glyph(gids[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, contour := range explicit.Contours {
+		var points []GlyphPoint
+		for j, p := range contour {
+			next := contour[(j+1)%len(contour)]
+			points = append(points, p)
+			if !p.OnCurve && !next.OnCurve && (p.X+next.X)%2 == 0 && (p.Y+next.Y)%2 == 0 {
+				points = append(points, GlyphPoint{(p.X + next.X) / 2, (p.Y + next.Y) / 2, true})
+			}
+		}
+		explicit.Contours[i] = points
+	}
+	glyf.SetGlyph(gids[1], explicit)
+
+	differences, err = CompareOutlines(a, b, 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Glyphs that use A as a component (such as Aacute) differ too.
+	if len(differences) == 0 || differences[0].GlyphID != gids[0] {
+		t.Fatalf("CompareOutlines() = %v, want glyph %d to differ", differences, gids[0])
+	}
+	for _, d := range differences {
+		if d.GlyphID == gids[1] || math.Abs(d.Distance-5) > 0.01 {
+			t.Errorf("CompareOutlines() = %v, want only A and its composites to differ by 5", differences)
+			break
+		}
+	}
+}
+
+func TestOutlineDistanceFarApart(t *testing.T) {
+	a := [][]GlyphPoint{{{-30000, -30000, true}, {-30000, 30000, true}, {30000, 0, true}}}
+	b := [][]GlyphPoint{{{30000, -30000, true}, {-30000, 30000, true}, {30000, 0, true}}}
+	if got := outlineDistance(a, b); got != 60000 {
+		t.Errorf("outlineDistance() = %v, want 60000", got)
+	}
+}
@@ -0,0 +1,129 @@
+package sfnt
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// GlyphClosure returns the glyphs in gids, every glyph that they use as a
+// component (directly or through other composite glyphs), and the .notdef
+// glyph, sorted by glyph id. These are the glyphs kept by SubsetGlyphs.
+// Only fonts with TrueType outlines are supported.
+func (font *Font) GlyphClosure(gids []uint16) ([]uint16, error) {
+	glyf, err := font.GlyfTable()
+	if err == ErrMissingTable {
+		return nil, fmt.Errorf("glyph closures are only supported for fonts with TrueType outlines")
+	} else if err != nil {
+		return nil, err
+	}
+
+	keep, err := glyf.closure(context.Background(), gids)
+	if err != nil {
+		return nil, err
+	}
+	closure := make([]uint16, 0, len(keep))
+	for gid := range keep {
+		closure = append(closure, gid)
+	}
+	sortGlyphIDs(closure)
+	return closure, nil
+}
+
+// ComponentGraph records which composite glyphs refer to which glyphs as their
+// components.
+type ComponentGraph struct {
+	// Components maps each composite glyph to the glyphs it refers to
+	// directly, in the order they are drawn. A glyph used twice is listed twice.
+	Components map[uint16][]uint16
+
+	// UsedBy maps each glyph that is used as a component to the composite
+	// glyphs that refer to it directly, sorted by glyph id.
+	UsedBy map[uint16][]uint16
+}
+
+// ComponentGraph returns the graph of the components of the composite glyphs
+// in the table.
+func (table *TableGlyf) ComponentGraph() (*ComponentGraph, error) {
+	graph := &ComponentGraph{
+		Components: make(map[uint16][]uint16),
+		UsedBy:     make(map[uint16][]uint16),
+	}
+	for i := range table.Glyphs {
+		gid := uint16(i)
+		components, err := table.Components(gid)
+		if err != nil {
+			return nil, err
+		}
+		if components == nil {
+			continue
+		}
+		graph.Components[gid] = components
+		for j, component := range components {
+			if !containsGlyphID(components[:j], component) {
+				graph.UsedBy[component] = append(graph.UsedBy[component], gid)
+			}
+		}
+	}
+	return graph, nil
+}
+
+// Dependents returns every composite glyph that uses gid, directly or through
+// other composite glyphs, sorted by glyph id. These are the glyphs whose
+// outlines change when gid is changed.
+func (graph *ComponentGraph) Dependents(gid uint16) []uint16 {
+	seen := map[uint16]bool{gid: true}
+	queue := []uint16{gid}
+	var dependents []uint16
+	for len(queue) > 0 {
+		for _, user := range graph.UsedBy[queue[0]] {
+			if !seen[user] {
+				seen[user] = true
+				dependents = append(dependents, user)
+				queue = append(queue, user)
+			}
+		}
+		queue = queue[1:]
+	}
+	sortGlyphIDs(dependents)
+	return dependents
+}
+
+// Depth returns the number of levels of components below gid: 0 for a simple
+// glyph, 1 for a composite of simple glyphs, and so on. This is the
+// maxComponentDepth of the glyph in the 'maxp' table. Cycles, which are
+// invalid, are not followed.
+func (graph *ComponentGraph) Depth(gid uint16) int {
+	return graph.depth(gid, map[uint16]bool{})
+}
+
+func (graph *ComponentGraph) depth(gid uint16, visiting map[uint16]bool) int {
+	if visiting[gid] {
+		return 0
+	}
+	visiting[gid] = true
+	defer delete(visiting, gid)
+
+	depth := 0
+	for _, component := range graph.Components[gid] {
+		if d := graph.depth(component, visiting) + 1; d > depth {
+			depth = d
+		}
+	}
+	return depth
+}
+
+// sortGlyphIDs sorts gids in increasing order.
+func sortGlyphIDs(gids []uint16) {
+	sort.Slice(gids, func(i, j int) bool { return gids[i] < gids[j] })
+}
+
+// containsGlyphID returns true if gids contains gid.
+func containsGlyphID(gids []uint16, gid uint16) bool {
+	for _, g := range gids {
+		if g == gid {
+			return true
+		}
+	}
+	return false
+}
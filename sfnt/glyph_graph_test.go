@@ -0,0 +1,60 @@
+package sfnt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestComponentGraph(t *testing.T) {
+	identity := [4]float64{1, 0, 0, 1}
+	b := NewBuilder(1000)
+	sq := b.AddGlyph("square", 600, [][]GlyphPoint{square(0, 0, 500)})
+	bar := b.AddGlyph("bar", 600, [][]GlyphPoint{square(0, 0, 100)})
+	twice := b.AddComposite("twice", 1200, []GlyphComponent{
+		{GlyphID: sq, Transform: identity},
+		{GlyphID: sq, Arg1: 600, Transform: identity},
+	})
+	nested := b.AddComposite("nested", 1200, []GlyphComponent{
+		{GlyphID: twice, Transform: identity},
+		{GlyphID: bar, Transform: identity},
+	})
+	b.AddGlyph("unused", 600, nil)
+	font, err := b.Font()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	closure, err := font.GlyphClosure([]uint16{nested})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []uint16{0, sq, bar, twice, nested}; !reflect.DeepEqual(closure, want) {
+		t.Errorf("GlyphClosure(%d) = %v, want %v", nested, closure, want)
+	}
+
+	glyf, err := font.GlyfTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	graph, err := glyf.ComponentGraph()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[uint16][]uint16{twice: {sq, sq}, nested: {twice, bar}}; !reflect.DeepEqual(graph.Components, want) {
+		t.Errorf("Components = %v, want %v", graph.Components, want)
+	}
+	if want := map[uint16][]uint16{sq: {twice}, bar: {nested}, twice: {nested}}; !reflect.DeepEqual(graph.UsedBy, want) {
+		t.Errorf("UsedBy = %v, want %v", graph.UsedBy, want)
+	}
+	if got, want := graph.Dependents(sq), []uint16{twice, nested}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Dependents(%d) = %v, want %v", sq, got, want)
+	}
+	if got := graph.Dependents(nested); len(got) != 0 {
+		t.Errorf("Dependents(%d) = %v, want none", nested, got)
+	}
+	for gid, want := range map[uint16]int{sq: 0, twice: 1, nested: 2} {
+		if got := graph.Depth(gid); got != want {
+			t.Errorf("Depth(%d) = %d, want %d", gid, got, want)
+		}
+	}
+}
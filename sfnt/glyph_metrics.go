@@ -0,0 +1,341 @@
+package sfnt
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// Bounds is the bounding box of a glyph, in font units.
+type Bounds struct {
+	XMin, YMin, XMax, YMax float64
+}
+
+// GlyphAdvance returns the advance width of the glyph, in font units.
+//
+// For variable fonts, coords contains the normalized position on each axis, as
+// taken by KerningValue. The advance is adjusted by the deltas in the 'HVAR'
+// table, or if the font does not have one, by the deltas that 'gvar' applies to
+// the glyph's phantom points. If coords is empty the default instance is used.
+func (font *Font) GlyphAdvance(gid uint16, coords []float64) (float64, error) {
+	hmtx, err := font.HmtxTable()
+	if err != nil {
+		return 0, err
+	}
+	advance := float64(hmtx.Advance(gid))
+	if len(coords) == 0 {
+		return advance, nil
+	}
+
+	if font.HasTable(tagHvar) {
+		hvar, err := font.hvarAdvances()
+		if err != nil {
+			return 0, err
+		}
+		return advance + hvar.delta(gid, coords), nil
+	}
+	if !font.HasTable(TagGvar) || !font.HasTable(TagGlyf) {
+		return advance, nil
+	}
+
+	v, err := font.glyphVariations(hmtx, coords)
+	if err != nil {
+		return 0, err
+	}
+	_, points, _, err := v.points(gid)
+	if err != nil {
+		return 0, err
+	}
+	phantom := points[len(points)-4:]
+	return phantom[1].X - phantom[0].X, nil
+}
+
+// GlyphBounds returns the bounding box of the outline of the glyph, with the
+// components of composite glyphs resolved. Empty glyphs have empty bounds at
+// the origin.
+//
+// For variable fonts, coords is the position of the instance to measure, as for
+// GlyphAdvance, and the outline is varied by the deltas in 'gvar'.
+func (font *Font) GlyphBounds(gid uint16, coords []float64) (Bounds, error) {
+	if !font.HasTable(TagGlyf) {
+		return Bounds{}, fmt.Errorf("glyph bounds are only supported for fonts with glyf outlines")
+	}
+	hmtx, err := font.HmtxTable()
+	if err != nil {
+		return Bounds{}, err
+	}
+	v, err := font.glyphVariations(hmtx, coords)
+	if err != nil {
+		return Bounds{}, err
+	}
+	contours, err := v.contours(gid, 0)
+	if err != nil {
+		return Bounds{}, err
+	}
+
+	var bounds Bounds
+	first := true
+	for _, contour := range contours {
+		for _, p := range contour {
+			if first {
+				bounds = Bounds{p.X, p.Y, p.X, p.Y}
+				first = false
+			}
+			bounds.XMin, bounds.YMin = math.Min(bounds.XMin, p.X), math.Min(bounds.YMin, p.Y)
+			bounds.XMax, bounds.YMax = math.Max(bounds.XMax, p.X), math.Max(bounds.YMax, p.Y)
+		}
+	}
+	return bounds, nil
+}
+
+// hvarAdvances are the variations of advance widths in an 'HVAR' table.
+type hvarAdvances struct {
+	store    *ItemVariationStore
+	advances *DeltaSetIndexMap // advances is nil if glyph ids are the inner indices of the first item variation data.
+}
+
+func parseHvarAdvances(hvar []byte) (*hvarAdvances, error) {
+	if len(hvar) < 20 {
+		return nil, fmt.Errorf("unexpected EOF")
+	}
+	storeOffset, mapOffset := binary.BigEndian.Uint32(hvar[4:]), binary.BigEndian.Uint32(hvar[8:])
+	if int(storeOffset) >= len(hvar) || int(mapOffset) >= len(hvar) {
+		return nil, fmt.Errorf("invalid offsets %d and %d", storeOffset, mapOffset)
+	}
+	h := &hvarAdvances{}
+	var err error
+	if h.store, err = ParseItemVariationStore(hvar[storeOffset:]); err != nil {
+		return nil, err
+	}
+	if mapOffset != 0 {
+		if h.advances, err = ParseDeltaSetIndexMap(hvar[mapOffset:]); err != nil {
+			return nil, err
+		}
+	}
+	return h, nil
+}
+
+// hvarAdvances returns the advance variations in the font's 'HVAR' table.
+func (font *Font) hvarAdvances() (*hvarAdvances, error) {
+	hvar, err := font.TableData(tagHvar)
+	if err != nil {
+		return nil, err
+	}
+	h, err := parseHvarAdvances(hvar)
+	if err != nil {
+		return nil, fmt.Errorf("reading HVAR: %s", err)
+	}
+	return h, nil
+}
+
+// delta returns the adjustment to the advance width of the glyph.
+func (h *hvarAdvances) delta(gid uint16, coords []float64) float64 {
+	outer, inner := h.advances.Map(int(gid))
+	return h.store.Delta(outer, inner, coords)
+}
+
+// glyphVariations varies the points of glyphs to a position in the design space.
+type glyphVariations struct {
+	glyf   *TableGlyf
+	hmtx   *TableHmtx
+	gvar   *TableGvar // gvar is nil at the default position, or if the font does not vary.
+	coords []float64
+}
+
+func (font *Font) glyphVariations(hmtx *TableHmtx, coords []float64) (*glyphVariations, error) {
+	glyf, err := font.GlyfTable()
+	if err != nil {
+		return nil, err
+	}
+	v := &glyphVariations{glyf: glyf, hmtx: hmtx, coords: coords}
+	if len(coords) > 0 && font.HasTable(TagGvar) {
+		if v.gvar, err = font.GvarTable(); err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+// points returns the varied points of the glyph: the points of each contour of
+// a simple glyph, or the offset of each component of a composite glyph,
+// followed by the four phantom points. The first two phantom points are at
+// the glyph's origin and advance. ends contains the index of the last point
+// of each contour.
+func (v *glyphVariations) points(gid uint16) (glyph *Glyph, points []vector, ends []int, err error) {
+	if glyph, err = v.glyf.Glyph(gid); err != nil {
+		return nil, nil, nil, err
+	}
+	for _, contour := range glyph.Contours {
+		for _, p := range contour {
+			points = append(points, vector{float64(p.X), float64(p.Y)})
+		}
+		ends = append(ends, len(points)-1)
+	}
+	for _, c := range glyph.Components {
+		points = append(points, vector{float64(c.Arg1), float64(c.Arg2)})
+	}
+
+	var lsb float64
+	if int(gid) < len(v.hmtx.Metrics) {
+		lsb = float64(v.hmtx.Metrics[gid].LeftSideBearing)
+	}
+	origin := float64(glyph.XMin) - lsb
+	points = append(points, vector{origin, 0}, vector{origin + float64(v.hmtx.Advance(gid)), 0}, vector{}, vector{})
+
+	if v.gvar != nil && int(gid) < len(v.gvar.Glyphs) {
+		deltas, err := v.gvar.Glyphs[gid].pointDeltas(v.coords, points, ends)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("glyph %d: %s", gid, err)
+		}
+		for i := range points {
+			points[i] = points[i].add(deltas[i])
+		}
+	}
+	return glyph, points, ends, nil
+}
+
+// contours returns the varied contours of the glyph, with the components of
+// composite glyphs resolved and transformed into place, like TableGlyf.Contours.
+func (v *glyphVariations) contours(gid uint16, depth int) ([][]vector, error) {
+	if depth > maxComponentDepth {
+		return nil, fmt.Errorf("glyph %d: components are nested too deeply", gid)
+	}
+	glyph, points, ends, err := v.points(gid)
+	if err != nil {
+		return nil, err
+	}
+
+	var contours [][]vector
+	start := 0
+	for _, end := range ends {
+		contours = append(contours, points[start:end+1])
+		start = end + 1
+	}
+	for i, c := range glyph.Components {
+		if c.Flags&glyfArgsAreXY == 0 {
+			return nil, fmt.Errorf("glyph %d: components aligned by point are not supported", gid)
+		}
+		parts, err := v.contours(c.GlyphID, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		offset := points[i]
+		for _, part := range parts {
+			contour := make([]vector, len(part))
+			for j, p := range part {
+				contour[j] = vector{
+					p.X*c.Transform[0] + p.Y*c.Transform[2] + offset.X,
+					p.X*c.Transform[1] + p.Y*c.Transform[3] + offset.Y,
+				}
+			}
+			contours = append(contours, contour)
+		}
+	}
+	return contours, nil
+}
+
+// pointDeltas returns the total delta of each point at the given coordinates.
+// Points that a tuple does not move are interpolated from the moved points on
+// the same contour, where ends contains the index of the last point of each
+// contour, as described in
+// https://docs.microsoft.com/en-us/typography/opentype/spec/gvar#inferred-deltas-for-un-referenced-point-numbers
+func (variations *TupleVariations) pointDeltas(coords []float64, points []vector, ends []int) ([]vector, error) {
+	deltas := make([]vector, len(points))
+	for i, tuple := range variations.Tuples {
+		scalar := regionScalar(tuple.Region(), coords)
+		if scalar == 0 {
+			continue
+		}
+
+		packed := tuple.points
+		if packed == nil {
+			packed = variations.sharedPoints
+		}
+		var indices []int
+		if packed != nil {
+			var err error
+			if indices, err = unpackPoints(packed); err != nil {
+				return nil, fmt.Errorf("reading points of tuple %d: %s", i, err)
+			}
+		}
+
+		if indices == nil {
+			if len(tuple.Deltas) != 2*len(points) {
+				return nil, fmt.Errorf("tuple %d has %d deltas for %d points", i, len(tuple.Deltas), len(points))
+			}
+			for j := range points {
+				d := vector{float64(tuple.Deltas[j]), float64(tuple.Deltas[len(points)+j])}
+				deltas[j] = deltas[j].add(d.scale(scalar))
+			}
+			continue
+		}
+
+		if len(tuple.Deltas) != 2*len(indices) {
+			return nil, fmt.Errorf("tuple %d has %d deltas for %d points", i, len(tuple.Deltas), len(indices))
+		}
+		moved := make([]vector, len(points))
+		touched := make([]bool, len(points))
+		for j, point := range indices {
+			if point < len(points) {
+				moved[point] = vector{float64(tuple.Deltas[j]), float64(tuple.Deltas[len(indices)+j])}
+				touched[point] = true
+			}
+		}
+		interpolateDeltas(points, ends, moved, touched)
+		for j := range deltas {
+			deltas[j] = deltas[j].add(moved[j].scale(scalar))
+		}
+	}
+	return deltas, nil
+}
+
+// interpolateDeltas infers the deltas of the points on each contour that are
+// not touched, from the touched points before and after them.
+func interpolateDeltas(points []vector, ends []int, deltas []vector, touched []bool) {
+	start := 0
+	for _, end := range ends {
+		next := func(i int) int {
+			if i == end {
+				return start
+			}
+			return i + 1
+		}
+
+		var refs []int
+		for i := start; i <= end; i++ {
+			if touched[i] {
+				refs = append(refs, i)
+			}
+		}
+		for k, a := range refs {
+			b := refs[(k+1)%len(refs)]
+			for i := next(a); i != b; i = next(i) {
+				deltas[i] = vector{
+					interpolateDelta(points[i].X, points[a].X, points[b].X, deltas[a].X, deltas[b].X),
+					interpolateDelta(points[i].Y, points[a].Y, points[b].Y, deltas[a].Y, deltas[b].Y),
+				}
+			}
+		}
+		start = end + 1
+	}
+}
+
+// interpolateDelta returns the delta of a coordinate v between the coordinates
+// v1 and v2 of two touched points with deltas d1 and d2.
+func interpolateDelta(v, v1, v2, d1, d2 float64) float64 {
+	if v1 > v2 {
+		v1, v2, d1, d2 = v2, v1, d2, d1
+	}
+	switch {
+	case v1 == v2:
+		if d1 == d2 {
+			return d1
+		}
+		return 0
+	case v <= v1:
+		return d1
+	case v >= v2:
+		return d2
+	}
+	return d1 + (v-v1)*(d2-d1)/(v2-v1)
+}
@@ -0,0 +1,85 @@
+package sfnt
+
+import (
+	"testing"
+)
+
+// variedTestFont returns a font with a square and a composite glyph that vary
+// along a wght axis.
+func variedTestFont(t *testing.T) (*Font, uint16, uint16) {
+	b := NewBuilder(1000)
+	sq := b.AddGlyph("square", 600, [][]GlyphPoint{square(100, 0, 400)})
+	moved := b.AddComposite("moved", 1200, []GlyphComponent{{GlyphID: sq, Arg1: 1000, Transform: [4]float64{1, 0, 0, 1}}})
+	font, err := b.Font()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The square's top right corner moves up and right, the bottom left corner
+	// stays, and the others are interpolated. The advance also grows by 50. The
+	// composite moves the varied square right by another 20.
+	glyphs := make([]TupleVariations, moved+1)
+	glyphs[sq] = TupleVariations{Tuples: []TupleVariation{{Peak: []float64{1}, Deltas: []int16{0, 100, 50, 0, 100, 0}, points: []byte{3, 2, 0, 2, 3}}}}
+	glyphs[moved] = TupleVariations{Tuples: []TupleVariation{{Peak: []float64{1}, Deltas: []int16{20, 0, 0, 0, 0, 0, 0, 0, 0, 0}}}}
+	font.AddTable(TagGvar, &TableGvar{baseTable: baseTable(TagGvar), AxisCount: 1, Glyphs: glyphs})
+	font.AddTable(TagFvar, &TableFvar{
+		baseTable: baseTable(TagFvar),
+		Axes:      []VariationAxis{{Tag: axisWeight, Min: 100, Default: 400, Max: 900, NameID: 256}},
+	})
+	return font, sq, moved
+}
+
+func TestGlyphMetricsVariations(t *testing.T) {
+	font, sq, moved := variedTestFont(t)
+
+	tests := []struct {
+		gid     uint16
+		coords  []float64
+		advance float64
+		bounds  Bounds
+	}{
+		{sq, nil, 600, Bounds{100, 0, 500, 400}},
+		{sq, []float64{1}, 650, Bounds{100, 0, 600, 500}},
+		{sq, []float64{0.5}, 625, Bounds{100, 0, 550, 450}},
+		{sq, []float64{-1}, 600, Bounds{100, 0, 500, 400}},
+		{moved, []float64{1}, 1200, Bounds{1120, 0, 1620, 500}},
+		{0, []float64{1}, 500, Bounds{0, 0, 500, 700}},
+	}
+	for _, test := range tests {
+		advance, err := font.GlyphAdvance(test.gid, test.coords)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if advance != test.advance {
+			t.Errorf("GlyphAdvance(%d, %v) = %v, want %v", test.gid, test.coords, advance, test.advance)
+		}
+		bounds, err := font.GlyphBounds(test.gid, test.coords)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bounds != test.bounds {
+			t.Errorf("GlyphBounds(%d, %v) = %v, want %v", test.gid, test.coords, bounds, test.bounds)
+		}
+	}
+}
+
+func TestGlyphAdvanceHVAR(t *testing.T) {
+	font := limitTestFont(t)
+	for _, test := range []struct {
+		coords []float64
+		want   float64
+	}{
+		{nil, 500},
+		{[]float64{1, 0}, 550},
+		{[]float64{0.5, 0}, 625},
+		{[]float64{-1, -1}, 470},
+	} {
+		advance, err := font.GlyphAdvance(0, test.coords)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if advance != test.want {
+			t.Errorf("GlyphAdvance(0, %v) = %v, want %v", test.coords, advance, test.want)
+		}
+	}
+}
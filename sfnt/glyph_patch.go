@@ -0,0 +1,142 @@
+package sfnt
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"sort"
+)
+
+// glyphPatchMagic identifies a serialized GlyphPatch.
+var glyphPatchMagic = MustNamedTag("ifgp")
+
+// GlyphPatch contains the outlines needed to extend a subset of a font to support
+// more characters, in the style of the W3C Incremental Font Transfer specification.
+//
+// SubsetGlyphs keeps glyph ids stable, so a subset font still has the complete 'cmap',
+// 'hmtx' and layout tables of the original, and only the outlines of glyphs need to
+// be sent. A patch can be applied to any subset of the same font that does not have
+// the glyphs it contains. Unlike IFT patches, patches are not compressed, as this
+// package has no Brotli encoder; they should be compressed in transit instead.
+//
+// Serialized patches consist of the tag 'ifgp', the number of glyphs as a uint32,
+// the id of each glyph as a uint16, count+1 uint32 offsets to the start of each
+// glyph's data (relative to the end of the offsets), and then the glyph data.
+type GlyphPatch struct {
+	GlyphIDs []uint16 // GlyphIDs are the glyphs in the patch, in increasing order.
+	Data     [][]byte // Data contains the 'glyf' table entry for each glyph.
+}
+
+// GlyphPatch returns the outlines of the glyphs needed to support the additional
+// runes, that are not already needed for the base runes. The font must be the
+// complete font that both subsets were made from.
+func (font *Font) GlyphPatch(base, additional []rune) (*GlyphPatch, error) {
+	glyf, err := font.GlyfTable()
+	if err == ErrMissingTable {
+		return nil, fmt.Errorf("patches are only supported for fonts with TrueType outlines")
+	} else if err != nil {
+		return nil, err
+	}
+
+	baseGIDs, err := font.GlyphsForRunes(base)
+	if err != nil {
+		return nil, err
+	}
+	additionalGIDs, err := font.GlyphsForRunes(additional)
+	if err != nil {
+		return nil, err
+	}
+	have, err := glyf.closure(context.Background(), baseGIDs)
+	if err != nil {
+		return nil, err
+	}
+	want, err := glyf.closure(context.Background(), append(baseGIDs, additionalGIDs...))
+	if err != nil {
+		return nil, err
+	}
+
+	patch := &GlyphPatch{}
+	for gid := range want {
+		if !have[gid] && len(glyf.Glyphs[gid]) > 0 {
+			patch.GlyphIDs = append(patch.GlyphIDs, gid)
+		}
+	}
+	sort.Slice(patch.GlyphIDs, func(i, j int) bool { return patch.GlyphIDs[i] < patch.GlyphIDs[j] })
+	for _, gid := range patch.GlyphIDs {
+		patch.Data = append(patch.Data, glyf.Glyphs[gid])
+	}
+	return patch, nil
+}
+
+// ApplyGlyphPatch adds the glyphs in the patch to a subset font.
+func (font *Font) ApplyGlyphPatch(patch *GlyphPatch) error {
+	if len(patch.Data) != len(patch.GlyphIDs) {
+		return fmt.Errorf("patch has %d glyph ids, but data for %d glyphs", len(patch.GlyphIDs), len(patch.Data))
+	}
+	glyf, err := font.GlyfTable()
+	if err != nil {
+		return err
+	}
+
+	for i, gid := range patch.GlyphIDs {
+		if int(gid) >= glyf.NumGlyphs() {
+			return fmt.Errorf("glyph %d is out of range (font has %d glyphs)", gid, glyf.NumGlyphs())
+		}
+		if len(glyf.Glyphs[gid]) > 0 && string(glyf.Glyphs[gid]) != string(patch.Data[i]) {
+			return fmt.Errorf("glyph %d is already in the font, with a different outline", gid)
+		}
+	}
+	for i, gid := range patch.GlyphIDs {
+		glyf.Glyphs[gid] = patch.Data[i]
+	}
+	return nil
+}
+
+// Bytes returns the serialized form of the patch.
+func (patch *GlyphPatch) Bytes() []byte {
+	buf := appendUint32s(nil, glyphPatchMagic.Number, uint32(len(patch.GlyphIDs)))
+	buf = appendUint16s(buf, patch.GlyphIDs...)
+
+	offset := uint32(0)
+	buf = appendUint32s(buf, offset)
+	for _, data := range patch.Data {
+		offset += uint32(len(data))
+		buf = appendUint32s(buf, offset)
+	}
+	for _, data := range patch.Data {
+		buf = append(buf, data...)
+	}
+	return buf
+}
+
+// ParseGlyphPatch parses a patch serialized by GlyphPatch.Bytes.
+func ParseGlyphPatch(buf []byte) (*GlyphPatch, error) {
+	if len(buf) < 8 || NewTag(buf) != glyphPatchMagic {
+		return nil, fmt.Errorf("not a glyph patch")
+	}
+	count := int(binary.BigEndian.Uint32(buf[4:]))
+	offsets := 8 + 2*count
+	start := offsets + 4*(count+1)
+	if count > len(buf) || start > len(buf) {
+		return nil, fmt.Errorf("reading %d glyphs: unexpected EOF", count)
+	}
+
+	patch := &GlyphPatch{}
+	previous := -1
+	for i := 0; i < count; i++ {
+		gid := binary.BigEndian.Uint16(buf[8+2*i:])
+		if int(gid) <= previous {
+			return nil, fmt.Errorf("glyph ids are not in increasing order")
+		}
+		previous = int(gid)
+
+		from := start + int(binary.BigEndian.Uint32(buf[offsets+4*i:]))
+		to := start + int(binary.BigEndian.Uint32(buf[offsets+4*i+4:]))
+		if from > to || to > len(buf) {
+			return nil, fmt.Errorf("invalid offsets for glyph %d", gid)
+		}
+		patch.GlyphIDs = append(patch.GlyphIDs, gid)
+		patch.Data = append(patch.Data, buf[from:to])
+	}
+	return patch, nil
+}
@@ -0,0 +1,68 @@
+package sfnt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGlyphPatch(t *testing.T) {
+	full := parseTestFont(t, "open-sans-v15-latin-regular.woff")
+	subset := parseTestFont(t, "open-sans-v15-latin-regular.woff")
+
+	base := []rune("ABC")
+	gids, err := subset.GlyphsForRunes(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := subset.SubsetGlyphs(gids); err != nil {
+		t.Fatal(err)
+	}
+
+	// Aacute is made of A, which the subset already has, and acute, which it doesn't.
+	patch, err := full.GlyphPatch(base, []rune("Á"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := full.GlyphIDs([]string{"acute", "Aacute"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want[0] > want[1] {
+		want[0], want[1] = want[1], want[0]
+	}
+	if !reflect.DeepEqual(patch.GlyphIDs, want) {
+		t.Errorf("GlyphPatch(ABC, Á).GlyphIDs = %v, want %v", patch.GlyphIDs, want)
+	}
+
+	parsed, err := ParseGlyphPatch(patch.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(parsed, patch) {
+		t.Errorf("ParseGlyphPatch(Bytes()) = %v, want %v", parsed, patch)
+	}
+
+	mismatched := &GlyphPatch{GlyphIDs: parsed.GlyphIDs, Data: parsed.Data[:1]}
+	if err := subset.ApplyGlyphPatch(mismatched); err == nil {
+		t.Errorf("ApplyGlyphPatch(%d ids, 1 glyph) err = nil, want an error", len(mismatched.GlyphIDs))
+	}
+	if err := subset.ApplyGlyphPatch(parsed); err != nil {
+		t.Fatal(err)
+	}
+	glyf, err := subset.GlyfTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	aacute, _ := full.GlyphsForRunes([]rune("Á"))
+	contours, err := glyf.Contours(aacute[0])
+	if err != nil || len(contours) == 0 {
+		t.Errorf("Contours(Aacute) after patching = %d contours, %v, want the outline", len(contours), err)
+	}
+	if mapped, err := subset.GlyphsForRunes([]rune("Á")); err != nil || !reflect.DeepEqual(mapped, aacute) {
+		t.Errorf("GlyphsForRunes(Á) after patching = %v, %v; want %v", mapped, err, aacute)
+	}
+
+	if _, err := ParseGlyphPatch(patch.Bytes()[:12]); err == nil {
+		t.Errorf("ParseGlyphPatch(truncated) err = nil, want an error")
+	}
+}
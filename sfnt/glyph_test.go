@@ -0,0 +1,113 @@
+package sfnt
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func parseTestFont(t *testing.T, name string) *Font {
+	t.Helper()
+	file, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { file.Close() })
+
+	font, err := Parse(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return font
+}
+
+func TestGlyphRoundTrip(t *testing.T) {
+	for _, name := range []string{"open-sans-v15-latin-regular.woff", "Roboto-BoldItalic.ttf"} {
+		font := parseTestFont(t, name)
+		glyf, err := font.GlyfTable()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i := range glyf.Glyphs {
+			glyph, err := glyf.Glyph(uint16(i))
+			if err != nil {
+				t.Fatalf("%s: Glyph(%d) err = %q, want nil", name, i, err)
+			}
+			parsed, err := parseGlyph(glyph.Bytes())
+			if err != nil {
+				t.Fatalf("%s: parseGlyph(Glyph(%d).Bytes()) err = %q, want nil", name, i, err)
+			}
+			if !reflect.DeepEqual(glyph, parsed) {
+				t.Errorf("%s: glyph %d = %v after round trip, want %v", name, i, parsed, glyph)
+			}
+			if _, err := glyf.Contours(uint16(i)); err != nil {
+				t.Errorf("%s: Contours(%d) err = %q, want nil", name, i, err)
+			}
+		}
+	}
+}
+
+func TestParseGlyphTruncated(t *testing.T) {
+	simple := (&Glyph{Contours: [][]GlyphPoint{square(0, 0, 100)}, Instructions: []byte{1, 2, 3, 4}}).Bytes()
+	composite := (&Glyph{Components: []GlyphComponent{{GlyphID: 1, Arg1: 10, Arg2: 20, Flags: glyfArgsAreXY, Transform: [4]float64{1, 0, 0, 1}}}}).Bytes()
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		// The header, the end of the contour, the instruction length and half the instructions.
+		{"instructions", simple[:10+2+2+2]},
+		// The header, the component's flags and glyph id, and one of its two byte arguments.
+		{"arguments", composite[:10+4+1]},
+	}
+	for _, test := range tests {
+		if _, err := parseGlyph(test.data); err != errInvalidGlyph {
+			t.Errorf("parseGlyph(truncated %s) err = %v, want %v", test.name, err, errInvalidGlyph)
+		}
+	}
+}
+
+func TestGlyphContours(t *testing.T) {
+	font := parseTestFont(t, "open-sans-v15-latin-regular.woff")
+	glyf, err := font.GlyfTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	gids, err := font.GlyphIDs([]string{"A", "Aacute"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := glyf.Contours(gids[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	aacute, err := glyf.Contours(gids[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(aacute) <= len(a) || !reflect.DeepEqual(aacute[:len(a)], a) {
+		t.Errorf("Contours(Aacute) = %v, want the contours of A followed by the accent", aacute)
+	}
+}
+
+func TestHmtxRoundTrip(t *testing.T) {
+	font := parseTestFont(t, "Roboto-BoldItalic.ttf")
+	data, err := font.TableData(TagHmtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hmtx, err := font.HmtxTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(hmtx.Bytes(), data) {
+		t.Errorf("Bytes() differs from the parsed table")
+	}
+	if hmtx.Advance(0) == 0 {
+		t.Errorf("Advance(0) = 0, want non-zero")
+	}
+}
@@ -0,0 +1,115 @@
+package sfnt
+
+import (
+	"math"
+)
+
+// roundCoordinate rounds v to the nearest integer, rounding halves up (towards
+// positive infinity) rather than away from zero. Unlike math.Round, this gives
+// the same result for a shape wherever it is positioned, so an outline that is
+// moved by a whole number of units rounds to the moved outline. Coordinates and
+// metrics computed by instancing, interpolation and grid rounding all use it.
+func roundCoordinate(v float64) float64 {
+	return math.Floor(v + 0.5)
+}
+
+// roundToGrid rounds v to the nearest multiple of grid, as by roundCoordinate.
+func roundToGrid(v float64, grid int) int {
+	g := float64(grid)
+	return int(roundCoordinate(v/g) * g)
+}
+
+// griddedTables returns copies of the 'glyf', 'loca' and 'hmtx' tables with the
+// glyph coordinates, component offsets, advance widths and left side bearings
+// rounded to multiples of grid font units, and updates the bounding box in
+// head. Components positioned by aligning points are left as they are.
+//
+// The left side bearing of each glyph is recomputed from the rounded outline
+// and the rounded position of its origin, so that the outline stays at the
+// same place relative to the advance.
+func (font *Font) griddedTables(grid int, head *TableHead) (map[Tag]Table, error) {
+	tables := map[Tag]Table{}
+	if !font.HasTable(TagGlyf) {
+		return tables, nil
+	}
+	original, err := font.GlyfTable()
+	if err != nil {
+		return nil, err
+	}
+	glyf := &TableGlyf{baseTable: original.baseTable, Glyphs: append([][]byte(nil), original.Glyphs...)}
+
+	var hmtx *TableHmtx
+	if font.HasTable(TagHmtx) {
+		original, err := font.HmtxTable()
+		if err != nil {
+			return nil, err
+		}
+		hmtx = &TableHmtx{baseTable: original.baseTable, Metrics: append([]HMetric(nil), original.Metrics...)}
+	}
+
+	origins := make([]int, len(glyf.Glyphs))
+	for i := range glyf.Glyphs {
+		gid := uint16(i)
+		glyph, err := glyf.Glyph(gid)
+		if err != nil {
+			return nil, err
+		}
+		if hmtx != nil && i < len(hmtx.Metrics) {
+			origin := float64(glyph.XMin) - float64(hmtx.Metrics[i].LeftSideBearing)
+			origins[i] = roundToGrid(origin, grid)
+		}
+		if glyph.IsEmpty() {
+			continue
+		}
+
+		for _, contour := range glyph.Contours {
+			for j := range contour {
+				contour[j].X = clampInt16(roundToGrid(float64(contour[j].X), grid))
+				contour[j].Y = clampInt16(roundToGrid(float64(contour[j].Y), grid))
+			}
+		}
+		for j := range glyph.Components {
+			c := &glyph.Components[j]
+			if c.Flags&glyfArgsAreXY != 0 {
+				c.Arg1 = clampInt16(roundToGrid(float64(c.Arg1), grid))
+				c.Arg2 = clampInt16(roundToGrid(float64(c.Arg2), grid))
+			}
+		}
+		if len(glyph.Contours) > 0 {
+			glyph.UpdateBounds()
+		}
+		if err := glyf.SetGlyph(gid, glyph); err != nil {
+			return nil, err
+		}
+	}
+	// The bounds of composite glyphs are recomputed from their resolved contours
+	// once all of their components have been rounded.
+	if err := glyf.updateBounds(head); err != nil {
+		return nil, err
+	}
+	tables[TagGlyf] = glyf
+	tables[TagLoca] = &TableLoca{baseTable: baseTable(TagLoca), glyf: glyf}
+
+	if hmtx == nil {
+		return tables, nil
+	}
+	for i := range hmtx.Metrics {
+		m := &hmtx.Metrics[i]
+		advance := roundToGrid(float64(m.AdvanceWidth), grid)
+		if advance > math.MaxUint16 {
+			advance -= grid
+		}
+		m.AdvanceWidth = uint16(advance)
+		if i >= len(glyf.Glyphs) {
+			m.LeftSideBearing = clampInt16(roundToGrid(float64(m.LeftSideBearing), grid))
+			continue
+		}
+		glyph, err := glyf.Glyph(uint16(i))
+		if err != nil {
+			return nil, err
+		}
+		m.LeftSideBearing = clampInt16(int(glyph.XMin) - origins[i])
+	}
+	tables[TagHmtx] = hmtx
+	return tables, nil
+}
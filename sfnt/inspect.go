@@ -0,0 +1,160 @@
+package sfnt
+
+import (
+	"encoding/binary"
+	"sort"
+)
+
+// CharInfo describes how a font renders a character, for debugging characters
+// that look wrong.
+type CharInfo struct {
+	Rune      rune
+	GlyphID   uint16 // GlyphID is 0 (.notdef) if the character is not mapped.
+	Mapped    bool   // Mapped is true if the 'cmap' table maps the character to a glyph.
+	GlyphName string
+	Advance   float64
+	Bounds    *Bounds // Bounds is nil if the font does not have TrueType outlines.
+
+	// Features are the GSUB features with a lookup that applies to the glyph,
+	// sorted by tag. These can replace the glyph with another, depending on
+	// the context.
+	Features []Tag
+
+	// KernedBefore are the glyphs with a kerning adjustment when they come
+	// before the glyph, and KernedAfter those with an adjustment when they
+	// come after it, sorted by glyph id.
+	KernedBefore []KernPair
+	KernedAfter  []KernPair
+}
+
+// KernPair is the kerning adjustment between a glyph and another glyph.
+type KernPair struct {
+	GlyphID   uint16
+	GlyphName string
+	Value     float64
+}
+
+// InspectChar returns the glyph that r is mapped to, its metrics, and the
+// features and kerning that can change how it is drawn.
+func (font *Font) InspectChar(r rune) (*CharInfo, error) {
+	info := &CharInfo{Rune: r}
+	cmap, err := font.CmapTable()
+	if err != nil {
+		return nil, err
+	}
+	info.GlyphID, info.Mapped = cmap.Lookup(r)
+
+	names, err := font.glyphNames()
+	if err != nil {
+		return nil, err
+	}
+	if int(info.GlyphID) < len(names) {
+		info.GlyphName = names[info.GlyphID]
+	}
+	if info.Advance, err = font.GlyphAdvance(info.GlyphID, nil); err != nil {
+		return nil, err
+	}
+	if font.HasTable(TagGlyf) {
+		bounds, err := font.GlyphBounds(info.GlyphID, nil)
+		if err != nil {
+			return nil, err
+		}
+		info.Bounds = &bounds
+	}
+
+	if font.HasTable(TagGsub) {
+		gsub, err := font.GsubTable()
+		if err != nil {
+			return nil, err
+		}
+		info.Features = gsub.glyphFeatures(info.GlyphID)
+	}
+
+	maxp, err := font.MaxpTable()
+	if err != nil {
+		return nil, err
+	}
+	for gid := 0; gid < int(maxp.NumGlyphs); gid++ {
+		other := uint16(gid)
+		var name string
+		if gid < len(names) {
+			name = names[gid]
+		}
+		before, err := font.KerningValue(other, info.GlyphID, nil)
+		if err != nil {
+			return nil, err
+		}
+		if before != 0 {
+			info.KernedBefore = append(info.KernedBefore, KernPair{other, name, before})
+		}
+		after, err := font.KerningValue(info.GlyphID, other, nil)
+		if err != nil {
+			return nil, err
+		}
+		if after != 0 {
+			info.KernedAfter = append(info.KernedAfter, KernPair{other, name, after})
+		}
+	}
+	return info, nil
+}
+
+// glyphFeatures returns the tags of the features that use a lookup that
+// applies to gid, sorted and without duplicates.
+func (t *TableLayout) glyphFeatures(gid uint16) []Tag {
+	applies := map[uint16]bool{}
+	var tags []Tag
+	for _, f := range t.Features {
+		if hasTag(tags, f.Tag) {
+			continue
+		}
+		for _, i := range f.LookupIndices {
+			if int(i) >= len(t.Lookups) {
+				continue
+			}
+			if _, checked := applies[i]; !checked {
+				applies[i] = t.Lookups[i].covers(gid)
+			}
+			if applies[i] {
+				tags = append(tags, f.Tag)
+				break
+			}
+		}
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Number < tags[j].Number })
+	return tags
+}
+
+// GSUB lookup types that match glyphs in context.
+const (
+	gsubContext      = 5
+	gsubChainContext = 6
+)
+
+// covers returns true if gid is in the coverage of one of the subtables of a
+// GSUB lookup, which for contextual lookups is the coverage of the first input
+// glyph.
+func (l *Lookup) covers(gid uint16) bool {
+	subtables, lookupType := l.extensionSubtables(gsubExtension)
+	for _, sub := range subtables {
+		if len(sub) < 4 {
+			continue
+		}
+		offset := 2
+		if binary.BigEndian.Uint16(sub) == 3 {
+			switch lookupType {
+			case gsubContext:
+				offset = 6
+			case gsubChainContext:
+				// Skip the backtrack coverages and the input glyph count.
+				offset = 6 + 2*int(binary.BigEndian.Uint16(sub[2:]))
+			}
+		}
+		if offset+2 > len(sub) {
+			continue
+		}
+		if _, found := coverageIndex(at(sub, int(binary.BigEndian.Uint16(sub[offset:]))), gid); found {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package sfnt
+
+import (
+	"testing"
+)
+
+func TestInspectChar(t *testing.T) {
+	font := parseTestFont(t, "Roboto-BoldItalic.ttf")
+	cmap, err := font.CmapTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, _ := cmap.Lookup('A')
+	v, _ := cmap.Lookup('V')
+
+	info, err := font.InspectChar('V')
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.Mapped || info.GlyphID != v || info.Advance == 0 || info.Bounds == nil || info.Bounds.YMax <= 0 {
+		t.Errorf("InspectChar('V') = %+v, want glyph %d with metrics", info, v)
+	}
+	if !hasTag(info.Features, MustNamedTag("c2sc")) {
+		t.Errorf("InspectChar('V').Features = %v, want c2sc", info.Features)
+	}
+
+	want, err := font.KerningValue(a, v, nil)
+	if err != nil || want == 0 {
+		t.Fatalf("KerningValue(A, V) = %v, %v; want a kerning pair", want, err)
+	}
+	found := false
+	for _, pair := range info.KernedBefore {
+		if pair.GlyphID == a {
+			found = pair.Value == want
+		}
+	}
+	if !found {
+		t.Errorf("InspectChar('V').KernedBefore does not contain {%d %v}", a, want)
+	}
+
+	info, err = font.InspectChar('☃')
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mapped || info.GlyphID != 0 || info.GlyphName != ".notdef" {
+		t.Errorf("InspectChar('☃') = %+v, want unmapped .notdef", info)
+	}
+}
@@ -0,0 +1,357 @@
+package sfnt
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	axisWeight = MustNamedTag("wght")
+	axisWidth  = MustNamedTag("wdth")
+	axisItalic = MustNamedTag("ital")
+	axisSlant  = MustNamedTag("slnt")
+)
+
+// styleName is the name of a position on an axis.
+type styleName struct {
+	Value    float64
+	Name     string
+	Elidable bool
+}
+
+// standardStyleNames are the names used for positions on the registered axes
+// when the font's 'STAT' table does not name them.
+var standardStyleNames = map[Tag][]styleName{
+	axisWeight: {
+		{100, "Thin", false}, {200, "ExtraLight", false}, {300, "Light", false},
+		{400, "Regular", true}, {500, "Medium", false}, {600, "SemiBold", false},
+		{700, "Bold", false}, {800, "ExtraBold", false}, {900, "Black", false},
+	},
+	axisWidth: {
+		{50, "UltraCondensed", false}, {62.5, "ExtraCondensed", false}, {75, "Condensed", false},
+		{87.5, "SemiCondensed", false}, {100, "Normal", true}, {112.5, "SemiExpanded", false},
+		{125, "Expanded", false}, {150, "ExtraExpanded", false}, {200, "UltraExpanded", false},
+	},
+	axisItalic: {{0, "Roman", true}, {1, "Italic", false}},
+	axisSlant:  {{0, "Upright", true}},
+}
+
+const (
+	fsSelectionItalic  = 0x0001
+	fsSelectionBold    = 0x0020
+	fsSelectionRegular = 0x0040
+	fsSelectionOblique = 0x0200
+
+	macStyleBold   = 0x0001
+	macStyleItalic = 0x0002
+)
+
+// UpdateInstanceStyle updates the style of a font that has been instanced at the
+// given user coordinates (for example with LimitAxis), so that it is grouped
+// correctly with the rest of its family in font menus.
+//
+// The style name is made from the names of the coordinates in 'STAT', or from
+// the standard names for the registered axes. It is used to update the family
+// and style names (ids 1, 2, 4, 6, 16 and 17), and name id 25 is kept only while
+// the font still varies. The weight, width, italic and bold flags in 'OS/2' and
+// 'head' are updated from the coordinates, and a 'STAT' table is generated or
+// updated to describe the instance.
+func (font *Font) UpdateInstanceStyle(location map[Tag]float64) error {
+	name, err := font.NameTable()
+	if err != nil {
+		return err
+	}
+	var fvar *TableFvar
+	if font.HasTable(TagFvar) {
+		if fvar, err = font.FvarTable(); err != nil {
+			return err
+		}
+	}
+	stat := &TableStat{baseTable: baseTable(TagStat), ElidedFallbackNameID: NameFontSubfamily}
+	if font.HasTable(TagStat) {
+		if stat, err = font.StatTable(); err != nil {
+			return err
+		}
+	}
+
+	// Each pinned axis needs a design axis record in 'STAT'.
+	tags := make([]Tag, 0, len(location))
+	for tag := range location {
+		tags = append(tags, tag)
+	}
+	sortTags(tags)
+	if fvar != nil {
+		for _, axis := range fvar.Axes {
+			statAxisIndex(stat, axis.Tag, axis.NameID)
+		}
+	}
+	for _, tag := range tags {
+		if statAxisIndex(stat, tag, 0) == -1 {
+			info, _ := LookupAxis(tag)
+			nameID, err := styleNameID(name, info.Name)
+			if err != nil {
+				return err
+			}
+			statAxisIndex(stat, tag, nameID)
+		}
+	}
+	sort.SliceStable(tags, func(i, j int) bool {
+		return stat.Axes[statAxisIndex(stat, tags[i], 0)].Ordering < stat.Axes[statAxisIndex(stat, tags[j], 0)].Ordering
+	})
+
+	// Find the name of each coordinate, and remove the 'STAT' values for other
+	// positions on the pinned axes.
+	var style, legacy []string
+	bold, italic, oblique := false, false, false
+	for _, tag := range tags {
+		v := location[tag]
+		index := statAxisIndex(stat, tag, 0)
+		part, err := statStyleName(stat, name, fvar, index, v)
+		if err != nil {
+			return err
+		}
+
+		ribbi := false
+		switch tag {
+		case axisWeight:
+			bold = v == 700
+			ribbi = bold
+		case axisItalic:
+			italic = italic || v == 1
+			ribbi = v == 1
+		case axisSlant:
+			oblique = v != 0
+			italic = italic || oblique
+			ribbi = oblique
+		}
+		if !part.Elidable {
+			style = append(style, part.Name)
+			if !ribbi {
+				legacy = append(legacy, part.Name)
+			}
+		}
+	}
+
+	// Axes that are not pinned keep the style of the font.
+	os2, err := font.OS2Table()
+	if err != nil {
+		return err
+	}
+	fsSelection := os2.FsSelection
+	if _, found := location[axisWeight]; !found {
+		bold = fsSelection&fsSelectionBold != 0
+	}
+	_, hasItal := location[axisItalic]
+	_, hasSlnt := location[axisSlant]
+	if !hasItal && !hasSlnt {
+		italic = fsSelection&fsSelectionItalic != 0
+		oblique = fsSelection&fsSelectionOblique != 0
+	}
+
+	if err := updateStyleNames(name, fvar, stat, style, legacy, bold, italic); err != nil {
+		return err
+	}
+	font.AddTable(TagName, name)
+	font.AddTable(TagStat, stat)
+
+	if v, found := location[axisWeight]; found {
+		os2.USWeightClass = uint16(math.Max(1, math.Min(1000, math.Round(v))))
+	}
+	if v, found := location[axisWidth]; found {
+		os2.USWidthClass = WidthClass(v)
+	}
+	fsSelection &^= fsSelectionItalic | fsSelectionBold | fsSelectionRegular | fsSelectionOblique
+	switch {
+	case bold && italic:
+		fsSelection |= fsSelectionBold | fsSelectionItalic
+	case bold:
+		fsSelection |= fsSelectionBold
+	case italic:
+		fsSelection |= fsSelectionItalic
+	default:
+		fsSelection |= fsSelectionRegular
+	}
+	// The oblique flag was added in version 4 of the table.
+	if oblique && os2.Version >= 4 {
+		fsSelection |= fsSelectionOblique
+	}
+	os2.FsSelection = fsSelection
+	font.AddTable(TagOS2, os2)
+
+	head, err := font.HeadTable()
+	if err != nil {
+		return err
+	}
+	head.MacStyle &^= macStyleBold | macStyleItalic
+	if bold {
+		head.MacStyle |= macStyleBold
+	}
+	if italic {
+		head.MacStyle |= macStyleItalic
+	}
+	font.AddTable(font.headTag(), head)
+	return nil
+}
+
+// updateStyleNames sets the family and style names of the font from the names
+// of the non-elidable coordinates in style. Legacy contains the names other
+// than Bold and Italic, which are part of the legacy family name.
+func updateStyleNames(name *TableName, fvar *TableFvar, stat *TableStat, style, legacy []string, bold, italic bool) error {
+	family := name.Lookup(NamePreferredFamily)
+	if family == "" {
+		family = name.Lookup(NameFontFamily)
+	}
+	psFamily := name.VariationsPostscriptPrefix()
+
+	subfamily := strings.Join(style, " ")
+	if subfamily == "" {
+		subfamily = name.Lookup(stat.ElidedFallbackNameID)
+	}
+	if subfamily == "" {
+		subfamily = "Regular"
+	}
+	legacyFamily := strings.Join(append([]string{family}, legacy...), " ")
+	legacySubfamily := "Regular"
+	switch {
+	case bold && italic:
+		legacySubfamily = "Bold Italic"
+	case bold:
+		legacySubfamily = "Bold"
+	case italic:
+		legacySubfamily = "Italic"
+	}
+
+	names := map[NameID]string{
+		NameFontFamily:         legacyFamily,
+		NameFontSubfamily:      legacySubfamily,
+		NameFull:               family + " " + subfamily,
+		NamePostscript:         limitPostscriptName(psFamily, psFamily+"-"+postscriptName(subfamily)),
+		NamePreferredFamily:    family,
+		NamePreferredSubfamily: subfamily,
+	}
+
+	// The prefix is only used to name the instances of a variable font.
+	name.Remove(NameVariationsPostscriptPrefix)
+	if fvar != nil {
+		for _, axis := range fvar.Axes {
+			if axis.Min != axis.Max {
+				names[NameVariationsPostscriptPrefix] = psFamily
+			}
+		}
+	}
+
+	for _, id := range []NameID{NameFontFamily, NameFontSubfamily, NameFull, NamePostscript, NamePreferredFamily, NamePreferredSubfamily, NameVariationsPostscriptPrefix} {
+		if value, found := names[id]; found {
+			name.Remove(id)
+			if err := name.AddMicrosoftEnglishEntry(id, value); err != nil {
+				return fmt.Errorf("name %d: %s", id, err)
+			}
+		}
+	}
+	return nil
+}
+
+// statStyleName returns the name of the position v on the axis with the given
+// index in stat, and removes the other values for the axis from stat. If stat
+// does not name the position, a value is added with a standard name.
+func statStyleName(stat *TableStat, name *TableName, fvar *TableFvar, index int, v float64) (styleName, error) {
+	found := -1
+	values := stat.Values[:0]
+	for _, value := range stat.Values {
+		uses, matches := false, true
+		for _, location := range value.Locations {
+			if int(location.AxisIndex) == index {
+				uses = true
+				matches = matches && (location.Value == v || value.Format == 2 && value.RangeMin <= v && v <= value.RangeMax)
+			}
+		}
+		if uses && !matches {
+			continue
+		}
+		values = append(values, value)
+		if found == -1 && value.Matches(index, v) {
+			found = len(values) - 1
+		}
+	}
+	stat.Values = values
+	if found != -1 {
+		return styleName{v, name.Lookup(values[found].NameID), values[found].Elidable()}, nil
+	}
+
+	tag := stat.Axes[index].Tag
+	axisName := name.Lookup(stat.Axes[index].NameID)
+	if axisName == "" {
+		info, _ := LookupAxis(tag)
+		axisName = info.Name
+	}
+	part := styleName{Value: v, Name: axisName + " " + strconv.FormatFloat(v, 'f', -1, 64)}
+	if fvar != nil {
+		for _, axis := range fvar.Axes {
+			if axis.Tag == tag && axis.Default == v {
+				part.Elidable = true
+			}
+		}
+	}
+	for _, standard := range standardStyleNames[tag] {
+		if standard.Value == v {
+			part = standard
+		}
+	}
+
+	value := StatAxisValue{Format: 1, Locations: []StatAxisLocation{{uint16(index), v}}}
+	if part.Elidable {
+		value.Flags |= statElidableAxisValueName
+	}
+	var err error
+	if value.NameID, err = styleNameID(name, part.Name); err != nil {
+		return part, err
+	}
+	stat.Values = append(stat.Values, value)
+	return part, nil
+}
+
+// statAxisIndex returns the index of the design axis with the given tag in stat.
+// If there is no such axis and nameID is not 0, it is added.
+func statAxisIndex(stat *TableStat, tag Tag, nameID NameID) int {
+	for i, axis := range stat.Axes {
+		if axis.Tag == tag {
+			return i
+		}
+	}
+	if nameID == 0 {
+		return -1
+	}
+	stat.Axes = append(stat.Axes, StatAxis{Tag: tag, NameID: nameID, Ordering: uint16(len(stat.Axes))})
+	return len(stat.Axes) - 1
+}
+
+// styleNameID returns the id of a font-specific entry in the name table with the
+// given value, adding one if needed.
+func styleNameID(name *TableName, value string) (NameID, error) {
+	next := NameID(256)
+	for _, entry := range name.List() {
+		if entry.NameID < 256 {
+			continue
+		}
+		if entry.String() == value {
+			return entry.NameID, nil
+		}
+		if entry.NameID >= next {
+			next = entry.NameID + 1
+		}
+	}
+	return next, name.AddMicrosoftEnglishEntry(next, value)
+}
+
+// postscriptName removes the characters that are not allowed in PostScript names.
+func postscriptName(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 || strings.ContainsRune("[](){}<>/%", r) {
+			return -1
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,133 @@
+package sfnt
+
+import "testing"
+
+// instanceStyleTestFont returns a font in the "Test Sans" family with the given axes.
+func instanceStyleTestFont(t *testing.T, axes ...VariationAxis) *Font {
+	b := NewBuilder(1000)
+	b.SetName(NameFontFamily, "Test Sans")
+	b.SetName(256, "Weight")
+	b.SetName(257, "Width")
+	b.SetName(258, "Italic")
+	font, err := b.Font()
+	if err != nil {
+		t.Fatal(err)
+	}
+	font.AddTable(TagFvar, &TableFvar{baseTable: baseTable(TagFvar), Axes: axes})
+	return font
+}
+
+func checkNames(t *testing.T, font *Font, want map[NameID]string) {
+	name, err := font.NameTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for id, value := range want {
+		if got := name.Lookup(id); got != value {
+			t.Errorf("name %d = %q, want %q", id, got, value)
+		}
+	}
+}
+
+func TestUpdateInstanceStyle(t *testing.T) {
+	font := instanceStyleTestFont(t,
+		VariationAxis{Tag: axisWeight, Min: 700, Default: 700, Max: 700, NameID: 256},
+		VariationAxis{Tag: axisWidth, Min: 75, Default: 75, Max: 75, NameID: 257},
+		VariationAxis{Tag: axisItalic, Min: 1, Default: 1, Max: 1, NameID: 258},
+	)
+	if err := font.UpdateInstanceStyle(map[Tag]float64{axisWeight: 700, axisWidth: 75, axisItalic: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	checkNames(t, font, map[NameID]string{
+		NameFontFamily:                 "Test Sans Condensed",
+		NameFontSubfamily:              "Bold Italic",
+		NameFull:                       "Test Sans Bold Condensed Italic",
+		NamePostscript:                 "TestSans-BoldCondensedItalic",
+		NamePreferredFamily:            "Test Sans",
+		NamePreferredSubfamily:         "Bold Condensed Italic",
+		NameVariationsPostscriptPrefix: "",
+	})
+
+	os2, err := font.OS2Table()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if os2.USWeightClass != 700 || os2.USWidthClass != 3 || os2.FsSelection != 0x00A1 {
+		t.Errorf("OS/2 weight, width, fsSelection = %d, %d, %#x, want 700, 3, 0xa1", os2.USWeightClass, os2.USWidthClass, os2.FsSelection)
+	}
+	head, err := font.HeadTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if head.MacStyle != macStyleBold|macStyleItalic {
+		t.Errorf("head macStyle = %#x, want 0x3", head.MacStyle)
+	}
+
+	stat, err := font.StatTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stat.Axes) != 3 || len(stat.Values) != 3 {
+		t.Fatalf("STAT has %d axes and %d values, want 3 and 3", len(stat.Axes), len(stat.Values))
+	}
+	name, err := font.NameTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := name.Lookup(stat.Values[1].NameID); got != "Condensed" || !stat.Values[1].Matches(1, 75) {
+		t.Errorf("STAT Values[1] = %q %+v, want Condensed at 75", got, stat.Values[1])
+	}
+}
+
+func TestUpdateInstanceStyleStat(t *testing.T) {
+	font := instanceStyleTestFont(t,
+		VariationAxis{Tag: axisWeight, Min: 400, Default: 400, Max: 400, NameID: 256},
+		VariationAxis{Tag: axisWidth, Min: 75, Default: 100, Max: 100, NameID: 257},
+	)
+	name, err := font.NameTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	name.AddMicrosoftEnglishEntry(NameVariationsPostscriptPrefix, "TestSansVF")
+	name.AddMicrosoftEnglishEntry(259, "Book")
+	name.AddMicrosoftEnglishEntry(260, "Heavy")
+	font.AddTable(TagStat, &TableStat{
+		baseTable: baseTable(TagStat),
+		Axes:      []StatAxis{{axisWidth, 257, 1}, {axisWeight, 256, 0}},
+		Values: []StatAxisValue{
+			{Format: 2, Flags: statElidableAxisValueName, NameID: 259, Locations: []StatAxisLocation{{1, 400}}, RangeMin: 350, RangeMax: 450},
+			{Format: 1, NameID: 260, Locations: []StatAxisLocation{{1, 800}}},
+			{Format: 1, NameID: 2, Locations: []StatAxisLocation{{0, 100}}},
+		},
+		ElidedFallbackNameID: 2,
+	})
+	os2, err := font.OS2Table()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os2.FsSelection = fsSelectionItalic
+
+	if err := font.UpdateInstanceStyle(map[Tag]float64{axisWeight: 420}); err != nil {
+		t.Fatal(err)
+	}
+	checkNames(t, font, map[NameID]string{
+		NameFontFamily:                 "Test Sans",
+		NameFontSubfamily:              "Italic",
+		NameFull:                       "Test Sans Regular",
+		NamePostscript:                 "TestSansVF-Regular",
+		NamePreferredSubfamily:         "Regular",
+		NameVariationsPostscriptPrefix: "TestSansVF",
+	})
+
+	stat, err := font.StatTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stat.Axes) != 2 || len(stat.Values) != 2 || stat.Values[0].NameID != 259 || stat.Values[1].NameID != 2 {
+		t.Errorf("STAT = %+v, want the Book and width values", stat)
+	}
+	if os2, err := font.OS2Table(); err != nil || os2.USWeightClass != 420 || os2.FsSelection != 0x0001 {
+		t.Errorf("OS/2 = %+v, %v, want weight 420 and italic", os2, err)
+	}
+}
@@ -0,0 +1,232 @@
+package sfnt
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+var tagCvt = MustNamedTag("cvt ")
+
+// Instantiate makes a static font from a variable font with TrueType outlines,
+// at the given user coordinates. Axes missing from location are at their
+// default. The outlines and metrics of each glyph are varied by 'gvar' (with
+// the advance widths from 'HVAR' if the font has one), and the values in
+// 'cvt ' by 'cvar', and then the variation tables are removed.
+//
+// The deltas in 'GPOS' and 'MVAR' are not applied, so kerning and the font-wide
+// metrics keep the values of the default instance. Use UpdateInstanceStyle to
+// rename the font after instancing it.
+func (font *Font) Instantiate(location map[Tag]float64) error {
+	if font.HasTable(tagCFF2) {
+		return fmt.Errorf("instancing fonts with CFF2 outlines is not supported")
+	}
+	if !font.HasTable(TagFvar) {
+		return fmt.Errorf("font has no variation axes")
+	}
+	coords, err := font.NormalizedCoordinates(location)
+	if err != nil {
+		return err
+	}
+
+	if font.HasTable(TagGvar) && font.HasTable(TagGlyf) {
+		if err := font.instantiateGlyphs(coords); err != nil {
+			return err
+		}
+	}
+	if font.HasTable(TagCvar) && font.HasTable(tagCvt) {
+		if err := font.instantiateCvt(coords); err != nil {
+			return err
+		}
+	}
+	for _, tag := range []Tag{TagFvar, TagAvar, TagGvar, TagCvar, tagHvar, tagVvar, tagMvar} {
+		font.RemoveTable(tag)
+	}
+	return nil
+}
+
+// instantiateGlyphs applies the variations of each glyph at coords to 'glyf',
+// 'hmtx' and the bounding box in 'head'.
+func (font *Font) instantiateGlyphs(coords []float64) error {
+	hmtx, err := font.HmtxTable()
+	if err != nil {
+		return err
+	}
+	v, err := font.glyphVariations(hmtx, coords)
+	if err != nil {
+		return err
+	}
+	var hvar *hvarAdvances
+	if font.HasTable(tagHvar) {
+		if hvar, err = font.hvarAdvances(); err != nil {
+			return err
+		}
+	}
+	head, err := font.HeadTable()
+	if err != nil {
+		return err
+	}
+
+	glyf := v.glyf
+	origins := make([]float64, glyf.NumGlyphs())
+	advances := make([]float64, glyf.NumGlyphs())
+	for i := range glyf.Glyphs {
+		gid := uint16(i)
+		glyph, points, _, err := v.points(gid)
+		if err != nil {
+			return err
+		}
+		n := 0
+		for _, contour := range glyph.Contours {
+			for j := range contour {
+				contour[j].X, contour[j].Y = roundInt16(points[n].X), roundInt16(points[n].Y)
+				n++
+			}
+		}
+		for j := range glyph.Components {
+			if glyph.Components[j].Flags&glyfArgsAreXY != 0 {
+				glyph.Components[j].Arg1, glyph.Components[j].Arg2 = roundInt16(points[n].X), roundInt16(points[n].Y)
+			}
+			n++
+		}
+		phantom := points[n:]
+		origins[i], advances[i] = phantom[0].X, phantom[1].X-phantom[0].X
+		if hvar != nil {
+			advances[i] = float64(hmtx.Advance(gid)) + hvar.delta(gid, coords)
+		}
+
+		if len(glyph.Contours) > 0 {
+			glyph.UpdateBounds()
+		}
+		if err := glyf.SetGlyph(gid, glyph); err != nil {
+			return err
+		}
+	}
+
+	if err := glyf.updateBounds(head); err != nil {
+		return err
+	}
+	for i := range glyf.Glyphs {
+		if i >= len(hmtx.Metrics) {
+			break
+		}
+		glyph, err := glyf.Glyph(uint16(i))
+		if err != nil {
+			return err
+		}
+		hmtx.Metrics[i] = HMetric{
+			AdvanceWidth:    uint16(math.Max(0, roundCoordinate(advances[i]))),
+			LeftSideBearing: glyph.XMin - roundInt16(origins[i]),
+		}
+	}
+
+	font.AddTable(TagGlyf, glyf)
+	font.AddTable(TagHmtx, hmtx)
+	font.AddTable(TagHead, head)
+	return nil
+}
+
+// instantiateCvt applies the variations in 'cvar' at coords to the values in
+// the 'cvt ' table.
+func (font *Font) instantiateCvt(coords []float64) error {
+	cvar, err := font.CvarTable()
+	if err != nil {
+		return err
+	}
+	cvt, err := font.TableData(tagCvt)
+	if err != nil {
+		return err
+	}
+	values := make([]float64, len(cvt)/2)
+	for i := range values {
+		values[i] = float64(int16(binary.BigEndian.Uint16(cvt[2*i:])))
+	}
+
+	for i, tuple := range cvar.Variations.Tuples {
+		scalar := regionScalar(tuple.Region(), coords)
+		if scalar == 0 {
+			continue
+		}
+		packed := tuple.points
+		if packed == nil {
+			packed = cvar.Variations.sharedPoints
+		}
+		var indices []int
+		if packed != nil {
+			if indices, err = unpackPoints(packed); err != nil {
+				return fmt.Errorf("reading cvar: points of tuple %d: %s", i, err)
+			}
+		}
+		if indices == nil {
+			indices = make([]int, len(values))
+			for j := range indices {
+				indices[j] = j
+			}
+		}
+		if len(tuple.Deltas) != len(indices) {
+			return fmt.Errorf("reading cvar: tuple %d has %d deltas for %d values", i, len(tuple.Deltas), len(indices))
+		}
+		for j, index := range indices {
+			if index < len(values) {
+				values[index] += scalar * float64(tuple.Deltas[j])
+			}
+		}
+	}
+
+	buf := make([]byte, 0, len(cvt))
+	for _, v := range values {
+		buf = appendUint16s(buf, uint16(roundInt16(v)))
+	}
+	font.SetTable(tagCvt, buf)
+	return nil
+}
+
+// updateBounds recalculates the bounds of the composite glyphs from their
+// resolved contours, which must already be in place, and the bounding box of
+// all the glyphs in head.
+func (table *TableGlyf) updateBounds(head *TableHead) error {
+	first := true
+	for i := range table.Glyphs {
+		gid := uint16(i)
+		glyph, err := table.Glyph(gid)
+		if err != nil {
+			return err
+		}
+		if len(glyph.Components) > 0 {
+			contours, err := table.Contours(gid)
+			if err != nil {
+				return err
+			}
+			components := glyph.Components
+			glyph.Components, glyph.Contours = nil, contours
+			glyph.UpdateBounds()
+			glyph.Components, glyph.Contours = components, nil
+			if err := table.SetGlyph(gid, glyph); err != nil {
+				return err
+			}
+		}
+
+		if glyph.IsEmpty() {
+			continue
+		}
+		if first || glyph.XMin < head.XMin {
+			head.XMin = glyph.XMin
+		}
+		if first || glyph.YMin < head.YMin {
+			head.YMin = glyph.YMin
+		}
+		if first || glyph.XMax > head.XMax {
+			head.XMax = glyph.XMax
+		}
+		if first || glyph.YMax > head.YMax {
+			head.YMax = glyph.YMax
+		}
+		first = false
+	}
+	return nil
+}
+
+// roundInt16 rounds v to the nearest int16, as by roundCoordinate.
+func roundInt16(v float64) int16 {
+	return clampInt16(int(roundCoordinate(v)))
+}
@@ -0,0 +1,83 @@
+package sfnt
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestInstantiate(t *testing.T) {
+	font, sq, moved := variedTestFont(t)
+	font.SetTable(tagCvt, appendUint16s(nil, 100, 200))
+	font.AddTable(TagCvar, &TableCvar{
+		baseTable:  baseTable(TagCvar),
+		Variations: TupleVariations{Tuples: []TupleVariation{{Peak: []float64{1}, Deltas: []int16{10, -20}, points: []byte{0}}}},
+	})
+
+	if err := font.Instantiate(map[Tag]float64{axisWeight: 900}); err != nil {
+		t.Fatal(err)
+	}
+	for _, tag := range []Tag{TagFvar, TagGvar, TagCvar} {
+		if font.HasTable(tag) {
+			t.Errorf("Instantiate() kept the %s table", tag)
+		}
+	}
+
+	var buf bytes.Buffer
+	if _, err := font.WriteOTF(&buf); err != nil {
+		t.Fatal(err)
+	}
+	static, err := Parse(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	glyf, err := static.GlyfTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	hmtx, err := static.HmtxTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		gid    uint16
+		bounds [4]int16
+		metric HMetric
+	}{
+		{sq, [4]int16{100, 0, 600, 500}, HMetric{650, 100}},
+		{moved, [4]int16{1120, 0, 1620, 500}, HMetric{1200, 1120}},
+	} {
+		glyph, err := glyf.Glyph(test.gid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bounds := [4]int16{glyph.XMin, glyph.YMin, glyph.XMax, glyph.YMax}; bounds != test.bounds {
+			t.Errorf("glyph %d bounds = %v, want %v", test.gid, bounds, test.bounds)
+		}
+		if hmtx.Metrics[test.gid] != test.metric {
+			t.Errorf("glyph %d metrics = %v, want %v", test.gid, hmtx.Metrics[test.gid], test.metric)
+		}
+	}
+	contours, err := glyf.Contours(sq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [][]GlyphPoint{{{100, 0, true}, {100, 500, true}, {600, 500, true}, {600, 0, true}}}; !reflect.DeepEqual(contours, want) {
+		t.Errorf("Contours(%d) = %v, want %v", sq, contours, want)
+	}
+
+	head, err := static.HeadTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if head.XMax != 1620 || head.YMax != 700 {
+		t.Errorf("head bounds = %d, %d, %d, %d, want a maximum of 1620, 700", head.XMin, head.YMin, head.XMax, head.YMax)
+	}
+	if cvt, err := static.TableData(tagCvt); err != nil || !bytes.Equal(cvt, appendUint16s(nil, 110, 180)) {
+		t.Errorf("cvt = %v, %v, want 110, 180", cvt, err)
+	}
+
+	if err := font.Instantiate(nil); err == nil {
+		t.Errorf("Instantiate() of a static font err = nil, want error")
+	}
+}
@@ -0,0 +1,90 @@
+package sfnt
+
+import "sort"
+
+// GlyphInstructions is the size of the TrueType instructions of one glyph.
+type GlyphInstructions struct {
+	GlyphID uint16
+	Name    string // Name is the glyph's name, from the 'post' table or generated.
+	Size    int    // Size is the length of the instructions in bytes.
+}
+
+// InstructionSizes is the space used by the TrueType hinting instructions of a
+// font, to help decide whether to remove the hinting or to hint it again.
+type InstructionSizes struct {
+	// Glyphs are the glyphs that have instructions, with the largest first.
+	Glyphs []GlyphInstructions
+
+	FontProgram   int // FontProgram is the size of the 'fpgm' table.
+	ControlValues int // ControlValues is the size of the 'cvt ' table.
+	Prep          int // Prep is the size of the control value program, the 'prep' table.
+}
+
+// GlyphTotal returns the total size of the instructions of every glyph.
+func (s *InstructionSizes) GlyphTotal() int {
+	total := 0
+	for _, g := range s.Glyphs {
+		total += g.Size
+	}
+	return total
+}
+
+// Total returns the total size of the instructions of the glyphs and the
+// font-wide programs and control values.
+func (s *InstructionSizes) Total() int {
+	return s.GlyphTotal() + s.FontProgram + s.ControlValues + s.Prep
+}
+
+// InstructionSizes returns the size of the instructions of each glyph and of
+// the font-wide hinting tables. Fonts with CFF outlines, whose hints are part
+// of the charstrings, have no instructions.
+func (font *Font) InstructionSizes() (*InstructionSizes, error) {
+	sizes := &InstructionSizes{}
+	for _, table := range []struct {
+		tag  Tag
+		size *int
+	}{
+		{MustNamedTag("fpgm"), &sizes.FontProgram},
+		{tagCvt, &sizes.ControlValues},
+		{MustNamedTag("prep"), &sizes.Prep},
+	} {
+		if !font.HasTable(table.tag) {
+			continue
+		}
+		data, err := font.TableData(table.tag)
+		if err != nil {
+			return nil, err
+		}
+		*table.size = len(data)
+	}
+
+	if !font.HasTable(TagGlyf) {
+		return sizes, nil
+	}
+	glyf, err := font.GlyfTable()
+	if err != nil {
+		return nil, err
+	}
+	names, err := font.glyphNames()
+	if err != nil {
+		return nil, err
+	}
+	for i := range glyf.Glyphs {
+		glyph, err := glyf.Glyph(uint16(i))
+		if err != nil {
+			return nil, err
+		}
+		if glyph == nil || len(glyph.Instructions) == 0 {
+			continue
+		}
+		g := GlyphInstructions{GlyphID: uint16(i), Size: len(glyph.Instructions)}
+		if i < len(names) {
+			g.Name = names[i]
+		}
+		sizes.Glyphs = append(sizes.Glyphs, g)
+	}
+	sort.SliceStable(sizes.Glyphs, func(i, j int) bool {
+		return sizes.Glyphs[i].Size > sizes.Glyphs[j].Size
+	})
+	return sizes, nil
+}
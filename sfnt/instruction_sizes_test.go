@@ -0,0 +1,42 @@
+package sfnt
+
+import "testing"
+
+func TestInstructionSizes(t *testing.T) {
+	font := parseTestFont(t, "open-sans-v15-latin-regular.woff")
+	sizes, err := font.InstructionSizes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sizes.Glyphs) == 0 || sizes.FontProgram == 0 || sizes.Prep == 0 {
+		t.Fatalf("InstructionSizes() = %+v, want hinted glyphs and programs", sizes)
+	}
+	for i := 1; i < len(sizes.Glyphs); i++ {
+		if sizes.Glyphs[i].Size > sizes.Glyphs[i-1].Size {
+			t.Fatalf("Glyphs[%d] is larger than Glyphs[%d], want the largest first", i, i-1)
+		}
+	}
+
+	heaviest := sizes.Glyphs[0]
+	glyf, err := font.GlyfTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	glyph, err := glyf.Glyph(heaviest.GlyphID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if heaviest.Size != len(glyph.Instructions) || heaviest.Name == "" {
+		t.Errorf("Glyphs[0] = %+v, want %d bytes and a name", heaviest, len(glyph.Instructions))
+	}
+	if sizes.Total() != sizes.GlyphTotal()+sizes.FontProgram+sizes.ControlValues+sizes.Prep {
+		t.Errorf("Total() = %d, want the sum of the glyphs and tables", sizes.Total())
+	}
+
+	for _, name := range []string{"Roboto-BoldItalic.ttf", "Raleway-v4020-Regular.otf"} {
+		unhinted := parseTestFont(t, name)
+		if sizes, err := unhinted.InstructionSizes(); err != nil || sizes.Total() != 0 {
+			t.Errorf("InstructionSizes() of %s = %+v, %v; want none", name, sizes, err)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package sfnt
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// Instrumentation receives events as fonts are parsed, so that services can
+// record metrics or traces (for example with Prometheus or OpenTelemetry)
+// without this package depending on them. Set it with SetInstrumentation.
+//
+// Parse only reads the table directory; each table is parsed when it is first
+// used, or by StrictParse, so TableParsed is usually called after
+// ParseFinished. Methods may be called concurrently, from several goroutines.
+type Instrumentation interface {
+	// ParseStarted is called when Parse, or one of its variants, starts.
+	ParseStarted()
+	// TableParsed is called after each table is parsed.
+	TableParsed(event TableEvent)
+	// ParseFinished is called when Parse, or one of its variants, returns.
+	ParseFinished(event ParseEvent)
+}
+
+// ParseEvent describes a call to Parse.
+type ParseEvent struct {
+	Format   Format        // Format is the format of the file, from its magic number.
+	Size     int64         // Size is the size of the file in bytes, or -1 if it is not known.
+	Tables   int           // Tables is the number of tables in the font.
+	Duration time.Duration // Duration is how long parsing took.
+	Err      error         // Err is the error returned, if parsing failed.
+}
+
+// TableEvent describes the parsing of a table.
+type TableEvent struct {
+	Tag      Tag
+	Size     int           // Size is the uncompressed size of the table in bytes.
+	Duration time.Duration // Duration is how long reading and parsing the table took.
+	Err      error         // Err is the error returned, if parsing failed.
+}
+
+var instrumentationMu sync.RWMutex
+
+var instrumentation Instrumentation
+
+// SetInstrumentation sets the Instrumentation that receives events for every
+// font parsed from then on, replacing any that was set before. Nil, the
+// default, turns instrumentation off. It is usually called from an init
+// function, or when a service starts.
+func SetInstrumentation(i Instrumentation) {
+	instrumentationMu.Lock()
+	defer instrumentationMu.Unlock()
+	instrumentation = i
+}
+
+// currentInstrumentation returns the Instrumentation set with
+// SetInstrumentation, or nil.
+func currentInstrumentation() Instrumentation {
+	instrumentationMu.RLock()
+	defer instrumentationMu.RUnlock()
+	return instrumentation
+}
+
+// fileSize returns the size of the file, or -1 if it cannot be found. The file
+// is left at its start.
+func fileSize(file File) int64 {
+	size, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return -1
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return -1
+	}
+	return size
+}
+
+// formatForMagic returns the format of a file that starts with magic, as
+// recognized by Parse.
+func formatForMagic(magic Tag) Format {
+	switch magic {
+	case SignatureWOFF:
+		return FormatWOFF
+	case SignatureWOFF2:
+		return FormatWOFF2
+	case TypeOpenType:
+		return FormatOpenType
+	case TypeTrueType, TypePostScript1, TypeAppleTrueType:
+		return FormatTrueType
+	}
+	return FormatUnknown
+}
@@ -0,0 +1,89 @@
+package sfnt
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// recordingInstrumentation records the events it receives.
+type recordingInstrumentation struct {
+	mu      sync.Mutex
+	started int
+	tables  []TableEvent
+	parses  []ParseEvent
+}
+
+func (r *recordingInstrumentation) ParseStarted() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.started++
+}
+
+func (r *recordingInstrumentation) TableParsed(event TableEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tables = append(r.tables, event)
+}
+
+func (r *recordingInstrumentation) ParseFinished(event ParseEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.parses = append(r.parses, event)
+}
+
+func TestInstrumentation(t *testing.T) {
+	data, err := ioutil.ReadFile(filepath.Join("testdata", "open-sans-v15-latin-regular.woff"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &recordingInstrumentation{}
+	SetInstrumentation(r)
+	defer SetInstrumentation(nil)
+
+	font, err := Parse(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.started != 1 || len(r.parses) != 1 {
+		t.Fatalf("got %d ParseStarted and %d ParseFinished, want 1 of each", r.started, len(r.parses))
+	}
+	event := r.parses[0]
+	if event.Format != FormatWOFF || event.Size != int64(len(data)) || event.Tables != len(font.Tags()) || event.Err != nil {
+		t.Errorf("ParseFinished(%+v), want format WOFF, size %d and %d tables", event, len(data), len(font.Tags()))
+	}
+	if len(r.tables) != 0 {
+		t.Errorf("Parse() parsed %d tables, want none until they are used", len(r.tables))
+	}
+
+	name, err := font.TableData(TagName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := font.NameTable(); err != nil {
+		t.Fatal(err)
+	}
+	if len(r.tables) != 1 {
+		t.Fatalf("got %d TableParsed, want 1", len(r.tables))
+	}
+	if table := r.tables[0]; table.Tag != TagName || table.Size != len(name) || table.Err != nil {
+		t.Errorf("TableParsed(%+v), want tag 'name' and size %d", table, len(name))
+	}
+
+	// Tables that depend on others, such as 'hmtx', are reported too.
+	if _, err := font.HmtxTable(); err != nil {
+		t.Fatal(err)
+	}
+	if table := r.tables[len(r.tables)-1]; table.Tag != TagHmtx || table.Err != nil {
+		t.Errorf("TableParsed(%+v), want tag 'hmtx'", table)
+	}
+
+	if _, err := Parse(bytes.NewReader([]byte("not a font"))); err == nil {
+		t.Fatal("Parse() of a text file succeeded")
+	}
+	if event := r.parses[1]; event.Format != FormatUnknown || event.Err == nil {
+		t.Errorf("ParseFinished(%+v), want an unknown format and the error", event)
+	}
+}
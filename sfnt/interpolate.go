@@ -0,0 +1,215 @@
+package sfnt
+
+import (
+	"fmt"
+	"math"
+)
+
+// Interpolate returns a new static font between the masters a and b, where t
+// is 0 for a and 1 for b. Values of t outside that range extrapolate. The
+// masters must have TrueType outlines that can be interpolated, as checked by
+// CheckCompatibility, and the same units per em.
+//
+// The outlines, advance widths and the vertical metrics, weight and width in
+// 'hhea' and 'OS/2' are interpolated. Every other table, including the names,
+// kerning and hinting, is copied from a, so the result should be renamed with
+// UpdateInstanceStyle. Interpolation is experimental: it is intended to make
+// intermediate weights when there is no variable font to instance.
+func Interpolate(a, b *Font, t float64) (*Font, error) {
+	problems, err := CheckCompatibility([]*Font{a, b})
+	if err != nil {
+		return nil, err
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("the masters are not compatible: %s (and %d other problems)", problems[0], len(problems)-1)
+	}
+	headA, err := a.HeadTable()
+	if err != nil {
+		return nil, err
+	}
+	headB, err := b.HeadTable()
+	if err != nil {
+		return nil, err
+	}
+	if headA.UnitsPerEm != headB.UnitsPerEm {
+		return nil, fmt.Errorf("the masters have %d and %d units per em", headA.UnitsPerEm, headB.UnitsPerEm)
+	}
+
+	font := New(a.Type())
+	for _, tag := range a.Tags() {
+		data, err := a.TableData(tag)
+		if err != nil {
+			return nil, err
+		}
+		font.SetTable(tag, data)
+	}
+	if err := font.interpolateGlyphs(b, t); err != nil {
+		return nil, err
+	}
+	if err := font.interpolateMetrics(b, t); err != nil {
+		return nil, err
+	}
+	return font, nil
+}
+
+// interpolateGlyphs interpolates the glyphs and horizontal metrics of the font
+// towards those of b.
+func (font *Font) interpolateGlyphs(b *Font, t float64) error {
+	glyfA, err := font.GlyfTable()
+	if err != nil {
+		return err
+	}
+	glyfB, err := b.GlyfTable()
+	if err != nil {
+		return err
+	}
+	hmtxA, err := font.HmtxTable()
+	if err != nil {
+		return err
+	}
+	hmtxB, err := b.HmtxTable()
+	if err != nil {
+		return err
+	}
+	head, err := font.HeadTable()
+	if err != nil {
+		return err
+	}
+
+	// Glyphs are matched by name when both masters name them, as by
+	// CheckCompatibility.
+	namesA, err := allGlyphNames(font, glyfA.NumGlyphs())
+	if err != nil {
+		return err
+	}
+	namesB, err := allGlyphNames(b, glyfB.NumGlyphs())
+	if err != nil {
+		return err
+	}
+	match := func(gid uint16) uint16 { return gid }
+	if namesA != nil && namesB != nil {
+		ids := map[string]uint16{}
+		for gid := len(namesB) - 1; gid >= 0; gid-- {
+			ids[namesB[gid]] = uint16(gid)
+		}
+		match = func(gid uint16) uint16 { return ids[namesA[gid]] }
+	}
+
+	lerp := func(a, b float64) float64 { return a + (b-a)*t }
+	origin := func(glyph *Glyph, hmtx *TableHmtx, gid uint16) float64 {
+		if int(gid) >= len(hmtx.Metrics) {
+			return 0
+		}
+		return float64(glyph.XMin) - float64(hmtx.Metrics[gid].LeftSideBearing)
+	}
+
+	origins := make([]float64, glyfA.NumGlyphs())
+	advances := make([]float64, glyfA.NumGlyphs())
+	for i := range glyfA.Glyphs {
+		gidA := uint16(i)
+		gidB := match(gidA)
+		glyph, err := glyfA.Glyph(gidA)
+		if err != nil {
+			return err
+		}
+		other, err := glyfB.Glyph(gidB)
+		if err != nil {
+			return err
+		}
+		origins[i] = lerp(origin(glyph, hmtxA, gidA), origin(other, hmtxB, gidB))
+		advances[i] = lerp(float64(hmtxA.Advance(gidA)), float64(hmtxB.Advance(gidB)))
+
+		for j, contour := range glyph.Contours {
+			for k, p := range contour {
+				q := other.Contours[j][k]
+				contour[k].X = roundInt16(lerp(float64(p.X), float64(q.X)))
+				contour[k].Y = roundInt16(lerp(float64(p.Y), float64(q.Y)))
+			}
+		}
+		for j := range glyph.Components {
+			c, d := &glyph.Components[j], other.Components[j]
+			if c.Flags&glyfArgsAreXY != 0 {
+				c.Arg1 = roundInt16(lerp(float64(c.Arg1), float64(d.Arg1)))
+				c.Arg2 = roundInt16(lerp(float64(c.Arg2), float64(d.Arg2)))
+			}
+			for k := range c.Transform {
+				c.Transform[k] = lerp(c.Transform[k], d.Transform[k])
+			}
+		}
+		if len(glyph.Contours) > 0 {
+			glyph.UpdateBounds()
+		}
+		if err := glyfA.SetGlyph(gidA, glyph); err != nil {
+			return err
+		}
+	}
+
+	if err := glyfA.updateBounds(head); err != nil {
+		return err
+	}
+	for i := range glyfA.Glyphs {
+		if i >= len(hmtxA.Metrics) {
+			break
+		}
+		glyph, err := glyfA.Glyph(uint16(i))
+		if err != nil {
+			return err
+		}
+		hmtxA.Metrics[i] = HMetric{
+			AdvanceWidth:    uint16(math.Max(0, roundCoordinate(advances[i]))),
+			LeftSideBearing: glyph.XMin - roundInt16(origins[i]),
+		}
+	}
+
+	font.AddTable(TagGlyf, glyfA)
+	font.AddTable(TagHmtx, hmtxA)
+	font.AddTable(TagHead, head)
+	return nil
+}
+
+// interpolateMetrics interpolates the vertical metrics, weight and width of the
+// font towards those of b.
+func (font *Font) interpolateMetrics(b *Font, t float64) error {
+	lerp := func(a, b int16) int16 { return roundInt16(float64(a) + (float64(b)-float64(a))*t) }
+	lerpU := func(a, b uint16) uint16 {
+		return uint16(math.Max(0, roundCoordinate(float64(a)+(float64(b)-float64(a))*t)))
+	}
+
+	if font.HasTable(TagHhea) && b.HasTable(TagHhea) {
+		hheaA, err := font.HheaTable()
+		if err != nil {
+			return err
+		}
+		hheaB, err := b.HheaTable()
+		if err != nil {
+			return err
+		}
+		hheaA.Ascent = lerp(hheaA.Ascent, hheaB.Ascent)
+		hheaA.Descent = lerp(hheaA.Descent, hheaB.Descent)
+		hheaA.LineGap = lerp(hheaA.LineGap, hheaB.LineGap)
+		font.AddTable(TagHhea, hheaA)
+	}
+
+	if font.HasTable(TagOS2) && b.HasTable(TagOS2) {
+		os2A, err := font.OS2Table()
+		if err != nil {
+			return err
+		}
+		os2B, err := b.OS2Table()
+		if err != nil {
+			return err
+		}
+		os2A.USWeightClass = lerpU(os2A.USWeightClass, os2B.USWeightClass)
+		os2A.USWidthClass = lerpU(os2A.USWidthClass, os2B.USWidthClass)
+		os2A.XAvgCharWidth = lerpU(os2A.XAvgCharWidth, os2B.XAvgCharWidth)
+		os2A.STypoAscender = lerp(os2A.STypoAscender, os2B.STypoAscender)
+		os2A.STypoDescender = lerp(os2A.STypoDescender, os2B.STypoDescender)
+		os2A.STypoLineGap = lerp(os2A.STypoLineGap, os2B.STypoLineGap)
+		os2A.UsWinAscent = lerpU(os2A.UsWinAscent, os2B.UsWinAscent)
+		os2A.UsWinDescent = lerpU(os2A.UsWinDescent, os2B.UsWinDescent)
+		os2A.SxHeigh = lerp(os2A.SxHeigh, os2B.SxHeigh)
+		os2A.SCapHeight = lerp(os2A.SCapHeight, os2B.SCapHeight)
+		font.AddTable(TagOS2, os2A)
+	}
+	return nil
+}
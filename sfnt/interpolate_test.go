@@ -0,0 +1,80 @@
+package sfnt
+
+import (
+	"testing"
+)
+
+func TestInterpolate(t *testing.T) {
+	master := func(size, weight uint16) *Font {
+		b := NewBuilder(1000)
+		sq := b.AddGlyph("square", size+100, [][]GlyphPoint{square(50, 0, int16(size))})
+		b.AddComposite("pair", 2*size+200, []GlyphComponent{{GlyphID: sq, Transform: [4]float64{1, 0, 0, 1}}, {GlyphID: sq, Arg1: int16(size + 100), Transform: [4]float64{1, 0, 0, 1}}})
+		font, err := b.Font()
+		if err != nil {
+			t.Fatal(err)
+		}
+		os2, err := font.OS2Table()
+		if err != nil {
+			t.Fatal(err)
+		}
+		os2.USWeightClass = weight
+		return font
+	}
+	light, bold := master(400, 300), master(600, 700)
+
+	font, err := Interpolate(light, bold, 0.25)
+	if err != nil {
+		t.Fatal(err)
+	}
+	glyf, err := font.GlyfTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	hmtx, err := font.HmtxTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for gid, want := range map[uint16]struct {
+		bounds [4]int16
+		metric HMetric
+	}{
+		1: {[4]int16{50, 0, 500, 450}, HMetric{550, 50}},
+		2: {[4]int16{50, 0, 1050, 450}, HMetric{1100, 50}},
+	} {
+		glyph, err := glyf.Glyph(gid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bounds := [4]int16{glyph.XMin, glyph.YMin, glyph.XMax, glyph.YMax}; bounds != want.bounds {
+			t.Errorf("glyph %d bounds = %v, want %v", gid, bounds, want.bounds)
+		}
+		if hmtx.Metrics[gid] != want.metric {
+			t.Errorf("glyph %d metrics = %v, want %v", gid, hmtx.Metrics[gid], want.metric)
+		}
+	}
+	os2, err := font.OS2Table()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if os2.USWeightClass != 400 {
+		t.Errorf("USWeightClass = %d, want 400", os2.USWeightClass)
+	}
+	lightGlyf, err := light.GlyfTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if glyph, err := lightGlyf.Glyph(1); err != nil || glyph.XMax != 450 {
+		t.Errorf("Interpolate() changed the first master")
+	}
+
+	b := NewBuilder(1000)
+	b.AddGlyph("square", 600, [][]GlyphPoint{square(0, 0, 500)[:3]})
+	b.AddComposite("pair", 600, nil)
+	other, err := b.Font()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Interpolate(light, other, 0.5); err == nil {
+		t.Errorf("Interpolate() of incompatible masters err = nil, want error")
+	}
+}
@@ -0,0 +1,189 @@
+package sfnt
+
+import (
+	"encoding/binary"
+	"sort"
+)
+
+var featureKern = MustNamedTag("kern")
+
+// KerningValue returns the adjustment to the advance of the glyph left when it is
+// followed by the glyph right, in font units.
+//
+// If the font has a 'kern' feature in its GPOS table, the pair adjustment lookups
+// used by that feature in any script are used. Otherwise the 'kerx' table is used
+// if there is one, and then the legacy 'kern' table. A font without kerning
+// returns 0.
+//
+// For variable fonts, coords contains the normalized position (from -1 to 1) on
+// each axis, in the order they appear in the 'fvar' table; any 'avar' mapping must
+// already have been applied. GPOS kerning is adjusted by the variation deltas in
+// the GDEF table. If coords is empty the default instance is used.
+func (font *Font) KerningValue(left, right uint16, coords []float64) (float64, error) {
+	if font.HasTable(TagGpos) {
+		gpos, err := font.GposTable()
+		if err != nil {
+			return 0, err
+		}
+		if lookups := gpos.featureLookups(featureKern); len(lookups) > 0 {
+			var store *ItemVariationStore
+			if len(coords) > 0 && font.HasTable(TagGdef) {
+				gdef, err := font.TableData(TagGdef)
+				if err != nil {
+					return 0, err
+				}
+				if store, err = gdefVariationStore(gdef); err != nil {
+					return 0, err
+				}
+			}
+			var total float64
+			for _, lookup := range lookups {
+				total += lookup.pairAdjustment(left, right, store, coords)
+			}
+			return total, nil
+		}
+	}
+
+	if font.HasTable(TagKerx) {
+		kerx, err := font.KerxTable()
+		if err != nil {
+			return 0, err
+		}
+		return float64(kerx.Kerning(left, right)), nil
+	}
+
+	if font.HasTable(TagKern) {
+		kern, err := font.KernTable()
+		if err != nil {
+			return 0, err
+		}
+		return float64(kern.Kerning(left, right)), nil
+	}
+
+	return 0, nil
+}
+
+// featureLookups returns the lookups used by every feature with one of the given
+// tags, in the order they are applied.
+func (t *TableLayout) featureLookups(tags ...Tag) []*Lookup {
+	seen := map[uint16]bool{}
+	var indices []int
+	for _, f := range t.Features {
+		if !hasTag(tags, f.Tag) {
+			continue
+		}
+		for _, i := range f.LookupIndices {
+			if !seen[i] && int(i) < len(t.Lookups) {
+				seen[i] = true
+				indices = append(indices, int(i))
+			}
+		}
+	}
+	sort.Ints(indices)
+
+	lookups := make([]*Lookup, len(indices))
+	for i, index := range indices {
+		lookups[i] = t.Lookups[index]
+	}
+	return lookups
+}
+
+const (
+	gposPairAdjustment = 2
+	gposExtension      = 9
+)
+
+// pairAdjustment returns the change to the advance of left made by a GPOS pair
+// adjustment lookup. As when shaping, only the first subtable that applies is used.
+func (l *Lookup) pairAdjustment(left, right uint16, store *ItemVariationStore, coords []float64) float64 {
+	subtables, lookupType := l.extensionSubtables(gposExtension)
+	if lookupType != gposPairAdjustment {
+		return 0
+	}
+
+	for _, sub := range subtables {
+		v, _, base, ok := pairPos(sub, left, right)
+		if !ok {
+			continue
+		}
+		value := float64(v.XAdvance)
+		if v.XAdvanceDevice != 0 && len(coords) > 0 {
+			value += store.deviceDelta(at(base, int(v.XAdvanceDevice)), coords)
+		}
+		return value
+	}
+	return 0
+}
+
+// pairPos returns the value records for the first and second glyph of a pair in
+// a pair adjustment subtable, and the data that their device offsets are relative to.
+// ok is false if the subtable does not apply to the pair.
+func pairPos(sub []byte, left, right uint16) (v1, v2 valueRecord, base []byte, ok bool) {
+	if len(sub) < 10 {
+		return v1, v2, nil, false
+	}
+	index, covered := coverageIndex(at(sub, int(binary.BigEndian.Uint16(sub[2:]))), left)
+	if !covered {
+		return v1, v2, nil, false
+	}
+	format1 := binary.BigEndian.Uint16(sub[4:])
+	format2 := binary.BigEndian.Uint16(sub[6:])
+	size1, size2 := valueRecordSize(format1), valueRecordSize(format2)
+
+	var record []byte
+	switch binary.BigEndian.Uint16(sub) {
+	case 1:
+		count := int(binary.BigEndian.Uint16(sub[8:]))
+		if index >= count || len(sub) < 10+2*count {
+			return v1, v2, nil, false
+		}
+		// Device offsets in format 1 are relative to the pair set.
+		base = at(sub, int(binary.BigEndian.Uint16(sub[10+2*index:])))
+		if len(base) < 2 {
+			return v1, v2, nil, false
+		}
+		pairs := int(binary.BigEndian.Uint16(base))
+		size := 2 + size1 + size2
+		if len(base) < 2+pairs*size {
+			return v1, v2, nil, false
+		}
+		i := sort.Search(pairs, func(i int) bool {
+			return binary.BigEndian.Uint16(base[2+i*size:]) >= right
+		})
+		if i == pairs || binary.BigEndian.Uint16(base[2+i*size:]) != right {
+			return v1, v2, nil, false
+		}
+		record = base[2+i*size+2:]
+
+	case 2:
+		if len(sub) < 16 {
+			return v1, v2, nil, false
+		}
+		class1 := classOf(at(sub, int(binary.BigEndian.Uint16(sub[8:]))), left)
+		class2 := classOf(at(sub, int(binary.BigEndian.Uint16(sub[10:]))), right)
+		count1 := int(binary.BigEndian.Uint16(sub[12:]))
+		count2 := int(binary.BigEndian.Uint16(sub[14:]))
+		if int(class1) >= count1 || int(class2) >= count2 {
+			return v1, v2, nil, false
+		}
+		base = sub
+		i := 16 + (int(class1)*count2+int(class2))*(size1+size2)
+		if len(sub) < i+size1+size2 {
+			return v1, v2, nil, false
+		}
+		record = sub[i:]
+
+	default:
+		return v1, v2, nil, false
+	}
+
+	return parseValueRecord(record, format1), parseValueRecord(record[size1:], format2), base, true
+}
+
+// at returns b from offset onwards, or nil if offset is out of range.
+func at(b []byte, offset int) []byte {
+	if offset < 0 || offset >= len(b) {
+		return nil
+	}
+	return b[offset:]
+}
@@ -0,0 +1,113 @@
+package sfnt
+
+import (
+	"testing"
+)
+
+func TestKerningValue(t *testing.T) {
+	for _, name := range []string{"Roboto-BoldItalic.ttf", "Raleway-v4020-Regular.otf"} {
+		font := parseTestFont(t, name)
+		cmap, err := font.CmapTable()
+		if err != nil {
+			t.Fatal(err)
+		}
+		a, _ := cmap.Lookup('A')
+		v, _ := cmap.Lookup('V')
+
+		kern, err := font.KerningValue(uint16(a), uint16(v), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if kern >= 0 {
+			t.Errorf("%s: KerningValue(A, V) = %v, want < 0", name, kern)
+		}
+	}
+
+	font := parseTestFont(t, "Go-Regular.woff2")
+	if kern, err := font.KerningValue(1, 2, nil); err != nil || kern != 0 {
+		t.Errorf("KerningValue() without kerning = %v, %v, want 0, nil", kern, err)
+	}
+}
+
+// kerningTestFont returns a font with three glyphs and the given kerning table.
+func kerningTestFont(t *testing.T, tag Tag, data []byte) *Font {
+	b := NewBuilder(1000)
+	for _, name := range []string{"a", "b", "c"} {
+		b.AddGlyph(name, 500, nil)
+	}
+	font, err := b.Font()
+	if err != nil {
+		t.Fatal(err)
+	}
+	font.SetTable(tag, data)
+	return font
+}
+
+func TestKerningValueKern(t *testing.T) {
+	pairs := []interface{}{
+		uint16(2), uint16(12), uint16(1), uint16(0), // nPairs, searchRange, entrySelector, rangeShift
+		uint16(1), uint16(2), int16(-50),
+		uint16(1), uint16(3), int16(-20),
+	}
+	openType := writeBigEndian(t, append([]interface{}{
+		uint16(0), uint16(1), // version, nTables
+		uint16(0), uint16(26), uint16(0x0001), // version, length, coverage
+	}, pairs...)...)
+	apple := writeBigEndian(t, append([]interface{}{
+		uint32(0x00010000), uint32(2), // version, nTables
+		uint32(28), uint16(0), uint16(0), // length, coverage, tupleIndex
+	}, append(pairs,
+		uint32(28), uint16(0x8000), uint16(0), // a vertical subtable, which is ignored
+		uint16(1), uint16(12), uint16(1), uint16(0),
+		uint16(1), uint16(2), int16(100),
+	)...)...)
+
+	for name, data := range map[string][]byte{"OpenType": openType, "Apple": apple} {
+		font := kerningTestFont(t, TagKern, data)
+		for _, test := range []struct {
+			left, right uint16
+			want        float64
+		}{{1, 2, -50}, {1, 3, -20}, {2, 1, 0}} {
+			got, err := font.KerningValue(test.left, test.right, nil)
+			if err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			if got != test.want {
+				t.Errorf("%s: KerningValue(%d, %d) = %v, want %v", name, test.left, test.right, got, test.want)
+			}
+		}
+	}
+}
+
+func TestKerningValueKerx(t *testing.T) {
+	data := writeBigEndian(t,
+		uint16(2), uint16(0), uint32(2), // version, padding, nTables
+
+		// Format 2: left classes are row indices, and right classes column indices.
+		uint32(66), uint32(2), uint32(0), // length, coverage, tupleCount
+		uint32(4), uint32(28), uint32(38), uint32(58), // rowWidth, left, right, array
+		uint16(8), uint16(1), uint16(2), uint16(0), uint16(2), // trimmed array lookup
+		uint16(6), uint16(4), uint16(2), uint16(8), uint16(1), uint16(0), // single table lookup
+		uint16(1), uint16(0), uint16(2), uint16(1),
+		int16(0), int16(-10), int16(-20), int16(-30),
+
+		// Format 0.
+		uint32(34), uint32(0), uint32(0),
+		uint32(1), uint32(6), uint32(0), uint32(0),
+		uint16(2), uint16(2), int16(-5),
+	)
+	font := kerningTestFont(t, TagKerx, data)
+
+	for _, test := range []struct {
+		left, right uint16
+		want        float64
+	}{{1, 1, 0}, {1, 2, -10}, {2, 1, -20}, {2, 2, -35}, {3, 2, -10}} {
+		got, err := font.KerningValue(test.left, test.right, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != test.want {
+			t.Errorf("KerningValue(%d, %d) = %v, want %v", test.left, test.right, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package sfnt
+
+import (
+	"fmt"
+	"unicode"
+)
+
+// Language is a language identified by its BCP 47 code, such as "pt" for
+// Portuguese.
+type Language struct {
+	Code string
+	Name string // Name is the English name of the language.
+}
+
+// SupportedLanguages returns the languages whose main exemplar characters in
+// CLDR are all mapped to glyphs by the font's Unicode 'cmap' subtable, ordered
+// by code. For cased scripts the uppercase form of each exemplar is required
+// too, as CLDR only lists the lowercase letters.
+func (font *Font) SupportedLanguages() ([]Language, error) {
+	cmap, err := font.CmapTable()
+	if err != nil {
+		return nil, err
+	}
+	subtable := cmap.Unicode()
+	if subtable == nil {
+		return nil, fmt.Errorf("font has no unicode cmap")
+	}
+	supports := func(r rune) bool {
+		return subtable.Mapping[r] != 0
+	}
+
+	var languages []Language
+	for _, language := range languageExemplars {
+		supported := true
+		for _, r := range exemplarRunes(language.exemplars) {
+			if !supports(r) || !supports(unicode.ToUpper(r)) {
+				supported = false
+				break
+			}
+		}
+		if supported {
+			languages = append(languages, Language{language.code, language.name})
+		}
+	}
+	return languages, nil
+}
+
+// exemplarRunes expands the ranges in an exemplar set from languageExemplars.
+func exemplarRunes(exemplars string) []rune {
+	set := []rune(exemplars)
+	var runes []rune
+	for i := 0; i < len(set); i++ {
+		if i+2 < len(set) && set[i+1] == '-' {
+			for r := set[i]; r <= set[i+2]; r++ {
+				runes = append(runes, r)
+			}
+			i += 2
+			continue
+		}
+		runes = append(runes, set[i])
+	}
+	return runes
+}
@@ -0,0 +1,50 @@
+package sfnt
+
+import "testing"
+
+func TestExemplarRunes(t *testing.T) {
+	if got := string(exemplarRunes("a-dxyé")); got != "abcdxyé" {
+		t.Errorf("exemplarRunes() = %q, want abcdxyé", got)
+	}
+}
+
+func TestSupportedLanguages(t *testing.T) {
+	b := NewBuilder(1000)
+	gid := b.AddGlyph("box", 500, nil)
+	for r := 'a'; r <= 'z'; r++ {
+		b.Map(r, gid)
+		b.Map(r-'a'+'A', gid)
+	}
+	font, err := b.Font()
+	if err != nil {
+		t.Fatal(err)
+	}
+	languages, err := font.SupportedLanguages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	codes := map[string]bool{}
+	for _, language := range languages {
+		codes[language.Code] = true
+	}
+	if !codes["en"] || codes["fr"] || codes["ru"] {
+		t.Errorf("SupportedLanguages() of a-z = %v, want en but not fr or ru", languages)
+	}
+
+	roboto := parseTestFont(t, "Roboto-BoldItalic.ttf")
+	if languages, err = roboto.SupportedLanguages(); err != nil {
+		t.Fatal(err)
+	}
+	codes = map[string]bool{}
+	for _, language := range languages {
+		codes[language.Code] = true
+	}
+	for _, code := range []string{"en", "fr", "de", "ru", "vi", "el"} {
+		if !codes[code] {
+			t.Errorf("SupportedLanguages() of Roboto does not include %q", code)
+		}
+	}
+	if codes["ja"] || codes["hi"] {
+		t.Errorf("SupportedLanguages() of Roboto includes ja or hi")
+	}
+}
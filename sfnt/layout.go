@@ -0,0 +1,229 @@
+package sfnt
+
+import (
+	"encoding/binary"
+	"sort"
+)
+
+// This file contains the common structures used by the lookups in GPOS and GSUB.
+// See https://docs.microsoft.com/en-us/typography/opentype/spec/chapter2
+
+// coverageIndex returns the index of gid in the coverage table at the start of b.
+func coverageIndex(b []byte, gid uint16) (int, bool) {
+	if len(b) < 4 {
+		return 0, false
+	}
+	count := int(binary.BigEndian.Uint16(b[2:]))
+
+	switch binary.BigEndian.Uint16(b) {
+	case 1:
+		if len(b) < 4+2*count {
+			return 0, false
+		}
+		i := sort.Search(count, func(i int) bool {
+			return binary.BigEndian.Uint16(b[4+2*i:]) >= gid
+		})
+		if i < count && binary.BigEndian.Uint16(b[4+2*i:]) == gid {
+			return i, true
+		}
+	case 2:
+		if len(b) < 4+6*count {
+			return 0, false
+		}
+		i := sort.Search(count, func(i int) bool {
+			return binary.BigEndian.Uint16(b[4+6*i+2:]) >= gid
+		})
+		if i < count {
+			record := b[4+6*i:]
+			start := binary.BigEndian.Uint16(record)
+			if start <= gid {
+				return int(binary.BigEndian.Uint16(record[4:])) + int(gid-start), true
+			}
+		}
+	}
+	return 0, false
+}
+
+// classOf returns the class of gid in the class definition table at the start of b.
+// Glyphs that are not listed are in class 0.
+func classOf(b []byte, gid uint16) uint16 {
+	if len(b) < 4 {
+		return 0
+	}
+
+	switch binary.BigEndian.Uint16(b) {
+	case 1:
+		if len(b) < 6 {
+			return 0
+		}
+		start := binary.BigEndian.Uint16(b[2:])
+		count := int(binary.BigEndian.Uint16(b[4:]))
+		if gid < start || int(gid-start) >= count || len(b) < 6+2*count {
+			return 0
+		}
+		return binary.BigEndian.Uint16(b[6+2*int(gid-start):])
+	case 2:
+		count := int(binary.BigEndian.Uint16(b[2:]))
+		if len(b) < 4+6*count {
+			return 0
+		}
+		i := sort.Search(count, func(i int) bool {
+			return binary.BigEndian.Uint16(b[4+6*i+2:]) >= gid
+		})
+		if i < count {
+			record := b[4+6*i:]
+			if binary.BigEndian.Uint16(record) <= gid {
+				return binary.BigEndian.Uint16(record[4:])
+			}
+		}
+	}
+	return 0
+}
+
+// Value record formats, which say which fields are present in a value record.
+const (
+	valueXPlacement       = 0x0001
+	valueYPlacement       = 0x0002
+	valueXAdvance         = 0x0004
+	valueYAdvance         = 0x0008
+	valueXPlacementDevice = 0x0010
+	valueYPlacementDevice = 0x0020
+	valueXAdvanceDevice   = 0x0040
+	valueYAdvanceDevice   = 0x0080
+)
+
+// valueRecord is an adjustment to the position of a glyph.
+type valueRecord struct {
+	XPlacement, YPlacement, XAdvance, YAdvance int16
+
+	// The device tables are offsets from the start of the subtable, or 0.
+	XPlacementDevice, YPlacementDevice, XAdvanceDevice, YAdvanceDevice uint16
+}
+
+// valueRecordSize returns the size of a value record with the given format.
+func valueRecordSize(format uint16) int {
+	size := 0
+	for bit := uint16(1); bit <= valueYAdvanceDevice; bit <<= 1 {
+		if format&bit != 0 {
+			size += 2
+		}
+	}
+	return size
+}
+
+// parseValueRecord reads a value record with the given format from the start of b,
+// which must contain at least valueRecordSize(format) bytes.
+func parseValueRecord(b []byte, format uint16) valueRecord {
+	var fields [8]uint16
+	for i := range fields {
+		if format&(1<<uint(i)) != 0 {
+			fields[i] = binary.BigEndian.Uint16(b)
+			b = b[2:]
+		}
+	}
+	return valueRecord{
+		XPlacement:       int16(fields[0]),
+		YPlacement:       int16(fields[1]),
+		XAdvance:         int16(fields[2]),
+		YAdvance:         int16(fields[3]),
+		XPlacementDevice: fields[4],
+		YPlacementDevice: fields[5],
+		XAdvanceDevice:   fields[6],
+		YAdvanceDevice:   fields[7],
+	}
+}
+
+// extensionSubtables returns the subtables of a lookup, replacing extension subtables
+// (GPOS type 9 and GSUB type 7) with the subtables they point to, and the type of
+// the lookup they contain.
+func (l *Lookup) extensionSubtables(extensionType uint16) ([][]byte, uint16) {
+	if l.Type != extensionType {
+		return l.subtables, l.Type
+	}
+
+	var subtables [][]byte
+	lookupType := l.Type
+	for _, sub := range l.subtables {
+		if len(sub) < 8 || binary.BigEndian.Uint16(sub) != 1 {
+			continue
+		}
+		lookupType = binary.BigEndian.Uint16(sub[2:])
+		offset := binary.BigEndian.Uint32(sub[4:])
+		if uint64(offset) >= uint64(len(sub)) {
+			continue
+		}
+		subtables = append(subtables, sub[offset:])
+	}
+	return subtables, lookupType
+}
+
+// coverageGlyphs returns the glyphs in the coverage table at the start of b, in
+// the order of their coverage indices.
+func coverageGlyphs(b []byte) []uint16 {
+	if len(b) < 4 {
+		return nil
+	}
+	count := int(binary.BigEndian.Uint16(b[2:]))
+
+	var glyphs []uint16
+	switch binary.BigEndian.Uint16(b) {
+	case 1:
+		if len(b) < 4+2*count {
+			return nil
+		}
+		for i := 0; i < count; i++ {
+			glyphs = append(glyphs, binary.BigEndian.Uint16(b[4+2*i:]))
+		}
+	case 2:
+		if len(b) < 4+6*count {
+			return nil
+		}
+		for i := 0; i < count; i++ {
+			start, end := binary.BigEndian.Uint16(b[4+6*i:]), binary.BigEndian.Uint16(b[6+6*i:])
+			for gid := int(start); gid <= int(end); gid++ {
+				glyphs = append(glyphs, uint16(gid))
+			}
+		}
+	}
+	return glyphs
+}
+
+// classGlyphs returns the glyphs in each class of the class definition table at
+// the start of b. Class 0 is not included, as it contains every other glyph.
+func classGlyphs(b []byte) map[uint16][]uint16 {
+	classes := map[uint16][]uint16{}
+	if len(b) < 4 {
+		return classes
+	}
+
+	switch binary.BigEndian.Uint16(b) {
+	case 1:
+		if len(b) < 6 {
+			return classes
+		}
+		start := int(binary.BigEndian.Uint16(b[2:]))
+		count := int(binary.BigEndian.Uint16(b[4:]))
+		if len(b) < 6+2*count {
+			return classes
+		}
+		for i := 0; i < count; i++ {
+			if class := binary.BigEndian.Uint16(b[6+2*i:]); class != 0 {
+				classes[class] = append(classes[class], uint16(start+i))
+			}
+		}
+	case 2:
+		count := int(binary.BigEndian.Uint16(b[2:]))
+		if len(b) < 4+6*count {
+			return classes
+		}
+		for i := 0; i < count; i++ {
+			record := b[4+6*i:]
+			start, end := binary.BigEndian.Uint16(record), binary.BigEndian.Uint16(record[2:])
+			class := binary.BigEndian.Uint16(record[4:])
+			for gid := int(start); gid <= int(end) && class != 0; gid++ {
+				classes[class] = append(classes[class], uint16(gid))
+			}
+		}
+	}
+	return classes
+}
@@ -0,0 +1,261 @@
+package sfnt
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var scriptDefault = MustNamedTag("DFLT")
+
+// mergeLayouts returns a GSUB or GPOS table containing the scripts, features and
+// lookups of existing, followed by those of added. The features and lookups of
+// existing keep their indices, so its feature variations still apply, and each
+// language system has the features it had in either table.
+//
+// The lookups refer to the subtables of both tables, which are copied unchanged
+// after the lookup list, using extension subtables.
+func mergeLayouts(tag Tag, existing, added *TableLayout) ([]byte, error) {
+	layouts := []*TableLayout{existing, added}
+	extensionType := uint16(gsubExtension)
+	if tag == TagGpos {
+		extensionType = gposExtension
+	}
+
+	// extension is an extension subtable in the lookup list, and the position of
+	// the subtable that it points to in the bytes of its layout.
+	type extension struct {
+		offset, layout, target int
+	}
+	var extensions []extension
+	var lookupTables [][]byte
+	var lookupIndex []int // lookupIndex contains the index of the first lookup of each layout.
+	for i, t := range layouts {
+		lookupIndex = append(lookupIndex, len(lookupTables))
+		for _, l := range t.Lookups {
+			subtables, lookupType := l.extensionSubtables(extensionType)
+			table := appendUint16s(nil, extensionType, l.Flag, uint16(len(subtables)))
+			headerLength := len(table) + 2*len(subtables)
+			if l.Flag&lookupUseMarkFilteringSet != 0 {
+				headerLength += 2
+			}
+			for j := range subtables {
+				table = appendUint16s(table, uint16(headerLength+8*j))
+			}
+			if l.Flag&lookupUseMarkFilteringSet != 0 {
+				table = appendUint16s(table, l.markFilteringSet)
+			}
+			for j, sub := range subtables {
+				// The offset of the lookup in the list is added below.
+				extensions = append(extensions, extension{headerLength + 8*j, i, len(t.bytes) - len(sub)})
+				table = appendUint32s(appendUint16s(table, 1, lookupType), 0)
+			}
+			lookupTables = append(lookupTables, table)
+		}
+	}
+	if len(lookupTables) > 0xFFFF {
+		return nil, fmt.Errorf("too many lookups")
+	}
+	lookupList, err := packRecords(appendUint16s(nil, uint16(len(lookupTables))), nil, lookupTables)
+	if err != nil {
+		return nil, err
+	}
+
+	featureIndex := map[*Feature]int{}
+	var featureTags []uint32
+	var featureTables [][]byte
+	for i, t := range layouts {
+		for _, f := range t.Features {
+			params := featureParams(f)
+			table := appendUint16s(nil, 0, uint16(len(f.LookupIndices)))
+			if params != nil {
+				binary.BigEndian.PutUint16(table, uint16(4+2*len(f.LookupIndices)))
+			}
+			for _, index := range f.LookupIndices {
+				table = appendUint16s(table, uint16(lookupIndex[i]+int(index)))
+			}
+			featureIndex[f] = len(featureTables)
+			featureTags = append(featureTags, f.Tag.Number)
+			featureTables = append(featureTables, append(table, params...))
+		}
+	}
+	if len(featureTables) > 0xFFFF {
+		return nil, fmt.Errorf("too many features")
+	}
+	featureList, err := packRecords(appendUint16s(nil, uint16(len(featureTables))), featureTags, featureTables)
+	if err != nil {
+		return nil, err
+	}
+
+	// A language system has the features of the language system that would be
+	// used in each table, falling back to the default language and script.
+	langSys := func(script, lang Tag) []byte {
+		required := 0xFFFF
+		var indices []uint16
+		seen := map[int]bool{}
+		for _, t := range layouts {
+			l := t.langSys(script, lang)
+			if l == nil {
+				continue
+			}
+			if l.required != nil && required == 0xFFFF {
+				required = featureIndex[l.required]
+			}
+			for _, f := range l.Features {
+				if index := featureIndex[f]; !seen[index] {
+					seen[index] = true
+					indices = append(indices, uint16(index))
+				}
+			}
+		}
+		return appendUint16s(appendUint16s(nil, 0, uint16(required), uint16(len(indices))), indices...)
+	}
+
+	var scriptTags []Tag
+	for _, t := range layouts {
+		for _, s := range t.Scripts {
+			scriptTags = appendTag(scriptTags, s.Tag)
+		}
+	}
+	sort.Slice(scriptTags, func(i, j int) bool { return scriptTags[i].Number < scriptTags[j].Number })
+
+	var scriptNumbers []uint32
+	var scripts [][]byte
+	for _, script := range scriptTags {
+		var langTags []Tag
+		hasDefault := false
+		for _, t := range layouts {
+			s := t.script(script)
+			if s == nil {
+				continue
+			}
+			hasDefault = hasDefault || s.DefaultLanguage != nil
+			for _, l := range s.Languages {
+				langTags = appendTag(langTags, l.Tag)
+			}
+		}
+		sort.Slice(langTags, func(i, j int) bool { return langTags[i].Number < langTags[j].Number })
+
+		var tags []uint32
+		var tables [][]byte
+		for _, lang := range langTags {
+			tags = append(tags, lang.Number)
+			tables = append(tables, langSys(script, lang))
+		}
+		table, err := packRecords(appendUint16s(nil, 0, uint16(len(tags))), tags, tables)
+		if err != nil {
+			return nil, err
+		}
+		if hasDefault {
+			if len(table) > 0xFFFF {
+				return nil, fmt.Errorf("script %q is too large", script)
+			}
+			binary.BigEndian.PutUint16(table, uint16(len(table)))
+			table = append(table, langSys(script, DefaultLanguage)...)
+		}
+		scriptNumbers = append(scriptNumbers, script.Number)
+		scripts = append(scripts, table)
+	}
+	scriptList, err := packRecords(appendUint16s(nil, uint16(len(scripts))), scriptNumbers, scripts)
+	if err != nil {
+		return nil, err
+	}
+
+	// The feature variations of existing are kept, and refer to its features and
+	// lookups, which have the same indices in the merged table.
+	buf := appendUint32s(nil, 0x00010000)
+	offset := 10
+	hasVariations := existing.version.Minor == 1 && existing.header.FeatureVariationsOffset != 0
+	if hasVariations {
+		buf = appendUint32s(nil, 0x00010001)
+		offset = 14
+	}
+	for _, table := range [][]byte{scriptList, featureList, lookupList} {
+		if offset > 0xFFFF {
+			return nil, fmt.Errorf("table is too large")
+		}
+		buf = appendUint16s(buf, uint16(offset))
+		offset += len(table)
+	}
+	// The added subtables are kept at even offsets.
+	padding := len(existing.bytes) % 2
+	layoutStart := []int{offset, offset + len(existing.bytes) + padding}
+	if hasVariations {
+		buf = appendUint32s(buf, uint32(layoutStart[0])+existing.header.FeatureVariationsOffset)
+	}
+
+	lookupListStart := len(buf) + len(scriptList) + len(featureList)
+	buf = append(buf, scriptList...)
+	buf = append(buf, featureList...)
+	buf = append(buf, lookupList...)
+	buf = append(buf, existing.bytes...)
+	buf = append(buf, make([]byte, padding)...)
+	buf = append(buf, added.bytes...)
+
+	e := 0
+	for i := range lookupTables {
+		lookupStart := lookupListStart + int(binary.BigEndian.Uint16(lookupList[2+2*i:]))
+		count := int(binary.BigEndian.Uint16(buf[lookupStart+4:]))
+		for ; count > 0; count-- {
+			ext := lookupStart + extensions[e].offset
+			target := layoutStart[extensions[e].layout] + extensions[e].target
+			binary.BigEndian.PutUint32(buf[ext+4:], uint32(target-ext))
+			e++
+		}
+	}
+	return buf, nil
+}
+
+// script returns the script with the given tag, or the default script if there
+// is none, or nil if there is neither.
+func (t *TableLayout) script(tag Tag) *Script {
+	var fallback *Script
+	for _, s := range t.Scripts {
+		if s.Tag == tag {
+			return s
+		}
+		if s.Tag == scriptDefault {
+			fallback = s
+		}
+	}
+	return fallback
+}
+
+// langSys returns the language system used for text in the given script and
+// language, falling back to the default language and script, or nil if there
+// is none.
+func (t *TableLayout) langSys(script, lang Tag) *LangSys {
+	s := t.script(script)
+	if s == nil {
+		return nil
+	}
+	for _, l := range s.Languages {
+		if l.Tag == lang {
+			return l
+		}
+	}
+	return s.DefaultLanguage
+}
+
+// featureParams returns the parameters of f, which are only defined for the
+// 'size', 'ssXX' and 'cvXX' features, or nil if it has none.
+func featureParams(f *Feature) []byte {
+	tag := f.Tag.String()
+	switch {
+	case f.Tag == featureSize:
+		for _, params := range [][]byte{f.params, f.listParams} {
+			if _, ok := parseSizeParams(params); ok {
+				return params[:10]
+			}
+		}
+	case strings.HasPrefix(tag, "ss") && len(f.params) >= 4:
+		return f.params[:4]
+	case strings.HasPrefix(tag, "cv") && len(f.params) >= 14:
+		// The parameters end with a list of 24-bit characters.
+		if n := 14 + 3*int(binary.BigEndian.Uint16(f.params[12:])); len(f.params) >= n {
+			return f.params[:n]
+		}
+	}
+	return nil
+}
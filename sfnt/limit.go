@@ -0,0 +1,436 @@
+package sfnt
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+var (
+	tagCFF2 = MustNamedTag("CFF2")
+	tagHvar = MustNamedTag("HVAR")
+	tagVvar = MustNamedTag("VVAR")
+	tagMvar = MustNamedTag("MVAR")
+)
+
+// LimitAxis restricts the axis with the given tag to the range from min to max,
+// in user coordinates (for example wght 400 to 700). The range must include the
+// default position of the axis, and is clamped to the current range.
+//
+// The axis is updated in 'fvar', named instances outside the range are removed,
+// and the variation data in 'avar', 'gvar', 'cvar', 'HVAR', 'VVAR', 'MVAR', 'GDEF'
+// and the feature variations in 'GSUB' and 'GPOS' are renormalized so that the
+// font looks the same at each position in the range. Variation data that only
+// applies outside the range is removed.
+//
+// Fonts with 'CFF2' outlines are not supported. Other tables, such as 'STAT',
+// are left unchanged.
+func (font *Font) LimitAxis(tag Tag, min, max float64) error {
+	return font.LimitAxisContext(context.Background(), tag, min, max)
+}
+
+// LimitAxisContext is like LimitAxis, but returns ctx.Err() without modifying
+// the font if ctx is cancelled before the variations of each glyph have been
+// limited.
+func (font *Font) LimitAxisContext(ctx context.Context, tag Tag, min, max float64) error {
+	if font.HasTable(tagCFF2) {
+		return fmt.Errorf("limiting axes of fonts with CFF2 outlines is not supported")
+	}
+	fvar, err := font.FvarTable()
+	if err != nil {
+		return err
+	}
+
+	index := -1
+	for i, axis := range fvar.Axes {
+		if axis.Tag == tag {
+			index = i
+		}
+	}
+	if index == -1 {
+		return fmt.Errorf("font has no %q axis", tag)
+	}
+	axis := &fvar.Axes[index]
+	min, max = math.Max(min, axis.Min), math.Min(max, axis.Max)
+	if min > axis.Default || max < axis.Default {
+		return fmt.Errorf("range %v-%v does not include the default %q of %v", min, max, tag, axis.Default)
+	}
+
+	limit := axisLimit{axis: index, lower: axis.Normalize(min), upper: axis.Normalize(max)}
+	rawLower, rawUpper := limit.lower, limit.upper
+	var avar *TableAvar
+	if font.HasTable(TagAvar) {
+		if avar, err = font.AvarTable(); err != nil {
+			return err
+		}
+		limit.lower, limit.upper = avar.Map(index, rawLower), avar.Map(index, rawUpper)
+	}
+
+	// The glyph variations take the longest to limit, so they are limited
+	// first, and the font is not modified if ctx is cancelled meanwhile.
+	var gvar *TableGvar
+	var glyphTuples [][]TupleVariation
+	if font.HasTable(TagGvar) {
+		if gvar, err = font.GvarTable(); err != nil {
+			return err
+		}
+		glyphTuples = make([][]TupleVariation, len(gvar.Glyphs))
+		for i := range gvar.Glyphs {
+			if i%1024 == 0 {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+			}
+			glyphTuples[i] = limit.tuples(gvar.Glyphs[i].Tuples)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if gvar != nil {
+		for i := range gvar.Glyphs {
+			gvar.Glyphs[i].Tuples = glyphTuples[i]
+		}
+		font.AddTable(TagGvar, gvar)
+	}
+
+	if font.HasTable(TagCvar) {
+		cvar, err := font.CvarTable()
+		if err != nil {
+			return err
+		}
+		cvar.Variations.Tuples = limit.tuples(cvar.Variations.Tuples)
+		font.AddTable(TagCvar, cvar)
+	}
+
+	stores := []struct {
+		tag    Tag
+		offset int  // offset is the position of the offset to the store in the table.
+		long   bool // long is true if the offset is 32-bit.
+	}{
+		{tagHvar, 4, true},
+		{tagVvar, 4, true},
+		{tagMvar, 10, false},
+		{TagGdef, 14, true},
+	}
+	for _, s := range stores {
+		if !font.HasTable(s.tag) {
+			continue
+		}
+		data, err := font.TableData(s.tag)
+		if err != nil {
+			return err
+		}
+		if s.tag == TagGdef {
+			// The store was added to GDEF in version 1.3.
+			if len(data) < 18 || binary.BigEndian.Uint16(data[2:]) < 3 {
+				continue
+			}
+		}
+		if data, err = limit.variationStore(data, s.offset, s.long); err != nil {
+			return fmt.Errorf("limiting %s: %s", s.tag, err)
+		}
+		font.SetTable(s.tag, data)
+	}
+
+	for _, tag := range []Tag{TagGsub, TagGpos} {
+		if !font.HasTable(tag) {
+			continue
+		}
+		data, err := font.TableData(tag)
+		if err != nil {
+			return err
+		}
+		if data, err = limit.featureVariations(data); err != nil {
+			return fmt.Errorf("limiting %s: %s", tag, err)
+		}
+		font.SetTable(tag, data)
+	}
+
+	if avar != nil && index < len(avar.Segments) {
+		avar.Segments[index] = limitSegments(avar.Segments[index], rawLower, rawUpper, limit.lower, limit.upper)
+		font.AddTable(TagAvar, avar)
+	}
+
+	axis.Min, axis.Max = min, max
+	instances := fvar.Instances[:0]
+	for _, instance := range fvar.Instances {
+		if v := instance.Coordinates[index]; v >= min && v <= max {
+			instances = append(instances, instance)
+		}
+	}
+	fvar.Instances = instances
+	font.AddTable(TagFvar, fvar)
+	return nil
+}
+
+// axisLimit is the new range of an axis, in the normalized coordinates used by
+// variation data (after applying 'avar'). Lower is between -1 and 0, and upper
+// between 0 and 1. Coordinates in the range are scaled so that lower becomes -1
+// and upper becomes 1.
+type axisLimit struct {
+	axis         int
+	lower, upper float64
+}
+
+// limitedRegion is a region on the limited axis, and the amount to scale the
+// deltas of the original region by.
+type limitedRegion struct {
+	RegionAxis
+	scale float64
+}
+
+// region returns the regions that have the same effect within the new range
+// as r has in the old range.
+func (l axisLimit) region(r RegionAxis) []limitedRegion {
+	if r.Start > r.Peak || r.Peak > r.End || (r.Start < 0 && r.End > 0) || r.Peak == 0 {
+		return []limitedRegion{{r, 1}}
+	}
+	if r.Peak > 0 {
+		return limitTent(r, l.upper)
+	}
+
+	limited := limitTent(RegionAxis{-r.End, -r.Peak, -r.Start}, -l.lower)
+	for i, t := range limited {
+		limited[i].RegionAxis = RegionAxis{-t.End, -t.Peak, -t.Start}
+	}
+	return limited
+}
+
+// limitTent limits the positive region r to the range from 0 to limit.
+func limitTent(r RegionAxis, limit float64) []limitedRegion {
+	s, p, e := r.Start, r.Peak, r.End
+	switch {
+	case limit <= 0 || (p > limit && s >= limit):
+		return nil
+	case p > limit:
+		// Only the rising part of the region is in range, so it now peaks at
+		// the end of the range, with deltas scaled to the value at the limit.
+		return []limitedRegion{{RegionAxis{s / limit, 1, 1}, (limit - s) / (p - s)}}
+	case e <= limit:
+		return []limitedRegion{{RegionAxis{s / limit, p / limit, e / limit}, 1}}
+	}
+
+	// The region is cut off while falling, so it now ends at the end of the
+	// range, and another region adds back the difference up to the limit.
+	limited := []limitedRegion{{RegionAxis{s / limit, p / limit, 1}, 1}}
+	if p < limit {
+		limited = append(limited, limitedRegion{RegionAxis{p / limit, 1, 1}, (e - limit) / (e - p)})
+	}
+	return limited
+}
+
+// tuples returns the tuple variations limited to the new range.
+func (l axisLimit) tuples(tuples []TupleVariation) []TupleVariation {
+	var limited []TupleVariation
+	for _, tuple := range tuples {
+		region := tuple.Region()
+		for _, r := range l.region(region[l.axis]) {
+			region[l.axis] = r.RegionAxis
+			t := TupleVariation{Deltas: make([]int16, len(tuple.Deltas)), points: tuple.points}
+			for i, d := range tuple.Deltas {
+				t.Deltas[i] = int16(math.Round(float64(d) * r.scale))
+			}
+
+			// Start and End are only needed if the region does not run from
+			// 0 to the peak on each axis.
+			intermediate := false
+			t.Peak = make([]float64, len(region))
+			for i, axis := range region {
+				t.Peak[i] = axis.Peak
+				if axis.Start != math.Min(axis.Peak, 0) || axis.End != math.Max(axis.Peak, 0) {
+					intermediate = true
+				}
+			}
+			if intermediate {
+				t.Start, t.End = make([]float64, len(region)), make([]float64, len(region))
+				for i, axis := range region {
+					t.Start[i], t.End[i] = axis.Start, axis.End
+				}
+			}
+			limited = append(limited, t)
+		}
+	}
+	return limited
+}
+
+// variationStore limits the item variation store that is pointed to by the
+// offset at b[at:], and returns the table with the new store appended.
+func (l axisLimit) variationStore(b []byte, at int, long bool) ([]byte, error) {
+	size := 2
+	if long {
+		size = 4
+	}
+	if len(b) < at+size {
+		return nil, fmt.Errorf("unexpected EOF")
+	}
+	var offset int
+	if long {
+		offset = int(binary.BigEndian.Uint32(b[at:]))
+	} else {
+		offset = int(binary.BigEndian.Uint16(b[at:]))
+	}
+	if offset == 0 {
+		return b, nil
+	}
+	if offset >= len(b) {
+		return nil, fmt.Errorf("invalid item variation store offset %d", offset)
+	}
+	store, err := ParseItemVariationStore(b[offset:])
+	if err != nil {
+		return nil, err
+	}
+
+	// The old store is left in place, as other parts of the table may be
+	// stored after it.
+	buf := append([]byte{}, b...)
+	for len(buf)%4 != 0 {
+		buf = append(buf, 0)
+	}
+	offset = len(buf)
+	if long {
+		binary.BigEndian.PutUint32(buf[at:], uint32(offset))
+	} else if offset > math.MaxUint16 {
+		return nil, fmt.Errorf("item variation store offset %d is too large", offset)
+	} else {
+		binary.BigEndian.PutUint16(buf[at:], uint16(offset))
+	}
+	return append(buf, l.store(store).Bytes()...), nil
+}
+
+// store returns the item variation store limited to the new range. Regions that
+// are split in two have their deltas stored twice, and the outer and inner
+// indices of items are not changed.
+func (l axisLimit) store(s *ItemVariationStore) *ItemVariationStore {
+	type column struct {
+		region int
+		scale  float64
+	}
+
+	limited := &ItemVariationStore{}
+	columns := make([][]column, len(s.Regions))
+	for i, region := range s.Regions {
+		for _, r := range l.region(region[l.axis]) {
+			region := append([]RegionAxis{}, region...)
+			region[l.axis] = r.RegionAxis
+			columns[i] = append(columns[i], column{len(limited.Regions), r.scale})
+			limited.Regions = append(limited.Regions, region)
+		}
+	}
+
+	for _, data := range s.Data {
+		var d ItemVariationData
+		for _, r := range data.RegionIndices {
+			for _, c := range columns[r] {
+				d.RegionIndices = append(d.RegionIndices, uint16(c.region))
+			}
+		}
+		for _, deltas := range data.Deltas {
+			row := make([]int32, 0, len(d.RegionIndices))
+			for j, delta := range deltas {
+				for _, c := range columns[data.RegionIndices[j]] {
+					row = append(row, int32(math.Round(float64(delta)*c.scale)))
+				}
+			}
+			d.Deltas = append(d.Deltas, row)
+		}
+		limited.Data = append(limited.Data, d)
+	}
+	return limited
+}
+
+// coordinate converts a normalized coordinate from the old range to the new one.
+// Coordinates outside the new range are beyond -1 or 1.
+func (l axisLimit) coordinate(v float64) float64 {
+	switch {
+	case v > 0 && l.upper > 0:
+		v /= l.upper
+	case v > 0:
+		v = 2
+	case v < 0 && l.lower < 0:
+		v /= -l.lower
+	case v < 0:
+		v = -2
+	}
+	return math.Max(-2, math.Min(v, f2dot14(math.MaxInt16)))
+}
+
+// featureVariations returns the GSUB or GPOS table b with the axis ranges of
+// the conditions in its feature variations converted to the new range.
+func (l axisLimit) featureVariations(b []byte) ([]byte, error) {
+	// The offset to the feature variations was added in version 1.1.
+	if len(b) < 14 || binary.BigEndian.Uint16(b[2:]) < 1 {
+		return b, nil
+	}
+	offset := int(binary.BigEndian.Uint32(b[10:]))
+	if offset == 0 {
+		return b, nil
+	}
+	if len(b) < offset+8 {
+		return nil, fmt.Errorf("reading feature variations: unexpected EOF")
+	}
+	buf := append([]byte{}, b...)
+	variations := buf[offset:]
+	count := int(binary.BigEndian.Uint32(variations[4:]))
+	if len(variations) < 8+8*count {
+		return nil, fmt.Errorf("reading %d feature variation records: unexpected EOF", count)
+	}
+
+	seen := map[int]bool{}
+	for i := 0; i < count; i++ {
+		set := offset + int(binary.BigEndian.Uint32(variations[8+8*i:]))
+		if set == offset {
+			continue
+		}
+		if len(buf) < set+2 {
+			return nil, fmt.Errorf("reading condition set %d: unexpected EOF", i)
+		}
+		conditions := int(binary.BigEndian.Uint16(buf[set:]))
+		if len(buf) < set+2+4*conditions {
+			return nil, fmt.Errorf("reading condition set %d: unexpected EOF", i)
+		}
+		for j := 0; j < conditions; j++ {
+			c := set + int(binary.BigEndian.Uint32(buf[set+2+4*j:]))
+			if seen[c] {
+				continue
+			}
+			seen[c] = true
+			if len(buf) < c+8 {
+				return nil, fmt.Errorf("reading condition %d of set %d: unexpected EOF", j, i)
+			}
+			if binary.BigEndian.Uint16(buf[c:]) != 1 || int(binary.BigEndian.Uint16(buf[c+2:])) != l.axis {
+				continue
+			}
+			for _, at := range []int{c + 4, c + 6} {
+				v := l.coordinate(f2dot14(int16(binary.BigEndian.Uint16(buf[at:]))))
+				binary.BigEndian.PutUint16(buf[at:], uint16(toF2dot14(v)))
+			}
+		}
+	}
+	return buf, nil
+}
+
+// limitSegments returns the 'avar' mapping of an axis after limiting it.
+// rawLower and rawUpper are the new range before applying the old mapping, and
+// lower and upper after.
+func limitSegments(segment []AxisValueMap, rawLower, rawUpper, lower, upper float64) []AxisValueMap {
+	if len(segment) == 0 {
+		return segment
+	}
+
+	limited := []AxisValueMap{{-1, -1}}
+	for _, m := range segment {
+		if m.From < 0 && m.From > -1 && m.From > rawLower {
+			limited = append(limited, AxisValueMap{m.From / -rawLower, m.To / -lower})
+		}
+	}
+	limited = append(limited, AxisValueMap{0, 0})
+	for _, m := range segment {
+		if m.From > 0 && m.From < 1 && m.From < rawUpper {
+			limited = append(limited, AxisValueMap{m.From / rawUpper, m.To / upper})
+		}
+	}
+	return append(limited, AxisValueMap{1, 1})
+}
@@ -0,0 +1,223 @@
+package sfnt
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// limitTestFont returns a font with 'wght' and 'wdth' axes, and variation data
+// in 'avar', 'gvar', 'cvar' and 'HVAR'.
+func limitTestFont(t *testing.T) *Font {
+	font, err := NewBuilder(1000).Font()
+	if err != nil {
+		t.Fatal(err)
+	}
+	font.AddTable(TagFvar, &TableFvar{
+		baseTable: baseTable(TagFvar),
+		Axes: []VariationAxis{
+			{Tag: MustNamedTag("wght"), Min: 100, Default: 400, Max: 900, NameID: 256},
+			{Tag: MustNamedTag("wdth"), Min: 75, Default: 100, Max: 100, NameID: 257},
+		},
+		Instances: []NamedInstance{
+			{SubfamilyNameID: 258, PostScriptNameID: 0xFFFF, Coordinates: []float64{300, 100}},
+			{SubfamilyNameID: 259, PostScriptNameID: 0xFFFF, Coordinates: []float64{400, 100}},
+			{SubfamilyNameID: 260, PostScriptNameID: 0xFFFF, Coordinates: []float64{700, 100}},
+		},
+	})
+	font.AddTable(TagAvar, &TableAvar{
+		baseTable: baseTable(TagAvar),
+		Segments:  [][]AxisValueMap{{{-1, -1}, {-0.5, -0.25}, {0, 0}, {0.5, 0.8}, {1, 1}}, {}},
+	})
+	tuples := []TupleVariation{
+		{Peak: []float64{1, 0}, Deltas: []int16{100, -50}},
+		{Peak: []float64{-1, 0}, Deltas: []int16{-80, 40}},
+		{Peak: []float64{0.5, 0}, Start: []float64{0.2, 0}, End: []float64{0.9, 0}, Deltas: []int16{30, 300}},
+		{Peak: []float64{1, -1}, Deltas: []int16{10, 20}},
+		{Peak: []float64{0, -1}, Deltas: []int16{7, 0}},
+	}
+	font.AddTable(TagGvar, &TableGvar{
+		baseTable: baseTable(TagGvar),
+		AxisCount: 2,
+		Glyphs:    []TupleVariations{{Tuples: tuples, sharedPoints: []byte{0}}},
+	})
+	font.AddTable(TagCvar, &TableCvar{
+		baseTable:  baseTable(TagCvar),
+		Variations: TupleVariations{Tuples: []TupleVariation{{Peak: []float64{0.5, 0}, Deltas: []int16{16}, points: []byte{0}}}},
+	})
+
+	store := &ItemVariationStore{
+		Regions: [][]RegionAxis{{{0, 1, 1}, {0, 0, 0}}, {{0.2, 0.5, 0.9}, {0, 0, 0}}, {{-1, -1, 0}, {-1, -1, 0}}},
+		Data:    []ItemVariationData{{RegionIndices: []uint16{0, 1, 2}, Deltas: [][]int32{{50, 100, -30}}}},
+	}
+	font.SetTable(tagHvar, append(writeBigEndian(t, uint16(1), uint16(0), uint32(20), uint32(0), uint32(0), uint32(0)), store.Bytes()...))
+	return font
+}
+
+// limitTestDeltas returns the variations of the glyph, cvt and advance in the
+// test font at the given user coordinates.
+func limitTestDeltas(t *testing.T, font *Font, user []float64) []float64 {
+	fvar, err := font.FvarTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	avar, err := font.AvarTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	coords := make([]float64, len(user))
+	for i, v := range user {
+		coords[i] = avar.Map(i, fvar.Axes[i].Normalize(v))
+	}
+
+	var deltas []float64
+	add := func(tuples []TupleVariation, count int) {
+		for i := 0; i < count; i++ {
+			var total float64
+			for _, tuple := range tuples {
+				total += float64(tuple.Deltas[i]) * regionScalar(tuple.Region(), coords)
+			}
+			deltas = append(deltas, total)
+		}
+	}
+
+	gvar, err := font.GvarTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	add(gvar.Glyphs[0].Tuples, 2)
+	cvar, err := font.CvarTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	add(cvar.Variations.Tuples, 1)
+
+	hvar, err := font.TableData(tagHvar)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := ParseItemVariationStore(hvar[int(hvar[4])<<24|int(hvar[5])<<16|int(hvar[6])<<8|int(hvar[7]):])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return append(deltas, store.Delta(0, 0, coords))
+}
+
+func TestLimitAxis(t *testing.T) {
+	font := limitTestFont(t)
+	var positions [][]float64
+	for _, wght := range []float64{400, 420, 480, 520, 560, 600, 300, 250, 200} {
+		for _, wdth := range []float64{75, 90, 100} {
+			positions = append(positions, []float64{wght, wdth})
+		}
+	}
+	var want [][]float64
+	for _, user := range positions {
+		want = append(want, limitTestDeltas(t, font, user))
+	}
+
+	if err := font.LimitAxis(MustNamedTag("wght"), 200, 600); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if _, err := font.WriteOTF(&buf); err != nil {
+		t.Fatal(err)
+	}
+	limited, err := Parse(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fvar, err := limited.FvarTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if axis := fvar.Axes[0]; axis.Min != 200 || axis.Max != 600 {
+		t.Errorf("limited wght axis = %v-%v, want 200-600", axis.Min, axis.Max)
+	}
+	if len(fvar.Instances) != 2 || fvar.Instances[1].Coordinates[0] != 400 {
+		t.Errorf("limited Instances = %+v, want the instances at 300 and 400", fvar.Instances)
+	}
+
+	for i, user := range positions {
+		got := limitTestDeltas(t, limited, user)
+		for j := range got {
+			if math.Abs(got[j]-want[i][j]) > 1 {
+				t.Errorf("deltas at %v = %v, want %v", user, got, want[i])
+				break
+			}
+		}
+	}
+
+	if err := font.LimitAxis(MustNamedTag("wght"), 500, 600); err == nil {
+		t.Errorf("LimitAxis(500, 600) without the default err = nil, want an error")
+	}
+	if err := font.LimitAxis(MustNamedTag("opsz"), 10, 20); err == nil {
+		t.Errorf("LimitAxis(opsz) err = nil, want an error")
+	}
+}
+
+func TestLimitAxisContextCancelled(t *testing.T) {
+	font := limitTestFont(t)
+	want := limitTestDeltas(t, font, []float64{600, 100})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := font.LimitAxisContext(ctx, MustNamedTag("wght"), 200, 600); err != context.Canceled {
+		t.Fatalf("LimitAxisContext(cancelled) = %v, want %v", err, context.Canceled)
+	}
+
+	fvar, err := font.FvarTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if axis := fvar.Axes[0]; axis.Min != 100 || axis.Max != 900 {
+		t.Errorf("wght axis after LimitAxisContext(cancelled) = %v-%v, want 100-900", axis.Min, axis.Max)
+	}
+	if got := limitTestDeltas(t, font, []float64{600, 100}); !reflect.DeepEqual(got, want) {
+		t.Errorf("deltas after LimitAxisContext(cancelled) = %v, want %v", got, want)
+	}
+}
+
+func TestLimitTent(t *testing.T) {
+	for _, test := range []struct {
+		r    RegionAxis
+		want []limitedRegion
+	}{
+		{RegionAxis{0, 0.5, 0.5}, []limitedRegion{{RegionAxis{0, 1, 1}, 1}}},
+		{RegionAxis{0, 1, 1}, []limitedRegion{{RegionAxis{0, 1, 1}, 0.5}}},
+		{RegionAxis{0.5, 1, 1}, nil},
+		{RegionAxis{0.25, 1, 1}, []limitedRegion{{RegionAxis{0.5, 1, 1}, 1.0 / 3}}},
+		{RegionAxis{0, 0.25, 1}, []limitedRegion{{RegionAxis{0, 0.5, 1}, 1}, {RegionAxis{0.5, 1, 1}, 2.0 / 3}}},
+	} {
+		got := limitTent(test.r, 0.5)
+		if len(got) != len(test.want) {
+			t.Errorf("limitTent(%v, 0.5) = %v, want %v", test.r, got, test.want)
+			continue
+		}
+		for i := range got {
+			if got[i].RegionAxis != test.want[i].RegionAxis || math.Abs(got[i].scale-test.want[i].scale) > 1e-9 {
+				t.Errorf("limitTent(%v, 0.5) = %v, want %v", test.r, got, test.want)
+			}
+		}
+	}
+}
+
+func TestLimitFeatureVariations(t *testing.T) {
+	gsub := writeBigEndian(t,
+		uint16(1), uint16(1), uint16(0), uint16(0), uint16(0), uint32(14), // version, scriptList, featureList, lookupList, featureVariations
+		uint16(1), uint16(0), uint32(1), uint32(16), uint32(0), // version, count, conditionSet, featureTableSubstitution
+		uint16(1), uint32(6), // conditionCount, offset
+		uint16(1), uint16(0), uint16(0x1000), uint16(0x4000), // format, axisIndex, filterRangeMin, filterRangeMax
+	)
+	limited, err := axisLimit{axis: 0, lower: -1, upper: 0.5}.featureVariations(gsub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	condition := limited[len(limited)-4:]
+	if min, max := f2dot14(int16(condition[0])<<8|int16(condition[1])), f2dot14(int16(condition[2])<<8|int16(condition[3])); min != 0.5 || max <= 1 {
+		t.Errorf("limited condition = %v-%v, want 0.5 to beyond 1", min, max)
+	}
+}
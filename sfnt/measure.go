@@ -0,0 +1,52 @@
+package sfnt
+
+// defaultShapingFeatures are the GSUB features that browsers apply to
+// horizontal text by default. Kerning is also on by default, but is chosen
+// separately by TextWidth.
+var defaultShapingFeatures = []Tag{
+	featureCcmp,
+	MustNamedTag("locl"),
+	MustNamedTag("rlig"),
+	MustNamedTag("liga"),
+	MustNamedTag("clig"),
+	MustNamedTag("calt"),
+}
+
+// TextWidth returns the advance width of a line of text in font units, when it
+// is shaped by Shape with the features that browsers use by default. With
+// kerning, the 'kern' feature is applied too, or if the font has no GPOS
+// kerning, the adjustments in its 'kerx' or 'kern' table, as for KerningValue.
+//
+// Shape is not a complete shaping engine, so the width can differ from that
+// of a browser for text that needs contextual lookups or complex scripts.
+func (font *Font) TextWidth(text string, kerning bool) (float64, error) {
+	features := defaultShapingFeatures
+	gposKerning := false
+	if kerning && font.HasTable(TagGpos) {
+		gpos, err := font.GposTable()
+		if err != nil {
+			return 0, err
+		}
+		if len(gpos.featureLookups(featureKern)) > 0 {
+			features = append(features[:len(features):len(features)], featureKern)
+			gposKerning = true
+		}
+	}
+
+	glyphs, err := font.Shape(text, features)
+	if err != nil {
+		return 0, err
+	}
+	var width float64
+	for i, g := range glyphs {
+		width += float64(g.XAdvance)
+		if kerning && !gposKerning && i+1 < len(glyphs) {
+			kern, err := font.KerningValue(g.GlyphID, glyphs[i+1].GlyphID, nil)
+			if err != nil {
+				return 0, err
+			}
+			width += kern
+		}
+	}
+	return width, nil
+}
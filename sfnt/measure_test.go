@@ -0,0 +1,33 @@
+package sfnt
+
+import (
+	"testing"
+)
+
+func TestTextWidth(t *testing.T) {
+	font := parseTestFont(t, "Roboto-BoldItalic.ttf")
+	cmap, err := font.CmapTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	hmtx, err := font.HmtxTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, _ := cmap.Lookup('A')
+	v, _ := cmap.Lookup('V')
+	kern, err := font.KerningValue(a, v, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unkerned := float64(hmtx.Advance(a)) + float64(hmtx.Advance(v))
+
+	for kerning, want := range map[bool]float64{false: unkerned, true: unkerned + kern} {
+		if got, err := font.TextWidth("AV", kerning); got != want || err != nil {
+			t.Errorf("TextWidth(\"AV\", %v) = %v, %v; want %v", kerning, got, err, want)
+		}
+	}
+	if got, err := font.TextWidth("", true); got != 0 || err != nil {
+		t.Errorf("TextWidth(\"\", true) = %v, %v; want 0", got, err)
+	}
+}
@@ -0,0 +1,130 @@
+package sfnt
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	encunicode "golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// nameKey identifies an entry of the 'name' table. The specification allows
+// only one entry for each.
+type nameKey struct {
+	PlatformID PlatformID
+	EncodingID PlatformEncodingID
+	LanguageID PlatformLanguageID
+	NameID     NameID
+}
+
+// sanitizedNames returns a copy of the table, as written with the SanitizeNames
+// option: without duplicate entries (of which the first is kept), with control
+// characters removed from the values, and with the entries sorted by platform,
+// encoding, language and name id. If dropLanguages is true, only the entries in
+// English, for the Unicode platform, or in one of the keep languages are kept.
+func (table *TableName) sanitizedNames(dropLanguages bool, keep []PlatformLanguageID) *TableName {
+	sanitized := &TableName{baseTable: table.baseTable}
+	seen := map[nameKey]bool{}
+	for _, entry := range table.entries {
+		key := nameKey{entry.PlatformID, entry.EncodingID, entry.LanguageID, entry.NameID}
+		if seen[key] || (dropLanguages && !entry.isEssentialLanguage(keep)) {
+			continue
+		}
+		seen[key] = true
+		sanitized.Add(&NameEntry{
+			PlatformID: entry.PlatformID,
+			EncodingID: entry.EncodingID,
+			LanguageID: entry.LanguageID,
+			NameID:     entry.NameID,
+			Value:      entry.valueWithoutControls(),
+		})
+	}
+
+	sort.SliceStable(sanitized.entries, func(i, j int) bool {
+		a, b := sanitized.entries[i], sanitized.entries[j]
+		if a.PlatformID != b.PlatformID {
+			return a.PlatformID < b.PlatformID
+		}
+		if a.EncodingID != b.EncodingID {
+			return a.EncodingID < b.EncodingID
+		}
+		if a.LanguageID != b.LanguageID {
+			return a.LanguageID < b.LanguageID
+		}
+		return a.NameID < b.NameID
+	})
+	return sanitized
+}
+
+// isUTF16 returns true if the value of the entry is encoded in UTF-16, as it
+// is for the Unicode platform and the Microsoft symbol and Unicode encodings.
+func (nameEntry *NameEntry) isUTF16() bool {
+	if nameEntry.PlatformID == PlatformUnicode {
+		return true
+	}
+	return nameEntry.PlatformID == PlatformMicrosoft && (nameEntry.EncodingID == PlatformEncodingMicrosoftSymbol ||
+		nameEntry.EncodingID == PlatformEncodingMicrosoftUnicode || nameEntry.EncodingID == PlatformEncodingMicrosoftUCS4)
+}
+
+// isEssentialLanguage returns true if the entry is in English, which is the
+// language that software falls back to, is for the Unicode platform, which has
+// no languages, or is in one of the keep languages.
+func (nameEntry *NameEntry) isEssentialLanguage(keep []PlatformLanguageID) bool {
+	switch {
+	case nameEntry.PlatformID == PlatformUnicode:
+		return true
+	case nameEntry.PlatformID == PlatformMac && nameEntry.LanguageID == PlatformLanguageMacEnglish:
+		return true
+	case nameEntry.PlatformID == PlatformMicrosoft && nameEntry.LanguageID == PlatformLanguageMicrosoftEnglish:
+		return true
+	}
+	for _, id := range keep {
+		if nameEntry.LanguageID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// valueWithoutControls returns the value of the entry without control
+// characters, other than tabs and line feeds, which are used in long entries
+// such as the license. Only UTF-16 and Mac Roman values are changed, as other
+// encodings cannot be decoded reliably.
+func (nameEntry *NameEntry) valueWithoutControls() []byte {
+	keep := func(r rune) bool {
+		return !unicode.IsControl(r) || r == '\t' || r == '\n'
+	}
+
+	switch {
+	case nameEntry.isUTF16():
+		utf16 := encunicode.UTF16(encunicode.BigEndian, encunicode.IgnoreBOM)
+		s, _, err := transform.String(utf16.NewDecoder(), string(nameEntry.Value))
+		if err != nil {
+			break
+		}
+		cleaned := strings.Map(func(r rune) rune {
+			if keep(r) {
+				return r
+			}
+			return -1
+		}, s)
+		if cleaned == s {
+			break
+		}
+		value, _, err := transform.String(utf16.NewEncoder(), cleaned)
+		if err == nil {
+			return []byte(value)
+		}
+	case nameEntry.PlatformID == PlatformMac && nameEntry.EncodingID == PlatformEncodingMacRoman:
+		// Mac Roman is ASCII below 0x80, so its controls are single bytes.
+		value := make([]byte, 0, len(nameEntry.Value))
+		for _, b := range nameEntry.Value {
+			if keep(rune(b)) || b >= 0x80 {
+				value = append(value, b)
+			}
+		}
+		return value
+	}
+	return append([]byte(nil), nameEntry.Value...)
+}
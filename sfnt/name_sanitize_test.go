@@ -0,0 +1,67 @@
+package sfnt
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestWriteSanitizedNames(t *testing.T) {
+	font := parseTestFont(t, "Roboto-BoldItalic.ttf")
+	name := NewTableName()
+	name.AddMicrosoftEnglishEntry(NameFull, "Roboto\x00 Bold\r\nItalic")
+	name.AddMacEnglishEntry(NameFontFamily, "Roboto\x01")
+	name.AddMicrosoftEnglishEntry(NameFontFamily, "Roboto")
+	name.AddMicrosoftEnglishEntry(NameFontFamily, "Roboto Duplicate")
+	german := &NameEntry{PlatformID: PlatformMicrosoft, EncodingID: PlatformEncodingMicrosoftUnicode, LanguageID: 0x0407, NameID: NameFontSubfamily, Value: []byte{0, 'K', 0, 'u', 0, 'r', 0, 's', 0, 'i', 0, 'v'}}
+	name.Add(german)
+	french := &NameEntry{PlatformID: PlatformMicrosoft, EncodingID: PlatformEncodingMicrosoftUnicode, LanguageID: 0x040C, NameID: NameFontSubfamily, Value: []byte{0, 'I', 0, 't', 0, 'a', 0, 'l', 0, 'i', 0, 'q', 0, 'u', 0, 'e'}}
+	name.Add(french)
+	font.AddTable(TagName, name)
+
+	written := func(options WriteOptions) []string {
+		t.Helper()
+		var buf bytes.Buffer
+		if _, err := font.WriteOTFWithOptions(&buf, options); err != nil {
+			t.Fatal(err)
+		}
+		parsed, err := Parse(bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		name, err := parsed.NameTable()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var entries []string
+		for _, entry := range name.List() {
+			entries = append(entries, entry.Platform()+" "+entry.Label()+": "+entry.String())
+		}
+		return entries
+	}
+
+	want := []string{
+		"Mac Font Family: Roboto",
+		"Microsoft Font Subfamily: Kursiv",
+		"Microsoft Font Family: Roboto",
+		"Microsoft Full Name: Roboto Bold\nItalic",
+		"Microsoft Font Subfamily: Italique",
+	}
+	if got := written(WriteOptions{SanitizeNames: true}); !reflect.DeepEqual(got, want) {
+		t.Errorf("written names = %q, want %q", got, want)
+	}
+	want = []string{
+		"Mac Font Family: Roboto",
+		"Microsoft Font Family: Roboto",
+		"Microsoft Full Name: Roboto Bold\nItalic",
+		"Microsoft Font Subfamily: Italique",
+	}
+	if got := written(WriteOptions{SanitizeNames: true, DropNameLanguages: true, KeepNameLanguages: []PlatformLanguageID{0x040C}}); !reflect.DeepEqual(got, want) {
+		t.Errorf("written names with DropNameLanguages = %q, want %q", got, want)
+	}
+
+	// The font itself is not changed.
+	if len(name.List()) != 6 {
+		t.Errorf("len(List()) = %d after writing, want 6", len(name.List()))
+	}
+}
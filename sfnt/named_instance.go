@@ -0,0 +1,74 @@
+package sfnt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Instance is a named instance of a variable font, with its names looked up in
+// the 'name' table.
+type Instance struct {
+	Name           string // Name is the subfamily name of the instance, such as "SemiBold Italic".
+	PostScriptName string // PostScriptName is the name given by InstancePostscriptName.
+
+	// Location is the user coordinate of the instance on each axis.
+	Location map[Tag]float64
+}
+
+// NamedInstances returns the named instances of a variable font in the order
+// of the 'fvar' table, or nil if the font does not vary.
+func (font *Font) NamedInstances() ([]Instance, error) {
+	if !font.HasTable(TagFvar) {
+		return nil, nil
+	}
+	fvar, err := font.FvarTable()
+	if err != nil {
+		return nil, err
+	}
+	name, err := font.NameTable()
+	if err != nil {
+		return nil, err
+	}
+
+	instances := make([]Instance, len(fvar.Instances))
+	for i, record := range fvar.Instances {
+		location := make(map[Tag]float64, len(fvar.Axes))
+		for j, axis := range fvar.Axes {
+			location[axis.Tag] = record.Coordinates[j]
+		}
+		ps, err := font.InstancePostscriptName(location)
+		if err != nil {
+			return nil, err
+		}
+		instances[i] = Instance{Name: name.Lookup(record.SubfamilyNameID), PostScriptName: ps, Location: location}
+	}
+	return instances, nil
+}
+
+// InstanceByName returns the location of the named instance with the given
+// subfamily or PostScript name. Names are compared ignoring case, spaces and
+// hyphens, so "SemiBold Italic" also matches "semibold-italic".
+func (font *Font) InstanceByName(name string) (map[Tag]float64, error) {
+	instances, err := font.NamedInstances()
+	if err != nil {
+		return nil, err
+	}
+	if instances == nil {
+		return nil, fmt.Errorf("font has no named instances")
+	}
+
+	key := instanceNameKey(name)
+	var names []string
+	for _, instance := range instances {
+		if instanceNameKey(instance.Name) == key || instanceNameKey(instance.PostScriptName) == key {
+			return instance.Location, nil
+		}
+		names = append(names, fmt.Sprintf("%q", instance.Name))
+	}
+	return nil, fmt.Errorf("font has no instance named %q (it has %s)", name, strings.Join(names, ", "))
+}
+
+// instanceNameKey returns the form of an instance name used to compare names.
+func instanceNameKey(name string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "-", "").Replace(name))
+}
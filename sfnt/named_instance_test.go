@@ -0,0 +1,50 @@
+package sfnt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNamedInstances(t *testing.T) {
+	font := limitTestFont(t)
+	name, err := font.NameTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for id, value := range map[NameID]string{NameFontFamily: "Test Sans", 258: "Light", 259: "Regular", 260: "SemiBold Italic"} {
+		name.Remove(id)
+		if err := name.AddMicrosoftEnglishEntry(id, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	instances, err := font.NamedInstances()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Instance{
+		{"Light", "TestSans-Light", map[Tag]float64{axisWeight: 300, axisWidth: 100}},
+		{"Regular", "TestSans-Regular", map[Tag]float64{axisWeight: 400, axisWidth: 100}},
+		{"SemiBold Italic", "TestSans-SemiBoldItalic", map[Tag]float64{axisWeight: 700, axisWidth: 100}},
+	}
+	if !reflect.DeepEqual(instances, want) {
+		t.Errorf("NamedInstances() = %v, want %v", instances, want)
+	}
+
+	for _, name := range []string{"SemiBold Italic", "semibold-italic", "TestSans-SemiBoldItalic"} {
+		if location, err := font.InstanceByName(name); err != nil || !reflect.DeepEqual(location, want[2].Location) {
+			t.Errorf("InstanceByName(%q) = %v, %v, want %v", name, location, err, want[2].Location)
+		}
+	}
+	if _, err := font.InstanceByName("Black"); err == nil {
+		t.Errorf("InstanceByName(Black) err = nil, want error")
+	}
+
+	static, err := NewBuilder(1000).Font()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if instances, err := static.NamedInstances(); err != nil || instances != nil {
+		t.Errorf("NamedInstances() of a static font = %v, %v, want nil", instances, err)
+	}
+}
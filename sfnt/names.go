@@ -71,6 +71,19 @@ var (
 		"VDMX": "Vertical device metrics",
 		"vhea": "Vertical Metrics header",
 		"vmtx": "Vertical Metrics",
+
+		// Graphite Tables
+		"Feat": "Graphite features",
+		"Glat": "Graphite glyph attributes",
+		"Gloc": "Graphite glyph attribute locations",
+		"Silf": "Graphite rules",
+
+		// Apple Advanced Typography Tables
+		"bdat": "Bitmap data",
+		"bhed": "Bitmap font header",
+		"bloc": "Bitmap location data",
+		"kerx": "Extended kerning",
+		"trak": "Tracking",
 	}
 
 	// languageTags contains the registered language names mapped by tag.
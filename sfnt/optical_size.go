@@ -0,0 +1,117 @@
+package sfnt
+
+import "math"
+
+var axisOpticalSize = MustNamedTag("opsz")
+
+// OpticalSize is the range of point sizes that a font is designed to be used at.
+type OpticalSize struct {
+	Design float64 // Design is the point size the font was designed for, or 0 if it is not known.
+
+	// Min and Max are the smallest and largest recommended point sizes. Max is
+	// +Inf if there is no upper limit.
+	Min, Max float64
+}
+
+// Contains returns true if the font is recommended for the given point size.
+// The range includes Min and excludes Max, so that adjacent fonts of an optical
+// family do not overlap.
+func (size OpticalSize) Contains(pointSize float64) bool {
+	return pointSize >= size.Min && pointSize < size.Max
+}
+
+// OpticalSize returns the range of point sizes that the font is designed for,
+// from its 'opsz' variation axis, the 'size' feature in GPOS, or the optical
+// point sizes in version 5 of 'OS/2'. It returns false if the font has none of
+// them.
+func (font *Font) OpticalSize() (OpticalSize, bool, error) {
+	if font.HasTable(TagFvar) {
+		fvar, err := font.FvarTable()
+		if err != nil {
+			return OpticalSize{}, false, err
+		}
+		for _, axis := range fvar.Axes {
+			if axis.Tag == axisOpticalSize {
+				return OpticalSize{Design: axis.Default, Min: axis.Min, Max: axis.Max}, true, nil
+			}
+		}
+	}
+
+	if font.HasTable(TagGpos) {
+		gpos, err := font.GposTable()
+		if err != nil {
+			return OpticalSize{}, false, err
+		}
+		if params, found := gpos.SizeParams(); found {
+			// Fonts that are not part of an optical family have no range.
+			size := OpticalSize{Design: params.DesignSize, Max: math.Inf(1)}
+			if params.SubfamilyID != 0 {
+				size.Min, size.Max = params.RangeStart, params.RangeEnd
+			}
+			return size, true, nil
+		}
+	}
+
+	if font.HasTable(TagOS2) {
+		os2, err := font.OS2Table()
+		if err != nil {
+			return OpticalSize{}, false, err
+		}
+		// The sizes are in twentieths of a point.
+		if os2.Version >= 5 && (os2.UsLowerPointSize != 0 || os2.UsUpperPointSize != 0xFFFF) {
+			size := OpticalSize{Min: float64(os2.UsLowerPointSize) / 20, Max: math.Inf(1)}
+			if os2.UsUpperPointSize != 0xFFFF {
+				size.Max = float64(os2.UsUpperPointSize) / 20
+			}
+			return size, true, nil
+		}
+	}
+	return OpticalSize{}, false, nil
+}
+
+// NormalizedCoordinates converts a location in the design space of a variable
+// font to the normalized coordinates used by variation data (as taken by
+// KerningValue), in the order of the axes in 'fvar' and with the 'avar' mapping
+// applied. Axes missing from location are set to their default. It returns nil
+// for fonts that do not vary.
+func (font *Font) NormalizedCoordinates(location map[Tag]float64) ([]float64, error) {
+	if !font.HasTable(TagFvar) {
+		return nil, nil
+	}
+	fvar, err := font.FvarTable()
+	if err != nil {
+		return nil, err
+	}
+	var avar *TableAvar
+	if font.HasTable(TagAvar) {
+		if avar, err = font.AvarTable(); err != nil {
+			return nil, err
+		}
+	}
+
+	coords := make([]float64, len(fvar.Axes))
+	for i, axis := range fvar.Axes {
+		if v, found := location[axis.Tag]; found {
+			coords[i] = axis.Normalize(v)
+		}
+		if avar != nil {
+			coords[i] = avar.Map(i, coords[i])
+		}
+	}
+	return coords, nil
+}
+
+// CoordinatesAtSize returns the normalized coordinates of location, like
+// NormalizedCoordinates, for text set at the given point size. If the font has
+// an 'opsz' axis that is not in location, it is set to the point size, as
+// browsers do with font-optical-sizing: auto.
+func (font *Font) CoordinatesAtSize(location map[Tag]float64, pointSize float64) ([]float64, error) {
+	if _, found := location[axisOpticalSize]; !found {
+		sized := map[Tag]float64{axisOpticalSize: pointSize}
+		for tag, v := range location {
+			sized[tag] = v
+		}
+		location = sized
+	}
+	return font.NormalizedCoordinates(location)
+}
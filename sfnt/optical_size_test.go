@@ -0,0 +1,82 @@
+package sfnt
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestOpticalSize(t *testing.T) {
+	font := limitTestFont(t)
+	if _, found, err := font.OpticalSize(); err != nil || found {
+		t.Errorf("OpticalSize() without opsz = %v, %v, want not found", found, err)
+	}
+
+	os2, err := font.OS2Table()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os2.UpgradeToVersion(5); err != nil {
+		t.Fatal(err)
+	}
+	os2.UsLowerPointSize, os2.UsUpperPointSize = 8*20, 12*20
+	got, found, err := font.OpticalSize()
+	if err != nil || !found || got != (OpticalSize{Min: 8, Max: 12}) {
+		t.Errorf("OpticalSize() from OS/2 = %+v, %v, %v, want 8 to 12", got, found, err)
+	}
+	if !got.Contains(8) || got.Contains(12) {
+		t.Errorf("Contains(8), Contains(12) = %v, %v, want true, false", got.Contains(8), got.Contains(12))
+	}
+	os2.UsUpperPointSize = 0xFFFF
+	if got, _, _ := font.OpticalSize(); !math.IsInf(got.Max, 1) {
+		t.Errorf("OpticalSize() with no upper limit has Max %v, want +Inf", got.Max)
+	}
+
+	fvar, err := font.FvarTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fvar.Axes = append(fvar.Axes, VariationAxis{Tag: axisOpticalSize, Min: 6, Default: 12, Max: 72})
+	got, found, err = font.OpticalSize()
+	if err != nil || !found || got != (OpticalSize{Design: 12, Min: 6, Max: 72}) {
+		t.Errorf("OpticalSize() from fvar = %+v, %v, %v, want 12 in 6 to 72", got, found, err)
+	}
+}
+
+func TestCoordinatesAtSize(t *testing.T) {
+	font := limitTestFont(t)
+	fvar, err := font.FvarTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fvar.Axes = append(fvar.Axes, VariationAxis{Tag: axisOpticalSize, Min: 6, Default: 12, Max: 72})
+
+	tests := []struct {
+		location  map[Tag]float64
+		pointSize float64
+		want      []float64
+	}{
+		{nil, 12, []float64{0, 0, 0}},
+		{nil, 42, []float64{0, 0, 0.5}},
+		{nil, 200, []float64{0, 0, 1}},
+		{map[Tag]float64{axisWeight: 650}, 9, []float64{0.8, 0, -0.5}},
+		{map[Tag]float64{axisOpticalSize: 6}, 42, []float64{0, 0, -1}},
+	}
+	for _, test := range tests {
+		got, err := font.CoordinatesAtSize(test.location, test.pointSize)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("CoordinatesAtSize(%v, %v) = %v, want %v", test.location, test.pointSize, got, test.want)
+		}
+	}
+
+	plain, err := NewBuilder(1000).Font()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := plain.CoordinatesAtSize(nil, 12); err != nil || got != nil {
+		t.Errorf("CoordinatesAtSize() for a static font = %v, %v, want nil", got, err)
+	}
+}
@@ -0,0 +1,201 @@
+package sfnt
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// HeaderTables are the tables read by ParseHeader if no others are requested.
+var HeaderTables = []Tag{TagHead, TagName, TagOS2}
+
+// ParseHeader reads the table directory and the requested tables (or HeaderTables
+// if none are given) from an OpenType, TrueType, WOFF or WOFF2 stream. Unlike Parse,
+// it reads r sequentially without seeking, and stops reading once it has the last
+// requested table, which makes it suitable for inspecting fonts as they arrive
+// over the network.
+//
+// The returned Font contains only the requested tables that are present in the font.
+// WOFF2 fonts are compressed as a single stream, so the whole of r is read.
+func ParseHeader(r io.Reader, tags ...Tag) (*Font, error) {
+	if len(tags) == 0 {
+		tags = HeaderTables
+	}
+
+	magic, err := ReadTag(r)
+	if err != nil {
+		return nil, err
+	}
+	stream := &streamReader{r: io.MultiReader(bytes.NewReader(magic.bytes()), r)}
+
+	var font *Font
+	switch magic {
+	case SignatureWOFF:
+		font, err = parseWOFFHeader(stream)
+	case SignatureWOFF2:
+		font, err = parseWOFF2(stream)
+	case TypeTrueType, TypeOpenType, TypePostScript1, TypeAppleTrueType:
+		font, err = parseOTFHeader(stream)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[Tag]bool, len(tags))
+	for _, tag := range tags {
+		wanted[tag] = true
+	}
+	// Apple fonts that only have bitmaps have a 'bhed' table instead of 'head'.
+	wanted[TagBhed] = wanted[TagHead]
+	var sections []*tableSection
+	for tag, s := range font.tables {
+		if !wanted[tag] {
+			delete(font.tables, tag)
+			continue
+		}
+		sections = append(sections, s)
+	}
+
+	if magic == SignatureWOFF2 {
+		return font, nil
+	}
+
+	sort.Slice(sections, func(i, j int) bool {
+		return sections[i].offset < sections[j].offset
+	})
+	// Tables with the same contents may share their data, but other tables
+	// must not overlap.
+	var previous *tableSection
+	for _, s := range sections {
+		if previous != nil && s.offset == previous.offset && s.length == previous.length && s.zLength == previous.zLength {
+			s.bytes = previous.bytes
+			continue
+		}
+		if err := stream.readSection(s); err != nil {
+			return nil, fmt.Errorf("failed to read %q: %s", s.tag, err)
+		}
+		previous = s
+	}
+	font.file = nil
+
+	return font, nil
+}
+
+// parseOTFHeader reads the table directory of an OpenType font.
+func parseOTFHeader(r io.Reader) (*Font, error) {
+	var header otfHeader
+	if err := readOTFHeaderFast(r, &header); err != nil {
+		return nil, err
+	}
+
+	font := &Font{
+		scalerType: header.ScalerType,
+		tables:     make(map[Tag]*tableSection, header.NumTables),
+	}
+
+	for i := 0; i < int(header.NumTables); i++ {
+		var entry directoryEntry
+		if err := readDirectoryEntryFast(r, &entry); err != nil {
+			return nil, err
+		}
+		if _, found := font.tables[entry.Tag]; found {
+			return nil, fmt.Errorf("found multiple %q tables", entry.Tag)
+		}
+
+		font.tables[entry.Tag] = &tableSection{
+			tag:      entry.Tag,
+			offset:   entry.Offset,
+			length:   entry.Length,
+			checkSum: entry.CheckSum,
+		}
+	}
+
+	return font, nil
+}
+
+// parseWOFFHeader reads the table directory of a WOFF font.
+func parseWOFFHeader(r io.Reader) (*Font, error) {
+	var header woffHeader
+	if err := readWOFFHeaderFast(r, &header); err != nil {
+		return nil, err
+	}
+
+	font := &Font{
+		scalerType: header.Flavor,
+		tables:     make(map[Tag]*tableSection, header.NumTables),
+	}
+
+	for i := 0; i < int(header.NumTables); i++ {
+		var entry woffEntry
+		if err := readWOFFEntryFast(r, &entry); err != nil {
+			return nil, err
+		}
+		if _, found := font.tables[entry.Tag]; found {
+			return nil, fmt.Errorf("found multiple %q tables", entry.Tag)
+		}
+
+		font.tables[entry.Tag] = &tableSection{
+			tag:      entry.Tag,
+			offset:   entry.Offset,
+			length:   entry.CompLength,
+			zLength:  entry.OrigLength,
+			checkSum: entry.OrigChecksum,
+		}
+	}
+
+	return font, nil
+}
+
+// streamReader is a reader that keeps track of how far through the stream it is.
+type streamReader struct {
+	r      io.Reader
+	offset int64
+}
+
+func (s *streamReader) Read(buf []byte) (int, error) {
+	n, err := s.r.Read(buf)
+	s.offset += int64(n)
+	return n, err
+}
+
+// readSection skips forward to the table, and reads its uncompressed content into
+// s.bytes. Tables must be read in order of their offset.
+func (s *streamReader) readSection(section *tableSection) error {
+	if int64(section.offset) < s.offset {
+		return fmt.Errorf("table at offset %d overlaps the preceding data", section.offset)
+	}
+	if _, err := io.CopyN(io.Discard, s, int64(section.offset)-s.offset); err != nil {
+		return err
+	}
+
+	// Read into a growing buffer rather than allocating the claimed length up front,
+	// so that a bogus length in the directory can't exhaust memory.
+	var buf bytes.Buffer
+	if _, err := io.CopyN(&buf, s, int64(section.length)); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return err
+	}
+
+	if section.length != 0 && section.length < section.zLength {
+		r, err := zlib.NewReader(&buf)
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+
+		var data bytes.Buffer
+		if _, err := io.CopyN(&data, r, int64(section.zLength)); err != nil {
+			return err
+		}
+		section.bytes = data.Bytes()
+	} else {
+		section.bytes = buf.Bytes()
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package sfnt
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+)
+
+// ChecksumMode is what parsing does when the checksum recorded for a table in
+// the table directory does not match the table's contents, as happens when a
+// download is truncated or corrupted.
+type ChecksumMode int
+
+const (
+	// ChecksumIgnore does not verify checksums. It is the default, and what
+	// Parse and StrictParse do.
+	ChecksumIgnore ChecksumMode = iota
+	// ChecksumWarn verifies checksums, and records a *ChecksumError for each
+	// table whose checksum is wrong, which can be read with Font.ParseWarnings.
+	ChecksumWarn
+	// ChecksumFail verifies checksums, and returns a *ChecksumError for the
+	// first table (by tag) whose checksum is wrong.
+	ChecksumFail
+)
+
+// ParseOptions controls how a font is parsed.
+type ParseOptions struct {
+	// Strict parses every table, and fails if any cannot be parsed, as
+	// StrictParse does.
+	Strict bool
+
+	// Checksums controls whether the checksum of each table is verified.
+	// Verifying checksums reads every table from the file. WOFF2 files do not
+	// record checksums, so they are never verified.
+	Checksums ChecksumMode
+
+	// MaxSize is the largest total size of the font's tables, in bytes, once
+	// they are decompressed. Larger fonts are rejected with ErrTooLarge before
+	// their tables are read, so that a small WOFF or WOFF2 file can't make
+	// parsing allocate without bound. If it is zero, there is no limit.
+	MaxSize int64
+}
+
+// ChecksumError is the problem found when a table's checksum is wrong.
+type ChecksumError struct {
+	Tag      Tag
+	Recorded uint32 // Recorded is the checksum in the table directory.
+	Computed uint32 // Computed is the checksum of the table's contents.
+
+	length int // length is the size of the table's contents.
+}
+
+// Error returns a human readable description of the problem.
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("%q: invalid checksum: the table directory has 0x%08X, but the table has 0x%08X", e.Tag, e.Recorded, e.Computed)
+}
+
+// Unwrap returns ErrInvalidChecksum, so that errors.Is can be used to detect
+// checksum errors.
+func (e *ChecksumError) Unwrap() error {
+	return ErrInvalidChecksum
+}
+
+// ParseWithOptions parses an OpenType, TrueType, WOFF or WOFF2 file, like
+// ParseContext, using the given options.
+func ParseWithOptions(ctx context.Context, file File, options ParseOptions) (*Font, error) {
+	magic, err := ReadTag(file)
+	if err != nil {
+		return nil, err
+	}
+	file.Seek(0, 0)
+
+	// The table directory of a WOFF2 file does not bound the size of its
+	// compressed data, which is decompressed as it is parsed.
+	if options.MaxSize > 0 && magic == SignatureWOFF2 {
+		if err := checkWOFF2Size(file, options.MaxSize); err != nil {
+			return nil, err
+		}
+		file.Seek(0, 0)
+	}
+
+	font, err := ParseContext(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.MaxSize > 0 && font.tablesSize() > options.MaxSize {
+		return nil, ErrTooLarge
+	}
+
+	if options.Checksums != ChecksumIgnore && magic != SignatureWOFF2 {
+		problems, err := font.verifyChecksums()
+		if err != nil {
+			return nil, err
+		}
+		if len(problems) > 0 && options.Checksums == ChecksumFail {
+			return nil, problems[0]
+		}
+		for _, problem := range problems {
+			font.warnings = append(font.warnings, problem)
+		}
+	}
+
+	if options.Strict {
+		if err := font.parseTables(ctx, runtime.GOMAXPROCS(0)); err != nil {
+			return nil, err
+		}
+	}
+	return font, nil
+}
+
+// tablesSize returns the total size of the tables in the file the font was
+// parsed from, once they are decompressed.
+func (font *Font) tablesSize() int64 {
+	var size int64
+	for _, s := range font.tables {
+		if s.zLength > s.length {
+			size += int64(s.zLength)
+		} else {
+			size += int64(s.length)
+		}
+	}
+	return size
+}
+
+// ParseWarnings returns the problems found while parsing the font that did
+// not stop it from being parsed, such as the checksum errors recorded with
+// ChecksumWarn.
+func (font *Font) ParseWarnings() []error {
+	return font.warnings
+}
+
+// verifyChecksums returns an error for each table in the file the font was
+// parsed from whose contents do not match the checksum in the table directory.
+// Tables that were added after parsing are not checked.
+func (font *Font) verifyChecksums() ([]*ChecksumError, error) {
+	var problems []*ChecksumError
+	for _, tag := range font.Tags() {
+		s, found := font.section(tag)
+		if !found || s.bytes != nil || s.length == 0 {
+			continue
+		}
+		data, err := font.readTable(s)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %s", tag, err)
+		}
+
+		if sum := tableCheckSum(tag, data); sum != s.checkSum {
+			problems = append(problems, &ChecksumError{Tag: tag, Recorded: s.checkSum, Computed: sum, length: len(data)})
+		}
+	}
+	return problems, nil
+}
+
+// tableCheckSum returns the checksum of the table with the given tag, as it is
+// recorded in the table directory.
+func tableCheckSum(tag Tag, data []byte) uint32 {
+	// The checksum of 'head' is calculated with checkSumAdjustment set
+	// to zero, as it is set after the checksums of all the tables.
+	if (tag == TagHead || tag == TagBhed) && len(data) >= headCheckSumAdjustmentOffset+4 {
+		data = append([]byte(nil), data...)
+		copy(data[headCheckSumAdjustmentOffset:], []byte{0, 0, 0, 0})
+	}
+	return checkSum(data)
+}
@@ -0,0 +1,92 @@
+package sfnt
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseWithOptionsChecksums(t *testing.T) {
+	for _, name := range []string{"Roboto-BoldItalic.ttf", "Raleway-v4020-Regular.otf", "open-sans-v15-latin-regular.woff", "Go-Regular.woff2"} {
+		data, err := ioutil.ReadFile(filepath.Join("testdata", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		font, err := ParseWithOptions(context.Background(), bytes.NewReader(data), ParseOptions{Checksums: ChecksumFail})
+		if err != nil {
+			t.Errorf("%s: ParseWithOptions() err = %q, want nil", name, err)
+			continue
+		}
+		if warnings := font.ParseWarnings(); len(warnings) != 0 {
+			t.Errorf("%s: ParseWarnings() = %v, want none", name, warnings)
+		}
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join("testdata", "Roboto-BoldItalic.ttf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	font, err := Parse(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Fonts written by this package have correct checksums.
+	var buf bytes.Buffer
+	if _, err := font.WriteOTF(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseWithOptions(context.Background(), bytes.NewReader(buf.Bytes()), ParseOptions{Checksums: ChecksumFail}); err != nil {
+		t.Errorf("ParseWithOptions() of written font err = %q, want nil", err)
+	}
+	var name TableRecord
+	for _, record := range font.Directory() {
+		if record.Tag == TagName {
+			name = record
+		}
+	}
+	data[name.Offset+10]++
+
+	if _, err := ParseWithOptions(context.Background(), bytes.NewReader(data), ParseOptions{}); err != nil {
+		t.Errorf("ParseWithOptions(ChecksumIgnore) err = %q, want nil", err)
+	}
+
+	_, err = ParseWithOptions(context.Background(), bytes.NewReader(data), ParseOptions{Checksums: ChecksumFail})
+	var checksumErr *ChecksumError
+	if !errors.As(err, &checksumErr) || checksumErr.Tag != TagName || !errors.Is(err, ErrInvalidChecksum) {
+		t.Errorf("ParseWithOptions(ChecksumFail) err = %v, want a checksum error for 'name'", err)
+	}
+
+	font, err = ParseWithOptions(context.Background(), bytes.NewReader(data), ParseOptions{Checksums: ChecksumWarn, Strict: true})
+	if err != nil {
+		t.Fatalf("ParseWithOptions(ChecksumWarn) err = %q, want nil", err)
+	}
+	if warnings := font.ParseWarnings(); len(warnings) != 1 || !errors.Is(warnings[0], ErrInvalidChecksum) {
+		t.Errorf("ParseWarnings() = %v, want one checksum error", warnings)
+	}
+}
+
+func TestParseWithOptionsMaxSize(t *testing.T) {
+	for _, name := range []string{"Roboto-BoldItalic.ttf", "open-sans-v15-latin-regular.woff", "Go-Regular.woff2"} {
+		data, err := ioutil.ReadFile(filepath.Join("testdata", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		font, err := ParseWithOptions(context.Background(), bytes.NewReader(data), ParseOptions{MaxSize: 1 << 30})
+		if err != nil {
+			t.Errorf("%s: ParseWithOptions() err = %q, want nil", name, err)
+			continue
+		}
+
+		size := font.tablesSize()
+		if _, err := ParseWithOptions(context.Background(), bytes.NewReader(data), ParseOptions{MaxSize: size}); err != nil {
+			t.Errorf("%s: ParseWithOptions(MaxSize: %d) err = %q, want nil", name, size, err)
+		}
+		if _, err := ParseWithOptions(context.Background(), bytes.NewReader(data), ParseOptions{MaxSize: size - 1}); err != ErrTooLarge {
+			t.Errorf("%s: ParseWithOptions(MaxSize: %d) err = %v, want ErrTooLarge", name, size-1, err)
+		}
+	}
+}
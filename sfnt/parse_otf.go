@@ -110,7 +110,7 @@ func parseOTF(file File) (*Font, error) {
 			return nil, err
 		}
 
-		// TODO Check the checksum.
+		// The checksum is verified by ParseWithOptions, depending on its ChecksumMode.
 
 		if _, found := font.tables[entry.Tag]; found {
 			return nil, fmt.Errorf("found multiple %q tables", entry.Tag)
@@ -119,12 +119,13 @@ func parseOTF(file File) (*Font, error) {
 		font.tables[entry.Tag] = &tableSection{
 			tag: entry.Tag,
 
-			offset: entry.Offset,
-			length: entry.Length,
+			offset:   entry.Offset,
+			length:   entry.Length,
+			checkSum: entry.CheckSum,
 		}
 	}
 
-	if _, ok := font.tables[TagHead]; !ok {
+	if font.tables[TagHead] == nil && font.tables[TagBhed] == nil {
 		return nil, ErrMissingHead
 	}
 
@@ -2,7 +2,12 @@ package sfnt
 
 import (
 	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
 	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 )
 
@@ -41,3 +46,144 @@ func TestGSubLookupRecords(t *testing.T) {
 			table.Lookups[34].GSubString())
 	}
 }
+
+func TestParseHeader(t *testing.T) {
+	for _, filename := range []string{"Roboto-BoldItalic.ttf", "Raleway-v4020-Regular.otf", "open-sans-v15-latin-regular.woff", "Go-Regular.woff2"} {
+		data, err := os.ReadFile(filepath.Join("testdata", filename))
+		if err != nil {
+			t.Fatal(err)
+		}
+		full, err := Parse(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Hide everything except Read, so that ParseHeader can't seek.
+		r := struct{ io.Reader }{bytes.NewReader(data)}
+		font, err := ParseHeader(r)
+		if err != nil {
+			t.Errorf("ParseHeader(%q) err = %q, want nil", filename, err)
+			continue
+		}
+
+		if got := font.Tags(); !reflect.DeepEqual(got, []Tag{TagOS2, TagHead, TagName}) {
+			t.Errorf("ParseHeader(%q).Tags() = %v, want [OS/2 head name]", filename, got)
+		}
+		for _, tag := range font.Tags() {
+			got, err := font.TableData(tag)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := full.TableData(tag)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("ParseHeader(%q).TableData(%q) differs from Parse", filename, tag)
+			}
+		}
+		if _, err := font.NameTable(); err != nil {
+			t.Errorf("ParseHeader(%q).NameTable() err = %q, want nil", filename, err)
+		}
+	}
+}
+
+func TestParseHeaderTruncated(t *testing.T) {
+	data, err := os.ReadFile("testdata/Roboto-BoldItalic.ttf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseHeader(bytes.NewReader(data[:1000]), TagPost); err == nil {
+		t.Errorf("ParseHeader(truncated) err = nil, want an error")
+	}
+}
+
+func TestParseHeaderSharedTables(t *testing.T) {
+	data, err := os.ReadFile("testdata/Roboto-BoldItalic.ttf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// directoryEntry returns the entry for tag in the table directory.
+	directoryEntry := func(data []byte, tag Tag) []byte {
+		for i := 0; i < int(binary.BigEndian.Uint16(data[4:])); i++ {
+			entry := data[otfHeaderLength+directoryEntryLength*i:][:directoryEntryLength]
+			if NewTag(entry) == tag {
+				return entry
+			}
+		}
+		t.Fatalf("no %q table", tag)
+		return nil
+	}
+
+	// Point 'post' at the data of 'name', as fonts may do for identical tables.
+	shared := append([]byte(nil), data...)
+	copy(directoryEntry(shared, TagPost)[4:], directoryEntry(shared, TagName)[4:])
+	font, err := ParseHeader(bytes.NewReader(shared), TagName, TagPost)
+	if err != nil {
+		t.Fatalf("ParseHeader(shared tables) err = %q, want nil", err)
+	}
+	name, err := font.TableData(TagName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	post, err := font.TableData(TagPost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(name) == 0 || !bytes.Equal(name, post) {
+		t.Errorf("ParseHeader(shared tables) 'post' differs from 'name'")
+	}
+
+	// Tables that overlap without being the same are still rejected.
+	overlapping := append([]byte(nil), shared...)
+	entry := directoryEntry(overlapping, TagPost)
+	binary.BigEndian.PutUint32(entry[12:], binary.BigEndian.Uint32(entry[12:])-4)
+	if _, err := ParseHeader(bytes.NewReader(overlapping), TagName, TagPost); err == nil {
+		t.Errorf("ParseHeader(overlapping tables) err = nil, want an error")
+	}
+}
+
+func TestParseBitmapOnlyAppleFont(t *testing.T) {
+	roboto := parseTestFont(t, "Roboto-BoldItalic.ttf")
+	head, err := roboto.TableData(TagHead)
+	if err != nil {
+		t.Fatal(err)
+	}
+	name, err := roboto.TableData(TagName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Classic Mac bitmap fonts have a 'bhed' table instead of 'head'.
+	font := New(TypeAppleTrueType)
+	font.RemoveTable(TagHead)
+	font.SetTable(TagBhed, head)
+	font.SetTable(TagName, name)
+	var buf bytes.Buffer
+	if _, err := font.WriteOTF(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := ParseWithOptions(context.Background(), bytes.NewReader(buf.Bytes()), ParseOptions{Strict: true, Checksums: ChecksumFail})
+	if err != nil {
+		t.Fatalf("ParseWithOptions() err = %q, want nil", err)
+	}
+	if parsed.Type() != TypeAppleTrueType {
+		t.Errorf("Type() = %s, want true", parsed.Type())
+	}
+	if parsed.HasTable(TagHead) {
+		t.Errorf("HasTable('head') = true, want false")
+	}
+	if got, err := parsed.HeadTable(); err != nil || got.UnitsPerEm != 2048 {
+		t.Errorf("HeadTable() = %v, %v; want the 'bhed' table", got, err)
+	}
+
+	header, err := ParseHeader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(header.Tags(), []Tag{TagBhed, TagName}) {
+		t.Errorf("ParseHeader() tags = %v, want [bhed name]", header.Tags())
+	}
+}
@@ -90,7 +90,7 @@ func parseWOFF(file File) (*Font, error) {
 			return nil, err
 		}
 
-		// TODO Check the checksum.
+		// The checksum is verified by ParseWithOptions, depending on its ChecksumMode.
 
 		if _, found := font.tables[entry.Tag]; found {
 			return nil, fmt.Errorf("found multiple %q tables", entry.Tag)
@@ -99,13 +99,14 @@ func parseWOFF(file File) (*Font, error) {
 		font.tables[entry.Tag] = &tableSection{
 			tag: entry.Tag,
 
-			offset:  entry.Offset,
-			length:  entry.CompLength,
-			zLength: entry.OrigLength,
+			offset:   entry.Offset,
+			length:   entry.CompLength,
+			zLength:  entry.OrigLength,
+			checkSum: entry.OrigChecksum,
 		}
 	}
 
-	if _, ok := font.tables[TagHead]; !ok {
+	if font.tables[TagHead] == nil && font.tables[TagBhed] == nil {
 		return nil, ErrMissingHead
 	}
 
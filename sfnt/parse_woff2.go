@@ -1,12 +1,17 @@
 package sfnt
 
 import (
+	"bufio"
 	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
 
 	"dmitri.shuralyov.com/font/woff2"
+	"github.com/dsnet/compress/brotli"
 )
 
-func parseWOFF2(file File) (*Font, error) {
+func parseWOFF2(file io.Reader) (*Font, error) {
 	f, err := woff2.Parse(file)
 	if err != nil {
 		return nil, err
@@ -27,3 +32,85 @@ func parseWOFF2(file File) (*Font, error) {
 	}
 	return font, nil
 }
+
+// woff2HeaderLength is the size of the WOFF2 header.
+const woff2HeaderLength = 48
+
+// checkWOFF2Size returns ErrTooLarge if the compressed data of a WOFF2 file
+// decompresses to more than maxSize bytes. The data is decompressed without
+// being kept, as woff2.Parse reads all of it into memory before checking its
+// size against the table directory.
+func checkWOFF2Size(file io.Reader, maxSize int64) error {
+	r := bufio.NewReader(file)
+	header := make([]byte, woff2HeaderLength)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return err
+	}
+	if NewTag(header[4:]) == signatureCollection {
+		return fmt.Errorf("WOFF2 font collections are not supported")
+	}
+	numTables := int(binary.BigEndian.Uint16(header[12:]))
+	compressedSize := int64(binary.BigEndian.Uint32(header[20:]))
+
+	// The compressed data follows the table directory, whose entries have a
+	// variable length.
+	for i := 0; i < numTables; i++ {
+		flags, err := r.ReadByte()
+		if err != nil {
+			return err
+		}
+		// Tags 10 and 11 in the list of known tags are 'glyf' and 'loca'.
+		known := flags & 0x3f
+		isGlyf := known == 10 || known == 11
+		if known == 0x3f {
+			tag := make([]byte, 4)
+			if _, err := io.ReadFull(r, tag); err != nil {
+				return err
+			}
+			isGlyf = NewTag(tag) == TagGlyf || NewTag(tag) == TagLoca
+		}
+		transformed := flags>>6 != 0
+		if isGlyf {
+			// A transform version of 0 means that 'glyf' and 'loca' are transformed.
+			transformed = !transformed
+		}
+		// The original length is followed by the transformed length, if
+		// the table is transformed.
+		if err := skipBase128(r); err != nil {
+			return err
+		}
+		if transformed {
+			if err := skipBase128(r); err != nil {
+				return err
+			}
+		}
+	}
+
+	br, err := brotli.NewReader(io.LimitReader(r, compressedSize), nil)
+	if err != nil {
+		return err
+	}
+	defer br.Close()
+	n, err := io.Copy(io.Discard, io.LimitReader(br, maxSize+1))
+	if err != nil {
+		return err
+	}
+	if n > maxSize {
+		return ErrTooLarge
+	}
+	return nil
+}
+
+// skipBase128 reads a UIntBase128 number, which has up to five bytes.
+func skipBase128(r *bufio.Reader) error {
+	for i := 0; i < 5; i++ {
+		b, err := r.ReadByte()
+		if err != nil {
+			return err
+		}
+		if b&0x80 == 0 {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid UIntBase128 number")
+}
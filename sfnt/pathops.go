@@ -0,0 +1,260 @@
+package sfnt
+
+import (
+	"math"
+	"sort"
+)
+
+// unionCurveSamples is the number of line segments each curve is split into
+// when removing overlaps.
+const unionCurveSamples = 8
+
+// unionEpsilon is the distance from an edge, in font units, at which the
+// winding number is sampled to decide whether the edge is on the boundary.
+const unionEpsilon = 1.0 / 64
+
+// RemoveOverlaps returns contours that cover the same area as the given contours
+// (using the non-zero winding rule) but do not overlap each other or themselves.
+// Outer contours are clockwise and holes are counter-clockwise.
+//
+// Curves are converted to line segments, so this should only be used on glyphs
+// that have overlaps; see TableGlyf.RemoveOverlaps.
+func RemoveOverlaps(contours [][]GlyphPoint) [][]GlyphPoint {
+	var edges []unionEdge
+	for _, polygon := range flattenContours(contours, unionCurveSamples) {
+		for i, a := range polygon {
+			b := polygon[(i+1)%len(polygon)]
+			if a != b {
+				edges = append(edges, unionEdge{a: a, b: b})
+			}
+		}
+	}
+
+	// Split every edge at the points where it meets another edge, so that
+	// each piece is either entirely on the boundary of the union or not.
+	for i := range edges {
+		for j := i + 1; j < len(edges); j++ {
+			splitEdges(&edges[i], &edges[j])
+		}
+	}
+
+	var pieces []unionEdge
+	for _, e := range edges {
+		sort.Slice(e.splits, func(i, j int) bool { return e.splits[i].t < e.splits[j].t })
+		points := []vector{e.a}
+		for _, split := range e.splits {
+			points = append(points, split.p)
+		}
+		points = append(points, e.b)
+		for i := 0; i+1 < len(points); i++ {
+			if points[i] != points[i+1] {
+				pieces = append(pieces, unionEdge{a: points[i], b: points[i+1]})
+			}
+		}
+	}
+
+	// Keep the pieces with the filled area on exactly one side, oriented so
+	// that the filled area is on the right (making outer contours clockwise).
+	kept := make(map[[2]vector]bool)
+	var boundary []unionEdge
+	for _, e := range pieces {
+		d := e.b.sub(e.a)
+		normal := vector{-d.Y, d.X}.scale(unionEpsilon / d.length())
+		mid := e.a.midpoint(e.b)
+		left := windingNumber(mid.add(normal), edges) != 0
+		right := windingNumber(mid.sub(normal), edges) != 0
+		if left == right {
+			continue
+		}
+		if left {
+			e = unionEdge{a: e.b, b: e.a}
+		}
+		if key := [2]vector{e.a, e.b}; !kept[key] {
+			kept[key] = true
+			boundary = append(boundary, e)
+		}
+	}
+
+	// Join the boundary pieces into closed contours.
+	from := make(map[vector][]int)
+	for i, e := range boundary {
+		from[e.a] = append(from[e.a], i)
+	}
+	used := make([]bool, len(boundary))
+	var result [][]GlyphPoint
+	for i := range boundary {
+		if used[i] {
+			continue
+		}
+		var polygon []vector
+		for j := i; j >= 0 && !used[j]; {
+			used[j] = true
+			polygon = append(polygon, boundary[j].a)
+			next := -1
+			for _, k := range from[boundary[j].b] {
+				if !used[k] {
+					next = k
+					break
+				}
+			}
+			j = next
+		}
+		if contour := roundPolygon(polygon); len(contour) >= 3 {
+			result = append(result, contour)
+		}
+	}
+	return result
+}
+
+// unionEdge is an edge of a polygon, with the points at which it meets other edges.
+type unionEdge struct {
+	a, b   vector
+	splits []edgeSplit
+}
+
+// edgeSplit is a point on an edge, at position t (from 0 to 1) along it. The same
+// point is recorded on both edges that meet there, so the pieces join exactly.
+type edgeSplit struct {
+	t float64
+	p vector
+}
+
+// splitEdges records where the edges meet, if they do.
+func splitEdges(e, f *unionEdge) {
+	r, s := e.b.sub(e.a), f.b.sub(f.a)
+	denominator := r.cross(s)
+	if denominator == 0 {
+		// Parallel edges only need splitting where an end point of one is on the other.
+		for _, p := range []vector{f.a, f.b} {
+			if t, ok := pointOnEdge(p, e); ok {
+				e.splits = append(e.splits, edgeSplit{t, p})
+			}
+		}
+		for _, p := range []vector{e.a, e.b} {
+			if t, ok := pointOnEdge(p, f); ok {
+				f.splits = append(f.splits, edgeSplit{t, p})
+			}
+		}
+		return
+	}
+
+	qp := f.a.sub(e.a)
+	t := qp.cross(s) / denominator
+	u := qp.cross(r) / denominator
+	if t < 0 || t > 1 || u < 0 || u > 1 {
+		return
+	}
+
+	// Where an end point of one edge is on the other, use the end point itself.
+	p := e.a.add(r.scale(t))
+	switch {
+	case t == 0:
+		p = e.a
+	case t == 1:
+		p = e.b
+	case u == 0:
+		p = f.a
+	case u == 1:
+		p = f.b
+	}
+	if t > 0 && t < 1 {
+		e.splits = append(e.splits, edgeSplit{t, p})
+	}
+	if u > 0 && u < 1 {
+		f.splits = append(f.splits, edgeSplit{u, p})
+	}
+}
+
+// pointOnEdge returns the position of p along the edge, if it is strictly between its end points.
+func pointOnEdge(p vector, e *unionEdge) (float64, bool) {
+	d := e.b.sub(e.a)
+	if math.Abs(d.cross(p.sub(e.a))) > 1e-9*d.dot(d) {
+		return 0, false
+	}
+	t := p.sub(e.a).dot(d) / d.dot(d)
+	return t, t > 0 && t < 1
+}
+
+// windingNumber returns the winding number of the edges around p.
+func windingNumber(p vector, edges []unionEdge) int {
+	winding := 0
+	for _, e := range edges {
+		if e.a.Y <= p.Y && e.b.Y > p.Y && e.b.sub(e.a).cross(p.sub(e.a)) > 0 {
+			winding++
+		} else if e.a.Y > p.Y && e.b.Y <= p.Y && e.b.sub(e.a).cross(p.sub(e.a)) < 0 {
+			winding--
+		}
+	}
+	return winding
+}
+
+// roundPolygon rounds the points of a polygon to font units, removing points that
+// become duplicates or lie on a straight line between their neighbours.
+func roundPolygon(polygon []vector) []GlyphPoint {
+	var contour []GlyphPoint
+	for _, v := range polygon {
+		p := GlyphPoint{int16(math.Round(v.X)), int16(math.Round(v.Y)), true}
+		if len(contour) == 0 || contour[len(contour)-1] != p {
+			contour = append(contour, p)
+		}
+	}
+	for len(contour) > 1 && contour[0] == contour[len(contour)-1] {
+		contour = contour[:len(contour)-1]
+	}
+
+	for changed := true; changed && len(contour) >= 3; {
+		changed = false
+		for i := 0; i < len(contour) && len(contour) >= 3; i++ {
+			prev, p, next := contour[(i+len(contour)-1)%len(contour)], contour[i], contour[(i+1)%len(contour)]
+			if (int(p.X)-int(prev.X))*(int(next.Y)-int(p.Y)) == (int(p.Y)-int(prev.Y))*(int(next.X)-int(p.X)) {
+				contour = append(contour[:i], contour[i+1:]...)
+				changed = true
+			}
+		}
+	}
+	return contour
+}
+
+// HasOverlaps returns true if the glyph's contours overlap each other or themselves.
+func (table *TableGlyf) HasOverlaps(gid uint16) (bool, error) {
+	contours, err := table.Contours(gid)
+	if err != nil {
+		return false, err
+	}
+	return len(checkOverlaps(flattenContours(contours, validationCurveSamples))) > 0, nil
+}
+
+// RemoveOverlaps replaces a glyph that has overlapping contours with a simple glyph
+// with the overlaps removed. Glyphs without overlaps are not changed, so that their
+// curves are preserved. It returns true if the glyph was changed.
+func (table *TableGlyf) RemoveOverlaps(gid uint16) (bool, error) {
+	overlaps, err := table.HasOverlaps(gid)
+	if err != nil || !overlaps {
+		return false, err
+	}
+	contours, err := table.Contours(gid)
+	if err != nil {
+		return false, err
+	}
+
+	glyph := &Glyph{Contours: RemoveOverlaps(contours)}
+	glyph.UpdateBounds()
+	return true, table.SetGlyph(gid, glyph)
+}
+
+// Decompose replaces a composite glyph with a simple glyph containing the
+// contours of its components. The glyph's instructions are discarded.
+// It returns true if the glyph was changed.
+func (table *TableGlyf) Decompose(gid uint16) (bool, error) {
+	if !table.IsComposite(gid) {
+		return false, nil
+	}
+	contours, err := table.Contours(gid)
+	if err != nil {
+		return false, err
+	}
+
+	glyph := &Glyph{Contours: contours}
+	glyph.UpdateBounds()
+	return true, table.SetGlyph(gid, glyph)
+}
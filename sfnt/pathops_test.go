@@ -0,0 +1,78 @@
+package sfnt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRemoveOverlaps(t *testing.T) {
+	tests := []struct {
+		name     string
+		contours [][]GlyphPoint
+		want     [][]GlyphPoint
+	}{
+		{
+			"overlapping squares",
+			[][]GlyphPoint{square(0, 0, 100), square(50, 50, 100)},
+			[][]GlyphPoint{{{0, 0, true}, {0, 100, true}, {50, 100, true}, {50, 150, true}, {150, 150, true}, {150, 50, true}, {100, 50, true}, {100, 0, true}}},
+		},
+		{
+			"nested squares",
+			[][]GlyphPoint{square(0, 0, 100), square(25, 25, 50)},
+			[][]GlyphPoint{square(0, 0, 100)},
+		},
+		{
+			"counter-clockwise square",
+			[][]GlyphPoint{reversed(square(0, 0, 100))},
+			[][]GlyphPoint{{{100, 100, true}, {100, 0, true}, {0, 0, true}, {0, 100, true}}},
+		},
+		{
+			"square with a hole",
+			[][]GlyphPoint{square(0, 0, 100), reversed(square(25, 25, 50))},
+			[][]GlyphPoint{square(0, 0, 100), {{75, 25, true}, {75, 75, true}, {25, 75, true}, {25, 25, true}}},
+		},
+	}
+
+	for _, test := range tests {
+		got := RemoveOverlaps(test.contours)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("RemoveOverlaps(%s) = %v, want %v", test.name, got, test.want)
+		}
+		if messages := checkContours(got); len(messages) > 0 {
+			t.Errorf("RemoveOverlaps(%s) has problems: %v", test.name, messages)
+		}
+	}
+}
+
+func TestGlyfRemoveOverlaps(t *testing.T) {
+	font := parseTestFont(t, "open-sans-v15-latin-regular.woff")
+	glyf, err := font.GlyfTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	gids, err := font.GlyphIDs([]string{"A", "Ccedilla"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if changed, err := glyf.RemoveOverlaps(gids[0]); changed || err != nil {
+		t.Errorf("RemoveOverlaps(A) = %v, %v; want false, nil", changed, err)
+	}
+	if changed, err := glyf.RemoveOverlaps(gids[1]); !changed || err != nil {
+		t.Fatalf("RemoveOverlaps(Ccedilla) = %v, %v; want true, nil", changed, err)
+	}
+	if glyf.IsComposite(gids[1]) {
+		t.Errorf("IsComposite(Ccedilla) = true after RemoveOverlaps, want false")
+	}
+
+	contours, err := glyf.Contours(gids[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(contours) != 1 {
+		t.Errorf("len(Contours(Ccedilla)) = %d, want 1", len(contours))
+	}
+	if messages := checkContours(contours); len(messages) > 0 {
+		t.Errorf("Ccedilla has problems after RemoveOverlaps: %v", messages)
+	}
+}
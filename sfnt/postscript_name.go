@@ -0,0 +1,104 @@
+package sfnt
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// maxPostscriptNameLength is the longest PostScript name that applications
+// are required to support.
+const maxPostscriptNameLength = 63
+
+// VariationsPostscriptPrefix returns the prefix of the PostScript names of the
+// instances of a variable font. It is name id 25 if the font has one, and
+// otherwise the family name without the characters that are not allowed in
+// PostScript names.
+func (table *TableName) VariationsPostscriptPrefix() string {
+	if prefix := table.Lookup(NameVariationsPostscriptPrefix); prefix != "" {
+		return prefix
+	}
+	family := table.Lookup(NamePreferredFamily)
+	if family == "" {
+		family = table.Lookup(NameFontFamily)
+	}
+	return postscriptName(family)
+}
+
+// InstancePostscriptName returns the PostScript name of the instance of a
+// variable font at the given user coordinates, following Adobe Technical Note
+// #5902. Axes missing from location are at their default.
+//
+// If the location is a named instance, its PostScript name from 'fvar' is used,
+// or the prefix and the subfamily name separated by a hyphen (for example
+// "Roboto-BoldItalic"). Other locations append the value and tag of each axis
+// to the prefix (for example "Roboto_650wght_87.5wdth"). Names that are too
+// long are shortened with a hash of the full name.
+func (font *Font) InstancePostscriptName(location map[Tag]float64) (string, error) {
+	fvar, err := font.FvarTable()
+	if err != nil {
+		return "", err
+	}
+	name, err := font.NameTable()
+	if err != nil {
+		return "", err
+	}
+	prefix := name.VariationsPostscriptPrefix()
+
+	coords := make([]float64, len(fvar.Axes))
+	for i, axis := range fvar.Axes {
+		coords[i] = axis.Default
+		if v, found := location[axis.Tag]; found {
+			coords[i] = math.Max(axis.Min, math.Min(axis.Max, v))
+		}
+	}
+
+	for _, instance := range fvar.Instances {
+		if !equalCoordinates(instance.Coordinates, coords) {
+			continue
+		}
+		if instance.PostScriptNameID != 0xFFFF {
+			if ps := name.Lookup(instance.PostScriptNameID); ps != "" {
+				return ps, nil
+			}
+		}
+		if subfamily := name.Lookup(instance.SubfamilyNameID); subfamily != "" {
+			return limitPostscriptName(prefix, prefix+"-"+postscriptName(subfamily)), nil
+		}
+	}
+
+	ps := prefix
+	for i, axis := range fvar.Axes {
+		value := strconv.FormatFloat(math.Round(coords[i]*100)/100, 'f', -1, 64)
+		ps += "_" + value + strings.TrimRight(axis.Tag.String(), " ")
+	}
+	return limitPostscriptName(prefix, ps), nil
+}
+
+// limitPostscriptName returns ps, or if it is too long the prefix followed by a
+// hash of ps, so that different long names stay distinct.
+func limitPostscriptName(prefix, ps string) string {
+	if len(ps) <= maxPostscriptNameLength {
+		return ps
+	}
+	hash := fmt.Sprintf("-%X", sha1.Sum([]byte(ps)))[:17] + "..."
+	if len(prefix) > maxPostscriptNameLength-len(hash) {
+		prefix = prefix[:maxPostscriptNameLength-len(hash)]
+	}
+	return prefix + hash
+}
+
+// equalCoordinates returns true if a and b are the same position.
+func equalCoordinates(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
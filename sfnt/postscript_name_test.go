@@ -0,0 +1,69 @@
+package sfnt
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInstancePostscriptName(t *testing.T) {
+	font := limitTestFont(t)
+	name, err := font.NameTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for id, value := range map[NameID]string{NameFontFamily: "Test Sans", 258: "Light", 259: "Regular", 260: "Bold Display"} {
+		name.Remove(id)
+		if err := name.AddMicrosoftEnglishEntry(id, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		location map[Tag]float64
+		want     string
+	}{
+		{nil, "TestSans-Regular"},
+		{map[Tag]float64{axisWeight: 700}, "TestSans-BoldDisplay"},
+		{map[Tag]float64{axisWeight: 650}, "TestSans_650wght_100wdth"},
+		{map[Tag]float64{axisWeight: 650.125, axisWidth: 87.5}, "TestSans_650.13wght_87.5wdth"},
+		{map[Tag]float64{axisWeight: 2000}, "TestSans_900wght_100wdth"},
+	}
+	for _, test := range tests {
+		if got, err := font.InstancePostscriptName(test.location); err != nil || got != test.want {
+			t.Errorf("InstancePostscriptName(%v) = %q, %v, want %q", test.location, got, err, test.want)
+		}
+	}
+
+	if err := name.AddMicrosoftEnglishEntry(NameVariationsPostscriptPrefix, "TestSansVF"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := font.InstancePostscriptName(map[Tag]float64{axisWeight: 300}); err != nil || got != "TestSansVF-Light" {
+		t.Errorf("InstancePostscriptName() with a prefix = %q, %v, want TestSansVF-Light", got, err)
+	}
+
+	fvar, err := font.FvarTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fvar.Instances[0].PostScriptNameID = 261
+	if err := name.AddMicrosoftEnglishEntry(261, "TestSans-ExtraLight"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := font.InstancePostscriptName(map[Tag]float64{axisWeight: 300}); err != nil || got != "TestSans-ExtraLight" {
+		t.Errorf("InstancePostscriptName() with a name in fvar = %q, %v, want TestSans-ExtraLight", got, err)
+	}
+}
+
+func TestLimitPostscriptName(t *testing.T) {
+	prefix := strings.Repeat("Long", 10)
+	long := limitPostscriptName(prefix, prefix+"_100wght_100wdth_100opsz")
+	if len(long) > maxPostscriptNameLength || !strings.HasPrefix(long, prefix[:20]) || !strings.HasSuffix(long, "...") {
+		t.Errorf("limitPostscriptName() = %q, want the prefix and a hash", long)
+	}
+	if other := limitPostscriptName(prefix, prefix+"_200wght_100wdth_100opsz"); other == long {
+		t.Errorf("limitPostscriptName() returned %q for different names", other)
+	}
+	if got := limitPostscriptName("Short", "Short-Bold"); got != "Short-Bold" {
+		t.Errorf("limitPostscriptName() = %q, want Short-Bold", got)
+	}
+}
@@ -0,0 +1,61 @@
+package sfnt
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is returned when parsing or subsetting a font panics, which is
+// always a bug in this package, so that a malformed font cannot crash a
+// program that processes untrusted fonts. Please report it with the font.
+type PanicError struct {
+	Tag    Tag   // Tag is the table being processed, or the zero Tag if it is not known.
+	Offset int64 // Offset is where the table starts in the font file, or -1 if it is not known.
+
+	Value interface{} // Value is the value that was passed to panic.
+	Stack []byte      // Stack is the stack trace of the goroutine that panicked.
+}
+
+// Error returns a human readable description of the problem.
+func (e *PanicError) Error() string {
+	switch {
+	case e.Tag == (Tag{}):
+		return fmt.Sprintf("internal error: %v", e.Value)
+	case e.Offset < 0:
+		return fmt.Sprintf("%q: internal error: %v", e.Tag, e.Value)
+	}
+	return fmt.Sprintf("%q (at offset %d): internal error: %v", e.Tag, e.Offset, e.Value)
+}
+
+// panicContext describes what is being processed, for the PanicError returned
+// if it panics.
+type panicContext struct {
+	tag    Tag
+	offset int64
+}
+
+// panicContext returns the context for processing the table in s.
+func (s *tableSection) panicContext() *panicContext {
+	// Tables set with SetTable, or added with AddTable, are not in the file.
+	if s.bytes != nil || s.length == 0 {
+		return &panicContext{tag: s.tag, offset: -1}
+	}
+	return &panicContext{tag: s.tag, offset: int64(s.offset)}
+}
+
+// panicContext returns the context for processing the table with the given tag.
+func (font *Font) panicContext(tag Tag) *panicContext {
+	if s, found := font.section(tag); found {
+		return s.panicContext()
+	}
+	return &panicContext{tag: tag, offset: -1}
+}
+
+// recover sets *err to a *PanicError if the function that deferred it panics.
+// It must be deferred directly, as recover only stops a panic when called by a
+// deferred function.
+func (c *panicContext) recover(err *error) {
+	if r := recover(); r != nil {
+		*err = &PanicError{Tag: c.tag, Offset: c.offset, Value: r, Stack: debug.Stack()}
+	}
+}
@@ -0,0 +1,51 @@
+package sfnt
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParsePanicError(t *testing.T) {
+	tag := MustNamedTag("Zpan")
+	font := parseTestFont(t, "Roboto-BoldItalic.ttf")
+	font.SetTable(tag, []byte("data"))
+	var buf bytes.Buffer
+	if _, err := font.WriteOTF(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	original := tableParserFor(tag)
+	defer RegisterTableParser(tag, original)
+	RegisterTableParser(tag, func(tag Tag, buf []byte) (Table, error) {
+		return nil, errors.New(string(buf[len(buf)]))
+	})
+	parsed, err := Parse(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var offset int64
+	for _, record := range parsed.Directory() {
+		if record.Tag == tag {
+			offset = int64(record.Offset)
+		}
+	}
+
+	_, err = parsed.Table(tag)
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("Table(%q) err = %v, want a *PanicError", tag, err)
+	}
+	if panicErr.Tag != tag || panicErr.Offset != offset || offset == 0 || len(panicErr.Stack) == 0 {
+		t.Errorf("Table(%q) err = %+v, want the tag and offset %d", tag, panicErr, offset)
+	}
+	if !strings.Contains(err.Error(), "index out of range") {
+		t.Errorf("Table(%q) err = %q, want the panic's message", tag, err)
+	}
+
+	// StrictParse parses the tables in other goroutines, which must not crash.
+	if _, err := StrictParse(bytes.NewReader(buf.Bytes())); err == nil || !strings.Contains(err.Error(), "internal error") {
+		t.Errorf("StrictParse() err = %v, want the panic as an error", err)
+	}
+}
@@ -0,0 +1,109 @@
+package sfnt
+
+import (
+	"sort"
+)
+
+// DefaultLanguage is the tag used in ScriptSupport for the language system
+// that is used when no specific language is requested.
+var DefaultLanguage = MustNamedTag("dflt")
+
+// ScriptSupport describes the support for one script in the GSUB and GPOS tables.
+type ScriptSupport struct {
+	Tag       Tag               // Tag for this script, for example 'latn' or 'DFLT'.
+	Name      string            // Name is the registered name of the script, or "" if the tag is not registered.
+	Languages []LanguageSupport // Languages contains the default language system first, if there is one.
+}
+
+// LanguageSupport describes the features registered for one language system.
+type LanguageSupport struct {
+	Tag  Tag    // Tag for this language, or DefaultLanguage.
+	Name string // Name is the registered name of the language, or "" if the tag is not registered.
+	GSUB []Tag  // GSUB contains the substitution features for this language.
+	GPOS []Tag  // GPOS contains the positioning features for this language.
+}
+
+// Scripts returns the scripts and language systems declared in the GSUB and GPOS
+// tables, sorted by tag, along with the features registered under each.
+func (font *Font) Scripts() ([]ScriptSupport, error) {
+	var scripts []ScriptSupport
+	index := map[Tag]int{}
+
+	for _, tag := range []Tag{TagGsub, TagGpos} {
+		if !font.HasTable(tag) {
+			continue
+		}
+		layout, err := font.TableLayout(tag)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, script := range layout.Scripts {
+			i, found := index[script.Tag]
+			if !found {
+				i = len(scripts)
+				index[script.Tag] = i
+				scripts = append(scripts, ScriptSupport{Tag: script.Tag, Name: script.String()})
+			}
+
+			langs := script.Languages
+			if script.DefaultLanguage != nil {
+				langs = append([]*LangSys{{Tag: DefaultLanguage, Features: script.DefaultLanguage.Features}}, langs...)
+			}
+			for _, lang := range langs {
+				support := scripts[i].language(lang)
+				for _, feature := range lang.Features {
+					if tag == TagGsub {
+						support.GSUB = appendTag(support.GSUB, feature.Tag)
+					} else {
+						support.GPOS = appendTag(support.GPOS, feature.Tag)
+					}
+				}
+			}
+		}
+	}
+
+	for _, script := range scripts {
+		sort.SliceStable(script.Languages, func(i, j int) bool {
+			return script.Languages[i].Tag == DefaultLanguage && script.Languages[j].Tag != DefaultLanguage
+		})
+	}
+	sort.Slice(scripts, func(i, j int) bool {
+		return scripts[i].Tag.Number < scripts[j].Tag.Number
+	})
+	return scripts, nil
+}
+
+// language returns the support for lang, adding it if it is not yet present.
+func (s *ScriptSupport) language(lang *LangSys) *LanguageSupport {
+	for i := range s.Languages {
+		if s.Languages[i].Tag == lang.Tag {
+			return &s.Languages[i]
+		}
+	}
+
+	name := "Default"
+	if lang.Tag != DefaultLanguage {
+		name = lang.String()
+	}
+	s.Languages = append(s.Languages, LanguageSupport{Tag: lang.Tag, Name: name})
+	return &s.Languages[len(s.Languages)-1]
+}
+
+// appendTag appends tag to tags if it is not already present.
+func appendTag(tags []Tag, tag Tag) []Tag {
+	if hasTag(tags, tag) {
+		return tags
+	}
+	return append(tags, tag)
+}
+
+// hasTag returns true if tag is in tags.
+func hasTag(tags []Tag, tag Tag) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
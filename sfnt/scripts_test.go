@@ -0,0 +1,37 @@
+package sfnt
+
+import (
+	"testing"
+)
+
+func TestScripts(t *testing.T) {
+	font := parseTestFont(t, "Roboto-BoldItalic.ttf")
+	scripts, err := font.Scripts()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var latin *ScriptSupport
+	for i := range scripts {
+		if i > 0 && scripts[i-1].Tag.Number >= scripts[i].Tag.Number {
+			t.Errorf("Scripts() not sorted: %q before %q", scripts[i-1].Tag, scripts[i].Tag)
+		}
+		if scripts[i].Tag == MustNamedTag("latn") {
+			latin = &scripts[i]
+		}
+	}
+	if latin == nil {
+		t.Fatalf("Scripts() = %v, want latn", scripts)
+	}
+	if latin.Name != "Latin" {
+		t.Errorf("Name = %q, want Latin", latin.Name)
+	}
+
+	dflt := latin.Languages[0]
+	if dflt.Tag != DefaultLanguage || dflt.Name != "Default" {
+		t.Errorf("Languages[0] = %q (%s), want dflt (Default)", dflt.Tag, dflt.Name)
+	}
+	if !hasTag(dflt.GSUB, MustNamedTag("liga")) || !hasTag(dflt.GPOS, MustNamedTag("kern")) {
+		t.Errorf("GSUB = %v, GPOS = %v, want liga and kern", dflt.GSUB, dflt.GPOS)
+	}
+}
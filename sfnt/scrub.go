@@ -0,0 +1,73 @@
+package sfnt
+
+// scrubbedNames are the 'name' entries that ScrubMetadata removes, as they
+// identify the people and companies who made the font.
+var scrubbedNames = []NameID{NameManufacturer, NameDesigner, NameDescription, NameVendorURL, NameDesignerURL}
+
+// tagDSIG is the tag of the digital signature table, which identifies the
+// signer and is invalidated by any change to the font.
+var tagDSIG = MustNamedTag("DSIG")
+
+// ScrubMetadata removes metadata that identifies who made the font and when, so
+// that it can be redistributed under another name. It removes the manufacturer,
+// designer, description and URL entries of the 'name' table, replaces the
+// unique identifier with the PostScript name (as the entry is required), resets
+// the creation and modification times in the 'head' table, and removes the
+// 'DSIG' table and any tables that are not defined by the specifications.
+//
+// The copyright, trademark and license entries are kept, as redistributing the
+// font without them may breach its license.
+func (font *Font) ScrubMetadata() error {
+	if font.HasTable(TagName) {
+		name, err := font.NameTable()
+		if err != nil {
+			return err
+		}
+		for _, id := range scrubbedNames {
+			name.Remove(id)
+		}
+		scrubUniqueIdentifier(name)
+	}
+
+	if font.HasTable(TagHead) {
+		head, err := font.HeadTable()
+		if err != nil {
+			return err
+		}
+		head.Created = longdatetime{}
+		head.Updated = longdatetime{}
+	}
+
+	for _, tag := range font.Tags() {
+		if tag == tagDSIG || !IsKnownTable(tag) {
+			font.RemoveTable(tag)
+		}
+	}
+	return nil
+}
+
+// scrubUniqueIdentifier replaces the value of each unique identifier entry with
+// the PostScript name for the same platform, encoding and language, or removes
+// the entry if there is no such PostScript name.
+func scrubUniqueIdentifier(name *TableName) {
+	postscript := map[nameKey][]byte{}
+	for _, entry := range name.entries {
+		if entry.NameID == NamePostscript {
+			postscript[nameKey{entry.PlatformID, entry.EncodingID, entry.LanguageID, NameUniqueIdentifier}] = entry.Value
+		}
+	}
+
+	entries := name.entries[:0]
+	for _, entry := range name.entries {
+		if entry.NameID == NameUniqueIdentifier {
+			value, found := postscript[nameKey{entry.PlatformID, entry.EncodingID, entry.LanguageID, entry.NameID}]
+			if !found {
+				continue
+			}
+			entry.Value = append([]byte(nil), value...)
+		}
+		entries = append(entries, entry)
+	}
+	name.bytes = nil
+	name.entries = entries
+}
@@ -0,0 +1,57 @@
+package sfnt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestScrubMetadata(t *testing.T) {
+	font := parseTestFont(t, "Roboto-BoldItalic.ttf")
+	name := NewTableName()
+	name.AddMicrosoftEnglishEntry(NameCopyrightNotice, "Copyright 2011 Google Inc.")
+	name.AddMicrosoftEnglishEntry(NameFontFamily, "Roboto")
+	name.AddMicrosoftEnglishEntry(NameUniqueIdentifier, "Google:Roboto Bold Italic:2016")
+	name.AddMacEnglishEntry(NameUniqueIdentifier, "Google:Roboto Bold Italic:2016")
+	name.AddMicrosoftEnglishEntry(NamePostscript, "Roboto-BoldItalic")
+	name.AddMicrosoftEnglishEntry(NameManufacturer, "Google")
+	name.AddMicrosoftEnglishEntry(NameDesigner, "Christian Robertson")
+	name.AddMicrosoftEnglishEntry(NameVendorURL, "http://www.google.com")
+	font.AddTable(TagName, name)
+	font.SetTable(MustNamedTag("FFTM"), make([]byte, 28))
+	font.SetTable(tagDSIG, make([]byte, 8))
+	head, err := font.HeadTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	head.Created = longdatetime{SecondsSince1904: 3000000000}
+	head.Updated = longdatetime{SecondsSince1904: 3000000000}
+
+	if err := font.ScrubMetadata(); err != nil {
+		t.Fatal(err)
+	}
+
+	var entries []string
+	for _, entry := range name.List() {
+		entries = append(entries, entry.Platform()+" "+entry.Label()+": "+entry.String())
+	}
+	want := []string{
+		"Microsoft Copyright Notice: Copyright 2011 Google Inc.",
+		"Microsoft Font Family: Roboto",
+		"Microsoft Unique Identifier: Roboto-BoldItalic",
+		"Microsoft PostScript Name: Roboto-BoldItalic",
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("name entries = %q, want %q", entries, want)
+	}
+	if head.Created != (longdatetime{}) || head.Updated != (longdatetime{}) {
+		t.Errorf("head timestamps = %v, %v; want zero", head.Created, head.Updated)
+	}
+	for _, tag := range []Tag{MustNamedTag("FFTM"), tagDSIG} {
+		if font.HasTable(tag) {
+			t.Errorf("HasTable(%q) = true after scrubbing", tag)
+		}
+	}
+	if !font.HasTable(TagGlyf) {
+		t.Errorf("HasTable(%q) = false after scrubbing", TagGlyf)
+	}
+}
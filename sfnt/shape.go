@@ -0,0 +1,248 @@
+package sfnt
+
+import (
+	"encoding/binary"
+)
+
+// ShapedGlyph is a glyph positioned by Shape. All values are in font units.
+type ShapedGlyph struct {
+	GlyphID uint16
+	Cluster int // Cluster is the byte offset in the text of the first character the glyph represents.
+
+	XAdvance, YAdvance int // XAdvance and YAdvance move the pen after drawing the glyph.
+	XOffset, YOffset   int // XOffset and YOffset move the glyph without moving the pen.
+}
+
+const (
+	gsubSingle    = 1
+	gsubMultiple  = 2
+	gsubAlternate = 3
+	gsubLigature  = 4
+	gsubExtension = 7
+
+	gposSingleAdjustment = 1
+)
+
+// Shape maps text to glyphs and applies the GSUB and GPOS lookups of the given
+// features, as a quick check of how a font behaves. Lookups from every script
+// and language are used, in the order of the lookup list.
+//
+// Shape is not a complete shaping engine: it applies single, multiple, alternate
+// (choosing the first alternate) and ligature substitutions, and single and pair
+// positioning. Contextual lookups, mark attachment and lookup flags are ignored,
+// and text is not reordered for right-to-left or complex scripts.
+func (font *Font) Shape(text string, features []Tag) ([]ShapedGlyph, error) {
+	cmap, err := font.CmapTable()
+	if err != nil {
+		return nil, err
+	}
+	var glyphs []ShapedGlyph
+	for i, r := range text {
+		gid, _ := cmap.Lookup(r)
+		glyphs = append(glyphs, ShapedGlyph{GlyphID: uint16(gid), Cluster: i})
+	}
+
+	if font.HasTable(TagGsub) {
+		gsub, err := font.GsubTable()
+		if err != nil {
+			return nil, err
+		}
+		for _, lookup := range gsub.featureLookups(features...) {
+			glyphs = lookup.substitute(glyphs)
+		}
+	}
+
+	hmtx, err := font.HmtxTable()
+	if err != nil {
+		return nil, err
+	}
+	for i := range glyphs {
+		glyphs[i].XAdvance = int(hmtx.Advance(glyphs[i].GlyphID))
+	}
+
+	if font.HasTable(TagGpos) {
+		gpos, err := font.GposTable()
+		if err != nil {
+			return nil, err
+		}
+		for _, lookup := range gpos.featureLookups(features...) {
+			lookup.position(glyphs)
+		}
+	}
+
+	return glyphs, nil
+}
+
+// substitute applies a GSUB lookup to each glyph in turn.
+func (l *Lookup) substitute(glyphs []ShapedGlyph) []ShapedGlyph {
+	subtables, lookupType := l.extensionSubtables(gsubExtension)
+
+	var out []ShapedGlyph
+	for i := 0; i < len(glyphs); {
+		g := glyphs[i]
+		matched := false
+		for _, sub := range subtables {
+			var gids []uint16
+			var consumed int
+			if gids, consumed, matched = substitution(lookupType, sub, glyphs[i:]); matched {
+				for _, gid := range gids {
+					out = append(out, ShapedGlyph{GlyphID: gid, Cluster: g.Cluster})
+				}
+				i += consumed
+				break
+			}
+		}
+		if !matched {
+			out = append(out, g)
+			i++
+		}
+	}
+	return out
+}
+
+// substitution returns the glyphs that replace the start of glyphs according to
+// a GSUB subtable, and the number of glyphs replaced.
+func substitution(lookupType uint16, sub []byte, glyphs []ShapedGlyph) ([]uint16, int, bool) {
+	if len(sub) < 6 {
+		return nil, 0, false
+	}
+	gid := glyphs[0].GlyphID
+	index, ok := coverageIndex(at(sub, int(binary.BigEndian.Uint16(sub[2:]))), gid)
+	if !ok {
+		return nil, 0, false
+	}
+	format := binary.BigEndian.Uint16(sub)
+
+	switch {
+	case lookupType == gsubSingle && format == 1:
+		return []uint16{gid + binary.BigEndian.Uint16(sub[4:])}, 1, true
+
+	case lookupType == gsubSingle && format == 2:
+		if substitutes := uint16Array(sub, 4); index < len(substitutes) {
+			return []uint16{substitutes[index]}, 1, true
+		}
+
+	case lookupType == gsubMultiple && format == 1, lookupType == gsubAlternate && format == 1:
+		offsets := uint16Array(sub, 4)
+		if index >= len(offsets) {
+			break
+		}
+		gids := uint16Array(sub, int(offsets[index]))
+		if lookupType == gsubAlternate && len(gids) > 1 {
+			gids = gids[:1]
+		}
+		if lookupType == gsubAlternate && len(gids) == 0 {
+			break
+		}
+		return gids, 1, true
+
+	case lookupType == gsubLigature && format == 1:
+		offsets := uint16Array(sub, 4)
+		if index >= len(offsets) {
+			break
+		}
+		set := at(sub, int(offsets[index]))
+		for _, offset := range uint16Array(set, 0) {
+			ligature := at(set, int(offset))
+			if len(ligature) < 4 {
+				continue
+			}
+			count := int(binary.BigEndian.Uint16(ligature[2:]))
+			if count == 0 || count > len(glyphs) || len(ligature) < 4+2*(count-1) {
+				continue
+			}
+			match := true
+			for j := 1; j < count; j++ {
+				if glyphs[j].GlyphID != binary.BigEndian.Uint16(ligature[4+2*(j-1):]) {
+					match = false
+					break
+				}
+			}
+			if match {
+				return []uint16{binary.BigEndian.Uint16(ligature)}, count, true
+			}
+		}
+	}
+	return nil, 0, false
+}
+
+// position applies a GPOS lookup to each glyph in turn.
+func (l *Lookup) position(glyphs []ShapedGlyph) {
+	subtables, lookupType := l.extensionSubtables(gposExtension)
+
+	for i := 0; i < len(glyphs); i++ {
+		for _, sub := range subtables {
+			if lookupType == gposSingleAdjustment {
+				if v, ok := singlePos(sub, glyphs[i].GlyphID); ok {
+					glyphs[i].adjust(v)
+					break
+				}
+			}
+			if lookupType == gposPairAdjustment && i+1 < len(glyphs) {
+				if v1, v2, _, ok := pairPos(sub, glyphs[i].GlyphID, glyphs[i+1].GlyphID); ok {
+					glyphs[i].adjust(v1)
+					glyphs[i+1].adjust(v2)
+					// As in other shapers, a second glyph that is adjusted can't start another pair.
+					if binary.BigEndian.Uint16(sub[6:]) != 0 {
+						i++
+					}
+					break
+				}
+			}
+		}
+	}
+}
+
+// singlePos returns the value record for gid in a single adjustment subtable.
+func singlePos(sub []byte, gid uint16) (valueRecord, bool) {
+	if len(sub) < 6 {
+		return valueRecord{}, false
+	}
+	index, ok := coverageIndex(at(sub, int(binary.BigEndian.Uint16(sub[2:]))), gid)
+	if !ok {
+		return valueRecord{}, false
+	}
+	format := binary.BigEndian.Uint16(sub[4:])
+	size := valueRecordSize(format)
+
+	switch binary.BigEndian.Uint16(sub) {
+	case 1:
+		if len(sub) >= 6+size {
+			return parseValueRecord(sub[6:], format), true
+		}
+	case 2:
+		if len(sub) < 8 {
+			break
+		}
+		count := int(binary.BigEndian.Uint16(sub[6:]))
+		if index < count && len(sub) >= 8+count*size {
+			return parseValueRecord(sub[8+index*size:], format), true
+		}
+	}
+	return valueRecord{}, false
+}
+
+// adjust applies the placement and advance values in v to the glyph.
+func (g *ShapedGlyph) adjust(v valueRecord) {
+	g.XOffset += int(v.XPlacement)
+	g.YOffset += int(v.YPlacement)
+	g.XAdvance += int(v.XAdvance)
+	g.YAdvance += int(v.YAdvance)
+}
+
+// uint16Array returns the array of uint16 values preceded by a uint16 count at offset in b.
+// It returns nil if the array is out of range.
+func uint16Array(b []byte, offset int) []uint16 {
+	if offset < 0 || len(b) < offset+2 {
+		return nil
+	}
+	count := int(binary.BigEndian.Uint16(b[offset:]))
+	if len(b) < offset+2+2*count {
+		return nil
+	}
+	values := make([]uint16, count)
+	for i := range values {
+		values[i] = binary.BigEndian.Uint16(b[offset+2+2*i:])
+	}
+	return values
+}
@@ -0,0 +1,104 @@
+//go:build harfbuzz
+// +build harfbuzz
+
+package sfnt
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// The tests in this file compare Shape with HarfBuzz's hb-shape, which must be
+// installed (or given by $HB_SHAPE). Run them with:
+//
+//	go test -tags harfbuzz ./sfnt
+
+// harfbuzzDefaultFeatures are the features HarfBuzz applies unless told not to.
+var harfbuzzDefaultFeatures = []string{
+	"abvm", "blwm", "calt", "ccmp", "clig", "curs", "dist", "kern", "liga", "locl", "mark", "mkmk", "rclt", "rlig",
+}
+
+// hbGlyph is a glyph in the JSON output of hb-shape.
+type hbGlyph struct {
+	GlyphID  uint16 `json:"g"`
+	Cluster  int    `json:"cl"`
+	XOffset  int    `json:"dx"`
+	YOffset  int    `json:"dy"`
+	XAdvance int    `json:"ax"`
+	YAdvance int    `json:"ay"`
+}
+
+// harfbuzzShape shapes text with hb-shape, enabling only the given features.
+func harfbuzzShape(t *testing.T, path, text string, features []string) []ShapedGlyph {
+	hbShape := os.Getenv("HB_SHAPE")
+	if hbShape == "" {
+		hbShape = "hb-shape"
+	}
+	if _, err := exec.LookPath(hbShape); err != nil {
+		t.Skipf("hb-shape not found: %s", err)
+	}
+
+	var settings []string
+	for _, f := range harfbuzzDefaultFeatures {
+		settings = append(settings, "-"+f)
+	}
+	settings = append(settings, features...)
+
+	out, err := exec.Command(hbShape, "--output-format=json", "--no-glyph-names",
+		"--direction=ltr", "--script=latn", "--features="+strings.Join(settings, ","),
+		path, text).Output()
+	if err != nil {
+		t.Fatalf("hb-shape %s %q: %s", path, text, err)
+	}
+
+	var glyphs []hbGlyph
+	if err := json.Unmarshal(out, &glyphs); err != nil {
+		t.Fatalf("reading hb-shape output %q: %s", out, err)
+	}
+	shaped := make([]ShapedGlyph, len(glyphs))
+	for i, g := range glyphs {
+		shaped[i] = ShapedGlyph{
+			GlyphID:  g.GlyphID,
+			Cluster:  g.Cluster,
+			XAdvance: g.XAdvance,
+			YAdvance: g.YAdvance,
+			XOffset:  g.XOffset,
+			YOffset:  g.YOffset,
+		}
+	}
+	return shaped
+}
+
+func TestShapeHarfBuzz(t *testing.T) {
+	// HarfBuzz can't read WOFF files, so only the uncompressed fonts are compared.
+	fonts := []string{"Roboto-BoldItalic.ttf", "Raleway-v4020-Regular.otf"}
+	texts := []string{"office", "AVATAR", "Hamburgefonstiv", "T.V. Yo, LYNX!", "1/2 ffl"}
+	features := [][]string{nil, {"liga"}, {"kern"}, {"liga", "kern"}}
+
+	for _, name := range fonts {
+		font := parseTestFont(t, name)
+		path := filepath.Join("testdata", name)
+
+		for _, text := range texts {
+			for _, f := range features {
+				var tags []Tag
+				for _, s := range f {
+					tags = append(tags, MustNamedTag(s))
+				}
+				got, err := font.Shape(text, tags)
+				if err != nil {
+					t.Fatal(err)
+				}
+				want := harfbuzzShape(t, path, text, f)
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("%s: Shape(%q, %v) = %v, hb-shape = %v", name, text, f, got, want)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package sfnt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestShape(t *testing.T) {
+	font := parseTestFont(t, "Roboto-BoldItalic.ttf")
+	liga, kern := MustNamedTag("liga"), MustNamedTag("kern")
+
+	glyphs, err := font.Shape("office", []Tag{liga})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var clusters []int
+	for _, g := range glyphs {
+		clusters = append(clusters, g.Cluster)
+	}
+	if want := []int{0, 1, 4, 5}; !reflect.DeepEqual(clusters, want) {
+		t.Errorf("Shape(office) clusters = %v, want %v", clusters, want)
+	}
+
+	unkerned, err := font.Shape("AV", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kerned, err := font.Shape("AV", []Tag{kern})
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, err := font.KerningValue(kerned[0].GlyphID, kerned[1].GlyphID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value == 0 || kerned[0].XAdvance != unkerned[0].XAdvance+int(value) {
+		t.Errorf("Shape(AV) advance = %d, want %d%+v", kerned[0].XAdvance, unkerned[0].XAdvance, value)
+	}
+}
@@ -0,0 +1,126 @@
+package sfnt
+
+import (
+	"fmt"
+)
+
+// SimplifyContours returns the contours with the points that do not change
+// their shape by more than tolerance font units removed:
+//
+//   - on-curve points on the straight line between two on-curve points,
+//     including points that duplicate their neighbour,
+//   - on-curve points midway between two off-curve points, where TrueType
+//     implies an on-curve point anyway, and
+//   - off-curve points on the line between two on-curve points, which make a
+//     curve that is drawn as a straight line.
+//
+// Every point that is removed stays within tolerance of the simplified contour,
+// so the error does not add up as neighbouring points are removed. Contours are
+// not reduced to fewer than three points.
+func SimplifyContours(contours [][]GlyphPoint, tolerance float64) [][]GlyphPoint {
+	simplified := make([][]GlyphPoint, len(contours))
+	for i, contour := range contours {
+		simplified[i] = simplifyContour(contour, tolerance)
+	}
+	return simplified
+}
+
+func simplifyContour(contour []GlyphPoint, tolerance float64) []GlyphPoint {
+	points := append([]GlyphPoint(nil), contour...)
+	// dropped[i] contains the points removed between points[i] and the point
+	// after it, which must stay within tolerance of the simplified contour.
+	dropped := make([][]GlyphPoint, len(points))
+	for changed := true; changed; {
+		changed = false
+		for i := 0; i < len(points) && len(points) > 3; i++ {
+			before := (i + len(points) - 1) % len(points)
+			span := append(append(append([]GlyphPoint(nil), dropped[before]...), points[i]), dropped[i]...)
+			if redundantPoints(points[before], span, points[(i+1)%len(points)], tolerance) {
+				dropped[before] = span
+				points = append(points[:i], points[i+1:]...)
+				dropped = append(dropped[:i], dropped[i+1:]...)
+				changed = true
+				i--
+			}
+		}
+	}
+	return points
+}
+
+// redundantPoints returns true if the contour is the same to within tolerance
+// without the points in span, which are between prev and next.
+func redundantPoints(prev GlyphPoint, span []GlyphPoint, next GlyphPoint, tolerance float64) bool {
+	a, b := vector{float64(prev.X), float64(prev.Y)}, vector{float64(next.X), float64(next.Y)}
+	switch {
+	case prev.OnCurve && next.OnCurve:
+		for _, p := range span {
+			if (vector{float64(p.X), float64(p.Y)}).segmentDistance(a, b) > tolerance {
+				return false
+			}
+		}
+		return true
+	case len(span) == 1 && span[0].OnCurve && !prev.OnCurve && !next.OnCurve:
+		v := vector{float64(span[0].X), float64(span[0].Y)}
+		return v.sub(a.midpoint(b)).length() <= tolerance
+	}
+	return false
+}
+
+// Simplify removes the redundant points from a simple glyph, as described by
+// SimplifyContours. The glyph's instructions are discarded if points are
+// removed, as they refer to points by number. It returns true if the glyph was
+// changed.
+func (table *TableGlyf) Simplify(gid uint16, tolerance float64) (bool, error) {
+	glyph, err := table.Glyph(gid)
+	if err != nil || len(glyph.Contours) == 0 {
+		return false, err
+	}
+	contours := SimplifyContours(glyph.Contours, tolerance)
+	if numPoints(contours) == glyph.NumPoints() {
+		return false, nil
+	}
+
+	glyph = &Glyph{Contours: contours}
+	glyph.UpdateBounds()
+	return true, table.SetGlyph(gid, glyph)
+}
+
+// SimplifyOutlines removes the redundant points from every glyph in the font,
+// as described by SimplifyContours, and returns the number of glyphs that were
+// changed. Simplification is for fonts that are only displayed, such as
+// webfonts, as the hinting of changed glyphs is discarded. It is not supported
+// for variable fonts, whose variations refer to points by number, or for fonts
+// with CFF outlines.
+func (font *Font) SimplifyOutlines(tolerance float64) (int, error) {
+	if font.HasTable(TagGvar) {
+		return 0, fmt.Errorf("simplifying the outlines of variable fonts is not supported")
+	}
+	glyf, err := font.GlyfTable()
+	if err == ErrMissingTable {
+		return 0, fmt.Errorf("simplifying is only supported for fonts with TrueType outlines")
+	} else if err != nil {
+		return 0, err
+	}
+
+	changed := 0
+	for i := range glyf.Glyphs {
+		simplified, err := glyf.Simplify(uint16(i), tolerance)
+		if err != nil {
+			return 0, err
+		}
+		if simplified {
+			changed++
+		}
+	}
+	font.AddTable(TagGlyf, glyf)
+	return changed, nil
+}
+
+// numPoints returns the total number of points in the contours.
+func numPoints(contours [][]GlyphPoint) int {
+	n := 0
+	for _, contour := range contours {
+		n += len(contour)
+	}
+	return n
+}
@@ -0,0 +1,80 @@
+package sfnt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSimplifyContours(t *testing.T) {
+	tests := []struct {
+		name      string
+		contour   []GlyphPoint
+		tolerance float64
+		want      []GlyphPoint
+	}{
+		{
+			name:    "collinear and duplicate points",
+			contour: []GlyphPoint{{0, 0, true}, {0, 100, true}, {0, 200, true}, {200, 200, true}, {200, 200, true}, {200, 0, true}},
+			want:    []GlyphPoint{{0, 0, true}, {0, 200, true}, {200, 200, true}, {200, 0, true}},
+		},
+		{
+			name:    "implied on-curve point",
+			contour: []GlyphPoint{{0, 0, true}, {0, 100, false}, {50, 150, true}, {100, 200, false}, {200, 200, true}, {200, 0, true}},
+			want:    []GlyphPoint{{0, 0, true}, {0, 100, false}, {100, 200, false}, {200, 200, true}, {200, 0, true}},
+		},
+		{
+			name:    "straight curve",
+			contour: []GlyphPoint{{0, 0, true}, {0, 101, false}, {0, 200, true}, {200, 200, true}, {200, 0, true}},
+			want:    []GlyphPoint{{0, 0, true}, {0, 200, true}, {200, 200, true}, {200, 0, true}},
+		},
+		{
+			name:    "curve",
+			contour: []GlyphPoint{{0, 0, true}, {-1, 100, false}, {0, 200, true}, {200, 200, true}, {200, 0, true}},
+			want:    []GlyphPoint{{0, 0, true}, {-1, 100, false}, {0, 200, true}, {200, 200, true}, {200, 0, true}},
+		},
+		{
+			name:      "within tolerance",
+			contour:   []GlyphPoint{{0, 0, true}, {1, 101, false}, {0, 200, true}, {200, 200, true}, {200, 0, true}},
+			tolerance: 1,
+			want:      []GlyphPoint{{0, 0, true}, {0, 200, true}, {200, 200, true}, {200, 0, true}},
+		},
+		{
+			// Removing (25,4) and then (50,3) would leave (25,4) 4 units
+			// from the contour, so only (25,4) is removed.
+			name:      "accumulated error",
+			contour:   []GlyphPoint{{0, 0, true}, {25, 4, true}, {50, 3, true}, {100, 0, true}, {100, -50, true}, {0, -50, true}},
+			tolerance: 3,
+			want:      []GlyphPoint{{0, 0, true}, {50, 3, true}, {100, 0, true}, {100, -50, true}, {0, -50, true}},
+		},
+		{
+			name:    "triangle",
+			contour: []GlyphPoint{{0, 0, true}, {50, 0, true}, {100, 0, true}},
+			want:    []GlyphPoint{{0, 0, true}, {50, 0, true}, {100, 0, true}},
+		},
+	}
+	for _, test := range tests {
+		got := SimplifyContours([][]GlyphPoint{test.contour}, test.tolerance)
+		if !reflect.DeepEqual(got, [][]GlyphPoint{test.want}) {
+			t.Errorf("SimplifyContours(%s) = %v, want %v", test.name, got, [][]GlyphPoint{test.want})
+		}
+	}
+}
+
+func TestSimplifyOutlines(t *testing.T) {
+	font := parseTestFont(t, "Roboto-BoldItalic.ttf")
+	original := parseTestFont(t, "Roboto-BoldItalic.ttf")
+	changed, err := font.SimplifyOutlines(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed == 0 {
+		t.Errorf("SimplifyOutlines() changed no glyphs")
+	}
+	differences, err := CompareOutlines(original, font, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(differences) > 0 {
+		t.Errorf("SimplifyOutlines() changed %d outlines, for example glyph %d by %v", len(differences), differences[0].GlyphID, differences[0].Distance)
+	}
+}
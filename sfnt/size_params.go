@@ -0,0 +1,68 @@
+package sfnt
+
+import "encoding/binary"
+
+var featureSize = MustNamedTag("size")
+
+// SizeParams are the parameters of the 'size' feature in GPOS, which give the
+// optical size of the font, and group the fonts of a family that differ only in
+// optical size.
+// https://docs.microsoft.com/en-us/typography/opentype/spec/features_pt#tag-size
+type SizeParams struct {
+	DesignSize float64 // DesignSize is the point size the font was designed for.
+
+	// SubfamilyID is the same for each font in a group of optical sizes, or 0
+	// if the font does not belong to one. SubfamilyNameID is the entry in the
+	// 'name' table containing the name of the group in menus, such as "Display".
+	SubfamilyID     uint16
+	SubfamilyNameID NameID
+
+	// RangeStart and RangeEnd are the point sizes the font is intended for. The
+	// range excludes RangeStart and includes RangeEnd, and both are 0 if the
+	// font does not belong to a group.
+	RangeStart, RangeEnd float64
+}
+
+// SizeParams returns the parameters of the 'size' feature, or false if the
+// layout does not have the feature or its parameters are invalid.
+func (t *TableLayout) SizeParams() (SizeParams, bool) {
+	for _, f := range t.Features {
+		if f.Tag != featureSize {
+			continue
+		}
+		// Fonts made before the specification was clarified measure the offset
+		// from the feature list, so both offsets are tried.
+		if params, ok := parseSizeParams(f.params); ok {
+			return params, true
+		}
+		if params, ok := parseSizeParams(f.listParams); ok {
+			return params, true
+		}
+	}
+	return SizeParams{}, false
+}
+
+// parseSizeParams parses the parameters of the 'size' feature, and returns false
+// if they are not consistent.
+func parseSizeParams(b []byte) (SizeParams, bool) {
+	if len(b) < 10 {
+		return SizeParams{}, false
+	}
+	// Sizes are in tenths of a point.
+	params := SizeParams{
+		DesignSize:      float64(binary.BigEndian.Uint16(b)) / 10,
+		SubfamilyID:     binary.BigEndian.Uint16(b[2:]),
+		SubfamilyNameID: NameID(binary.BigEndian.Uint16(b[4:])),
+		RangeStart:      float64(binary.BigEndian.Uint16(b[6:])) / 10,
+		RangeEnd:        float64(binary.BigEndian.Uint16(b[8:])) / 10,
+	}
+
+	if params.DesignSize == 0 {
+		return params, false
+	}
+	if params.SubfamilyID == 0 {
+		return params, params.SubfamilyNameID == 0 && params.RangeStart == 0 && params.RangeEnd == 0
+	}
+	return params, params.RangeStart <= params.DesignSize && params.DesignSize <= params.RangeEnd &&
+		params.SubfamilyNameID >= 256 && params.SubfamilyNameID <= 32767
+}
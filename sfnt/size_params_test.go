@@ -0,0 +1,57 @@
+package sfnt
+
+import "testing"
+
+// sizeTestLayout returns a GPOS table with a 'size' feature, where the feature
+// parameters are at the given offset from the feature table.
+func sizeTestLayout(t *testing.T, paramsOffset uint16, params ...uint16) *TableLayout {
+	buf := writeBigEndian(t,
+		uint16(1), uint16(0), uint16(10), uint16(12), uint16(34), // header
+		uint16(0),                                         // script list
+		uint16(1), MustNamedTag("size").Number, uint16(8), // feature list
+		paramsOffset, uint16(0), // feature table
+	)
+	buf = append(buf, writeBigEndian(t, params)...)
+	buf = append(buf, writeBigEndian(t, uint16(0))...) // lookup list
+	table, err := parseTableLayout(TagGpos, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return table.(*TableLayout)
+}
+
+func TestSizeParams(t *testing.T) {
+	tests := []struct {
+		offset uint16
+		params []uint16
+		want   SizeParams
+		found  bool
+	}{
+		{4, []uint16{100, 0, 0, 0, 0}, SizeParams{DesignSize: 10}, true},
+		{4, []uint16{120, 3, 256, 95, 139}, SizeParams{12, 3, 256, 9.5, 13.9}, true},
+		// Offset from the feature list, as written by old versions of the Adobe tools.
+		{12, []uint16{120, 3, 256, 95, 139}, SizeParams{12, 3, 256, 9.5, 13.9}, true},
+		{4, []uint16{0, 0, 0, 0, 0}, SizeParams{}, false},
+		{4, []uint16{100, 0, 256, 0, 0}, SizeParams{}, false},
+		{4, []uint16{200, 3, 256, 95, 139}, SizeParams{}, false},
+		{4, []uint16{120, 3, 12, 95, 139}, SizeParams{}, false},
+	}
+	for _, test := range tests {
+		got, found := sizeTestLayout(t, test.offset, test.params...).SizeParams()
+		if found != test.found || (found && got != test.want) {
+			t.Errorf("SizeParams() with %v at %d = %+v, %v, want %+v, %v", test.params, test.offset, got, found, test.want, test.found)
+		}
+	}
+}
+
+func TestOpticalSizeFromSizeFeature(t *testing.T) {
+	font, err := NewBuilder(1000).Font()
+	if err != nil {
+		t.Fatal(err)
+	}
+	font.AddTable(TagGpos, sizeTestLayout(t, 4, 120, 3, 256, 95, 139))
+	got, found, err := font.OpticalSize()
+	if err != nil || !found || got != (OpticalSize{Design: 12, Min: 9.5, Max: 13.9}) {
+		t.Errorf("OpticalSize() = %+v, %v, %v, want 12 in 9.5 to 13.9", got, found, err)
+	}
+}
@@ -0,0 +1,209 @@
+package sfnt
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Format is the kind of file that a font is stored in.
+type Format int
+
+const (
+	// FormatUnknown is used for files that are not recognized as fonts.
+	FormatUnknown Format = iota
+	// FormatTrueType is an SFNT font with TrueType outlines (.ttf).
+	FormatTrueType
+	// FormatOpenType is an SFNT font with PostScript outlines (.otf).
+	FormatOpenType
+	// FormatCollection is a TrueType or OpenType collection (.ttc), which
+	// contains several fonts that share tables.
+	FormatCollection
+	// FormatWOFF is a WOFF 1.0 web font (.woff).
+	FormatWOFF
+	// FormatWOFF2 is a WOFF 2.0 web font (.woff2).
+	FormatWOFF2
+	// FormatEOT is an Embedded OpenType font (.eot).
+	FormatEOT
+	// FormatType1 is a PostScript Type 1 font (.pfb or .pfa), which can be
+	// converted with ConvertType1.
+	FormatType1
+	// FormatDfont is a Macintosh font suitcase (.dfont), whose fonts can be
+	// read with ParseDfont.
+	FormatDfont
+)
+
+// String returns the name of the format.
+func (f Format) String() string {
+	switch f {
+	case FormatUnknown:
+		return "unknown"
+	case FormatTrueType:
+		return "TrueType"
+	case FormatOpenType:
+		return "OpenType"
+	case FormatCollection:
+		return "font collection"
+	case FormatWOFF:
+		return "WOFF"
+	case FormatWOFF2:
+		return "WOFF2"
+	case FormatEOT:
+		return "EOT"
+	case FormatType1:
+		return "Type 1"
+	case FormatDfont:
+		return "dfont"
+	default:
+		return fmt.Sprintf("format %d", int(f))
+	}
+}
+
+// MIMEType returns the media type for files in the format, which is
+// "application/octet-stream" for unknown formats.
+func (f Format) MIMEType() string {
+	switch f {
+	case FormatTrueType:
+		return "font/ttf"
+	case FormatOpenType:
+		return "font/otf"
+	case FormatCollection:
+		return "font/collection"
+	case FormatWOFF:
+		return "font/woff"
+	case FormatWOFF2:
+		return "font/woff2"
+	case FormatEOT:
+		return "application/vnd.ms-fontobject"
+	case FormatType1:
+		return "application/x-font-type1"
+	case FormatDfont:
+		return "application/x-dfont"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// Extension returns the recommended file extension for the format, including
+// the leading dot, or "" for unknown formats.
+func (f Format) Extension() string {
+	switch f {
+	case FormatTrueType:
+		return ".ttf"
+	case FormatOpenType:
+		return ".otf"
+	case FormatCollection:
+		return ".ttc"
+	case FormatWOFF:
+		return ".woff"
+	case FormatWOFF2:
+		return ".woff2"
+	case FormatEOT:
+		return ".eot"
+	case FormatType1:
+		return ".pfb"
+	case FormatDfont:
+		return ".dfont"
+	default:
+		return ""
+	}
+}
+
+// FormatFromExtension returns the format of files with the extension, which
+// may be given with or without the leading dot and in any case (e.g. ".TTF" or
+// "woff2"). It returns FormatUnknown if the extension is not used for fonts.
+func FormatFromExtension(ext string) Format {
+	switch strings.ToLower(strings.TrimPrefix(ext, ".")) {
+	case "ttf":
+		return FormatTrueType
+	case "otf":
+		return FormatOpenType
+	case "ttc", "otc":
+		return FormatCollection
+	case "woff":
+		return FormatWOFF
+	case "woff2":
+		return FormatWOFF2
+	case "eot":
+		return FormatEOT
+	case "pfb", "pfa":
+		return FormatType1
+	case "dfont":
+		return FormatDfont
+	default:
+		return FormatUnknown
+	}
+}
+
+// Format returns the format that WriteOTF writes the font in, which is
+// FormatOpenType for fonts with CFF outlines and FormatTrueType for the rest.
+func (font *Font) Format() Format {
+	if font.Type() == TypeOpenType {
+		return FormatOpenType
+	}
+	return FormatTrueType
+}
+
+// signatureCollection is the first four bytes of a font collection.
+var signatureCollection = MustNamedTag("ttcf")
+
+// sniffLength is the number of bytes that Sniff reads, which is enough for the
+// magic number in the header of an EOT file.
+const sniffLength = 36
+
+// eotMagicNumber is stored at offset 34 in an EOT file.
+const eotMagicNumber = 0x504C
+
+// Sniff identifies the format of a font file from the magic number at its
+// start, without parsing it. It returns FormatUnknown if the file is not a
+// recognized font format, and only returns an error if reading the file fails.
+func Sniff(r io.ReaderAt) (Format, error) {
+	start := make([]byte, sniffLength)
+	n, err := r.ReadAt(start, 0)
+	if err != nil && err != io.EOF {
+		return FormatUnknown, err
+	}
+	return sniff(start[:n]), nil
+}
+
+func sniff(start []byte) Format {
+	switch {
+	case isEOT(start):
+		return FormatEOT
+	case IsType1(start):
+		return FormatType1
+	case len(start) < 4:
+		return FormatUnknown
+	}
+
+	switch NewTag(start) {
+	case TypeTrueType, TypeAppleTrueType:
+		return FormatTrueType
+	case TypeOpenType, TypePostScript1:
+		return FormatOpenType
+	case signatureCollection:
+		return FormatCollection
+	case SignatureWOFF:
+		return FormatWOFF
+	case SignatureWOFF2:
+		return FormatWOFF2
+	}
+	if IsDfont(start) {
+		return FormatDfont
+	}
+	return FormatUnknown
+}
+
+// isEOT returns true if start is the header of an EOT file, which begins with
+// its length, so the version and magic number are checked instead.
+func isEOT(start []byte) bool {
+	if len(start) < sniffLength || binary.LittleEndian.Uint16(start[34:]) != eotMagicNumber {
+		return false
+	}
+	switch binary.LittleEndian.Uint32(start[8:]) {
+	case 0x00010000, 0x00020001, 0x00020002:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package sfnt
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSniff(t *testing.T) {
+	for filename, want := range map[string]Format{
+		"Roboto-BoldItalic.ttf":            FormatTrueType,
+		"Raleway-v4020-Regular.otf":        FormatOpenType,
+		"open-sans-v15-latin-regular.woff": FormatWOFF,
+		"Go-Regular.woff2":                 FormatWOFF2,
+	} {
+		file, err := os.Open(filepath.Join("testdata", filename))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer file.Close()
+		if got, err := Sniff(file); got != want || err != nil {
+			t.Errorf("Sniff(%q) = %s, %v; want %s", filename, got, err, want)
+		}
+	}
+
+	eot := make([]byte, 100)
+	copy(eot, []byte{100, 0, 0, 0, 0, 0, 0, 0, 0x01, 0x00, 0x02, 0x00})
+	copy(eot[34:], []byte{0x4C, 0x50})
+	for name, test := range map[string]struct {
+		data []byte
+		want Format
+	}{
+		"collection": {[]byte("ttcf\x00\x01\x00\x00"), FormatCollection},
+		"apple":      {[]byte("true\x00\x01"), FormatTrueType},
+		"eot":        {eot, FormatEOT},
+		"pfb":        {testPFB(), FormatType1},
+		"pfa":        {testPFA(), FormatType1},
+		"dfont":      {testDfont(t, nil, "Roboto-BoldItalic.ttf"), FormatDfont},
+		"short":      {[]byte("OT"), FormatUnknown},
+		"empty":      {nil, FormatUnknown},
+		"text":       {[]byte("hello, world"), FormatUnknown},
+	} {
+		if got, err := Sniff(bytes.NewReader(test.data)); got != test.want || err != nil {
+			t.Errorf("Sniff(%s) = %s, %v; want %s", name, got, err, test.want)
+		}
+	}
+}
+
+func TestFormatExtension(t *testing.T) {
+	for f := FormatTrueType; f <= FormatDfont; f++ {
+		if got := FormatFromExtension(f.Extension()); got != f {
+			t.Errorf("FormatFromExtension(%q) = %s, want %s", f.Extension(), got, f)
+		}
+		if f.MIMEType() == FormatUnknown.MIMEType() {
+			t.Errorf("%s.MIMEType() = %q, want a font type", f, f.MIMEType())
+		}
+	}
+	for ext, want := range map[string]Format{"WOFF2": FormatWOFF2, ".PFA": FormatType1, "otc": FormatCollection, ".svg": FormatUnknown, "": FormatUnknown} {
+		if got := FormatFromExtension(ext); got != want {
+			t.Errorf("FormatFromExtension(%q) = %s, want %s", ext, got, want)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package sfnt
+
+import (
+	"context"
+	"fmt"
+)
+
+// GlyphIDs returns the glyph id of each of the named glyphs, using the names in
+// the 'post' table.
+func (font *Font) GlyphIDs(names []string) ([]uint16, error) {
+	post, err := font.PostTable()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[string]uint16, len(post.GlyphNames()))
+	for i, name := range post.GlyphNames() {
+		if _, found := ids[name]; !found {
+			ids[name] = uint16(i)
+		}
+	}
+
+	gids := make([]uint16, 0, len(names))
+	for _, name := range names {
+		gid, found := ids[name]
+		if !found {
+			return nil, fmt.Errorf("no glyph named %q", name)
+		}
+		gids = append(gids, gid)
+	}
+	return gids, nil
+}
+
+// SubsetGlyphs removes the outlines of every glyph that is not in gids, or used as a
+// component of a glyph in gids. The .notdef glyph (glyph 0) is always kept.
+//
+// Glyph ids are not changed, so the other tables in the font remain valid and
+// removed glyphs are left empty. The 'cmap' table still maps every character, so
+// that removed glyphs can be added back by a GlyphPatch, and is rebuilt with a
+// format 4 subtable (for older rasterizers) and a format 12 subtable, keeping any
+// variation sequences.
+// Only fonts with TrueType outlines are supported. If subsetting panics, as it
+// may on a malformed font, a *PanicError is returned instead.
+func (font *Font) SubsetGlyphs(gids []uint16) error {
+	return font.SubsetGlyphsContext(context.Background(), gids)
+}
+
+// SubsetGlyphsContext is like SubsetGlyphs, but returns ctx.Err() without modifying
+// the font if ctx is cancelled before the glyphs to keep have been found.
+func (font *Font) SubsetGlyphsContext(ctx context.Context, gids []uint16) (err error) {
+	pc := font.panicContext(TagGlyf)
+	defer pc.recover(&err)
+
+	glyf, err := font.GlyfTable()
+	if err == ErrMissingTable {
+		return fmt.Errorf("subsetting is only supported for fonts with TrueType outlines")
+	} else if err != nil {
+		return err
+	}
+
+	keep, err := glyf.closure(ctx, gids)
+	if err != nil {
+		return err
+	}
+
+	var cmap *TableCmap
+	if font.HasTable(TagCmap) {
+		if cmap, err = font.CmapTable(); err != nil {
+			return err
+		}
+	}
+
+	for i := range glyf.Glyphs {
+		if !keep[uint16(i)] {
+			glyf.Glyphs[i] = nil
+		}
+	}
+
+	*pc = *font.panicContext(TagCmap)
+	if cmap != nil && cmap.Unicode() != nil {
+		font.AddTable(TagCmap, cmap.Rebuild(cmap.Unicode().Mapping))
+	}
+	return nil
+}
+
+// closure returns the glyphs in gids, the glyphs used as their components, and
+// the .notdef glyph.
+func (table *TableGlyf) closure(ctx context.Context, gids []uint16) (map[uint16]bool, error) {
+	keep := make(map[uint16]bool, len(gids)+1)
+	queue := append([]uint16{0}, gids...)
+	for n := 0; len(queue) > 0; n++ {
+		if n%1024 == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+		gid := queue[0]
+		queue = queue[1:]
+		if keep[gid] {
+			continue
+		}
+		if int(gid) >= table.NumGlyphs() {
+			return nil, fmt.Errorf("glyph %d is out of range (font has %d glyphs)", gid, table.NumGlyphs())
+		}
+		keep[gid] = true
+
+		components, err := table.Components(gid)
+		if err != nil {
+			return nil, err
+		}
+		queue = append(queue, components...)
+	}
+	return keep, nil
+}
+
+// GlyphsForRunes returns the glyph ids that the font's Unicode 'cmap' subtable maps
+// the runes to. Runes that the font does not support are ignored.
+func (font *Font) GlyphsForRunes(runes []rune) ([]uint16, error) {
+	cmap, err := font.CmapTable()
+	if err != nil {
+		return nil, err
+	}
+
+	gids := make([]uint16, 0, len(runes))
+	for _, r := range runes {
+		if gid, found := cmap.Lookup(r); found {
+			gids = append(gids, gid)
+		}
+	}
+	return gids, nil
+}
@@ -0,0 +1,120 @@
+package sfnt
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSubsetGlyphs(t *testing.T) {
+	file, err := os.Open(filepath.Join("testdata", "open-sans-v15-latin-regular.woff"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	font, err := Parse(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	glyf, err := font.GlyfTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var composite uint16
+	for i := range glyf.Glyphs {
+		if glyf.IsComposite(uint16(i)) {
+			composite = uint16(i)
+			break
+		}
+	}
+	components, err := glyf.Components(composite)
+	if err != nil || len(components) == 0 {
+		t.Fatalf("Components(%d) = %v, %v; want components", composite, components, err)
+	}
+
+	gids, err := font.GlyphIDs([]string{"A"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := font.GlyphIDs([]string{"no-such-glyph"}); err == nil {
+		t.Errorf("GlyphIDs(no-such-glyph) err = nil, want error")
+	}
+
+	want := map[uint16][]byte{0: glyf.Glyphs[0], gids[0]: glyf.Glyphs[gids[0]], composite: glyf.Glyphs[composite]}
+	for _, gid := range components {
+		want[gid] = glyf.Glyphs[gid]
+	}
+
+	if err := font.SubsetGlyphs(append(gids, composite)); err != nil {
+		t.Fatalf("SubsetGlyphs() err = %q, want nil", err)
+	}
+
+	var buf bytes.Buffer
+	if _, err := font.WriteOTF(&buf); err != nil {
+		t.Fatal(err)
+	}
+	subset, err := StrictParse(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	glyf, err = subset.GlyfTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, glyph := range glyf.Glyphs {
+		// Glyphs may gain padding when they are written out.
+		expected := want[uint16(i)]
+		if !bytes.HasPrefix(glyph, expected) || len(glyph)-len(expected) > 3 {
+			t.Errorf("glyph %d = %d bytes, want %d bytes", i, len(glyph), len(expected))
+		}
+	}
+
+	cmap, err := subset.CmapTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gid, _ := cmap.Lookup('A'); gid != gids[0] {
+		t.Errorf("Lookup('A') = %d, want %d", gid, gids[0])
+	}
+	// Removed glyphs stay mapped, so that they can be added back by a patch.
+	if gid, found := cmap.Lookup('B'); !found || len(glyf.Glyphs[gid]) != 0 {
+		t.Errorf("Lookup('B') = %d, %t; want the removed glyph", gid, found)
+	}
+	if format := cmap.Unicode().Format; format != 4 {
+		t.Errorf("Unicode().Format = %d, want 4", format)
+	}
+}
+
+func TestSubsetGlyphsContextCancelled(t *testing.T) {
+	font := parseTestFont(t, "open-sans-v15-latin-regular.woff")
+	glyf, err := font.GlyfTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	empty := func() int {
+		n := 0
+		for _, glyph := range glyf.Glyphs {
+			if len(glyph) == 0 {
+				n++
+			}
+		}
+		return n
+	}
+	before := empty()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := font.SubsetGlyphsContext(ctx, []uint16{1}); err != context.Canceled {
+		t.Fatalf("SubsetGlyphsContext(cancelled) = %v, want %v", err, context.Canceled)
+	}
+	if after := empty(); after != before {
+		t.Errorf("SubsetGlyphsContext(cancelled) emptied %d glyphs, want 0", after-before)
+	}
+}
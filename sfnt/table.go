@@ -3,15 +3,38 @@ package sfnt
 import (
 	"compress/zlib"
 	"io"
+	"sync"
+	"time"
 )
 
-var parsers = map[Tag]tableParser{
+var parsersMu sync.RWMutex
+
+var parsers = map[Tag]TableParser{
 	TagHead: parseTableHead,
+	TagBhed: parseTableHead,
+	TagCmap: parseTableCmap,
 	TagName: parseTableName,
 	TagHhea: parseTableHhea,
+	TagMaxp: parseTableMaxp,
 	TagOS2:  parseTableOS2,
+	TagPost: parseTablePost,
 	TagGpos: parseTableLayout,
 	TagGsub: parseTableLayout,
+	TagBase: parseTableBase,
+	TagJstf: parseTableJstf,
+	TagSilf: parseTableSilf,
+	TagGlat: parseTableGlat,
+	TagGloc: parseTableGloc,
+	TagFeat: parseTableFeat,
+	TagFvar: parseTableFvar,
+	TagAvar: parseTableAvar,
+	TagGvar: parseTableGvar,
+	TagStat: parseTableStat,
+	TagKern: parseTableKern,
+	TagKerx: parseTableKerx,
+	TagTrak: parseTableTrak,
+	TagGasp: parseTableGasp,
+	TagCFF:  parseTableCFF,
 }
 
 // Table is an interface for each section of the font file.
@@ -27,19 +50,78 @@ func (b baseTable) Name() string {
 	return tableTags[Tag(b).String()]
 }
 
+// IsKnownTable returns true if the tag is for a table defined by the OpenType
+// or TrueType specifications (whether or not this package can parse it).
+func IsKnownTable(tag Tag) bool {
+	_, found := tableTags[tag.String()]
+	return found
+}
+
 type unparsedTable struct {
 	baseTable
 
 	bytes []byte // Uncompress content of this table.
 }
 
-type tableParser func(tag Tag, buffer []byte) (Table, error)
+// TableParser parses the uncompressed content of the table identified by tag.
+type TableParser func(tag Tag, buffer []byte) (Table, error)
+
+// RegisterTableParser registers the parser used for tables with the given tag,
+// replacing any existing parser. This allows proprietary or otherwise unsupported
+// tables to be parsed without modifying this package. It is usually called
+// from an init function, before any fonts are parsed.
+func RegisterTableParser(tag Tag, parser TableParser) {
+	parsersMu.Lock()
+	defer parsersMu.Unlock()
+	parsers[tag] = parser
+}
+
+// tableParserFor returns the registered parser for tag, or a parser that
+// leaves the table unparsed if there is none.
+func tableParserFor(tag Tag) TableParser {
+	parsersMu.RLock()
+	defer parsersMu.RUnlock()
+	if parser, found := parsers[tag]; found {
+		return parser
+	}
+	return newUnparsedTable
+}
 
 func newUnparsedTable(tag Tag, buffer []byte) (Table, error) {
 	return &unparsedTable{baseTable(tag), buffer}, nil
 }
 
 func (font *Font) parseTable(s *tableSection) (Table, error) {
+	return font.parseTableWith(s, tableParserFor(s.tag))
+}
+
+// parseTableWith parses the table in s with parser, which is used for tables
+// such as 'glyf' that need the content of other tables to be parsed. The
+// caller must hold s.mu.
+func (font *Font) parseTableWith(s *tableSection, parser TableParser) (t Table, err error) {
+	var buf []byte
+	if i := currentInstrumentation(); i != nil {
+		start := time.Now()
+		defer func() {
+			i.TableParsed(TableEvent{Tag: s.tag, Size: len(buf), Duration: time.Since(start), Err: err})
+		}()
+	}
+	defer s.panicContext().recover(&err)
+
+	buf, err = font.sectionData(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return parser(s.tag, buf)
+}
+
+// readTable reads the uncompressed content of the table from the font file.
+func (font *Font) readTable(s *tableSection) ([]byte, error) {
+	if s.bytes != nil {
+		return s.bytes, nil
+	}
+
 	var buf []byte
 
 	if s.length != 0 && s.length < s.zLength {
@@ -61,10 +143,5 @@ func (font *Font) parseTable(s *tableSection) (Table, error) {
 		}
 	}
 
-	parser, found := parsers[s.tag]
-	if !found {
-		parser = newUnparsedTable
-	}
-
-	return parser(s.tag, buf)
+	return buf, nil
 }
@@ -0,0 +1,92 @@
+package sfnt
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// TableAvar represents the 'avar' table, which modifies the normalized
+// coordinates of each axis of a variable font with a piecewise linear mapping.
+// https://docs.microsoft.com/en-us/typography/opentype/spec/avar
+type TableAvar struct {
+	baseTable
+
+	// Segments contains the mapping for each axis, in the order of the axes in
+	// 'fvar', sorted by From. A mapping must map -1, 0 and 1 to themselves;
+	// an empty mapping leaves coordinates unchanged.
+	Segments [][]AxisValueMap
+}
+
+// AxisValueMap maps one normalized coordinate to another.
+type AxisValueMap struct {
+	From, To float64
+}
+
+func parseTableAvar(tag Tag, buf []byte) (Table, error) {
+	if len(buf) < 8 {
+		return nil, fmt.Errorf("reading avar header: unexpected EOF")
+	}
+	if version := binary.BigEndian.Uint16(buf); version != 1 {
+		return nil, fmt.Errorf("unsupported avar version %d", version)
+	}
+	axisCount := int(binary.BigEndian.Uint16(buf[6:]))
+
+	table := &TableAvar{baseTable: baseTable(tag)}
+	b := buf[8:]
+	for i := 0; i < axisCount; i++ {
+		if len(b) < 2 {
+			return nil, fmt.Errorf("reading avar axis %d: unexpected EOF", i)
+		}
+		count := int(binary.BigEndian.Uint16(b))
+		if len(b) < 2+4*count {
+			return nil, fmt.Errorf("reading avar axis %d: unexpected EOF", i)
+		}
+		segment := make([]AxisValueMap, count)
+		for j := range segment {
+			segment[j] = AxisValueMap{
+				From: f2dot14(int16(binary.BigEndian.Uint16(b[2+4*j:]))),
+				To:   f2dot14(int16(binary.BigEndian.Uint16(b[4+4*j:]))),
+			}
+		}
+		table.Segments = append(table.Segments, segment)
+		b = b[2+4*count:]
+	}
+	return table, nil
+}
+
+// Bytes returns the byte representation of this table.
+func (table *TableAvar) Bytes() []byte {
+	buf := appendUint16s(nil, 1, 0, 0, uint16(len(table.Segments)))
+	for _, segment := range table.Segments {
+		buf = appendUint16s(buf, uint16(len(segment)))
+		for _, m := range segment {
+			buf = appendUint16s(buf, uint16(toF2dot14(m.From)), uint16(toF2dot14(m.To)))
+		}
+	}
+	return buf
+}
+
+// Map returns the normalized coordinate v on the given axis after applying the mapping.
+func (table *TableAvar) Map(axis int, v float64) float64 {
+	if axis >= len(table.Segments) {
+		return v
+	}
+	segment := table.Segments[axis]
+	if len(segment) == 0 {
+		return v
+	}
+	if v <= segment[0].From {
+		return v - segment[0].From + segment[0].To
+	}
+	for i := 1; i < len(segment); i++ {
+		if v <= segment[i].From {
+			prev, next := segment[i-1], segment[i]
+			if next.From == prev.From {
+				return next.To
+			}
+			return prev.To + (next.To-prev.To)*(v-prev.From)/(next.From-prev.From)
+		}
+	}
+	last := segment[len(segment)-1]
+	return v - last.From + last.To
+}
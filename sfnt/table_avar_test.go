@@ -0,0 +1,40 @@
+package sfnt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAvar(t *testing.T) {
+	want := &TableAvar{
+		baseTable: baseTable(TagAvar),
+		Segments: [][]AxisValueMap{
+			{{-1, -1}, {0, 0}, {0.5, 0.75}, {1, 1}},
+			{},
+		},
+	}
+
+	got, err := parseTableAvar(TagAvar, want.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseTableAvar(Bytes()) = %+v, want %+v", got, want)
+	}
+
+	for _, test := range []struct {
+		axis    int
+		v, want float64
+	}{
+		{0, -0.5, -0.5},
+		{0, 0.25, 0.375},
+		{0, 0.75, 0.875},
+		{0, 1, 1},
+		{1, 0.25, 0.25},
+		{2, 0.25, 0.25},
+	} {
+		if got := want.Map(test.axis, test.v); got != test.want {
+			t.Errorf("Map(%d, %v) = %v, want %v", test.axis, test.v, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package sfnt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+var (
+	// BaselineRoman is the baseline used by most alphabetic scripts (Latin, Cyrillic, Greek).
+	BaselineRoman = MustNamedTag("romn")
+	// BaselineIdeographic is the ideographic em-box bottom edge baseline used by CJK scripts.
+	BaselineIdeographic = MustNamedTag("ideo")
+	// BaselineHanging is the hanging baseline used by Tibetan, Devanagari and similar scripts.
+	BaselineHanging = MustNamedTag("hang")
+	// BaselineMath is the math characters baseline.
+	BaselineMath = MustNamedTag("math")
+)
+
+// TableBase represents the OpenType 'BASE' table. This contains the baseline
+// positions each script expects, so that text in mixed scripts can be aligned.
+//
+// See https://docs.microsoft.com/en-us/typography/opentype/spec/base
+type TableBase struct {
+	baseTable
+
+	bytes []byte
+
+	Horizontal *BaseAxis // Horizontal contains the baselines for horizontal text (may be nil).
+	Vertical   *BaseAxis // Vertical contains the baselines for vertical text (may be nil).
+}
+
+// Bytes returns the bytes for this table. The TableBase is read only, so
+// the bytes will always be the same as what is read in.
+func (t *TableBase) Bytes() []byte {
+	return t.bytes
+}
+
+// BaseAxis contains the baseline information for one layout direction.
+type BaseAxis struct {
+	Baselines []Tag         // Baselines lists the baseline tags defined for this axis.
+	Scripts   []*BaseScript // Scripts contains the baseline values for each script.
+}
+
+// Script returns the baseline values for the given script tag, or nil
+// if the script is not present on this axis.
+func (a *BaseAxis) Script(tag Tag) *BaseScript {
+	for _, s := range a.Scripts {
+		if s.Tag == tag {
+			return s
+		}
+	}
+	return nil
+}
+
+// BaseScript contains the baseline values for a single script.
+type BaseScript struct {
+	Tag             Tag           // Tag for this script.
+	DefaultBaseline Tag           // DefaultBaseline is the baseline this script is aligned on.
+	Coordinates     map[Tag]int16 // Coordinates of each baseline in design units.
+}
+
+// String returns the name for this script.
+func (s *BaseScript) String() string {
+	return scriptTags[s.Tag.String()]
+}
+
+// Baseline returns the position of the given baseline in design units, and whether it is defined.
+func (s *BaseScript) Baseline(tag Tag) (int16, bool) {
+	v, ok := s.Coordinates[tag]
+	return v, ok
+}
+
+// baseHeader is the on-disk format of the BASE table header.
+type baseHeader struct {
+	Major           uint16
+	Minor           uint16
+	HorizAxisOffset uint16 // offset to horizontal Axis table, from beginning of BASE table (may be NULL).
+	VertAxisOffset  uint16 // offset to vertical Axis table, from beginning of BASE table (may be NULL).
+}
+
+type baseAxisTable struct {
+	BaseTagListOffset    uint16 // offset to BaseTagList table, from beginning of Axis table (may be NULL).
+	BaseScriptListOffset uint16 // offset to BaseScriptList table, from beginning of Axis table.
+}
+
+type baseScriptTable struct {
+	BaseValuesOffset    uint16 // offset to BaseValues table, from beginning of BaseScript table (may be NULL).
+	DefaultMinMaxOffset uint16 // offset to MinMax table, from beginning of BaseScript table (may be NULL).
+	BaseLangSysCount    uint16
+}
+
+type baseValuesTable struct {
+	DefaultBaselineIndex uint16 // index into the BaseTagList of the default baseline.
+	BaseCoordCount       uint16 // number of BaseCoord tables, must equal the count in BaseTagList.
+}
+
+type baseCoordTable struct {
+	Format     uint16
+	Coordinate int16 // X or Y value, in design units.
+}
+
+// parseBaseCoord parses a BaseCoord table. Only the coordinate is read, the
+// contour point and device table adjustments of formats 2 and 3 are ignored.
+func parseBaseCoord(b []byte, offset uint16) (int16, error) {
+	if int(offset) >= len(b) {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	var coord baseCoordTable
+	if err := binary.Read(bytes.NewReader(b[offset:]), binary.BigEndian, &coord); err != nil {
+		return 0, fmt.Errorf("reading baseCoord: %s", err)
+	}
+
+	if coord.Format < 1 || coord.Format > 3 {
+		return 0, fmt.Errorf("unsupported baseCoord format %d", coord.Format)
+	}
+
+	return coord.Coordinate, nil
+}
+
+// parseBaseScript parses a single BaseScript table. b expected to be the beginning of BaseScriptList.
+func (t *TableBase) parseBaseScript(b []byte, record tagOffsetRecord, baselines []Tag) (*BaseScript, error) {
+	if int(record.Offset) >= len(b) {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	b = b[record.Offset:]
+
+	var script baseScriptTable
+	if err := binary.Read(bytes.NewReader(b), binary.BigEndian, &script); err != nil {
+		return nil, fmt.Errorf("reading baseScript: %s", err)
+	}
+
+	s := &BaseScript{
+		Tag:         record.Tag,
+		Coordinates: make(map[Tag]int16),
+	}
+
+	// TODO Read the MinMax tables, and the BaseLangSys records.
+	if script.BaseValuesOffset == 0 {
+		return s, nil
+	}
+	if int(script.BaseValuesOffset) >= len(b) {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	b = b[script.BaseValuesOffset:]
+	r := bytes.NewReader(b)
+
+	var values baseValuesTable
+	if err := binary.Read(r, binary.BigEndian, &values); err != nil {
+		return nil, fmt.Errorf("reading baseValues: %s", err)
+	}
+
+	if int(values.BaseCoordCount) != len(baselines) {
+		return nil, fmt.Errorf("baseValues has %d coordinates, want %d", values.BaseCoordCount, len(baselines))
+	}
+
+	if int(values.DefaultBaselineIndex) < len(baselines) {
+		s.DefaultBaseline = baselines[values.DefaultBaselineIndex]
+	}
+
+	offsets := make([]uint16, values.BaseCoordCount)
+	if err := binary.Read(r, binary.BigEndian, &offsets); err != nil {
+		return nil, fmt.Errorf("reading baseCoord offsets[%d]: %s", values.BaseCoordCount, err)
+	}
+
+	for i, offset := range offsets {
+		coord, err := parseBaseCoord(b, offset)
+		if err != nil {
+			return nil, err
+		}
+		s.Coordinates[baselines[i]] = coord
+	}
+
+	return s, nil
+}
+
+// parseBaseAxis parses an Axis table. b is expected to be the beginning of the Axis table.
+func (t *TableBase) parseBaseAxis(b []byte) (*BaseAxis, error) {
+	var header baseAxisTable
+	if err := binary.Read(bytes.NewReader(b), binary.BigEndian, &header); err != nil {
+		return nil, fmt.Errorf("reading axis: %s", err)
+	}
+
+	axis := &BaseAxis{}
+
+	if header.BaseTagListOffset > 0 {
+		if int(header.BaseTagListOffset) >= len(b) {
+			return nil, io.ErrUnexpectedEOF
+		}
+
+		r := bytes.NewReader(b[header.BaseTagListOffset:])
+
+		var count uint16
+		if err := binary.Read(r, binary.BigEndian, &count); err != nil {
+			return nil, fmt.Errorf("reading baseTagCount: %s", err)
+		}
+
+		axis.Baselines = make([]Tag, count)
+		if err := binary.Read(r, binary.BigEndian, &axis.Baselines); err != nil {
+			return nil, fmt.Errorf("reading baselineTags[%d]: %s", count, err)
+		}
+	}
+
+	if int(header.BaseScriptListOffset) >= len(b) {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	b = b[header.BaseScriptListOffset:]
+	r := bytes.NewReader(b)
+
+	var count uint16
+	if err := binary.Read(r, binary.BigEndian, &count); err != nil {
+		return nil, fmt.Errorf("reading baseScriptCount: %s", err)
+	}
+
+	for i := 0; i < int(count); i++ {
+		var record tagOffsetRecord
+		if err := binary.Read(r, binary.BigEndian, &record); err != nil {
+			return nil, fmt.Errorf("reading baseScriptRecord[%d]: %s", i, err)
+		}
+
+		script, err := t.parseBaseScript(b, record, axis.Baselines)
+		if err != nil {
+			return nil, err
+		}
+
+		axis.Scripts = append(axis.Scripts, script)
+	}
+
+	return axis, nil
+}
+
+// parseTableBase parses the BASE table.
+func parseTableBase(tag Tag, buf []byte) (Table, error) {
+	t := &TableBase{
+		baseTable: baseTable(tag),
+		bytes:     buf,
+	}
+
+	var header baseHeader
+	if err := binary.Read(bytes.NewReader(buf), binary.BigEndian, &header); err != nil {
+		return nil, fmt.Errorf("reading BASE header: %s", err)
+	}
+
+	if header.Major != 1 || header.Minor > 1 {
+		return nil, fmt.Errorf("unsupported BASE version (major: %d, minor: %d)", header.Major, header.Minor)
+	}
+
+	var err error
+	if header.HorizAxisOffset > 0 {
+		if int(header.HorizAxisOffset) >= len(buf) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		if t.Horizontal, err = t.parseBaseAxis(buf[header.HorizAxisOffset:]); err != nil {
+			return nil, err
+		}
+	}
+
+	if header.VertAxisOffset > 0 {
+		if int(header.VertAxisOffset) >= len(buf) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		if t.Vertical, err = t.parseBaseAxis(buf[header.VertAxisOffset:]); err != nil {
+			return nil, err
+		}
+	}
+
+	return t, nil
+}
@@ -0,0 +1,60 @@
+package sfnt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestParseTableBase(t *testing.T) {
+	var buf bytes.Buffer
+	for _, v := range []interface{}{
+		// BASE header, with only a horizontal axis.
+		baseHeader{Major: 1, Minor: 0, HorizAxisOffset: 8},
+		// Axis table.
+		baseAxisTable{BaseTagListOffset: 4, BaseScriptListOffset: 14},
+		// BaseTagList.
+		uint16(2), BaselineIdeographic, BaselineRoman,
+		// BaseScriptList.
+		uint16(1), tagOffsetRecord{Tag: MustNamedTag("latn"), Offset: 8},
+		// BaseScript.
+		baseScriptTable{BaseValuesOffset: 6},
+		// BaseValues.
+		baseValuesTable{DefaultBaselineIndex: 1, BaseCoordCount: 2}, []uint16{8, 12},
+		// BaseCoords.
+		baseCoordTable{Format: 1, Coordinate: -120},
+		baseCoordTable{Format: 1, Coordinate: 0},
+	} {
+		if err := binary.Write(&buf, binary.BigEndian, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	table, err := parseTableBase(TagBase, buf.Bytes())
+	if err != nil {
+		t.Fatalf("parseTableBase() err = %q, want nil", err)
+	}
+
+	base := table.(*TableBase)
+	if base.Vertical != nil {
+		t.Errorf("Vertical = %v, want nil", base.Vertical)
+	}
+
+	latn := base.Horizontal.Script(MustNamedTag("latn"))
+	if latn == nil {
+		t.Fatalf("Script(latn) = nil, want script")
+	}
+	if latn.DefaultBaseline != BaselineRoman {
+		t.Errorf("DefaultBaseline = %q, want %q", latn.DefaultBaseline, BaselineRoman)
+	}
+	if v, ok := latn.Baseline(BaselineIdeographic); !ok || v != -120 {
+		t.Errorf("Baseline(ideo) = %d, %v, want -120, true", v, ok)
+	}
+	if _, ok := latn.Baseline(BaselineHanging); ok {
+		t.Errorf("Baseline(hang) found, want missing")
+	}
+
+	if _, err := parseTableBase(TagBase, buf.Bytes()[:20]); err == nil {
+		t.Errorf("parseTableBase(truncated) err = nil, want error")
+	}
+}
@@ -0,0 +1,443 @@
+package sfnt
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// Operators in CFF DICTs. Two byte operators are 1200 plus their second byte.
+const (
+	cffCharset     = 15
+	cffCharStrings = 17
+	cffPrivate     = 18
+	cffROS         = 1230
+	cffCIDCount    = 1234
+	cffFDArray     = 1236
+	cffFDSelect    = 1237
+	cffFontName    = 1238
+)
+
+// cffStandardStrings is the number of strings predefined by the CFF
+// specification. Strings with higher ids are stored in the font.
+const cffStandardStrings = 391
+
+var errCFFTruncated = errors.New("unexpected EOF")
+
+// TableCFF represents the 'CFF ' table, which contains PostScript outlines in
+// the Compact Font Format. Only the information needed to describe the font is
+// parsed, and the table is written out unchanged.
+// https://docs.microsoft.com/en-us/typography/opentype/spec/cff
+// https://adobe-type-tools.github.io/font-tech-notes/pdfs/5176.CFF.pdf
+type TableCFF struct {
+	baseTable
+
+	bytes []byte
+
+	// FontName is the PostScript name of the font.
+	FontName string
+
+	// ROS is the character collection used by a CID-keyed font, and is nil
+	// for other fonts.
+	ROS *CIDSystemInfo
+
+	// CIDCount is the number of CIDs in a CID-keyed font.
+	CIDCount int
+
+	// FDArray contains the Font DICTs of a CID-keyed font, which hold the
+	// hinting parameters of each group of glyphs.
+	FDArray []CFFFontDict
+
+	numGlyphs int
+	charset   []uint16 // charset is the SID or CID of each glyph.
+	fdSelect  []uint8  // fdSelect is the index in FDArray of each glyph.
+	gids      map[uint16]uint16
+}
+
+// CIDSystemInfo identifies the character collection of a CID-keyed font.
+type CIDSystemInfo struct {
+	Registry   string
+	Ordering   string
+	Supplement int
+}
+
+// String returns the name of the character collection, for example
+// "Adobe-Japan1-6".
+func (ros CIDSystemInfo) String() string {
+	return ros.Registry + "-" + ros.Ordering + "-" + strconv.Itoa(ros.Supplement)
+}
+
+// CFFFontDict is a Font DICT in the FDArray of a CID-keyed font.
+type CFFFontDict struct {
+	FontName string
+
+	// PrivateOffset and PrivateSize locate the Private DICT, which contains
+	// the hinting parameters, in the table.
+	PrivateOffset, PrivateSize int
+}
+
+// Bytes returns the bytes for this table. The TableCFF is read only, so
+// the bytes will always be the same as what is read in.
+func (t *TableCFF) Bytes() []byte {
+	return t.bytes
+}
+
+// NumGlyphs returns the number of glyphs in the font.
+func (t *TableCFF) NumGlyphs() int {
+	return t.numGlyphs
+}
+
+// IsCIDKeyed returns true if the glyphs are identified by CIDs in a character
+// collection, as in most CJK fonts, rather than by name.
+func (t *TableCFF) IsCIDKeyed() bool {
+	return t.ROS != nil
+}
+
+// CID returns the CID of the glyph. It returns false if the font is not
+// CID-keyed or the glyph does not exist.
+func (t *TableCFF) CID(gid uint16) (uint16, bool) {
+	if !t.IsCIDKeyed() || int(gid) >= len(t.charset) {
+		return 0, false
+	}
+	return t.charset[gid], true
+}
+
+// GlyphID returns the glyph with the given CID. It returns false if the font
+// is not CID-keyed or has no glyph for the CID.
+func (t *TableCFF) GlyphID(cid uint16) (uint16, bool) {
+	gid, found := t.gids[cid]
+	return gid, found
+}
+
+// CIDs returns the CID of each glyph that the font has, in order.
+func (t *TableCFF) CIDs() []uint16 {
+	if !t.IsCIDKeyed() {
+		return nil
+	}
+	cids := append([]uint16(nil), t.charset...)
+	sort.Slice(cids, func(i, j int) bool { return cids[i] < cids[j] })
+	return cids
+}
+
+// FontDict returns the index in FDArray of the Font DICT used by the glyph. It
+// returns false if the font is not CID-keyed or the glyph does not exist.
+func (t *TableCFF) FontDict(gid uint16) (int, bool) {
+	if int(gid) >= len(t.fdSelect) {
+		return 0, false
+	}
+	return int(t.fdSelect[gid]), true
+}
+
+func parseTableCFF(tag Tag, buf []byte) (Table, error) {
+	if len(buf) < 4 {
+		return nil, fmt.Errorf("reading CFF header: %s", errCFFTruncated)
+	}
+	if buf[0] != 1 {
+		return nil, fmt.Errorf("unsupported CFF version %d", buf[0])
+	}
+
+	names, offset, err := parseCFFIndex(buf, int(buf[2]))
+	if err != nil {
+		return nil, fmt.Errorf("reading Name INDEX: %s", err)
+	}
+	topDicts, offset, err := parseCFFIndex(buf, offset)
+	if err != nil {
+		return nil, fmt.Errorf("reading Top DICT INDEX: %s", err)
+	}
+	stringIndex, _, err := parseCFFIndex(buf, offset)
+	if err != nil {
+		return nil, fmt.Errorf("reading String INDEX: %s", err)
+	}
+	if len(names) != 1 || len(topDicts) != 1 {
+		return nil, fmt.Errorf("CFF table contains %d fonts, want 1", len(names))
+	}
+	str := func(sid int) string {
+		if i := sid - cffStandardStrings; i >= 0 && i < len(stringIndex) {
+			return string(stringIndex[i])
+		}
+		return ""
+	}
+
+	top, err := parseCFFDict(topDicts[0])
+	if err != nil {
+		return nil, fmt.Errorf("reading Top DICT: %s", err)
+	}
+	table := &TableCFF{
+		baseTable: baseTable(tag),
+		bytes:     buf,
+		FontName:  string(names[0]),
+	}
+
+	charStrings, _, err := parseCFFIndex(buf, top.int(cffCharStrings, 0))
+	if err != nil {
+		return nil, fmt.Errorf("reading CharStrings INDEX: %s", err)
+	}
+	table.numGlyphs = len(charStrings)
+
+	if ros := top[cffROS]; len(ros) == 3 {
+		table.ROS = &CIDSystemInfo{str(int(ros[0])), str(int(ros[1])), int(ros[2])}
+		table.CIDCount = top.int(cffCIDCount, 8720)
+
+		fonts, _, err := parseCFFIndex(buf, top.int(cffFDArray, 0))
+		if err != nil {
+			return nil, fmt.Errorf("reading FDArray: %s", err)
+		}
+		for i, data := range fonts {
+			dict, err := parseCFFDict(data)
+			if err != nil {
+				return nil, fmt.Errorf("reading Font DICT %d: %s", i, err)
+			}
+			fd := CFFFontDict{FontName: str(dict.int(cffFontName, 0))}
+			if private := dict[cffPrivate]; len(private) == 2 {
+				fd.PrivateSize, fd.PrivateOffset = int(private[0]), int(private[1])
+			}
+			table.FDArray = append(table.FDArray, fd)
+		}
+
+		if table.fdSelect, err = parseCFFFDSelect(buf, top.int(cffFDSelect, 0), table.numGlyphs); err != nil {
+			return nil, fmt.Errorf("reading FDSelect: %s", err)
+		}
+		for gid, fd := range table.fdSelect {
+			if int(fd) >= len(table.FDArray) {
+				return nil, fmt.Errorf("glyph %d uses Font DICT %d, but there are %d", gid, fd, len(table.FDArray))
+			}
+		}
+	}
+
+	if charset := top.int(cffCharset, 0); charset > 2 {
+		if table.charset, err = parseCFFCharset(buf, charset, table.numGlyphs); err != nil {
+			return nil, fmt.Errorf("reading charset: %s", err)
+		}
+	} else if table.IsCIDKeyed() {
+		return nil, fmt.Errorf("CID-keyed font has a predefined charset")
+	}
+	if table.IsCIDKeyed() {
+		table.gids = make(map[uint16]uint16, len(table.charset))
+		for gid, cid := range table.charset {
+			if _, found := table.gids[cid]; !found {
+				table.gids[cid] = uint16(gid)
+			}
+		}
+	}
+
+	return table, nil
+}
+
+// parseCFFIndex reads the INDEX at offset in buf, and returns each of its
+// objects and the offset of the data after it.
+func parseCFFIndex(buf []byte, offset int) ([][]byte, int, error) {
+	if offset <= 0 || offset+2 > len(buf) {
+		return nil, 0, errCFFTruncated
+	}
+	count := int(binary.BigEndian.Uint16(buf[offset:]))
+	if count == 0 {
+		return nil, offset + 2, nil
+	}
+	if offset+3 > len(buf) {
+		return nil, 0, errCFFTruncated
+	}
+	offSize := int(buf[offset+2])
+	if offSize < 1 || offSize > 4 {
+		return nil, 0, fmt.Errorf("invalid offset size %d", offSize)
+	}
+	offsets := offset + 3
+	// Offsets are relative to the byte before the object data.
+	data := offsets + (count+1)*offSize - 1
+	if data >= len(buf) {
+		return nil, 0, errCFFTruncated
+	}
+
+	readOffset := func(i int) int {
+		v := 0
+		for _, b := range buf[offsets+i*offSize : offsets+(i+1)*offSize] {
+			v = v<<8 | int(b)
+		}
+		return data + v
+	}
+	objects := make([][]byte, count)
+	start := readOffset(0)
+	for i := range objects {
+		end := readOffset(i + 1)
+		if end < start || end > len(buf) {
+			return nil, 0, errCFFTruncated
+		}
+		objects[i] = buf[start:end]
+		start = end
+	}
+	return objects, start, nil
+}
+
+// cffDict maps each operator in a DICT to its operands.
+type cffDict map[int][]float64
+
+// int returns the first operand of op, or value if op is not in the DICT.
+func (dict cffDict) int(op int, value int) int {
+	if operands := dict[op]; len(operands) > 0 {
+		return int(operands[0])
+	}
+	return value
+}
+
+// parseCFFDict reads the operators and operands of a DICT.
+func parseCFFDict(buf []byte) (cffDict, error) {
+	dict := cffDict{}
+	var operands []float64
+	for i := 0; i < len(buf); {
+		b0 := int(buf[i])
+		switch {
+		case b0 <= 21:
+			op := b0
+			if b0 == 12 {
+				if i+1 >= len(buf) {
+					return nil, errCFFTruncated
+				}
+				op = 1200 + int(buf[i+1])
+				i++
+			}
+			dict[op] = operands
+			operands = nil
+			i++
+		case b0 == 28:
+			if i+3 > len(buf) {
+				return nil, errCFFTruncated
+			}
+			operands = append(operands, float64(int16(binary.BigEndian.Uint16(buf[i+1:]))))
+			i += 3
+		case b0 == 29:
+			if i+5 > len(buf) {
+				return nil, errCFFTruncated
+			}
+			operands = append(operands, float64(int32(binary.BigEndian.Uint32(buf[i+1:]))))
+			i += 5
+		case b0 == 30:
+			v, n, err := parseCFFReal(buf[i+1:])
+			if err != nil {
+				return nil, err
+			}
+			operands = append(operands, v)
+			i += 1 + n
+		case b0 >= 32 && b0 <= 246:
+			operands = append(operands, float64(b0-139))
+			i++
+		case b0 >= 247 && b0 <= 254:
+			if i+2 > len(buf) {
+				return nil, errCFFTruncated
+			}
+			v := (b0-247)*256 + int(buf[i+1]) + 108
+			if b0 >= 251 {
+				v = -(b0-251)*256 - int(buf[i+1]) - 108
+			}
+			operands = append(operands, float64(v))
+			i += 2
+		default:
+			return nil, fmt.Errorf("invalid DICT byte %d", b0)
+		}
+	}
+	return dict, nil
+}
+
+// parseCFFReal reads a real number operand, which is stored as a string of
+// nibbles, and returns it and the number of bytes it used.
+func parseCFFReal(buf []byte) (float64, int, error) {
+	var s []byte
+	for i, b := range buf {
+		for _, nibble := range []byte{b >> 4, b & 0xF} {
+			switch {
+			case nibble <= 9:
+				s = append(s, '0'+nibble)
+			case nibble == 0xA:
+				s = append(s, '.')
+			case nibble == 0xB:
+				s = append(s, 'E')
+			case nibble == 0xC:
+				s = append(s, 'E', '-')
+			case nibble == 0xE:
+				s = append(s, '-')
+			case nibble == 0xF:
+				v, err := strconv.ParseFloat(string(s), 64)
+				return v, i + 1, err
+			}
+		}
+	}
+	return 0, 0, errCFFTruncated
+}
+
+// parseCFFCharset reads the charset at offset in buf, which contains the SID
+// (or CID) of each of the glyphs.
+func parseCFFCharset(buf []byte, offset, numGlyphs int) ([]uint16, error) {
+	if offset >= len(buf) || numGlyphs == 0 {
+		return nil, errCFFTruncated
+	}
+	charset := make([]uint16, 1, numGlyphs) // Glyph 0 is always .notdef.
+	format := buf[offset]
+	offset++
+	switch format {
+	case 0:
+		if offset+2*(numGlyphs-1) > len(buf) {
+			return nil, errCFFTruncated
+		}
+		for gid := 1; gid < numGlyphs; gid++ {
+			charset = append(charset, binary.BigEndian.Uint16(buf[offset+2*(gid-1):]))
+		}
+	case 1, 2:
+		for len(charset) < numGlyphs {
+			if offset+3+int(format-1) > len(buf) {
+				return nil, errCFFTruncated
+			}
+			first := int(binary.BigEndian.Uint16(buf[offset:]))
+			left := int(buf[offset+2])
+			if format == 2 {
+				left = int(binary.BigEndian.Uint16(buf[offset+2:]))
+			}
+			offset += 3 + int(format-1)
+			for sid := first; sid <= first+left && len(charset) < numGlyphs; sid++ {
+				charset = append(charset, uint16(sid))
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported format %d", format)
+	}
+	return charset, nil
+}
+
+// parseCFFFDSelect reads the FDSelect at offset in buf, which contains the
+// index of the Font DICT used by each of the glyphs.
+func parseCFFFDSelect(buf []byte, offset, numGlyphs int) ([]uint8, error) {
+	if offset <= 0 || offset >= len(buf) {
+		return nil, errCFFTruncated
+	}
+	format := buf[offset]
+	offset++
+	switch format {
+	case 0:
+		if offset+numGlyphs > len(buf) {
+			return nil, errCFFTruncated
+		}
+		return buf[offset : offset+numGlyphs], nil
+	case 3:
+		if offset+2 > len(buf) {
+			return nil, errCFFTruncated
+		}
+		numRanges := int(binary.BigEndian.Uint16(buf[offset:]))
+		ranges := buf[offset+2:]
+		if 3*numRanges+2 > len(ranges) {
+			return nil, errCFFTruncated
+		}
+		fdSelect := make([]uint8, numGlyphs)
+		for i := 0; i < numRanges; i++ {
+			first := int(binary.BigEndian.Uint16(ranges[3*i:]))
+			end := int(binary.BigEndian.Uint16(ranges[3*i+3:])) // The first glyph of the next range, or the sentinel.
+			if first > end || end > numGlyphs {
+				return nil, fmt.Errorf("invalid range %d-%d", first, end)
+			}
+			for gid := first; gid < end; gid++ {
+				fdSelect[gid] = ranges[3*i+2]
+			}
+		}
+		return fdSelect, nil
+	default:
+		return nil, fmt.Errorf("unsupported format %d", format)
+	}
+}
@@ -0,0 +1,116 @@
+package sfnt
+
+import (
+	"reflect"
+	"testing"
+)
+
+// cffTestIndex returns a CFF INDEX containing objects, with 2 byte offsets.
+func cffTestIndex(objects ...[]byte) []byte {
+	if len(objects) == 0 {
+		return []byte{0, 0}
+	}
+	buf := appendUint16s(nil, uint16(len(objects)))
+	buf = append(buf, 2)
+	offset := 1
+	buf = appendUint16s(buf, uint16(offset))
+	for _, object := range objects {
+		offset += len(object)
+		buf = appendUint16s(buf, uint16(offset))
+	}
+	for _, object := range objects {
+		buf = append(buf, object...)
+	}
+	return buf
+}
+
+// cffTestInt returns a DICT operand for v, which always uses 5 bytes.
+func cffTestInt(v int) []byte {
+	return appendUint32s([]byte{29}, uint32(v))
+}
+
+func cffTestCIDFont() []byte {
+	stringIndex := cffTestIndex([]byte("Adobe"), []byte("Identity"), []byte("Test-Kana"), []byte("Test-Kanji"))
+	charset := []byte{1, 0, 100, 1, 1, 244, 0}          // CIDs 100-101 and 500.
+	fdSelect := []byte{3, 0, 2, 0, 0, 0, 0, 3, 1, 0, 4} // Glyphs 0-2 use Font DICT 0, and glyph 3 Font DICT 1.
+	charStrings := cffTestIndex([]byte{14}, []byte{14}, []byte{14}, []byte{14})
+
+	topDict := func(offset int) []byte {
+		var dict []byte
+		dict = append(append(append(dict, cffTestInt(391)...), cffTestInt(392)...), cffTestInt(0)...)
+		dict = append(dict, 12, 30)
+		dict = append(append(dict, cffTestInt(65535)...), 12, 34)
+		dict = append(append(dict, cffTestInt(offset)...), cffCharset)
+		offset += len(charset)
+		dict = append(append(dict, cffTestInt(offset)...), 12, 37)
+		offset += len(fdSelect)
+		dict = append(append(dict, cffTestInt(offset)...), cffCharStrings)
+		offset += len(charStrings)
+		return append(append(dict, cffTestInt(offset)...), 12, 36)
+	}
+	header := append([]byte{1, 0, 4, 2}, cffTestIndex([]byte("Test-CID"))...)
+	size := len(header) + len(cffTestIndex(topDict(0))) + len(stringIndex) + 2
+
+	buf := append(header, cffTestIndex(topDict(size))...)
+	buf = append(append(buf, stringIndex...), 0, 0)
+	buf = append(append(append(buf, charset...), fdSelect...), charStrings...)
+	fontDict := func(sid int) []byte {
+		return append(append(cffTestInt(sid), 12, 38), 139, 139, cffPrivate)
+	}
+	return append(buf, cffTestIndex(fontDict(393), fontDict(394))...)
+}
+
+func TestParseTableCFFCIDKeyed(t *testing.T) {
+	table, err := parseTableCFF(TagCFF, cffTestCIDFont())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cff := table.(*TableCFF)
+
+	if !cff.IsCIDKeyed() || cff.ROS.String() != "Adobe-Identity-0" || cff.CIDCount != 65535 {
+		t.Errorf("ROS = %v, CIDCount = %d; want Adobe-Identity-0 and 65535", cff.ROS, cff.CIDCount)
+	}
+	if cff.FontName != "Test-CID" || cff.NumGlyphs() != 4 {
+		t.Errorf("FontName = %q, NumGlyphs() = %d; want Test-CID and 4", cff.FontName, cff.NumGlyphs())
+	}
+	if want := []CFFFontDict{{FontName: "Test-Kana"}, {FontName: "Test-Kanji"}}; !reflect.DeepEqual(cff.FDArray, want) {
+		t.Errorf("FDArray = %v, want %v", cff.FDArray, want)
+	}
+
+	for gid, want := range []uint16{0, 100, 101, 500} {
+		if cid, _ := cff.CID(uint16(gid)); cid != want {
+			t.Errorf("CID(%d) = %d, want %d", gid, cid, want)
+		}
+		if got, _ := cff.GlyphID(want); got != uint16(gid) {
+			t.Errorf("GlyphID(%d) = %d, want %d", want, got, gid)
+		}
+		if fd, _ := cff.FontDict(uint16(gid)); fd != gid/3 {
+			t.Errorf("FontDict(%d) = %d, want %d", gid, fd, gid/3)
+		}
+	}
+	if _, found := cff.GlyphID(102); found {
+		t.Errorf("GlyphID(102) found a glyph, want none")
+	}
+
+	if _, err := parseTableCFF(TagCFF, cffTestCIDFont()[:100]); err == nil {
+		t.Errorf("parseTableCFF(truncated) err = nil, want error")
+	}
+}
+
+func TestParseTableCFF(t *testing.T) {
+	font := parseTestFont(t, "Raleway-v4020-Regular.otf")
+	cff, err := font.CFFTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	maxp, err := font.MaxpTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cff.FontName != "Raleway-v4020-Regular" || cff.NumGlyphs() != int(maxp.NumGlyphs) || cff.IsCIDKeyed() {
+		t.Errorf("FontName = %q, NumGlyphs() = %d, IsCIDKeyed() = %t; want Raleway-v4020-Regular, %d, false", cff.FontName, cff.NumGlyphs(), cff.IsCIDKeyed(), maxp.NumGlyphs)
+	}
+	if _, found := cff.CID(1); found {
+		t.Errorf("CID(1) found a CID in a font that is not CID-keyed")
+	}
+}
@@ -0,0 +1,585 @@
+package sfnt
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"unicode"
+)
+
+// TableCmap represents the 'cmap' table, which maps characters to glyphs.
+// https://docs.microsoft.com/en-us/typography/opentype/spec/cmap
+type TableCmap struct {
+	baseTable
+
+	bytes []byte
+
+	// Subtables contains each encoding record in the table.
+	Subtables []*CmapSubtable
+
+	// legacy maps Unicode code points using a symbol or CJK subtable, and is
+	// used when the table has no Unicode subtable.
+	legacy *CmapSubtable
+}
+
+// CmapSubtable is a mapping from characters in one encoding to glyphs.
+type CmapSubtable struct {
+	PlatformID PlatformID
+	EncodingID PlatformEncodingID
+	Format     uint16
+	Language   uint32
+
+	// Mapping maps each character code to its glyph id. It is nil if the
+	// subtable uses a format that this package cannot parse.
+	Mapping map[rune]uint16
+
+	// bytes is the content of a format 14 (Unicode variation sequences)
+	// subtable, which is copied unchanged when the table is rebuilt.
+	bytes []byte
+}
+
+// IsUnicode returns true if the subtable maps Unicode code points.
+func (s *CmapSubtable) IsUnicode() bool {
+	return s.PlatformID == PlatformUnicode ||
+		s.PlatformID == PlatformMicrosoft && (s.EncodingID == PlatformEncodingMicrosoftUnicode || s.EncodingID == PlatformEncodingMicrosoftUCS4)
+}
+
+// IsSymbol returns true if the subtable maps the characters of a symbol font,
+// which are conventionally in the private use area from U+F020 to U+F0FF.
+func (s *CmapSubtable) IsSymbol() bool {
+	return s.PlatformID == PlatformMicrosoft && s.EncodingID == PlatformEncodingMicrosoftSymbol
+}
+
+func parseTableCmap(tag Tag, buf []byte) (Table, error) {
+	if len(buf) < 4 {
+		return nil, fmt.Errorf("reading cmap header: unexpected EOF")
+	}
+	numTables := int(binary.BigEndian.Uint16(buf[2:]))
+	if len(buf) < 4+8*numTables {
+		return nil, fmt.Errorf("reading %d encoding records: unexpected EOF", numTables)
+	}
+
+	table := &TableCmap{
+		baseTable: baseTable(tag),
+		bytes:     buf,
+	}
+
+	// Encoding records often share a subtable, which is only parsed once.
+	parsed := make(map[uint32]*CmapSubtable)
+	budget := maxCmapCharacters
+	for i := 0; i < numTables; i++ {
+		record := buf[4+8*i:]
+		offset := binary.BigEndian.Uint32(record[4:])
+		if int(offset)+2 > len(buf) {
+			return nil, fmt.Errorf("invalid offset %d for cmap subtable %d", offset, i)
+		}
+
+		sub := &CmapSubtable{
+			PlatformID: PlatformID(binary.BigEndian.Uint16(record)),
+			EncodingID: PlatformEncodingID(binary.BigEndian.Uint16(record[2:])),
+			Format:     binary.BigEndian.Uint16(buf[offset:]),
+		}
+		if shared, found := parsed[offset]; found {
+			sub.Language, sub.Mapping, sub.bytes = shared.Language, shared.Mapping, shared.bytes
+		} else if err := sub.parse(buf[offset:], &budget); err != nil {
+			return nil, fmt.Errorf("cmap subtable %d (format %d): %s", i, sub.Format, err)
+		}
+		parsed[offset] = sub
+		table.Subtables = append(table.Subtables, sub)
+	}
+
+	if symbol := table.Symbol(); symbol != nil {
+		table.legacy = symbolAliases(symbol)
+	} else {
+		table.legacy = table.decodeLegacy()
+	}
+	return table, nil
+}
+
+// symbolAliases returns a copy of the symbol subtable s that also maps each
+// character from U+0000 to U+00FF to the glyph of the same character offset by
+// U+F000, as Windows does when text is drawn with a symbol font.
+func symbolAliases(s *CmapSubtable) *CmapSubtable {
+	aliased := *s
+	aliased.Mapping = make(map[rune]uint16, 2*len(s.Mapping))
+	for r, gid := range s.Mapping {
+		aliased.Mapping[r] = gid
+	}
+	for r, gid := range s.Mapping {
+		if r >= 0xF000 && r <= 0xF0FF {
+			if _, found := aliased.Mapping[r-0xF000]; !found {
+				aliased.Mapping[r-0xF000] = gid
+			}
+		}
+	}
+	return &aliased
+}
+
+var errCmapTruncated = errors.New("subtable is truncated")
+
+// maxCmapCharacters is the number of characters that the format 4, 12 and 13
+// subtables of a table may map in total. These formats map ranges of
+// characters, so a small table could otherwise expand to a huge mapping. The
+// ranges of a valid subtable do not overlap, so each maps at most every Unicode
+// code point, and this allows two such subtables.
+const maxCmapCharacters = 2 * (int(unicode.MaxRune) + 1)
+
+// useCmapBudget takes the characters that a subtable maps from budget, the
+// number of characters that the table may still map.
+func useCmapBudget(budget *int, characters int) error {
+	if characters > *budget {
+		return fmt.Errorf("maps %d characters, which is too many", characters)
+	}
+	*budget -= characters
+	return nil
+}
+
+// parse reads the mapping for the formats that are understood. Formats 4, 12
+// and 13 use up budget, the number of characters that the table may still map.
+func (s *CmapSubtable) parse(buf []byte, budget *int) error {
+	switch s.Format {
+	case 0:
+		if len(buf) < 6+256 {
+			return errCmapTruncated
+		}
+		s.Language = uint32(binary.BigEndian.Uint16(buf[4:]))
+		s.Mapping = make(map[rune]uint16, 256)
+		for i, gid := range buf[6 : 6+256] {
+			if gid != 0 {
+				s.Mapping[rune(i)] = uint16(gid)
+			}
+		}
+
+	case 2:
+		if len(buf) < 6+512 {
+			return errCmapTruncated
+		}
+		s.Language = uint32(binary.BigEndian.Uint16(buf[4:]))
+		s.Mapping = make(map[rune]uint16)
+		for high := 0; high < 256; high++ {
+			// Bytes using sub-header 0 are single byte characters, and the
+			// others are the first byte of a two byte character.
+			index := int(binary.BigEndian.Uint16(buf[6+2*high:])) / 8
+			header := 6 + 512 + 8*index
+			if header+8 > len(buf) {
+				return errCmapTruncated
+			}
+			first := int(binary.BigEndian.Uint16(buf[header:]))
+			count := int(binary.BigEndian.Uint16(buf[header+2:]))
+			delta := binary.BigEndian.Uint16(buf[header+4:])
+			rangeOffset := int(binary.BigEndian.Uint16(buf[header+6:]))
+
+			for low := first; low < first+count; low++ {
+				c := high<<8 | low
+				if index == 0 {
+					if low != high {
+						continue
+					}
+					c = high
+				}
+				at := header + 6 + rangeOffset + 2*(low-first)
+				if at+2 > len(buf) {
+					return errCmapTruncated
+				}
+				if gid := binary.BigEndian.Uint16(buf[at:]); gid != 0 {
+					s.Mapping[rune(c)] = gid + delta
+				}
+			}
+		}
+
+	case 4:
+		if len(buf) < 14 {
+			return errCmapTruncated
+		}
+		s.Language = uint32(binary.BigEndian.Uint16(buf[4:]))
+		segCount := int(binary.BigEndian.Uint16(buf[6:]) / 2)
+		if len(buf) < 16+8*segCount {
+			return errCmapTruncated
+		}
+		ends := buf[14:]
+		starts := buf[16+2*segCount:]
+		deltas := buf[16+4*segCount:]
+		rangeOffsets := buf[16+6*segCount:]
+
+		characters := 0
+		for i := 0; i < segCount; i++ {
+			if start, end := binary.BigEndian.Uint16(starts[2*i:]), binary.BigEndian.Uint16(ends[2*i:]); start <= end {
+				characters += int(end-start) + 1
+			}
+		}
+		if err := useCmapBudget(budget, characters); err != nil {
+			return err
+		}
+
+		s.Mapping = make(map[rune]uint16)
+		for i := 0; i < segCount; i++ {
+			end := binary.BigEndian.Uint16(ends[2*i:])
+			start := binary.BigEndian.Uint16(starts[2*i:])
+			delta := binary.BigEndian.Uint16(deltas[2*i:])
+			rangeOffset := int(binary.BigEndian.Uint16(rangeOffsets[2*i:]))
+
+			for c := int(start); c <= int(end) && c != 0xFFFF; c++ {
+				gid := uint16(c) + delta
+				if rangeOffset != 0 {
+					at := 16 + 6*segCount + 2*i + rangeOffset + 2*(c-int(start))
+					if at+2 > len(buf) {
+						return errCmapTruncated
+					}
+					gid = binary.BigEndian.Uint16(buf[at:])
+					if gid != 0 {
+						gid += delta
+					}
+				}
+				if gid != 0 {
+					s.Mapping[rune(c)] = gid
+				}
+			}
+		}
+
+	case 6:
+		if len(buf) < 10 {
+			return errCmapTruncated
+		}
+		s.Language = uint32(binary.BigEndian.Uint16(buf[4:]))
+		first := rune(binary.BigEndian.Uint16(buf[6:]))
+		count := int(binary.BigEndian.Uint16(buf[8:]))
+		if len(buf) < 10+2*count {
+			return errCmapTruncated
+		}
+		s.Mapping = make(map[rune]uint16, count)
+		for i := 0; i < count; i++ {
+			if gid := binary.BigEndian.Uint16(buf[10+2*i:]); gid != 0 {
+				s.Mapping[first+rune(i)] = gid
+			}
+		}
+
+	case 14:
+		if len(buf) < 10 {
+			return errCmapTruncated
+		}
+		length := binary.BigEndian.Uint32(buf[2:])
+		if length < 10 || uint64(length) > uint64(len(buf)) {
+			return errCmapTruncated
+		}
+		s.bytes = buf[:length]
+
+	case 12, 13:
+		if len(buf) < 16 {
+			return errCmapTruncated
+		}
+		s.Language = binary.BigEndian.Uint32(buf[8:])
+		numGroups := int(binary.BigEndian.Uint32(buf[12:]))
+		if numGroups > (len(buf)-16)/12 {
+			return errCmapTruncated
+		}
+		characters := 0
+		for i := 0; i < numGroups; i++ {
+			group := buf[16+12*i:]
+			start := binary.BigEndian.Uint32(group)
+			end := binary.BigEndian.Uint32(group[4:])
+			if end < start || end > unicode.MaxRune {
+				return fmt.Errorf("invalid group %d: %X-%X", i, start, end)
+			}
+			characters += int(end-start) + 1
+		}
+		if err := useCmapBudget(budget, characters); err != nil {
+			return err
+		}
+
+		s.Mapping = make(map[rune]uint16)
+		for i := 0; i < numGroups; i++ {
+			group := buf[16+12*i:]
+			start := binary.BigEndian.Uint32(group)
+			end := binary.BigEndian.Uint32(group[4:])
+			gid := binary.BigEndian.Uint32(group[8:])
+			for c := start; c <= end; c++ {
+				if s.Format == 12 {
+					s.Mapping[rune(c)] = uint16(gid + c - start)
+				} else {
+					s.Mapping[rune(c)] = uint16(gid)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// Unicode returns the subtable that best maps Unicode code points to glyphs,
+// preferring subtables that cover characters outside the Basic Multilingual Plane.
+//
+// Symbol fonts have no Unicode subtable, so for them it returns a copy of the
+// symbol subtable in which the characters U+0000 to U+00FF are aliases for
+// U+F000 to U+F0FF. Similarly, for old CJK fonts that only have a subtable for
+// Shift-JIS, GB 2312, Big5 or Wansung, it returns a copy of that subtable with
+// the characters converted to Unicode. It returns nil if there is no subtable
+// that can be used.
+func (t *TableCmap) Unicode() *CmapSubtable {
+	var best *CmapSubtable
+	for _, s := range t.Subtables {
+		if !s.IsUnicode() || s.Mapping == nil {
+			continue
+		}
+		if best == nil || s.Format == 12 && best.Format != 12 {
+			best = s
+		}
+	}
+	if best == nil {
+		return t.legacy
+	}
+	return best
+}
+
+// Symbol returns the symbol subtable (platform 3, encoding 0), or nil if there
+// is no such subtable.
+func (t *TableCmap) Symbol() *CmapSubtable {
+	for _, s := range t.Subtables {
+		if s.IsSymbol() && s.Mapping != nil {
+			return s
+		}
+	}
+	return nil
+}
+
+// IsSymbol returns true if the table maps the characters of a symbol font,
+// which has a symbol subtable but no Unicode subtable.
+func (t *TableCmap) IsSymbol() bool {
+	return t.legacy != nil && t.legacy.IsSymbol() && t.Unicode() == t.legacy
+}
+
+// Lookup returns the glyph id for r in the best Unicode subtable.
+func (t *TableCmap) Lookup(r rune) (uint16, bool) {
+	s := t.Unicode()
+	if s == nil {
+		return 0, false
+	}
+	gid, found := s.Mapping[r]
+	return gid, found
+}
+
+// Bytes returns the bytes for this table. The TableCmap is read only, so
+// the bytes will always be the same as what is read in.
+func (t *TableCmap) Bytes() []byte {
+	return t.bytes
+}
+
+// NewTableCmap returns a 'cmap' table mapping each character to a glyph id. It
+// contains a format 4 subtable for the Basic Multilingual Plane and, if any of the
+// characters are outside it, a format 12 subtable for all of the characters.
+func NewTableCmap(mapping map[rune]uint16) *TableCmap {
+	return newTableCmap(mapping, nil, false)
+}
+
+// Rebuild returns a new 'cmap' table mapping each character to a glyph id, like
+// NewTableCmap, which also contains the Unicode variation sequences subtable
+// (platform 0, encoding 5) of t if it has one. Other subtables are dropped.
+//
+// If t is a symbol font, the new table has only a symbol subtable, which maps
+// the characters from U+F000 to U+F0FF in mapping.
+func (t *TableCmap) Rebuild(mapping map[rune]uint16) *TableCmap {
+	if t.IsSymbol() {
+		symbol := make(map[rune]uint16)
+		for r, gid := range mapping {
+			if r >= 0xF000 && r <= 0xF0FF {
+				symbol[r] = gid
+			}
+		}
+		return newTableCmap(symbol, nil, true)
+	}
+
+	var variations []byte
+	for _, s := range t.Subtables {
+		if s.PlatformID == PlatformUnicode && s.EncodingID == 5 && s.bytes != nil {
+			variations = s.bytes
+			break
+		}
+	}
+	return newTableCmap(mapping, variations, false)
+}
+
+// cmapSegment is a range of characters in a format 4 subtable. The characters
+// are mapped to consecutive glyph ids starting at gid, or if gids is not nil,
+// to the glyph ids in gids.
+type cmapSegment struct {
+	start, end rune
+	gid        uint16
+	gids       []uint16
+}
+
+// maxDeltaSegment is the length below which a segment of consecutive glyph ids
+// is merged into a neighbouring segment that lists its glyph ids, as eight
+// bytes per segment is more than two bytes per character.
+const maxDeltaSegment = 4
+
+// newTableCmap returns a 'cmap' table with a format 4 and (if necessary) a format
+// 12 subtable for mapping, and the format 14 subtable variations if it is not nil.
+// If symbol is true, the table has only a format 4 symbol subtable.
+func newTableCmap(mapping map[rune]uint16, variations []byte, symbol bool) *TableCmap {
+	runes := make([]rune, 0, len(mapping))
+	for r := range mapping {
+		if r >= 0 && r <= 0x10FFFF {
+			runes = append(runes, r)
+		}
+	}
+	sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+
+	// Group characters mapped to consecutive glyph ids.
+	var groups []cmapSegment
+	for _, r := range runes {
+		if n := len(groups); n > 0 && groups[n-1].end == r-1 && mapping[r] == groups[n-1].gid+uint16(r-groups[n-1].start) {
+			groups[n-1].end = r
+		} else {
+			groups = append(groups, cmapSegment{start: r, end: r, gid: mapping[r]})
+		}
+	}
+
+	// Short groups next to each other are stored as one segment with an array
+	// of glyph ids, which keeps fonts with unordered glyphs small.
+	var bmp []cmapSegment
+	for _, g := range groups {
+		if g.start > 0xFFFE {
+			break
+		}
+		if g.end > 0xFFFE {
+			g.end = 0xFFFE
+		}
+		n := len(bmp)
+		short := g.end-g.start < maxDeltaSegment
+		if n > 0 && bmp[n-1].end == g.start-1 && short && (bmp[n-1].gids != nil || bmp[n-1].end-bmp[n-1].start < maxDeltaSegment) {
+			last := &bmp[n-1]
+			if last.gids == nil {
+				for r := last.start; r <= last.end; r++ {
+					last.gids = append(last.gids, mapping[r])
+				}
+			}
+			for r := g.start; r <= g.end; r++ {
+				last.gids = append(last.gids, mapping[r])
+			}
+			last.end = g.end
+			continue
+		}
+		bmp = append(bmp, g)
+	}
+
+	// The length of a format 4 subtable is 16 bits, so if there are too many
+	// characters the rest are only in the format 12 subtable.
+	size, truncated := 16+8, false
+	for i, g := range bmp {
+		size += 8 + 2*len(g.gids)
+		if size > 0xFFFF {
+			bmp, truncated = bmp[:i], true
+			break
+		}
+	}
+
+	var format4 []byte
+	segCount := len(bmp) + 1
+	entrySelector := 0
+	for 1<<(entrySelector+1) <= segCount {
+		entrySelector++
+	}
+	searchRange := 2 << entrySelector
+	format4 = appendUint16s(format4, 4, 0, 0, uint16(2*segCount), uint16(searchRange), uint16(entrySelector), uint16(2*segCount-searchRange))
+	for _, g := range bmp {
+		format4 = appendUint16s(format4, uint16(g.end))
+	}
+	format4 = appendUint16s(format4, 0xFFFF, 0)
+	for _, g := range bmp {
+		format4 = appendUint16s(format4, uint16(g.start))
+	}
+	format4 = appendUint16s(format4, 0xFFFF)
+	for _, g := range bmp {
+		if g.gids != nil {
+			format4 = appendUint16s(format4, 0)
+		} else {
+			format4 = appendUint16s(format4, g.gid-uint16(g.start))
+		}
+	}
+	format4 = appendUint16s(format4, 1)
+	// Each range offset is relative to its own position in the table.
+	glyphIDs := 0
+	for i, g := range bmp {
+		if g.gids != nil {
+			format4 = appendUint16s(format4, uint16(2*(segCount-i+glyphIDs)))
+			glyphIDs += len(g.gids)
+		} else {
+			format4 = appendUint16s(format4, 0)
+		}
+	}
+	format4 = appendUint16s(format4, 0)
+	for _, g := range bmp {
+		format4 = appendUint16s(format4, g.gids...)
+	}
+	binary.BigEndian.PutUint16(format4[2:], uint16(len(format4)))
+
+	var format12 []byte
+	if truncated || len(runes) > 0 && runes[len(runes)-1] > 0xFFFE {
+		format12 = appendUint16s(format12, 12, 0)
+		format12 = appendUint32s(format12, uint32(16+12*len(groups)), 0, uint32(len(groups)))
+		for _, g := range groups {
+			format12 = appendUint32s(format12, uint32(g.start), uint32(g.end), uint32(g.gid))
+		}
+	}
+
+	// Both the Unicode and Microsoft platform records refer to the same subtables,
+	// and the records are sorted by platform and encoding.
+	type record struct {
+		platform, encoding uint16
+		subtable           int
+	}
+	subtables := [][]byte{format4}
+	records := []record{{0, 3, 0}}
+	if symbol {
+		records = []record{{3, 0, 0}}
+		format12 = nil
+	}
+	if format12 != nil {
+		subtables = append(subtables, format12)
+		records = append(records, record{0, 4, len(subtables) - 1})
+	}
+	if variations != nil {
+		subtables = append(subtables, variations)
+		records = append(records, record{0, 5, len(subtables) - 1})
+	}
+	if !symbol {
+		records = append(records, record{3, 1, 0})
+	}
+	if format12 != nil {
+		records = append(records, record{3, 10, 1})
+	}
+
+	offsets := make([]uint32, len(subtables))
+	offset := uint32(4 + 8*len(records))
+	for i, subtable := range subtables {
+		offsets[i] = offset
+		offset += uint32(len(subtable))
+	}
+	buf := appendUint16s(nil, 0, uint16(len(records)))
+	for _, r := range records {
+		buf = appendUint32s(appendUint16s(buf, r.platform, r.encoding), offsets[r.subtable])
+	}
+	for _, subtable := range subtables {
+		buf = append(buf, subtable...)
+	}
+
+	table, err := parseTableCmap(TagCmap, buf)
+	if err != nil {
+		panic(err) // should never happen
+	}
+	return table.(*TableCmap)
+}
+
+func appendUint16s(buf []byte, values ...uint16) []byte {
+	for _, v := range values {
+		buf = append(buf, byte(v>>8), byte(v))
+	}
+	return buf
+}
+
+func appendUint32s(buf []byte, values ...uint32) []byte {
+	for _, v := range values {
+		buf = append(buf, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+	}
+	return buf
+}
@@ -0,0 +1,240 @@
+package sfnt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCmapLookup(t *testing.T) {
+	file, err := os.Open(filepath.Join("testdata", "open-sans-v15-latin-regular.woff"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	font, err := Parse(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmap, err := font.CmapTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	post, err := font.PostTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for r, name := range map[rune]string{'A': "A", 'z': "z", 0x2019: "quoteright"} {
+		gid, found := cmap.Lookup(r)
+		if !found {
+			t.Errorf("Lookup(%q) = _, false; want true", r)
+			continue
+		}
+		if got := post.GlyphNames()[gid]; got != name {
+			t.Errorf("Lookup(%q) = glyph %q, want %q", r, got, name)
+		}
+	}
+	if _, found := cmap.Lookup(0x4E00); found {
+		t.Errorf("Lookup(U+4E00) = _, true; want false")
+	}
+}
+
+func TestParseTableCmapFormat12(t *testing.T) {
+	var buf bytes.Buffer
+	for _, v := range []interface{}{
+		uint16(0), uint16(1),
+		uint16(3), uint16(10), uint32(12),
+		// Format 12 subtable.
+		uint16(12), uint16(0), uint32(40), uint32(0), uint32(2),
+		uint32(0x41), uint32(0x43), uint32(1),
+		uint32(0x1F600), uint32(0x1F600), uint32(10),
+	} {
+		if err := binary.Write(&buf, binary.BigEndian, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	table, err := parseTableCmap(TagCmap, buf.Bytes())
+	if err != nil {
+		t.Fatalf("parseTableCmap() err = %q, want nil", err)
+	}
+	cmap := table.(*TableCmap)
+	for r, want := range map[rune]uint16{'A': 1, 'C': 3, 0x1F600: 10} {
+		if gid, _ := cmap.Lookup(r); gid != want {
+			t.Errorf("Lookup(%U) = %d, want %d", r, gid, want)
+		}
+	}
+
+	if _, err := parseTableCmap(TagCmap, buf.Bytes()[:buf.Len()-4]); err == nil {
+		t.Errorf("parseTableCmap(truncated) err = nil, want error")
+	}
+}
+
+func TestParseTableCmapTooManyCharacters(t *testing.T) {
+	// Each format 12 group maps every code point, so the table would map 200
+	// times as many characters as Unicode has.
+	format12 := appendUint16s(nil, 12, 0)
+	format12 = appendUint32s(format12, 16+12*200, 0, 200)
+	for i := 0; i < 200; i++ {
+		format12 = appendUint32s(format12, 0, 0x10FFFF, 1)
+	}
+	// Each format 4 segment maps the whole Basic Multilingual Plane.
+	format4 := appendUint16s(nil, 4, 0, 0, 2*1000, 0, 0, 0)
+	for i := 0; i < 1000; i++ {
+		format4 = appendUint16s(format4, 0xFFFE)
+	}
+	format4 = appendUint16s(format4, 0)
+	for i := 0; i < 1000; i++ {
+		format4 = appendUint16s(format4, 0)
+	}
+	for i := 0; i < 1000; i++ {
+		format4 = appendUint16s(format4, 1)
+	}
+	for i := 0; i < 1000; i++ {
+		format4 = appendUint16s(format4, 0)
+	}
+
+	for _, subtable := range [][]byte{format12, format4} {
+		buf := appendUint32s(appendUint16s(nil, 0, 1, 3, 10), 12)
+		buf = append(buf, subtable...)
+		_, err := parseTableCmap(TagCmap, buf)
+		if err == nil || !strings.Contains(err.Error(), "too many") {
+			t.Errorf("parseTableCmap(format %d) err = %v, want too many characters", subtable[1], err)
+		}
+	}
+}
+
+func TestNewTableCmapGlyphArrays(t *testing.T) {
+	// Unordered glyph ids are stored in arrays rather than one segment each.
+	mapping := map[rune]uint16{}
+	for r := rune(0x4E00); r < 0x5E00; r++ {
+		mapping[r] = uint16(r*7919) % 5000
+	}
+	for r := rune('a'); r <= 'z'; r++ {
+		mapping[r] = uint16(r - 'a' + 100)
+	}
+	cmap := NewTableCmap(mapping)
+	if len(cmap.Subtables) != 2 || cmap.Subtables[0].Format != 4 {
+		t.Fatalf("Subtables = %v, want two records of a format 4 subtable", cmap.Subtables)
+	}
+	if size := len(cmap.Bytes()); size > 2*len(mapping)+100 {
+		t.Errorf("len(Bytes()) = %d, want at most %d", size, 2*len(mapping)+100)
+	}
+	for r, want := range mapping {
+		if gid, found := cmap.Lookup(r); gid != want || found != (want != 0) {
+			t.Errorf("Lookup(%U) = %d, %t; want %d", r, gid, found, want)
+		}
+	}
+}
+
+func TestCmapRebuild(t *testing.T) {
+	var buf bytes.Buffer
+	for _, v := range []interface{}{
+		uint16(0), uint16(1),
+		uint16(0), uint16(5), uint32(12),
+		// Format 14 subtable with one default variation sequence.
+		uint16(14), uint32(29), uint32(1),
+		[]byte{0x00, 0xFE, 0x0F}, uint32(21), uint32(0),
+		uint32(1), []byte{0x00, 0x26, 0x1D}, byte(0),
+	} {
+		if err := binary.Write(&buf, binary.BigEndian, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	table, err := parseTableCmap(TagCmap, buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmap := table.(*TableCmap).Rebuild(map[rune]uint16{0x261D: 1, 0x1F446: 2})
+	table, err = parseTableCmap(TagCmap, cmap.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var records []string
+	for _, s := range table.(*TableCmap).Subtables {
+		records = append(records, fmt.Sprintf("%d/%d:%d", s.PlatformID, s.EncodingID, s.Format))
+	}
+	if got, want := strings.Join(records, " "), "0/3:4 0/4:12 0/5:14 3/1:4 3/10:12"; got != want {
+		t.Errorf("subtables = %s, want %s", got, want)
+	}
+	if !bytes.Equal(table.(*TableCmap).Subtables[2].bytes, buf.Bytes()[12:]) {
+		t.Errorf("format 14 subtable was not copied")
+	}
+}
+
+func TestCmapSymbol(t *testing.T) {
+	table, err := parseTableCmap(TagCmap, newTableCmap(map[rune]uint16{0xF020: 1, 0xF041: 2, 0xF042: 3, 0x41: 4}, nil, true).Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmap := table.(*TableCmap)
+	if !cmap.IsSymbol() || cmap.Symbol() == nil {
+		t.Fatalf("IsSymbol() = false, want true")
+	}
+	for r, want := range map[rune]uint16{0xF020: 1, ' ': 1, 0xF041: 2, 'A': 4, 'B': 3} {
+		if gid, _ := cmap.Lookup(r); gid != want {
+			t.Errorf("Lookup(%U) = %d, want %d", r, gid, want)
+		}
+	}
+
+	rebuilt := cmap.Rebuild(map[rune]uint16{0xF041: 2, 'A': 2})
+	if len(rebuilt.Subtables) != 1 || !rebuilt.Subtables[0].IsSymbol() {
+		t.Fatalf("Rebuild() subtables = %v, want one symbol subtable", rebuilt.Subtables)
+	}
+	if mapping := rebuilt.Symbol().Mapping; len(mapping) != 1 || mapping[0xF041] != 2 {
+		t.Errorf("Rebuild() mapping = %v, want only U+F041", mapping)
+	}
+
+	if NewTableCmap(map[rune]uint16{'A': 1}).IsSymbol() {
+		t.Errorf("IsSymbol() of a Unicode cmap = true, want false")
+	}
+}
+
+func TestParseTableCmapFormat2(t *testing.T) {
+	keys := make([]uint16, 256)
+	keys[0x82] = 8
+	glyphs := make([]uint16, 257)
+	glyphs['A'] = 1
+	glyphs[256] = 2
+
+	var buf bytes.Buffer
+	for _, v := range []interface{}{
+		uint16(0), uint16(1),
+		uint16(3), uint16(2), uint32(12),
+		// Format 2 subtable for Shift-JIS, with 'A' and U+3042 (0x82A0).
+		uint16(2), uint16(1048), uint16(0), keys,
+		uint16(0), uint16(256), uint16(0), uint16(10),
+		uint16(0xA0), uint16(1), uint16(0), uint16(514),
+		glyphs,
+	} {
+		if err := binary.Write(&buf, binary.BigEndian, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	table, err := parseTableCmap(TagCmap, buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmap := table.(*TableCmap)
+	if mapping := cmap.Subtables[0].Mapping; len(mapping) != 2 || mapping['A'] != 1 || mapping[0x82A0] != 2 {
+		t.Errorf("format 2 mapping = %v, want 'A' and 0x82A0", mapping)
+	}
+	for r, want := range map[rune]uint16{'A': 1, 0x3042: 2} {
+		if gid, _ := cmap.Lookup(r); gid != want {
+			t.Errorf("Lookup(%U) = %d, want %d", r, gid, want)
+		}
+	}
+
+	if _, err := parseTableCmap(TagCmap, buf.Bytes()[:300]); err == nil {
+		t.Errorf("parseTableCmap(truncated) err = nil, want error")
+	}
+}
@@ -0,0 +1,65 @@
+package sfnt
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// TableCvar represents the 'cvar' table, which contains the changes to the
+// values in the 'cvt ' table across the design space of a variable font.
+// https://docs.microsoft.com/en-us/typography/opentype/spec/cvar
+type TableCvar struct {
+	baseTable
+
+	Variations TupleVariations
+}
+
+// CvarTable returns the table corresponding to the 'cvar' tag. The table is
+// parsed using the number of axes in the 'fvar' table.
+func (font *Font) CvarTable() (*TableCvar, error) {
+	s, found := font.section(TagCvar)
+	if !found {
+		return nil, ErrMissingTable
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cvar, ok := s.table.(*TableCvar); ok {
+		return cvar, nil
+	}
+
+	fvar, err := font.FvarTable()
+	if err != nil {
+		return nil, err
+	}
+	data, err := font.sectionData(s)
+	if err != nil {
+		return nil, err
+	}
+
+	cvar, err := parseTableCvar(data, len(fvar.Axes))
+	if err != nil {
+		return nil, err
+	}
+	s.table = cvar
+	return cvar, nil
+}
+
+func parseTableCvar(data []byte, axisCount int) (*TableCvar, error) {
+	if len(data) < 8 {
+		return nil, fmt.Errorf("reading cvar header: unexpected EOF")
+	}
+	if version := binary.BigEndian.Uint16(data); version != 1 {
+		return nil, fmt.Errorf("unsupported cvar version %d", version)
+	}
+	variations, err := parseTupleVariations(data, 4, axisCount, nil)
+	if err != nil {
+		return nil, fmt.Errorf("reading cvar: %s", err)
+	}
+	return &TableCvar{baseTable: baseTable(TagCvar), Variations: variations}, nil
+}
+
+// Bytes returns the byte representation of this table.
+func (table *TableCvar) Bytes() []byte {
+	return table.Variations.bytes(appendUint16s(nil, 1, 0))
+}
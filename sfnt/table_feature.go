@@ -50,6 +50,8 @@ func (s *Script) String() string {
 type LangSys struct {
 	Tag      Tag        // Tag for this language.
 	Features []*Feature // Features contains the features for this language.
+
+	required *Feature // required is the feature that is always applied for this language, or nil.
 }
 
 // String returns the name for this language.
@@ -59,7 +61,13 @@ func (l *LangSys) String() string {
 
 // Feature represents a glyph substitution or glyph positioning features.
 type Feature struct {
-	Tag Tag // Tag for this feature
+	Tag           Tag      // Tag for this feature
+	LookupIndices []uint16 // LookupIndices are the indices in Lookups of the lookups used by this feature.
+
+	// params contains the feature parameters, starting at the offset from the
+	// feature table. listParams starts at the same offset from the feature list,
+	// where some old fonts put the parameters of the 'size' feature.
+	params, listParams []byte
 }
 
 // Script returns the name for this feature.
@@ -86,8 +94,15 @@ func (f *Feature) String() string {
 type Lookup struct {
 	Type uint16 // Different enumerations for GSUB and GPOS.
 	Flag uint16 // Lookup qualifiers.
+
+	subtables        [][]byte // subtables contains the data of each subtable, starting at the subtable.
+	markFilteringSet uint16   // markFilteringSet is the index of the GDEF mark glyph set, if Flag has lookupUseMarkFilteringSet.
 }
 
+// lookupUseMarkFilteringSet is the bit of Lookup.Flag that is set when the lookup
+// only applies to the marks in one of GDEF's mark glyph sets.
+const lookupUseMarkFilteringSet = 0x0010
+
 // GSubString returns the Type as a readable entry.
 func (l Lookup) GSubString() string {
 	switch l.Type {
@@ -200,9 +215,15 @@ func (t *TableLayout) parseLangSys(b []byte, record langSysRecord) (*LangSys, er
 		features = append(features, t.Features[featureIndices[i]])
 	}
 
+	var required *Feature
+	if int(lang.RequiredFeatureIndex) < len(t.Features) {
+		required = t.Features[lang.RequiredFeatureIndex]
+	}
+
 	return &LangSys{
 		Tag:      record.Tag,
 		Features: features,
+		required: required,
 	}, nil
 }
 
@@ -306,11 +327,24 @@ func (t *TableLayout) parseFeature(b []byte, record featureRecord) (*Feature, er
 		return nil, fmt.Errorf("reading featureTable: %s", err)
 	}
 
-	// TODO Read feature.FeatureParams and feature.LookupIndexCount
+	indices := make([]uint16, feature.LookupIndexCount)
+	if err := binary.Read(r, binary.BigEndian, &indices); err != nil {
+		return nil, fmt.Errorf("reading featureTable: %s", err)
+	}
 
-	return &Feature{
-		Tag: record.Tag,
-	}, nil
+	f := &Feature{
+		Tag:           record.Tag,
+		LookupIndices: indices,
+	}
+	if feature.FeatureParams != 0 {
+		if offset := int(record.Offset) + int(feature.FeatureParams); offset < len(b) {
+			f.params = b[offset:]
+		}
+		if offset := int(feature.FeatureParams); offset < len(b) {
+			f.listParams = b[offset:]
+		}
+	}
+	return f, nil
 }
 
 // parseFeatureList parses the FeatureList.
@@ -367,15 +401,28 @@ func (t *TableLayout) parseLookup(b []byte, offset uint16) (*Lookup, error) {
 		return nil, fmt.Errorf("reading lookupRecord: %s", err)
 	}
 	lookup.subrecordOffsets = subs
-	// reading of lookup record is complete at this spot
-	// by converting it into a Lookup we lose information about sub-tables' location
 
-	// TODO Read lookup.Subtable
-	// TODO Read lookup.MarkFilteringSet
+	var markFilteringSet uint16
+	if lookup.Flag&lookupUseMarkFilteringSet != 0 {
+		if err := binary.Read(r, binary.BigEndian, &markFilteringSet); err != nil {
+			return nil, fmt.Errorf("reading lookup markFilteringSet: %s", err)
+		}
+	}
+
+	var subtables [][]byte
+	for _, sub := range subs {
+		start := int(offset) + int(sub)
+		if start >= len(b) {
+			return nil, fmt.Errorf("reading lookup subtable: %s", io.ErrUnexpectedEOF)
+		}
+		subtables = append(subtables, b[start:])
+	}
 
 	return &Lookup{
-		Type: lookup.Type,
-		Flag: lookup.Flag, // TODO Parse the type Enum
+		Type:             lookup.Type,
+		Flag:             lookup.Flag, // TODO Parse the type Enum
+		subtables:        subtables,
+		markFilteringSet: markFilteringSet,
 	}, nil
 }
 
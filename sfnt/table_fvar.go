@@ -0,0 +1,158 @@
+package sfnt
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// TableFvar represents the 'fvar' table, which lists the axes along which a
+// variable font can vary, and its named instances.
+// https://docs.microsoft.com/en-us/typography/opentype/spec/fvar
+type TableFvar struct {
+	baseTable
+
+	Axes      []VariationAxis
+	Instances []NamedInstance
+}
+
+// VariationAxis is an axis along which a variable font can vary.
+type VariationAxis struct {
+	Tag     Tag // Tag identifies the axis, for example 'wght' or 'wdth'.
+	Min     float64
+	Default float64
+	Max     float64
+	Flags   uint16 // Flags is 1 if the axis should be hidden from users.
+	NameID  NameID // NameID is the entry in the 'name' table containing the name of the axis.
+}
+
+// Hidden returns true if the axis should not be shown to users.
+func (axis VariationAxis) Hidden() bool {
+	return axis.Flags&1 != 0
+}
+
+// Normalize converts a coordinate on the axis to the normalized range used by
+// variation data, where Min is -1, Default is 0 and Max is 1. Values outside the
+// axis are clamped. It does not apply the mapping in the 'avar' table.
+func (axis VariationAxis) Normalize(v float64) float64 {
+	switch {
+	case v < axis.Default && axis.Min < axis.Default:
+		return math.Max(-1, (v-axis.Default)/(axis.Default-axis.Min))
+	case v > axis.Default && axis.Max > axis.Default:
+		return math.Min(1, (v-axis.Default)/(axis.Max-axis.Default))
+	}
+	return 0
+}
+
+// NamedInstance is a named position in the design space of a variable font.
+type NamedInstance struct {
+	SubfamilyNameID NameID
+	Flags           uint16
+
+	// PostScriptNameID is the entry in the 'name' table containing the PostScript
+	// name of the instance, or 0xFFFF if it does not have one.
+	PostScriptNameID NameID
+
+	// Coordinates is the position of the instance on each axis, in the order of Axes.
+	Coordinates []float64
+}
+
+const (
+	fvarHeaderLength = 16
+	fvarAxisLength   = 20
+)
+
+func parseTableFvar(tag Tag, buf []byte) (Table, error) {
+	if len(buf) < fvarHeaderLength {
+		return nil, fmt.Errorf("reading fvar header: unexpected EOF")
+	}
+	axesOffset := int(binary.BigEndian.Uint16(buf[4:]))
+	axisCount := int(binary.BigEndian.Uint16(buf[8:]))
+	axisSize := int(binary.BigEndian.Uint16(buf[10:]))
+	instanceCount := int(binary.BigEndian.Uint16(buf[12:]))
+	instanceSize := int(binary.BigEndian.Uint16(buf[14:]))
+
+	if axisSize < fvarAxisLength || instanceSize < 4+4*axisCount {
+		return nil, fmt.Errorf("invalid fvar record sizes %d and %d", axisSize, instanceSize)
+	}
+	end := axesOffset + axisCount*axisSize + instanceCount*instanceSize
+	if end > len(buf) {
+		return nil, fmt.Errorf("reading %d axes and %d instances: unexpected EOF", axisCount, instanceCount)
+	}
+
+	table := &TableFvar{baseTable: baseTable(tag)}
+	for i := 0; i < axisCount; i++ {
+		b := buf[axesOffset+i*axisSize:]
+		table.Axes = append(table.Axes, VariationAxis{
+			Tag:     NewTag(b),
+			Min:     fixedToFloat(binary.BigEndian.Uint32(b[4:])),
+			Default: fixedToFloat(binary.BigEndian.Uint32(b[8:])),
+			Max:     fixedToFloat(binary.BigEndian.Uint32(b[12:])),
+			Flags:   binary.BigEndian.Uint16(b[16:]),
+			NameID:  NameID(binary.BigEndian.Uint16(b[18:])),
+		})
+	}
+
+	instancesOffset := axesOffset + axisCount*axisSize
+	for i := 0; i < instanceCount; i++ {
+		b := buf[instancesOffset+i*instanceSize:]
+		instance := NamedInstance{
+			SubfamilyNameID:  NameID(binary.BigEndian.Uint16(b)),
+			Flags:            binary.BigEndian.Uint16(b[2:]),
+			PostScriptNameID: 0xFFFF,
+			Coordinates:      make([]float64, axisCount),
+		}
+		for j := range instance.Coordinates {
+			instance.Coordinates[j] = fixedToFloat(binary.BigEndian.Uint32(b[4+4*j:]))
+		}
+		if instanceSize >= 6+4*axisCount {
+			instance.PostScriptNameID = NameID(binary.BigEndian.Uint16(b[4+4*axisCount:]))
+		}
+		table.Instances = append(table.Instances, instance)
+	}
+
+	return table, nil
+}
+
+// Bytes returns the byte representation of this table.
+func (table *TableFvar) Bytes() []byte {
+	// The PostScript names are only written if an instance has one.
+	instanceSize := 4 + 4*len(table.Axes)
+	for _, instance := range table.Instances {
+		if instance.PostScriptNameID != 0xFFFF {
+			instanceSize += 2
+			break
+		}
+	}
+
+	buf := make([]byte, 0, fvarHeaderLength+fvarAxisLength*len(table.Axes)+instanceSize*len(table.Instances))
+	buf = appendUint16s(buf, 1, 0, fvarHeaderLength, 2, uint16(len(table.Axes)), fvarAxisLength, uint16(len(table.Instances)), uint16(instanceSize))
+	for _, axis := range table.Axes {
+		buf = appendUint32s(buf, axis.Tag.Number, floatToFixed(axis.Min), floatToFixed(axis.Default), floatToFixed(axis.Max))
+		buf = appendUint16s(buf, axis.Flags, uint16(axis.NameID))
+	}
+	for _, instance := range table.Instances {
+		buf = appendUint16s(buf, uint16(instance.SubfamilyNameID), instance.Flags)
+		for i := range table.Axes {
+			var coordinate float64
+			if i < len(instance.Coordinates) {
+				coordinate = instance.Coordinates[i]
+			}
+			buf = appendUint32s(buf, floatToFixed(coordinate))
+		}
+		if instanceSize > 4+4*len(table.Axes) {
+			buf = appendUint16s(buf, uint16(instance.PostScriptNameID))
+		}
+	}
+	return buf
+}
+
+// fixedToFloat converts a 16.16 fixed point number to a float.
+func fixedToFloat(v uint32) float64 {
+	return float64(int32(v)) / 0x10000
+}
+
+// floatToFixed converts a float to a 16.16 fixed point number.
+func floatToFixed(f float64) uint32 {
+	return uint32(int32(math.Round(f * 0x10000)))
+}
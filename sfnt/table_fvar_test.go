@@ -0,0 +1,35 @@
+package sfnt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFvarRoundTrip(t *testing.T) {
+	want := &TableFvar{
+		baseTable: baseTable(TagFvar),
+		Axes: []VariationAxis{
+			{Tag: MustNamedTag("wght"), Min: 100, Default: 400, Max: 900, NameID: 256},
+			{Tag: MustNamedTag("slnt"), Min: -12.5, Default: 0, Max: 0, Flags: 1, NameID: 257},
+		},
+		Instances: []NamedInstance{
+			{SubfamilyNameID: 258, PostScriptNameID: 0xFFFF, Coordinates: []float64{400, 0}},
+			{SubfamilyNameID: 259, PostScriptNameID: 260, Coordinates: []float64{700, -12.5}},
+		},
+	}
+
+	got, err := parseTableFvar(TagFvar, want.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseTableFvar(Bytes()) = %+v, want %+v", got, want)
+	}
+	if !want.Axes[1].Hidden() || want.Axes[0].Hidden() {
+		t.Errorf("Hidden() = %v, %v, want false, true", want.Axes[0].Hidden(), want.Axes[1].Hidden())
+	}
+
+	if _, err := parseTableFvar(TagFvar, want.Bytes()[:30]); err == nil {
+		t.Errorf("parseTableFvar(truncated) err = nil, want an error")
+	}
+}
@@ -0,0 +1,130 @@
+package sfnt
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+// TableGasp represents the 'gasp' table, which contains the rendering that the
+// font's designer recommends for each range of sizes, for example to turn off
+// hinting at small sizes where it distorts the glyphs.
+//
+// See https://docs.microsoft.com/en-us/typography/opentype/spec/gasp
+type TableGasp struct {
+	baseTable
+
+	Version uint16      // Version is 0, or 1 if the table has the symmetric behaviors.
+	Ranges  []GaspRange // Ranges are in increasing order of MaxPPEM, the last usually 0xFFFF.
+}
+
+// GaspRange is the behavior recommended for the sizes up to MaxPPEM.
+type GaspRange struct {
+	MaxPPEM  uint16 // MaxPPEM is the largest size in pixels per em to which the range applies.
+	Behavior GaspBehavior
+}
+
+// GaspBehavior is a set of flags describing how glyphs should be rendered.
+type GaspBehavior uint16
+
+const (
+	// GaspGridfit recommends grid-fitting (hinting) the glyphs.
+	GaspGridfit GaspBehavior = 0x0001
+	// GaspDoGray recommends grayscale antialiasing.
+	GaspDoGray GaspBehavior = 0x0002
+	// GaspSymmetricGridfit recommends grid-fitting with ClearType (version 1).
+	GaspSymmetricGridfit GaspBehavior = 0x0004
+	// GaspSymmetricSmoothing recommends smoothing along the multiple axes of
+	// ClearType (version 1).
+	GaspSymmetricSmoothing GaspBehavior = 0x0008
+)
+
+var gaspBehaviorNames = []struct {
+	flag GaspBehavior
+	name string
+}{
+	{GaspGridfit, "gridfit"},
+	{GaspDoGray, "antialias"},
+	{GaspSymmetricGridfit, "symmetric gridfit"},
+	{GaspSymmetricSmoothing, "symmetric smoothing"},
+}
+
+// GridFit returns true if grid-fitting is recommended.
+func (b GaspBehavior) GridFit() bool {
+	return b&GaspGridfit != 0
+}
+
+// Antialias returns true if grayscale antialiasing is recommended.
+func (b GaspBehavior) Antialias() bool {
+	return b&GaspDoGray != 0
+}
+
+// String returns the names of the flags, for example "gridfit, antialias".
+func (b GaspBehavior) String() string {
+	var names []string
+	for _, n := range gaspBehaviorNames {
+		if b&n.flag != 0 {
+			names = append(names, n.name)
+			b &^= n.flag
+		}
+	}
+	if b != 0 {
+		names = append(names, fmt.Sprintf("0x%04X", uint16(b)))
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ", ")
+}
+
+// Behavior returns the rendering recommended at the given size in pixels per
+// em. Sizes above the last range, which the specification does not allow, use
+// the behavior of the last range. It returns 0 if the table has no ranges.
+func (t *TableGasp) Behavior(ppem uint16) GaspBehavior {
+	for _, r := range t.Ranges {
+		if ppem <= r.MaxPPEM {
+			return r.Behavior
+		}
+	}
+	if len(t.Ranges) == 0 {
+		return 0
+	}
+	return t.Ranges[len(t.Ranges)-1].Behavior
+}
+
+// Bytes returns the byte representation of this table.
+func (t *TableGasp) Bytes() []byte {
+	buf := make([]byte, 4+4*len(t.Ranges))
+	binary.BigEndian.PutUint16(buf, t.Version)
+	binary.BigEndian.PutUint16(buf[2:], uint16(len(t.Ranges)))
+	for i, r := range t.Ranges {
+		binary.BigEndian.PutUint16(buf[4+4*i:], r.MaxPPEM)
+		binary.BigEndian.PutUint16(buf[6+4*i:], uint16(r.Behavior))
+	}
+	return buf
+}
+
+func parseTableGasp(tag Tag, buf []byte) (Table, error) {
+	if len(buf) < 4 {
+		return nil, fmt.Errorf("reading gasp header: unexpected EOF")
+	}
+	table := &TableGasp{baseTable: baseTable(tag), Version: binary.BigEndian.Uint16(buf)}
+	if table.Version > 1 {
+		return nil, fmt.Errorf("unsupported gasp version %d", table.Version)
+	}
+	numRanges := int(binary.BigEndian.Uint16(buf[2:]))
+	if len(buf) < 4+4*numRanges {
+		return nil, fmt.Errorf("reading %d gasp ranges: unexpected EOF", numRanges)
+	}
+	for i := 0; i < numRanges; i++ {
+		r := GaspRange{
+			MaxPPEM:  binary.BigEndian.Uint16(buf[4+4*i:]),
+			Behavior: GaspBehavior(binary.BigEndian.Uint16(buf[6+4*i:])),
+		}
+		if i > 0 && r.MaxPPEM <= table.Ranges[i-1].MaxPPEM {
+			return nil, fmt.Errorf("gasp ranges are not in increasing order")
+		}
+		table.Ranges = append(table.Ranges, r)
+	}
+	return table, nil
+}
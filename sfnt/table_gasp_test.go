@@ -0,0 +1,54 @@
+package sfnt
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestParseTableGasp(t *testing.T) {
+	buf := writeBigEndian(t,
+		uint16(1), uint16(3), // version, numRanges
+		uint16(8), uint16(0x000A), // symmetric smoothing and antialiasing, no hinting
+		uint16(16), uint16(0x0007), // hinting and antialiasing
+		uint16(0xFFFF), uint16(0x000F),
+	)
+
+	table, err := parseTableGasp(TagGasp, buf)
+	if err != nil {
+		t.Fatalf("parseTableGasp() err = %q, want nil", err)
+	}
+	gasp := table.(*TableGasp)
+	for _, test := range []struct {
+		ppem               uint16
+		gridFit, antialias bool
+		behavior           string
+	}{
+		{6, false, true, "antialias, symmetric smoothing"},
+		{8, false, true, "antialias, symmetric smoothing"},
+		{12, true, true, "gridfit, antialias, symmetric gridfit"},
+		{100, true, true, "gridfit, antialias, symmetric gridfit, symmetric smoothing"},
+	} {
+		b := gasp.Behavior(test.ppem)
+		if b.GridFit() != test.gridFit || b.Antialias() != test.antialias || b.String() != test.behavior {
+			t.Errorf("Behavior(%d) = %s, want %s", test.ppem, b, test.behavior)
+		}
+	}
+	if !bytes.Equal(gasp.Bytes(), buf) {
+		t.Errorf("Bytes() = %x, want %x", gasp.Bytes(), buf)
+	}
+
+	if _, err := parseTableGasp(TagGasp, buf[:10]); err == nil {
+		t.Errorf("parseTableGasp() of a truncated table err = nil, want an error")
+	}
+}
+
+func TestGaspTable(t *testing.T) {
+	font := parseTestFont(t, "open-sans-v15-latin-regular.woff")
+	gasp, err := font.GaspTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gasp.Ranges) == 0 || gasp.Ranges[len(gasp.Ranges)-1].MaxPPEM != 0xFFFF {
+		t.Errorf("GaspTable().Ranges = %+v, want the last to end at 0xFFFF", gasp.Ranges)
+	}
+}
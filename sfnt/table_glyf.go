@@ -0,0 +1,255 @@
+package sfnt
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Flags used by the components of a composite glyph.
+const (
+	glyfArg1And2AreWords   = 0x0001
+	glyfWeHaveAScale       = 0x0008
+	glyfMoreComponents     = 0x0020
+	glyfWeHaveAnXAndYScale = 0x0040
+	glyfWeHaveATwoByTwo    = 0x0080
+)
+
+var errInvalidGlyph = errors.New("invalid glyph data")
+
+// TableGlyf represents the 'glyf' table, which contains the TrueType outline of each glyph.
+// It is parsed together with the 'loca' table, which contains the location of each glyph.
+// https://docs.microsoft.com/en-us/typography/opentype/spec/glyf
+type TableGlyf struct {
+	baseTable
+
+	// Glyphs contains the raw data for each glyph, indexed by glyph id.
+	// Glyphs with no outline (such as space) have no data.
+	Glyphs [][]byte
+
+	locaFormat int16 // locaFormat is the indexToLocFormat in the font's 'head' table.
+}
+
+// TableLoca represents the 'loca' table, which contains the offset of each glyph
+// in the 'glyf' table. It is generated from the 'glyf' table when the font is written.
+// https://docs.microsoft.com/en-us/typography/opentype/spec/loca
+type TableLoca struct {
+	baseTable
+
+	glyf *TableGlyf
+}
+
+// GlyfTable returns the table corresponding to the 'glyf' tag. The 'loca' table
+// is replaced by one that is kept up to date with the returned table.
+func (font *Font) GlyfTable() (*TableGlyf, error) {
+	s, found := font.section(TagGlyf)
+	if !found {
+		return nil, ErrMissingTable
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if glyf, ok := s.table.(*TableGlyf); ok {
+		return glyf, nil
+	}
+
+	glyf, err := font.parseGlyf(s)
+	if err != nil {
+		return nil, err
+	}
+	s.table = glyf
+	font.AddTable(TagLoca, &TableLoca{baseTable: baseTable(TagLoca), glyf: glyf})
+	return glyf, nil
+}
+
+// parseGlyf parses the 'glyf' table in s, without adding it (or the 'loca'
+// table that goes with it) to the font. The caller must hold s.mu.
+func (font *Font) parseGlyf(s *tableSection) (*TableGlyf, error) {
+	head, err := font.HeadTable()
+	if err != nil {
+		return nil, err
+	}
+	loca, err := font.TableData(TagLoca)
+	if err != nil {
+		return nil, err
+	}
+	t, err := font.parseTableWith(s, func(tag Tag, data []byte) (Table, error) {
+		return parseTableGlyf(data, loca, head.IndexToLocFormat)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return t.(*TableGlyf), nil
+}
+
+func parseTableGlyf(data, loca []byte, locaFormat int16) (*TableGlyf, error) {
+	var offsets []uint32
+	switch locaFormat {
+	case 0:
+		for i := 0; i+2 <= len(loca); i += 2 {
+			offsets = append(offsets, uint32(binary.BigEndian.Uint16(loca[i:]))*2)
+		}
+	case 1:
+		for i := 0; i+4 <= len(loca); i += 4 {
+			offsets = append(offsets, binary.BigEndian.Uint32(loca[i:]))
+		}
+	default:
+		return nil, fmt.Errorf("unsupported indexToLocFormat %d", locaFormat)
+	}
+	if len(offsets) == 0 {
+		// WOFF2 fonts usually store a transformed 'glyf' table with an empty 'loca' table.
+		return nil, fmt.Errorf("empty 'loca' table (transformed WOFF2 glyphs are not supported)")
+	}
+
+	glyphs := make([][]byte, len(offsets)-1)
+	for i := range glyphs {
+		start, end := offsets[i], offsets[i+1]
+		if start > end || int(end) > len(data) {
+			return nil, fmt.Errorf("invalid location for glyph %d: %d-%d", i, start, end)
+		}
+		if start < end {
+			glyphs[i] = data[start:end]
+		}
+	}
+
+	return &TableGlyf{
+		baseTable:  baseTable(TagGlyf),
+		Glyphs:     glyphs,
+		locaFormat: locaFormat,
+	}, nil
+}
+
+// NumGlyphs returns the number of glyphs in the table.
+func (table *TableGlyf) NumGlyphs() int {
+	return len(table.Glyphs)
+}
+
+// IsComposite returns true if the glyph is made up of other glyphs.
+func (table *TableGlyf) IsComposite(gid uint16) bool {
+	if int(gid) >= len(table.Glyphs) || len(table.Glyphs[gid]) < 10 {
+		return false
+	}
+	return int16(binary.BigEndian.Uint16(table.Glyphs[gid])) < 0
+}
+
+// Components returns the ids of the glyphs that a composite glyph refers to,
+// or nil if the glyph is not a composite glyph.
+func (table *TableGlyf) Components(gid uint16) ([]uint16, error) {
+	if !table.IsComposite(gid) {
+		return nil, nil
+	}
+
+	data := table.Glyphs[gid][10:]
+	var components []uint16
+	for {
+		if len(data) < 4 {
+			return nil, fmt.Errorf("glyph %d: %s", gid, errInvalidGlyph)
+		}
+		flags := binary.BigEndian.Uint16(data)
+		components = append(components, binary.BigEndian.Uint16(data[2:]))
+
+		size := 4 + 2
+		if flags&glyfArg1And2AreWords != 0 {
+			size = 4 + 4
+		}
+		switch {
+		case flags&glyfWeHaveAScale != 0:
+			size += 2
+		case flags&glyfWeHaveAnXAndYScale != 0:
+			size += 4
+		case flags&glyfWeHaveATwoByTwo != 0:
+			size += 8
+		}
+		if len(data) < size {
+			return nil, fmt.Errorf("glyph %d: %s", gid, errInvalidGlyph)
+		}
+		data = data[size:]
+
+		if flags&glyfMoreComponents == 0 {
+			return components, nil
+		}
+	}
+}
+
+// maxShortLocaOffset is the largest offset that a short 'loca' table can store,
+// as it stores offsets divided by two in 16 bits.
+const maxShortLocaOffset = 2 * 0xFFFF
+
+// IndexToLocFormat returns the format of the 'loca' table written for the
+// table: 0 for short offsets if all of the glyphs fit in 128KB when aligned to
+// two bytes, and otherwise 1 for long offsets. Until the font is written, its
+// 'glyf' and 'loca' tables keep the format in its 'head' table, and the 'head'
+// table that is written is updated to match.
+func (table *TableGlyf) IndexToLocFormat() int16 {
+	size := 0
+	for _, glyph := range table.Glyphs {
+		size += (len(glyph) + 1) &^ 1
+	}
+	if size <= maxShortLocaOffset {
+		return 0
+	}
+	return 1
+}
+
+// storedLocaFormat returns the format of the 'loca' table that goes with the
+// table's bytes: the format in the font's 'head' table, unless the glyphs no
+// longer fit in short offsets.
+func (table *TableGlyf) storedLocaFormat() int16 {
+	if table.locaFormat == 0 && table.IndexToLocFormat() != 0 {
+		return 1
+	}
+	return table.locaFormat
+}
+
+// withLocaFormat returns copies of the table and its 'loca' table that are
+// stored with the given format.
+func (table *TableGlyf) withLocaFormat(format int16) (*TableGlyf, *TableLoca) {
+	glyf := *table
+	glyf.locaFormat = format
+	return &glyf, &TableLoca{baseTable: baseTable(TagLoca), glyf: &glyf}
+}
+
+// padding returns the alignment of each glyph when the table is written.
+func (table *TableGlyf) padding() int {
+	if table.storedLocaFormat() == 0 {
+		return 2
+	}
+	return 4
+}
+
+// Bytes returns the byte representation of this table.
+func (table *TableGlyf) Bytes() []byte {
+	padding := table.padding()
+
+	var buf []byte
+	for _, glyph := range table.Glyphs {
+		buf = append(buf, glyph...)
+		for len(buf)%padding != 0 {
+			buf = append(buf, 0)
+		}
+	}
+	return buf
+}
+
+// Bytes returns the byte representation of this table.
+func (table *TableLoca) Bytes() []byte {
+	padding := table.glyf.padding()
+	format := table.glyf.storedLocaFormat()
+
+	var buf []byte
+	offset := 0
+	write := func() {
+		if format == 0 {
+			buf = append(buf, byte(offset>>9), byte(offset>>1))
+		} else {
+			buf = append(buf, byte(offset>>24), byte(offset>>16), byte(offset>>8), byte(offset))
+		}
+	}
+
+	for _, glyph := range table.glyf.Glyphs {
+		write()
+		offset += (len(glyph) + padding - 1) / padding * padding
+	}
+	write()
+	return buf
+}
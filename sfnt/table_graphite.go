@@ -0,0 +1,395 @@
+package sfnt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// The SIL Graphite tables are used instead of GSUB/GPOS by fonts for some
+// minority scripts. Only enough is parsed to enumerate the features a font
+// offers and the attributes attached to each glyph, the rules in the Silf
+// table are left alone.
+//
+// See https://github.com/silnrsi/graphite/blob/master/doc/TTFTables.md (formerly
+// http://scripts.sil.org/cms/scripts/page.php?site_id=projects&item_id=graphite_fontdemos)
+
+// TableFeat represents the Graphite 'Feat' table, which lists the features
+// that can be selected when shaping with the font.
+type TableFeat struct {
+	baseTable
+
+	bytes []byte
+
+	Version  fixed
+	Features []*GraphiteFeature
+}
+
+// Bytes returns the bytes for this table. The TableFeat is read only, so
+// the bytes will always be the same as what is read in.
+func (t *TableFeat) Bytes() []byte {
+	return t.bytes
+}
+
+// GraphiteFeature is a single feature defined in the Graphite 'Feat' table.
+type GraphiteFeature struct {
+	ID       uint32 // ID of the feature, usually a four byte tag.
+	Flags    uint16
+	Label    NameID // Label is the 'name' table entry that describes this feature.
+	Settings []GraphiteFeatureSetting
+}
+
+// Tag returns the ID of this feature as a tag.
+func (f *GraphiteFeature) Tag() Tag {
+	return Tag{f.ID}
+}
+
+// GraphiteFeatureSetting is one of the values a Graphite feature can take.
+type GraphiteFeatureSetting struct {
+	Value int16
+	Label NameID // Label is the 'name' table entry that describes this setting.
+}
+
+type featHeader struct {
+	Version   fixed
+	NumFeat   uint16
+	Reserved1 uint16
+	Reserved2 uint32
+}
+
+// featDefn1 is the on-disk format of a FeatureDefn in version 1 of the 'Feat' table.
+type featDefn1 struct {
+	ID          uint16
+	NumSettings uint16
+	Offset      uint32 // offset to the FeatureSettingDefn array, from beginning of Feat table.
+	Flags       uint16
+	Label       NameID
+}
+
+// featDefn2 is the on-disk format of a FeatureDefn in version 2 and later of the 'Feat' table.
+type featDefn2 struct {
+	ID          uint32
+	NumSettings uint16
+	Reserved    uint16
+	Offset      uint32 // offset to the FeatureSettingDefn array, from beginning of Feat table.
+	Flags       uint16
+	Label       NameID
+}
+
+func parseTableFeat(tag Tag, buf []byte) (Table, error) {
+	t := &TableFeat{
+		baseTable: baseTable(tag),
+		bytes:     buf,
+	}
+
+	r := bytes.NewReader(buf)
+
+	var header featHeader
+	if err := binary.Read(r, binary.BigEndian, &header); err != nil {
+		return nil, fmt.Errorf("reading Feat header: %s", err)
+	}
+	t.Version = header.Version
+
+	for i := 0; i < int(header.NumFeat); i++ {
+		var defn featDefn2
+		if header.Version.Major < 2 {
+			var defn1 featDefn1
+			if err := binary.Read(r, binary.BigEndian, &defn1); err != nil {
+				return nil, fmt.Errorf("reading featureDefn[%d]: %s", i, err)
+			}
+			defn = featDefn2{
+				ID:          uint32(defn1.ID),
+				NumSettings: defn1.NumSettings,
+				Offset:      defn1.Offset,
+				Flags:       defn1.Flags,
+				Label:       defn1.Label,
+			}
+		} else if err := binary.Read(r, binary.BigEndian, &defn); err != nil {
+			return nil, fmt.Errorf("reading featureDefn[%d]: %s", i, err)
+		}
+
+		if int64(defn.Offset) >= int64(len(buf)) {
+			return nil, io.ErrUnexpectedEOF
+		}
+
+		settings := make([]GraphiteFeatureSetting, defn.NumSettings)
+		if err := binary.Read(bytes.NewReader(buf[defn.Offset:]), binary.BigEndian, &settings); err != nil {
+			return nil, fmt.Errorf("reading featureSettingDefn[%d]: %s", defn.NumSettings, err)
+		}
+
+		t.Features = append(t.Features, &GraphiteFeature{
+			ID:       defn.ID,
+			Flags:    defn.Flags,
+			Label:    defn.Label,
+			Settings: settings,
+		})
+	}
+
+	return t, nil
+}
+
+// TableGloc represents the Graphite 'Gloc' table, which contains the location
+// of each glyph's attributes within the 'Glat' table.
+type TableGloc struct {
+	baseTable
+
+	bytes []byte
+
+	Version      fixed
+	Locations    []uint32 // Locations has one more entry than there are glyphs.
+	AttributeIDs []uint16 // AttributeIDs are 'name' table entries for each attribute (may be empty).
+}
+
+// Bytes returns the bytes for this table. The TableGloc is read only, so
+// the bytes will always be the same as what is read in.
+func (t *TableGloc) Bytes() []byte {
+	return t.bytes
+}
+
+type glocHeader struct {
+	Version    fixed
+	Flags      uint16
+	NumAttribs uint16
+}
+
+const (
+	glocLongFormat     = 0x1
+	glocAttributeNames = 0x2
+)
+
+func parseTableGloc(tag Tag, buf []byte) (Table, error) {
+	t := &TableGloc{
+		baseTable: baseTable(tag),
+		bytes:     buf,
+	}
+
+	r := bytes.NewReader(buf)
+
+	var header glocHeader
+	if err := binary.Read(r, binary.BigEndian, &header); err != nil {
+		return nil, fmt.Errorf("reading Gloc header: %s", err)
+	}
+	t.Version = header.Version
+
+	size := len(buf) - binary.Size(header)
+	if header.Flags&glocAttributeNames != 0 {
+		size -= 2 * int(header.NumAttribs)
+	}
+
+	if header.Flags&glocLongFormat != 0 {
+		if size < 0 || size%4 != 0 {
+			return nil, fmt.Errorf("invalid Gloc length %d", len(buf))
+		}
+		t.Locations = make([]uint32, size/4)
+		if err := binary.Read(r, binary.BigEndian, &t.Locations); err != nil {
+			return nil, fmt.Errorf("reading locations: %s", err)
+		}
+	} else {
+		if size < 0 || size%2 != 0 {
+			return nil, fmt.Errorf("invalid Gloc length %d", len(buf))
+		}
+		locations := make([]uint16, size/2)
+		if err := binary.Read(r, binary.BigEndian, &locations); err != nil {
+			return nil, fmt.Errorf("reading locations: %s", err)
+		}
+		t.Locations = make([]uint32, len(locations))
+		for i, l := range locations {
+			t.Locations[i] = uint32(l)
+		}
+	}
+
+	if header.Flags&glocAttributeNames != 0 {
+		t.AttributeIDs = make([]uint16, header.NumAttribs)
+		if err := binary.Read(r, binary.BigEndian, &t.AttributeIDs); err != nil {
+			return nil, fmt.Errorf("reading attribIds: %s", err)
+		}
+	}
+
+	return t, nil
+}
+
+// NumGlyphs returns the number of glyphs with locations in this table.
+func (t *TableGloc) NumGlyphs() int {
+	if len(t.Locations) == 0 {
+		return 0
+	}
+	return len(t.Locations) - 1
+}
+
+// TableGlat represents the Graphite 'Glat' table, which contains the attributes
+// for each glyph. The attributes can be read using the locations in the 'Gloc' table.
+type TableGlat struct {
+	baseTable
+
+	bytes []byte
+
+	Version     fixed
+	Compression uint32 // Compression is only present in version 3 and later.
+}
+
+// Bytes returns the bytes for this table. The TableGlat is read only, so
+// the bytes will always be the same as what is read in.
+func (t *TableGlat) Bytes() []byte {
+	return t.bytes
+}
+
+const glatOctaboxes = 0x1
+
+func parseTableGlat(tag Tag, buf []byte) (Table, error) {
+	t := &TableGlat{
+		baseTable: baseTable(tag),
+		bytes:     buf,
+	}
+
+	r := bytes.NewReader(buf)
+	if err := binary.Read(r, binary.BigEndian, &t.Version); err != nil {
+		return nil, fmt.Errorf("reading Glat version: %s", err)
+	}
+
+	if t.Version.Major >= 3 {
+		if err := binary.Read(r, binary.BigEndian, &t.Compression); err != nil {
+			return nil, fmt.Errorf("reading Glat compression: %s", err)
+		}
+		if t.Compression>>27 != 0 {
+			return nil, fmt.Errorf("unsupported Glat compression scheme %d", t.Compression>>27)
+		}
+	}
+
+	return t, nil
+}
+
+// GlyphAttributes returns the attributes of the given glyph, mapped from
+// attribute number to value. The gloc table must come from the same font.
+func (t *TableGlat) GlyphAttributes(gloc *TableGloc, glyph uint16) (map[uint16]int16, error) {
+	if int(glyph) >= gloc.NumGlyphs() {
+		return nil, fmt.Errorf("glyph %d not in Gloc table", glyph)
+	}
+
+	start, end := int64(gloc.Locations[glyph]), int64(gloc.Locations[glyph+1])
+	if start > end || end > int64(len(t.bytes)) {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	r := bytes.NewReader(t.bytes[start:end])
+	if t.Version.Major >= 3 && t.Compression&glatOctaboxes != 0 {
+		// TODO Expose the octabox metrics rather than skipping them.
+		var bitmap uint16
+		if err := binary.Read(r, binary.BigEndian, &bitmap); err != nil {
+			return nil, fmt.Errorf("reading octabox bitmap: %s", err)
+		}
+		subboxes := 0
+		for ; bitmap != 0; bitmap &= bitmap - 1 {
+			subboxes++
+		}
+		if _, err := r.Seek(int64(4+8*subboxes), io.SeekCurrent); err != nil {
+			return nil, err
+		}
+	}
+
+	attributes := make(map[uint16]int16)
+	for r.Len() > 0 {
+		var attNum, num uint16
+		if t.Version.Major < 2 {
+			var run [2]uint8
+			if err := binary.Read(r, binary.BigEndian, &run); err != nil {
+				return nil, fmt.Errorf("reading attribute run: %s", err)
+			}
+			attNum, num = uint16(run[0]), uint16(run[1])
+		} else {
+			var run [2]uint16
+			if err := binary.Read(r, binary.BigEndian, &run); err != nil {
+				return nil, fmt.Errorf("reading attribute run: %s", err)
+			}
+			attNum, num = run[0], run[1]
+		}
+
+		values := make([]int16, num)
+		if err := binary.Read(r, binary.BigEndian, &values); err != nil {
+			return nil, fmt.Errorf("reading attributes[%d]: %s", num, err)
+		}
+		for i, v := range values {
+			attributes[attNum+uint16(i)] = v
+		}
+	}
+
+	return attributes, nil
+}
+
+// TableSilf represents the Graphite 'Silf' table, which contains the
+// shaping rules. Only the header of each sub-table is parsed.
+type TableSilf struct {
+	baseTable
+
+	bytes []byte
+
+	Version         fixed
+	CompilerVersion uint32 // CompilerVersion is only present in version 3 and later.
+	Subtables       []*SilfSubtable
+}
+
+// Bytes returns the bytes for this table. The TableSilf is read only, so
+// the bytes will always be the same as what is read in.
+func (t *TableSilf) Bytes() []byte {
+	return t.bytes
+}
+
+// SilfSubtable contains the summary information for one set of Graphite rules.
+// This matches the on-disk format of the start of a version 2 sub-table.
+type SilfSubtable struct {
+	MaxGlyphID   uint16
+	ExtraAscent  int16
+	ExtraDescent int16
+	NumPasses    uint8
+}
+
+func parseTableSilf(tag Tag, buf []byte) (Table, error) {
+	t := &TableSilf{
+		baseTable: baseTable(tag),
+		bytes:     buf,
+	}
+
+	r := bytes.NewReader(buf)
+	if err := binary.Read(r, binary.BigEndian, &t.Version); err != nil {
+		return nil, fmt.Errorf("reading Silf version: %s", err)
+	}
+
+	if t.Version.Major >= 3 {
+		if err := binary.Read(r, binary.BigEndian, &t.CompilerVersion); err != nil {
+			return nil, fmt.Errorf("reading Silf compilerVersion: %s", err)
+		}
+	}
+
+	var count [2]uint16 // numSub, reserved
+	if err := binary.Read(r, binary.BigEndian, &count); err != nil {
+		return nil, fmt.Errorf("reading Silf numSub: %s", err)
+	}
+
+	offsets := make([]uint32, count[0])
+	if err := binary.Read(r, binary.BigEndian, &offsets); err != nil {
+		return nil, fmt.Errorf("reading Silf offsets[%d]: %s", count[0], err)
+	}
+
+	for i, offset := range offsets {
+		if int64(offset) >= int64(len(buf)) {
+			return nil, io.ErrUnexpectedEOF
+		}
+
+		r := bytes.NewReader(buf[offset:])
+		if t.Version.Major >= 3 {
+			// Skip the ruleVersion, passOffset and pseudosOffset fields.
+			if _, err := r.Seek(8, io.SeekStart); err != nil {
+				return nil, err
+			}
+		}
+
+		var subtable SilfSubtable
+		if err := binary.Read(r, binary.BigEndian, &subtable); err != nil {
+			return nil, fmt.Errorf("reading Silf subtable[%d]: %s", i, err)
+		}
+
+		t.Subtables = append(t.Subtables, &subtable)
+	}
+
+	return t, nil
+}
@@ -0,0 +1,76 @@
+package sfnt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+func writeBigEndian(t *testing.T, values ...interface{}) []byte {
+	var buf bytes.Buffer
+	for _, v := range values {
+		if err := binary.Write(&buf, binary.BigEndian, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return buf.Bytes()
+}
+
+func TestParseTableFeat(t *testing.T) {
+	buf := writeBigEndian(t,
+		featHeader{Version: fixed{Major: 2}, NumFeat: 1},
+		featDefn2{ID: MustNamedTag("smcp").Number, NumSettings: 2, Offset: 28, Label: 256},
+		GraphiteFeatureSetting{Value: 0, Label: 257},
+		GraphiteFeatureSetting{Value: 1, Label: 258},
+	)
+
+	table, err := parseTableFeat(TagFeat, buf)
+	if err != nil {
+		t.Fatalf("parseTableFeat() err = %q, want nil", err)
+	}
+
+	feat := table.(*TableFeat)
+	if len(feat.Features) != 1 {
+		t.Fatalf("len(Features) = %d, want 1", len(feat.Features))
+	}
+	if f := feat.Features[0]; f.Tag() != MustNamedTag("smcp") || f.Label != 256 || len(f.Settings) != 2 {
+		t.Errorf("Features[0] = %+v, want smcp with 2 settings", f)
+	}
+}
+
+func TestGlyphAttributes(t *testing.T) {
+	glocBuf := writeBigEndian(t,
+		glocHeader{Version: fixed{Major: 1}},
+		[]uint16{4, 4, 10},
+	)
+	glatBuf := writeBigEndian(t,
+		fixed{Major: 1},
+		[2]uint8{3, 2}, []int16{-1, 7},
+	)
+
+	gloc, err := parseTableGloc(TagGloc, glocBuf)
+	if err != nil {
+		t.Fatalf("parseTableGloc() err = %q, want nil", err)
+	}
+	glat, err := parseTableGlat(TagGlat, glatBuf)
+	if err != nil {
+		t.Fatalf("parseTableGlat() err = %q, want nil", err)
+	}
+
+	if n := gloc.(*TableGloc).NumGlyphs(); n != 2 {
+		t.Errorf("NumGlyphs() = %d, want 2", n)
+	}
+
+	attrs, err := glat.(*TableGlat).GlyphAttributes(gloc.(*TableGloc), 1)
+	if err != nil {
+		t.Fatalf("GlyphAttributes(1) err = %q, want nil", err)
+	}
+	if want := map[uint16]int16{3: -1, 4: 7}; !reflect.DeepEqual(attrs, want) {
+		t.Errorf("GlyphAttributes(1) = %v, want %v", attrs, want)
+	}
+
+	if _, err := glat.(*TableGlat).GlyphAttributes(gloc.(*TableGloc), 2); err == nil {
+		t.Errorf("GlyphAttributes(2) err = nil, want error")
+	}
+}
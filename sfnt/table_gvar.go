@@ -0,0 +1,407 @@
+package sfnt
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// TableGvar represents the 'gvar' table, which contains the changes to the points
+// of each TrueType glyph across the design space of a variable font.
+// https://docs.microsoft.com/en-us/typography/opentype/spec/gvar
+type TableGvar struct {
+	baseTable
+
+	AxisCount int
+	Glyphs    []TupleVariations // Glyphs contains the variations of each glyph.
+}
+
+// TupleVariations contains the variations of a set of values, such as the
+// points of a glyph.
+type TupleVariations struct {
+	Tuples []TupleVariation
+
+	sharedPoints []byte // sharedPoints are the packed point numbers used by tuples without their own.
+}
+
+// TupleVariation contains the deltas that apply to a region of the design space.
+// The region's extent on each axis rises from Start to Peak and falls to End, in
+// normalized coordinates.
+type TupleVariation struct {
+	Peak []float64
+
+	// Start and End are nil unless the tuple is an intermediate region. Otherwise
+	// the region on each axis starts (or ends) at 0 and ends (or starts) at Peak.
+	Start, End []float64
+
+	// Deltas contains the x deltas followed by the y deltas of each point in
+	// 'gvar', or the deltas of each value in 'cvar'.
+	Deltas []int16
+
+	points []byte // points are the packed point numbers of the deltas, or nil to use the shared points.
+}
+
+// Region returns the extent of the tuple's region on each axis.
+func (tuple *TupleVariation) Region() []RegionAxis {
+	region := make([]RegionAxis, len(tuple.Peak))
+	for i, peak := range tuple.Peak {
+		region[i] = RegionAxis{math.Min(peak, 0), peak, math.Max(peak, 0)}
+		if tuple.Start != nil {
+			region[i].Start, region[i].End = tuple.Start[i], tuple.End[i]
+		}
+	}
+	return region
+}
+
+const (
+	tupleSharedPointNumbers  = 0x8000
+	tupleCountMask           = 0x0FFF
+	tupleEmbeddedPeak        = 0x8000
+	tupleIntermediateRegion  = 0x4000
+	tuplePrivatePointNumbers = 0x2000
+	tupleIndexMask           = 0x0FFF
+)
+
+func parseTableGvar(tag Tag, buf []byte) (Table, error) {
+	if len(buf) < 20 {
+		return nil, fmt.Errorf("reading gvar header: unexpected EOF")
+	}
+	if version := binary.BigEndian.Uint16(buf); version != 1 {
+		return nil, fmt.Errorf("unsupported gvar version %d", version)
+	}
+	axisCount := int(binary.BigEndian.Uint16(buf[4:]))
+	sharedCount := int(binary.BigEndian.Uint16(buf[6:]))
+	sharedOffset := int(binary.BigEndian.Uint32(buf[8:]))
+	glyphCount := int(binary.BigEndian.Uint16(buf[12:]))
+	longOffsets := binary.BigEndian.Uint16(buf[14:])&1 != 0
+	dataOffset := int(binary.BigEndian.Uint32(buf[16:]))
+
+	if len(buf) < sharedOffset+sharedCount*axisCount*2 {
+		return nil, fmt.Errorf("reading %d shared tuples: unexpected EOF", sharedCount)
+	}
+	shared := make([][]float64, sharedCount)
+	for i := range shared {
+		shared[i] = readF2dot14s(buf[sharedOffset+i*axisCount*2:], axisCount)
+	}
+
+	offsetSize := 2
+	if longOffsets {
+		offsetSize = 4
+	}
+	if len(buf) < 20+(glyphCount+1)*offsetSize {
+		return nil, fmt.Errorf("reading %d glyph variation offsets: unexpected EOF", glyphCount)
+	}
+	offset := func(i int) int {
+		if longOffsets {
+			return dataOffset + int(binary.BigEndian.Uint32(buf[20+4*i:]))
+		}
+		return dataOffset + 2*int(binary.BigEndian.Uint16(buf[20+2*i:]))
+	}
+
+	table := &TableGvar{baseTable: baseTable(tag), AxisCount: axisCount, Glyphs: make([]TupleVariations, glyphCount)}
+	for i := range table.Glyphs {
+		start, end := offset(i), offset(i+1)
+		if start > end || end > len(buf) {
+			return nil, fmt.Errorf("invalid variation data offsets %d-%d for glyph %d", start, end, i)
+		}
+		if start == end {
+			continue
+		}
+		var err error
+		table.Glyphs[i], err = parseTupleVariations(buf[start:end], 0, axisCount, shared)
+		if err != nil {
+			return nil, fmt.Errorf("reading variations of glyph %d: %s", i, err)
+		}
+	}
+	return table, nil
+}
+
+// Bytes returns the byte representation of this table. Peak coordinates are
+// always stored in each tuple, rather than being shared between glyphs.
+func (table *TableGvar) Bytes() []byte {
+	header := 20 + 4*(len(table.Glyphs)+1)
+	buf := appendUint16s(nil, 1, 0, uint16(table.AxisCount), 0)
+	buf = appendUint32s(buf, uint32(header))
+	buf = appendUint16s(buf, uint16(len(table.Glyphs)), 1)
+	buf = appendUint32s(buf, uint32(header))
+
+	var data []byte
+	for _, glyph := range table.Glyphs {
+		buf = appendUint32s(buf, uint32(len(data)))
+		if len(glyph.Tuples) > 0 {
+			data = append(data, glyph.bytes(nil)...)
+		}
+	}
+	buf = appendUint32s(buf, uint32(len(data)))
+	return append(buf, data...)
+}
+
+// parseTupleVariations parses a tuple variation store, starting with the count
+// of tuples at b[header:]. The offset to the serialized data is from the start of b.
+func parseTupleVariations(b []byte, header int, axisCount int, shared [][]float64) (TupleVariations, error) {
+	var v TupleVariations
+	if len(b) < header+4 {
+		return v, fmt.Errorf("unexpected EOF")
+	}
+	count := int(binary.BigEndian.Uint16(b[header:]) & tupleCountMask)
+	hasSharedPoints := binary.BigEndian.Uint16(b[header:])&tupleSharedPointNumbers != 0
+	data := int(binary.BigEndian.Uint16(b[header+2:]))
+	if data > len(b) {
+		return v, fmt.Errorf("invalid data offset %d", data)
+	}
+
+	if hasSharedPoints {
+		n, err := packedPointsLength(b[data:])
+		if err != nil {
+			return v, fmt.Errorf("reading shared points: %s", err)
+		}
+		v.sharedPoints = b[data : data+n]
+		data += n
+	}
+
+	h := b[header+4:]
+	for i := 0; i < count; i++ {
+		if len(h) < 4 {
+			return v, fmt.Errorf("reading tuple %d: unexpected EOF", i)
+		}
+		size := int(binary.BigEndian.Uint16(h))
+		index := binary.BigEndian.Uint16(h[2:])
+		h = h[4:]
+
+		var tuple TupleVariation
+		if index&tupleEmbeddedPeak != 0 {
+			if len(h) < 2*axisCount {
+				return v, fmt.Errorf("reading tuple %d: unexpected EOF", i)
+			}
+			tuple.Peak = readF2dot14s(h, axisCount)
+			h = h[2*axisCount:]
+		} else if int(index&tupleIndexMask) < len(shared) {
+			tuple.Peak = shared[index&tupleIndexMask]
+		} else {
+			return v, fmt.Errorf("invalid shared tuple index %d", index&tupleIndexMask)
+		}
+		if index&tupleIntermediateRegion != 0 {
+			if len(h) < 4*axisCount {
+				return v, fmt.Errorf("reading tuple %d: unexpected EOF", i)
+			}
+			tuple.Start = readF2dot14s(h, axisCount)
+			tuple.End = readF2dot14s(h[2*axisCount:], axisCount)
+			h = h[4*axisCount:]
+		}
+
+		if data+size > len(b) {
+			return v, fmt.Errorf("reading tuple %d: unexpected EOF", i)
+		}
+		d := b[data : data+size]
+		data += size
+		if index&tuplePrivatePointNumbers != 0 {
+			n, err := packedPointsLength(d)
+			if err != nil {
+				return v, fmt.Errorf("reading points of tuple %d: %s", i, err)
+			}
+			tuple.points, d = d[:n], d[n:]
+		}
+		var err error
+		if tuple.Deltas, err = unpackDeltas(d); err != nil {
+			return v, fmt.Errorf("reading deltas of tuple %d: %s", i, err)
+		}
+		v.Tuples = append(v.Tuples, tuple)
+	}
+	return v, nil
+}
+
+// bytes returns the tuple variation store, preceded by prefix. Offsets in the
+// store are from the start of prefix.
+func (v *TupleVariations) bytes(prefix []byte) []byte {
+	count := uint16(len(v.Tuples))
+	if v.sharedPoints != nil {
+		count |= tupleSharedPointNumbers
+	}
+
+	var headers, data []byte
+	data = append(data, v.sharedPoints...)
+	for _, tuple := range v.Tuples {
+		start := len(data)
+		data = append(data, tuple.points...)
+		data = append(data, packDeltas(tuple.Deltas)...)
+
+		index := uint16(tupleEmbeddedPeak)
+		if tuple.Start != nil {
+			index |= tupleIntermediateRegion
+		}
+		if tuple.points != nil {
+			index |= tuplePrivatePointNumbers
+		}
+		headers = appendUint16s(headers, uint16(len(data)-start), index)
+		headers = appendF2dot14s(headers, tuple.Peak)
+		if tuple.Start != nil {
+			headers = appendF2dot14s(appendF2dot14s(headers, tuple.Start), tuple.End)
+		}
+	}
+
+	buf := append(prefix, appendUint16s(nil, count, uint16(len(prefix)+4+len(headers)))...)
+	buf = append(buf, headers...)
+	buf = append(buf, data...)
+	if len(buf)%2 != 0 {
+		buf = append(buf, 0)
+	}
+	return buf
+}
+
+func readF2dot14s(b []byte, count int) []float64 {
+	values := make([]float64, count)
+	for i := range values {
+		values[i] = f2dot14(int16(binary.BigEndian.Uint16(b[2*i:])))
+	}
+	return values
+}
+
+func appendF2dot14s(buf []byte, values []float64) []byte {
+	for _, v := range values {
+		buf = appendUint16s(buf, uint16(toF2dot14(v)))
+	}
+	return buf
+}
+
+// packedPointsLength returns the length of the packed point numbers at the start of b.
+func packedPointsLength(b []byte) (int, error) {
+	if len(b) < 1 {
+		return 0, fmt.Errorf("unexpected EOF")
+	}
+	count, i := int(b[0]), 1
+	if count&0x80 != 0 {
+		if len(b) < 2 {
+			return 0, fmt.Errorf("unexpected EOF")
+		}
+		count, i = (count&0x7F)<<8|int(b[1]), 2
+	}
+
+	for count > 0 {
+		if len(b) < i+1 {
+			return 0, fmt.Errorf("unexpected EOF")
+		}
+		run := int(b[i]&0x7F) + 1
+		size := 1
+		if b[i]&0x80 != 0 {
+			size = 2
+		}
+		i += 1 + run*size
+		count -= run
+	}
+	if i > len(b) {
+		return 0, fmt.Errorf("unexpected EOF")
+	}
+	return i, nil
+}
+
+// unpackPoints returns the packed point numbers at the start of b, or nil if
+// the deltas apply to all points.
+func unpackPoints(b []byte) ([]int, error) {
+	n, err := packedPointsLength(b)
+	if err != nil {
+		return nil, err
+	}
+	count, i := int(b[0]), 1
+	if count&0x80 != 0 {
+		count, i = (count&0x7F)<<8|int(b[1]), 2
+	}
+	if count == 0 {
+		return nil, nil
+	}
+
+	points := make([]int, 0, count)
+	point := 0
+	for i < n {
+		run := int(b[i]&0x7F) + 1
+		words := b[i]&0x80 != 0
+		i++
+		for j := 0; j < run; j++ {
+			if words {
+				point += int(binary.BigEndian.Uint16(b[i:]))
+				i += 2
+			} else {
+				point += int(b[i])
+				i++
+			}
+			points = append(points, point)
+		}
+	}
+	return points, nil
+}
+
+const (
+	deltasAreZero  = 0x80
+	deltasAreWords = 0x40
+	deltaRunMask   = 0x3F
+)
+
+// unpackDeltas returns all of the packed deltas in b.
+func unpackDeltas(b []byte) ([]int16, error) {
+	var deltas []int16
+	for len(b) > 0 {
+		control := b[0]
+		run := int(control&deltaRunMask) + 1
+		b = b[1:]
+		switch {
+		case control&deltasAreZero != 0:
+			deltas = append(deltas, make([]int16, run)...)
+		case control&deltasAreWords != 0:
+			if len(b) < 2*run {
+				return nil, fmt.Errorf("unexpected EOF")
+			}
+			for i := 0; i < run; i++ {
+				deltas = append(deltas, int16(binary.BigEndian.Uint16(b[2*i:])))
+			}
+			b = b[2*run:]
+		default:
+			if len(b) < run {
+				return nil, fmt.Errorf("unexpected EOF")
+			}
+			for i := 0; i < run; i++ {
+				deltas = append(deltas, int16(int8(b[i])))
+			}
+			b = b[run:]
+		}
+	}
+	return deltas, nil
+}
+
+// packDeltas returns the deltas in packed form, using runs of zeros, bytes or words.
+func packDeltas(deltas []int16) []byte {
+	var buf []byte
+	for i := 0; i < len(deltas); {
+		// Find the longest run of the same kind of delta, up to 64.
+		kind := deltaKind(deltas[i])
+		run := 1
+		for i+run < len(deltas) && run < deltaRunMask+1 && deltaKind(deltas[i+run]) == kind {
+			run++
+		}
+
+		switch kind {
+		case deltasAreZero:
+			buf = append(buf, deltasAreZero|byte(run-1))
+		case deltasAreWords:
+			buf = append(buf, deltasAreWords|byte(run-1))
+			for _, d := range deltas[i : i+run] {
+				buf = appendUint16s(buf, uint16(d))
+			}
+		default:
+			buf = append(buf, byte(run-1))
+			for _, d := range deltas[i : i+run] {
+				buf = append(buf, byte(int8(d)))
+			}
+		}
+		i += run
+	}
+	return buf
+}
+
+func deltaKind(d int16) byte {
+	switch {
+	case d == 0:
+		return deltasAreZero
+	case d < -128 || d > 127:
+		return deltasAreWords
+	}
+	return 0
+}
@@ -0,0 +1,72 @@
+package sfnt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGvarRoundTrip(t *testing.T) {
+	want := &TableGvar{
+		baseTable: baseTable(TagGvar),
+		AxisCount: 2,
+		Glyphs: []TupleVariations{
+			{},
+			{
+				Tuples: []TupleVariation{
+					{Peak: []float64{1, 0}, Deltas: []int16{10, -20, 0, 0, 0, 300, -300, 5}},
+					{Peak: []float64{0.5, -1}, Start: []float64{0.25, -1}, End: []float64{1, 0}, Deltas: []int16{1, 2}, points: []byte{2, 1, 0, 3}},
+				},
+				sharedPoints: []byte{0},
+			},
+			{Tuples: []TupleVariation{{Peak: []float64{-1, 0}, Deltas: make([]int16, 100)}}},
+		},
+	}
+
+	got, err := parseTableGvar(TagGvar, want.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseTableGvar(Bytes()) = %+v, want %+v", got, want)
+	}
+
+	if _, err := parseTableGvar(TagGvar, want.Bytes()[:40]); err == nil {
+		t.Errorf("parseTableGvar(truncated) err = nil, want an error")
+	}
+}
+
+func TestGvarSharedTuples(t *testing.T) {
+	table, err := parseTableGvar(TagGvar, writeBigEndian(t,
+		uint16(1), uint16(0), uint16(1), uint16(1), uint32(24), // version, axisCount, sharedTupleCount, sharedTuplesOffset
+		uint16(1), uint16(0), uint32(26), // glyphCount, flags, dataOffset
+		uint16(0), uint16(5), // short offsets to the glyph data, divided by 2
+		uint16(0xC000),       // shared tuple at -1
+		uint16(1), uint16(8), // tupleVariationCount, dataOffset
+		uint16(2), uint16(0x2000), // size, private points and shared tuple 0
+		uint8(0), uint8(0x80), // all points, one zero delta
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []TupleVariation{{Peak: []float64{-1}, Deltas: []int16{0}, points: []byte{0}}}
+	if gvar := table.(*TableGvar); !reflect.DeepEqual(gvar.Glyphs[0].Tuples, want) {
+		t.Errorf("Glyphs[0].Tuples = %+v, want %+v", gvar.Glyphs[0].Tuples, want)
+	}
+}
+
+func TestCvarRoundTrip(t *testing.T) {
+	want := &TableCvar{
+		baseTable: baseTable(TagCvar),
+		Variations: TupleVariations{Tuples: []TupleVariation{
+			{Peak: []float64{1}, Deltas: []int16{4, -4, 0}, points: []byte{0}},
+		}},
+	}
+
+	got, err := parseTableCvar(want.Bytes(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseTableCvar(Bytes()) = %+v, want %+v", got, want)
+	}
+}
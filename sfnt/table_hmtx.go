@@ -0,0 +1,130 @@
+package sfnt
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// TableHmtx represents the 'hmtx' table, which contains the advance width and
+// left side bearing of each glyph.
+// https://docs.microsoft.com/en-us/typography/opentype/spec/hmtx
+type TableHmtx struct {
+	baseTable
+
+	// Metrics contains the metrics of each glyph, indexed by glyph id.
+	Metrics []HMetric
+}
+
+// HMetric contains the horizontal metrics of a glyph.
+type HMetric struct {
+	AdvanceWidth    uint16
+	LeftSideBearing int16
+}
+
+// HmtxTable returns the table corresponding to the 'hmtx' tag. The table is
+// parsed using the number of metrics in the 'hhea' table.
+func (font *Font) HmtxTable() (*TableHmtx, error) {
+	s, found := font.section(TagHmtx)
+	if !found {
+		return nil, ErrMissingTable
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if hmtx, ok := s.table.(*TableHmtx); ok {
+		return hmtx, nil
+	}
+
+	hmtx, err := font.parseHmtx(s)
+	if err != nil {
+		return nil, err
+	}
+	s.table = hmtx
+	return hmtx, nil
+}
+
+// parseHmtx parses the 'hmtx' table in s, without adding it to the font. The
+// number of metrics is read from the 'hhea' table, and the number of glyphs
+// from the 'maxp' table. The caller must hold s.mu.
+func (font *Font) parseHmtx(s *tableSection) (*TableHmtx, error) {
+	hhea, err := font.HheaTable()
+	if err != nil {
+		return nil, err
+	}
+	maxp, err := font.MaxpTable()
+	if err != nil {
+		return nil, err
+	}
+	t, err := font.parseTableWith(s, func(tag Tag, data []byte) (Table, error) {
+		return parseTableHmtx(data, int(uint16(hhea.NumOfLongHorMetrics)), int(maxp.NumGlyphs))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return t.(*TableHmtx), nil
+}
+
+// parseTableHmtx parses the metrics of numGlyphs glyphs, the first
+// numberOfHMetrics of which have their own advance width. Any data after the
+// metrics is ignored.
+func parseTableHmtx(data []byte, numberOfHMetrics, numGlyphs int) (*TableHmtx, error) {
+	if numberOfHMetrics == 0 || numberOfHMetrics > numGlyphs {
+		return nil, fmt.Errorf("numberOfHMetrics is %d, but there are %d glyphs", numberOfHMetrics, numGlyphs)
+	}
+	if want := 4*numberOfHMetrics + 2*(numGlyphs-numberOfHMetrics); len(data) < want {
+		return nil, fmt.Errorf("the table is %d bytes, but %d are needed for %d glyphs", len(data), want, numGlyphs)
+	}
+
+	table := &TableHmtx{
+		baseTable: baseTable(TagHmtx),
+		Metrics:   make([]HMetric, numGlyphs),
+	}
+
+	for i := range table.Metrics {
+		if i < numberOfHMetrics {
+			table.Metrics[i].AdvanceWidth = binary.BigEndian.Uint16(data[4*i:])
+			table.Metrics[i].LeftSideBearing = int16(binary.BigEndian.Uint16(data[4*i+2:]))
+		} else {
+			table.Metrics[i].AdvanceWidth = table.Metrics[numberOfHMetrics-1].AdvanceWidth
+			offset := 4*numberOfHMetrics + 2*(i-numberOfHMetrics)
+			table.Metrics[i].LeftSideBearing = int16(binary.BigEndian.Uint16(data[offset:]))
+		}
+	}
+
+	return table, nil
+}
+
+// Advance returns the advance width of the glyph, or 0 if it is out of range.
+func (table *TableHmtx) Advance(gid uint16) uint16 {
+	if int(gid) >= len(table.Metrics) {
+		return 0
+	}
+	return table.Metrics[gid].AdvanceWidth
+}
+
+// NumberOfHMetrics returns the number of metrics that are written with their
+// advance width, which is the numberOfHMetrics field of the 'hhea' table. The
+// glyphs at the end of the table that have the same advance width as the last
+// of these are written with only a left side bearing, which saves space in
+// monospaced and CJK fonts.
+func (table *TableHmtx) NumberOfHMetrics() int {
+	n := len(table.Metrics)
+	for n > 1 && table.Metrics[n-1].AdvanceWidth == table.Metrics[n-2].AdvanceWidth {
+		n--
+	}
+	return n
+}
+
+// Bytes returns the byte representation of this table.
+func (table *TableHmtx) Bytes() []byte {
+	n := table.NumberOfHMetrics()
+
+	buf := make([]byte, 0, 4*n+2*(len(table.Metrics)-n))
+	for i, m := range table.Metrics {
+		if i < n {
+			buf = append(buf, byte(m.AdvanceWidth>>8), byte(m.AdvanceWidth))
+		}
+		buf = append(buf, byte(uint16(m.LeftSideBearing)>>8), byte(m.LeftSideBearing))
+	}
+	return buf
+}
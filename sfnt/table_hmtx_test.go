@@ -0,0 +1,36 @@
+package sfnt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHmtxNumberOfHMetrics(t *testing.T) {
+	table := &TableHmtx{Metrics: []HMetric{{500, 10}, {1000, 20}, {1000, 30}, {1000, -40}}}
+	if n := table.NumberOfHMetrics(); n != 2 {
+		t.Errorf("NumberOfHMetrics() = %d, want 2", n)
+	}
+	data := table.Bytes()
+	if len(data) != 2*4+2*2 {
+		t.Errorf("len(Bytes()) = %d, want 12", len(data))
+	}
+	parsed, err := parseTableHmtx(data, 2, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(parsed.Metrics, table.Metrics) {
+		t.Errorf("parseTableHmtx(Bytes()) = %v, want %v", parsed.Metrics, table.Metrics)
+	}
+
+	if _, err := parseTableHmtx(data, 2, 5); err == nil {
+		t.Errorf("parseTableHmtx(Bytes()) with 5 glyphs err = nil, want an error")
+	}
+	if parsed, err := parseTableHmtx(append(data, 0, 0), 2, 4); err != nil || len(parsed.Metrics) != 4 {
+		t.Errorf("parseTableHmtx(Bytes() with trailing data) = %v, %v, want the 4 metrics", parsed, err)
+	}
+
+	single := &TableHmtx{Metrics: []HMetric{{600, 0}, {600, 0}}}
+	if n := single.NumberOfHMetrics(); n != 1 {
+		t.Errorf("NumberOfHMetrics() of a monospaced font = %d, want 1", n)
+	}
+}
@@ -0,0 +1,266 @@
+package sfnt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// TableJstf represents the OpenType 'JSTF' table. This contains the GSUB and
+// GPOS lookups that may be enabled or disabled to shrink or extend lines of
+// text when justifying them.
+//
+// See https://docs.microsoft.com/en-us/typography/opentype/spec/jstf
+type TableJstf struct {
+	baseTable
+
+	bytes []byte
+
+	Scripts []*JstfScript // Scripts contains the justification data for each script.
+}
+
+// Bytes returns the bytes for this table. The TableJstf is read only, so
+// the bytes will always be the same as what is read in.
+func (t *TableJstf) Bytes() []byte {
+	return t.bytes
+}
+
+// JstfScript represents the justification data for a single script.
+type JstfScript struct {
+	Tag             Tag            // Tag for this script.
+	ExtenderGlyphs  []uint16       // ExtenderGlyphs are the glyph ids that may be inserted to extend a line.
+	DefaultLanguage *JstfLangSys   // DefaultLanguage used by this script (may be nil).
+	Languages       []*JstfLangSys // Languages within this script.
+}
+
+// String returns the name for this script.
+func (s *JstfScript) String() string {
+	return scriptTags[s.Tag.String()]
+}
+
+// JstfLangSys represents the justification data for a language system.
+type JstfLangSys struct {
+	Tag        Tag             // Tag for this language.
+	Priorities []*JstfPriority // Priorities in the order they should be tried.
+}
+
+// String returns the name for this language.
+func (l *JstfLangSys) String() string {
+	return languageTags[l.Tag.String()]
+}
+
+// JstfPriority contains the lookups to enable or disable at one justification priority.
+// Each field is a list of indices into the LookupList of the GSUB or GPOS table.
+type JstfPriority struct {
+	GsubShrinkageEnable  []uint16
+	GsubShrinkageDisable []uint16
+	GposShrinkageEnable  []uint16
+	GposShrinkageDisable []uint16
+	GsubExtensionEnable  []uint16
+	GsubExtensionDisable []uint16
+	GposExtensionEnable  []uint16
+	GposExtensionDisable []uint16
+}
+
+// jstfHeader is the on-disk format of the JSTF table header.
+type jstfHeader struct {
+	Major           uint16
+	Minor           uint16
+	JstfScriptCount uint16
+}
+
+type jstfScriptTable struct {
+	ExtenderGlyphOffset  uint16 // offset to ExtenderGlyph table, from beginning of JstfScript table (may be NULL).
+	DefJstfLangSysOffset uint16 // offset to default JstfLangSys table, from beginning of JstfScript table (may be NULL).
+	JstfLangSysCount     uint16
+}
+
+type jstfPriorityTable struct {
+	GsubShrinkageEnableOffset  uint16
+	GsubShrinkageDisableOffset uint16
+	GposShrinkageEnableOffset  uint16
+	GposShrinkageDisableOffset uint16
+	ShrinkageJstfMaxOffset     uint16
+	GsubExtensionEnableOffset  uint16
+	GsubExtensionDisableOffset uint16
+	GposExtensionEnableOffset  uint16
+	GposExtensionDisableOffset uint16
+	ExtensionJstfMaxOffset     uint16
+}
+
+// parseUint16List parses a uint16 count followed by that many uint16s,
+// which is how many lists of glyph ids and lookup indices are stored.
+// A zero offset is treated as NULL and returns an empty list.
+func parseUint16List(b []byte, offset uint16) ([]uint16, error) {
+	if offset == 0 {
+		return nil, nil
+	}
+	if int(offset) >= len(b) {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	r := bytes.NewReader(b[offset:])
+
+	var count uint16
+	if err := binary.Read(r, binary.BigEndian, &count); err != nil {
+		return nil, err
+	}
+
+	list := make([]uint16, count)
+	if err := binary.Read(r, binary.BigEndian, &list); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// parseJstfPriority parses a single JstfPriority table. b expected to be the beginning of JstfLangSys.
+func (t *TableJstf) parseJstfPriority(b []byte, offset uint16) (*JstfPriority, error) {
+	if int(offset) >= len(b) {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	b = b[offset:]
+
+	var table jstfPriorityTable
+	if err := binary.Read(bytes.NewReader(b), binary.BigEndian, &table); err != nil {
+		return nil, fmt.Errorf("reading jstfPriority: %s", err)
+	}
+
+	// TODO Read the JstfMax tables, these contain GPOS lookups of their own.
+
+	p := &JstfPriority{}
+	for _, list := range []struct {
+		offset uint16
+		dest   *[]uint16
+	}{
+		{table.GsubShrinkageEnableOffset, &p.GsubShrinkageEnable},
+		{table.GsubShrinkageDisableOffset, &p.GsubShrinkageDisable},
+		{table.GposShrinkageEnableOffset, &p.GposShrinkageEnable},
+		{table.GposShrinkageDisableOffset, &p.GposShrinkageDisable},
+		{table.GsubExtensionEnableOffset, &p.GsubExtensionEnable},
+		{table.GsubExtensionDisableOffset, &p.GsubExtensionDisable},
+		{table.GposExtensionEnableOffset, &p.GposExtensionEnable},
+		{table.GposExtensionDisableOffset, &p.GposExtensionDisable},
+	} {
+		indices, err := parseUint16List(b, list.offset)
+		if err != nil {
+			return nil, fmt.Errorf("reading jstfModList: %s", err)
+		}
+		*list.dest = indices
+	}
+
+	return p, nil
+}
+
+// parseJstfLangSys parses a single JstfLangSys table. b expected to be the beginning of JstfScript.
+func (t *TableJstf) parseJstfLangSys(b []byte, record tagOffsetRecord) (*JstfLangSys, error) {
+	if int(record.Offset) >= len(b) {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	b = b[record.Offset:]
+	r := bytes.NewReader(b)
+
+	var count uint16
+	if err := binary.Read(r, binary.BigEndian, &count); err != nil {
+		return nil, fmt.Errorf("reading jstfPriorityCount: %s", err)
+	}
+
+	priorityOffsets := make([]uint16, count)
+	if err := binary.Read(r, binary.BigEndian, &priorityOffsets); err != nil {
+		return nil, fmt.Errorf("reading jstfPriorityOffsets[%d]: %s", count, err)
+	}
+
+	lang := &JstfLangSys{Tag: record.Tag}
+	for _, offset := range priorityOffsets {
+		priority, err := t.parseJstfPriority(b, offset)
+		if err != nil {
+			return nil, err
+		}
+		lang.Priorities = append(lang.Priorities, priority)
+	}
+
+	return lang, nil
+}
+
+// parseJstfScript parses a single JstfScript table. b expected to be the beginning of the JSTF table.
+func (t *TableJstf) parseJstfScript(b []byte, record tagOffsetRecord) (*JstfScript, error) {
+	if int(record.Offset) >= len(b) {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	b = b[record.Offset:]
+	r := bytes.NewReader(b)
+
+	var table jstfScriptTable
+	if err := binary.Read(r, binary.BigEndian, &table); err != nil {
+		return nil, fmt.Errorf("reading jstfScript: %s", err)
+	}
+
+	script := &JstfScript{Tag: record.Tag}
+
+	var err error
+	if script.ExtenderGlyphs, err = parseUint16List(b, table.ExtenderGlyphOffset); err != nil {
+		return nil, fmt.Errorf("reading extenderGlyph: %s", err)
+	}
+
+	if table.DefJstfLangSysOffset > 0 {
+		script.DefaultLanguage, err = t.parseJstfLangSys(b, tagOffsetRecord{Offset: table.DefJstfLangSysOffset})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i := 0; i < int(table.JstfLangSysCount); i++ {
+		var record tagOffsetRecord
+		if err := binary.Read(r, binary.BigEndian, &record); err != nil {
+			return nil, fmt.Errorf("reading jstfLangSysRecord[%d]: %s", i, err)
+		}
+
+		lang, err := t.parseJstfLangSys(b, record)
+		if err != nil {
+			return nil, err
+		}
+
+		script.Languages = append(script.Languages, lang)
+	}
+
+	return script, nil
+}
+
+// parseTableJstf parses the JSTF table.
+func parseTableJstf(tag Tag, buf []byte) (Table, error) {
+	t := &TableJstf{
+		baseTable: baseTable(tag),
+		bytes:     buf,
+	}
+
+	r := bytes.NewReader(buf)
+
+	var header jstfHeader
+	if err := binary.Read(r, binary.BigEndian, &header); err != nil {
+		return nil, fmt.Errorf("reading JSTF header: %s", err)
+	}
+
+	if header.Major != 1 || header.Minor != 0 {
+		return nil, fmt.Errorf("unsupported JSTF version (major: %d, minor: %d)", header.Major, header.Minor)
+	}
+
+	for i := 0; i < int(header.JstfScriptCount); i++ {
+		var record tagOffsetRecord
+		if err := binary.Read(r, binary.BigEndian, &record); err != nil {
+			return nil, fmt.Errorf("reading jstfScriptRecord[%d]: %s", i, err)
+		}
+
+		script, err := t.parseJstfScript(buf, record)
+		if err != nil {
+			return nil, err
+		}
+
+		t.Scripts = append(t.Scripts, script)
+	}
+
+	return t, nil
+}
@@ -0,0 +1,50 @@
+package sfnt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+func TestParseTableJstf(t *testing.T) {
+	var buf bytes.Buffer
+	for _, v := range []interface{}{
+		jstfHeader{Major: 1, Minor: 0, JstfScriptCount: 1},
+		tagOffsetRecord{Tag: MustNamedTag("arab"), Offset: 12},
+		// JstfScript.
+		jstfScriptTable{DefJstfLangSysOffset: 6},
+		// JstfLangSys.
+		uint16(1), uint16(4),
+		// JstfPriority.
+		jstfPriorityTable{GsubShrinkageEnableOffset: 20},
+		// JstfGSUBModList.
+		uint16(2), uint16(3), uint16(5),
+	} {
+		if err := binary.Write(&buf, binary.BigEndian, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	table, err := parseTableJstf(TagJstf, buf.Bytes())
+	if err != nil {
+		t.Fatalf("parseTableJstf() err = %q, want nil", err)
+	}
+
+	jstf := table.(*TableJstf)
+	if len(jstf.Scripts) != 1 || jstf.Scripts[0].DefaultLanguage == nil {
+		t.Fatalf("Scripts = %v, want one script with a default language", jstf.Scripts)
+	}
+
+	priorities := jstf.Scripts[0].DefaultLanguage.Priorities
+	if len(priorities) != 1 {
+		t.Fatalf("len(Priorities) = %d, want 1", len(priorities))
+	}
+	if got, want := priorities[0].GsubShrinkageEnable, []uint16{3, 5}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GsubShrinkageEnable = %v, want %v", got, want)
+	}
+
+	if _, err := parseTableJstf(TagJstf, buf.Bytes()[:buf.Len()-2]); err == nil {
+		t.Errorf("parseTableJstf(truncated) err = nil, want error")
+	}
+}
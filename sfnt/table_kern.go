@@ -0,0 +1,176 @@
+package sfnt
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+)
+
+// TableKern represents the legacy 'kern' table, which contains adjustments to the
+// spacing between pairs of glyphs. Both the OpenType and the Apple versions of the
+// table are supported, though only horizontal kerning subtables in formats 0 and 2
+// are used; other subtables are ignored.
+//
+// Most fonts position pairs of glyphs with the 'kern' feature in GPOS instead.
+// Use Font.KerningValue to look up kerning regardless of where it is stored.
+//
+// See https://docs.microsoft.com/en-us/typography/opentype/spec/kern
+// See https://developer.apple.com/fonts/TrueType-Reference-Manual/RM06/Chap6kern.html
+type TableKern struct {
+	baseTable
+
+	bytes     []byte
+	subtables []kernSubtable
+}
+
+// kernSubtable is a single horizontal kerning subtable.
+type kernSubtable struct {
+	format   uint8
+	override bool   // override is set if the value replaces the values from previous subtables.
+	data     []byte // data starts at the subtable header.
+	header   int    // header is the length of the subtable header.
+}
+
+// Bytes returns the bytes for this table. The TableKern is read only, so
+// the bytes will always be the same as what is read in.
+func (t *TableKern) Bytes() []byte {
+	return t.bytes
+}
+
+// Kerning returns the adjustment to the advance of left when it is followed by right.
+func (t *TableKern) Kerning(left, right uint16) int16 {
+	var total int16
+	for _, s := range t.subtables {
+		v, ok := s.kerning(left, right)
+		if !ok {
+			continue
+		}
+		if s.override {
+			total = v
+		} else {
+			total += v
+		}
+	}
+	return total
+}
+
+func parseTableKern(tag Tag, buf []byte) (Table, error) {
+	if len(buf) < 4 {
+		return nil, fmt.Errorf("reading kern header: unexpected EOF")
+	}
+	table := &TableKern{baseTable: baseTable(tag), bytes: buf}
+
+	// The OpenType table has a 16-bit version of 0, the Apple table a 32-bit version of 1.0.
+	apple := binary.BigEndian.Uint16(buf) == 1
+	var count, offset int
+	if apple {
+		if len(buf) < 8 {
+			return nil, fmt.Errorf("reading kern header: unexpected EOF")
+		}
+		count, offset = int(binary.BigEndian.Uint32(buf[4:])), 8
+	} else {
+		count, offset = int(binary.BigEndian.Uint16(buf[2:])), 4
+	}
+
+	for i := 0; i < count; i++ {
+		var length int
+		var s kernSubtable
+		horizontal := false
+
+		if apple {
+			if len(buf) < offset+8 {
+				return nil, fmt.Errorf("reading kern subtable %d: unexpected EOF", i)
+			}
+			length = int(binary.BigEndian.Uint32(buf[offset:]))
+			coverage := binary.BigEndian.Uint16(buf[offset+4:])
+			s.format = uint8(coverage)
+			s.header = 8
+			// Skip vertical, cross-stream and variation subtables.
+			horizontal = coverage&0xE000 == 0
+		} else {
+			if len(buf) < offset+6 {
+				return nil, fmt.Errorf("reading kern subtable %d: unexpected EOF", i)
+			}
+			length = int(binary.BigEndian.Uint16(buf[offset+2:]))
+			coverage := binary.BigEndian.Uint16(buf[offset+4:])
+			s.format = uint8(coverage >> 8)
+			s.override = coverage&0x8 != 0
+			s.header = 6
+			// Skip vertical, minimum and cross-stream subtables.
+			horizontal = coverage&0x7 == 0x1
+		}
+		if length < s.header {
+			return nil, fmt.Errorf("kern subtable %d has invalid length %d", i, length)
+		}
+
+		// The 16-bit length of large OpenType subtables overflows, so each subtable
+		// is allowed to run to the end of the table and the lookups check bounds.
+		s.data = buf[offset:]
+		if horizontal && (s.format == 0 || s.format == 2) {
+			table.subtables = append(table.subtables, s)
+		}
+		offset += length
+	}
+
+	return table, nil
+}
+
+// kerning returns the value for the pair, and whether the pair is in this subtable.
+func (s kernSubtable) kerning(left, right uint16) (int16, bool) {
+	b := s.data[s.header:]
+	switch s.format {
+	case 0:
+		if len(b) < 8 {
+			return 0, false
+		}
+		return kernPairs(b, 8, int(binary.BigEndian.Uint16(b)), left, right)
+	case 2:
+		if len(b) < 8 {
+			return 0, false
+		}
+		l := kernClass(s.data, int(binary.BigEndian.Uint16(b[2:])), left)
+		r := kernClass(s.data, int(binary.BigEndian.Uint16(b[4:])), right)
+		// The left class values already include the offset of the kerning array.
+		offset := int(l) + int(r)
+		if offset < int(binary.BigEndian.Uint16(b[6:])) || offset+2 > len(s.data) {
+			return 0, false
+		}
+		return int16(binary.BigEndian.Uint16(s.data[offset:])), true
+	}
+	return 0, false
+}
+
+// kernPairs looks up a pair in a sorted array of left, right and value records
+// that starts after a header of the given length.
+func kernPairs(b []byte, header int, count int, left, right uint16) (int16, bool) {
+	if len(b) < header+6*count {
+		count = (len(b) - header) / 6
+	}
+	if count <= 0 {
+		return 0, false
+	}
+	pairs := b[header:]
+
+	key := uint32(left)<<16 | uint32(right)
+	i := sort.Search(count, func(i int) bool {
+		return binary.BigEndian.Uint32(pairs[6*i:]) >= key
+	})
+	if i < count && binary.BigEndian.Uint32(pairs[6*i:]) == key {
+		return int16(binary.BigEndian.Uint16(pairs[6*i+4:])), true
+	}
+	return 0, false
+}
+
+// kernClass returns the value for gid in the 'kern' format 2 class table at offset in b.
+func kernClass(b []byte, offset int, gid uint16) uint16 {
+	if len(b) < offset+4 {
+		return 0
+	}
+	b = b[offset:]
+	first := binary.BigEndian.Uint16(b)
+	count := int(binary.BigEndian.Uint16(b[2:]))
+	if gid < first || int(gid-first) >= count || len(b) < 4+2*count {
+		return 0
+	}
+	return binary.BigEndian.Uint16(b[4+2*int(gid-first):])
+}
@@ -0,0 +1,196 @@
+package sfnt
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+)
+
+// TableKerx represents Apple's 'kerx' table, the extended version of the 'kern'
+// table. Only horizontal kerning subtables in formats 0 and 2 are used; subtables
+// that use state machines or anchor points, and variation subtables, are ignored.
+//
+// See https://developer.apple.com/fonts/TrueType-Reference-Manual/RM06/Chap6kerx.html
+type TableKerx struct {
+	baseTable
+
+	bytes     []byte
+	subtables []kerxSubtable
+}
+
+// kerxSubtable is a single horizontal kerning subtable.
+type kerxSubtable struct {
+	format uint8
+	data   []byte // data starts at the subtable header.
+}
+
+const kerxSubtableHeaderLength = 12
+
+// Bytes returns the bytes for this table. The TableKerx is read only, so
+// the bytes will always be the same as what is read in.
+func (t *TableKerx) Bytes() []byte {
+	return t.bytes
+}
+
+// Kerning returns the adjustment to the advance of left when it is followed by right.
+func (t *TableKerx) Kerning(left, right uint16) int16 {
+	var total int16
+	for _, s := range t.subtables {
+		total += s.kerning(left, right)
+	}
+	return total
+}
+
+func parseTableKerx(tag Tag, buf []byte) (Table, error) {
+	if len(buf) < 8 {
+		return nil, fmt.Errorf("reading kerx header: unexpected EOF")
+	}
+	if version := binary.BigEndian.Uint16(buf); version < 2 {
+		return nil, fmt.Errorf("unsupported kerx version %d", version)
+	}
+	table := &TableKerx{baseTable: baseTable(tag), bytes: buf}
+
+	count := int(binary.BigEndian.Uint32(buf[4:]))
+	offset := 8
+	for i := 0; i < count; i++ {
+		if len(buf) < offset+kerxSubtableHeaderLength {
+			return nil, fmt.Errorf("reading kerx subtable %d: unexpected EOF", i)
+		}
+		length := int(binary.BigEndian.Uint32(buf[offset:]))
+		coverage := binary.BigEndian.Uint32(buf[offset+4:])
+		if length < kerxSubtableHeaderLength || offset+length > len(buf) {
+			return nil, fmt.Errorf("kerx subtable %d has invalid length %d", i, length)
+		}
+
+		// Skip vertical, cross-stream and variation subtables.
+		format := uint8(coverage)
+		if coverage&0xE0000000 == 0 && (format == 0 || format == 2) {
+			table.subtables = append(table.subtables, kerxSubtable{
+				format: format,
+				data:   buf[offset : offset+length],
+			})
+		}
+		offset += length
+	}
+
+	return table, nil
+}
+
+// kerning returns the value for the pair in this subtable, or 0.
+func (s kerxSubtable) kerning(left, right uint16) int16 {
+	b := s.data[kerxSubtableHeaderLength:]
+	if len(b) < 16 {
+		return 0
+	}
+
+	switch s.format {
+	case 0:
+		v, _ := kernPairs(b, 16, int(binary.BigEndian.Uint32(b)), left, right)
+		return v
+	case 2:
+		l, _ := aatLookup(s.data, int(binary.BigEndian.Uint32(b[4:])), left)
+		r, _ := aatLookup(s.data, int(binary.BigEndian.Uint32(b[8:])), right)
+		// Unlike 'kern', the class values are indices into the kerning array.
+		offset := int(binary.BigEndian.Uint32(b[12:])) + 2*(int(l)+int(r))
+		if offset < 0 || offset+2 > len(s.data) {
+			return 0
+		}
+		return int16(binary.BigEndian.Uint16(s.data[offset:]))
+	}
+	return 0
+}
+
+// aatLookup returns the value for gid in the AAT lookup table at offset in b.
+// See https://developer.apple.com/fonts/TrueType-Reference-Manual/RM06/Chap6Tables.html
+func aatLookup(b []byte, offset int, gid uint16) (uint16, bool) {
+	if offset < 0 || len(b) < offset+2 {
+		return 0, false
+	}
+	b = b[offset:]
+
+	switch binary.BigEndian.Uint16(b) {
+	case 0: // Simple array, indexed by glyph.
+		if len(b) < 4+2*int(gid) {
+			return 0, false
+		}
+		return binary.BigEndian.Uint16(b[2+2*int(gid):]), true
+
+	case 2, 4: // Segments of glyphs with a single value, or an array of values.
+		segment, ok := aatSearch(b, 6, func(unit []byte) int {
+			switch {
+			case gid > binary.BigEndian.Uint16(unit):
+				return -1
+			case gid < binary.BigEndian.Uint16(unit[2:]):
+				return 1
+			}
+			return 0
+		})
+		if !ok {
+			return 0, false
+		}
+		value := binary.BigEndian.Uint16(segment[4:])
+		if binary.BigEndian.Uint16(b) == 2 {
+			return value, true
+		}
+		i := int(value) + 2*int(gid-binary.BigEndian.Uint16(segment[2:]))
+		if len(b) < i+2 {
+			return 0, false
+		}
+		return binary.BigEndian.Uint16(b[i:]), true
+
+	case 6: // Sorted list of glyphs and values.
+		single, ok := aatSearch(b, 4, func(unit []byte) int {
+			g := binary.BigEndian.Uint16(unit)
+			switch {
+			case gid > g:
+				return -1
+			case gid < g:
+				return 1
+			}
+			return 0
+		})
+		if !ok {
+			return 0, false
+		}
+		return binary.BigEndian.Uint16(single[2:]), true
+
+	case 8: // Trimmed array of consecutive glyphs.
+		if len(b) < 6 {
+			return 0, false
+		}
+		first := binary.BigEndian.Uint16(b[2:])
+		count := int(binary.BigEndian.Uint16(b[4:]))
+		if gid < first || int(gid-first) >= count || len(b) < 6+2*count {
+			return 0, false
+		}
+		return binary.BigEndian.Uint16(b[6+2*int(gid-first):]), true
+	}
+	return 0, false
+}
+
+// aatSearch does a binary search of the units in the binary search table of an
+// AAT lookup table. The compare function returns -1 if the unit is before the
+// glyph being searched for, 1 if it is after, and 0 if it matches.
+func aatSearch(b []byte, minUnitSize int, compare func(unit []byte) int) ([]byte, bool) {
+	if len(b) < 12 {
+		return nil, false
+	}
+	unitSize := int(binary.BigEndian.Uint16(b[2:]))
+	count := int(binary.BigEndian.Uint16(b[4:]))
+	if unitSize < minUnitSize {
+		return nil, false
+	}
+	units := b[12:]
+	if len(units) < unitSize*count {
+		count = len(units) / unitSize
+	}
+	// The last unit may be a 0xFFFF terminator, which never matches a real glyph.
+
+	i := sort.Search(count, func(i int) bool {
+		return compare(units[unitSize*i:]) >= 0
+	})
+	if i < count && compare(units[unitSize*i:]) == 0 {
+		return units[unitSize*i:], true
+	}
+	return nil, false
+}
@@ -0,0 +1,74 @@
+package sfnt
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+var (
+	maxpVersion05 = fixed{Major: 0, Minor: 0x5000}
+	maxpVersion1  = fixed{Major: 1}
+)
+
+// TableMaxp represents the 'maxp' table, which contains the number of glyphs in
+// the font and, for fonts with TrueType outlines, the memory they need.
+// https://docs.microsoft.com/en-us/typography/opentype/spec/maxp
+type TableMaxp struct {
+	baseTable
+	tableMaxpFields
+}
+
+type tableMaxpFields struct {
+	Version   fixed
+	NumGlyphs uint16
+
+	// The remaining fields are only present in version 1.0, used by fonts with TrueType outlines.
+	MaxPoints             uint16
+	MaxContours           uint16
+	MaxComponentPoints    uint16
+	MaxComponentContours  uint16
+	MaxZones              uint16
+	MaxTwilightPoints     uint16
+	MaxStorage            uint16
+	MaxFunctionDefs       uint16
+	MaxInstructionDefs    uint16
+	MaxStackElements      uint16
+	MaxSizeOfInstructions uint16
+	MaxComponentElements  uint16
+	MaxComponentDepth     uint16
+}
+
+func parseTableMaxp(tag Tag, buf []byte) (Table, error) {
+	r := bytes.NewReader(buf)
+
+	var fields tableMaxpFields
+	if err := binary.Read(r, binary.BigEndian, &fields.Version); err != nil {
+		return nil, err
+	}
+	if err := binary.Read(r, binary.BigEndian, &fields.NumGlyphs); err != nil {
+		return nil, err
+	}
+	if fields.Version == maxpVersion1 {
+		r.Reset(buf)
+		if err := binary.Read(r, binary.BigEndian, &fields); err != nil {
+			return nil, err
+		}
+	}
+
+	return &TableMaxp{
+		baseTable:       baseTable(tag),
+		tableMaxpFields: fields,
+	}, nil
+}
+
+// Bytes returns the byte representation of this table.
+func (table *TableMaxp) Bytes() []byte {
+	var buffer bytes.Buffer
+	if table.Version == maxpVersion1 {
+		binary.Write(&buffer, binary.BigEndian, table.tableMaxpFields)
+	} else {
+		binary.Write(&buffer, binary.BigEndian, table.Version)
+		binary.Write(&buffer, binary.BigEndian, table.NumGlyphs)
+	}
+	return buffer.Bytes()
+}
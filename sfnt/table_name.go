@@ -52,13 +52,19 @@ func (p PlatformID) String() string {
 }
 
 // PlatformEncodingID represents the platform specific id for entries in the name table.
-// the three most common values are provided as constants.
+// the most common values are provided as constants.
 type PlatformEncodingID uint16
 
 var (
-	PlatformEncodingMacRoman         = PlatformEncodingID(0)
-	PlatformEncodingUnicodeDefault   = PlatformEncodingID(0)
-	PlatformEncodingMicrosoftUnicode = PlatformEncodingID(1)
+	PlatformEncodingMacRoman          = PlatformEncodingID(0)
+	PlatformEncodingUnicodeDefault    = PlatformEncodingID(0)
+	PlatformEncodingMicrosoftSymbol   = PlatformEncodingID(0)
+	PlatformEncodingMicrosoftUnicode  = PlatformEncodingID(1)
+	PlatformEncodingMicrosoftShiftJIS = PlatformEncodingID(2)
+	PlatformEncodingMicrosoftPRC      = PlatformEncodingID(3)
+	PlatformEncodingMicrosoftBig5     = PlatformEncodingID(4)
+	PlatformEncodingMicrosoftWansung  = PlatformEncodingID(5)
+	PlatformEncodingMicrosoftUCS4     = PlatformEncodingID(10)
 )
 
 // PlatformLanguageID represents the language used by an entry in the name table,
@@ -75,31 +81,32 @@ var (
 type NameID uint16
 
 var (
-	NameCopyrightNotice        = NameID(0)
-	NameFontFamily             = NameID(1)
-	NameFontSubfamily          = NameID(2)
-	NameUniqueIdentifier       = NameID(3)
-	NameFull                   = NameID(4)
-	NameVersion                = NameID(5)
-	NamePostscript             = NameID(6)
-	NameTrademark              = NameID(7)
-	NameManufacturer           = NameID(8)
-	NameDesigner               = NameID(9)
-	NameDescription            = NameID(10)
-	NameVendorURL              = NameID(11)
-	NameDesignerURL            = NameID(12)
-	NameLicenseDescription     = NameID(13)
-	_NameReserved              = NameID(15)
-	NameLicenseURL             = NameID(14)
-	NamePreferredFamily        = NameID(16)
-	NamePreferredSubfamily     = NameID(17)
-	NameCompatibleFull         = NameID(18)
-	NameSampleText             = NameID(19)
-	NamePostscriptCID          = NameID(20)
-	NameWWSFamily              = NameID(21)
-	NameWWSSubfamily           = NameID(22)
-	NameLightBackgroundPalette = NameID(23)
-	NameDarkBackgroundPalette  = NameID(24)
+	NameCopyrightNotice            = NameID(0)
+	NameFontFamily                 = NameID(1)
+	NameFontSubfamily              = NameID(2)
+	NameUniqueIdentifier           = NameID(3)
+	NameFull                       = NameID(4)
+	NameVersion                    = NameID(5)
+	NamePostscript                 = NameID(6)
+	NameTrademark                  = NameID(7)
+	NameManufacturer               = NameID(8)
+	NameDesigner                   = NameID(9)
+	NameDescription                = NameID(10)
+	NameVendorURL                  = NameID(11)
+	NameDesignerURL                = NameID(12)
+	NameLicenseDescription         = NameID(13)
+	_NameReserved                  = NameID(15)
+	NameLicenseURL                 = NameID(14)
+	NamePreferredFamily            = NameID(16)
+	NamePreferredSubfamily         = NameID(17)
+	NameCompatibleFull             = NameID(18)
+	NameSampleText                 = NameID(19)
+	NamePostscriptCID              = NameID(20)
+	NameWWSFamily                  = NameID(21)
+	NameWWSSubfamily               = NameID(22)
+	NameLightBackgroundPalette     = NameID(23)
+	NameDarkBackgroundPalette      = NameID(24)
+	NameVariationsPostscriptPrefix = NameID(25)
 )
 
 // String returns an identifying
@@ -153,6 +160,8 @@ func (nameId NameID) String() string {
 		return "Light Background Palette"
 	case NameDarkBackgroundPalette:
 		return "Dark Background Palette"
+	case NameVariationsPostscriptPrefix:
+		return "Variations PostScript Name Prefix"
 	default:
 		return "Name " + strconv.Itoa(int(nameId))
 	}
@@ -330,6 +339,18 @@ func (table *TableName) Add(entry *NameEntry) {
 	table.entries = append(table.entries, entry)
 }
 
+// Remove removes all entries with the given name id from the table.
+func (table *TableName) Remove(nameId NameID) {
+	entries := table.entries[:0]
+	for _, entry := range table.entries {
+		if entry.NameID != nameId {
+			entries = append(entries, entry)
+		}
+	}
+	table.bytes = nil
+	table.entries = entries
+}
+
 // Bytes returns the representation of this table to be stored in a font.
 func (table *TableName) Bytes() []byte {
 	if len(table.bytes) > 0 {
@@ -365,6 +386,27 @@ func (table *TableName) Bytes() []byte {
 	return table.bytes
 }
 
+// Lookup returns the value of the entry with the given name id, preferring the
+// Microsoft English entry, or "" if there is no such entry.
+func (table *TableName) Lookup(nameId NameID) string {
+	var found *NameEntry
+	for _, entry := range table.entries {
+		if entry.NameID != nameId {
+			continue
+		}
+		if entry.PlatformID == PlatformMicrosoft && entry.LanguageID == PlatformLanguageMicrosoftEnglish {
+			return entry.String()
+		}
+		if found == nil {
+			found = entry
+		}
+	}
+	if found == nil {
+		return ""
+	}
+	return found.String()
+}
+
 // List returns a list of all the strings defined in this table.
 func (table *TableName) List() []*NameEntry {
 	return table.entries
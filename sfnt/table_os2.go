@@ -3,7 +3,9 @@ package sfnt
 import (
 	"bytes"
 	"encoding/binary"
+	"fmt"
 	"io"
+	"math"
 )
 
 type tableOS2Fields struct {
@@ -45,33 +47,171 @@ type tableOS2Fields struct {
 	UsUpperPointSize    uint16
 }
 
+// TableOS2 represents the 'OS/2' table, which contains metrics and style
+// information used by Windows.
+// https://docs.microsoft.com/en-us/typography/opentype/spec/os2
 type TableOS2 struct {
 	baseTable
 	tableOS2Fields
-	bytes []byte
+
+	// length is the length of the parsed table, as some version 0 tables end
+	// before the typographic metrics.
+	length int
 }
 
-func parseTableOS2(tag Tag, buf []byte) (Table, error) {
-	r := bytes.NewBuffer(buf)
+// os2Lengths are the lengths of each version of the table.
+var os2Lengths = []int{78, 86, 96, 96, 96, 100}
+
+// os2ShortLength is the length of version 0 tables that end before the
+// typographic metrics, which were added by Microsoft after Apple's version.
+const os2ShortLength = 68
 
+func parseTableOS2(tag Tag, buf []byte) (Table, error) {
+	if len(buf) == 0 {
+		return nil, io.EOF
+	}
 	var table tableOS2Fields
-	if err := binary.Read(r, binary.BigEndian, &table); err != nil {
-		// Different versions of the table are different lengths, as such
-		// we may not already read every field.
-		if err != io.ErrUnexpectedEOF {
-			return nil, err
-		}
 
-		// TODO Check the len(buf) is expected for this version
+	// Different versions of the table are different lengths, so the fields
+	// missing from older versions are read as zero.
+	padded := buf
+	if size := binary.Size(table); len(buf) < size {
+		padded = append(append([]byte{}, buf...), make([]byte, size-len(buf))...)
+	}
+	if err := binary.Read(bytes.NewReader(padded), binary.BigEndian, &table); err != nil {
+		return nil, err
 	}
 
 	return &TableOS2{
 		baseTable:      baseTable(tag),
 		tableOS2Fields: table,
-		bytes:          buf,
+		length:         len(buf),
 	}, nil
 }
 
+// Bytes returns the byte representation of this table, containing the fields
+// in its version.
 func (t *TableOS2) Bytes() []byte {
-	return t.bytes
+	var buf bytes.Buffer
+	if err := binary.Write(&buf, binary.BigEndian, t.tableOS2Fields); err != nil {
+		panic(err) // should never happen
+	}
+
+	length := buf.Len()
+	if int(t.Version) < len(os2Lengths) {
+		length = os2Lengths[t.Version]
+	}
+	if t.Version == 0 && t.length == os2ShortLength {
+		length = os2ShortLength
+	}
+	return buf.Bytes()[:length]
+}
+
+// UpgradeToVersion changes the version of the table, and initializes the fields
+// added since its current version: usBreakChar is set to the space character,
+// and usUpperOpticalPointSize to 0xFFFF so the font is used at every size. The
+// other new fields are set to 0, and fields that depend on the rest of the font
+// (such as ulCodePageRange, sxHeight and sCapHeight) should be set by the caller.
+func (t *TableOS2) UpgradeToVersion(version uint16) error {
+	if int(version) >= len(os2Lengths) {
+		return fmt.Errorf("unsupported OS/2 version %d", version)
+	}
+	if version < t.Version {
+		return fmt.Errorf("cannot downgrade OS/2 from version %d to %d", t.Version, version)
+	}
+
+	if t.Version == 0 && t.length == os2ShortLength {
+		t.STypoAscender, t.STypoDescender, t.STypoLineGap = 0, 0, 0
+		t.UsWinAscent, t.UsWinDescent = 0, 0
+	}
+	if t.Version < 1 {
+		t.UlCodePageRange1, t.UlCodePageRange2 = 0, 0
+	}
+	if t.Version < 2 {
+		t.SxHeigh, t.SCapHeight = 0, 0
+		t.UsDefaultChar, t.UsBreakChar, t.UsMaxContext = 0, ' ', 0
+	}
+	if t.Version < 5 {
+		t.UsLowerPointSize, t.UsUpperPointSize = 0, 0xFFFF
+	}
+	t.Version = version
+	t.length = os2Lengths[version]
+	return nil
+}
+
+// weightNames are the names of each hundred of usWeightClass, from the OpenType specification.
+var weightNames = []string{"Thin", "Extra-light", "Light", "Normal", "Medium", "Semi-bold", "Bold", "Extra-bold", "Black"}
+
+// widthNames are the names of each usWidthClass, from the OpenType specification.
+var widthNames = []string{"Ultra-condensed", "Extra-condensed", "Condensed", "Semi-condensed", "Medium", "Semi-expanded", "Expanded", "Extra-expanded", "Ultra-expanded"}
+
+// widthPercents are the percentages of normal width for each usWidthClass.
+var widthPercents = []float64{50, 62.5, 75, 87.5, 100, 112.5, 125, 150, 200}
+
+// WeightName returns the name of the font's usWeightClass, for example "Bold" for 700.
+func (t *TableOS2) WeightName() string {
+	return WeightName(t.USWeightClass)
+}
+
+// CSSFontWeight returns the value of the CSS font-weight property for the font,
+// which is its usWeightClass.
+func (t *TableOS2) CSSFontWeight() string {
+	info, _ := LookupAxis(axisWeight)
+	return info.CSSValue(float64(t.USWeightClass))
+}
+
+// WidthName returns the name of the font's usWidthClass, for example "Condensed" for 3.
+func (t *TableOS2) WidthName() string {
+	return WidthName(t.USWidthClass)
+}
+
+// CSSFontStretch returns the value of the CSS font-stretch property for the
+// font, for example "75%" for a usWidthClass of 3.
+func (t *TableOS2) CSSFontStretch() string {
+	info, _ := LookupAxis(axisWidth)
+	return info.CSSValue(WidthPercent(t.USWidthClass))
+}
+
+// WeightName returns the name of the weight class, rounded to the nearest 100,
+// or "" if it is not between 1 and 1000.
+func WeightName(weightClass uint16) string {
+	if weightClass < 1 || weightClass > 1000 {
+		return ""
+	}
+	i := (int(weightClass)+50)/100 - 1
+	if i < 0 {
+		i = 0
+	} else if i >= len(weightNames) {
+		i = len(weightNames) - 1
+	}
+	return weightNames[i]
+}
+
+// WidthName returns the name of the width class, or "" if it is not between 1 and 9.
+func WidthName(widthClass uint16) string {
+	if widthClass < 1 || int(widthClass) > len(widthNames) {
+		return ""
+	}
+	return widthNames[widthClass-1]
+}
+
+// WidthPercent returns the percentage of normal width of the width class, as
+// used by the CSS font-stretch property and the 'wdth' axis. Invalid classes
+// are treated as normal width.
+func WidthPercent(widthClass uint16) float64 {
+	if widthClass < 1 || int(widthClass) > len(widthPercents) {
+		return 100
+	}
+	return widthPercents[widthClass-1]
+}
+
+// WidthClass returns the width class closest to the given percentage of normal width.
+func WidthClass(percent float64) uint16 {
+	class := 0
+	for i, width := range widthPercents {
+		if math.Abs(width-percent) < math.Abs(widthPercents[class]-percent) {
+			class = i
+		}
+	}
+	return uint16(class + 1)
 }
@@ -0,0 +1,128 @@
+package sfnt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestWeightAndWidthClasses(t *testing.T) {
+	for _, test := range []struct {
+		class uint16
+		want  string
+	}{{0, ""}, {1, "Thin"}, {100, "Thin"}, {349, "Light"}, {350, "Normal"}, {700, "Bold"}, {950, "Black"}, {1000, "Black"}, {1001, ""}} {
+		if got := WeightName(test.class); got != test.want {
+			t.Errorf("WeightName(%d) = %q, want %q", test.class, got, test.want)
+		}
+	}
+
+	for class := uint16(1); class <= 9; class++ {
+		if got := WidthClass(WidthPercent(class)); got != class {
+			t.Errorf("WidthClass(WidthPercent(%d)) = %d, want %d", class, got, class)
+		}
+	}
+	if got := WidthClass(80); got != 3 {
+		t.Errorf("WidthClass(80) = %d, want 3", got)
+	}
+	if got, name := WidthPercent(0), WidthName(10); got != 100 || name != "" {
+		t.Errorf("WidthPercent(0), WidthName(10) = %v, %q, want 100, \"\"", got, name)
+	}
+
+	font := parseTestFont(t, "Roboto-BoldItalic.ttf")
+	os2, err := font.OS2Table()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if os2.USWeightClass != 700 || os2.WeightName() != "Bold" || os2.CSSFontWeight() != "700" {
+		t.Errorf("weight = %d, %q, %q, want 700, Bold, 700", os2.USWeightClass, os2.WeightName(), os2.CSSFontWeight())
+	}
+	if os2.WidthName() != "Medium" || os2.CSSFontStretch() != "100%" {
+		t.Errorf("width = %q, %q, want Medium, 100%%", os2.WidthName(), os2.CSSFontStretch())
+	}
+}
+
+func TestOS2RoundTrip(t *testing.T) {
+	for _, name := range []string{"Roboto-BoldItalic.ttf", "open-sans-v15-latin-regular.woff"} {
+		font := parseTestFont(t, name)
+		want, err := font.TableData(TagOS2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		table, err := parseTableOS2(TagOS2, want)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := table.Bytes(); !bytes.Equal(got, want) {
+			t.Errorf("%s: Bytes() = %x, want %x", name, got, want)
+		}
+	}
+}
+
+func TestOS2UpgradeToVersion(t *testing.T) {
+	font := parseTestFont(t, "Roboto-BoldItalic.ttf")
+	data, err := font.TableData(TagOS2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A version 1 table ends after the code page ranges.
+	v1 := append([]byte{}, data[:86]...)
+	binary.BigEndian.PutUint16(v1, 1)
+	table, err := parseTableOS2(TagOS2, v1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	os2 := table.(*TableOS2)
+	if got := os2.Bytes(); !bytes.Equal(got, v1) {
+		t.Errorf("version 1 Bytes() = %x, want %x", got, v1)
+	}
+
+	if err := os2.UpgradeToVersion(5); err != nil {
+		t.Fatal(err)
+	}
+	upgraded, err := parseTableOS2(TagOS2, os2.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := upgraded.(*TableOS2)
+	if len(os2.Bytes()) != 100 || got.Version != 5 || got.UsBreakChar != ' ' || got.UsUpperPointSize != 0xFFFF || got.USWeightClass != 700 {
+		t.Errorf("upgraded table = %+v, want version 5 with defaults", got.tableOS2Fields)
+	}
+
+	if err := os2.UpgradeToVersion(4); err == nil {
+		t.Errorf("UpgradeToVersion(4) from version 5 err = nil, want an error")
+	}
+	if err := os2.UpgradeToVersion(6); err == nil {
+		t.Errorf("UpgradeToVersion(6) err = nil, want an error")
+	}
+
+	// Apple's version 0 tables end before the typographic metrics.
+	short, err := parseTableOS2(TagOS2, append(append([]byte{}, 0, 0), data[2:68]...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(short.Bytes()); got != 68 {
+		t.Errorf("short version 0 Bytes() has length %d, want 68", got)
+	}
+}
+
+func TestOS2FamilyClass(t *testing.T) {
+	for _, test := range []struct {
+		value int16
+		want  string
+	}{
+		{0x0000, "No Classification"},
+		{0x0102, "Oldstyle Serifs / Garalde"},
+		{0x0800, "Sans Serif / No Classification"},
+		{0x080F, "Sans Serif / Miscellaneous"},
+		{0x0C07, "Symbolic / Neo-grotesque Sans Serif"},
+		{0x0C01, "Symbolic / Reserved 1"},
+		{0x0601, "Reserved 6 / Reserved 1"},
+	} {
+		os2 := &TableOS2{}
+		os2.SFamilyClass = test.value
+		if got := os2.FamilyClassName(); got != test.want {
+			t.Errorf("FamilyClassName() of 0x%04X = %q, want %q", test.value, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package sfnt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/ConradIrwin/font/aglfn"
+)
+
+var (
+	postVersion1 = fixed{Major: 1}
+	postVersion2 = fixed{Major: 2}
+	postVersion3 = fixed{Major: 3}
+)
+
+// TablePost represents the 'post' table, which contains information needed
+// to use the font on a PostScript printer, including the name of each glyph.
+// https://docs.microsoft.com/en-us/typography/opentype/spec/post
+type TablePost struct {
+	baseTable
+	tablePostFields
+
+	bytes []byte // bytes is the table as it was parsed.
+	names []string
+}
+
+type tablePostFields struct {
+	Version            fixed
+	ItalicAngle        fixed
+	UnderlinePosition  int16
+	UnderlineThickness int16
+	IsFixedPitch       uint32
+	MinMemType42       uint32
+	MaxMemType42       uint32
+	MinMemType1        uint32
+	MaxMemType1        uint32
+}
+
+func parseTablePost(tag Tag, buf []byte) (Table, error) {
+	r := bytes.NewReader(buf)
+
+	var fields tablePostFields
+	if err := binary.Read(r, binary.BigEndian, &fields); err != nil {
+		return nil, err
+	}
+
+	table := &TablePost{
+		baseTable:       baseTable(tag),
+		tablePostFields: fields,
+		bytes:           buf,
+	}
+
+	switch fields.Version {
+	case postVersion1:
+		table.names = macintoshGlyphNames[:]
+	case postVersion2:
+		names, err := parsePostNames(r)
+		if err != nil {
+			return nil, err
+		}
+		table.names = names
+	}
+
+	// Version 2.5 is deprecated, version 3 has no glyph names, and version 4
+	// (Apple-only) is not supported, so those are treated as having no names.
+	return table, nil
+}
+
+// parsePostNames reads the glyph names in a version 2.0 'post' table.
+func parsePostNames(r *bytes.Reader) ([]string, error) {
+	var numGlyphs uint16
+	if err := binary.Read(r, binary.BigEndian, &numGlyphs); err != nil {
+		return nil, err
+	}
+
+	indices := make([]uint16, numGlyphs)
+	if err := binary.Read(r, binary.BigEndian, &indices); err != nil {
+		return nil, fmt.Errorf("reading glyphNameIndex[%d]: %s", numGlyphs, err)
+	}
+
+	var strings []string
+	for r.Len() > 0 {
+		length, _ := r.ReadByte()
+		str := make([]byte, length)
+		if _, err := io.ReadFull(r, str); err != nil {
+			return nil, fmt.Errorf("reading glyph name %d: %s", len(strings), err)
+		}
+		strings = append(strings, string(str))
+	}
+
+	names := make([]string, numGlyphs)
+	for i, index := range indices {
+		if int(index) < len(macintoshGlyphNames) {
+			names[i] = macintoshGlyphNames[index]
+		} else if int(index)-len(macintoshGlyphNames) < len(strings) {
+			names[i] = strings[int(index)-len(macintoshGlyphNames)]
+		} else {
+			return nil, fmt.Errorf("invalid glyphNameIndex[%d] = %d", i, index)
+		}
+	}
+
+	return names, nil
+}
+
+// GlyphNames returns the name of each glyph, indexed by glyph id. It returns
+// nil if the table does not contain glyph names (i.e. is version 3.0).
+func (table *TablePost) GlyphNames() []string {
+	return table.names
+}
+
+// SetGlyphNames replaces the glyph names in the table, converting it to version 2.0.
+// If names is nil, the table is converted to version 3.0, which contains no glyph names.
+func (table *TablePost) SetGlyphNames(names []string) error {
+	for _, name := range names {
+		if len(name) > 255 {
+			return fmt.Errorf("glyph name %q is longer than 255 bytes", name)
+		}
+	}
+
+	table.names = names
+	if names == nil {
+		table.Version = postVersion3
+	} else {
+		table.Version = postVersion2
+	}
+	return nil
+}
+
+// Bytes returns the byte representation of this table. It is built from the
+// fields of the table each time, so that changes to them are kept.
+func (table *TablePost) Bytes() []byte {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.BigEndian, table.tablePostFields)
+
+	switch table.Version {
+	case postVersion1, postVersion3:
+	case postVersion2:
+		standard := make(map[string]int, len(macintoshGlyphNames))
+		for i, name := range macintoshGlyphNames {
+			standard[name] = i
+		}
+
+		var strings bytes.Buffer
+		custom := make(map[string]int)
+
+		binary.Write(&buf, binary.BigEndian, uint16(len(table.names)))
+		for _, name := range table.names {
+			index, found := standard[name]
+			if !found {
+				if index, found = custom[name]; !found {
+					index = len(macintoshGlyphNames) + len(custom)
+					custom[name] = index
+					strings.WriteByte(byte(len(name)))
+					strings.WriteString(name)
+				}
+			}
+			binary.Write(&buf, binary.BigEndian, uint16(index))
+		}
+		buf.Write(strings.Bytes())
+	default:
+		// The data of versions that are not parsed, such as 2.5, is kept.
+		if header := binary.Size(table.tablePostFields); len(table.bytes) > header {
+			buf.Write(table.bytes[header:])
+		}
+	}
+
+	return buf.Bytes()
+}
+
+// bytesVersion3 returns the byte representation of this table, without any glyph names.
+func (table *TablePost) bytesVersion3() []byte {
+	fields := table.tablePostFields
+	fields.Version = postVersion3
+
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.BigEndian, fields)
+	return buf.Bytes()
+}
+
+// GenerateGlyphNames returns a name for each of numGlyphs glyphs, suitable for
+// use with SetGlyphNames. Glyphs that map to a single character in runes are given
+// its name in the Adobe Glyph List For New Fonts, or if it has none (or the name
+// is already used), uniXXXX (or uXXXXX outside the Basic Multilingual Plane),
+// following the Adobe Glyph List Specification. Glyph 0 is named .notdef, and all
+// other glyphs glyphN.
+func GenerateGlyphNames(numGlyphs int, runes map[uint16]rune) []string {
+	names := make([]string, numGlyphs)
+	used := make(map[string]bool, numGlyphs)
+
+	for i := range names {
+		name := fmt.Sprintf("glyph%d", i)
+		if i == 0 {
+			name = ".notdef"
+		} else if r, found := runes[uint16(i)]; found {
+			for _, candidate := range []string{aglfn.Name(r), aglfn.UniName(r)} {
+				if !used[candidate] {
+					name = candidate
+					break
+				}
+			}
+		}
+
+		used[name] = true
+		names[i] = name
+	}
+
+	return names
+}
+
+// macintoshGlyphNames are the names of the 258 glyphs in the standard Macintosh
+// character set, which are used by version 1.0 of the 'post' table and referred
+// to by index in version 2.0.
+var macintoshGlyphNames = [...]string{
+	".notdef", ".null", "nonmarkingreturn", "space", "exclam", "quotedbl", "numbersign",
+	"dollar", "percent", "ampersand", "quotesingle", "parenleft", "parenright", "asterisk",
+	"plus", "comma", "hyphen", "period", "slash", "zero", "one", "two", "three", "four",
+	"five", "six", "seven", "eight", "nine", "colon", "semicolon", "less", "equal",
+	"greater", "question", "at", "A", "B", "C", "D", "E", "F", "G", "H", "I", "J", "K",
+	"L", "M", "N", "O", "P", "Q", "R", "S", "T", "U", "V", "W", "X", "Y", "Z",
+	"bracketleft", "backslash", "bracketright", "asciicircum", "underscore", "grave",
+	"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l", "m", "n", "o", "p", "q",
+	"r", "s", "t", "u", "v", "w", "x", "y", "z", "braceleft", "bar", "braceright",
+	"asciitilde", "Adieresis", "Aring", "Ccedilla", "Eacute", "Ntilde", "Odieresis",
+	"Udieresis", "aacute", "agrave", "acircumflex", "adieresis", "atilde", "aring",
+	"ccedilla", "eacute", "egrave", "ecircumflex", "edieresis", "iacute", "igrave",
+	"icircumflex", "idieresis", "ntilde", "oacute", "ograve", "ocircumflex", "odieresis",
+	"otilde", "uacute", "ugrave", "ucircumflex", "udieresis", "dagger", "degree", "cent",
+	"sterling", "section", "bullet", "paragraph", "germandbls", "registered", "copyright",
+	"trademark", "acute", "dieresis", "notequal", "AE", "Oslash", "infinity", "plusminus",
+	"lessequal", "greaterequal", "yen", "mu", "partialdiff", "summation", "product", "pi",
+	"integral", "ordfeminine", "ordmasculine", "Omega", "ae", "oslash", "questiondown",
+	"exclamdown", "logicalnot", "radical", "florin", "approxequal", "Delta",
+	"guillemotleft", "guillemotright", "ellipsis", "nonbreakingspace", "Agrave", "Atilde",
+	"Otilde", "OE", "oe", "endash", "emdash", "quotedblleft", "quotedblright", "quoteleft",
+	"quoteright", "divide", "lozenge", "ydieresis", "Ydieresis", "fraction", "currency",
+	"guilsinglleft", "guilsinglright", "fi", "fl", "daggerdbl", "periodcentered",
+	"quotesinglbase", "quotedblbase", "perthousand", "Acircumflex", "Ecircumflex",
+	"Aacute", "Edieresis", "Egrave", "Iacute", "Icircumflex", "Idieresis", "Igrave",
+	"Oacute", "Ocircumflex", "apple", "Ograve", "Uacute", "Ucircumflex", "Ugrave",
+	"dotlessi", "circumflex", "tilde", "macron", "breve", "dotaccent", "ring", "cedilla",
+	"hungarumlaut", "ogonek", "caron", "Lslash", "lslash", "Scaron", "scaron", "Zcaron",
+	"zcaron", "brokenbar", "Eth", "eth", "Yacute", "yacute", "Thorn", "thorn", "minus",
+	"multiply", "onesuperior", "twosuperior", "threesuperior", "onehalf", "onequarter",
+	"threequarters", "franc", "Gbreve", "gbreve", "Idotaccent", "Scedilla", "scedilla",
+	"Cacute", "cacute", "Ccaron", "ccaron", "dcroat",
+}
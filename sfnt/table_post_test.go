@@ -0,0 +1,82 @@
+package sfnt
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestMacintoshGlyphNames(t *testing.T) {
+	if len(macintoshGlyphNames) != 258 {
+		t.Errorf("len(macintoshGlyphNames) = %d, want 258", len(macintoshGlyphNames))
+	}
+	if macintoshGlyphNames[257] != "dcroat" {
+		t.Errorf("macintoshGlyphNames[257] = %q, want dcroat", macintoshGlyphNames[257])
+	}
+}
+
+func TestPostGlyphNames(t *testing.T) {
+	file, err := os.Open(filepath.Join("testdata", "open-sans-v15-latin-regular.woff"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	font, err := Parse(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	post, err := font.PostTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := post.GlyphNames()
+	if len(names) == 0 || names[0] != ".notdef" {
+		t.Fatalf("GlyphNames() = %v, want names starting with .notdef", names)
+	}
+
+	generated := GenerateGlyphNames(len(names), map[uint16]rune{1: 'A', 2: 'A', 3: 'A', 4: 0x1F600})
+	if want := []string{".notdef", "A", "uni0041", "glyph3", "u1F600"}; !reflect.DeepEqual(generated[:5], want) {
+		t.Errorf("GenerateGlyphNames() = %v, want %v", generated[:5], want)
+	}
+
+	if err := post.SetGlyphNames(generated); err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := parseTablePost(TagPost, post.Bytes())
+	if err != nil {
+		t.Fatalf("parseTablePost() err = %q, want nil", err)
+	}
+	if got := parsed.(*TablePost).GlyphNames(); !reflect.DeepEqual(got, generated) {
+		t.Errorf("GlyphNames() after SetGlyphNames = %v, want %v", got[:4], generated[:4])
+	}
+
+	// Changes to the fields are written, even once the table has been encoded.
+	post.ItalicAngle = fixed{Major: -12}
+	parsed, err = parseTablePost(TagPost, post.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := parsed.(*TablePost).ItalicAngle; got != post.ItalicAngle {
+		t.Errorf("ItalicAngle = %v after Bytes(), want %v", got, post.ItalicAngle)
+	}
+
+	var buf bytes.Buffer
+	if _, err := font.WriteOTFWithOptions(&buf, WriteOptions{DropGlyphNames: true}); err != nil {
+		t.Fatal(err)
+	}
+	font, err = Parse(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if post, err = font.PostTable(); err != nil {
+		t.Fatal(err)
+	}
+	if post.Version != postVersion3 || post.GlyphNames() != nil {
+		t.Errorf("post version = %v after DropGlyphNames, want 3.0 with no names", post.Version)
+	}
+}
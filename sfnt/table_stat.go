@@ -0,0 +1,202 @@
+package sfnt
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// TableStat represents the 'STAT' table, which describes the style of the font
+// along each design axis, so that applications can group the fonts of a family
+// and name them consistently.
+// https://docs.microsoft.com/en-us/typography/opentype/spec/stat
+type TableStat struct {
+	baseTable
+
+	Axes   []StatAxis
+	Values []StatAxisValue
+
+	// ElidedFallbackNameID is the entry in the 'name' table to use as the style
+	// name when all of the axis values are elided.
+	ElidedFallbackNameID NameID
+}
+
+// StatAxis is a design axis along which the fonts of a family vary.
+type StatAxis struct {
+	Tag      Tag
+	NameID   NameID
+	Ordering uint16 // Ordering determines the order of the axis value names in a style name.
+}
+
+// StatAxisValue names a position, or a range of positions, on one or more axes.
+type StatAxisValue struct {
+	Format uint16
+	Flags  uint16
+	NameID NameID
+
+	// Locations contains the position of the value on each axis. Formats 1, 2
+	// and 3 have exactly one location; format 4 has one for each axis it uses.
+	Locations []StatAxisLocation
+
+	RangeMin, RangeMax float64 // RangeMin and RangeMax are the range of values covered by a format 2 value.
+	LinkedValue        float64 // LinkedValue is the position of the style linked to a format 3 value, such as Bold for Regular.
+}
+
+// StatAxisLocation is a position on the axis with the given index in Axes.
+type StatAxisLocation struct {
+	AxisIndex uint16
+	Value     float64
+}
+
+const (
+	statOlderSiblingFontAttribute = 0x0001
+	statElidableAxisValueName     = 0x0002
+)
+
+// Elidable returns true if the name of the value should be left out of style
+// names, for example "Regular" in "Bold Regular".
+func (value StatAxisValue) Elidable() bool {
+	return value.Flags&statElidableAxisValueName != 0
+}
+
+// Matches returns true if the value applies to the position v on the axis with
+// the given index in Axes.
+func (value StatAxisValue) Matches(axis int, v float64) bool {
+	if len(value.Locations) != 1 || int(value.Locations[0].AxisIndex) != axis {
+		return false
+	}
+	if value.Format == 2 {
+		return v >= value.RangeMin && v <= value.RangeMax
+	}
+	return value.Locations[0].Value == v
+}
+
+func parseTableStat(tag Tag, buf []byte) (Table, error) {
+	if len(buf) < 18 {
+		return nil, fmt.Errorf("reading STAT header: unexpected EOF")
+	}
+	minorVersion := binary.BigEndian.Uint16(buf[2:])
+	axisSize := int(binary.BigEndian.Uint16(buf[4:]))
+	axisCount := int(binary.BigEndian.Uint16(buf[6:]))
+	axesOffset := int(binary.BigEndian.Uint32(buf[8:]))
+	valueCount := int(binary.BigEndian.Uint16(buf[12:]))
+	valuesOffset := int(binary.BigEndian.Uint32(buf[14:]))
+
+	table := &TableStat{baseTable: baseTable(tag), ElidedFallbackNameID: NameFontSubfamily}
+	if minorVersion > 0 {
+		if len(buf) < 20 {
+			return nil, fmt.Errorf("reading STAT header: unexpected EOF")
+		}
+		table.ElidedFallbackNameID = NameID(binary.BigEndian.Uint16(buf[18:]))
+	}
+
+	if axisSize < 8 || len(buf) < axesOffset+axisCount*axisSize {
+		return nil, fmt.Errorf("reading %d design axes: unexpected EOF", axisCount)
+	}
+	for i := 0; i < axisCount; i++ {
+		b := buf[axesOffset+i*axisSize:]
+		table.Axes = append(table.Axes, StatAxis{
+			Tag:      NewTag(b),
+			NameID:   NameID(binary.BigEndian.Uint16(b[4:])),
+			Ordering: binary.BigEndian.Uint16(b[6:]),
+		})
+	}
+
+	if len(buf) < valuesOffset+2*valueCount {
+		return nil, fmt.Errorf("reading %d axis values: unexpected EOF", valueCount)
+	}
+	for i := 0; i < valueCount; i++ {
+		value, err := parseStatAxisValue(buf, valuesOffset+int(binary.BigEndian.Uint16(buf[valuesOffset+2*i:])))
+		if err != nil {
+			return nil, fmt.Errorf("reading axis value %d: %s", i, err)
+		}
+		table.Values = append(table.Values, value)
+	}
+	return table, nil
+}
+
+func parseStatAxisValue(buf []byte, offset int) (StatAxisValue, error) {
+	var value StatAxisValue
+	if len(buf) < offset+8 {
+		return value, fmt.Errorf("unexpected EOF")
+	}
+	b := buf[offset:]
+	value.Format = binary.BigEndian.Uint16(b)
+	value.Flags = binary.BigEndian.Uint16(b[4:])
+	value.NameID = NameID(binary.BigEndian.Uint16(b[6:]))
+	fixed := func(i int) float64 {
+		return fixedToFloat(binary.BigEndian.Uint32(b[i:]))
+	}
+
+	switch value.Format {
+	case 1, 2, 3:
+		// Format 1 values are 12 bytes, format 2 adds a range and format 3 a linked value.
+		size := map[uint16]int{1: 12, 2: 20, 3: 16}[value.Format]
+		if len(b) < size {
+			return value, fmt.Errorf("unexpected EOF")
+		}
+		value.Locations = []StatAxisLocation{{binary.BigEndian.Uint16(b[2:]), fixed(8)}}
+		if value.Format == 2 {
+			value.RangeMin, value.RangeMax = fixed(12), fixed(16)
+		}
+		if value.Format == 3 {
+			value.LinkedValue = fixed(12)
+		}
+	case 4:
+		count := int(binary.BigEndian.Uint16(b[2:]))
+		if len(b) < 8+6*count {
+			return value, fmt.Errorf("unexpected EOF")
+		}
+		for i := 0; i < count; i++ {
+			value.Locations = append(value.Locations, StatAxisLocation{binary.BigEndian.Uint16(b[8+6*i:]), fixed(10 + 6*i)})
+		}
+	default:
+		return value, fmt.Errorf("unsupported axis value format %d", value.Format)
+	}
+	return value, nil
+}
+
+// Bytes returns the byte representation of this table.
+func (table *TableStat) Bytes() []byte {
+	const headerLength = 20
+	minorVersion := uint16(1)
+	var values []byte
+	offsets := make([]uint16, len(table.Values))
+	for i, value := range table.Values {
+		offsets[i] = uint16(2*len(table.Values) + len(values))
+		if value.Format == 4 {
+			minorVersion = 2
+			values = appendUint16s(values, 4, uint16(len(value.Locations)), value.Flags, uint16(value.NameID))
+			for _, location := range value.Locations {
+				values = appendUint16s(values, location.AxisIndex)
+				values = appendUint32s(values, floatToFixed(location.Value))
+			}
+			continue
+		}
+
+		var location StatAxisLocation
+		if len(value.Locations) > 0 {
+			location = value.Locations[0]
+		}
+		values = appendUint16s(values, value.Format, location.AxisIndex, value.Flags, uint16(value.NameID))
+		values = appendUint32s(values, floatToFixed(location.Value))
+		switch value.Format {
+		case 2:
+			values = appendUint32s(values, floatToFixed(value.RangeMin), floatToFixed(value.RangeMax))
+		case 3:
+			values = appendUint32s(values, floatToFixed(value.LinkedValue))
+		}
+	}
+
+	valuesOffset := headerLength + 8*len(table.Axes)
+	buf := appendUint16s(nil, 1, minorVersion, 8, uint16(len(table.Axes)))
+	buf = appendUint32s(buf, headerLength)
+	buf = appendUint16s(buf, uint16(len(table.Values)))
+	buf = appendUint32s(buf, uint32(valuesOffset))
+	buf = appendUint16s(buf, uint16(table.ElidedFallbackNameID))
+	for _, axis := range table.Axes {
+		buf = appendUint32s(buf, axis.Tag.Number)
+		buf = appendUint16s(buf, uint16(axis.NameID), axis.Ordering)
+	}
+	buf = appendUint16s(buf, offsets...)
+	return append(buf, values...)
+}
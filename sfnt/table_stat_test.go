@@ -0,0 +1,51 @@
+package sfnt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStatRoundTrip(t *testing.T) {
+	want := &TableStat{
+		baseTable: baseTable(TagStat),
+		Axes: []StatAxis{
+			{Tag: MustNamedTag("wght"), NameID: 256, Ordering: 0},
+			{Tag: MustNamedTag("ital"), NameID: 257, Ordering: 1},
+		},
+		Values: []StatAxisValue{
+			{Format: 1, NameID: 258, Locations: []StatAxisLocation{{0, 300}}},
+			{Format: 2, NameID: 259, Locations: []StatAxisLocation{{0, 500}}, RangeMin: 450, RangeMax: 550},
+			{Format: 3, Flags: statElidableAxisValueName, NameID: 2, Locations: []StatAxisLocation{{0, 400}}, LinkedValue: 700},
+			{Format: 4, NameID: 260, Locations: []StatAxisLocation{{0, 700}, {1, 1}}},
+		},
+		ElidedFallbackNameID: 2,
+	}
+
+	got, err := parseTableStat(TagStat, want.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseTableStat(Bytes()) = %+v, want %+v", got, want)
+	}
+
+	for _, test := range []struct {
+		value, axis int
+		v           float64
+		want        bool
+	}{
+		{0, 0, 300, true},
+		{0, 1, 300, false},
+		{1, 0, 460, true},
+		{1, 0, 600, false},
+		{2, 0, 400, true},
+		{3, 0, 700, false},
+	} {
+		if got := want.Values[test.value].Matches(test.axis, test.v); got != test.want {
+			t.Errorf("Values[%d].Matches(%d, %v) = %v, want %v", test.value, test.axis, test.v, got, test.want)
+		}
+	}
+	if !want.Values[2].Elidable() || want.Values[0].Elidable() {
+		t.Errorf("Elidable() = %v, %v, want false, true", want.Values[0].Elidable(), want.Values[2].Elidable())
+	}
+}
@@ -0,0 +1,56 @@
+package sfnt
+
+import (
+	"os"
+	"testing"
+)
+
+type customTable struct {
+	bytes []byte
+}
+
+func (c *customTable) Bytes() []byte { return c.bytes }
+func (c *customTable) Name() string  { return "Custom" }
+
+func TestRegisterTableParser(t *testing.T) {
+	tag := MustNamedTag("post")
+	original := tableParserFor(tag)
+	defer RegisterTableParser(tag, original)
+
+	RegisterTableParser(tag, func(tag Tag, buf []byte) (Table, error) {
+		return &customTable{buf}, nil
+	})
+
+	file, err := os.Open("testdata/Roboto-BoldItalic.ttf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	font, err := Parse(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	table, err := font.Table(tag)
+	if err != nil {
+		t.Fatalf("Table(%q) err = %q, want nil", tag, err)
+	}
+	if _, ok := table.(*customTable); !ok {
+		t.Errorf("Table(%q) = %T, want *customTable", tag, table)
+	}
+}
+
+func TestRegisterTableParserReplacingBuiltIn(t *testing.T) {
+	original := tableParserFor(TagHead)
+	defer RegisterTableParser(TagHead, original)
+
+	RegisterTableParser(TagHead, func(tag Tag, buf []byte) (Table, error) {
+		return &customTable{buf}, nil
+	})
+
+	font := parseTestFont(t, "Roboto-BoldItalic.ttf")
+	if _, err := font.HeadTable(); err == nil {
+		t.Errorf("HeadTable() err = nil, want an error for a *customTable")
+	}
+}
@@ -0,0 +1,147 @@
+package sfnt
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+)
+
+// TableTrak represents Apple's 'trak' table, which contains tracking (the
+// adjustment to the space between all glyphs) for different point sizes.
+// Apple platforms apply the normal track when laying out text, so that small
+// text is spaced more loosely and large text more tightly.
+//
+// See https://developer.apple.com/fonts/TrueType-Reference-Manual/RM06/Chap6trak.html
+type TableTrak struct {
+	baseTable
+
+	bytes []byte
+
+	Horizontal *TrackData // Horizontal contains the tracking for horizontal text (may be nil).
+	Vertical   *TrackData // Vertical contains the tracking for vertical text (may be nil).
+}
+
+// TrackData contains the tracks for one layout direction.
+type TrackData struct {
+	Sizes  []float64 // Sizes are the point sizes for which each track has a value, in increasing order.
+	Tracks []Track   // Tracks contains each track, for example loose, normal and tight.
+}
+
+// Track contains the tracking values for one track.
+type Track struct {
+	Value  float64 // Value identifies the track: 0 is normal, -1 is tight and 1 is loose.
+	NameID NameID  // NameID is the entry in the 'name' table containing the name of the track.
+	Values []int16 // Values contains the adjustment in font units at each of the Sizes.
+}
+
+// Bytes returns the bytes for this table. The TableTrak is read only, so
+// the bytes will always be the same as what is read in.
+func (t *TableTrak) Bytes() []byte {
+	return t.bytes
+}
+
+// Tracking returns the adjustment in font units to add to the advance of each
+// glyph in horizontal text at the given point size, using the normal track.
+// It returns 0 if the table has no normal horizontal track.
+func (t *TableTrak) Tracking(sizePt float64) float64 {
+	if t.Horizontal == nil {
+		return 0
+	}
+	return t.Horizontal.Tracking(0, sizePt)
+}
+
+// Tracking returns the adjustment in font units for the track with the given
+// value at the given point size. Sizes between those in the table are linearly
+// interpolated, and sizes outside them are extrapolated from the nearest two.
+// It returns 0 if there is no such track.
+func (d *TrackData) Tracking(track float64, sizePt float64) float64 {
+	var values []int16
+	for _, t := range d.Tracks {
+		if t.Value == track {
+			values = t.Values
+		}
+	}
+	if len(values) == 0 || len(values) != len(d.Sizes) {
+		return 0
+	}
+	if len(values) == 1 {
+		return float64(values[0])
+	}
+
+	// Find the pair of sizes around sizePt, or the nearest pair at either end.
+	i := sort.SearchFloat64s(d.Sizes, sizePt)
+	if i == 0 {
+		i = 1
+	} else if i == len(d.Sizes) {
+		i--
+	}
+	s0, s1 := d.Sizes[i-1], d.Sizes[i]
+	v0, v1 := float64(values[i-1]), float64(values[i])
+	if s0 == s1 {
+		return v0
+	}
+	return v0 + (v1-v0)*(sizePt-s0)/(s1-s0)
+}
+
+func parseTableTrak(tag Tag, buf []byte) (Table, error) {
+	if len(buf) < 12 {
+		return nil, fmt.Errorf("reading trak header: unexpected EOF")
+	}
+	if format := binary.BigEndian.Uint16(buf[4:]); format != 0 {
+		return nil, fmt.Errorf("unsupported trak format %d", format)
+	}
+
+	table := &TableTrak{baseTable: baseTable(tag), bytes: buf}
+	var err error
+	if offset := binary.BigEndian.Uint16(buf[6:]); offset != 0 {
+		if table.Horizontal, err = parseTrackData(buf, int(offset)); err != nil {
+			return nil, fmt.Errorf("reading horizontal tracks: %s", err)
+		}
+	}
+	if offset := binary.BigEndian.Uint16(buf[8:]); offset != 0 {
+		if table.Vertical, err = parseTrackData(buf, int(offset)); err != nil {
+			return nil, fmt.Errorf("reading vertical tracks: %s", err)
+		}
+	}
+	return table, nil
+}
+
+// parseTrackData parses the track data at offset. All offsets in the track data
+// are from the start of the table.
+func parseTrackData(buf []byte, offset int) (*TrackData, error) {
+	if len(buf) < offset+8 {
+		return nil, fmt.Errorf("unexpected EOF")
+	}
+	trackCount := int(binary.BigEndian.Uint16(buf[offset:]))
+	sizeCount := int(binary.BigEndian.Uint16(buf[offset+2:]))
+	sizesOffset := int(binary.BigEndian.Uint32(buf[offset+4:]))
+	if len(buf) < offset+8+8*trackCount || len(buf) < sizesOffset+4*sizeCount {
+		return nil, fmt.Errorf("reading %d tracks with %d sizes: unexpected EOF", trackCount, sizeCount)
+	}
+
+	data := &TrackData{Sizes: make([]float64, sizeCount)}
+	for i := range data.Sizes {
+		data.Sizes[i] = fixedToFloat(binary.BigEndian.Uint32(buf[sizesOffset+4*i:]))
+		if i > 0 && data.Sizes[i] < data.Sizes[i-1] {
+			return nil, fmt.Errorf("sizes are not in increasing order")
+		}
+	}
+
+	for i := 0; i < trackCount; i++ {
+		b := buf[offset+8+8*i:]
+		track := Track{
+			Value:  fixedToFloat(binary.BigEndian.Uint32(b)),
+			NameID: NameID(binary.BigEndian.Uint16(b[4:])),
+			Values: make([]int16, sizeCount),
+		}
+		valuesOffset := int(binary.BigEndian.Uint16(b[6:]))
+		if len(buf) < valuesOffset+2*sizeCount {
+			return nil, fmt.Errorf("reading track %d: unexpected EOF", i)
+		}
+		for j := range track.Values {
+			track.Values[j] = int16(binary.BigEndian.Uint16(buf[valuesOffset+2*j:]))
+		}
+		data.Tracks = append(data.Tracks, track)
+	}
+	return data, nil
+}
@@ -0,0 +1,45 @@
+package sfnt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTableTrak(t *testing.T) {
+	buf := writeBigEndian(t,
+		uint32(0x00010000), uint16(0), uint16(12), uint16(0), uint16(0), // version, format, horizontal, vertical, reserved
+		uint16(2), uint16(2), uint32(36), // nTracks, nSizes, sizeTableOffset
+		int32(-0x10000), uint16(256), uint16(44), // tight
+		int32(0), uint16(257), uint16(48), // normal
+		int32(12<<16), int32(24<<16),
+		int16(-10), int16(-20),
+		int16(20), int16(0),
+	)
+
+	table, err := parseTableTrak(TagTrak, buf)
+	if err != nil {
+		t.Fatalf("parseTableTrak() err = %q, want nil", err)
+	}
+	trak := table.(*TableTrak)
+	want := &TrackData{
+		Sizes: []float64{12, 24},
+		Tracks: []Track{
+			{Value: -1, NameID: 256, Values: []int16{-10, -20}},
+			{Value: 0, NameID: 257, Values: []int16{20, 0}},
+		},
+	}
+	if !reflect.DeepEqual(trak.Horizontal, want) || trak.Vertical != nil {
+		t.Errorf("parseTableTrak() = %+v, %+v, want %+v, nil", trak.Horizontal, trak.Vertical, want)
+	}
+
+	for _, test := range []struct {
+		size, want float64
+	}{{6, 30}, {12, 20}, {18, 10}, {24, 0}, {36, -20}} {
+		if got := trak.Tracking(test.size); got != test.want {
+			t.Errorf("Tracking(%v) = %v, want %v", test.size, got, test.want)
+		}
+	}
+	if got := trak.Horizontal.Tracking(-1, 18); got != -15 {
+		t.Errorf("Tracking(-1, 18) = %v, want -15", got)
+	}
+}
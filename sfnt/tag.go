@@ -10,6 +10,8 @@ import (
 var (
 	// TagHead represents the 'head' table, which contains the font header
 	TagHead = MustNamedTag("head")
+	// TagBhed represents Apple's 'bhed' table, which replaces 'head' in fonts that only have bitmaps
+	TagBhed = MustNamedTag("bhed")
 	// TagMaxp represents the 'maxp' table, which contains the maximum profile
 	TagMaxp = MustNamedTag("maxp")
 	// TagHmtx represents the 'hmtx' table, which contains the horizontal metrics
@@ -20,10 +22,52 @@ var (
 	TagOS2 = MustNamedTag("OS/2")
 	// TagName represents the 'name' table, which contains font name information
 	TagName = MustNamedTag("name")
+	// TagPost represents the 'post' table, which contains PostScript information
+	TagPost = MustNamedTag("post")
 	// TagGpos represents the 'GPOS' table, which contains Glyph Positioning features
 	TagGpos = MustNamedTag("GPOS")
 	// TagGsub represents the 'GSUB' table, which contains Glyph Substitution features
 	TagGsub = MustNamedTag("GSUB")
+	// TagBase represents the 'BASE' table, which contains Baseline data
+	TagBase = MustNamedTag("BASE")
+	// TagJstf represents the 'JSTF' table, which contains Justification data
+	TagJstf = MustNamedTag("JSTF")
+	// TagSilf represents the 'Silf' table, which contains Graphite rules
+	TagSilf = MustNamedTag("Silf")
+	// TagGlat represents the 'Glat' table, which contains Graphite glyph attributes
+	TagGlat = MustNamedTag("Glat")
+	// TagGloc represents the 'Gloc' table, which contains the location of Graphite glyph attributes
+	TagGloc = MustNamedTag("Gloc")
+	// TagFeat represents the 'Feat' table, which contains Graphite features
+	TagFeat = MustNamedTag("Feat")
+	// TagCmap represents the 'cmap' table, which maps characters to glyphs
+	TagCmap = MustNamedTag("cmap")
+	// TagGlyf represents the 'glyf' table, which contains TrueType glyph outlines
+	TagGlyf = MustNamedTag("glyf")
+	// TagLoca represents the 'loca' table, which contains the location of each glyph in the 'glyf' table
+	TagLoca = MustNamedTag("loca")
+	// TagCFF represents the 'CFF ' table, which contains PostScript glyph outlines
+	TagCFF = MustNamedTag("CFF ")
+	// TagFvar represents the 'fvar' table, which contains the axes of a variable font
+	TagFvar = MustNamedTag("fvar")
+	// TagAvar represents the 'avar' table, which maps the normalized coordinates of a variable font
+	TagAvar = MustNamedTag("avar")
+	// TagGvar represents the 'gvar' table, which contains the variations of TrueType glyph outlines
+	TagGvar = MustNamedTag("gvar")
+	// TagCvar represents the 'cvar' table, which contains the variations of the control values
+	TagCvar = MustNamedTag("cvar")
+	// TagStat represents the 'STAT' table, which contains the style attributes of each design axis
+	TagStat = MustNamedTag("STAT")
+	// TagGdef represents the 'GDEF' table, which contains glyph definitions used by GPOS and GSUB
+	TagGdef = MustNamedTag("GDEF")
+	// TagKern represents the 'kern' table, which contains legacy kerning
+	TagKern = MustNamedTag("kern")
+	// TagKerx represents Apple's 'kerx' table, which contains extended kerning
+	TagKerx = MustNamedTag("kerx")
+	// TagTrak represents Apple's 'trak' table, which contains tracking for each point size
+	TagTrak = MustNamedTag("trak")
+	// TagGasp represents the 'gasp' table, which contains the recommended rendering for each size
+	TagGasp = MustNamedTag("gasp")
 
 	// TypeTrueType is the first four bytes of an OpenType file containing a TrueType font
 	TypeTrueType = Tag{0x00010000}
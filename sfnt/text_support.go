@@ -0,0 +1,101 @@
+package sfnt
+
+import (
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	featureCcmp = MustNamedTag("ccmp")
+	featureMark = MustNamedTag("mark")
+)
+
+// TextSupport describes how well a font can render a piece of text.
+type TextSupport struct {
+	// Missing contains the characters that the font cannot render, in the
+	// order they first appear.
+	Missing []rune
+
+	// Decomposed contains the characters that the font does not map to a glyph,
+	// but that can be rendered from their canonical decomposition (NFD) into a
+	// base character and combining marks, which the font can compose with its
+	// 'ccmp' feature or position with its 'mark' feature.
+	Decomposed []rune
+}
+
+// Supported returns true if every character can be rendered.
+func (s TextSupport) Supported() bool {
+	return len(s.Missing) == 0
+}
+
+// SupportsText returns whether the font can render the text. A character that
+// the font does not map to a glyph may still be rendered after normalization,
+// as shaping engines compose a base character and combining marks into a
+// precomposed character the font supports, and fonts with mark positioning
+// can render a precomposed character from its decomposition. Control
+// characters, such as newlines, are ignored.
+func (font *Font) SupportsText(text string) (TextSupport, error) {
+	var support TextSupport
+	cmap, err := font.CmapTable()
+	if err != nil {
+		return support, err
+	}
+	mapped := func(s string) bool {
+		for _, r := range s {
+			if gid, found := cmap.Lookup(r); (!found || gid == 0) && !unicode.IsControl(r) {
+				return false
+			}
+		}
+		return true
+	}
+
+	canDecompose := false
+	for _, feature := range []struct{ table, tag Tag }{{TagGsub, featureCcmp}, {TagGpos, featureMark}} {
+		if !font.HasTable(feature.table) {
+			continue
+		}
+		layout, err := font.TableLayout(feature.table)
+		if err != nil {
+			return support, err
+		}
+		canDecompose = canDecompose || len(layout.featureLookups(feature.tag)) > 0
+	}
+
+	seen := map[rune]bool{}
+	for _, segment := range normSegments(text) {
+		if mapped(segment) || mapped(norm.NFC.String(segment)) {
+			continue
+		}
+		for _, r := range segment {
+			if seen[r] || mapped(string(r)) {
+				continue
+			}
+			seen[r] = true
+			if canDecompose && mapped(norm.NFD.String(string(r))) {
+				support.Decomposed = append(support.Decomposed, r)
+			} else {
+				support.Missing = append(support.Missing, r)
+			}
+		}
+	}
+	return support, nil
+}
+
+// normSegments splits text into segments that each start with a character
+// that does not combine with the characters before it, so that each segment
+// can be normalized on its own.
+func normSegments(text string) []string {
+	var segments []string
+	start := 0
+	for i, r := range text {
+		if i > start && norm.NFD.PropertiesString(string(r)).CCC() == 0 {
+			segments = append(segments, text[start:i])
+			start = i
+		}
+	}
+	if start < len(text) {
+		segments = append(segments, text[start:])
+	}
+	return segments
+}
@@ -0,0 +1,59 @@
+package sfnt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSupportsText(t *testing.T) {
+	b := NewBuilder(1000)
+	for _, g := range []struct {
+		r    rune
+		name string
+	}{{'a', "a"}, {'e', "e"}, {'é', "eacute"}, {0x0301, "acutecomb"}} {
+		b.Map(g.r, b.AddGlyph(g.name, 500, nil))
+	}
+	font, err := b.Font()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		text string
+		want TextSupport
+	}{
+		{"ae\n", TextSupport{}},
+		// The combining acute is mapped, but can't be positioned over the a.
+		{"eáx", TextSupport{Missing: []rune{'á', 'x'}}},
+		// The shaper composes e and a combining acute into é.
+		{"é", TextSupport{}},
+		{"xx", TextSupport{Missing: []rune{'x'}}},
+	}
+	for _, test := range tests {
+		got, err := font.SupportsText(test.text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("SupportsText(%q) = %+v, want %+v", test.text, got, test.want)
+		}
+	}
+
+	err = font.CompileFea(`
+markClass [acutecomb] <anchor 0 500> @TOP;
+feature mark { pos base [a] <anchor 250 500> mark @TOP; } mark;
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := font.SupportsText("eáx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (TextSupport{Missing: []rune{'x'}, Decomposed: []rune{'á'}}); !reflect.DeepEqual(got, want) {
+		t.Errorf("SupportsText() with mark positioning = %+v, want %+v", got, want)
+	}
+	if got.Supported() {
+		t.Errorf("Supported() = true with missing characters")
+	}
+}
@@ -0,0 +1,200 @@
+package sfnt
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+)
+
+// maxBfChars is the most entries that a bfchar section of a CMap may contain.
+const maxBfChars = 100
+
+// ToUnicodeCMap returns a PDF ToUnicode CMap stream for an embedded subset of
+// the font containing the given glyphs, so that text can be extracted from the
+// PDF. The character codes are two byte glyph ids, as used with the Identity-H
+// encoding, or CIDs for fonts with CID-keyed CFF outlines.
+//
+// The text of each glyph comes from the 'cmap' table, or if no character is
+// mapped to the glyph, from its name in the 'post' table, so that ligatures
+// such as "f_i" and alternates such as "a.sc" are also extracted. Glyphs with
+// no known text are left out.
+func (font *Font) ToUnicodeCMap(gids []uint16) ([]byte, error) {
+	texts, err := font.glyphTexts()
+	if err != nil {
+		return nil, err
+	}
+
+	var cff *TableCFF
+	if font.HasTable(TagCFF) {
+		if cff, err = font.CFFTable(); err != nil {
+			return nil, err
+		}
+	}
+
+	codes := map[uint16][]rune{}
+	for _, gid := range gids {
+		text := texts(gid)
+		if gid == 0 || text == nil {
+			continue
+		}
+		code := gid
+		if cff != nil && cff.IsCIDKeyed() {
+			cid, found := cff.CID(gid)
+			if !found {
+				continue
+			}
+			code = cid
+		}
+		codes[code] = text
+	}
+	sorted := make([]uint16, 0, len(codes))
+	for code := range codes {
+		sorted = append(sorted, code)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var buf bytes.Buffer
+	buf.WriteString("/CIDInit /ProcSet findresource begin\n" +
+		"12 dict begin\n" +
+		"begincmap\n" +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n" +
+		"/CMapName /Adobe-Identity-UCS def\n" +
+		"/CMapType 2 def\n" +
+		"1 begincodespacerange\n" +
+		"<0000> <FFFF>\n" +
+		"endcodespacerange\n")
+	for len(sorted) > 0 {
+		n := len(sorted)
+		if n > maxBfChars {
+			n = maxBfChars
+		}
+		fmt.Fprintf(&buf, "%d beginbfchar\n", n)
+		for _, code := range sorted[:n] {
+			fmt.Fprintf(&buf, "<%04X> <", code)
+			for _, unit := range utf16.Encode(codes[code]) {
+				fmt.Fprintf(&buf, "%04X", unit)
+			}
+			buf.WriteString(">\n")
+		}
+		buf.WriteString("endbfchar\n")
+		sorted = sorted[n:]
+	}
+	buf.WriteString("endcmap\n" +
+		"CMapName currentdict /CMap defineresource pop\n" +
+		"end\n" +
+		"end\n")
+	return buf.Bytes(), nil
+}
+
+// glyphTexts returns a function that returns the text of a glyph, using the
+// characters mapped to it by the 'cmap' table or its name in the 'post' table.
+func (font *Font) glyphTexts() (func(gid uint16) []rune, error) {
+	// A glyph used for several characters is given the one outside the private
+	// use area with the lowest code point.
+	chars := map[uint16]rune{}
+	if font.HasTable(TagCmap) {
+		cmap, err := font.CmapTable()
+		if err != nil {
+			return nil, err
+		}
+		if subtable := cmap.Unicode(); subtable != nil {
+			for r, gid := range subtable.Mapping {
+				existing, found := chars[gid]
+				if !found || preferredChar(r, existing) {
+					chars[gid] = r
+				}
+			}
+		}
+	}
+
+	var names []string
+	gids := map[string]uint16{}
+	if font.HasTable(TagPost) {
+		post, err := font.PostTable()
+		if err != nil {
+			return nil, err
+		}
+		names = post.GlyphNames()
+		for i, name := range names {
+			if _, found := gids[name]; !found {
+				gids[name] = uint16(i)
+			}
+		}
+	}
+
+	var text func(gid uint16, depth int) []rune
+	text = func(gid uint16, depth int) []rune {
+		if r, found := chars[gid]; found {
+			return []rune{r}
+		}
+		if int(gid) >= len(names) || depth > 1 {
+			return nil
+		}
+		name := names[gid]
+		if i := strings.IndexByte(name, '.'); i > 0 {
+			name = name[:i]
+		} else if i == 0 {
+			return nil
+		}
+		var runes []rune
+		for _, component := range strings.Split(name, "_") {
+			decoded := glyphNameRunes(component)
+			if decoded == nil {
+				if gid, found := gids[component]; found {
+					decoded = text(gid, depth+1)
+				}
+			}
+			if decoded == nil {
+				return nil
+			}
+			runes = append(runes, decoded...)
+		}
+		return runes
+	}
+	return func(gid uint16) []rune { return text(gid, 0) }, nil
+}
+
+// preferredChar returns true if r is a better choice than existing for the
+// text of a glyph.
+func preferredChar(r, existing rune) bool {
+	pua, existingPUA := unicode.Is(unicode.Co, r), unicode.Is(unicode.Co, existing)
+	if pua != existingPUA {
+		return !pua
+	}
+	return r < existing
+}
+
+// glyphNameRunes returns the characters named by a glyph name in the "uniXXXX"
+// or "uXXXXX" forms of the Adobe Glyph List Specification, or nil if the name
+// is not in either form.
+func glyphNameRunes(name string) []rune {
+	hex := func(s string) (rune, bool) {
+		v, err := strconv.ParseUint(s, 16, 32)
+		if err != nil || strings.ToUpper(s) != s || v > unicode.MaxRune || v >= 0xD800 && v <= 0xDFFF {
+			return 0, false
+		}
+		return rune(v), true
+	}
+
+	if strings.HasPrefix(name, "uni") && len(name) > 3 && (len(name)-3)%4 == 0 {
+		var runes []rune
+		for i := 3; i < len(name); i += 4 {
+			r, ok := hex(name[i : i+4])
+			if !ok {
+				return nil
+			}
+			runes = append(runes, r)
+		}
+		return runes
+	}
+	if strings.HasPrefix(name, "u") && len(name) >= 5 && len(name) <= 7 {
+		if r, ok := hex(name[1:]); ok {
+			return []rune{r}
+		}
+	}
+	return nil
+}
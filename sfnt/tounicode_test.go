@@ -0,0 +1,61 @@
+package sfnt
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestToUnicodeCMap(t *testing.T) {
+	b := NewBuilder(1000)
+	f := b.AddGlyph("f", 300, [][]GlyphPoint{square(0, 0, 100)})
+	b.Map('f', f)
+	i := b.AddGlyph("i", 300, [][]GlyphPoint{square(0, 0, 100)})
+	b.Map('i', i)
+	fi := b.AddGlyph("f_i", 600, [][]GlyphPoint{square(0, 0, 200)})
+	alternate := b.AddGlyph("f.ss01", 300, [][]GlyphPoint{square(0, 0, 150)})
+	emoji := b.AddGlyph("u1F600", 1000, [][]GlyphPoint{square(0, 0, 300)})
+	private := b.AddGlyph("logo", 1000, [][]GlyphPoint{square(0, 0, 400)})
+	b.Map(0xE000, private)
+	b.Map('L', private)
+	unknown := b.AddGlyph("ornament", 1000, [][]GlyphPoint{square(0, 0, 500)})
+	font, err := b.Font()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmap, err := font.ToUnicodeCMap([]uint16{0, f, i, fi, alternate, emoji, private, unknown})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "6 beginbfchar\n" +
+		"<0001> <0066>\n" +
+		"<0002> <0069>\n" +
+		"<0003> <00660069>\n" +
+		"<0004> <0066>\n" +
+		"<0005> <D83DDE00>\n" +
+		"<0006> <004C>\n" +
+		"endbfchar\n"
+	if !strings.Contains(string(cmap), want) {
+		t.Errorf("ToUnicodeCMap() = %s, want it to contain %s", cmap, want)
+	}
+	if !strings.HasPrefix(string(cmap), "/CIDInit /ProcSet findresource begin\n") || !strings.HasSuffix(string(cmap), "end\nend\n") {
+		t.Errorf("ToUnicodeCMap() = %s, want a complete CMap", cmap)
+	}
+}
+
+func TestGlyphNameRunes(t *testing.T) {
+	for name, want := range map[string]string{
+		"uni0041":     "A",
+		"uni00410042": "AB",
+		"u1F600":      "\U0001F600",
+		"uni004":      "",
+		"uni0041x":    "",
+		"uniD800":     "",
+		"uni00e9":     "",
+		"a":           "",
+	} {
+		if got := string(glyphNameRunes(name)); got != want {
+			t.Errorf("glyphNameRunes(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,584 @@
+package sfnt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// ErrType1 is returned from Parse if the file is a PostScript Type 1 font,
+// which can be converted to an OpenType font with ConvertType1.
+var ErrType1 = errors.New("PostScript Type 1 font (use ConvertType1 to convert it to OpenType)")
+
+// IsType1 returns true if data starts like a PostScript Type 1 font, either in
+// the binary PFB format used on Windows or the ASCII PFA format used on Unix.
+func IsType1(data []byte) bool {
+	return bytes.HasPrefix(data, []byte{0x80, 0x01}) ||
+		bytes.HasPrefix(data, []byte("%!PS-AdobeFont")) ||
+		bytes.HasPrefix(data, []byte("%!FontType1"))
+}
+
+// Keys used to decrypt the encrypted parts of a Type 1 font.
+// https://adobe-type-tools.github.io/font-tech-notes/pdfs/T1_SPEC.pdf
+const (
+	type1EexecKey      = 55665
+	type1CharStringKey = 4330
+	type1DefaultLenIV  = 4
+)
+
+// type1Font contains the parts of a PostScript Type 1 font needed to convert
+// it to OpenType.
+type type1Font struct {
+	FontName           string
+	FamilyName         string
+	FullName           string
+	Weight             string
+	Notice             string
+	Version            string
+	ItalicAngle        float64
+	UnderlinePosition  float64
+	UnderlineThickness float64
+	IsFixedPitch       bool
+	FontMatrix         []float64
+	FontBBox           []float64
+
+	// Encoding maps character codes to glyph names, with "" for unused codes.
+	Encoding [256]string
+
+	// Private contains the numeric values in the Private dictionary, such as
+	// BlueValues, with single numbers stored as one element arrays.
+	Private map[string][]float64
+
+	subrs       [][]byte          // subrs are the decrypted subroutines.
+	charStrings map[string][]byte // charStrings are the decrypted glyph programs.
+	names       []string          // names are the glyph names in the order they are defined.
+}
+
+// parseType1 reads a Type 1 font in PFB or PFA format.
+func parseType1(data []byte) (*type1Font, error) {
+	clear, encrypted, err := splitType1(data)
+	if err != nil {
+		return nil, err
+	}
+
+	font := &type1Font{
+		UnderlinePosition:  -100,
+		UnderlineThickness: 50,
+		Private:            map[string][]float64{},
+		charStrings:        map[string][]byte{},
+	}
+	if err := font.parseFontDict(clear); err != nil {
+		return nil, err
+	}
+	if err := font.parsePrivate(type1Decrypt(encrypted, type1EexecKey, 4)); err != nil {
+		return nil, err
+	}
+	if len(font.charStrings) == 0 {
+		return nil, fmt.Errorf("reading Type 1 font: no CharStrings")
+	}
+	return font, nil
+}
+
+// splitType1 returns the clear text and the encrypted parts of a Type 1 font.
+func splitType1(data []byte) (clear, encrypted []byte, err error) {
+	if !IsType1(data) {
+		return nil, nil, fmt.Errorf("not a Type 1 font")
+	}
+
+	// PFB files are made of segments of ASCII or binary data.
+	if data[0] == 0x80 {
+		for len(data) >= 2 && data[0] == 0x80 && data[1] != 3 {
+			if len(data) < 6 {
+				return nil, nil, fmt.Errorf("reading PFB segment: %s", errCFFTruncated)
+			}
+			kind, length := data[1], int(binary.LittleEndian.Uint32(data[2:]))
+			if length < 0 || length > len(data)-6 {
+				return nil, nil, fmt.Errorf("PFB segment has length %d, but only %d bytes remain", length, len(data)-6)
+			}
+			segment := data[6 : 6+length]
+			switch {
+			case kind == 1 && encrypted == nil:
+				clear = append(clear, segment...)
+			case kind == 2:
+				encrypted = append(encrypted, segment...)
+			case kind != 1:
+				return nil, nil, fmt.Errorf("unknown PFB segment type %d", kind)
+			}
+			data = data[6+length:]
+		}
+		if encrypted == nil {
+			return nil, nil, fmt.Errorf("PFB file has no encrypted section")
+		}
+		return clear, encrypted, nil
+	}
+
+	// PFA files have the encrypted part after "eexec", usually in hexadecimal.
+	i := bytes.Index(data, []byte("eexec"))
+	if i < 0 {
+		return nil, nil, fmt.Errorf("PFA file has no encrypted section")
+	}
+	clear, rest := data[:i+5], bytes.TrimLeft(data[i+5:], " \t\r\n")
+	if len(rest) >= 4 && isHexDigit(rest[0]) && isHexDigit(rest[1]) && isHexDigit(rest[2]) && isHexDigit(rest[3]) {
+		return clear, decodeHexDigits(rest), nil
+	}
+	return clear, rest, nil
+}
+
+func isHexDigit(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+// decodeHexDigits decodes pairs of hexadecimal digits, ignoring whitespace,
+// up to the first other character.
+func decodeHexDigits(hex []byte) []byte {
+	var out []byte
+	var digits [2]byte
+	n := 0
+	for _, c := range hex {
+		if c == ' ' || c == '\t' || c == '\r' || c == '\n' {
+			continue
+		}
+		if !isHexDigit(c) {
+			break
+		}
+		digits[n] = c
+		if n++; n == 2 {
+			v, _ := strconv.ParseUint(string(digits[:]), 16, 8)
+			out = append(out, byte(v))
+			n = 0
+		}
+	}
+	return out
+}
+
+// type1Decrypt decrypts data with the given key, and discards the first skip
+// bytes, which are random.
+func type1Decrypt(data []byte, key uint16, skip int) []byte {
+	out := make([]byte, len(data))
+	r := key
+	for i, c := range data {
+		out[i] = c ^ byte(r>>8)
+		r = (uint16(c)+r)*52845 + 22719
+	}
+	if skip > len(out) {
+		return nil
+	}
+	return out[skip:]
+}
+
+// parseFontDict reads the names, metrics and encoding from the clear text
+// part of the font.
+func (font *type1Font) parseFontDict(clear []byte) error {
+	lex := &psLexer{buf: clear}
+	for {
+		tok, ok := lex.next()
+		if !ok {
+			return nil
+		}
+		if tok.kind != psName {
+			continue
+		}
+		switch tok.text {
+		case "FontName":
+			font.FontName = lex.nextName()
+		case "FamilyName":
+			font.FamilyName = lex.nextString()
+		case "FullName":
+			font.FullName = lex.nextString()
+		case "Weight":
+			font.Weight = lex.nextString()
+		case "Notice":
+			font.Notice = lex.nextString()
+		case "version":
+			font.Version = lex.nextString()
+		case "ItalicAngle":
+			font.ItalicAngle = lex.nextNumber()
+		case "UnderlinePosition":
+			font.UnderlinePosition = lex.nextNumber()
+		case "UnderlineThickness":
+			font.UnderlineThickness = lex.nextNumber()
+		case "isFixedPitch":
+			tok, _ := lex.next()
+			font.IsFixedPitch = tok.kind == psWord && tok.text == "true"
+		case "FontMatrix":
+			font.FontMatrix = lex.nextArray()
+		case "FontBBox":
+			font.FontBBox = lex.nextArray()
+		case "Encoding":
+			if err := font.parseEncoding(lex); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// parseEncoding reads an encoding, which is either StandardEncoding or an
+// array set by "dup code /name put" entries.
+func (font *type1Font) parseEncoding(lex *psLexer) error {
+	tok, ok := lex.next()
+	if ok && tok.kind == psWord && tok.text == "StandardEncoding" {
+		font.Encoding = standardEncoding
+		return nil
+	}
+	for ok && !(tok.kind == psWord && (tok.text == "def" || tok.text == "readonly")) {
+		if tok.kind == psWord && tok.text == "dup" {
+			code, name := lex.nextNumber(), lex.nextName()
+			if code < 0 || code > 255 {
+				return fmt.Errorf("reading Encoding: invalid character code %v", code)
+			}
+			font.Encoding[int(code)] = name
+		}
+		tok, ok = lex.next()
+	}
+	return nil
+}
+
+// parsePrivate reads the hinting values, subroutines and glyphs from the
+// decrypted part of the font.
+func (font *type1Font) parsePrivate(private []byte) error {
+	lex := &psLexer{buf: private}
+	lenIV := type1DefaultLenIV
+	for {
+		tok, ok := lex.next()
+		if !ok {
+			break
+		}
+		if tok.kind != psName {
+			continue
+		}
+		switch tok.text {
+		case "lenIV":
+			lenIV = int(lex.nextNumber())
+		case "BlueValues", "OtherBlues", "FamilyBlues", "FamilyOtherBlues", "StdHW", "StdVW", "StemSnapH", "StemSnapV":
+			font.Private[tok.text] = lex.nextArray()
+		case "BlueScale", "BlueShift", "BlueFuzz":
+			font.Private[tok.text] = []float64{lex.nextNumber()}
+		case "ForceBold":
+			if value, _ := lex.next(); value.kind == psWord && value.text == "true" {
+				font.Private[tok.text] = []float64{1}
+			}
+		case "Subrs":
+			if err := font.parseSubrs(lex); err != nil {
+				return err
+			}
+		case "CharStrings":
+			if err := font.parseCharStrings(lex); err != nil {
+				return err
+			}
+		}
+	}
+
+	decrypt := func(cs []byte) []byte {
+		if lenIV < 0 {
+			return cs
+		}
+		return type1Decrypt(cs, type1CharStringKey, lenIV)
+	}
+	for i, subr := range font.subrs {
+		font.subrs[i] = decrypt(subr)
+	}
+	for name, cs := range font.charStrings {
+		font.charStrings[name] = decrypt(cs)
+	}
+	return nil
+}
+
+// parseSubrs reads the "dup index length RD <binary> NP" entries of the Subrs
+// array.
+func (font *type1Font) parseSubrs(lex *psLexer) error {
+	count := int(lex.nextNumber())
+	if count < 0 || count > 65536 {
+		return fmt.Errorf("reading Subrs: invalid count %d", count)
+	}
+	font.subrs = make([][]byte, count)
+	for {
+		save := lex.pos
+		tok, ok := lex.next()
+		if !ok {
+			return nil
+		}
+		// Entries end with NP, which may be written out as "noaccess put".
+		if tok.kind == psWord && (tok.text == "array" || tok.text == "NP" || tok.text == "|" || tok.text == "noaccess" || tok.text == "put") {
+			continue
+		}
+		if tok.kind != psWord || tok.text != "dup" {
+			lex.pos = save
+			return nil
+		}
+		index := int(lex.nextNumber())
+		data, ok := lex.nextBinary()
+		if !ok || index < 0 || index >= count {
+			return fmt.Errorf("reading Subrs: invalid subroutine %d", index)
+		}
+		font.subrs[index] = data
+	}
+}
+
+// parseCharStrings reads the "/name length RD <binary> ND" entries of the
+// CharStrings dictionary.
+func (font *type1Font) parseCharStrings(lex *psLexer) error {
+	for {
+		tok, ok := lex.next()
+		if !ok || tok.kind == psWord && tok.text == "end" {
+			return nil
+		}
+		if tok.kind != psName {
+			continue
+		}
+		data, ok := lex.nextBinary()
+		if !ok {
+			return fmt.Errorf("reading CharStrings: glyph %q has no charstring", tok.text)
+		}
+		if _, found := font.charStrings[tok.text]; !found {
+			font.names = append(font.names, tok.text)
+		}
+		font.charStrings[tok.text] = data
+	}
+}
+
+type psKind int
+
+const (
+	psWord psKind = iota
+	psName
+	psNumber
+	psString
+	psBinary
+)
+
+// psToken is a token of PostScript, such as a name (/FontName) or number.
+type psToken struct {
+	kind psKind
+	text string
+	data []byte
+}
+
+// psLexer splits the PostScript program of a Type 1 font into tokens. It
+// understands just enough PostScript to find the values in font dictionaries,
+// including the binary data that follows "RD" (or "-|").
+type psLexer struct {
+	buf  []byte
+	pos  int
+	prev psToken
+}
+
+func isPSDelimiter(c byte) bool {
+	switch c {
+	case ' ', '\t', '\r', '\n', '\f', 0, '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return false
+}
+
+// next returns the next token, or false at the end of the program.
+func (lex *psLexer) next() (psToken, bool) {
+	tok, ok := lex.scan()
+	lex.prev = tok
+	return tok, ok
+}
+
+func (lex *psLexer) scan() (psToken, bool) {
+	for lex.pos < len(lex.buf) {
+		c := lex.buf[lex.pos]
+		switch {
+		case c == '%':
+			for lex.pos < len(lex.buf) && lex.buf[lex.pos] != '\n' && lex.buf[lex.pos] != '\r' {
+				lex.pos++
+			}
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '\f' || c == 0:
+			lex.pos++
+		case c == '(':
+			return psToken{kind: psString, text: lex.scanString()}, true
+		case c == '[' || c == ']' || c == '{' || c == '}':
+			lex.pos++
+			return psToken{kind: psWord, text: string(c)}, true
+		case c == '<' || c == '>':
+			start := lex.pos
+			lex.pos++
+			if lex.pos < len(lex.buf) && lex.buf[lex.pos] == c {
+				lex.pos++
+			} else if c == '<' {
+				// Hexadecimal strings are not used by the values we read.
+				for lex.pos < len(lex.buf) && lex.buf[lex.pos] != '>' {
+					lex.pos++
+				}
+				lex.pos++
+			}
+			return psToken{kind: psWord, text: string(lex.buf[start:min(lex.pos, len(lex.buf))])}, true
+		case c == '/':
+			lex.pos++
+			return psToken{kind: psName, text: lex.scanWord()}, true
+		default:
+			word := lex.scanWord()
+			if word == "" {
+				lex.pos++
+				continue
+			}
+			if _, err := strconv.ParseFloat(word, 64); err == nil {
+				return psToken{kind: psNumber, text: word}, true
+			}
+			if (word == "RD" || word == "-|") && lex.prev.kind == psNumber {
+				n, _ := strconv.Atoi(lex.prev.text)
+				start := lex.pos + 1 // A single space separates RD from the data.
+				if n < 0 || start+n > len(lex.buf) {
+					lex.pos = len(lex.buf)
+					return psToken{}, false
+				}
+				lex.pos = start + n
+				return psToken{kind: psBinary, data: lex.buf[start : start+n]}, true
+			}
+			return psToken{kind: psWord, text: word}, true
+		}
+	}
+	return psToken{}, false
+}
+
+func (lex *psLexer) scanWord() string {
+	start := lex.pos
+	for lex.pos < len(lex.buf) && !isPSDelimiter(lex.buf[lex.pos]) {
+		lex.pos++
+	}
+	return string(lex.buf[start:lex.pos])
+}
+
+// scanString reads a string in parentheses, which may contain balanced
+// parentheses and backslash escapes.
+func (lex *psLexer) scanString() string {
+	var out []byte
+	depth := 0
+	for lex.pos++; lex.pos < len(lex.buf); lex.pos++ {
+		c := lex.buf[lex.pos]
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				lex.pos++
+				return string(out)
+			}
+			depth--
+		case '\\':
+			lex.pos++
+			if lex.pos >= len(lex.buf) {
+				return string(out)
+			}
+			c = lex.buf[lex.pos]
+			switch c {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r', '\n':
+				continue
+			default:
+				if '0' <= c && c <= '7' {
+					v := 0
+					for i := 0; i < 3 && lex.pos < len(lex.buf) && '0' <= lex.buf[lex.pos] && lex.buf[lex.pos] <= '7'; i++ {
+						v = v*8 + int(lex.buf[lex.pos]-'0')
+						lex.pos++
+					}
+					lex.pos--
+					c = byte(v)
+				}
+			}
+		}
+		out = append(out, c)
+	}
+	return string(out)
+}
+
+// nextName returns the next token if it is a name, or "".
+func (lex *psLexer) nextName() string {
+	if tok, ok := lex.next(); ok && tok.kind == psName {
+		return tok.text
+	}
+	return ""
+}
+
+// nextString returns the next token if it is a string, or "".
+func (lex *psLexer) nextString() string {
+	if tok, ok := lex.next(); ok && tok.kind == psString {
+		return tok.text
+	}
+	return ""
+}
+
+// nextNumber returns the next token if it is a number, or 0.
+func (lex *psLexer) nextNumber() float64 {
+	if tok, ok := lex.next(); ok && tok.kind == psNumber {
+		v, _ := strconv.ParseFloat(tok.text, 64)
+		return v
+	}
+	return 0
+}
+
+// nextArray returns the numbers in the next array (or procedure).
+func (lex *psLexer) nextArray() []float64 {
+	tok, ok := lex.next()
+	if !ok || tok.kind != psWord || (tok.text != "[" && tok.text != "{") {
+		return nil
+	}
+	values := []float64{}
+	for {
+		tok, ok := lex.next()
+		if !ok || tok.kind == psWord && (tok.text == "]" || tok.text == "}") {
+			return values
+		}
+		if tok.kind == psNumber {
+			v, _ := strconv.ParseFloat(tok.text, 64)
+			values = append(values, v)
+		}
+	}
+}
+
+// nextBinary returns the data of the next "length RD <binary>" sequence.
+func (lex *psLexer) nextBinary() ([]byte, bool) {
+	for i := 0; i < 2; i++ {
+		tok, ok := lex.next()
+		if !ok {
+			return nil, false
+		}
+		if tok.kind == psBinary {
+			return tok.data, true
+		}
+	}
+	return nil, false
+}
+
+// standardEncoding is Adobe's StandardEncoding, which most Type 1 text fonts use.
+var standardEncoding = [256]string{
+	32: "space", "exclam", "quotedbl", "numbersign", "dollar", "percent", "ampersand", "quoteright",
+	"parenleft", "parenright", "asterisk", "plus", "comma", "hyphen", "period", "slash",
+	"zero", "one", "two", "three", "four", "five", "six", "seven",
+	"eight", "nine", "colon", "semicolon", "less", "equal", "greater", "question",
+	"at", "A", "B", "C", "D", "E", "F", "G",
+	"H", "I", "J", "K", "L", "M", "N", "O",
+	"P", "Q", "R", "S", "T", "U", "V", "W",
+	"X", "Y", "Z", "bracketleft", "backslash", "bracketright", "asciicircum", "underscore",
+	"quoteleft", "a", "b", "c", "d", "e", "f", "g",
+	"h", "i", "j", "k", "l", "m", "n", "o",
+	"p", "q", "r", "s", "t", "u", "v", "w",
+	"x", "y", "z", "braceleft", "bar", "braceright", "asciitilde",
+	161: "exclamdown", "cent", "sterling", "fraction", "yen", "florin", "section",
+	"currency", "quotesingle", "quotedblleft", "guillemotleft", "guilsinglleft", "guilsinglright", "fi", "fl",
+	177: "endash", "dagger", "daggerdbl", "periodcentered",
+	182: "paragraph", "bullet", "quotesinglbase", "quotedblbase", "quotedblright", "guillemotright", "ellipsis", "perthousand",
+	191: "questiondown",
+	193: "grave", "acute", "circumflex", "tilde", "macron", "breve", "dotaccent", "dieresis",
+	202: "ring", "cedilla",
+	205: "hungarumlaut", "ogonek", "caron", "emdash",
+	225: "AE",
+	227: "ordfeminine",
+	232: "Lslash", "Oslash", "OE", "ordmasculine",
+	241: "ae",
+	245: "dotlessi",
+	248: "lslash", "oslash", "oe", "germandbls",
+}
@@ -0,0 +1,468 @@
+package sfnt
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// type1MaxSubrDepth limits the nesting of subroutine calls and accented
+// characters, so that malformed fonts cannot recurse forever.
+const type1MaxSubrDepth = 10
+
+// type1Segment is a segment of a glyph outline in absolute coordinates. The
+// moveto and lineto segments use only the last point.
+type type1Segment struct {
+	op     byte // op is 'M' for moveto, 'L' for lineto or 'C' for curveto.
+	points [3]vector
+}
+
+// type1Glyph is the outline and advance width of a Type 1 glyph.
+type type1Glyph struct {
+	width float64
+	path  []type1Segment
+}
+
+// glyph runs the charstring for the named glyph, and returns its outline.
+// Hints are discarded, so converted fonts are unhinted.
+func (font *type1Font) glyph(name string) (*type1Glyph, error) {
+	return font.glyphAt(name, 0)
+}
+
+func (font *type1Font) glyphAt(name string, depth int) (*type1Glyph, error) {
+	cs, found := font.charStrings[name]
+	if !found {
+		return nil, fmt.Errorf("glyph %q is not in the font", name)
+	}
+	in := &type1Interpreter{font: font, depth: depth}
+	if err := in.run(cs, 0); err != nil {
+		return nil, fmt.Errorf("glyph %q: %s", name, err)
+	}
+	return &type1Glyph{width: in.width, path: in.path}, nil
+}
+
+// type1Interpreter runs Type 1 charstrings.
+type type1Interpreter struct {
+	font  *type1Font
+	depth int // depth is the nesting of accented characters.
+
+	stack []float64
+	path  []type1Segment
+
+	x, y     float64 // x and y are the current point.
+	sbx      float64 // sbx is the x coordinate of the left side bearing point.
+	width    float64
+	flexing  bool
+	flex     []vector // flex contains the points of a flex hint.
+	finished bool
+}
+
+// args returns the last n values on the stack, or an error if there are fewer.
+func (in *type1Interpreter) args(n int) ([]float64, error) {
+	if len(in.stack) < n {
+		return nil, fmt.Errorf("operator needs %d arguments, but the stack has %d", n, len(in.stack))
+	}
+	return in.stack[len(in.stack)-n:], nil
+}
+
+func (in *type1Interpreter) moveTo(dx, dy float64) {
+	in.x, in.y = in.x+dx, in.y+dy
+	if in.flexing {
+		in.flex = append(in.flex, vector{in.x, in.y})
+		return
+	}
+	in.path = append(in.path, type1Segment{op: 'M', points: [3]vector{{}, {}, {in.x, in.y}}})
+}
+
+func (in *type1Interpreter) lineTo(dx, dy float64) {
+	in.x, in.y = in.x+dx, in.y+dy
+	in.path = append(in.path, type1Segment{op: 'L', points: [3]vector{{}, {}, {in.x, in.y}}})
+}
+
+func (in *type1Interpreter) curveTo(dx1, dy1, dx2, dy2, dx3, dy3 float64) {
+	p1 := vector{in.x + dx1, in.y + dy1}
+	p2 := vector{p1.X + dx2, p1.Y + dy2}
+	p3 := vector{p2.X + dx3, p2.Y + dy3}
+	in.x, in.y = p3.X, p3.Y
+	in.path = append(in.path, type1Segment{op: 'C', points: [3]vector{p1, p2, p3}})
+}
+
+// run interprets a charstring. Calls to subroutines run them recursively, and
+// share the operand stack.
+func (in *type1Interpreter) run(cs []byte, depth int) error {
+	if depth > type1MaxSubrDepth {
+		return fmt.Errorf("subroutines are nested too deeply")
+	}
+	for i := 0; i < len(cs) && !in.finished; {
+		b := cs[i]
+		i++
+		switch {
+		case b >= 32 && b <= 246:
+			in.stack = append(in.stack, float64(int(b)-139))
+			continue
+		case b >= 247 && b <= 254:
+			if i >= len(cs) {
+				return errCFFTruncated
+			}
+			if b <= 250 {
+				in.stack = append(in.stack, float64((int(b)-247)*256+int(cs[i])+108))
+			} else {
+				in.stack = append(in.stack, float64(-(int(b)-251)*256-int(cs[i])-108))
+			}
+			i++
+			continue
+		case b == 255:
+			if i+4 > len(cs) {
+				return errCFFTruncated
+			}
+			in.stack = append(in.stack, float64(int32(binary.BigEndian.Uint32(cs[i:]))))
+			i += 4
+			continue
+		}
+
+		op := int(b)
+		if b == 12 {
+			if i >= len(cs) {
+				return errCFFTruncated
+			}
+			op = 1200 + int(cs[i])
+			i++
+		}
+		if len(in.stack) > 48 {
+			return fmt.Errorf("operand stack overflow")
+		}
+
+		switch op {
+		case 1, 3, 1200, 1201, 1202: // hstem, vstem, dotsection, vstem3, hstem3
+		case 13: // hsbw
+			a, err := in.args(2)
+			if err != nil {
+				return err
+			}
+			in.sbx, in.width = a[0], a[1]
+			in.x, in.y = a[0], 0
+		case 1207: // sbw
+			a, err := in.args(4)
+			if err != nil {
+				return err
+			}
+			in.sbx, in.width = a[0], a[2]
+			in.x, in.y = a[0], a[1]
+		case 21: // rmoveto
+			a, err := in.args(2)
+			if err != nil {
+				return err
+			}
+			in.moveTo(a[0], a[1])
+		case 22: // hmoveto
+			a, err := in.args(1)
+			if err != nil {
+				return err
+			}
+			in.moveTo(a[0], 0)
+		case 4: // vmoveto
+			a, err := in.args(1)
+			if err != nil {
+				return err
+			}
+			in.moveTo(0, a[0])
+		case 5: // rlineto
+			a, err := in.args(2)
+			if err != nil {
+				return err
+			}
+			in.lineTo(a[0], a[1])
+		case 6: // hlineto
+			a, err := in.args(1)
+			if err != nil {
+				return err
+			}
+			in.lineTo(a[0], 0)
+		case 7: // vlineto
+			a, err := in.args(1)
+			if err != nil {
+				return err
+			}
+			in.lineTo(0, a[0])
+		case 8: // rrcurveto
+			a, err := in.args(6)
+			if err != nil {
+				return err
+			}
+			in.curveTo(a[0], a[1], a[2], a[3], a[4], a[5])
+		case 30: // vhcurveto
+			a, err := in.args(4)
+			if err != nil {
+				return err
+			}
+			in.curveTo(0, a[0], a[1], a[2], a[3], 0)
+		case 31: // hvcurveto
+			a, err := in.args(4)
+			if err != nil {
+				return err
+			}
+			in.curveTo(a[0], 0, a[1], a[2], 0, a[3])
+		case 9: // closepath
+			// Type 2 charstrings close each contour implicitly.
+		case 10: // callsubr
+			a, err := in.args(1)
+			if err != nil {
+				return err
+			}
+			n := int(a[0])
+			in.stack = in.stack[:len(in.stack)-1]
+			if n < 0 || n >= len(in.font.subrs) {
+				return fmt.Errorf("call to undefined subroutine %d", n)
+			}
+			if err := in.run(in.font.subrs[n], depth+1); err != nil {
+				return err
+			}
+			continue
+		case 11: // return
+			return nil
+		case 14: // endchar
+			in.finished = true
+		case 1206: // seac
+			a, err := in.args(5)
+			if err != nil {
+				return err
+			}
+			if err := in.seac(a[0], a[1], a[2], int(a[3]), int(a[4])); err != nil {
+				return err
+			}
+			in.finished = true
+		case 1212: // div
+			a, err := in.args(2)
+			if err != nil {
+				return err
+			}
+			if a[1] == 0 {
+				return fmt.Errorf("division by zero")
+			}
+			in.stack = append(in.stack[:len(in.stack)-2], a[0]/a[1])
+			continue
+		case 1216: // callothersubr
+			// The arguments are left on the stack for the following pops,
+			// which is what the standard OtherSubrs return when hint
+			// replacement is not supported.
+			a, err := in.args(2)
+			if err != nil {
+				return err
+			}
+			othersubr, n := int(a[1]), int(a[0])
+			in.stack = in.stack[:len(in.stack)-2]
+			if err := in.callOtherSubr(othersubr, n); err != nil {
+				return err
+			}
+			continue
+		case 1217: // pop
+			continue
+		case 1233: // setcurrentpoint
+			a, err := in.args(2)
+			if err != nil {
+				return err
+			}
+			in.x, in.y = a[0], a[1]
+		default:
+			return fmt.Errorf("unknown operator %d", op)
+		}
+		in.stack = in.stack[:0]
+	}
+	return nil
+}
+
+// callOtherSubr runs the standard OtherSubrs used for flex hints, and ignores
+// the others, which are for hint replacement and counter control.
+func (in *type1Interpreter) callOtherSubr(othersubr, n int) error {
+	switch othersubr {
+	case 1:
+		in.flexing, in.flex = true, nil
+	case 0:
+		// The flex has a reference point and then the points of two curves.
+		in.flexing = false
+		if len(in.flex) != 7 {
+			return fmt.Errorf("flex has %d points, want 7", len(in.flex))
+		}
+		p := in.flex
+		in.path = append(in.path,
+			type1Segment{op: 'C', points: [3]vector{p[1], p[2], p[3]}},
+			type1Segment{op: 'C', points: [3]vector{p[4], p[5], p[6]}},
+		)
+		in.x, in.y = p[6].X, p[6].Y
+	}
+	if n < 0 || n > len(in.stack) {
+		return fmt.Errorf("othersubr %d needs %d arguments, but the stack has %d", othersubr, n, len(in.stack))
+	}
+	return nil
+}
+
+// seac draws an accented character, made of two glyphs from StandardEncoding.
+func (in *type1Interpreter) seac(asb, adx, ady float64, base, accent int) error {
+	if in.depth > 0 {
+		return fmt.Errorf("accented character uses another accented character")
+	}
+	if base < 0 || base > 255 || accent < 0 || accent > 255 {
+		return fmt.Errorf("seac uses invalid character codes %d and %d", base, accent)
+	}
+	b, err := in.font.glyphAt(standardEncoding[base], in.depth+1)
+	if err != nil {
+		return err
+	}
+	a, err := in.font.glyphAt(standardEncoding[accent], in.depth+1)
+	if err != nil {
+		return err
+	}
+
+	in.path = append(in.path, b.path...)
+	offset := vector{adx + in.sbx - asb, ady}
+	for _, segment := range a.path {
+		for i := range segment.points {
+			segment.points[i] = segment.points[i].add(offset)
+		}
+		in.path = append(in.path, segment)
+	}
+	return nil
+}
+
+// bounds returns the bounding box of the outline, including the extremes of
+// its curves. It returns false if the outline is empty.
+func (g *type1Glyph) bounds() (Bounds, bool) {
+	var bounds Bounds
+	found := false
+	add := func(p vector) {
+		if !found {
+			bounds = Bounds{p.X, p.Y, p.X, p.Y}
+			found = true
+			return
+		}
+		bounds.XMin, bounds.XMax = math.Min(bounds.XMin, p.X), math.Max(bounds.XMax, p.X)
+		bounds.YMin, bounds.YMax = math.Min(bounds.YMin, p.Y), math.Max(bounds.YMax, p.Y)
+	}
+
+	var current vector
+	for i, segment := range g.path {
+		end := segment.points[2]
+		if segment.op == 'M' {
+			// A moveto only adds to the bounds if a contour starts there.
+			if i+1 < len(g.path) && g.path[i+1].op != 'M' {
+				add(end)
+			}
+			current = end
+			continue
+		}
+		add(end)
+		if segment.op == 'C' {
+			for _, t := range cubicExtrema(current, segment.points[0], segment.points[1], end) {
+				add(cubicPoint(current, segment.points[0], segment.points[1], end, t))
+			}
+		}
+		current = end
+	}
+	return bounds, found
+}
+
+// cubicExtrema returns the parameters between 0 and 1 at which the cubic
+// Bézier curve from p0 to p3 has a horizontal or vertical tangent.
+func cubicExtrema(p0, p1, p2, p3 vector) []float64 {
+	var ts []float64
+	for _, c := range [][4]float64{{p0.X, p1.X, p2.X, p3.X}, {p0.Y, p1.Y, p2.Y, p3.Y}} {
+		// The derivative is a quadratic, a t² + b t + c.
+		a := -c[0] + 3*c[1] - 3*c[2] + c[3]
+		b := 2 * (c[0] - 2*c[1] + c[2])
+		k := c[1] - c[0]
+		if math.Abs(a) < 1e-12 {
+			if b != 0 {
+				ts = append(ts, -k/b)
+			}
+			continue
+		}
+		discriminant := b*b - 4*a*k
+		if discriminant < 0 {
+			continue
+		}
+		root := math.Sqrt(discriminant)
+		ts = append(ts, (-b+root)/(2*a), (-b-root)/(2*a))
+	}
+
+	valid := ts[:0]
+	for _, t := range ts {
+		if t > 0 && t < 1 {
+			valid = append(valid, t)
+		}
+	}
+	return valid
+}
+
+// cubicPoint returns the point at t on the cubic Bézier curve from p0 to p3.
+func cubicPoint(p0, p1, p2, p3 vector, t float64) vector {
+	u := 1 - t
+	return p0.scale(u * u * u).add(p1.scale(3 * u * u * t)).add(p2.scale(3 * u * t * t)).add(p3.scale(t * t * t))
+}
+
+// type2CharString returns the outline as a Type 2 charstring, as used in CFF
+// fonts. The width is always given, relative to a nominalWidthX of zero.
+func (g *type1Glyph) type2CharString() []byte {
+	var buf []byte
+	var args []float64
+	pending := -1
+	flush := func() {
+		for _, v := range args {
+			buf = appendType2Number(buf, v)
+		}
+		buf = append(buf, byte(pending))
+		args = args[:0]
+	}
+	args = append(args, g.width)
+
+	var current vector
+	for _, segment := range g.path {
+		var op int
+		var deltas []float64
+		switch segment.op {
+		case 'M':
+			op = 21
+			d := segment.points[2].sub(current)
+			deltas = []float64{d.X, d.Y}
+		case 'L':
+			op = 5
+			d := segment.points[2].sub(current)
+			deltas = []float64{d.X, d.Y}
+		case 'C':
+			op = 8
+			d1 := segment.points[0].sub(current)
+			d2 := segment.points[1].sub(segment.points[0])
+			d3 := segment.points[2].sub(segment.points[1])
+			deltas = []float64{d1.X, d1.Y, d2.X, d2.Y, d3.X, d3.Y}
+		}
+		current = segment.points[2]
+
+		// Consecutive lines and curves can share an operator, and moves
+		// replace each other.
+		switch {
+		case op == 21 && pending == 21:
+			d := args[len(args)-2:]
+			d[0], d[1] = d[0]+deltas[0], d[1]+deltas[1]
+			continue
+		case op != pending || len(args)+len(deltas) > 48:
+			if pending >= 0 {
+				flush()
+			}
+		}
+		pending = op
+		args = append(args, deltas...)
+	}
+	if pending >= 0 {
+		flush()
+	}
+	pending = 14 // endchar
+	flush()
+	return buf
+}
+
+// appendType2Number appends the Type 2 charstring encoding of v.
+func appendType2Number(buf []byte, v float64) []byte {
+	if v != math.Trunc(v) || v < -32768 || v > 32767 {
+		return appendUint32s(append(buf, 255), uint32(int32(math.Round(v*65536))))
+	}
+	return appendCFFInt(buf, int(v), 28)
+}
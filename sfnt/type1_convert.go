@@ -0,0 +1,382 @@
+package sfnt
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/ConradIrwin/font/aglfn"
+)
+
+// ConvertType1 converts a PostScript Type 1 font, in PFB or PFA format, to an
+// OpenType font with a 'CFF ' table. The glyph outlines are converted to Type 2
+// charstrings and hints are dropped, but the font-wide hinting values in the
+// Private dictionary are kept. Characters are mapped using the glyph names,
+// or the font's encoding for symbol fonts whose glyph names have no meaning.
+func ConvertType1(data []byte) (*Font, error) {
+	t1, err := parseType1(data)
+	if err != nil {
+		return nil, err
+	}
+
+	unitsPerEm := 1000
+	if m := t1.FontMatrix; m != nil {
+		if len(m) != 6 || m[0] <= 0 || m[1] != 0 || m[2] != 0 || m[3] != m[0] {
+			return nil, fmt.Errorf("unsupported FontMatrix %v", m)
+		}
+		unitsPerEm = int(math.Round(1 / m[0]))
+		if unitsPerEm < 16 || unitsPerEm > 16384 {
+			return nil, fmt.Errorf("unsupported FontMatrix %v", m)
+		}
+	}
+
+	// .notdef must be the first glyph, and is drawn as an empty glyph if the
+	// font does not have one.
+	names := []string{".notdef"}
+	for _, name := range t1.names {
+		if name != ".notdef" {
+			names = append(names, name)
+		}
+	}
+
+	cff := &cffFont{name: t1.FontName, glyphNames: names}
+	head := &TableHead{baseTable: baseTable(TagHead), tableHeadFields: tableHeadFields{
+		VersionNumber: fixed{Major: 1},
+		FontRevision:  fixed{Major: 1},
+		MagicNumber:   0x5F0F3CF5,
+		Flags:         0x0003, // Baseline at y=0, left side bearing point at x=0.
+		UnitsPerEm:    uint16(unitsPerEm),
+		FontDirection: 2,
+	}}
+	if version, err := strconv.ParseFloat(t1.Version, 64); err == nil && version > 0 && version < 32768 {
+		head.FontRevision = fixedFromFloat(version)
+	}
+	hhea := &TableHhea{baseTable: baseTable(TagHhea), tableHheaFields: tableHheaFields{
+		Version:        fixed{Major: 1},
+		CaretSlopeRise: 1,
+	}}
+	hmtx := &TableHmtx{baseTable: baseTable(TagHmtx)}
+
+	var totalAdvance, advances int
+	first := true
+	for _, name := range names {
+		glyph := &type1Glyph{}
+		if _, found := t1.charStrings[name]; found {
+			if glyph, err = t1.glyph(name); err != nil {
+				return nil, err
+			}
+		}
+		cff.charStrings = append(cff.charStrings, glyph.type2CharString())
+
+		advance := uint16(math.Max(0, math.Min(0xFFFF, roundCoordinate(glyph.width))))
+		hhea.AdvanceWidthMax = max16(hhea.AdvanceWidthMax, advance)
+		if advance > 0 {
+			totalAdvance += int(advance)
+			advances++
+		}
+
+		bounds, found := glyph.bounds()
+		if !found {
+			hmtx.Metrics = append(hmtx.Metrics, HMetric{AdvanceWidth: advance})
+			continue
+		}
+		xMin, yMin := roundInt16(math.Floor(bounds.XMin)), roundInt16(math.Floor(bounds.YMin))
+		xMax, yMax := roundInt16(math.Ceil(bounds.XMax)), roundInt16(math.Ceil(bounds.YMax))
+		hmtx.Metrics = append(hmtx.Metrics, HMetric{AdvanceWidth: advance, LeftSideBearing: xMin})
+
+		rsb := int16(int(advance) - int(xMax))
+		if first || xMin < hhea.MinLeftSideBearing {
+			hhea.MinLeftSideBearing = xMin
+		}
+		if first || rsb < hhea.MinRightSideBearing {
+			hhea.MinRightSideBearing = rsb
+		}
+		if first || xMax > hhea.XMaxExtent {
+			hhea.XMaxExtent = xMax
+		}
+		if first || xMin < head.XMin {
+			head.XMin = xMin
+		}
+		if first || yMin < head.YMin {
+			head.YMin = yMin
+		}
+		if first || xMax > head.XMax {
+			head.XMax = xMax
+		}
+		if first || yMax > head.YMax {
+			head.YMax = yMax
+		}
+		first = false
+	}
+	hhea.NumOfLongHorMetrics = int16(hmtx.NumberOfHMetrics())
+
+	// Type 1 fonts have no line spacing, so the ascender and descender are
+	// taken from the bounding box.
+	b := &Builder{
+		UnitsPerEm: uint16(unitsPerEm),
+		Ascender:   head.YMax,
+		Descender:  head.YMin,
+		mapping:    t1.characterMap(names),
+		names:      t1.nameEntries(),
+	}
+	hhea.Ascent, hhea.Descent = b.Ascender, b.Descender
+
+	var avgCharWidth uint16
+	if advances > 0 {
+		avgCharWidth = uint16(totalAdvance / advances)
+	}
+	os2 := b.os2Table(head, avgCharWidth)
+	os2.USWeightClass = type1WeightClass(t1.Weight)
+	os2.FsSelection &^= fsSelectionRegular
+	bold, italic := t1.isBold(), t1.isItalic()
+	if bold {
+		os2.FsSelection |= fsSelectionBold
+		head.MacStyle |= macStyleBold
+	}
+	if italic {
+		os2.FsSelection |= fsSelectionItalic
+		head.MacStyle |= macStyleItalic
+	}
+	if !bold && !italic {
+		os2.FsSelection |= fsSelectionRegular
+	}
+
+	name, err := b.nameTable()
+	if err != nil {
+		return nil, err
+	}
+
+	post := &TablePost{baseTable: baseTable(TagPost), tablePostFields: tablePostFields{
+		ItalicAngle:        fixedFromFloat(t1.ItalicAngle),
+		UnderlinePosition:  roundInt16(t1.UnderlinePosition),
+		UnderlineThickness: roundInt16(t1.UnderlineThickness),
+	}}
+	if t1.IsFixedPitch {
+		post.IsFixedPitch = 1
+	}
+	if err := post.SetGlyphNames(nil); err != nil {
+		return nil, err
+	}
+
+	maxp := &TableMaxp{baseTable: baseTable(TagMaxp), tableMaxpFields: tableMaxpFields{
+		Version:   maxpVersion05,
+		NumGlyphs: uint16(len(names)),
+	}}
+	if len(names) > 0xFFFF {
+		return nil, fmt.Errorf("font has %d glyphs, more than the maximum of 65535", len(names))
+	}
+
+	t1.writeDicts(cff, head)
+
+	font := New(TypeOpenType)
+	font.AddTable(TagHead, head)
+	font.AddTable(TagHhea, hhea)
+	font.AddTable(TagMaxp, maxp)
+	font.AddTable(TagOS2, os2)
+	font.AddTable(TagHmtx, hmtx)
+	font.AddTable(TagCmap, NewTableCmap(b.mapping))
+	font.AddTable(TagName, name)
+	font.AddTable(TagPost, post)
+	font.SetTable(TagCFF, cff.Bytes())
+	return font, nil
+}
+
+// fixedFromFloat returns the 16.16 fixed point number closest to v.
+func fixedFromFloat(v float64) fixed {
+	n := int32(math.Round(v * 65536))
+	return fixed{Major: int16(n >> 16), Minor: uint16(n)}
+}
+
+// characterMap maps characters to the glyphs, in the order given by names.
+// Glyphs are mapped by name, unless none of the names are meaningful, in which
+// case the codes in the encoding are mapped to the private use area like
+// other symbol fonts.
+func (font *type1Font) characterMap(names []string) map[rune]uint16 {
+	mapping := map[rune]uint16{}
+	gids := map[string]uint16{}
+	for gid, name := range names {
+		gids[name] = uint16(gid)
+		if gid == 0 || strings.Contains(name, ".") {
+			continue
+		}
+		if r, found := aglfn.Rune(name); found {
+			if _, mapped := mapping[r]; !mapped {
+				mapping[r] = uint16(gid)
+			}
+		}
+	}
+	if len(mapping) > 0 {
+		return mapping
+	}
+
+	for code, name := range font.Encoding {
+		if gid, found := gids[name]; found && gid != 0 {
+			mapping[rune(0xF000+code)] = gid
+		}
+	}
+	return mapping
+}
+
+// style returns the style name, which is the part of the full name after the
+// family name.
+func (font *type1Font) style() string {
+	style := strings.TrimSpace(strings.TrimPrefix(font.FullName, font.FamilyName))
+	if font.FamilyName == "" || style == font.FullName || style == "" {
+		return "Regular"
+	}
+	return style
+}
+
+// isBold returns true if the font is the bold style of its family, as
+// opposed to a semibold or black style.
+func (font *type1Font) isBold() bool {
+	for _, word := range strings.Fields(font.style()) {
+		if word == "Bold" {
+			return true
+		}
+	}
+	return false
+}
+
+func (font *type1Font) isItalic() bool {
+	for _, word := range strings.Fields(font.style()) {
+		if word == "Italic" || word == "Oblique" {
+			return true
+		}
+	}
+	return font.ItalicAngle != 0
+}
+
+// nameEntries returns the entries for the 'name' table. Styles other than regular,
+// bold, italic and bold italic are named with the typographic family and
+// subfamily names, and are part of the legacy family name.
+func (font *type1Font) nameEntries() map[NameID]string {
+	family, style := font.FamilyName, font.style()
+	if family == "" {
+		family = font.FontName
+	}
+
+	names := map[NameID]string{
+		NameCopyrightNotice: font.Notice,
+		NameFull:            font.FullName,
+		NamePostscript:      font.FontName,
+	}
+	if font.Version != "" {
+		names[NameVersion] = "Version " + font.Version
+	}
+
+	switch style {
+	case "Regular", "Bold", "Italic", "Bold Italic":
+		names[NameFontFamily], names[NameFontSubfamily] = family, style
+	default:
+		legacy := "Regular"
+		switch bold, italic := font.isBold(), font.isItalic(); {
+		case bold && italic:
+			legacy = "Bold Italic"
+		case bold:
+			legacy = "Bold"
+		case italic:
+			legacy = "Italic"
+		}
+		legacyFamily := []string{family}
+		for _, word := range strings.Fields(style) {
+			if word != "Bold" && word != "Italic" {
+				legacyFamily = append(legacyFamily, word)
+			}
+		}
+		names[NameFontFamily], names[NameFontSubfamily] = strings.Join(legacyFamily, " "), legacy
+		names[NamePreferredFamily], names[NamePreferredSubfamily] = family, style
+	}
+
+	for id, value := range names {
+		if value == "" {
+			delete(names, id)
+		}
+	}
+	return names
+}
+
+// type1WeightClass returns the usWeightClass for the Weight in a Type 1 font's
+// FontInfo dictionary.
+func type1WeightClass(weight string) uint16 {
+	weight = strings.ToLower(strings.NewReplacer(" ", "", "-", "").Replace(weight))
+	for _, style := range standardStyleNames[axisWeight] {
+		if strings.ToLower(style.Name) == weight {
+			return uint16(style.Value)
+		}
+	}
+	switch weight {
+	case "ultralight":
+		return 200
+	case "demi", "demibold":
+		return 600
+	case "ultrabold":
+		return 800
+	case "heavy":
+		return 900
+	}
+	return 400
+}
+
+// writeDicts fills in the Top and Private DICTs of the converted font.
+func (font *type1Font) writeDicts(cff *cffFont, head *TableHead) {
+	top := &cff.top
+	for _, entry := range []struct {
+		op    int
+		value string
+	}{
+		{cffVersion, font.Version},
+		{cffNotice, font.Notice},
+		{cffFullName, font.FullName},
+		{cffFamilyName, font.FamilyName},
+		{cffWeight, font.Weight},
+	} {
+		if entry.value != "" {
+			top.entry(entry.op, float64(cff.sid(entry.value)))
+		}
+	}
+	if font.IsFixedPitch {
+		top.entry(cffIsFixedPitch, 1)
+	}
+	if font.ItalicAngle != 0 {
+		top.entry(cffItalicAngle, font.ItalicAngle)
+	}
+	top.entry(cffUnderlinePosition, font.UnderlinePosition)
+	top.entry(cffUnderlineThickness, font.UnderlineThickness)
+	if head.UnitsPerEm != 1000 {
+		scale := 1 / float64(head.UnitsPerEm)
+		top.entry(cffFontMatrix, scale, 0, 0, scale, 0, 0)
+	}
+	top.entry(cffFontBBox, float64(head.XMin), float64(head.YMin), float64(head.XMax), float64(head.YMax))
+
+	private := &cff.private
+	for _, entry := range []struct {
+		op    int
+		key   string
+		delta bool
+	}{
+		{cffBlueValues, "BlueValues", true},
+		{cffOtherBlues, "OtherBlues", true},
+		{cffFamilyBlues, "FamilyBlues", true},
+		{cffFamilyOtherBlues, "FamilyOtherBlues", true},
+		{cffStdHW, "StdHW", false},
+		{cffStdVW, "StdVW", false},
+		{cffStemSnapH, "StemSnapH", true},
+		{cffStemSnapV, "StemSnapV", true},
+		{cffBlueScale, "BlueScale", false},
+		{cffBlueShift, "BlueShift", false},
+		{cffBlueFuzz, "BlueFuzz", false},
+		{cffForceBold, "ForceBold", false},
+	} {
+		values := font.Private[entry.key]
+		switch {
+		case len(values) == 0:
+		case entry.delta:
+			private.deltaEntry(entry.op, values)
+		default:
+			// StdHW and StdVW are arrays in Type 1 fonts, but numbers in CFF.
+			private.entry(entry.op, values[0])
+		}
+	}
+}
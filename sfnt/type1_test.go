@@ -0,0 +1,318 @@
+package sfnt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"testing"
+)
+
+// type1Op is a charstring operator, with two byte operators stored as 1200
+// plus their second byte.
+type type1Op int
+
+// type1CharString encodes numbers and operators as an unencrypted charstring.
+func type1CharString(tokens ...interface{}) []byte {
+	var buf []byte
+	for _, token := range tokens {
+		switch v := token.(type) {
+		case int:
+			if v >= -1131 && v <= 1131 {
+				buf = appendCFFInt(buf, v, 29)
+			} else {
+				buf = appendUint32s(append(buf, 255), uint32(int32(v)))
+			}
+		case type1Op:
+			if v >= 1200 {
+				buf = append(buf, 12, byte(v-1200))
+			} else {
+				buf = append(buf, byte(v))
+			}
+		}
+	}
+	return buf
+}
+
+func type1Encrypt(plain []byte, key uint16, lenIV int) []byte {
+	out := make([]byte, 0, lenIV+len(plain))
+	r := key
+	for _, p := range append(make([]byte, lenIV), plain...) {
+		c := p ^ byte(r>>8)
+		out = append(out, c)
+		r = (uint16(c)+r)*52845 + 22719
+	}
+	return out
+}
+
+const (
+	t1hstem           type1Op = 1
+	t1rlineto         type1Op = 5
+	t1closepath       type1Op = 9
+	t1callsubr        type1Op = 10
+	t1return          type1Op = 11
+	t1hsbw            type1Op = 13
+	t1endchar         type1Op = 14
+	t1rmoveto         type1Op = 21
+	t1seac            type1Op = 1206
+	t1div             type1Op = 1212
+	t1callothersubr   type1Op = 1216
+	t1pop             type1Op = 1217
+	t1setcurrentpoint type1Op = 1233
+)
+
+// testType1Font returns the clear and encrypted parts of a small Type 1 font.
+func testType1Font() (clear, encrypted []byte) {
+	clear = []byte(`%!PS-AdobeFont-1.0: Test-Bold 001.002
+%%Title: Test-Bold
+11 dict begin
+/FontInfo 9 dict dup begin
+/version (001.002) readonly def
+/Notice (Copyright \(c\) Nobody) readonly def
+/FullName (Test Bold) readonly def
+/FamilyName (Test) readonly def
+/Weight (Bold) readonly def
+/ItalicAngle 0 def
+/isFixedPitch false def
+/UnderlinePosition -120 def
+/UnderlineThickness 60 def
+end readonly def
+/FontName /Test-Bold def
+/PaintType 0 def
+/FontType 1 def
+/FontMatrix [0.001 0 0 0.001 0 0] readonly def
+/Encoding StandardEncoding def
+/FontBBox {0 -10 600 710} readonly def
+currentdict end
+currentfile eexec
+`)
+
+	subrs := [][]byte{
+		type1CharString(3, 0, t1callothersubr, t1pop, t1pop, t1setcurrentpoint, t1return),
+		type1CharString(0, 1, t1callothersubr, t1return),
+		type1CharString(0, 2, t1callothersubr, t1return),
+		type1CharString(t1return),
+		type1CharString(1, 3, t1callothersubr, t1pop, t1callsubr, t1return),
+		type1CharString(0, 50, t1hstem, t1return),
+	}
+	glyphs := []struct {
+		name string
+		cs   []byte
+	}{
+		{".notdef", type1CharString(0, 250, t1hsbw, t1endchar)},
+		{"A", type1CharString(20, 600, t1hsbw, 0, 0, t1rmoveto, 280, 700, t1rlineto, 280, -700, t1rlineto, t1closepath, t1endchar)},
+		{"O", type1CharString(50, 600, t1hsbw, 5, 4, t1callsubr, 0, 0, t1rmoveto, 500, 0, t1rlineto,
+			1, t1callsubr,
+			0, 50, t1rmoveto, 2, t1callsubr,
+			10, -30, t1rmoveto, 2, t1callsubr,
+			0, 30, t1rmoveto, 2, t1callsubr,
+			0, 30, t1rmoveto, 2, t1callsubr,
+			0, 30, t1rmoveto, 2, t1callsubr,
+			-10, 20, t1rmoveto, 2, t1callsubr,
+			0, -30, t1rmoveto, 2, t1callsubr,
+			50, 550, 100, 0, t1callsubr,
+			-500, 0, t1rlineto, t1closepath, t1endchar)},
+		{"acute", type1CharString(100, 600, 2, t1div, t1hsbw, 0, 500, t1rmoveto, 100, 100, t1rlineto, 20, -20, t1rlineto, t1closepath, t1endchar)},
+		{"Aacute", type1CharString(0, 600, t1hsbw, 100, 150, 200, 65, 194, t1seac)},
+	}
+
+	var private bytes.Buffer
+	private.WriteString(`dup /Private 9 dict dup begin
+/RD{string currentfile exch readstring pop}executeonly def
+/ND{noaccess def}executeonly def
+/NP{noaccess put}executeonly def
+/BlueValues [-10 0 700 710] def
+/StdHW [50] def
+/StdVW [80] def
+/BlueScale 0.039625 def
+/ForceBold true def
+/MinFeature{16 16}def
+/password 5839 def
+`)
+	fmt.Fprintf(&private, "/Subrs %d array\n", len(subrs))
+	for i, subr := range subrs {
+		cs := type1Encrypt(subr, type1CharStringKey, type1DefaultLenIV)
+		fmt.Fprintf(&private, "dup %d %d RD ", i, len(cs))
+		private.Write(cs)
+		private.WriteString(" NP\n")
+	}
+	fmt.Fprintf(&private, "ND\n2 index /CharStrings %d dict dup begin\n", len(glyphs))
+	for _, glyph := range glyphs {
+		cs := type1Encrypt(glyph.cs, type1CharStringKey, type1DefaultLenIV)
+		fmt.Fprintf(&private, "/%s %d RD ", glyph.name, len(cs))
+		private.Write(cs)
+		private.WriteString(" ND\n")
+	}
+	private.WriteString("end\nend\nreadonly put\nnoaccess put\ndup /FontName get exch definefont pop\nmark currentfile closefile\n")
+
+	return clear, type1Encrypt(private.Bytes(), type1EexecKey, 4)
+}
+
+func testPFB() []byte {
+	clear, encrypted := testType1Font()
+	trailer := []byte(string(bytes.Repeat([]byte("0"), 512)) + "\ncleartomark\n")
+	var buf []byte
+	for _, segment := range []struct {
+		kind byte
+		data []byte
+	}{{1, clear}, {2, encrypted}, {1, trailer}} {
+		buf = append(buf, 0x80, segment.kind, 0, 0, 0, 0)
+		binary.LittleEndian.PutUint32(buf[len(buf)-4:], uint32(len(segment.data)))
+		buf = append(buf, segment.data...)
+	}
+	return append(buf, 0x80, 3)
+}
+
+func testPFA() []byte {
+	clear, encrypted := testType1Font()
+	buf := append([]byte(nil), clear...)
+	encoded := hex.EncodeToString(encrypted)
+	for len(encoded) > 64 {
+		buf = append(buf, encoded[:64]+"\n"...)
+		encoded = encoded[64:]
+	}
+	buf = append(buf, encoded+"\n"...)
+	return append(buf, string(bytes.Repeat([]byte("0"), 512))+"\ncleartomark\n"...)
+}
+
+func TestConvertType1(t *testing.T) {
+	for format, data := range map[string][]byte{"PFB": testPFB(), "PFA": testPFA()} {
+		if !IsType1(data) {
+			t.Errorf("%s: IsType1() = false, want true", format)
+		}
+		if _, err := Parse(bytes.NewReader(data)); err != ErrType1 {
+			t.Errorf("%s: Parse() err = %v, want ErrType1", format, err)
+		}
+
+		converted, err := ConvertType1(data)
+		if err != nil {
+			t.Fatalf("%s: ConvertType1() err = %v", format, err)
+		}
+		var buf bytes.Buffer
+		if _, err := converted.WriteOTF(&buf); err != nil {
+			t.Fatal(err)
+		}
+		font, err := StrictParse(bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			t.Fatalf("%s: StrictParse() err = %v", format, err)
+		}
+		if font.Type() != TypeOpenType {
+			t.Errorf("%s: Type() = %q, want OTTO", format, font.Type())
+		}
+
+		cff, err := font.CFFTable()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n := cff.NumGlyphs(); n != 5 {
+			t.Errorf("%s: CFF has %d glyphs, want 5", format, n)
+		}
+		charStrings, _, err := parseCFFIndex(cff.Bytes(), cffCharStringsOffset(t, cff.Bytes()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 600 width, 20 0 rmoveto, 280 700 280 -700 rlineto, endchar.
+		want := []byte{248, 236, 159, 139, 21, 247, 172, 249, 80, 247, 172, 253, 80, 5, 14}
+		if !bytes.Equal(charStrings[1], want) {
+			t.Errorf("%s: charstring for A = %v, want %v", format, charStrings[1], want)
+		}
+
+		hmtx, err := font.HmtxTable()
+		if err != nil {
+			t.Fatal(err)
+		}
+		wantMetrics := []HMetric{{250, 0}, {600, 20}, {600, 50}, {300, 100}, {600, 20}}
+		for gid, m := range wantMetrics {
+			if hmtx.Metrics[gid] != m {
+				t.Errorf("%s: hmtx[%d] = %v, want %v", format, gid, hmtx.Metrics[gid], m)
+			}
+		}
+		head, err := font.HeadTable()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := [4]int16{head.XMin, head.YMin, head.XMax, head.YMax}; got != [4]int16{20, 0, 580, 800} {
+			t.Errorf("%s: head bounds = %v, want [20 0 580 800]", format, got)
+		}
+
+		cmap, err := font.CmapTable()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for r, want := range map[rune]uint16{'A': 1, 'O': 2, '´': 3, 'Á': 4} {
+			if gid, found := cmap.Lookup(r); !found || gid != want {
+				t.Errorf("%s: cmap[%q] = %d, want %d", format, r, gid, want)
+			}
+		}
+
+		name, err := font.NameTable()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for id, want := range map[NameID]string{
+			NameFontFamily:    "Test",
+			NameFontSubfamily: "Bold",
+			NamePostscript:    "Test-Bold",
+			NameVersion:       "Version 001.002",
+		} {
+			if got := name.Lookup(id); got != want {
+				t.Errorf("%s: name %d = %q, want %q", format, id, got, want)
+			}
+		}
+		os2, err := font.OS2Table()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if os2.USWeightClass != 700 || os2.FsSelection&fsSelectionBold == 0 {
+			t.Errorf("%s: usWeightClass, fsSelection = %d, %#x; want 700 and bold", format, os2.USWeightClass, os2.FsSelection)
+		}
+	}
+}
+
+// cffCharStringsOffset returns the offset of the CharStrings INDEX in a CFF table.
+func cffCharStringsOffset(t *testing.T, buf []byte) int {
+	t.Helper()
+	_, offset, err := parseCFFIndex(buf, int(buf[2]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	topDicts, _, err := parseCFFIndex(buf, offset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	top, err := parseCFFDict(topDicts[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return top.int(cffCharStrings, 0)
+}
+
+func TestType1Flex(t *testing.T) {
+	clear, encrypted := testType1Font()
+	font := &type1Font{Private: map[string][]float64{}, charStrings: map[string][]byte{}}
+	if err := font.parseFontDict(clear); err != nil {
+		t.Fatal(err)
+	}
+	if err := font.parsePrivate(type1Decrypt(encrypted, type1EexecKey, 4)); err != nil {
+		t.Fatal(err)
+	}
+	if got := font.Private["BlueValues"]; len(got) != 4 || got[2] != 700 {
+		t.Errorf("BlueValues = %v, want [-10 0 700 710]", got)
+	}
+
+	glyph, err := font.glyph("O")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ops []byte
+	for _, segment := range glyph.path {
+		ops = append(ops, segment.op)
+	}
+	if string(ops) != "MLCCL" {
+		t.Errorf("O path = %s, want MLCCL", ops)
+	}
+	bounds, _ := glyph.bounds()
+	if bounds.XMin != 50 || bounds.XMax != 560 || bounds.YMax <= 110 || bounds.YMax >= 130 {
+		t.Errorf("O bounds = %v, want x from 50 to 560 and y max between the flex points", bounds)
+	}
+}
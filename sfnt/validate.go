@@ -0,0 +1,250 @@
+package sfnt
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Severity is how serious a problem found by Validate is.
+type Severity int
+
+const (
+	// SeverityInfo is used for problems that are unlikely to cause issues.
+	SeverityInfo Severity = iota
+	// SeverityWarning is used for problems that may cause issues with some software.
+	SeverityWarning
+	// SeverityError is used for problems that make the font invalid.
+	SeverityError
+)
+
+// String returns the name of the severity.
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	default:
+		return fmt.Sprintf("severity %d", int(s))
+	}
+}
+
+// Problem is an issue found by Validate.
+type Problem struct {
+	Severity Severity
+	Tag      Tag    // Tag of the table containing the problem.
+	GlyphID  int    // GlyphID of the glyph with the problem, or -1 if it is not about a glyph.
+	Message  string // Message describes the problem.
+
+	// Offset and Length are the range of bytes in the table that the problem
+	// is about, in the table's data as returned by TableData. Length is 0 if
+	// the problem is about the whole table, or a table that is missing. The
+	// table's position in the file is given by Directory.
+	Offset int
+	Length int
+
+	// Fix identifies a change that fixes the problem, or is empty if it must
+	// be fixed by hand.
+	Fix Fix
+}
+
+// String returns a human readable description of the problem.
+func (p Problem) String() string {
+	if p.GlyphID >= 0 {
+		return fmt.Sprintf("%s: %q glyph %d: %s", p.Severity, p.Tag, p.GlyphID, p.Message)
+	}
+	return fmt.Sprintf("%s: %q: %s", p.Severity, p.Tag, p.Message)
+}
+
+// newProblem returns a problem that is not about particular bytes and has no
+// fix. These are set with at and fixedBy.
+func newProblem(severity Severity, tag Tag, gid int, message string) Problem {
+	return Problem{Severity: severity, Tag: tag, GlyphID: gid, Message: message}
+}
+
+// at returns p about length bytes at offset in the table.
+func (p Problem) at(offset, length int) Problem {
+	p.Offset, p.Length = offset, length
+	return p
+}
+
+// fixedBy returns p with the given fix.
+func (p Problem) fixedBy(fix Fix) Problem {
+	p.Fix = fix
+	return p
+}
+
+// Fix is a machine-readable identifier for a change that fixes a Problem, so
+// that editors and automated fixers can act on problems without parsing their
+// messages. The change applies to the bytes given by the problem's Tag, Offset
+// and Length.
+type Fix string
+
+const (
+	FixAddTable             Fix = "add-table"              // Add the missing table.
+	FixAddName              Fix = "add-name"               // Add the missing 'name' entry.
+	FixAddUnicodeCmap       Fix = "add-unicode-cmap"       // Add a Microsoft Unicode 'cmap' subtable.
+	FixSetVersion           Fix = "set-version"            // Set the table's version to one that is supported.
+	FixSetMagicNumber       Fix = "set-magic-number"       // Set the magicNumber of the 'head' table.
+	FixScaleUnitsPerEm      Fix = "scale-units-per-em"     // Scale the font to a valid unitsPerEm.
+	FixRebuildLoca          Fix = "rebuild-loca"           // Rebuild the 'loca' table from the 'glyf' table.
+	FixSetNumberOfHMetrics  Fix = "set-number-of-hmetrics" // Set numberOfHMetrics from the 'hmtx' table.
+	FixAddMetrics           Fix = "add-metrics"            // Add the missing metrics to the 'hmtx' table.
+	FixRecalculateChecksums Fix = "recalculate-checksums"  // Write the font again, which recalculates its checksums.
+	FixClampWeightClass     Fix = "clamp-weight-class"     // Clamp usWeightClass to between 1 and 1000.
+	FixClampWidthClass      Fix = "clamp-width-class"      // Clamp usWidthClass to between 1 and 9.
+	FixSetCodePageRange     Fix = "set-code-page-range"    // Set the symbol bit of ulCodePageRange1 to match the 'cmap' table.
+	FixRemoveOverlaps       Fix = "remove-overlaps"        // Remove the overlaps between contours, as the flatten command does.
+	FixReverseContour       Fix = "reverse-contour"        // Reverse the direction of the contour.
+	FixRenameNotdef         Fix = "rename-notdef"          // Name glyph 0 .notdef in the 'post' table.
+	FixDrawNotdef           Fix = "draw-notdef"            // Draw a box as the outline of .notdef.
+	FixSetNotdefAdvance     Fix = "set-notdef-advance"     // Give .notdef an advance width.
+	FixUnmapNotdef          Fix = "unmap-notdef"           // Remove the mappings of characters to .notdef.
+	FixMapWhitespace        Fix = "map-whitespace"         // Map the whitespace character to an empty glyph.
+	FixRemoveOutline        Fix = "remove-outline"         // Remove the outline of the glyph.
+	FixMatchSpaceAdvance    Fix = "match-space-advance"    // Give no-break space the advance width of space.
+	FixUseComposite         Fix = "use-composite"          // Replace the glyph with a composite of the identical glyph.
+)
+
+// Profile is a set of checks made by ValidateProfile, chosen for where the font
+// will be used. Each profile includes the checks of the profiles before it.
+type Profile int
+
+const (
+	// ProfileMinimal checks that the font has the tables needed to render it.
+	ProfileMinimal Profile = iota
+	// ProfileWeb also checks for the common problems that cause the OpenType
+	// Sanitizer (OTS) to reject a font, so that Chrome and Firefox will not load
+	// it as a web font: missing tables, tables that cannot be parsed, and invalid
+	// header fields, metrics and 'loca' offsets.
+	ProfileWeb
+	// ProfileDesktop also checks for problems that make text render incorrectly,
+	// such as overlapping contours and missing whitespace. It is the profile
+	// used by Validate.
+	ProfileDesktop
+	// ProfileStrict also checks for problems that break the rules of the
+	// OpenType specification, even if common software accepts them.
+	ProfileStrict
+)
+
+// profileNames are the names of the profiles, as used by String and ParseProfile.
+var profileNames = []string{"minimal", "web", "desktop", "strict"}
+
+// String returns the name of the profile.
+func (p Profile) String() string {
+	if p >= 0 && int(p) < len(profileNames) {
+		return profileNames[p]
+	}
+	return fmt.Sprintf("profile %d", int(p))
+}
+
+// ParseProfile returns the profile with the given name: "minimal", "web",
+// "desktop" or "strict".
+func ParseProfile(name string) (Profile, error) {
+	for i, n := range profileNames {
+		if n == name {
+			return Profile(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown validation profile %q, use one of %s", name, strings.Join(profileNames, ", "))
+}
+
+// Rule is a check made by ValidateProfile. The checks made by this package are
+// rules, and others can be added with RegisterRule, for example to enforce an
+// organization's naming or licensing conventions.
+type Rule interface {
+	// Name identifies the rule, for example "required-names".
+	Name() string
+	// Profile is the least strict profile that includes the rule.
+	Profile() Profile
+	// Check returns the problems that the rule finds in the font, or an error
+	// if the font could not be checked.
+	Check(font *Font) ([]Problem, error)
+}
+
+// funcRule is a Rule made by NewRule.
+type funcRule struct {
+	name    string
+	profile Profile
+	check   func(font *Font) ([]Problem, error)
+}
+
+// NewRule returns a Rule with the given name and profile, that uses check to
+// find problems.
+func NewRule(name string, profile Profile, check func(font *Font) ([]Problem, error)) Rule {
+	return &funcRule{name, profile, check}
+}
+
+func (r *funcRule) Name() string                        { return r.name }
+func (r *funcRule) Profile() Profile                    { return r.profile }
+func (r *funcRule) Check(font *Font) ([]Problem, error) { return r.check(font) }
+
+var rulesMu sync.RWMutex
+
+// rules are run in order by ValidateProfile, if they are part of the profile.
+var rules = []Rule{
+	NewRule("core-tables", ProfileMinimal, validateCoreTables),
+	NewRule("web-tables", ProfileWeb, validateWebTables),
+	NewRule("tables", ProfileWeb, validateTables),
+	NewRule("headers", ProfileWeb, validateHeaders),
+	NewRule("metrics", ProfileWeb, validateMetrics),
+	NewRule("loca", ProfileWeb, validateLoca),
+	NewRule("contours", ProfileDesktop, validateGlyphOutlines),
+	NewRule("notdef", ProfileDesktop, validateNotdef),
+	NewRule("whitespace", ProfileDesktop, validateWhitespace),
+	NewRule("outlines", ProfileDesktop, validateOutlines),
+	NewRule("checksums", ProfileStrict, validateChecksums),
+	NewRule("os2-classes", ProfileStrict, validateOS2Classes),
+	NewRule("required-names", ProfileStrict, validateRequiredNames),
+}
+
+// RegisterRule adds a rule that is checked by Validate and ValidateProfile,
+// after the rules already registered. A rule with the same name as an existing
+// rule replaces it, which allows the rules of this package to be changed. It is
+// usually called from an init function, before any fonts are validated.
+func RegisterRule(rule Rule) {
+	rulesMu.Lock()
+	defer rulesMu.Unlock()
+	for i, r := range rules {
+		if r.Name() == rule.Name() {
+			rules[i] = rule
+			return
+		}
+	}
+	rules = append(rules, rule)
+}
+
+// Rules returns the registered rules, in the order they are checked.
+func Rules() []Rule {
+	rulesMu.RLock()
+	defer rulesMu.RUnlock()
+	return append([]Rule{}, rules...)
+}
+
+// Validate checks the font for problems that may cause it to render incorrectly.
+// It is the same as ValidateProfile(ProfileDesktop).
+func (font *Font) Validate() ([]Problem, error) {
+	return font.ValidateProfile(ProfileDesktop)
+}
+
+// ValidateProfile checks the font with the registered rules that are part of
+// profile.
+// An error is returned if the font could not be checked, for example because a
+// table could not be parsed.
+func (font *Font) ValidateProfile(profile Profile) ([]Problem, error) {
+	var problems []Problem
+	for _, rule := range Rules() {
+		if rule.Profile() > profile {
+			continue
+		}
+		found, err := rule.Check(font)
+		if err != nil {
+			return nil, err
+		}
+		problems = append(problems, found...)
+	}
+	return problems, nil
+}
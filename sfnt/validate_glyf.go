@@ -0,0 +1,212 @@
+package sfnt
+
+import (
+	"fmt"
+	"math"
+)
+
+// validationCurveSamples is the number of line segments each curve is split into
+// when checking outlines. Fewer samples than CompareOutlines are used, as the
+// checks compare every segment with every other.
+const validationCurveSamples = 4
+
+// validateGlyphOutlines checks the contours of each TrueType glyph for incorrect
+// winding direction, self-intersections, and overlaps with other contours.
+func validateGlyphOutlines(font *Font) ([]Problem, error) {
+	if !font.HasTable(TagGlyf) {
+		return nil, nil
+	}
+	glyf, err := font.GlyfTable()
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []Problem
+	offsets := glyf.glyphOffsets()
+	for i := range glyf.Glyphs {
+		contours, err := glyf.Contours(uint16(i))
+		if err != nil {
+			problems = append(problems, newProblem(SeverityError, TagGlyf, i, err.Error()).at(offsets[i], len(glyf.Glyphs[i])))
+			continue
+		}
+
+		for _, c := range checkContours(contours) {
+			problems = append(problems, newProblem(SeverityWarning, TagGlyf, i, c.message).at(offsets[i], len(glyf.Glyphs[i])).fixedBy(c.fix))
+		}
+	}
+	return problems, nil
+}
+
+// glyphOffsets returns the offset of each glyph in the table's data, as it is
+// laid out by Bytes.
+func (table *TableGlyf) glyphOffsets() []int {
+	padding := table.padding()
+	offsets := make([]int, len(table.Glyphs))
+	offset := 0
+	for i, glyph := range table.Glyphs {
+		offsets[i] = offset
+		offset += (len(glyph) + padding - 1) / padding * padding
+	}
+	return offsets
+}
+
+// contourProblem is a problem found in the contours of a glyph, and its fix.
+type contourProblem struct {
+	message string
+	fix     Fix
+}
+
+// checkContours returns the problems found in the contours of a glyph.
+func checkContours(contours [][]GlyphPoint) []contourProblem {
+	polygons := flattenContours(contours, validationCurveSamples)
+
+	// The direction of a contour can only be determined from how deeply it is
+	// nested when no contours overlap.
+	if found := checkOverlaps(polygons); len(found) > 0 {
+		return found
+	}
+	return checkDirections(polygons)
+}
+
+// flattenContours converts each contour into a polygon.
+func flattenContours(contours [][]GlyphPoint, steps int) [][]vector {
+	polygons := make([][]vector, 0, len(contours))
+	for _, contour := range contours {
+		polygons = append(polygons, flattenContour(contour, steps))
+	}
+	return polygons
+}
+
+// checkOverlaps returns a problem for each polygon that crosses itself or another
+// polygon, or that is nested inside another polygon such that it is filled twice over.
+func checkOverlaps(polygons [][]vector) []contourProblem {
+	var found []contourProblem
+	for i, a := range polygons {
+		if polygonSelfIntersects(a) {
+			found = append(found, contourProblem{fmt.Sprintf("contour %d intersects itself", i), FixRemoveOverlaps})
+		}
+		for j := i + 1; j < len(polygons); j++ {
+			if polygonsIntersect(a, polygons[j]) {
+				found = append(found, contourProblem{fmt.Sprintf("contours %d and %d overlap", i, j), FixRemoveOverlaps})
+			}
+		}
+	}
+	if len(found) > 0 {
+		return found
+	}
+
+	for i, a := range polygons {
+		area := polygonArea(a)
+		if area == 0 {
+			continue
+		}
+		winding := 0
+		for j, b := range polygons {
+			if i != j && pointInPolygon(a[0], b) {
+				winding += direction(polygonArea(b))
+			}
+		}
+		if inside := winding + direction(area); inside > 1 || inside < -1 {
+			found = append(found, contourProblem{fmt.Sprintf("contour %d overlaps a contour containing it", i), FixRemoveOverlaps})
+		}
+	}
+	return found
+}
+
+// checkDirections returns a problem for each polygon with the wrong direction,
+// given that none of the polygons overlap.
+func checkDirections(polygons [][]vector) []contourProblem {
+	var found []contourProblem
+	for i, a := range polygons {
+		area := polygonArea(a)
+		if area == 0 {
+			continue
+		}
+		depth := 0
+		for j, b := range polygons {
+			if i != j && pointInPolygon(a[0], b) {
+				depth++
+			}
+		}
+
+		// TrueType outer contours are clockwise, so have a negative area with y pointing up.
+		if outer := depth%2 == 0; outer && area > 0 {
+			found = append(found, contourProblem{fmt.Sprintf("outer contour %d is counter-clockwise, but should be clockwise", i), FixReverseContour})
+		} else if !outer && area < 0 {
+			found = append(found, contourProblem{fmt.Sprintf("inner contour %d is clockwise, but should be counter-clockwise", i), FixReverseContour})
+		}
+	}
+	return found
+}
+
+// polygonArea returns the signed area of a polygon, which is positive if it is counter-clockwise.
+func polygonArea(polygon []vector) float64 {
+	area := 0.0
+	for i, p := range polygon {
+		area += p.cross(polygon[(i+1)%len(polygon)])
+	}
+	return area / 2
+}
+
+// direction returns +1 for a counter-clockwise polygon with the given area, and -1 otherwise.
+func direction(area float64) int {
+	if area > 0 {
+		return 1
+	}
+	return -1
+}
+
+// pointInPolygon returns true if p is inside polygon, using the non-zero winding rule.
+func pointInPolygon(p vector, polygon []vector) bool {
+	winding := 0
+	for i, a := range polygon {
+		b := polygon[(i+1)%len(polygon)]
+		if a.Y <= p.Y && b.Y > p.Y && b.sub(a).cross(p.sub(a)) > 0 {
+			winding++
+		} else if a.Y > p.Y && b.Y <= p.Y && b.sub(a).cross(p.sub(a)) < 0 {
+			winding--
+		}
+	}
+	return winding != 0
+}
+
+// segmentsCross returns true if the segments a-b and c-d cross at a point that is
+// not an end point of either segment.
+func segmentsCross(a, b, c, d vector) bool {
+	if math.Max(a.X, b.X) < math.Min(c.X, d.X) || math.Max(c.X, d.X) < math.Min(a.X, b.X) ||
+		math.Max(a.Y, b.Y) < math.Min(c.Y, d.Y) || math.Max(c.Y, d.Y) < math.Min(a.Y, b.Y) {
+		return false
+	}
+	ab, cd := b.sub(a), d.sub(c)
+	d1, d2 := ab.cross(c.sub(a)), ab.cross(d.sub(a))
+	d3, d4 := cd.cross(a.sub(c)), cd.cross(b.sub(c))
+	return (d1 > 0 && d2 < 0 || d1 < 0 && d2 > 0) && (d3 > 0 && d4 < 0 || d3 < 0 && d4 > 0)
+}
+
+// polygonSelfIntersects returns true if any two non-adjacent edges of polygon cross.
+func polygonSelfIntersects(polygon []vector) bool {
+	n := len(polygon)
+	for i := 0; i < n; i++ {
+		for j := i + 2; j < n; j++ {
+			if i == 0 && j == n-1 {
+				continue
+			}
+			if segmentsCross(polygon[i], polygon[(i+1)%n], polygon[j], polygon[(j+1)%n]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// polygonsIntersect returns true if any edge of a crosses an edge of b.
+func polygonsIntersect(a, b []vector) bool {
+	for i := range a {
+		for j := range b {
+			if segmentsCross(a[i], a[(i+1)%len(a)], b[j], b[(j+1)%len(b)]) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package sfnt
+
+import (
+	"reflect"
+	"testing"
+)
+
+// square returns a contour for a square with the given corner and size,
+// which is clockwise if size is positive.
+func square(x, y, size int16) []GlyphPoint {
+	return []GlyphPoint{{x, y, true}, {x, y + size, true}, {x + size, y + size, true}, {x + size, y, true}}
+}
+
+func reversed(contour []GlyphPoint) []GlyphPoint {
+	r := make([]GlyphPoint, len(contour))
+	for i, p := range contour {
+		r[len(contour)-1-i] = p
+	}
+	return r
+}
+
+func TestCheckContours(t *testing.T) {
+	tests := []struct {
+		name     string
+		contours [][]GlyphPoint
+		want     []contourProblem
+	}{
+		{"clockwise", [][]GlyphPoint{square(0, 0, 100)}, nil},
+		{"hole", [][]GlyphPoint{square(0, 0, 100), reversed(square(25, 25, 50))}, nil},
+		{"counter-clockwise", [][]GlyphPoint{reversed(square(0, 0, 100))}, []contourProblem{{"outer contour 0 is counter-clockwise, but should be clockwise", FixReverseContour}}},
+		{"clockwise hole", [][]GlyphPoint{square(0, 0, 100), reversed(square(25, 25, 50)), square(30, 30, 10)}, nil},
+		{"nested", [][]GlyphPoint{square(0, 0, 100), square(25, 25, 50)}, []contourProblem{{"contour 1 overlaps a contour containing it", FixRemoveOverlaps}}},
+		{"overlap", [][]GlyphPoint{square(0, 0, 100), square(50, 50, 100)}, []contourProblem{{"contours 0 and 1 overlap", FixRemoveOverlaps}}},
+		{"bow tie", [][]GlyphPoint{{{0, 0, true}, {100, 100, true}, {100, 0, true}, {0, 100, true}}}, []contourProblem{{"contour 0 intersects itself", FixRemoveOverlaps}}},
+	}
+
+	for _, test := range tests {
+		if got := checkContours(test.contours); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("checkContours(%s) = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	font := parseTestFont(t, "open-sans-v15-latin-regular.woff")
+	problems, err := font.Validate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Composite glyphs whose components touch, like the cedilla in Ccedilla, overlap.
+	gids, err := font.GlyphIDs([]string{"Ccedilla"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range problems {
+		if p.GlyphID == int(gids[0]) {
+			return
+		}
+	}
+	t.Errorf("Validate() = %v, want a problem with Ccedilla", problems)
+}
@@ -0,0 +1,267 @@
+package sfnt
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// whitespaceChecks are the whitespace characters that fonts should map, and the
+// severity of a missing mapping.
+var whitespaceChecks = []struct {
+	r        rune
+	name     string
+	severity Severity
+}{
+	{' ', "space", SeverityWarning},
+	{0x00A0, "no-break space", SeverityWarning},
+	{'\r', "carriage return", SeverityInfo},
+}
+
+// validateNotdef checks that glyph 0 is a .notdef glyph that is visible, so
+// that missing characters are not silently left out of text.
+func validateNotdef(font *Font) ([]Problem, error) {
+	var problems []Problem
+	if font.HasTable(TagPost) {
+		post, err := font.PostTable()
+		if err != nil {
+			return nil, err
+		}
+		if names := post.GlyphNames(); len(names) > 0 && names[0] != ".notdef" {
+			problems = append(problems, newProblem(SeverityWarning, TagPost, 0, fmt.Sprintf("glyph 0 is named %q, but should be .notdef", names[0])).fixedBy(FixRenameNotdef))
+		}
+	}
+
+	if font.HasTable(TagCmap) {
+		cmap, err := font.CmapTable()
+		if err != nil {
+			return nil, err
+		}
+		if subtable := cmap.Unicode(); subtable != nil {
+			// The last segment of a format 4 subtable maps U+FFFF to glyph 0.
+			var runes []rune
+			for r, gid := range subtable.Mapping {
+				if gid == 0 && r != 0xFFFF {
+					runes = append(runes, r)
+				}
+			}
+			sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+			for _, r := range runes {
+				problems = append(problems, newProblem(SeverityWarning, TagCmap, 0, fmt.Sprintf("%U is mapped to .notdef", r)).fixedBy(FixUnmapNotdef))
+			}
+		}
+	}
+
+	if font.HasTable(TagHmtx) {
+		hmtx, err := font.HmtxTable()
+		if err != nil {
+			return nil, err
+		}
+		if hmtx.Advance(0) == 0 {
+			problems = append(problems, newProblem(SeverityWarning, TagHmtx, 0, ".notdef has no advance width, so missing characters take no space").at(0, 2).fixedBy(FixSetNotdefAdvance))
+		}
+	}
+
+	if font.HasTable(TagGlyf) {
+		glyf, err := font.GlyfTable()
+		if err != nil {
+			return nil, err
+		}
+		if glyf.NumGlyphs() == 0 {
+			return append(problems, newProblem(SeverityError, TagGlyf, -1, "the font has no glyphs, but glyph 0 must be .notdef").fixedBy(FixDrawNotdef)), nil
+		}
+		glyph, err := glyf.Glyph(0)
+		if err != nil {
+			return nil, err
+		}
+		if glyph.IsEmpty() {
+			problems = append(problems, newProblem(SeverityWarning, TagGlyf, 0, ".notdef has no outline, so missing characters are invisible").at(0, len(glyf.Glyphs[0])).fixedBy(FixDrawNotdef))
+		}
+	}
+	return problems, nil
+}
+
+// validateWhitespace checks that the common whitespace characters are mapped
+// to empty glyphs with a sensible advance width, and that control characters
+// are not drawn.
+func validateWhitespace(font *Font) ([]Problem, error) {
+	if !font.HasTable(TagCmap) {
+		return nil, nil
+	}
+	cmap, err := font.CmapTable()
+	if err != nil {
+		return nil, err
+	}
+	subtable := cmap.Unicode()
+	if subtable == nil {
+		return nil, nil
+	}
+
+	var glyf *TableGlyf
+	if font.HasTable(TagGlyf) {
+		if glyf, err = font.GlyfTable(); err != nil {
+			return nil, err
+		}
+	}
+	var hmtx *TableHmtx
+	if font.HasTable(TagHmtx) {
+		if hmtx, err = font.HmtxTable(); err != nil {
+			return nil, err
+		}
+	}
+	head, err := font.HeadTable()
+	if err != nil {
+		return nil, err
+	}
+	hasOutline := func(gid uint16) bool {
+		if glyf == nil || int(gid) >= glyf.NumGlyphs() {
+			return false
+		}
+		glyph, err := glyf.Glyph(gid)
+		return err == nil && !glyph.IsEmpty()
+	}
+	// glyphProblem sets the range of a problem with the outline of a glyph,
+	// which is only found if the glyph has one.
+	var offsets []int
+	glyphProblem := func(p Problem) Problem {
+		if offsets == nil {
+			offsets = glyf.glyphOffsets()
+		}
+		return p.at(offsets[p.GlyphID], len(glyf.Glyphs[p.GlyphID]))
+	}
+
+	var problems []Problem
+	for _, check := range whitespaceChecks {
+		gid, found := subtable.Mapping[check.r]
+		if !found || gid == 0 {
+			problems = append(problems, newProblem(check.severity, TagCmap, -1, fmt.Sprintf("%s (%U) is not mapped", check.name, check.r)).fixedBy(FixMapWhitespace))
+			continue
+		}
+		if hasOutline(gid) {
+			problems = append(problems, glyphProblem(newProblem(SeverityWarning, TagGlyf, int(gid), fmt.Sprintf("%s (%U) has an outline, but should be empty", check.name, check.r)).fixedBy(FixRemoveOutline)))
+		}
+		if hmtx == nil || check.r == '\r' {
+			continue
+		}
+		if advance := hmtx.Advance(gid); advance == 0 || advance > head.UnitsPerEm {
+			problems = append(problems, newProblem(SeverityWarning, TagHmtx, int(gid), fmt.Sprintf("%s (%U) has an advance width of %d", check.name, check.r, advance)).at(hmtx.advanceOffset(gid), 2))
+		}
+	}
+	if hmtx != nil {
+		space, nbsp := subtable.Mapping[' '], subtable.Mapping[0x00A0]
+		if space != 0 && nbsp != 0 && hmtx.Advance(space) != hmtx.Advance(nbsp) {
+			problems = append(problems, newProblem(SeverityWarning, TagHmtx, int(nbsp), fmt.Sprintf("no-break space is %d wide, but space is %d", hmtx.Advance(nbsp), hmtx.Advance(space))).at(hmtx.advanceOffset(nbsp), 2).fixedBy(FixMatchSpaceAdvance))
+		}
+	}
+
+	for r := rune(0); r <= 0x9F; r++ {
+		if r == 0x20 {
+			r = 0x7F // skip to the C1 controls and delete
+		}
+		if gid, found := subtable.Mapping[r]; found && hasOutline(gid) {
+			problems = append(problems, glyphProblem(newProblem(SeverityWarning, TagGlyf, int(gid), fmt.Sprintf("control character %U has an outline, but should be empty", r)).fixedBy(FixRemoveOutline)))
+		}
+	}
+	return problems, nil
+}
+
+// DuplicateOutlines returns the groups of glyphs that have identical outlines,
+// ignoring their hinting instructions. Each group is in glyph id order, and
+// all but the first glyph of a group could be replaced by a composite glyph
+// referring to it. Empty glyphs are not included.
+func (table *TableGlyf) DuplicateOutlines() ([][]uint16, error) {
+	groups := map[string][]uint16{}
+	var keys []string
+	for i := range table.Glyphs {
+		glyph, err := table.Glyph(uint16(i))
+		if err != nil {
+			return nil, err
+		}
+		if glyph.IsEmpty() {
+			continue
+		}
+		glyph.Instructions = nil
+		key := string(glyph.Bytes())
+		if _, found := groups[key]; !found {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], uint16(i))
+	}
+
+	var duplicates [][]uint16
+	for _, key := range keys {
+		if len(groups[key]) > 1 {
+			duplicates = append(duplicates, groups[key])
+		}
+	}
+	return duplicates, nil
+}
+
+// validateOutlines checks for characters that are mapped to glyphs with no
+// outline, and for glyphs with the same outline as another glyph.
+func validateOutlines(font *Font) ([]Problem, error) {
+	if !font.HasTable(TagGlyf) {
+		return nil, nil
+	}
+	glyf, err := font.GlyfTable()
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []Problem
+	offsets := glyf.glyphOffsets()
+	if font.HasTable(TagCmap) {
+		cmap, err := font.CmapTable()
+		if err != nil {
+			return nil, err
+		}
+		if subtable := cmap.Unicode(); subtable != nil {
+			empty := map[uint16][]string{}
+			for r, gid := range subtable.Mapping {
+				if gid == 0 || int(gid) >= glyf.NumGlyphs() || isInvisible(r) {
+					continue
+				}
+				if glyph, err := glyf.Glyph(gid); err == nil && glyph.IsEmpty() {
+					empty[gid] = append(empty[gid], fmt.Sprintf("%U", r))
+				}
+			}
+			gids := make([]uint16, 0, len(empty))
+			for gid := range empty {
+				gids = append(gids, gid)
+			}
+			sort.Slice(gids, func(i, j int) bool { return gids[i] < gids[j] })
+			for _, gid := range gids {
+				sort.Strings(empty[gid])
+				problems = append(problems, newProblem(SeverityWarning, TagGlyf, int(gid), fmt.Sprintf("has no outline, but is mapped from %s", strings.Join(empty[gid], ", "))).at(offsets[gid], 0))
+			}
+		}
+	}
+
+	duplicates, err := glyf.DuplicateOutlines()
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range duplicates {
+		for _, gid := range group[1:] {
+			problems = append(problems, newProblem(SeverityInfo, TagGlyf, int(gid), fmt.Sprintf("has the same outline as glyph %d, and could be a composite of it", group[0])).at(offsets[gid], len(glyf.Glyphs[gid])).fixedBy(FixUseComposite))
+		}
+	}
+	return problems, nil
+}
+
+// advanceOffset returns the offset of the advance width of the glyph in the
+// table's data, as it is laid out by Bytes. Glyphs after the last long metric
+// share its advance width.
+func (table *TableHmtx) advanceOffset(gid uint16) int {
+	if n := table.NumberOfHMetrics(); int(gid) >= n {
+		return 4 * (n - 1)
+	}
+	return 4 * int(gid)
+}
+
+// isInvisible returns true for characters that are not drawn, such as spaces,
+// controls, joiners and variation selectors.
+func isInvisible(r rune) bool {
+	return unicode.IsSpace(r) || unicode.In(r, unicode.Cc, unicode.Cf, unicode.Zs, unicode.Variation_Selector, unicode.Other_Default_Ignorable_Code_Point)
+}
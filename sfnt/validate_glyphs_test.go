@@ -0,0 +1,111 @@
+package sfnt
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+func TestValidateNotdefAndWhitespace(t *testing.T) {
+	b := NewBuilder(1000)
+	b.Map(' ', b.AddGlyph("space", 250, nil))
+	b.Map(0x00A0, b.AddGlyph("nbspace", 300, nil))
+	b.Map('\r', b.AddGlyph("CR", 250, nil))
+	b.Map(0x0007, b.AddGlyph("bell", 500, [][]GlyphPoint{square(0, 0, 100)}))
+	font, err := b.Font()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	problems, err := validateWhitespace(font)
+	if err != nil {
+		t.Fatal(err)
+	}
+	glyf, err := font.GlyfTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Problem{
+		newProblem(SeverityWarning, TagHmtx, 2, "no-break space is 300 wide, but space is 250").at(8, 2).fixedBy(FixMatchSpaceAdvance),
+		newProblem(SeverityWarning, TagGlyf, 4, "control character U+0007 has an outline, but should be empty").at(glyf.glyphOffsets()[4], len(glyf.Glyphs[4])).fixedBy(FixRemoveOutline),
+	}
+	if !reflect.DeepEqual(problems, want) {
+		t.Errorf("validateWhitespace() = %v, want %v", problems, want)
+	}
+	if problems, err := validateNotdef(font); err != nil || len(problems) != 0 {
+		t.Errorf("validateNotdef() = %v, %v, want no problems", problems, err)
+	}
+
+	if err := glyf.SetGlyph(0, &Glyph{}); err != nil {
+		t.Fatal(err)
+	}
+	problems, err = validateNotdef(font)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []Problem{newProblem(SeverityWarning, TagGlyf, 0, ".notdef has no outline, so missing characters are invisible").fixedBy(FixDrawNotdef)}
+	if !reflect.DeepEqual(problems, want) {
+		t.Errorf("validateNotdef() = %v, want %v", problems, want)
+	}
+
+	empty, err := NewBuilder(1000).Font()
+	if err != nil {
+		t.Fatal(err)
+	}
+	problems, err = validateWhitespace(empty)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(problems) != 3 || problems[0].Message != "space (U+0020) is not mapped" || problems[2].Severity != SeverityInfo {
+		t.Errorf("validateWhitespace() of a font without whitespace = %v", problems)
+	}
+}
+
+func TestValidateOutlines(t *testing.T) {
+	b := NewBuilder(1000)
+	b.Map(' ', b.AddGlyph("space", 250, nil))
+	b.Map('A', b.AddGlyph("A", 600, [][]GlyphPoint{square(0, 0, 500)}))
+	b.Map('B', b.AddGlyph("B", 600, nil))
+	b.Map(0x0391, b.AddGlyph("Alpha", 600, [][]GlyphPoint{square(0, 0, 500)}))
+	b.Map(0x200D, b.AddGlyph("zwj", 0, nil))
+	font, err := b.Font()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	glyf, err := font.GlyfTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	duplicates, err := glyf.DuplicateOutlines()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [][]uint16{{2, 4}}; !reflect.DeepEqual(duplicates, want) {
+		t.Errorf("DuplicateOutlines() = %v, want %v", duplicates, want)
+	}
+
+	// The offsets of the glyphs match those in the 'loca' table.
+	offsets := glyf.glyphOffsets()
+	loca, err := font.TableData(TagLoca)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, offset := range offsets {
+		if want := 2 * int(binary.BigEndian.Uint16(loca[2*i:])); offset != want {
+			t.Errorf("glyphOffsets()[%d] = %d, want %d", i, offset, want)
+		}
+	}
+
+	problems, err := validateOutlines(font)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Problem{
+		newProblem(SeverityWarning, TagGlyf, 3, "has no outline, but is mapped from U+0042").at(offsets[3], 0),
+		newProblem(SeverityInfo, TagGlyf, 4, "has the same outline as glyph 2, and could be a composite of it").at(offsets[4], len(glyf.Glyphs[4])).fixedBy(FixUseComposite),
+	}
+	if !reflect.DeepEqual(problems, want) {
+		t.Errorf("validateOutlines() = %v, want %v", problems, want)
+	}
+}
@@ -0,0 +1,145 @@
+package sfnt
+
+import (
+	"fmt"
+)
+
+// Embedding permissions in the fsType field of the 'OS/2' table.
+// https://docs.microsoft.com/en-us/typography/opentype/spec/os2#fstype
+const (
+	fsTypeRestricted   = 0x0002
+	fsTypePreviewPrint = 0x0004
+	fsTypeEditable     = 0x0008
+	fsTypeNoSubsetting = 0x0100
+	fsTypeBitmapOnly   = 0x0200
+)
+
+// codePageSymbol is the bit of ulCodePageRange1 in the 'OS/2' table for the
+// symbol character set.
+const codePageSymbol = 1 << 31
+
+// pdfaRequiredTables are the tables needed to embed a font in a PDF.
+var pdfaRequiredTables = []Tag{TagCmap, TagHead, TagHhea, TagHmtx, TagMaxp, TagPost}
+
+// pdfaValidators are run in order by ValidatePDFA.
+var pdfaValidators = []func(font *Font) ([]Problem, error){
+	validatePDFAEmbedding,
+	validatePDFATables,
+	validatePDFASymbolic,
+}
+
+// ValidatePDFA checks that the font can be embedded in a PDF/A document: its
+// license must allow embedding, it must have the tables that PDF readers use,
+// and whether it is a symbolic font must be clear, so that the Symbolic flag
+// of its font descriptor can be set to match its 'cmap' table.
+//
+// These problems are in addition to those found by Validate.
+func (font *Font) ValidatePDFA() ([]Problem, error) {
+	var problems []Problem
+	for _, validate := range pdfaValidators {
+		found, err := validate(font)
+		if err != nil {
+			return nil, err
+		}
+		problems = append(problems, found...)
+	}
+	return problems, nil
+}
+
+// validatePDFAEmbedding checks the embedding permissions of the font.
+func validatePDFAEmbedding(font *Font) ([]Problem, error) {
+	if !font.HasTable(TagOS2) {
+		return nil, nil
+	}
+	os2, err := font.OS2Table()
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []Problem
+	if os2.FSType&fsTypeRestricted != 0 {
+		problems = append(problems, newProblem(SeverityError, TagOS2, -1, "fsType does not allow the font to be embedded").at(os2FSTypeOffset, 2))
+	}
+	if os2.FSType&fsTypeBitmapOnly != 0 {
+		problems = append(problems, newProblem(SeverityError, TagOS2, -1, "fsType only allows bitmaps to be embedded, but PDF/A requires outlines").at(os2FSTypeOffset, 2))
+	}
+	if os2.FSType&fsTypeNoSubsetting != 0 {
+		problems = append(problems, newProblem(SeverityWarning, TagOS2, -1, "fsType does not allow subsetting, so the whole font must be embedded").at(os2FSTypeOffset, 2))
+	}
+
+	// Versions 3 and later allow at most one of the usage permissions.
+	usage := 0
+	for _, bit := range []uint16{fsTypeRestricted, fsTypePreviewPrint, fsTypeEditable} {
+		if os2.FSType&bit != 0 {
+			usage++
+		}
+	}
+	if usage > 1 && os2.Version >= 3 {
+		problems = append(problems, newProblem(SeverityWarning, TagOS2, -1, fmt.Sprintf("fsType 0x%04X sets conflicting embedding permissions, and PDF/A validators may use the most restrictive", os2.FSType)).at(os2FSTypeOffset, 2))
+	}
+	return problems, nil
+}
+
+// validatePDFATables checks that the font has the tables that PDF readers need,
+// and outlines that PDF/A documents may contain.
+func validatePDFATables(font *Font) ([]Problem, error) {
+	var problems []Problem
+	for _, tag := range pdfaRequiredTables {
+		if !font.HasTable(tag) {
+			problems = append(problems, newProblem(SeverityError, tag, -1, "the table is required to embed the font in a PDF").fixedBy(FixAddTable))
+		}
+	}
+
+	switch {
+	case font.HasTable(tagCFF2):
+		problems = append(problems, newProblem(SeverityError, tagCFF2, -1, "PDF does not support fonts with CFF2 outlines"))
+	case font.HasTable(TagCFF):
+	case !font.HasTable(TagGlyf) || !font.HasTable(TagLoca):
+		problems = append(problems, newProblem(SeverityError, TagGlyf, -1, "the font has no glyf and loca or CFF outlines to embed"))
+	}
+	return problems, nil
+}
+
+// validatePDFASymbolic checks that it is clear whether the font is symbolic. A
+// non-symbolic TrueType font must have a Unicode 'cmap' subtable, and a symbolic
+// one should only have a symbol subtable.
+func validatePDFASymbolic(font *Font) ([]Problem, error) {
+	if !font.HasTable(TagCmap) {
+		return nil, nil
+	}
+	cmap, err := font.CmapTable()
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []Problem
+	hasUnicode := false
+	for _, s := range cmap.Subtables {
+		if s.PlatformID == PlatformMicrosoft && s.EncodingID == PlatformEncodingMicrosoftUnicode {
+			hasUnicode = true
+		}
+	}
+	symbol := cmap.Symbol() != nil
+	switch {
+	case symbol && hasUnicode:
+		problems = append(problems, newProblem(SeverityWarning, TagCmap, -1, "the table has both symbol (3,0) and Unicode (3,1) subtables, so it is unclear whether the font is symbolic"))
+	case !symbol && !hasUnicode:
+		problems = append(problems, newProblem(SeverityError, TagCmap, -1, "a non-symbolic font needs a Microsoft Unicode (3,1) subtable").fixedBy(FixAddUnicodeCmap))
+	}
+
+	if font.HasTable(TagOS2) {
+		os2, err := font.OS2Table()
+		if err != nil {
+			return nil, err
+		}
+		codePage := os2.UlCodePageRange1&codePageSymbol != 0
+		switch {
+		case os2.Version < 1:
+		case codePage && !symbol:
+			problems = append(problems, newProblem(SeverityWarning, TagOS2, -1, "ulCodePageRange1 includes the symbol character set, but the cmap table has no symbol subtable").at(os2CodePageRange1Offset, 4).fixedBy(FixSetCodePageRange))
+		case !codePage && symbol:
+			problems = append(problems, newProblem(SeverityWarning, TagOS2, -1, "the cmap table has a symbol subtable, but ulCodePageRange1 does not include the symbol character set").at(os2CodePageRange1Offset, 4).fixedBy(FixSetCodePageRange))
+		}
+	}
+	return problems, nil
+}
@@ -0,0 +1,41 @@
+package sfnt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidatePDFA(t *testing.T) {
+	b := NewBuilder(1000)
+	b.Map('A', b.AddGlyph("A", 600, [][]GlyphPoint{square(0, 0, 500)}))
+	font, err := b.Font()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if problems, err := font.ValidatePDFA(); err != nil || len(problems) != 0 {
+		t.Errorf("ValidatePDFA() = %v, %v; want no problems", problems, err)
+	}
+
+	os2, err := font.OS2Table()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os2.FSType = fsTypeRestricted | fsTypePreviewPrint | fsTypeNoSubsetting
+	font.RemoveTable(TagPost)
+	font.AddTable(TagCmap, newTableCmap(map[rune]uint16{0xF041: 1}, nil, true))
+
+	problems, err := font.ValidatePDFA()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Problem{
+		newProblem(SeverityError, TagOS2, -1, "fsType does not allow the font to be embedded").at(8, 2),
+		newProblem(SeverityWarning, TagOS2, -1, "fsType does not allow subsetting, so the whole font must be embedded").at(8, 2),
+		newProblem(SeverityWarning, TagOS2, -1, "fsType 0x0106 sets conflicting embedding permissions, and PDF/A validators may use the most restrictive").at(8, 2),
+		newProblem(SeverityError, TagPost, -1, "the table is required to embed the font in a PDF").fixedBy(FixAddTable),
+		newProblem(SeverityWarning, TagOS2, -1, "the cmap table has a symbol subtable, but ulCodePageRange1 does not include the symbol character set").at(78, 4).fixedBy(FixSetCodePageRange),
+	}
+	if !reflect.DeepEqual(problems, want) {
+		t.Errorf("ValidatePDFA() = %v, want %v", problems, want)
+	}
+}
@@ -0,0 +1,83 @@
+package sfnt
+
+import (
+	"fmt"
+)
+
+// requiredNames are the entries that the 'name' table must have for the
+// Windows platform.
+// https://docs.microsoft.com/en-us/typography/opentype/spec/name#name-ids
+var requiredNames = []NameID{NameFontFamily, NameFontSubfamily, NameUniqueIdentifier, NameFull, NameVersion, NamePostscript}
+
+// Offsets of the fields in the 'OS/2' table that are checked by validation.
+const (
+	os2WeightClassOffset    = 4
+	os2WidthClassOffset     = 6
+	os2FSTypeOffset         = 8
+	os2CodePageRange1Offset = 78
+)
+
+// validateChecksums checks the checksums recorded in the table directory of the
+// file the font was parsed from, as verifyChecksums does. WOFF2 files, which do
+// not record checksums, are not checked.
+func validateChecksums(font *Font) ([]Problem, error) {
+	errs, err := font.verifyChecksums()
+	if err != nil {
+		return nil, err
+	}
+	var problems []Problem
+	for _, e := range errs {
+		if e.Recorded == 0 {
+			continue
+		}
+		problems = append(problems, newProblem(SeverityError, e.Tag, -1, fmt.Sprintf("the table directory has checksum 0x%08X, but the table has 0x%08X", e.Recorded, e.Computed)).at(0, e.length).fixedBy(FixRecalculateChecksums))
+	}
+	return problems, nil
+}
+
+// validateOS2Classes checks that the weight and width classes in the 'OS/2'
+// table are in the ranges allowed by the specification.
+func validateOS2Classes(font *Font) ([]Problem, error) {
+	if !font.HasTable(TagOS2) {
+		return nil, nil
+	}
+	os2, err := font.OS2Table()
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []Problem
+	if os2.USWeightClass < 1 || os2.USWeightClass > 1000 {
+		problems = append(problems, newProblem(SeverityError, TagOS2, -1, fmt.Sprintf("usWeightClass %d is not between 1 and 1000", os2.USWeightClass)).at(os2WeightClassOffset, 2).fixedBy(FixClampWeightClass))
+	}
+	if os2.USWidthClass < 1 || os2.USWidthClass > 9 {
+		problems = append(problems, newProblem(SeverityError, TagOS2, -1, fmt.Sprintf("usWidthClass %d is not between 1 and 9", os2.USWidthClass)).at(os2WidthClassOffset, 2).fixedBy(FixClampWidthClass))
+	}
+	return problems, nil
+}
+
+// validateRequiredNames checks that the 'name' table has the entries that the
+// specification requires for the Windows platform.
+func validateRequiredNames(font *Font) ([]Problem, error) {
+	if !font.HasTable(TagName) {
+		return nil, nil
+	}
+	name, err := font.NameTable()
+	if err != nil {
+		return nil, err
+	}
+
+	found := map[NameID]bool{}
+	for _, entry := range name.List() {
+		if entry.PlatformID == PlatformMicrosoft {
+			found[entry.NameID] = true
+		}
+	}
+	var problems []Problem
+	for _, id := range requiredNames {
+		if !found[id] {
+			problems = append(problems, newProblem(SeverityError, TagName, -1, fmt.Sprintf("there is no Windows entry for %s (name ID %d)", id, int(id))).fixedBy(FixAddName))
+		}
+	}
+	return problems, nil
+}
@@ -0,0 +1,66 @@
+package sfnt
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestValidateProfileStrict(t *testing.T) {
+	data, err := ioutil.ReadFile(filepath.Join("testdata", "Roboto-BoldItalic.ttf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	font, err := Parse(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, validate := range []func(*Font) ([]Problem, error){validateChecksums, validateOS2Classes, validateRequiredNames} {
+		if problems, err := validate(font); err != nil || len(problems) != 0 {
+			t.Errorf("strict validation of Roboto = %v, %v; want no problems", problems, err)
+		}
+	}
+
+	var post TableRecord
+	for _, record := range font.Directory() {
+		if record.Tag == TagPost {
+			post = record
+		}
+	}
+	data[post.Offset+4]++
+	font, err = Parse(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	os2, err := font.OS2Table()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os2.USWidthClass = 0
+	name, err := font.NameTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	name.Remove(NameUniqueIdentifier)
+
+	problems, err := font.ValidateProfile(ProfileStrict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var strict []Problem
+	for _, p := range problems {
+		if p.GlyphID < 0 && p.Severity == SeverityError {
+			strict = append(strict, p)
+		}
+	}
+	want := []Problem{
+		newProblem(SeverityError, TagPost, -1, "the table directory has checksum 0xFF610064, but the table has 0x00610064").at(0, int(post.Length)).fixedBy(FixRecalculateChecksums),
+		newProblem(SeverityError, TagOS2, -1, "usWidthClass 0 is not between 1 and 9").at(6, 2).fixedBy(FixClampWidthClass),
+		newProblem(SeverityError, TagName, -1, "there is no Windows entry for Unique Identifier (name ID 3)").fixedBy(FixAddName),
+	}
+	if !reflect.DeepEqual(strict, want) {
+		t.Errorf("ValidateProfile(ProfileStrict) = %v, want %v", strict, want)
+	}
+}
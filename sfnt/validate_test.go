@@ -0,0 +1,58 @@
+package sfnt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRegisterRule(t *testing.T) {
+	defer func(registered []Rule) { rules = registered }(Rules())
+
+	font := parseTestFont(t, "Roboto-BoldItalic.ttf")
+	vendor := MustNamedTag("HSPT")
+	RegisterRule(NewRule("vendor", ProfileMinimal, func(font *Font) ([]Problem, error) {
+		os2, err := font.OS2Table()
+		if err != nil {
+			return nil, err
+		}
+		if os2.AchVendID != vendor {
+			return []Problem{newProblem(SeverityError, TagOS2, -1, "achVendID is "+os2.AchVendID.String())}, nil
+		}
+		return nil, nil
+	}))
+	// Replace a rule of this package, so that it finds no problems.
+	RegisterRule(NewRule("required-names", ProfileStrict, func(font *Font) ([]Problem, error) {
+		return nil, nil
+	}))
+
+	if got := Rules(); got[len(got)-1].Name() != "vendor" || len(got) != 14 {
+		t.Errorf("Rules() has %d rules ending with %q, want 14 ending with %q", len(got), got[len(got)-1].Name(), "vendor")
+	}
+	problems, err := font.ValidateProfile(ProfileMinimal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Problem{newProblem(SeverityError, TagOS2, -1, "achVendID is GOOG")}
+	if !reflect.DeepEqual(problems, want) {
+		t.Errorf("ValidateProfile(ProfileMinimal) = %v, want %v", problems, want)
+	}
+
+	name, err := font.NameTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	name.Remove(NameUniqueIdentifier)
+	problems, err = validateRequiredNames(font)
+	if err != nil || len(problems) != 1 {
+		t.Fatalf("validateRequiredNames() = %v, %v; want a problem", problems, err)
+	}
+	problems, err = font.ValidateProfile(ProfileStrict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range problems {
+		if p.Tag == TagName {
+			t.Errorf("ValidateProfile(ProfileStrict) = %v, want no problems from the replaced rule", p)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package sfnt
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// coreTables are the tables that every font needs to be rendered.
+var coreTables = []Tag{TagCmap, TagHead, TagHhea, TagHmtx, TagMaxp}
+
+// webTables are the tables that the OpenType Sanitizer also requires, so that
+// browsers do not load web fonts without them.
+// https://github.com/khaledhosny/ots/blob/main/src/ots.cc
+var webTables = []Tag{TagName, TagOS2, TagPost}
+
+// headMagicNumber is the value of the magicNumber field of the 'head' table.
+const headMagicNumber = 0x5F0F3CF5
+
+// Offsets of the fields in the 'head', 'hhea' and 'maxp' tables that are
+// checked by validateHeaders.
+const (
+	headMagicNumberOffset      = 12
+	headUnitsPerEmOffset       = 18
+	headIndexToLocFormatOffset = 50
+	hheaNumberOfHMetricsOffset = 34
+	maxpNumGlyphsOffset        = 4
+)
+
+// The range of unitsPerEm accepted by the OpenType Sanitizer.
+const (
+	minUnitsPerEm = 16
+	maxUnitsPerEm = 16384
+)
+
+// validateCoreTables checks that the font has the tables that are needed to
+// render it, and outlines.
+func validateCoreTables(font *Font) ([]Problem, error) {
+	var problems []Problem
+	for _, tag := range coreTables {
+		if !font.HasTable(tag) {
+			problems = append(problems, newProblem(SeverityError, tag, -1, "the table is required").fixedBy(FixAddTable))
+		}
+	}
+	if !font.HasTable(TagCFF) && !font.HasTable(tagCFF2) && !(font.HasTable(TagGlyf) && font.HasTable(TagLoca)) {
+		problems = append(problems, newProblem(SeverityError, TagGlyf, -1, "the font has no glyf and loca or CFF outlines"))
+	}
+	return problems, nil
+}
+
+// validateWebTables checks that the font has the other tables that browsers
+// require in web fonts.
+func validateWebTables(font *Font) ([]Problem, error) {
+	var problems []Problem
+	for _, tag := range webTables {
+		if !font.HasTable(tag) {
+			problems = append(problems, newProblem(SeverityError, tag, -1, "the table is required in web fonts").fixedBy(FixAddTable))
+		}
+	}
+	return problems, nil
+}
+
+// validateTables checks that every table can be parsed, as the OpenType Sanitizer
+// parses the tables it supports and rejects fonts with tables it cannot parse.
+// Tables without a parser in this package are not checked.
+func validateTables(font *Font) ([]Problem, error) {
+	var problems []Problem
+	for _, tag := range font.Tags() {
+		var err error
+		switch tag {
+		case TagGlyf:
+			if loca, locaErr := font.TableData(TagLoca); locaErr == nil && len(loca) == 0 {
+				// Transformed WOFF2 glyphs, which are allowed by validateLoca.
+				continue
+			}
+			_, err = font.GlyfTable()
+		case TagHmtx:
+			_, err = font.HmtxTable()
+		default:
+			_, err = font.Table(tag)
+		}
+		if err != nil {
+			problems = append(problems, newProblem(SeverityError, tag, -1, fmt.Sprintf("the table could not be parsed: %s", err)))
+		}
+	}
+	return problems, nil
+}
+
+// validateHeaders checks the fields of the 'head', 'hhea' and 'maxp' tables
+// that the OpenType Sanitizer rejects fonts for.
+func validateHeaders(font *Font) ([]Problem, error) {
+	var problems []Problem
+	if font.HasTable(TagHead) {
+		head, err := font.HeadTable()
+		if err != nil {
+			return nil, err
+		}
+		if head.VersionNumber.Major != 1 {
+			problems = append(problems, newProblem(SeverityError, TagHead, -1, fmt.Sprintf("version %d.%d is not 1.0", head.VersionNumber.Major, head.VersionNumber.Minor)).at(0, 4).fixedBy(FixSetVersion))
+		}
+		if head.MagicNumber != headMagicNumber {
+			problems = append(problems, newProblem(SeverityError, TagHead, -1, fmt.Sprintf("magicNumber is 0x%08X, but should be 0x%08X", head.MagicNumber, headMagicNumber)).at(headMagicNumberOffset, 4).fixedBy(FixSetMagicNumber))
+		}
+		if head.UnitsPerEm < minUnitsPerEm || head.UnitsPerEm > maxUnitsPerEm {
+			problems = append(problems, newProblem(SeverityError, TagHead, -1, fmt.Sprintf("unitsPerEm %d is not between %d and %d", head.UnitsPerEm, minUnitsPerEm, maxUnitsPerEm)).at(headUnitsPerEmOffset, 2).fixedBy(FixScaleUnitsPerEm))
+		}
+		if head.IndexToLocFormat != 0 && head.IndexToLocFormat != 1 {
+			problems = append(problems, newProblem(SeverityError, TagHead, -1, fmt.Sprintf("indexToLocFormat %d is not 0 or 1", head.IndexToLocFormat)).at(headIndexToLocFormatOffset, 2).fixedBy(FixRebuildLoca))
+		}
+	}
+
+	if font.HasTable(TagHhea) {
+		hhea, err := font.HheaTable()
+		if err != nil {
+			return nil, err
+		}
+		if hhea.Version.Major != 1 {
+			problems = append(problems, newProblem(SeverityError, TagHhea, -1, fmt.Sprintf("version %d.%d is not 1.0", hhea.Version.Major, hhea.Version.Minor)).at(0, 4).fixedBy(FixSetVersion))
+		}
+	}
+
+	if font.HasTable(TagMaxp) {
+		maxp, err := font.MaxpTable()
+		if err != nil {
+			return nil, err
+		}
+		if maxp.NumGlyphs == 0 {
+			problems = append(problems, newProblem(SeverityError, TagMaxp, -1, "numGlyphs is 0").at(maxpNumGlyphsOffset, 2))
+		}
+	}
+	return problems, nil
+}
+
+// validateMetrics checks that the 'hmtx' table has a metric for every glyph, and
+// no data after them.
+func validateMetrics(font *Font) ([]Problem, error) {
+	if !font.HasTable(TagHhea) || !font.HasTable(TagMaxp) || !font.HasTable(TagHmtx) {
+		return nil, nil
+	}
+	hhea, err := font.HheaTable()
+	if err != nil {
+		return nil, err
+	}
+	maxp, err := font.MaxpTable()
+	if err != nil {
+		return nil, err
+	}
+	hmtx, err := font.TableData(TagHmtx)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := int(uint16(hhea.NumOfLongHorMetrics))
+	numGlyphs := int(maxp.NumGlyphs)
+	switch {
+	case metrics == 0:
+		return []Problem{newProblem(SeverityError, TagHhea, -1, "numberOfHMetrics is 0").at(hheaNumberOfHMetricsOffset, 2).fixedBy(FixSetNumberOfHMetrics)}, nil
+	case metrics > numGlyphs:
+		return []Problem{newProblem(SeverityError, TagHhea, -1, fmt.Sprintf("numberOfHMetrics is %d, but there are only %d glyphs", metrics, numGlyphs)).at(hheaNumberOfHMetricsOffset, 2).fixedBy(FixSetNumberOfHMetrics)}, nil
+	}
+	switch want := 4*metrics + 2*(numGlyphs-metrics); {
+	case len(hmtx) < want:
+		return []Problem{newProblem(SeverityError, TagHmtx, -1, fmt.Sprintf("the table is %d bytes, but %d are needed for %d glyphs", len(hmtx), want, numGlyphs)).at(0, len(hmtx)).fixedBy(FixAddMetrics)}, nil
+	case len(hmtx) > want:
+		return []Problem{newProblem(SeverityWarning, TagHmtx, -1, fmt.Sprintf("the table is %d bytes, but only %d are used for %d glyphs", len(hmtx), want, numGlyphs)).at(want, len(hmtx)-want)}, nil
+	}
+	return nil, nil
+}
+
+// validateLoca checks that the offsets in the 'loca' table are in order and
+// within the 'glyf' table, as otherwise the 'glyf' table cannot be parsed.
+func validateLoca(font *Font) ([]Problem, error) {
+	if !font.HasTable(TagLoca) || !font.HasTable(TagGlyf) || !font.HasTable(TagHead) || !font.HasTable(TagMaxp) {
+		return nil, nil
+	}
+	head, err := font.HeadTable()
+	if err != nil {
+		return nil, err
+	}
+	maxp, err := font.MaxpTable()
+	if err != nil {
+		return nil, err
+	}
+	loca, err := font.TableData(TagLoca)
+	if err != nil {
+		return nil, err
+	}
+	glyf, err := font.TableData(TagGlyf)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(loca) == 0 {
+		// WOFF2 fonts usually store a transformed 'glyf' table with an empty
+		// 'loca' table, which browsers reconstruct.
+		return nil, nil
+	}
+
+	// size is the number of bytes in each offset.
+	size := 2
+	var offsets []uint32
+	switch head.IndexToLocFormat {
+	case 0:
+		for i := 0; i+2 <= len(loca); i += 2 {
+			offsets = append(offsets, uint32(binary.BigEndian.Uint16(loca[i:]))*2)
+		}
+	case 1:
+		size = 4
+		for i := 0; i+4 <= len(loca); i += 4 {
+			offsets = append(offsets, binary.BigEndian.Uint32(loca[i:]))
+		}
+	default:
+		// Reported by validateHeaders.
+		return nil, nil
+	}
+
+	if len(offsets) < int(maxp.NumGlyphs)+1 {
+		return []Problem{newProblem(SeverityError, TagLoca, -1, fmt.Sprintf("the table has %d offsets, but %d are needed for %d glyphs", len(offsets), int(maxp.NumGlyphs)+1, maxp.NumGlyphs)).at(0, len(loca))}, nil
+	}
+	var problems []Problem
+	for i := 0; i < int(maxp.NumGlyphs); i++ {
+		start, end := offsets[i], offsets[i+1]
+		switch {
+		case start > end:
+			problems = append(problems, newProblem(SeverityError, TagLoca, i, fmt.Sprintf("the glyph ends at %d, before it starts at %d", end, start)).at(i*size, 2*size))
+		case end > uint32(len(glyf)):
+			problems = append(problems, newProblem(SeverityError, TagLoca, i, fmt.Sprintf("the glyph ends at %d, but the glyf table is %d bytes", end, len(glyf))).at(i*size, 2*size))
+		}
+	}
+	return problems, nil
+}
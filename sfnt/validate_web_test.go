@@ -0,0 +1,220 @@
+package sfnt
+
+import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestParseProfile(t *testing.T) {
+	for _, p := range []Profile{ProfileMinimal, ProfileWeb, ProfileDesktop, ProfileStrict} {
+		if parsed, err := ParseProfile(p.String()); err != nil || parsed != p {
+			t.Errorf("ParseProfile(%q) = %v, %v; want %v", p.String(), parsed, err, p)
+		}
+	}
+	if _, err := ParseProfile("chrome"); err == nil {
+		t.Errorf("ParseProfile(%q) err = nil, want an error", "chrome")
+	}
+}
+
+func TestValidateProfileWeb(t *testing.T) {
+	b := NewBuilder(1000)
+	b.Map('A', b.AddGlyph("A", 600, [][]GlyphPoint{square(0, 0, 500)}))
+	font, err := b.Font()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []Profile{ProfileMinimal, ProfileWeb} {
+		if problems, err := font.ValidateProfile(p); err != nil || len(problems) != 0 {
+			t.Errorf("ValidateProfile(%v) = %v, %v; want no problems", p, problems, err)
+		}
+	}
+
+	font.RemoveTable(TagPost)
+	head, err := font.HeadTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	head.UnitsPerEm = 8
+	hhea, err := font.HheaTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	hhea.NumOfLongHorMetrics = 5
+
+	// The minimal profile only checks that the core tables are present.
+	if problems, err := font.ValidateProfile(ProfileMinimal); err != nil || len(problems) != 0 {
+		t.Errorf("ValidateProfile(ProfileMinimal) = %v, %v; want no problems", problems, err)
+	}
+	problems, err := font.ValidateProfile(ProfileWeb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Problem{
+		newProblem(SeverityError, TagPost, -1, "the table is required in web fonts").fixedBy(FixAddTable),
+		newProblem(SeverityError, TagHead, -1, "unitsPerEm 8 is not between 16 and 16384").at(18, 2).fixedBy(FixScaleUnitsPerEm),
+		newProblem(SeverityError, TagHhea, -1, "numberOfHMetrics is 5, but there are only 2 glyphs").at(34, 2).fixedBy(FixSetNumberOfHMetrics),
+	}
+	if !reflect.DeepEqual(problems, want) {
+		t.Errorf("ValidateProfile(ProfileWeb) = %v, want %v", problems, want)
+	}
+
+	font.RemoveTable(TagCmap)
+	problems, err = font.ValidateProfile(ProfileMinimal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []Problem{newProblem(SeverityError, TagCmap, -1, "the table is required").fixedBy(FixAddTable)}
+	if !reflect.DeepEqual(problems, want) {
+		t.Errorf("ValidateProfile(ProfileMinimal) = %v, want %v", problems, want)
+	}
+}
+
+func TestValidateMetrics(t *testing.T) {
+	b := NewBuilder(1000)
+	b.Map('A', b.AddGlyph("A", 600, [][]GlyphPoint{square(0, 0, 500)}))
+	b.Map('B', b.AddGlyph("B", 600, [][]GlyphPoint{square(0, 0, 400)}))
+	font, err := b.Font()
+	if err != nil {
+		t.Fatal(err)
+	}
+	hmtx, err := font.TableData(TagHmtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hmtx) != 10 {
+		t.Fatalf("len(hmtx) = %d, want 2 long metrics and a side bearing", len(hmtx))
+	}
+
+	tests := []struct {
+		data []byte
+		want []Problem
+	}{
+		{hmtx, nil},
+		{hmtx[:8], []Problem{newProblem(SeverityError, TagHmtx, -1, "the table is 8 bytes, but 10 are needed for 3 glyphs").at(0, 8).fixedBy(FixAddMetrics)}},
+		{append(hmtx[:10:10], 0, 0), []Problem{newProblem(SeverityWarning, TagHmtx, -1, "the table is 12 bytes, but only 10 are used for 3 glyphs").at(10, 2)}},
+	}
+	for _, test := range tests {
+		font.SetTable(TagHmtx, test.data)
+		if problems, err := validateMetrics(font); err != nil || !reflect.DeepEqual(problems, test.want) {
+			t.Errorf("validateMetrics() with %d bytes = %v, %v; want %v", len(test.data), problems, err, test.want)
+		}
+		if _, err := font.HmtxTable(); (err != nil) != (len(test.data) < len(hmtx)) {
+			t.Errorf("HmtxTable() with %d bytes err = %v, want an error only if it is too short", len(test.data), err)
+		}
+	}
+}
+
+func TestValidateLoca(t *testing.T) {
+	font := parseTestFont(t, "Roboto-BoldItalic.ttf")
+	if problems, err := validateLoca(font); err != nil || len(problems) != 0 {
+		t.Errorf("validateLoca() = %v, %v; want no problems", problems, err)
+	}
+
+	loca, err := font.TableData(TagLoca)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loca = append([]byte(nil), loca...)
+	// Roboto has long offsets, so the end of glyph 1 is at bytes 8-12.
+	binary.BigEndian.PutUint32(loca[8:], 1<<20)
+	font.SetTable(TagLoca, loca)
+
+	problems, err := validateLoca(font)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Problem{
+		newProblem(SeverityError, TagLoca, 1, "the glyph ends at 1048576, but the glyf table is 214830 bytes").at(4, 8),
+		newProblem(SeverityError, TagLoca, 2, fmt.Sprintf("the glyph ends at %d, before it starts at 1048576", binary.BigEndian.Uint32(loca[12:]))).at(8, 8),
+	}
+	if !reflect.DeepEqual(problems, want) {
+		t.Errorf("validateLoca() = %v, want %v", problems, want)
+	}
+}
+
+func TestValidateLocaAfterGlyfTable(t *testing.T) {
+	// A font with long offsets whose glyphs would fit in short ones.
+	small := parseTestFont(t, "open-sans-v15-latin-regular.woff")
+	loca, err := small.TableData(TagLoca)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var long []byte
+	for i := 0; i+2 <= len(loca); i += 2 {
+		long = appendUint32s(long, 2*uint32(binary.BigEndian.Uint16(loca[i:])))
+	}
+	small.SetTable(TagLoca, long)
+	head, err := small.HeadTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	head.IndexToLocFormat = 1
+
+	for name, font := range map[string]*Font{"open-sans": small, "Roboto": parseTestFont(t, "Roboto-BoldItalic.ttf")} {
+		if _, err := font.GlyfTable(); err != nil {
+			t.Fatal(err)
+		}
+		if problems, err := validateLoca(font); err != nil || len(problems) != 0 {
+			t.Errorf("%s: validateLoca() after GlyfTable() = %v, %v; want no problems", name, problems, err)
+		}
+
+		gids, err := font.GlyphsForRunes([]rune("ABC"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := font.SubsetGlyphs(gids); err != nil {
+			t.Fatal(err)
+		}
+		if problems, err := validateLoca(font); err != nil || len(problems) != 0 {
+			t.Errorf("%s: validateLoca() after SubsetGlyphs() = %v, %v; want no problems", name, problems, err)
+		}
+	}
+}
+
+func TestValidateTables(t *testing.T) {
+	b := NewBuilder(1000)
+	b.Map('A', b.AddGlyph("A", 600, [][]GlyphPoint{square(0, 0, 500)}))
+	font, err := b.Font()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if problems, err := validateTables(font); err != nil || len(problems) != 0 {
+		t.Errorf("validateTables() = %v, %v; want no problems", problems, err)
+	}
+
+	font.SetTable(TagGsub, []byte{0, 2, 0, 0})
+	problems, err := font.ValidateProfile(ProfileWeb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Problem{newProblem(SeverityError, TagGsub, -1, "the table could not be parsed: unsupported layout version (major: 2, minor: 0)")}
+	if !reflect.DeepEqual(problems, want) {
+		t.Errorf("ValidateProfile(ProfileWeb) = %v, want %v", problems, want)
+	}
+}
+
+func TestValidateTablesJustificationAndGraphite(t *testing.T) {
+	b := NewBuilder(1000)
+	b.Map('A', b.AddGlyph("A", 600, [][]GlyphPoint{square(0, 0, 500)}))
+	font, err := b.Font()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tag := range []Tag{TagJstf, TagBase, TagSilf, TagGlat, TagGloc} {
+		font.SetTable(tag, []byte{0, 9})
+	}
+
+	problems, err := font.ValidateProfile(ProfileWeb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tags []Tag
+	for _, p := range problems {
+		tags = append(tags, p.Tag)
+	}
+	if want := []Tag{TagBase, TagGlat, TagGloc, TagJstf, TagSilf}; !reflect.DeepEqual(tags, want) {
+		t.Errorf("ValidateProfile(ProfileWeb) found problems in %v, want %v", tags, want)
+	}
+}
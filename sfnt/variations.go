@@ -0,0 +1,349 @@
+package sfnt
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// ItemVariationStore contains the deltas that variable fonts use to adjust values
+// in tables such as GDEF, GPOS, HVAR, MVAR, BASE, COLR and CFF2 for each position
+// in the design space. Each value is identified by an outer index, which selects
+// an ItemVariationData, and an inner index, which selects an item within it.
+//
+// See https://docs.microsoft.com/en-us/typography/opentype/spec/otvarcommonformats#item-variation-store
+type ItemVariationStore struct {
+	Regions [][]RegionAxis // Regions contains the extent of each region on each axis.
+	Data    []ItemVariationData
+}
+
+// RegionAxis is the extent of a region on one axis, in normalized coordinates.
+type RegionAxis struct {
+	Start, Peak, End float64
+}
+
+// ItemVariationData contains the deltas for a set of items.
+type ItemVariationData struct {
+	RegionIndices []uint16  // RegionIndices are the regions that the deltas of each item apply to.
+	Deltas        [][]int32 // Deltas contains the delta for each region, for each item.
+}
+
+// ParseItemVariationStore parses the item variation store at the start of b.
+func ParseItemVariationStore(b []byte) (*ItemVariationStore, error) {
+	if len(b) < 8 {
+		return nil, fmt.Errorf("reading item variation store: unexpected EOF")
+	}
+	if format := binary.BigEndian.Uint16(b); format != 1 {
+		return nil, fmt.Errorf("unsupported item variation store format %d", format)
+	}
+	regionsOffset := int(binary.BigEndian.Uint32(b[2:]))
+	dataCount := int(binary.BigEndian.Uint16(b[6:]))
+	if len(b) < 8+4*dataCount || len(b) < regionsOffset+4 {
+		return nil, fmt.Errorf("reading item variation store: unexpected EOF")
+	}
+
+	store := &ItemVariationStore{}
+	regions := b[regionsOffset:]
+	axisCount := int(binary.BigEndian.Uint16(regions))
+	regionCount := int(binary.BigEndian.Uint16(regions[2:]))
+	if len(regions) < 4+regionCount*axisCount*6 {
+		return nil, fmt.Errorf("reading %d variation regions: unexpected EOF", regionCount)
+	}
+	for i := 0; i < regionCount; i++ {
+		region := make([]RegionAxis, axisCount)
+		for j := range region {
+			r := regions[4+(i*axisCount+j)*6:]
+			region[j] = RegionAxis{
+				Start: f2dot14(int16(binary.BigEndian.Uint16(r))),
+				Peak:  f2dot14(int16(binary.BigEndian.Uint16(r[2:]))),
+				End:   f2dot14(int16(binary.BigEndian.Uint16(r[4:]))),
+			}
+		}
+		store.Regions = append(store.Regions, region)
+	}
+
+	for i := 0; i < dataCount; i++ {
+		data, err := parseItemVariationData(b, int(binary.BigEndian.Uint32(b[8+4*i:])), regionCount)
+		if err != nil {
+			return nil, fmt.Errorf("reading item variation data %d: %s", i, err)
+		}
+		store.Data = append(store.Data, data)
+	}
+	return store, nil
+}
+
+func parseItemVariationData(b []byte, offset int, regionCount int) (ItemVariationData, error) {
+	var data ItemVariationData
+	if len(b) < offset+6 {
+		return data, fmt.Errorf("unexpected EOF")
+	}
+	b = b[offset:]
+	itemCount := int(binary.BigEndian.Uint16(b))
+	wordCount := int(binary.BigEndian.Uint16(b[2:]) & 0x7FFF)
+	longWords := binary.BigEndian.Uint16(b[2:])&0x8000 != 0
+	regionIndexCount := int(binary.BigEndian.Uint16(b[4:]))
+	if wordCount > regionIndexCount {
+		return data, fmt.Errorf("%d word deltas for %d regions", wordCount, regionIndexCount)
+	}
+
+	// Word deltas are 16-bit and the others 8-bit, or 32-bit and 16-bit with long words.
+	wordSize, shortSize := 2, 1
+	if longWords {
+		wordSize, shortSize = 4, 2
+	}
+	rowSize := wordCount*wordSize + (regionIndexCount-wordCount)*shortSize
+	if len(b) < 6+2*regionIndexCount+itemCount*rowSize {
+		return data, fmt.Errorf("unexpected EOF")
+	}
+
+	data.RegionIndices = make([]uint16, regionIndexCount)
+	for i := range data.RegionIndices {
+		data.RegionIndices[i] = binary.BigEndian.Uint16(b[6+2*i:])
+		if int(data.RegionIndices[i]) >= regionCount {
+			return data, fmt.Errorf("invalid region index %d", data.RegionIndices[i])
+		}
+	}
+
+	row := b[6+2*regionIndexCount:]
+	data.Deltas = make([][]int32, itemCount)
+	for i := range data.Deltas {
+		deltas := make([]int32, regionIndexCount)
+		for j := range deltas {
+			size := shortSize
+			if j < wordCount {
+				size = wordSize
+			}
+			switch size {
+			case 4:
+				deltas[j] = int32(binary.BigEndian.Uint32(row))
+			case 2:
+				deltas[j] = int32(int16(binary.BigEndian.Uint16(row)))
+			default:
+				deltas[j] = int32(int8(row[0]))
+			}
+			row = row[size:]
+		}
+		data.Deltas[i] = deltas
+	}
+	return data, nil
+}
+
+// Bytes returns the byte representation of the item variation store. Within each
+// ItemVariationData, the regions with large deltas are moved before the others.
+func (s *ItemVariationStore) Bytes() []byte {
+	axisCount := 0
+	if len(s.Regions) > 0 {
+		axisCount = len(s.Regions[0])
+	}
+	regions := appendUint16s(nil, uint16(axisCount), uint16(len(s.Regions)))
+	for _, region := range s.Regions {
+		for _, axis := range region {
+			regions = appendUint16s(regions, uint16(toF2dot14(axis.Start)), uint16(toF2dot14(axis.Peak)), uint16(toF2dot14(axis.End)))
+		}
+	}
+
+	header := 8 + 4*len(s.Data)
+	buf := appendUint16s(nil, 1)
+	buf = appendUint32s(buf, uint32(header))
+	buf = appendUint16s(buf, uint16(len(s.Data)))
+	offset := header + len(regions)
+	var data []byte
+	for _, d := range s.Data {
+		buf = appendUint32s(buf, uint32(offset+len(data)))
+		data = append(data, d.bytes()...)
+	}
+	buf = append(buf, regions...)
+	return append(buf, data...)
+}
+
+func (d *ItemVariationData) bytes() []byte {
+	// Each region's deltas are stored as words if any item needs them. If any
+	// delta needs 32 bits, words are 32-bit and the other deltas 16-bit.
+	var longWords bool
+	words := make([]bool, len(d.RegionIndices))
+	for _, deltas := range d.Deltas {
+		for j, delta := range deltas {
+			if delta < -128 || delta > 127 {
+				words[j] = true
+			}
+			if delta < math.MinInt16 || delta > math.MaxInt16 {
+				longWords = true
+			}
+		}
+	}
+	if longWords {
+		for j := range words {
+			words[j] = false
+			for _, deltas := range d.Deltas {
+				if deltas[j] < math.MinInt16 || deltas[j] > math.MaxInt16 {
+					words[j] = true
+				}
+			}
+		}
+	}
+
+	var order []int
+	for _, word := range []bool{true, false} {
+		for j := range words {
+			if words[j] == word {
+				order = append(order, j)
+			}
+		}
+	}
+	wordCount := 0
+	for _, word := range words {
+		if word {
+			wordCount++
+		}
+	}
+	flags := uint16(wordCount)
+	if longWords {
+		flags |= 0x8000
+	}
+
+	buf := appendUint16s(nil, uint16(len(d.Deltas)), flags, uint16(len(d.RegionIndices)))
+	for _, j := range order {
+		buf = appendUint16s(buf, d.RegionIndices[j])
+	}
+	for _, deltas := range d.Deltas {
+		for _, j := range order {
+			switch {
+			case words[j] && longWords:
+				buf = appendUint32s(buf, uint32(deltas[j]))
+			case words[j] || longWords:
+				buf = appendUint16s(buf, uint16(deltas[j]))
+			default:
+				buf = append(buf, byte(int8(deltas[j])))
+			}
+		}
+	}
+	return buf
+}
+
+// Delta returns the adjustment for the item identified by outer and inner at the
+// given normalized coordinates, which are in the order of the axes in 'fvar'.
+// Axes without a coordinate are at their default position. It returns 0 if the
+// item does not exist.
+func (s *ItemVariationStore) Delta(outer, inner uint16, coords []float64) float64 {
+	if s == nil || int(outer) >= len(s.Data) || int(inner) >= len(s.Data[outer].Deltas) {
+		return 0
+	}
+	data := s.Data[outer]
+
+	var total float64
+	for i, d := range data.Deltas[inner] {
+		if d != 0 {
+			total += float64(d) * regionScalar(s.Regions[data.RegionIndices[i]], coords)
+		}
+	}
+	return total
+}
+
+// regionScalar returns how much a region applies at the given coordinates, from 0 to 1.
+func regionScalar(region []RegionAxis, coords []float64) float64 {
+	scalar := 1.0
+	for i, axis := range region {
+		if axis.Start > axis.Peak || axis.Peak > axis.End || (axis.Start < 0 && axis.End > 0) || axis.Peak == 0 {
+			continue
+		}
+		var coord float64
+		if i < len(coords) {
+			coord = coords[i]
+		}
+
+		switch {
+		case coord < axis.Start || coord > axis.End:
+			return 0
+		case coord == axis.Peak:
+		case coord < axis.Peak:
+			scalar *= (coord - axis.Start) / (axis.Peak - axis.Start)
+		default:
+			scalar *= (axis.End - coord) / (axis.End - axis.Peak)
+		}
+	}
+	return scalar
+}
+
+// deviceDelta returns the adjustment made by the device or variation index table
+// at the start of b. Device tables that adjust for specific sizes are ignored.
+func (s *ItemVariationStore) deviceDelta(b []byte, coords []float64) float64 {
+	if len(b) < 6 || binary.BigEndian.Uint16(b[4:]) != 0x8000 {
+		return 0
+	}
+	return s.Delta(binary.BigEndian.Uint16(b), binary.BigEndian.Uint16(b[2:]), coords)
+}
+
+// DeltaSetIndexMap maps the indices used by a table, such as glyph IDs in HVAR,
+// to the outer and inner indices of items in an ItemVariationStore.
+//
+// See https://docs.microsoft.com/en-us/typography/opentype/spec/otvarcommonformats#associating-target-items-to-variation-data
+type DeltaSetIndexMap struct {
+	Outer, Inner []uint16
+}
+
+// ParseDeltaSetIndexMap parses the delta-set index map at the start of b.
+func ParseDeltaSetIndexMap(b []byte) (*DeltaSetIndexMap, error) {
+	if len(b) < 4 {
+		return nil, fmt.Errorf("reading delta-set index map: unexpected EOF")
+	}
+	entryFormat := b[1]
+	var count, offset int
+	switch b[0] {
+	case 0:
+		count, offset = int(binary.BigEndian.Uint16(b[2:])), 4
+	case 1:
+		if len(b) < 6 {
+			return nil, fmt.Errorf("reading delta-set index map: unexpected EOF")
+		}
+		count, offset = int(binary.BigEndian.Uint32(b[2:])), 6
+	default:
+		return nil, fmt.Errorf("unsupported delta-set index map format %d", b[0])
+	}
+
+	size := int(entryFormat>>4&0x3) + 1
+	innerBits := uint(entryFormat&0xF) + 1
+	if len(b) < offset+count*size {
+		return nil, fmt.Errorf("reading %d delta-set index map entries: unexpected EOF", count)
+	}
+
+	m := &DeltaSetIndexMap{Outer: make([]uint16, count), Inner: make([]uint16, count)}
+	for i := 0; i < count; i++ {
+		var entry uint32
+		for _, c := range b[offset+i*size : offset+(i+1)*size] {
+			entry = entry<<8 | uint32(c)
+		}
+		m.Outer[i] = uint16(entry >> innerBits)
+		m.Inner[i] = uint16(entry & (1<<innerBits - 1))
+	}
+	return m, nil
+}
+
+// Map returns the outer and inner indices for index i. Indices past the end of
+// the map use the last entry. A nil map maps each index to the item with that
+// inner index in the first ItemVariationData.
+func (m *DeltaSetIndexMap) Map(i int) (outer, inner uint16) {
+	if m == nil {
+		return 0, uint16(i)
+	}
+	if len(m.Outer) == 0 {
+		return 0, 0
+	}
+	if i >= len(m.Outer) {
+		i = len(m.Outer) - 1
+	}
+	return m.Outer[i], m.Inner[i]
+}
+
+// gdefVariationStore returns the item variation store in a GDEF table, or nil if
+// it does not have one.
+func gdefVariationStore(gdef []byte) (*ItemVariationStore, error) {
+	// The store was added to GDEF in version 1.3.
+	if len(gdef) < 18 || binary.BigEndian.Uint16(gdef) != 1 || binary.BigEndian.Uint16(gdef[2:]) < 3 {
+		return nil, nil
+	}
+	offset := binary.BigEndian.Uint32(gdef[14:])
+	if offset == 0 || uint64(offset) >= uint64(len(gdef)) {
+		return nil, nil
+	}
+	return ParseItemVariationStore(gdef[offset:])
+}
@@ -0,0 +1,93 @@
+package sfnt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestItemVariationStore(t *testing.T) {
+	store, err := ParseItemVariationStore(writeBigEndian(t,
+		uint16(1), uint32(12), uint16(1), uint32(40), // format, regions, count, data
+		uint16(2), uint16(2), // two axes and two regions
+		uint16(0), uint16(0x4000), uint16(0x4000), uint16(0), uint16(0), uint16(0), // 0 to 1 on the first axis
+		uint16(0), uint16(0), uint16(0), uint16(0xC000), uint16(0xC000), uint16(0), // -1 to 0 on the second axis
+		uint16(2), uint16(1), uint16(2), uint16(0), uint16(1), // two items, one word delta, two regions
+		int16(400), int8(-20),
+		int16(-2), int8(0),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &ItemVariationStore{
+		Regions: [][]RegionAxis{{{0, 1, 1}, {0, 0, 0}}, {{0, 0, 0}, {-1, -1, 0}}},
+		Data:    []ItemVariationData{{RegionIndices: []uint16{0, 1}, Deltas: [][]int32{{400, -20}, {-2, 0}}}},
+	}
+	if !reflect.DeepEqual(store, want) {
+		t.Errorf("ParseItemVariationStore() = %+v, want %+v", store, want)
+	}
+	if got, err := ParseItemVariationStore(want.Bytes()); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseItemVariationStore(Bytes()) = %+v, %v, want %+v", got, err, want)
+	}
+
+	for _, test := range []struct {
+		inner  uint16
+		coords []float64
+		want   float64
+	}{
+		{0, nil, 0},
+		{0, []float64{1}, 400},
+		{0, []float64{0.5, -0.5}, 190},
+		{0, []float64{-0.5, -1}, -20},
+		{1, []float64{0.5}, -1},
+		{2, []float64{1}, 0},
+	} {
+		if got := store.Delta(0, test.inner, test.coords); got != test.want {
+			t.Errorf("Delta(0, %d, %v) = %v, want %v", test.inner, test.coords, got, test.want)
+		}
+	}
+}
+
+func TestItemVariationStoreBytes(t *testing.T) {
+	// Word deltas are moved first, and are 32-bit if any delta needs it.
+	store := &ItemVariationStore{
+		Regions: [][]RegionAxis{{{0, 1, 1}}, {{-1, -1, 0}}, {{0, 0.5, 1}}},
+		Data: []ItemVariationData{
+			{RegionIndices: []uint16{0, 1}, Deltas: [][]int32{{1, 200}, {-3, 4}}},
+			{RegionIndices: []uint16{0, 1, 2}, Deltas: [][]int32{{1, 70000, -300}}},
+		},
+	}
+	got, err := ParseItemVariationStore(store.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, coords := range [][]float64{{1}, {-1}, {-0.25}, {0.5}, {0.75}} {
+		for outer, data := range store.Data {
+			for inner := range data.Deltas {
+				if a, b := got.Delta(uint16(outer), uint16(inner), coords), store.Delta(uint16(outer), uint16(inner), coords); a != b {
+					t.Errorf("Delta(%d, %d, %v) = %v, want %v", outer, inner, coords, a, b)
+				}
+			}
+		}
+	}
+}
+
+func TestDeltaSetIndexMap(t *testing.T) {
+	// Entries are 2 bytes, with 4 bits for the inner index.
+	m, err := ParseDeltaSetIndexMap(writeBigEndian(t, uint8(0), uint8(0x13), uint16(3), uint16(0x0005), uint16(0x0012), uint16(0x0020)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		i            int
+		outer, inner uint16
+	}{{0, 0, 5}, {1, 1, 2}, {2, 2, 0}, {10, 2, 0}} {
+		if outer, inner := m.Map(test.i); outer != test.outer || inner != test.inner {
+			t.Errorf("Map(%d) = %d, %d, want %d, %d", test.i, outer, inner, test.outer, test.inner)
+		}
+	}
+
+	var implicit *DeltaSetIndexMap
+	if outer, inner := implicit.Map(7); outer != 0 || inner != 7 {
+		t.Errorf("nil Map(7) = %d, %d, want 0, 7", outer, inner)
+	}
+}
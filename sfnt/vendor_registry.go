@@ -0,0 +1,51 @@
+package sfnt
+
+// VendorInfo describes a font vendor, as identified by the achVendID field of
+// the 'OS/2' table.
+// https://docs.microsoft.com/en-us/typography/vendors/
+type VendorInfo struct {
+	Tag  Tag
+	Name string // Name is the name of the foundry, for example "Adobe".
+}
+
+var vendorRegistry = []VendorInfo{
+	{MustNamedTag("ADBE"), "Adobe"},
+	{MustNamedTag("APPL"), "Apple"},
+	{MustNamedTag("B&H "), "Bigelow & Holmes"},
+	{MustNamedTag("BITS"), "Bitstream"},
+	{MustNamedTag("DAMA"), "Dalton Maag"},
+	{MustNamedTag("FBI "), "The Font Bureau"},
+	{MustNamedTag("FSI "), "FontShop International"},
+	{MustNamedTag("GOOG"), "Google"},
+	{MustNamedTag("H&FJ"), "Hoefler & Frere-Jones"},
+	{MustNamedTag("IBM "), "IBM"},
+	{MustNamedTag("ITC "), "International Typeface Corporation"},
+	{MustNamedTag("LINO"), "Linotype"},
+	{MustNamedTag("MONO"), "Monotype"},
+	{MustNamedTag("MS  "), "Microsoft"},
+	{MustNamedTag("P22 "), "P22 Type Foundry"},
+	{MustNamedTag("PARA"), "ParaType"},
+	{MustNamedTag("TPTQ"), "Typotheque"},
+	{MustNamedTag("URW "), "URW++"},
+	// The defaults of font editors, which do not identify a vendor.
+	{MustNamedTag("NONE"), "No vendor"},
+	{MustNamedTag("PfEd"), "FontForge (no vendor)"},
+	{MustNamedTag("UKWN"), "Unknown vendor"},
+}
+
+// LookupVendor returns the description of the vendor with the given achVendID.
+// If the vendor is not known, it returns false and a description named after
+// the tag.
+func LookupVendor(tag Tag) (VendorInfo, bool) {
+	for _, info := range vendorRegistry {
+		if info.Tag == tag {
+			return info, true
+		}
+	}
+	return VendorInfo{Tag: tag, Name: tag.String()}, false
+}
+
+// KnownVendors returns the description of every known vendor.
+func KnownVendors() []VendorInfo {
+	return append([]VendorInfo{}, vendorRegistry...)
+}
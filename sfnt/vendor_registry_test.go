@@ -0,0 +1,49 @@
+package sfnt
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestLookupVendor(t *testing.T) {
+	info, found := LookupVendor(MustNamedTag("ADBE"))
+	if !found || info.Name != "Adobe" {
+		t.Errorf("LookupVendor(ADBE) = %+v, %v, want Adobe", info, found)
+	}
+	info, found = LookupVendor(MustNamedTag("MS  "))
+	if !found || info.Name != "Microsoft" {
+		t.Errorf("LookupVendor(MS) = %+v, %v, want Microsoft", info, found)
+	}
+	info, found = LookupVendor(MustNamedTag("ZZZZ"))
+	if found || info.Name != "ZZZZ" {
+		t.Errorf("LookupVendor(ZZZZ) = %+v, %v, want ZZZZ, false", info, found)
+	}
+}
+
+func TestWriteVendorID(t *testing.T) {
+	font := parseTestFont(t, "Roboto-BoldItalic.ttf")
+	os2, err := font.OS2Table()
+	if err != nil {
+		t.Fatal(err)
+	}
+	original := os2.AchVendID
+
+	var buf bytes.Buffer
+	if _, err := font.WriteOTFWithOptions(&buf, WriteOptions{VendorID: MustNamedTag("ACME")}); err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := Parse(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	written, err := parsed.OS2Table()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if written.AchVendID != MustNamedTag("ACME") {
+		t.Errorf("written achVendID = %q, want ACME", written.AchVendID)
+	}
+	if os2.AchVendID != original {
+		t.Errorf("achVendID = %q after writing, want %q unchanged", os2.AchVendID, original)
+	}
+}
@@ -0,0 +1,190 @@
+package sfnt
+
+import "fmt"
+
+// fsSelectionUseTypoMetrics tells applications to use the typographic metrics
+// in 'OS/2' for line spacing, instead of the win metrics.
+const fsSelectionUseTypoMetrics = 0x0080
+
+// MetricChange is a change to a field in one of the font's tables.
+type MetricChange struct {
+	Table    Tag
+	Field    string
+	Old, New int
+}
+
+// FixVerticalMetrics makes the vertical metrics of the font consistent, so that
+// lines are spaced the same on every platform and browser:
+//
+//   - usWinAscent and usWinDescent are grown to cover the font's bounding box,
+//     as Windows clips glyphs outside them.
+//   - sTypoAscender and sTypoDescender are set to the win metrics, sTypoLineGap
+//     is set to 0, and USE_TYPO_METRICS is set in fsSelection (upgrading 'OS/2'
+//     to version 4 if needed).
+//   - The ascent, descent and line gap in 'hhea' are set to the same values.
+//
+// It returns the changes made. If dryRun is true, the changes are returned
+// without updating the font.
+func (font *Font) FixVerticalMetrics(dryRun bool) ([]MetricChange, error) {
+	head, err := font.HeadTable()
+	if err != nil {
+		return nil, err
+	}
+	hhea, err := font.HheaTable()
+	if err != nil {
+		return nil, err
+	}
+	os2, err := font.OS2Table()
+	if err != nil {
+		return nil, err
+	}
+
+	ascent, descent := int(os2.UsWinAscent), int(os2.UsWinDescent)
+	if int(head.YMax) > ascent {
+		ascent = int(head.YMax)
+	}
+	if -int(head.YMin) > descent {
+		descent = -int(head.YMin)
+	}
+
+	var changes []MetricChange
+	change := func(table Tag, field string, old, new int) {
+		if old != new {
+			changes = append(changes, MetricChange{table, field, old, new})
+		}
+	}
+	version := os2.Version
+	if version < 4 {
+		version = 4
+	}
+	change(TagOS2, "version", int(os2.Version), int(version))
+	change(TagOS2, "usWinAscent", int(os2.UsWinAscent), ascent)
+	change(TagOS2, "usWinDescent", int(os2.UsWinDescent), descent)
+	change(TagOS2, "sTypoAscender", int(os2.STypoAscender), ascent)
+	change(TagOS2, "sTypoDescender", int(os2.STypoDescender), -descent)
+	change(TagOS2, "sTypoLineGap", int(os2.STypoLineGap), 0)
+	change(TagOS2, "fsSelection", int(os2.FsSelection), int(os2.FsSelection|fsSelectionUseTypoMetrics))
+	change(TagHhea, "ascender", int(hhea.Ascent), ascent)
+	change(TagHhea, "descender", int(hhea.Descent), -descent)
+	change(TagHhea, "lineGap", int(hhea.LineGap), 0)
+	if dryRun || len(changes) == 0 {
+		return changes, nil
+	}
+
+	if err := os2.UpgradeToVersion(version); err != nil {
+		return nil, err
+	}
+	os2.UsWinAscent, os2.UsWinDescent = uint16(ascent), uint16(descent)
+	os2.STypoAscender, os2.STypoDescender, os2.STypoLineGap = int16(ascent), int16(-descent), 0
+	os2.FsSelection |= fsSelectionUseTypoMetrics
+	font.AddTable(TagOS2, os2)
+
+	hhea.Ascent, hhea.Descent, hhea.LineGap = int16(ascent), int16(-descent), 0
+	font.AddTable(TagHhea, hhea)
+	return changes, nil
+}
+
+// DecorationMetrics are the suggested positions and thicknesses of underlines
+// and strikeouts, as a fraction of the font size. Positions are the top of the
+// line, and are positive above the baseline.
+type DecorationMetrics struct {
+	UnderlinePosition  float64
+	UnderlineThickness float64
+	StrikeoutPosition  float64
+	StrikeoutThickness float64
+}
+
+// DecorationMetrics returns the underline metrics from 'post' and the strikeout
+// metrics from 'OS/2' in em units. If a table is missing its metrics are 0, and
+// if the font does not set the strikeout thickness the underline thickness is
+// used.
+func (font *Font) DecorationMetrics() (DecorationMetrics, error) {
+	var metrics DecorationMetrics
+	head, err := font.HeadTable()
+	if err != nil {
+		return metrics, err
+	}
+	if head.UnitsPerEm == 0 {
+		return metrics, fmt.Errorf("head: unitsPerEm is 0")
+	}
+	em := float64(head.UnitsPerEm)
+
+	if font.HasTable(TagPost) {
+		post, err := font.PostTable()
+		if err != nil {
+			return metrics, err
+		}
+		metrics.UnderlinePosition = float64(post.UnderlinePosition) / em
+		metrics.UnderlineThickness = float64(post.UnderlineThickness) / em
+	}
+	if font.HasTable(TagOS2) {
+		os2, err := font.OS2Table()
+		if err != nil {
+			return metrics, err
+		}
+		metrics.StrikeoutPosition = float64(os2.YStrikeoutPosition) / em
+		metrics.StrikeoutThickness = float64(os2.YStrikeoutSize) / em
+	}
+	if metrics.StrikeoutThickness == 0 {
+		metrics.StrikeoutThickness = metrics.UnderlineThickness
+	}
+	return metrics, nil
+}
+
+// XHeight returns the height of lowercase letters in font units. It is read
+// from sxHeight in 'OS/2', or measured from the outline of 'x' if the table is
+// older than version 2 or sets it to 0.
+func (font *Font) XHeight() (int, error) {
+	return font.letterHeight('x', func(os2 *TableOS2) int16 { return os2.SxHeigh })
+}
+
+// CapHeight returns the height of uppercase letters in font units. It is read
+// from sCapHeight in 'OS/2', or measured from the outline of 'H' if the table is
+// older than version 2 or sets it to 0.
+func (font *Font) CapHeight() (int, error) {
+	return font.letterHeight('H', func(os2 *TableOS2) int16 { return os2.SCapHeight })
+}
+
+// letterHeight returns the OS/2 field read by field, or the top of the outline
+// of the glyph that r is mapped to.
+func (font *Font) letterHeight(r rune, field func(os2 *TableOS2) int16) (int, error) {
+	if font.HasTable(TagOS2) {
+		os2, err := font.OS2Table()
+		if err != nil {
+			return 0, err
+		}
+		if v := field(os2); os2.Version >= 2 && v != 0 {
+			return int(v), nil
+		}
+	}
+
+	cmap, err := font.CmapTable()
+	if err != nil {
+		return 0, err
+	}
+	gid, found := cmap.Lookup(r)
+	if !found {
+		return 0, fmt.Errorf("cannot measure %q: the font does not support it", r)
+	}
+	glyf, err := font.GlyfTable()
+	if err != nil {
+		return 0, fmt.Errorf("cannot measure %q: %s", r, err)
+	}
+	contours, err := glyf.Contours(gid)
+	if err != nil {
+		return 0, err
+	}
+	height, empty := 0, true
+	for _, contour := range contours {
+		for _, p := range contour {
+			if empty || int(p.Y) > height {
+				height = int(p.Y)
+			}
+			empty = false
+		}
+	}
+	if empty {
+		return 0, fmt.Errorf("cannot measure %q: the glyph is empty", r)
+	}
+	return height, nil
+}
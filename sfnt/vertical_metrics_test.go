@@ -0,0 +1,125 @@
+package sfnt
+
+import "testing"
+
+func TestFixVerticalMetrics(t *testing.T) {
+	font := parseTestFont(t, "Roboto-BoldItalic.ttf")
+	head, err := font.HeadTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := font.FixVerticalMetrics(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) == 0 {
+		t.Fatalf("FixVerticalMetrics(true) returned no changes")
+	}
+	hhea, err := font.HheaTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hhea.Ascent != 1900 {
+		t.Errorf("dry run changed hhea ascent to %d", hhea.Ascent)
+	}
+
+	if _, err := font.FixVerticalMetrics(false); err != nil {
+		t.Fatal(err)
+	}
+	os2, err := font.OS2Table()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ascent, descent := int16(os2.UsWinAscent), -int16(os2.UsWinDescent)
+	if ascent < head.YMax || descent > head.YMin {
+		t.Errorf("win metrics %d, %d do not cover the bounding box %d, %d", ascent, descent, head.YMax, head.YMin)
+	}
+	if os2.STypoAscender != ascent || os2.STypoDescender != descent || os2.STypoLineGap != 0 {
+		t.Errorf("typo metrics = %d, %d, %d, want %d, %d, 0", os2.STypoAscender, os2.STypoDescender, os2.STypoLineGap, ascent, descent)
+	}
+	if hhea.Ascent != ascent || hhea.Descent != descent || hhea.LineGap != 0 {
+		t.Errorf("hhea metrics = %d, %d, %d, want %d, %d, 0", hhea.Ascent, hhea.Descent, hhea.LineGap, ascent, descent)
+	}
+	if os2.FsSelection&fsSelectionUseTypoMetrics == 0 {
+		t.Errorf("USE_TYPO_METRICS is not set")
+	}
+
+	if changes, err := font.FixVerticalMetrics(false); err != nil || len(changes) != 0 {
+		t.Errorf("second FixVerticalMetrics(false) = %v, %v, want no changes", changes, err)
+	}
+}
+
+func TestDecorationMetrics(t *testing.T) {
+	b := NewBuilder(1000)
+	font, err := b.Font()
+	if err != nil {
+		t.Fatal(err)
+	}
+	post, err := font.PostTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	post.UnderlinePosition = -100
+	os2, err := font.OS2Table()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os2.YStrikeoutPosition = 300
+
+	got, err := font.DecorationMetrics()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := DecorationMetrics{UnderlinePosition: -0.1, UnderlineThickness: 0.05, StrikeoutPosition: 0.3, StrikeoutThickness: 0.05}
+	if got != want {
+		t.Errorf("DecorationMetrics() = %+v, want %+v", got, want)
+	}
+
+	font.RemoveTable(TagOS2)
+	if got, err = font.DecorationMetrics(); err != nil {
+		t.Fatal(err)
+	}
+	if got.StrikeoutPosition != 0 || got.StrikeoutThickness != 0.05 {
+		t.Errorf("DecorationMetrics() without OS/2 = %+v, want the underline thickness", got)
+	}
+}
+
+func TestLetterHeights(t *testing.T) {
+	b := NewBuilder(1000)
+	box := func(height int16) [][]GlyphPoint {
+		return [][]GlyphPoint{{{0, 0, true}, {0, height, true}, {400, height, true}, {400, 0, true}}}
+	}
+	b.Map('x', b.AddGlyph("x", 500, box(480)))
+	b.Map('H', b.AddGlyph("H", 600, box(700)))
+	font, err := b.Font()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The builder leaves sxHeight and sCapHeight as 0, so they are measured.
+	if got, err := font.XHeight(); err != nil || got != 480 {
+		t.Errorf("XHeight() = %d, %v, want 480", got, err)
+	}
+	if got, err := font.CapHeight(); err != nil || got != 700 {
+		t.Errorf("CapHeight() = %d, %v, want 700", got, err)
+	}
+
+	os2, err := font.OS2Table()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os2.SxHeigh, os2.SCapHeight = 500, 720
+	if got, err := font.XHeight(); err != nil || got != 500 {
+		t.Errorf("XHeight() = %d, %v, want 500 from OS/2", got, err)
+	}
+	if got, err := font.CapHeight(); err != nil || got != 720 {
+		t.Errorf("CapHeight() = %d, %v, want 720 from OS/2", got, err)
+	}
+
+	// Version 1 tables do not have the fields.
+	os2.Version = 1
+	if got, err := font.CapHeight(); err != nil || got != 700 {
+		t.Errorf("CapHeight() = %d, %v, want 700 for OS/2 version 1", got, err)
+	}
+}
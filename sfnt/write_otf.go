@@ -2,18 +2,94 @@ package sfnt
 
 import (
 	"encoding/binary"
+	"fmt"
 	"io"
+	"math"
+	"os"
 	"sort"
+	"strconv"
 )
 
-// these headers always seem to come first in the serialized output.
-var outputOrder = map[Tag]int{
-	TagMaxp: 0,
-	TagHead: 1,
-	TagHmtx: 2,
-	TagHhea: 3,
-	TagOS2:  4,
-	TagName: 5,
+// recommendedOrderTrueType is the order in which tables should be stored in a
+// font with TrueType outlines, and recommendedOrderCFF in a font with CFF outlines.
+// Tables that are not listed are stored afterwards, sorted by tag.
+// See https://docs.microsoft.com/en-us/typography/opentype/spec/recom#optimized-table-ordering
+var recommendedOrderTrueType = []string{
+	"head", "hhea", "maxp", "OS/2", "hmtx", "LTSH", "VDMX", "hdmx", "cmap",
+	"fpgm", "prep", "cvt ", "loca", "glyf", "kern", "name", "post", "gasp", "PCLT", "DSIG",
+}
+
+var recommendedOrderCFF = []string{
+	"head", "hhea", "maxp", "OS/2", "name", "cmap", "post", "CFF ", "CFF2",
+}
+
+// storageOrder returns the tags of the font in the order the tables should be stored.
+func (font *Font) storageOrder() []Tag {
+	recommended := recommendedOrderTrueType
+	if font.HasTable(MustNamedTag("CFF ")) || font.HasTable(MustNamedTag("CFF2")) {
+		recommended = recommendedOrderCFF
+	}
+
+	score := make(map[Tag]int, len(recommended))
+	for i, name := range recommended {
+		score[MustNamedTag(name)] = i
+	}
+
+	tags := font.Tags()
+	sort.SliceStable(tags, func(i, j int) bool {
+		iScore, iOk := score[tags[i]]
+		jScore, jOk := score[tags[j]]
+		if iOk && jOk {
+			return iScore < jScore
+		}
+		return iOk && !jOk
+	})
+
+	return tags
+}
+
+// secondsFrom1904To1970 is the difference between the epoch used by
+// the 'head' table, and the unix epoch.
+const secondsFrom1904To1970 = 2082844800
+
+// WriteOptions controls how a font is serialized.
+type WriteOptions struct {
+	// Reproducible ensures that identical fonts are written out byte-for-byte
+	// identically. The modified date in the 'head' table is set from the
+	// SOURCE_DATE_EPOCH environment variable, or to zero if it is not set.
+	// (Tables are always stored in the recommended order, so nothing else varies.)
+	// See https://reproducible-builds.org/specs/source-date-epoch/
+	Reproducible bool
+
+	// DropGlyphNames writes the 'post' table as version 3.0, which contains
+	// no glyph names. This saves space in fonts that are only used on the web.
+	DropGlyphNames bool
+
+	// RoundToGrid rounds the glyph coordinates, component offsets, advance
+	// widths and left side bearings in 'glyf' and 'hmtx' to multiples of this
+	// many font units, as may be wanted after scaling or interpolating a font.
+	// Halves are rounded up, so that a shape rounds the same wherever it is.
+	// Zero (or one) leaves the values unchanged, as they are already integers.
+	RoundToGrid int
+
+	// SanitizeNames writes the 'name' table without duplicate entries, with
+	// control characters (other than tabs and line feeds) removed from the
+	// values, and with the entries sorted by platform, encoding, language and
+	// name id, as the specification requires. The font is not modified.
+	SanitizeNames bool
+
+	// DropNameLanguages also drops the entries of the 'name' table that are
+	// not in English or one of KeepNameLanguages, when SanitizeNames is set.
+	// Entries for the Unicode platform, which has no languages, are kept.
+	DropNameLanguages bool
+	// KeepNameLanguages are the Windows or Macintosh language ids of the
+	// entries kept by DropNameLanguages, other than English.
+	KeepNameLanguages []PlatformLanguageID
+
+	// VendorID sets the achVendID field of the 'OS/2' table, which identifies
+	// the vendor of the font (see LookupVendor). The zero Tag leaves it
+	// unchanged. The font is not modified.
+	VendorID Tag
 }
 
 // WriteOTF serializes a Font into OpenType format suitable
@@ -21,59 +97,58 @@ var outputOrder = map[Tag]int{
 // You can also use this to write to files called *.ttf if the
 // font contains TrueType glyphs.
 func (font *Font) WriteOTF(w io.Writer) (n int, err error) {
+	return font.WriteOTFWithOptions(w, WriteOptions{})
+}
 
-	todo := font.Tags()
-	sort.Slice(todo, func(i, j int) bool {
-		iScore, ok := outputOrder[todo[i]]
-		if !ok {
-			iScore = int(todo[i].Number)
-		}
-		jScore, ok := outputOrder[todo[j]]
-		if !ok {
-			jScore = int(todo[j].Number)
-		}
+// WriteOTFWithOptions serializes a Font into OpenType format, like WriteOTF,
+// using the given options.
+func (font *Font) WriteOTFWithOptions(w io.Writer, options WriteOptions) (n int, err error) {
 
-		return iScore < jScore
-	})
+	// The table directory must be sorted by tag, but the tables themselves
+	// are stored in the recommended order.
+	tags := font.Tags()
+	order := font.storageOrder()
 
-	headTable, err := font.HeadTable()
+	ow, err := font.newOTFWriter(options)
 	if err != nil {
 		return n, err
 	}
+	headTable := ow.head
 
-	headTable.ClearExpectedChecksum()
-
-	header := newOTFHeader(font.scalerType, uint16(len(todo)))
+	header := newOTFHeader(font.scalerType, uint16(len(tags)))
 
-	fragments := make([][]byte, len(todo))
+	fragments := make(map[Tag][]byte, len(tags))
+	offsets := make(map[Tag]int, len(tags))
 
-	offset := otfHeaderLength + directoryEntryLength*len(todo)
+	offset := otfHeaderLength + directoryEntryLength*len(tags)
 	checksum := header.checkSum()
 
+	for _, tag := range order {
+		if fragments[tag], err = ow.tableBytes(tag); err != nil {
+			return n, err
+		}
+		offsets[tag] = offset
+
+		offset += len(fragments[tag])
+		if len(fragments[tag])%4 != 0 {
+			offset += 4 - (len(fragments[tag]) % 4)
+		}
+	}
+
 	err = binary.Write(w, binary.BigEndian, header)
 	if err != nil {
 		return n, err
 	}
 	n += otfHeaderLength
 
-	for i, tag := range todo {
-		t, err := font.Table(tag)
-		if err != nil {
-			return n, err
-		}
-		fragments[i] = t.Bytes()
+	for _, tag := range tags {
 		entry := directoryEntry{
 			Tag:      tag,
-			CheckSum: checkSum(fragments[i]),
-			Offset:   uint32(offset),
-			Length:   uint32(len(fragments[i])),
+			CheckSum: checkSum(fragments[tag]),
+			Offset:   uint32(offsets[tag]),
+			Length:   uint32(len(fragments[tag])),
 		}
 
-		offset += len(fragments[i])
-		if len(fragments[i])%4 != 0 {
-			offset += 4 - (len(fragments[i]) % 4)
-
-		}
 		checksum += entry.CheckSum + entry.checkSum()
 
 		err = binary.Write(w, binary.BigEndian, entry)
@@ -83,16 +158,15 @@ func (font *Font) WriteOTF(w io.Writer) (n int, err error) {
 		n += directoryEntryLength
 	}
 
-	for i, tag := range todo {
+	for _, tag := range order {
 
 		var fragment []byte
 
-		if tag == TagHead {
+		if tag == ow.headTag {
 			headTable.SetExpectedChecksum(checksum)
 			fragment = headTable.Bytes()
-			headTable.SetExpectedChecksum(0)
 		} else {
-			fragment = fragments[i]
+			fragment = fragments[tag]
 		}
 
 		m, err := w.Write(fragment)
@@ -122,7 +196,117 @@ func (font *Font) WriteOTF(w io.Writer) (n int, err error) {
 
 	}
 
-	return 0, nil
+	return n, nil
+}
+
+// otfWriter holds the tables that are changed as a font is written: a copy of
+// the 'head' table, and the tables with recomputed or rounded values.
+type otfWriter struct {
+	font     *Font
+	options  WriteOptions
+	head     *TableHead
+	headTag  Tag // headTag is 'head', or 'bhed' in fonts that only have bitmaps.
+	replaced map[Tag]Table
+}
+
+// newOTFWriter prepares the tables that are changed as the font is written with
+// the given options.
+func (font *Font) newOTFWriter(options WriteOptions) (*otfWriter, error) {
+	original, err := font.HeadTable()
+	if err != nil {
+		return nil, err
+	}
+
+	// Work on a copy of the 'head' table, so that writing a font does not modify it.
+	head := *original
+	headTable := &head
+	headTable.ClearExpectedChecksum()
+
+	// The format of the 'loca' table depends on the size of the glyphs, which
+	// may have changed since the font was read. Unless the glyphs have been
+	// parsed, the original 'loca' table is written, so its format is kept.
+	// Copies of the tables are written, so the font keeps its format.
+	replaced := map[Tag]Table{}
+	if glyf, ok := font.parsedTable(TagGlyf).(*TableGlyf); ok {
+		headTable.IndexToLocFormat = glyf.IndexToLocFormat()
+		replaced[TagGlyf], replaced[TagLoca] = glyf.withLocaFormat(headTable.IndexToLocFormat)
+	}
+
+	if options.Reproducible {
+		updated, err := sourceDateEpoch()
+		if err != nil {
+			return nil, err
+		}
+		headTable.Updated = updated
+	}
+
+	if options.RoundToGrid > 1 {
+		if replaced, err = font.griddedTables(options.RoundToGrid, headTable); err != nil {
+			return nil, err
+		}
+		if glyf, ok := replaced[TagGlyf].(*TableGlyf); ok {
+			headTable.IndexToLocFormat = glyf.IndexToLocFormat()
+			replaced[TagGlyf], replaced[TagLoca] = glyf.withLocaFormat(headTable.IndexToLocFormat)
+		}
+	}
+	recomputed, err := font.recomputedMetrics(replaced)
+	if err != nil {
+		return nil, err
+	}
+	for tag, t := range recomputed {
+		replaced[tag] = t
+	}
+	if options.SanitizeNames && font.HasTable(TagName) {
+		name, err := font.NameTable()
+		if err != nil {
+			return nil, err
+		}
+		replaced[TagName] = name.sanitizedNames(options.DropNameLanguages, options.KeepNameLanguages)
+	}
+	if options.VendorID != (Tag{}) && font.HasTable(TagOS2) {
+		os2, err := font.OS2Table()
+		if err != nil {
+			return nil, err
+		}
+		vendor := *os2
+		vendor.AchVendID = options.VendorID
+		replaced[TagOS2] = &vendor
+	}
+	return &otfWriter{font: font, options: options, head: headTable, headTag: font.headTag(), replaced: replaced}, nil
+}
+
+// tableBytes returns the data to write for the table with the given tag.
+func (ow *otfWriter) tableBytes(tag Tag) ([]byte, error) {
+	if tag == ow.headTag {
+		return ow.head.Bytes(), nil
+	}
+	if t, found := ow.replaced[tag]; found {
+		return t.Bytes(), nil
+	}
+	t, err := ow.font.Table(tag)
+	if err != nil {
+		return nil, err
+	}
+	if post, ok := t.(*TablePost); ok && ow.options.DropGlyphNames {
+		return post.bytesVersion3(), nil
+	}
+	return t.Bytes(), nil
+}
+
+// sourceDateEpoch returns the time set in the SOURCE_DATE_EPOCH environment variable,
+// or zero if it is not set.
+func sourceDateEpoch() (longdatetime, error) {
+	epoch := os.Getenv("SOURCE_DATE_EPOCH")
+	if epoch == "" {
+		return longdatetime{}, nil
+	}
+
+	seconds, err := strconv.ParseUint(epoch, 10, 64)
+	if err != nil {
+		return longdatetime{}, fmt.Errorf("invalid SOURCE_DATE_EPOCH %q: %s", epoch, err)
+	}
+
+	return longdatetime{SecondsSince1904: seconds + secondsFrom1904To1970}, nil
 }
 
 func checkSum(buffer []byte) uint32 {
@@ -146,3 +330,117 @@ func checkSum(buffer []byte) uint32 {
 	return total
 
 }
+
+// recomputedMetrics returns copies of the 'maxp' and 'hhea' tables with the
+// number of glyphs, the number of horizontal metrics and the extents of the
+// glyphs recomputed from the 'glyf' and 'hmtx' tables, as these values are
+// easily left out of date when glyphs are changed. The 'glyf' and 'hmtx'
+// tables in replaced, if any, are used instead of those in the font. Only
+// tables that may have changed, because they are in replaced or have been
+// parsed, are recomputed from, so writing an unchanged font parses nothing.
+func (font *Font) recomputedMetrics(replaced map[Tag]Table) (map[Tag]Table, error) {
+	tables := map[Tag]Table{}
+
+	glyf, _ := replaced[TagGlyf].(*TableGlyf)
+	if glyf == nil {
+		glyf, _ = font.parsedTable(TagGlyf).(*TableGlyf)
+	}
+	hmtx, _ := replaced[TagHmtx].(*TableHmtx)
+	if hmtx == nil {
+		hmtx, _ = font.parsedTable(TagHmtx).(*TableHmtx)
+	}
+	if glyf == nil && hmtx == nil {
+		return tables, nil
+	}
+
+	// The side bearings depend on both tables, so the other one is parsed
+	// too, without adding it to the font.
+	var err error
+	if glyf == nil && font.HasTable(TagGlyf) {
+		s, _ := font.section(TagGlyf)
+		s.mu.Lock()
+		glyf, err = font.parseGlyf(s)
+		s.mu.Unlock()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if hmtx == nil && font.HasTable(TagHmtx) && font.HasTable(TagHhea) {
+		s, _ := font.section(TagHmtx)
+		s.mu.Lock()
+		hmtx, err = font.parseHmtx(s)
+		s.mu.Unlock()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if glyf != nil && font.HasTable(TagMaxp) {
+		original, err := font.MaxpTable()
+		if err != nil {
+			return nil, err
+		}
+		maxp := *original
+		maxp.NumGlyphs = uint16(glyf.NumGlyphs())
+		tables[TagMaxp] = &maxp
+	}
+
+	if hmtx == nil || !font.HasTable(TagHhea) {
+		return tables, nil
+	}
+	original, err := font.HheaTable()
+	if err != nil {
+		return nil, err
+	}
+	hhea := *original
+	tables[TagHhea] = &hhea
+
+	hhea.NumOfLongHorMetrics = int16(hmtx.NumberOfHMetrics())
+	hhea.AdvanceWidthMax = 0
+	for _, m := range hmtx.Metrics {
+		if m.AdvanceWidth > hhea.AdvanceWidthMax {
+			hhea.AdvanceWidthMax = m.AdvanceWidth
+		}
+	}
+	if glyf == nil {
+		return tables, nil
+	}
+
+	// The side bearings and extent only include glyphs with contours.
+	found := false
+	for i, data := range glyf.Glyphs {
+		if len(data) < 10 || i >= len(hmtx.Metrics) {
+			continue
+		}
+		m := hmtx.Metrics[i]
+		width := int(int16(binary.BigEndian.Uint16(data[6:]))) - int(int16(binary.BigEndian.Uint16(data[2:])))
+		lsb := int(m.LeftSideBearing)
+		rsb := int(m.AdvanceWidth) - lsb - width
+		extent := lsb + width
+		if !found || lsb < int(hhea.MinLeftSideBearing) {
+			hhea.MinLeftSideBearing = clampInt16(lsb)
+		}
+		if !found || rsb < int(hhea.MinRightSideBearing) {
+			hhea.MinRightSideBearing = clampInt16(rsb)
+		}
+		if !found || extent > int(hhea.XMaxExtent) {
+			hhea.XMaxExtent = clampInt16(extent)
+		}
+		found = true
+	}
+	if !found {
+		hhea.MinLeftSideBearing, hhea.MinRightSideBearing, hhea.XMaxExtent = 0, 0, 0
+	}
+	return tables, nil
+}
+
+// clampInt16 returns v, limited to the range of an int16.
+func clampInt16(v int) int16 {
+	if v < math.MinInt16 {
+		return math.MinInt16
+	}
+	if v > math.MaxInt16 {
+		return math.MaxInt16
+	}
+	return int16(v)
+}
@@ -0,0 +1,325 @@
+package sfnt
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteOTFReproducible(t *testing.T) {
+	defer os.Setenv("SOURCE_DATE_EPOCH", os.Getenv("SOURCE_DATE_EPOCH"))
+
+	write := func(updated uint64) []byte {
+		file, err := os.Open(filepath.Join("testdata", "Roboto-BoldItalic.ttf"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer file.Close()
+
+		font, err := Parse(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		head, err := font.HeadTable()
+		if err != nil {
+			t.Fatal(err)
+		}
+		head.Updated.SecondsSince1904 = updated
+
+		var buf bytes.Buffer
+		if _, err := font.WriteOTFWithOptions(&buf, WriteOptions{Reproducible: true}); err != nil {
+			t.Fatalf("WriteOTFWithOptions() err = %q, want nil", err)
+		}
+		if head.Updated.SecondsSince1904 != updated {
+			t.Errorf("head.Updated = %d after writing, want %d", head.Updated.SecondsSince1904, updated)
+		}
+		return buf.Bytes()
+	}
+
+	os.Setenv("SOURCE_DATE_EPOCH", "")
+	if !bytes.Equal(write(1), write(2)) {
+		t.Errorf("WriteOTFWithOptions() output differs for fonts with different modified dates")
+	}
+
+	os.Setenv("SOURCE_DATE_EPOCH", "1600000000")
+	font, err := StrictParse(bytes.NewReader(write(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	head, err := font.HeadTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := uint64(1600000000 + secondsFrom1904To1970); head.Updated.SecondsSince1904 != want {
+		t.Errorf("head.Updated = %d, want %d", head.Updated.SecondsSince1904, want)
+	}
+}
+
+func TestWriteOTFTableOrder(t *testing.T) {
+	for _, filename := range []string{"Roboto-BoldItalic.ttf", "Raleway-v4020-Regular.otf"} {
+		file, err := os.Open(filepath.Join("testdata", filename))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer file.Close()
+
+		font, err := Parse(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var buf bytes.Buffer
+		if _, err := font.WriteOTF(&buf); err != nil {
+			t.Fatalf("WriteOTF(%q) err = %q, want nil", filename, err)
+		}
+
+		if sum := checkSum(buf.Bytes()); sum != 0xB1B0AFBA {
+			t.Errorf("checkSum(WriteOTF(%q)) = %x, want b1b0afba", filename, sum)
+		}
+
+		written, err := Parse(bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		records := written.Directory()
+		for i, record := range records {
+			entry := buf.Bytes()[otfHeaderLength+directoryEntryLength*i:]
+			if tag := NewTag(entry); tag != record.Tag {
+				t.Errorf("%q: directory[%d] = %q, want %q", filename, i, tag, record.Tag)
+			}
+			if record.Tag == TagHead && record.Offset != uint32(otfHeaderLength+directoryEntryLength*len(records)) {
+				t.Errorf("%q: head stored at %d, want first", filename, record.Offset)
+			}
+			if record.Offset%4 != 0 {
+				t.Errorf("%q: %q stored at %d, want 4-byte aligned", filename, record.Tag, record.Offset)
+			}
+		}
+	}
+}
+
+func TestWriteOTFRecomputesMetrics(t *testing.T) {
+	b := NewBuilder(1000)
+	b.AddGlyph("A", 600, [][]GlyphPoint{square(50, 0, 500)})
+	b.AddGlyph("space", 250, nil)
+	font, err := b.Font()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Widen glyph 1 so that it overhangs both sides of its advance, and make
+	// the stored values stale.
+	glyph := &Glyph{Contours: [][]GlyphPoint{square(-100, 0, 800)}}
+	glyph.UpdateBounds()
+	glyf, err := font.GlyfTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := glyf.SetGlyph(1, glyph); err != nil {
+		t.Fatal(err)
+	}
+	hmtx, err := font.HmtxTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	hmtx.Metrics[1] = HMetric{AdvanceWidth: 650, LeftSideBearing: -100}
+	hhea, err := font.HheaTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale := *hhea
+	maxp, err := font.MaxpTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	maxp.NumGlyphs = 1
+
+	var buf bytes.Buffer
+	if _, err := font.WriteOTF(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if *hhea != stale || maxp.NumGlyphs != 1 {
+		t.Errorf("WriteOTF() modified the hhea or maxp table")
+	}
+
+	written, err := StrictParse(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if maxp, err := written.MaxpTable(); err != nil || maxp.NumGlyphs != 3 {
+		t.Errorf("maxp.NumGlyphs = %d, %v; want 3", maxp.NumGlyphs, err)
+	}
+	hhea, err = written.HheaTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := [4]int{int(hhea.AdvanceWidthMax), int(hhea.MinLeftSideBearing), int(hhea.MinRightSideBearing), int(hhea.XMaxExtent)}
+	if want := [4]int{650, -100, -50, 700}; got != want {
+		t.Errorf("advanceWidthMax, minLeftSideBearing, minRightSideBearing, xMaxExtent = %v, want %v", got, want)
+	}
+}
+
+func TestWriteOTFUnchangedGlyphs(t *testing.T) {
+	font := parseTestFont(t, "Roboto-BoldItalic.ttf")
+	if _, err := font.WriteOTF(ioutil.Discard); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := font.parsedTable(TagGlyf).(*TableGlyf); ok {
+		t.Errorf("WriteOTF() parsed the unchanged 'glyf' table")
+	}
+	if _, ok := font.parsedTable(TagLoca).(*TableLoca); ok {
+		t.Errorf("WriteOTF() replaced the 'loca' table")
+	}
+	if _, ok := font.parsedTable(TagHmtx).(*TableHmtx); ok {
+		t.Errorf("WriteOTF() parsed the unchanged 'hmtx' table")
+	}
+
+	// Once the metrics have changed, the glyphs are parsed to recompute the
+	// side bearings, and errors are returned.
+	if _, err := font.HmtxTable(); err != nil {
+		t.Fatal(err)
+	}
+	font.SetTable(TagLoca, []byte{})
+	if _, err := font.WriteOTF(ioutil.Discard); err == nil {
+		t.Errorf("WriteOTF() with an invalid 'loca' table err = nil, want an error")
+	}
+}
+
+func TestWriteOTFLocaFormat(t *testing.T) {
+	b := NewBuilder(1000)
+	b.AddGlyph("A", 600, [][]GlyphPoint{square(50, 0, 500)})
+	b.AddGlyph("B", 600, [][]GlyphPoint{square(50, 0, 500)})
+	font, err := b.Font()
+	if err != nil {
+		t.Fatal(err)
+	}
+	glyf, err := font.GlyfTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Odd lengths are padded to two bytes, so these fill a short 'loca' table
+	// exactly, and one more byte needs a long table.
+	notdef := len(glyf.Glyphs[0]) + len(glyf.Glyphs[0])%2
+	glyf.Glyphs[1] = make([]byte, 0x10001)
+	for _, test := range []struct {
+		extra  int
+		format int16
+	}{{0, 0}, {1, 1}} {
+		glyf.Glyphs[2] = make([]byte, maxShortLocaOffset-notdef-0x10002+test.extra)
+		var buf bytes.Buffer
+		if _, err := font.WriteOTF(&buf); err != nil {
+			t.Fatal(err)
+		}
+		written, err := StrictParse(bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		head, err := written.HeadTable()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if head.IndexToLocFormat != test.format {
+			t.Errorf("IndexToLocFormat = %d with %d more bytes, want %d", head.IndexToLocFormat, test.extra, test.format)
+		}
+		writtenGlyf, err := written.GlyfTable()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := len(writtenGlyf.Glyphs[2]), len(glyf.Glyphs[2]); got < want || got > want+3 {
+			t.Errorf("len(glyph 2) = %d with %d more bytes, want %d", got, test.extra, want)
+		}
+	}
+}
+
+func TestWriteOTFRoundToGrid(t *testing.T) {
+	b := NewBuilder(1000)
+	a := b.AddGlyph("A", 604, [][]GlyphPoint{square(53, -4, 497)})
+	b.AddComposite("Aacute", 605, []GlyphComponent{{GlyphID: a, Arg1: 15, Arg2: 25, Transform: [4]float64{1, 0, 0, 1}}})
+	pair := b.AddComposite("A_A", 1100, []GlyphComponent{
+		{GlyphID: a, Arg1: 15, Arg2: 25, Transform: [4]float64{1, 0, 0, 1}},
+		{GlyphID: a, Transform: [4]float64{1, 0, 0, 1}},
+	})
+	font, err := b.Font()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The second A is moved so that its first point is on the third point of the
+	// first A, so its position depends on the rounded outline of A.
+	if glyf, err := font.GlyfTable(); err != nil {
+		t.Fatal(err)
+	} else if glyph, err := glyf.Glyph(pair); err != nil {
+		t.Fatal(err)
+	} else {
+		c := &glyph.Components[1]
+		c.Flags &^= glyfArgsAreXY
+		c.Arg1, c.Arg2 = 2, 0
+		if err := glyf.SetGlyph(pair, glyph); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if _, err := font.WriteOTFWithOptions(&buf, WriteOptions{RoundToGrid: 10}); err != nil {
+		t.Fatal(err)
+	}
+	if glyf, err := font.GlyfTable(); err != nil {
+		t.Fatal(err)
+	} else if glyph, err := glyf.Glyph(1); err != nil || glyph.XMin != 53 {
+		t.Errorf("WriteOTFWithOptions() modified the glyphs")
+	}
+
+	written, err := StrictParse(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	glyf, err := written.GlyfTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	hmtx, err := written.HmtxTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	head, err := written.HeadTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		gid     uint16
+		bounds  [4]int16
+		advance uint16
+		lsb     int16
+	}{
+		{1, [4]int16{50, 0, 550, 490}, 600, 50},
+		{2, [4]int16{70, 30, 570, 520}, 610, 70},
+		{3, [4]int16{70, 30, 1070, 1010}, 1100, 70},
+	}
+	for _, tt := range tests {
+		glyph, err := glyf.Glyph(tt.gid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := [4]int16{glyph.XMin, glyph.YMin, glyph.XMax, glyph.YMax}; got != tt.bounds {
+			t.Errorf("glyph %d: bounds = %v, want %v", tt.gid, got, tt.bounds)
+		}
+		if m := hmtx.Metrics[tt.gid]; m.AdvanceWidth != tt.advance || m.LeftSideBearing != tt.lsb {
+			t.Errorf("glyph %d: metrics = %v, want {%d %d}", tt.gid, m, tt.advance, tt.lsb)
+		}
+	}
+	if got := [4]int16{head.XMin, head.YMin, head.XMax, head.YMax}; got != [4]int16{0, 0, 1070, 1010} {
+		t.Errorf("head bounds = %v, want [0 0 1070 1010]", got)
+	}
+}
+
+func TestRoundCoordinate(t *testing.T) {
+	for v, want := range map[float64]float64{0.5: 1, -0.5: 0, -1.5: -1, 2.49: 2, -2.51: -3} {
+		if got := roundCoordinate(v); got != want {
+			t.Errorf("roundCoordinate(%v) = %v, want %v", v, got, want)
+		}
+	}
+}
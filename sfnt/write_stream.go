@@ -0,0 +1,97 @@
+package sfnt
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// headCheckSumAdjustmentOffset is the offset of checkSumAdjustment in 'head' (and 'bhed').
+const headCheckSumAdjustmentOffset = 8
+
+// WriteOTFStream serializes a Font into OpenType format, like
+// WriteOTFWithOptions, but writes each table as soon as it is serialized,
+// instead of building the whole file in memory first. This is intended for
+// very large fonts, such as CJK fonts with tens of megabytes of outlines.
+// The tables themselves are still parsed, and kept by the font, as they are
+// written.
+//
+// As the table directory and the checksum in 'head' depend on every table,
+// they are written as placeholders first and filled in by seeking back once
+// the tables have been written, so w must be seekable (such as an *os.File).
+// The font is written from the current position of w, and w is left at the
+// end of the font.
+func (font *Font) WriteOTFStream(w io.WriteSeeker, options WriteOptions) (n int, err error) {
+	start, err := w.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return n, err
+	}
+
+	tags := font.Tags()
+	ow, err := font.newOTFWriter(options)
+	if err != nil {
+		return n, err
+	}
+
+	header := newOTFHeader(font.scalerType, uint16(len(tags)))
+	if err := binary.Write(w, binary.BigEndian, header); err != nil {
+		return n, err
+	}
+	n += otfHeaderLength
+	m, err := w.Write(make([]byte, directoryEntryLength*len(tags)))
+	n += m
+	if err != nil {
+		return n, err
+	}
+
+	entries := make(map[Tag]directoryEntry, len(tags))
+	for _, tag := range font.storageOrder() {
+		fragment, err := ow.tableBytes(tag)
+		if err != nil {
+			return n, err
+		}
+		entries[tag] = directoryEntry{
+			Tag:      tag,
+			CheckSum: checkSum(fragment),
+			Offset:   uint32(n),
+			Length:   uint32(len(fragment)),
+		}
+
+		m, err := w.Write(fragment)
+		n += m
+		if err != nil {
+			return n, err
+		}
+		if len(fragment)%4 != 0 {
+			m, err := w.Write(make([]byte, 4-len(fragment)%4))
+			n += m
+			if err != nil {
+				return n, err
+			}
+		}
+	}
+
+	if _, err := w.Seek(start+otfHeaderLength, io.SeekStart); err != nil {
+		return n, err
+	}
+	checksum := header.checkSum()
+	for _, tag := range tags {
+		entry := entries[tag]
+		checksum += entry.CheckSum + entry.checkSum()
+		if err := binary.Write(w, binary.BigEndian, entry); err != nil {
+			return n, err
+		}
+	}
+
+	adjustment := make([]byte, 4)
+	ow.head.SetExpectedChecksum(checksum)
+	binary.BigEndian.PutUint32(adjustment, ow.head.CheckSumAdjustment)
+	if _, err := w.Seek(start+int64(entries[ow.headTag].Offset)+headCheckSumAdjustmentOffset, io.SeekStart); err != nil {
+		return n, err
+	}
+	if _, err := w.Write(adjustment); err != nil {
+		return n, err
+	}
+
+	_, err = w.Seek(start+int64(n), io.SeekStart)
+	return n, err
+}
@@ -0,0 +1,50 @@
+package sfnt
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteOTFStream(t *testing.T) {
+	font := parseTestFont(t, "Roboto-BoldItalic.ttf")
+
+	var buf bytes.Buffer
+	want, err := font.WriteOTFWithOptions(&buf, WriteOptions{Reproducible: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := os.Create(filepath.Join(t.TempDir(), "font.ttf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	// The font need not start at the beginning of the file.
+	if _, err := file.Write([]byte("font")); err != nil {
+		t.Fatal(err)
+	}
+	n, err := font.WriteOTFStream(file, WriteOptions{Reproducible: true})
+	if err != nil {
+		t.Fatalf("WriteOTFStream() err = %q, want nil", err)
+	}
+	if n != want {
+		t.Errorf("WriteOTFStream() = %d, want %d", n, want)
+	}
+	if _, err := file.Write([]byte("end")); err != nil {
+		t.Fatal(err)
+	}
+
+	written, err := ioutil.ReadFile(file.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(written[4:len(written)-3], buf.Bytes()) {
+		t.Errorf("WriteOTFStream() output differs from WriteOTFWithOptions()")
+	}
+	if _, err := StrictParse(bytes.NewReader(written[4 : len(written)-3])); err != nil {
+		t.Errorf("StrictParse() err = %q, want nil", err)
+	}
+}
@@ -0,0 +1,100 @@
+package sfnt
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"io"
+)
+
+// woffHeaderLength and woffEntryLength are the sizes of the WOFF header and
+// of each entry in its table directory.
+const (
+	woffHeaderLength = 44
+	woffEntryLength  = 20
+)
+
+// WriteWOFF serializes a Font into WOFF 1.0 format, suitable for writing to a
+// file such as *.woff. Each table is compressed with zlib, unless that would
+// make it larger. The tables are the same as those written by WriteOTF, so
+// decompressing the file gives the same bytes.
+func (font *Font) WriteWOFF(w io.Writer) (n int, err error) {
+	return font.WriteWOFFWithOptions(w, WriteOptions{})
+}
+
+// WriteWOFFWithOptions serializes a Font into WOFF 1.0 format, like WriteWOFF,
+// with the tables written by WriteOTFWithOptions.
+func (font *Font) WriteWOFFWithOptions(w io.Writer, options WriteOptions) (n int, err error) {
+	var otf bytes.Buffer
+	if _, err := font.WriteOTFWithOptions(&otf, options); err != nil {
+		return n, err
+	}
+	sfnt := otf.Bytes()
+
+	numTables := int(binary.BigEndian.Uint16(sfnt[4:]))
+	header := woffHeader{
+		Signature:     SignatureWOFF,
+		Flavor:        font.scalerType,
+		NumTables:     uint16(numTables),
+		TotalSfntSize: uint32(len(sfnt)),
+		Version:       fixed{Major: 1},
+	}
+	entries := make([]woffEntry, numTables)
+	tables := make([][]byte, numTables)
+
+	offset := woffHeaderLength + woffEntryLength*numTables
+	for i := range entries {
+		directory := sfnt[otfHeaderLength+directoryEntryLength*i:]
+		tag := NewTag(directory)
+		start, length := binary.BigEndian.Uint32(directory[8:]), binary.BigEndian.Uint32(directory[12:])
+		table := sfnt[start : start+length]
+		if tag == TagHead || tag == TagBhed {
+			header.Version = fixed{Major: int16(binary.BigEndian.Uint16(table[4:])), Minor: binary.BigEndian.Uint16(table[6:])}
+		}
+
+		var compressed bytes.Buffer
+		zw, _ := zlib.NewWriterLevel(&compressed, zlib.BestCompression)
+		zw.Write(table)
+		if err := zw.Close(); err != nil {
+			return n, err
+		}
+		if compressed.Len() < len(table) {
+			table = compressed.Bytes()
+		}
+
+		entries[i] = woffEntry{
+			Tag:          tag,
+			Offset:       uint32(offset),
+			CompLength:   uint32(len(table)),
+			OrigLength:   length,
+			OrigChecksum: binary.BigEndian.Uint32(directory[4:]),
+		}
+		tables[i] = table
+		offset += (len(table) + 3) &^ 3
+	}
+	header.Length = uint32(offset)
+
+	if err := binary.Write(w, binary.BigEndian, header); err != nil {
+		return n, err
+	}
+	n += woffHeaderLength
+	for _, entry := range entries {
+		if err := binary.Write(w, binary.BigEndian, entry); err != nil {
+			return n, err
+		}
+		n += woffEntryLength
+	}
+	for _, table := range tables {
+		m, err := w.Write(table)
+		n += m
+		if err != nil {
+			return n, err
+		}
+		m, err = w.Write(make([]byte, (4-len(table)%4)%4))
+		n += m
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
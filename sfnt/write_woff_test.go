@@ -0,0 +1,44 @@
+package sfnt
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteWOFF(t *testing.T) {
+	for _, filename := range []string{"Roboto-BoldItalic.ttf", "Raleway-v4020-Regular.otf"} {
+		font := parseTestFont(t, filename)
+
+		var otf, woff bytes.Buffer
+		if _, err := font.WriteOTF(&otf); err != nil {
+			t.Fatal(err)
+		}
+		n, err := font.WriteWOFF(&woff)
+		if err != nil {
+			t.Fatalf("WriteWOFF(%q) err = %v", filename, err)
+		}
+		if n != woff.Len() || n%4 != 0 || n >= otf.Len() {
+			t.Errorf("WriteWOFF(%q) wrote %d bytes (%d in buffer), want fewer than %d and a multiple of 4", filename, n, woff.Len(), otf.Len())
+		}
+		if format, _ := Sniff(bytes.NewReader(woff.Bytes())); format != FormatWOFF {
+			t.Errorf("Sniff(WriteWOFF(%q)) = %s, want WOFF", filename, format)
+		}
+
+		written, err := StrictParse(bytes.NewReader(woff.Bytes()))
+		if err != nil {
+			t.Fatalf("StrictParse(WriteWOFF(%q)) err = %v", filename, err)
+		}
+		if written.Type() != font.Type() {
+			t.Errorf("%q: Type() = %q, want %q", filename, written.Type(), font.Type())
+		}
+
+		// Decompressing the tables gives the same file as WriteOTF.
+		var roundTrip bytes.Buffer
+		if _, err := written.WriteOTF(&roundTrip); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(roundTrip.Bytes(), otf.Bytes()) {
+			t.Errorf("%q: WriteOTF() after WriteWOFF() differs from WriteOTF()", filename)
+		}
+	}
+}